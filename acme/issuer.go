@@ -0,0 +1,213 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"sort"
+
+	cryptoacme "golang.org/x/crypto/acme"
+)
+
+const LetsEncryptDirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+
+type Client interface {
+	Register(ctx context.Context, account *cryptoacme.Account, prompt func(tosURL string) bool) (*cryptoacme.Account, error)
+	AuthorizeOrder(ctx context.Context, ids []cryptoacme.AuthzID, opts ...cryptoacme.OrderOption) (*cryptoacme.Order, error)
+	GetAuthorization(ctx context.Context, url string) (*cryptoacme.Authorization, error)
+	DNS01ChallengeRecord(token string) (string, error)
+	Accept(ctx context.Context, challenge *cryptoacme.Challenge) (*cryptoacme.Challenge, error)
+	WaitAuthorization(ctx context.Context, url string) (*cryptoacme.Authorization, error)
+	WaitOrder(ctx context.Context, url string) (*cryptoacme.Order, error)
+	CreateOrderCert(ctx context.Context, url string, csr []byte, bundle bool) ([][]byte, string, error)
+}
+
+func NewClient(key crypto.Signer, directoryURL string) Client {
+	return &cryptoacme.Client{
+		Key:          key,
+		DirectoryURL: directoryURL,
+	}
+}
+
+type newClient func(key crypto.Signer, directoryURL string) Client
+
+type keyGenerator func(random io.Reader, bits int) (*rsa.PrivateKey, error)
+
+type logger interface {
+	Step(string, ...interface{})
+	Warn(string, ...interface{})
+}
+
+type DNSProvider interface {
+	SetTXTRecord(zone, fqdn string, values []string) error
+	DeleteTXTRecord(zone, fqdn string, values []string) error
+}
+
+type Certificate struct {
+	Certificate []byte
+	PrivateKey  []byte
+	Chain       []byte
+}
+
+type Issuer struct {
+	newClient   newClient
+	generateKey keyGenerator
+	logger      logger
+}
+
+func NewIssuer(newClient newClient, generateKey keyGenerator, logger logger) Issuer {
+	return Issuer{
+		newClient:   newClient,
+		generateKey: generateKey,
+		logger:      logger,
+	}
+}
+
+// Issue obtains a certificate for the domains from the ACME directory,
+// answering DNS-01 challenges with TXT records in the given zone.
+func (i Issuer) Issue(ctx context.Context, accountKey crypto.Signer, directoryURL, email string,
+	dns DNSProvider, zone string, domains []string) (Certificate, error) {
+
+	client := i.newClient(accountKey, directoryURL)
+
+	account := &cryptoacme.Account{}
+	if email != "" {
+		account.Contact = []string{"mailto:" + email}
+	}
+
+	_, err := client.Register(ctx, account, cryptoacme.AcceptTOS)
+	if err != nil && err != cryptoacme.ErrAccountAlreadyExists {
+		return Certificate{}, fmt.Errorf("failed to register acme account: %s", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, cryptoacme.DomainIDs(domains...))
+	if err != nil {
+		return Certificate{}, fmt.Errorf("failed to create acme order: %s", err)
+	}
+
+	var (
+		authorizations []*cryptoacme.Authorization
+		challenges     []*cryptoacme.Challenge
+	)
+	records := map[string][]string{}
+	for _, url := range order.AuthzURLs {
+		authorization, err := client.GetAuthorization(ctx, url)
+		if err != nil {
+			return Certificate{}, err
+		}
+
+		if authorization.Status == cryptoacme.StatusValid {
+			continue
+		}
+
+		challenge := dns01Challenge(authorization)
+		if challenge == nil {
+			return Certificate{}, fmt.Errorf("the acme directory did not offer a dns-01 challenge for %s", authorization.Identifier.Value)
+		}
+
+		value, err := client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return Certificate{}, err
+		}
+
+		fqdn := fmt.Sprintf("_acme-challenge.%s", authorization.Identifier.Value)
+		records[fqdn] = append(records[fqdn], value)
+		authorizations = append(authorizations, authorization)
+		challenges = append(challenges, challenge)
+	}
+
+	fqdns := sortedKeys(records)
+	defer func() {
+		for _, fqdn := range fqdns {
+			if err := dns.DeleteTXTRecord(zone, fqdn, records[fqdn]); err != nil {
+				i.logger.Warn("failed to delete the acme challenge record %s: %s", fqdn, err)
+			}
+		}
+	}()
+
+	for _, fqdn := range fqdns {
+		i.logger.Step("creating acme challenge record %s", fqdn)
+		if err := dns.SetTXTRecord(zone, fqdn, records[fqdn]); err != nil {
+			return Certificate{}, err
+		}
+	}
+
+	for index, challenge := range challenges {
+		if _, err := client.Accept(ctx, challenge); err != nil {
+			return Certificate{}, err
+		}
+
+		if _, err := client.WaitAuthorization(ctx, authorizations[index].URI); err != nil {
+			return Certificate{}, fmt.Errorf("acme validation of %s failed: %s", authorizations[index].Identifier.Value, err)
+		}
+	}
+
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	privateKey, err := i.generateKey(rand.Reader, 2048)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, privateKey)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	i.logger.Step("requesting certificate for %s", domains[0])
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	if len(der) == 0 {
+		return Certificate{}, fmt.Errorf("the acme directory returned no certificate for %s", domains[0])
+	}
+
+	var chain bytes.Buffer
+	for _, cert := range der[1:] {
+		pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	}
+
+	return Certificate{
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der[0]}),
+		PrivateKey: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}),
+		Chain: chain.Bytes(),
+	}, nil
+}
+
+func dns01Challenge(authorization *cryptoacme.Authorization) *cryptoacme.Challenge {
+	for _, challenge := range authorization.Challenges {
+		if challenge.Type == "dns-01" {
+			return challenge
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(records map[string][]string) []string {
+	var keys []string
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,186 @@
+package acme_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+
+	"github.com/cloudfoundry/bosh-bootloader/acme"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cryptoacme "golang.org/x/crypto/acme"
+)
+
+var _ = Describe("Issuer", func() {
+	var (
+		issuer        acme.Issuer
+		client        *fakes.ACMEClient
+		newClientCall *fakes.NewACMEClientCall
+		dns           *fakes.TXTRecordManager
+		logger        *fakes.Logger
+		accountKey    *ecdsa.PrivateKey
+		domains       []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		accountKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		client = &fakes.ACMEClient{}
+		client.AuthorizeOrderCall.Returns.Order = &cryptoacme.Order{
+			URI:       "https://acme.example.com/order/1",
+			AuthzURLs: []string{"https://acme.example.com/authz/wildcard", "https://acme.example.com/authz/apex"},
+		}
+		client.GetAuthorizationCall.Stub = func(url string) (*cryptoacme.Authorization, error) {
+			return &cryptoacme.Authorization{
+				URI:        url,
+				Status:     cryptoacme.StatusPending,
+				Identifier: cryptoacme.AuthzID{Type: "dns", Value: "example.com"},
+				Challenges: []*cryptoacme.Challenge{
+					{Type: "http-01", Token: "http-token"},
+					{Type: "dns-01", Token: url + "-token"},
+				},
+			}, nil
+		}
+		client.WaitOrderCall.Returns.Order = &cryptoacme.Order{
+			FinalizeURL: "https://acme.example.com/order/1/finalize",
+		}
+		client.CreateOrderCertCall.Returns.DER = [][]byte{[]byte("leaf"), []byte("intermediate")}
+
+		newClientCall = &fakes.NewACMEClientCall{}
+		dns = &fakes.TXTRecordManager{}
+		logger = &fakes.Logger{}
+		domains = []string{"*.example.com", "example.com"}
+
+		issuer = acme.NewIssuer(fakes.NewACMEClientFunc(client, newClientCall), rsa.GenerateKey, logger)
+	})
+
+	It("answers the dns-01 challenges and returns the certificate", func() {
+		certificate, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory",
+			"ops@example.com", dns, "some-zone", domains)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newClientCall.Receives.Key).To(Equal(accountKey))
+		Expect(newClientCall.Receives.DirectoryURL).To(Equal("https://acme.example.com/directory"))
+
+		Expect(client.RegisterCall.Receives.Account.Contact).To(Equal([]string{"mailto:ops@example.com"}))
+		Expect(client.AuthorizeOrderCall.Receives.IDs).To(Equal(cryptoacme.DomainIDs("*.example.com", "example.com")))
+
+		By("putting both challenge values in one record, since the wildcard and the apex share a name", func() {
+			Expect(dns.SetTXTRecordCall.Receives).To(Equal([]fakes.TXTRecordCallReceives{{
+				Zone: "some-zone",
+				FQDN: "_acme-challenge.example.com",
+				Values: []string{
+					"record-for-https://acme.example.com/authz/wildcard-token",
+					"record-for-https://acme.example.com/authz/apex-token",
+				},
+			}}))
+		})
+
+		Expect(client.AcceptCall.CallCount).To(Equal(2))
+		Expect(client.WaitAuthorizationCall.Receives.URLs).To(Equal([]string{
+			"https://acme.example.com/authz/wildcard",
+			"https://acme.example.com/authz/apex",
+		}))
+		Expect(client.WaitOrderCall.Receives.URL).To(Equal("https://acme.example.com/order/1"))
+
+		Expect(dns.DeleteTXTRecordCall.Receives).To(Equal(dns.SetTXTRecordCall.Receives))
+
+		Expect(client.CreateOrderCertCall.Receives.URL).To(Equal("https://acme.example.com/order/1/finalize"))
+		Expect(client.CreateOrderCertCall.Receives.Bundle).To(BeTrue())
+
+		csr, err := x509.ParseCertificateRequest(client.CreateOrderCertCall.Receives.CSR)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(csr.Subject.CommonName).To(Equal("*.example.com"))
+		Expect(csr.DNSNames).To(Equal(domains))
+
+		Expect(certificate.Certificate).To(Equal(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("leaf")})))
+		Expect(certificate.Chain).To(Equal(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("intermediate")})))
+
+		block, _ := pem.Decode(certificate.PrivateKey)
+		Expect(block.Type).To(Equal("RSA PRIVATE KEY"))
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(privateKey.PublicKey).To(Equal(*csr.PublicKey.(*rsa.PublicKey)))
+	})
+
+	It("reuses an existing account", func() {
+		client.RegisterCall.Returns.Error = cryptoacme.ErrAccountAlreadyExists
+
+		_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.RegisterCall.Receives.Account.Contact).To(BeEmpty())
+	})
+
+	It("skips authorizations that are already valid", func() {
+		client.GetAuthorizationCall.Stub = func(url string) (*cryptoacme.Authorization, error) {
+			return &cryptoacme.Authorization{URI: url, Status: cryptoacme.StatusValid}, nil
+		}
+
+		_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dns.SetTXTRecordCall.CallCount).To(Equal(0))
+		Expect(client.AcceptCall.CallCount).To(Equal(0))
+		Expect(client.CreateOrderCertCall.CallCount).To(Equal(1))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the account cannot be registered", func() {
+			client.RegisterCall.Returns.Error = errors.New("bad nonce")
+
+			_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+			Expect(err).To(MatchError("failed to register acme account: bad nonce"))
+		})
+
+		It("returns an error when the directory does not offer a dns-01 challenge", func() {
+			client.GetAuthorizationCall.Stub = func(url string) (*cryptoacme.Authorization, error) {
+				return &cryptoacme.Authorization{
+					URI:        url,
+					Identifier: cryptoacme.AuthzID{Type: "dns", Value: "example.com"},
+					Challenges: []*cryptoacme.Challenge{{Type: "http-01"}},
+				}, nil
+			}
+
+			_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+			Expect(err).To(MatchError("the acme directory did not offer a dns-01 challenge for example.com"))
+		})
+
+		It("removes the challenge records when validation fails", func() {
+			client.WaitAuthorizationCall.Returns.Error = errors.New("NXDOMAIN")
+
+			_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+			Expect(err).To(MatchError("acme validation of example.com failed: NXDOMAIN"))
+
+			Expect(dns.DeleteTXTRecordCall.CallCount).To(Equal(1))
+		})
+
+		It("warns when a challenge record cannot be removed", func() {
+			dns.DeleteTXTRecordCall.Returns.Error = errors.New("throttled")
+
+			_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.WarnCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when the certificate key cannot be generated", func() {
+			issuer = acme.NewIssuer(fakes.NewACMEClientFunc(client, newClientCall), func(io.Reader, int) (*rsa.PrivateKey, error) {
+				return nil, errors.New("no entropy")
+			}, logger)
+
+			_, err := issuer.Issue(context.Background(), accountKey, "https://acme.example.com/directory", "", dns, "some-zone", domains)
+			Expect(err).To(MatchError("no entropy"))
+		})
+	})
+})
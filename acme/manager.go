@@ -0,0 +1,147 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+var now func() time.Time = time.Now
+
+const renewalPeriod = 30 * 24 * time.Hour
+
+type issuer interface {
+	Issue(ctx context.Context, accountKey crypto.Signer, directoryURL, email string, dns DNSProvider, zone string, domains []string) (Certificate, error)
+}
+
+type terraformManager interface {
+	GetOutputs(storage.State) (map[string]interface{}, error)
+}
+
+type Manager struct {
+	issuer           issuer
+	terraformManager terraformManager
+	awsDNSProvider   DNSProvider
+	gcpDNSProvider   DNSProvider
+}
+
+func NewManager(issuer issuer, terraformManager terraformManager, awsDNSProvider DNSProvider, gcpDNSProvider DNSProvider) Manager {
+	return Manager{
+		issuer:           issuer,
+		terraformManager: terraformManager,
+		awsDNSProvider:   awsDNSProvider,
+		gcpDNSProvider:   gcpDNSProvider,
+	}
+}
+
+// Issue obtains a certificate covering the load balancer domain and every host
+// under it, and stores it in the state along with the ACME account key.
+func (m Manager) Issue(ctx context.Context, state storage.State) (storage.State, error) {
+	if state.LB.ACME == nil {
+		return state, errors.New("the load balancer certificate is not managed by acme")
+	}
+
+	if state.LB.Domain == "" {
+		return state, errors.New("acme certificates require a load balancer domain")
+	}
+
+	account := *state.LB.ACME
+	accountKey, err := loadOrGenerateAccountKey(&account)
+	if err != nil {
+		return state, err
+	}
+
+	dns, zone, err := m.dnsZone(state)
+	if err != nil {
+		return state, err
+	}
+
+	certificate, err := m.issuer.Issue(ctx, accountKey, account.DirectoryURL, account.Email, dns, zone,
+		[]string{fmt.Sprintf("*.%s", state.LB.Domain), state.LB.Domain})
+	if err != nil {
+		return state, err
+	}
+
+	state.LB.Cert = string(certificate.Certificate)
+	state.LB.Key = string(certificate.PrivateKey)
+	state.LB.Chain = string(certificate.Chain)
+	state.LB.CA = ""
+	state.LB.ACME = &account
+
+	return state, nil
+}
+
+// NeedsRenewal reports whether the acme certificate in the state expires
+// within 30 days.
+func (m Manager) NeedsRenewal(state storage.State) (bool, error) {
+	if state.LB.ACME == nil {
+		return false, nil
+	}
+
+	block, _ := pem.Decode([]byte(state.LB.Cert))
+	if block == nil {
+		return true, nil
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the load balancer certificate: %s", err)
+	}
+
+	return certificate.NotAfter.Before(now().Add(renewalPeriod)), nil
+}
+
+func (m Manager) dnsZone(state storage.State) (DNSProvider, string, error) {
+	switch state.IAAS {
+	case "aws":
+		outputs, err := m.terraformManager.GetOutputs(state)
+		if err != nil {
+			return nil, "", err
+		}
+
+		zoneID, _ := outputs["cf_system_domain_dns_zone_id"].(string)
+		if zoneID == "" {
+			return nil, "", fmt.Errorf("could not find the dns zone for %s", state.LB.Domain)
+		}
+
+		return m.awsDNSProvider, zoneID, nil
+	case "gcp":
+		return m.gcpDNSProvider, fmt.Sprintf("%s-zone", state.EnvID), nil
+	default:
+		return nil, "", fmt.Errorf("acme certificates are not supported on %q", state.IAAS)
+	}
+}
+
+func loadOrGenerateAccountKey(account *storage.ACME) (crypto.Signer, error) {
+	if account.AccountKey != "" {
+		block, _ := pem.Decode([]byte(account.AccountKey))
+		if block == nil {
+			return nil, errors.New("failed to parse the acme account key")
+		}
+
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	account.AccountKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	return key, nil
+}
@@ -0,0 +1,221 @@
+package acme_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/acme"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		manager          acme.Manager
+		issuer           *fakes.ACMEIssuer
+		terraformManager *fakes.TerraformManager
+		awsDNSProvider   *fakes.TXTRecordManager
+		gcpDNSProvider   *fakes.TXTRecordManager
+		state            storage.State
+	)
+
+	BeforeEach(func() {
+		issuer = &fakes.ACMEIssuer{}
+		issuer.IssueCall.Returns.Certificate = acme.Certificate{
+			Certificate: []byte("some-cert"),
+			PrivateKey:  []byte("some-key"),
+			Chain:       []byte("some-chain"),
+		}
+		terraformManager = &fakes.TerraformManager{}
+		terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+			"cf_system_domain_dns_zone_id": "some-zone-id",
+		}
+		awsDNSProvider = &fakes.TXTRecordManager{}
+		gcpDNSProvider = &fakes.TXTRecordManager{}
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env",
+			LB: storage.LB{
+				Type:   "cf",
+				Domain: "example.com",
+				CA:     "some-old-ca",
+				ACME: &storage.ACME{
+					DirectoryURL: "https://acme.example.com/directory",
+					Email:        "ops@example.com",
+				},
+			},
+		}
+
+		manager = acme.NewManager(issuer, terraformManager, awsDNSProvider, gcpDNSProvider)
+	})
+
+	Describe("Issue", func() {
+		It("issues a certificate for the domain and its hosts using the route53 zone", func() {
+			newState, err := manager.Issue(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.GetOutputsCall.Receives.BBLState).To(Equal(state))
+			Expect(issuer.IssueCall.Receives.DirectoryURL).To(Equal("https://acme.example.com/directory"))
+			Expect(issuer.IssueCall.Receives.Email).To(Equal("ops@example.com"))
+			Expect(issuer.IssueCall.Receives.DNS).To(Equal(awsDNSProvider))
+			Expect(issuer.IssueCall.Receives.Zone).To(Equal("some-zone-id"))
+			Expect(issuer.IssueCall.Receives.Domains).To(Equal([]string{"*.example.com", "example.com"}))
+
+			Expect(newState.LB.Cert).To(Equal("some-cert"))
+			Expect(newState.LB.Key).To(Equal("some-key"))
+			Expect(newState.LB.Chain).To(Equal("some-chain"))
+			Expect(newState.LB.CA).To(BeEmpty())
+
+			By("saving a new account key", func() {
+				block, _ := pem.Decode([]byte(newState.LB.ACME.AccountKey))
+				Expect(block.Type).To(Equal("EC PRIVATE KEY"))
+
+				accountKey, err := x509.ParseECPrivateKey(block.Bytes)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issuer.IssueCall.Receives.AccountKey).To(Equal(accountKey))
+			})
+
+			Expect(state.LB.ACME.AccountKey).To(BeEmpty())
+		})
+
+		It("reuses the account key from the state", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalECPrivateKey(key)
+			Expect(err).NotTo(HaveOccurred())
+			state.LB.ACME.AccountKey = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+			newState, err := manager.Issue(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(issuer.IssueCall.Receives.AccountKey).To(Equal(key))
+			Expect(newState.LB.ACME.AccountKey).To(Equal(state.LB.ACME.AccountKey))
+		})
+
+		It("uses the cloud dns zone on gcp", func() {
+			state.IAAS = "gcp"
+
+			_, err := manager.Issue(context.Background(), state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(0))
+			Expect(issuer.IssueCall.Receives.DNS).To(Equal(gcpDNSProvider))
+			Expect(issuer.IssueCall.Receives.Zone).To(Equal("some-env-zone"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the certificate is not managed by acme", func() {
+				state.LB.ACME = nil
+
+				_, err := manager.Issue(context.Background(), state)
+				Expect(err).To(MatchError("the load balancer certificate is not managed by acme"))
+			})
+
+			It("returns an error when there is no domain", func() {
+				state.LB.Domain = ""
+
+				_, err := manager.Issue(context.Background(), state)
+				Expect(err).To(MatchError("acme certificates require a load balancer domain"))
+			})
+
+			It("returns an error when the route53 zone cannot be found", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{}
+
+				_, err := manager.Issue(context.Background(), state)
+				Expect(err).To(MatchError("could not find the dns zone for example.com"))
+			})
+
+			It("returns an error when the terraform outputs cannot be read", func() {
+				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+				_, err := manager.Issue(context.Background(), state)
+				Expect(err).To(MatchError("failed to get outputs"))
+			})
+
+			It("returns an error and leaves the state alone when issuing fails", func() {
+				issuer.IssueCall.Returns.Error = errors.New("rate limited")
+
+				newState, err := manager.Issue(context.Background(), state)
+				Expect(err).To(MatchError("rate limited"))
+				Expect(newState).To(Equal(state))
+			})
+		})
+	})
+
+	Describe("NeedsRenewal", func() {
+		BeforeEach(func() {
+			acme.SetNow(func() time.Time {
+				return time.Date(2017, time.June, 1, 0, 0, 0, 0, time.UTC)
+			})
+		})
+
+		AfterEach(func() {
+			acme.ResetNow()
+		})
+
+		It("returns true when the certificate expires within 30 days", func() {
+			state.LB.Cert = certificateExpiringAt(time.Date(2017, time.June, 20, 0, 0, 0, 0, time.UTC))
+
+			needsRenewal, err := manager.NeedsRenewal(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsRenewal).To(BeTrue())
+		})
+
+		It("returns false when the certificate expires later", func() {
+			state.LB.Cert = certificateExpiringAt(time.Date(2017, time.August, 20, 0, 0, 0, 0, time.UTC))
+
+			needsRenewal, err := manager.NeedsRenewal(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsRenewal).To(BeFalse())
+		})
+
+		It("returns true when there is no certificate yet", func() {
+			needsRenewal, err := manager.NeedsRenewal(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsRenewal).To(BeTrue())
+		})
+
+		It("returns false when the certificate is not managed by acme", func() {
+			state.LB.ACME = nil
+
+			needsRenewal, err := manager.NeedsRenewal(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsRenewal).To(BeFalse())
+		})
+
+		It("returns an error when the certificate cannot be parsed", func() {
+			state.LB.Cert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+
+			_, err := manager.NeedsRenewal(state)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse the load balancer certificate")))
+		})
+	})
+})
+
+func certificateExpiringAt(notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"example.com"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
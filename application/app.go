@@ -1,11 +1,12 @@
 package application
 
 import (
-	"errors"
+	"context"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -16,32 +17,76 @@ type usage interface {
 	PrintCommandUsage(command, message string)
 }
 
+type phaseError interface {
+	Phase() string
+	Output() string
+}
+
+type expiryWarner interface {
+	Warn(storage.State)
+}
+
 type App struct {
 	commands      CommandSet
 	configuration Configuration
 	stateStore    stateStore
 	usage         usage
+	expiryWarner  expiryWarner
 }
 
 func New(commands CommandSet, configuration Configuration, stateStore stateStore,
-	usage usage) App {
+	usage usage, expiryWarner expiryWarner) App {
 	return App{
 		commands:      commands,
 		configuration: configuration,
 		stateStore:    stateStore,
 		usage:         usage,
+		expiryWarner:  expiryWarner,
 	}
 }
 
-func (a App) Run() error {
-	err := a.execute()
+func (a App) Run(ctx context.Context) error {
+	err := a.execute(ctx)
 	if err != nil {
+		recordErr := a.recordFailure(err)
+		if recordErr != nil {
+			errorList := helpers.Errors{}
+			errorList.Add(err)
+			errorList.Add(recordErr)
+			return errorList
+		}
 		return err
 	}
 
 	return nil
 }
 
+func (a App) recordFailure(err error) error {
+	phaseError, ok := err.(phaseError)
+	if !ok {
+		return nil
+	}
+
+	state, stateErr := getState(a.configuration.Global.StateDir)
+	if stateErr != nil {
+		return stateErr
+	}
+
+	if state.IAAS == "" {
+		return nil
+	}
+
+	state.AddFailure(storage.Failure{
+		Time:    timeNow().UTC(),
+		Command: a.configuration.Command,
+		Phase:   phaseError.Phase(),
+		Error:   err.Error(),
+		Output:  phaseError.Output(),
+	})
+
+	return a.stateStore.Set(state)
+}
+
 func (a App) getCommand(commandString string) (commands.Command, error) {
 	command, ok := a.commands[commandString]
 	if !ok {
@@ -51,7 +96,7 @@ func (a App) getCommand(commandString string) (commands.Command, error) {
 	return command, nil
 }
 
-func (a App) execute() error {
+func (a App) execute(ctx context.Context) error {
 	command, err := a.getCommand(a.configuration.Command)
 	if err != nil {
 		return err
@@ -78,23 +123,23 @@ func (a App) execute() error {
 			return err
 		}
 
-		return versionCommand.Execute([]string{}, storage.State{})
+		return versionCommand.Execute(ctx, []string{}, storage.State{})
 	}
 
+	a.expiryWarner.Warn(a.configuration.State)
+
 	err = command.CheckFastFails(a.configuration.SubcommandFlags, a.configuration.State)
 	if err != nil {
 		return err
 	}
 
-	err = command.Execute(a.configuration.SubcommandFlags, a.configuration.State)
+	err = command.Execute(ctx, a.configuration.SubcommandFlags, a.configuration.State)
 	if err != nil {
 		switch err.(type) {
 		case awserr.RequestFailure:
 			requestFailure := err.(awserr.RequestFailure)
 			if requestFailure.StatusCode() == 403 {
-				return errors.New(fmt.Sprintf(
-					"The AWS credentials provided have insufficient permissions to perform the operation `bbl %s`.\nPlease refer to the bbl README:\nhttps://github.com/cloudfoundry/bosh-bootloader#configure-aws.\nOriginal error message from AWS:\n\n%s",
-					a.configuration.Command, requestFailure.Message()))
+				return NewInsufficientPermissionsError(a.configuration.Command, requestFailure.Message())
 			}
 			return err
 		default:
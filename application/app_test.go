@@ -1,7 +1,9 @@
 package application_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/cloudfoundry/bosh-bootloader/application"
@@ -13,13 +15,21 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type phaseError struct {
+	message string
+}
+
+func (p phaseError) Error() string  { return p.message }
+func (p phaseError) Phase() string  { return "create-env" }
+func (p phaseError) Output() string { return "some-create-env-output" }
+
 type setNewKeyPairName struct{}
 
 func (snkp setNewKeyPairName) CheckFastFails(subcommandFlags []string, state storage.State) error {
 	return nil
 }
 
-func (snkp setNewKeyPairName) Execute(subcommandFlags []string, state storage.State) error {
+func (snkp setNewKeyPairName) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	state.KeyPair = storage.KeyPair{
 		Name:       "some-new-keypair-name",
 		PublicKey:  state.KeyPair.PublicKey,
@@ -33,13 +43,14 @@ func (snkp setNewKeyPairName) Usage() string { return "" }
 
 var _ = Describe("App", func() {
 	var (
-		app        application.App
-		helpCmd    *fakes.Command
-		versionCmd *fakes.Command
-		someCmd    *fakes.Command
-		errorCmd   *fakes.Command
-		usage      *fakes.Usage
-		stateStore *fakes.StateStore
+		app          application.App
+		helpCmd      *fakes.Command
+		versionCmd   *fakes.Command
+		someCmd      *fakes.Command
+		errorCmd     *fakes.Command
+		usage        *fakes.Usage
+		stateStore   *fakes.StateStore
+		expiryWarner *fakes.ExpiryWarner
 	)
 
 	var NewAppWithConfiguration = func(configuration application.Configuration) application.App {
@@ -54,6 +65,7 @@ var _ = Describe("App", func() {
 			configuration,
 			stateStore,
 			usage,
+			expiryWarner,
 		)
 	}
 
@@ -67,6 +79,7 @@ var _ = Describe("App", func() {
 
 		usage = &fakes.Usage{}
 		stateStore = &fakes.StateStore{}
+		expiryWarner = &fakes.ExpiryWarner{}
 
 		app = NewAppWithConfiguration(application.Configuration{})
 	})
@@ -93,7 +106,7 @@ var _ = Describe("App", func() {
 					},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 
 				Expect(someCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(someCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{
@@ -101,6 +114,32 @@ var _ = Describe("App", func() {
 					"--second-subcommand-flag", "second-value",
 				}))
 			})
+
+			It("executes the command with the context so that it can be interrupted", func() {
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "some",
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				Expect(app.Run(ctx)).To(Succeed())
+
+				Expect(someCmd.ExecuteCall.Receives.Context).To(BeIdenticalTo(ctx))
+			})
+
+			It("warns about expiring certificates before executing the command", func() {
+				state := storage.State{EnvID: "some-env-id"}
+				app = NewAppWithConfiguration(application.Configuration{
+					Command: "some",
+					State:   state,
+				})
+
+				Expect(app.Run(context.Background())).To(Succeed())
+
+				Expect(expiryWarner.WarnCall.CallCount).To(Equal(1))
+				Expect(expiryWarner.WarnCall.Receives.State).To(Equal(state))
+			})
 		})
 
 		Context("when subcommand flags contains help", func() {
@@ -112,7 +151,7 @@ var _ = Describe("App", func() {
 					SubcommandFlags: []string{helpFlag},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.UsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.Receives.Message).To(Equal("some usage message"))
@@ -133,7 +172,7 @@ var _ = Describe("App", func() {
 					SubcommandFlags: []string{"some"},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.UsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.CallCount).To(Equal(1))
 				Expect(usage.PrintCommandUsageCall.Receives.Message).To(Equal("some usage message"))
@@ -148,7 +187,7 @@ var _ = Describe("App", func() {
 						SubcommandFlags: []string{"invalid-command"},
 					})
 
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(err).To(MatchError("unknown command: invalid-command"))
 					Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
 					Expect(usage.PrintCall.CallCount).To(Equal(1))
@@ -166,7 +205,7 @@ var _ = Describe("App", func() {
 					},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 
 				Expect(versionCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(versionCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -180,7 +219,7 @@ var _ = Describe("App", func() {
 					SubcommandFlags: []string{versionFlag},
 				})
 
-				Expect(app.Run()).To(Succeed())
+				Expect(app.Run(context.Background())).To(Succeed())
 				Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
 				Expect(versionCmd.ExecuteCall.CallCount).To(Equal(1))
 				Expect(versionCmd.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -197,9 +236,9 @@ var _ = Describe("App", func() {
 					}, application.Configuration{
 						Command:         "some",
 						SubcommandFlags: []string{"-v"},
-					}, storage.Store{}, usage)
+					}, storage.Store{}, usage, expiryWarner)
 
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(err).To(MatchError("unknown command: version"))
 				})
 			})
@@ -215,7 +254,7 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "some",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(someCmd.CheckFastFailsCall.CallCount).To(Equal(1))
 					Expect(err).To(MatchError("fast failed command"))
 					Expect(someCmd.ExecuteCall.CallCount).To(Equal(0))
@@ -227,7 +266,7 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "some-unknown-command",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(err).To(MatchError("unknown command: some-unknown-command"))
 					Expect(usage.PrintCall.CallCount).To(Equal(1))
 				})
@@ -240,9 +279,10 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "error",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 
 					Expect(err).To(MatchError("The AWS credentials provided have insufficient permissions to perform the operation `bbl error`.\nPlease refer to the bbl README:\nhttps://github.com/cloudfoundry/bosh-bootloader#configure-aws.\nOriginal error message from AWS:\n\nUser is not authorized to perform: action:SubCommand"))
+					Expect(err).To(BeAssignableToTypeOf(application.InsufficientPermissionsError{}))
 				})
 
 				It("returns an error when the error is not AccessDenied", func() {
@@ -251,7 +291,7 @@ var _ = Describe("App", func() {
 					app = NewAppWithConfiguration(application.Configuration{
 						Command: "error",
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 
 					Expect(err).To(ContainSubstring("InternalServerError"))
 					Expect(err).NotTo(ContainSubstring("README"))
@@ -267,9 +307,81 @@ var _ = Describe("App", func() {
 							Debug: true,
 						},
 					})
-					err := app.Run()
+					err := app.Run(context.Background())
 					Expect(err).To(MatchError("error executing command"))
 				})
+
+				It("does not record errors that have no phase", func() {
+					errorCmd.ExecuteCall.Returns.Error = errors.New("error executing command")
+					app = NewAppWithConfiguration(application.Configuration{
+						Command: "error",
+					})
+					_ = app.Run(context.Background())
+
+					Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when the command fails in a phase", func() {
+				var state storage.State
+
+				BeforeEach(func() {
+					state = storage.State{
+						IAAS:  "gcp",
+						EnvID: "some-env-id",
+					}
+					application.SetGetState(func(dir string) (storage.State, error) {
+						Expect(dir).To(Equal("some/state/dir"))
+						return state, nil
+					})
+					application.SetTimeNow(func() time.Time {
+						return time.Date(2017, time.June, 1, 12, 30, 0, 0, time.FixedZone("some-zone", 3600))
+					})
+
+					errorCmd.ExecuteCall.Returns.Error = phaseError{message: "failed to create env"}
+					app = NewAppWithConfiguration(application.Configuration{
+						Command: "error",
+						Global: application.GlobalConfiguration{
+							StateDir: "some/state/dir",
+						},
+					})
+				})
+
+				AfterEach(func() {
+					application.ResetGetState()
+					application.ResetTimeNow()
+				})
+
+				It("records the failure in the saved state", func() {
+					err := app.Run(context.Background())
+					Expect(err).To(MatchError("failed to create env"))
+
+					Expect(stateStore.SetCall.CallCount).To(Equal(1))
+					Expect(stateStore.SetCall.Receives[0].State.EnvID).To(Equal("some-env-id"))
+					Expect(stateStore.SetCall.Receives[0].State.Failures).To(Equal([]storage.Failure{{
+						Time:    time.Date(2017, time.June, 1, 11, 30, 0, 0, time.UTC),
+						Command: "error",
+						Phase:   "create-env",
+						Error:   "failed to create env",
+						Output:  "some-create-env-output",
+					}}))
+				})
+
+				It("does not record the failure when there is no saved state", func() {
+					state = storage.State{}
+
+					err := app.Run(context.Background())
+					Expect(err).To(MatchError("failed to create env"))
+
+					Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				})
+
+				It("returns both errors when the failure cannot be recorded", func() {
+					stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("failed to set state")}}
+
+					err := app.Run(context.Background())
+					Expect(err).To(MatchError("the following errors occurred:\nfailed to create env,\nfailed to set state"))
+				})
 			})
 		})
 	})
@@ -15,6 +15,28 @@ type CommandFinder interface {
 type commandFinder struct {
 }
 
+var globalFlagsWithValues = map[string]bool{
+	"--state-dir":            true,
+	"-state-dir":             true,
+	"--output":               true,
+	"-output":                true,
+	"-o":                     true,
+	"--log-level":            true,
+	"-log-level":             true,
+	"--log-format":           true,
+	"-log-format":            true,
+	"--events":               true,
+	"-events":                true,
+	"--timeout":              true,
+	"-timeout":               true,
+	"--terraform-timeout":    true,
+	"-terraform-timeout":     true,
+	"--create-env-timeout":   true,
+	"-create-env-timeout":    true,
+	"--cloud-config-timeout": true,
+	"-cloud-config-timeout":  true,
+}
+
 func NewCommandFinder() CommandFinder {
 	return commandFinder{}
 }
@@ -27,7 +49,7 @@ func (finder commandFinder) FindCommand(input []string) CommandFinderResult {
 	commandFound := false
 	for index, word := range input {
 		if !strings.HasPrefix(word, "-") {
-			if previousCommand != "--state-dir" && previousCommand != "-state-dir" {
+			if !globalFlagsWithValues[previousCommand] {
 				commandIndex = index
 				commandFound = true
 				break
@@ -22,6 +22,21 @@ var _ = Describe("CommandFinder", func() {
 		Entry("parses the first non-hyphenated word as the state-dir if it directly follows state-dir",
 			[]string{"-state-dir", "help", "delete-errthing", "--other-flag"},
 			application.CommandFinderResult{GlobalFlags: []string{"-state-dir", "help"}, Command: "delete-errthing", OtherArgs: []string{"--other-flag"}}),
+		Entry("parses the first non-hyphenated word as the output format if it directly follows output",
+			[]string{"--output", "json", "lbs", "--other-flag"},
+			application.CommandFinderResult{GlobalFlags: []string{"--output", "json"}, Command: "lbs", OtherArgs: []string{"--other-flag"}}),
+		Entry("parses the first non-hyphenated word as the output format if it directly follows o",
+			[]string{"-o", "yaml", "lbs"},
+			application.CommandFinderResult{GlobalFlags: []string{"-o", "yaml"}, Command: "lbs", OtherArgs: []string{}}),
+		Entry("parses the first non-hyphenated word as the log level if it directly follows log-level",
+			[]string{"--log-level", "debug", "--log-format", "json", "up"},
+			application.CommandFinderResult{GlobalFlags: []string{"--log-level", "debug", "--log-format", "json"}, Command: "up", OtherArgs: []string{}}),
+		Entry("parses the first non-hyphenated word as the events format if it directly follows events",
+			[]string{"--events", "ndjson=/tmp/events", "up", "--iaas", "gcp"},
+			application.CommandFinderResult{GlobalFlags: []string{"--events", "ndjson=/tmp/events"}, Command: "up", OtherArgs: []string{"--iaas", "gcp"}}),
+		Entry("parses the first non-hyphenated word as a timeout if it directly follows a timeout flag",
+			[]string{"--timeout", "2h", "-terraform-timeout", "30m", "--create-env-timeout", "1h", "-cloud-config-timeout", "5m", "up"},
+			application.CommandFinderResult{GlobalFlags: []string{"--timeout", "2h", "-terraform-timeout", "30m", "--create-env-timeout", "1h", "-cloud-config-timeout", "5m"}, Command: "up", OtherArgs: []string{}}),
 		Entry("parses the first non-hyphenated word as the attempted command if --state-dir=x is provided",
 			[]string{"--state-dir=some-dir", "help", "--other-flag"},
 			application.CommandFinderResult{GlobalFlags: []string{"--state-dir=some-dir"}, Command: "help", OtherArgs: []string{"--other-flag"}}),
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 )
 
@@ -17,6 +19,15 @@ type CommandLineConfiguration struct {
 	EndpointOverride string
 	StateDir         string
 	Debug            bool
+	Output           string
+	LogLevel         string
+	LogFormat        string
+	Events           string
+
+	Timeout            time.Duration
+	TerraformTimeout   time.Duration
+	CreateEnvTimeout   time.Duration
+	CloudConfigTimeout time.Duration
 
 	help    bool
 	version bool
@@ -98,6 +109,15 @@ func (c CommandLineParser) parseGlobalFlags(commandLineConfiguration CommandLine
 	globalFlags.String(&commandLineConfiguration.EndpointOverride, "endpoint-override", "")
 	globalFlags.String(&commandLineConfiguration.StateDir, "state-dir", "")
 	globalFlags.Bool(&commandLineConfiguration.Debug, "d", "debug", (debugEnv == "true"))
+	globalFlags.String(&commandLineConfiguration.Output, "output", commands.TextOutputFormat)
+	globalFlags.String(&commandLineConfiguration.Output, "o", commands.TextOutputFormat)
+	globalFlags.String(&commandLineConfiguration.LogLevel, "log-level", InfoLogLevel.String())
+	globalFlags.String(&commandLineConfiguration.LogFormat, "log-format", TextLogFormat)
+	globalFlags.String(&commandLineConfiguration.Events, "events", "")
+	globalFlags.Duration(&commandLineConfiguration.Timeout, "timeout", 0)
+	globalFlags.Duration(&commandLineConfiguration.TerraformTimeout, "terraform-timeout", 0)
+	globalFlags.Duration(&commandLineConfiguration.CreateEnvTimeout, "create-env-timeout", 0)
+	globalFlags.Duration(&commandLineConfiguration.CloudConfigTimeout, "cloud-config-timeout", 0)
 
 	globalFlags.Bool(&commandLineConfiguration.help, "h", "help", false)
 	globalFlags.Bool(&commandLineConfiguration.version, "v", "version", false)
@@ -107,6 +127,43 @@ func (c CommandLineParser) parseGlobalFlags(commandLineConfiguration CommandLine
 		return CommandLineConfiguration{}, []string{}, err
 	}
 
+	err = commands.ValidateOutputFormat(commandLineConfiguration.Output)
+	if err != nil {
+		return CommandLineConfiguration{}, []string{}, err
+	}
+
+	_, err = ParseLogLevel(commandLineConfiguration.LogLevel)
+	if err != nil {
+		return CommandLineConfiguration{}, []string{}, err
+	}
+
+	err = ValidateLogFormat(commandLineConfiguration.LogFormat)
+	if err != nil {
+		return CommandLineConfiguration{}, []string{}, err
+	}
+
+	if commandLineConfiguration.Events != "" {
+		_, err = ParseEventsFlag(commandLineConfiguration.Events)
+		if err != nil {
+			return CommandLineConfiguration{}, []string{}, err
+		}
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"timeout", commandLineConfiguration.Timeout},
+		{"terraform-timeout", commandLineConfiguration.TerraformTimeout},
+		{"create-env-timeout", commandLineConfiguration.CreateEnvTimeout},
+		{"cloud-config-timeout", commandLineConfiguration.CloudConfigTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			return CommandLineConfiguration{}, []string{}, fmt.Errorf("--%s must not be negative", timeout.name)
+		}
+	}
+
 	return commandLineConfiguration, globalFlags.Args(), nil
 }
 
@@ -3,6 +3,7 @@ package application_test
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -49,6 +50,88 @@ var _ = Describe("CommandLineParser", func() {
 			Expect(commandLineConfiguration.EndpointOverride).To(Equal("some-endpoint-override"))
 			Expect(commandLineConfiguration.StateDir).To(Equal("some/state/dir"))
 			Expect(commandLineConfiguration.Debug).To(BeTrue())
+			Expect(commandLineConfiguration.Output).To(Equal("text"))
+		})
+
+		DescribeTable("returns a command line configuration with the output format", func(arguments string, expectedFormat string) {
+			commandLineConfiguration, err := commandLineParser.Parse(strings.Split(arguments, " "))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(commandLineConfiguration.Command).To(Equal("up"))
+			Expect(commandLineConfiguration.Output).To(Equal(expectedFormat))
+		},
+			Entry("--output with spaces", "--output json up", "json"),
+			Entry("--output with equal signs", "--output=yaml up", "yaml"),
+			Entry("-o with spaces", "-o json up", "json"),
+			Entry("--output text", "--output text up", "text"),
+		)
+
+		It("returns a command line configuration with the log level and format", func() {
+			commandLineConfiguration, err := commandLineParser.Parse([]string{"--log-level", "warn", "--log-format=json", "up"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(commandLineConfiguration.Command).To(Equal("up"))
+			Expect(commandLineConfiguration.LogLevel).To(Equal("warn"))
+			Expect(commandLineConfiguration.LogFormat).To(Equal("json"))
+		})
+
+		It("defaults the log level to info and the log format to text", func() {
+			commandLineConfiguration, err := commandLineParser.Parse([]string{"up"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(commandLineConfiguration.LogLevel).To(Equal("info"))
+			Expect(commandLineConfiguration.LogFormat).To(Equal("text"))
+		})
+
+		It("returns an error when the log level is not supported", func() {
+			_, err := commandLineParser.Parse([]string{"--log-level", "trace", "up"})
+			Expect(err).To(MatchError(`"trace" is an invalid log level, supported values are: [debug, info, warn, error]`))
+		})
+
+		It("returns an error when the log format is not supported", func() {
+			_, err := commandLineParser.Parse([]string{"--log-format", "xml", "up"})
+			Expect(err).To(MatchError(`"xml" is an invalid log format, supported values are: [text, json]`))
+		})
+
+		It("returns a command line configuration with the events format", func() {
+			commandLineConfiguration, err := commandLineParser.Parse([]string{"--events", "ndjson=/tmp/events", "up"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(commandLineConfiguration.Command).To(Equal("up"))
+			Expect(commandLineConfiguration.Events).To(Equal("ndjson=/tmp/events"))
+		})
+
+		It("returns an error when the events format is not supported", func() {
+			_, err := commandLineParser.Parse([]string{"--events", "xml", "up"})
+			Expect(err).To(MatchError(`"xml" is an invalid events format, supported values are: [ndjson, ndjson=<path>]`))
+		})
+
+		It("returns a command line configuration with the timeouts", func() {
+			commandLineConfiguration, err := commandLineParser.Parse([]string{
+				"--timeout", "2h",
+				"--terraform-timeout", "30m",
+				"--create-env-timeout=1h",
+				"--cloud-config-timeout", "5m",
+				"up",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(commandLineConfiguration.Command).To(Equal("up"))
+			Expect(commandLineConfiguration.Timeout).To(Equal(2 * time.Hour))
+			Expect(commandLineConfiguration.TerraformTimeout).To(Equal(30 * time.Minute))
+			Expect(commandLineConfiguration.CreateEnvTimeout).To(Equal(time.Hour))
+			Expect(commandLineConfiguration.CloudConfigTimeout).To(Equal(5 * time.Minute))
+		})
+
+		It("returns an error when a timeout is negative", func() {
+			_, err := commandLineParser.Parse([]string{"--create-env-timeout", "-1m", "up"})
+			Expect(err).To(MatchError("--create-env-timeout must not be negative"))
+		})
+
+		It("returns an error when the output format is not supported", func() {
+			_, err := commandLineParser.Parse([]string{"--output", "xml", "up"})
+			Expect(err).To(MatchError(`"xml" is an invalid output format, supported values are: [text, json, yaml]`))
+			Expect(usageCallCount).To(Equal(1))
 		})
 
 		It("returns a command line configuration with correct command with subcommand flags based on arguments passed in", func() {
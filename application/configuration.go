@@ -1,11 +1,24 @@
 package application
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type GlobalConfiguration struct {
 	EndpointOverride string
 	StateDir         string
 	Debug            bool
+	Output           string
+	LogLevel         LogLevel
+	LogFormat        string
+	Events           bool
+	EventsPath       string
+
+	TerraformTimeout   time.Duration
+	CreateEnvTimeout   time.Duration
+	CloudConfigTimeout time.Duration
 }
 
 type StringSlice []string
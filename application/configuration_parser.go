@@ -1,6 +1,11 @@
 package application
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 var getState func(string) (storage.State, error) = storage.GetState
 
@@ -28,11 +33,43 @@ func (p ConfigurationParser) Parse(arguments []string) (Configuration, error) {
 		return Configuration{}, err
 	}
 
+	logLevel, err := ParseLogLevel(commandLineConfiguration.LogLevel)
+	if err != nil {
+		logLevel = InfoLogLevel
+	}
+
+	if commandLineConfiguration.Debug {
+		logLevel = DebugLogLevel
+	}
+
+	logFormat := commandLineConfiguration.LogFormat
+	if logFormat == "" {
+		logFormat = TextLogFormat
+	}
+
+	var eventsPath string
+	events := commandLineConfiguration.Events != ""
+	if events {
+		eventsPath, err = ParseEventsFlag(commandLineConfiguration.Events)
+		if err != nil {
+			return Configuration{}, err
+		}
+	}
+
 	configuration := Configuration{
 		Global: GlobalConfiguration{
 			StateDir:         commandLineConfiguration.StateDir,
 			EndpointOverride: commandLineConfiguration.EndpointOverride,
-			Debug:            commandLineConfiguration.Debug,
+			Debug:            commandLineConfiguration.Debug || logLevel == DebugLogLevel,
+			Output:           commandLineConfiguration.Output,
+			LogLevel:         logLevel,
+			LogFormat:        logFormat,
+			Events:           events,
+			EventsPath:       eventsPath,
+
+			TerraformTimeout:   phaseTimeout(commandLineConfiguration.TerraformTimeout, commandLineConfiguration.Timeout),
+			CreateEnvTimeout:   phaseTimeout(commandLineConfiguration.CreateEnvTimeout, commandLineConfiguration.Timeout),
+			CloudConfigTimeout: phaseTimeout(commandLineConfiguration.CloudConfigTimeout, commandLineConfiguration.Timeout),
 		},
 		Command:         commandLineConfiguration.Command,
 		SubcommandFlags: commandLineConfiguration.SubcommandFlags,
@@ -41,7 +78,7 @@ func (p ConfigurationParser) Parse(arguments []string) (Configuration, error) {
 
 	if !p.isHelpOrVersion(configuration.Command, configuration.SubcommandFlags) {
 		configuration.State, err = getState(configuration.Global.StateDir)
-		if err != nil {
+		if err != nil && configuration.Command != commands.DoctorCommand {
 			return Configuration{}, err
 		}
 	}
@@ -49,6 +86,14 @@ func (p ConfigurationParser) Parse(arguments []string) (Configuration, error) {
 	return configuration, nil
 }
 
+func phaseTimeout(phaseTimeout, timeout time.Duration) time.Duration {
+	if phaseTimeout != 0 {
+		return phaseTimeout
+	}
+
+	return timeout
+}
+
 func (ConfigurationParser) isHelpOrVersion(command string, subcommandFlags StringSlice) bool {
 	if command == "help" || command == "version" {
 		return true
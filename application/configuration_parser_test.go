@@ -2,6 +2,7 @@ package application_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
@@ -37,6 +38,7 @@ var _ = Describe("ConfigurationParser", func() {
 				StateDir:         "some/state/dir",
 				EndpointOverride: "some-endpoint-override",
 				Debug:            true,
+				Output:           "json",
 			}
 			configuration, err := configurationParser.Parse([]string{"up"})
 			Expect(err).NotTo(HaveOccurred())
@@ -47,11 +49,117 @@ var _ = Describe("ConfigurationParser", func() {
 				EndpointOverride: "some-endpoint-override",
 				StateDir:         "some/state/dir",
 				Debug:            true,
+				Output:           "json",
+				LogLevel:         application.DebugLogLevel,
+				LogFormat:        "text",
 			}))
 
 			Expect(commandLineParser.ParseCall.Receives.Arguments).To(Equal([]string{"up"}))
 		})
 
+		Describe("logging", func() {
+			It("parses the log level and format", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command:   "up",
+					LogLevel:  "warn",
+					LogFormat: "json",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.LogLevel).To(Equal(application.WarnLogLevel))
+				Expect(configuration.Global.LogFormat).To(Equal("json"))
+				Expect(configuration.Global.Debug).To(BeFalse())
+			})
+
+			It("enables debug when the log level is debug", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command:  "up",
+					LogLevel: "debug",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.LogLevel).To(Equal(application.DebugLogLevel))
+				Expect(configuration.Global.Debug).To(BeTrue())
+			})
+
+			It("defaults to the info level and text format", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "up",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.LogLevel).To(Equal(application.InfoLogLevel))
+				Expect(configuration.Global.LogFormat).To(Equal("text"))
+			})
+		})
+
+		Describe("events", func() {
+			It("enables events written to stdout", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "up",
+					Events:  "ndjson",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.Events).To(BeTrue())
+				Expect(configuration.Global.EventsPath).To(Equal(""))
+			})
+
+			It("enables events written to a path", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "up",
+					Events:  "ndjson=/tmp/events.ndjson",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.Events).To(BeTrue())
+				Expect(configuration.Global.EventsPath).To(Equal("/tmp/events.ndjson"))
+			})
+
+			It("does not enable events by default", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "up",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.Events).To(BeFalse())
+			})
+		})
+
+		Describe("timeouts", func() {
+			It("uses the timeout for every phase without its own timeout", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command:          "up",
+					Timeout:          2 * time.Hour,
+					CreateEnvTimeout: time.Hour,
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.TerraformTimeout).To(Equal(2 * time.Hour))
+				Expect(configuration.Global.CreateEnvTimeout).To(Equal(time.Hour))
+				Expect(configuration.Global.CloudConfigTimeout).To(Equal(2 * time.Hour))
+			})
+
+			It("does not time out by default", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "up",
+				}
+				configuration, err := configurationParser.Parse([]string{"up"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configuration.Global.TerraformTimeout).To(BeZero())
+				Expect(configuration.Global.CreateEnvTimeout).To(BeZero())
+				Expect(configuration.Global.CloudConfigTimeout).To(BeZero())
+			})
+		})
+
 		Describe("state management", func() {
 			It("returns a configuration with the state from the state store", func() {
 				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
@@ -105,6 +213,19 @@ var _ = Describe("ConfigurationParser", func() {
 
 				Expect(err).To(MatchError("failed to read state"))
 			})
+
+			It("does not return an error when the state cannot be read for doctor, so that doctor can report it", func() {
+				commandLineParser.ParseCall.Returns.CommandLineConfiguration = application.CommandLineConfiguration{
+					Command: "doctor",
+				}
+				application.SetGetState(func(dir string) (storage.State, error) {
+					return storage.State{}, errors.New("failed to read state")
+				})
+
+				configuration, err := configurationParser.Parse([]string{"doctor"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configuration.Command).To(Equal("doctor"))
+			})
 		})
 	})
 })
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+const (
+	BBLNotFoundErrorCode             = "bbl_not_found"
+	LBNotFoundErrorCode              = "lb_not_found"
+	EnvironmentUnhealthyErrorCode    = "environment_unhealthy"
+	DoctorChecksFailedErrorCode      = "doctor_checks_failed"
+	DriftDetectedErrorCode           = "drift_detected"
+	CertificatesExpiringErrorCode    = "certificates_expiring"
+	TerraformFailedErrorCode         = "terraform_failed"
+	CreateEnvFailedErrorCode         = "create_env_failed"
+	DeleteEnvFailedErrorCode         = "delete_env_failed"
+	InsufficientPermissionsErrorCode = "insufficient_permissions"
+	InsufficientQuotaErrorCode       = "insufficient_quota"
+	IAASRequestFailedErrorCode       = "iaas_request_failed"
+	UnknownErrorCode                 = "error"
+)
+
+func ErrorCode(err error) string {
+	switch err {
+	case BBLNotFound, commands.BBLNotFound:
+		return BBLNotFoundErrorCode
+	case commands.LBNotFound:
+		return LBNotFoundErrorCode
+	case commands.EnvironmentUnhealthy:
+		return EnvironmentUnhealthyErrorCode
+	case commands.DoctorChecksFailed:
+		return DoctorChecksFailedErrorCode
+	case commands.DriftDetected, commands.MigrationDriftDetected:
+		return DriftDetectedErrorCode
+	case commands.CertificatesExpiring:
+		return CertificatesExpiringErrorCode
+	}
+
+	switch err.(type) {
+	case terraform.ManagerError:
+		return TerraformFailedErrorCode
+	case bosh.ManagerCreateError:
+		return CreateEnvFailedErrorCode
+	case bosh.ManagerDeleteError:
+		return DeleteEnvFailedErrorCode
+	case InsufficientPermissionsError:
+		return InsufficientPermissionsErrorCode
+	case quota.ShortfallError:
+		return InsufficientQuotaErrorCode
+	case awserr.RequestFailure:
+		return IAASRequestFailedErrorCode
+	}
+
+	return UnknownErrorCode
+}
@@ -0,0 +1,40 @@
+package application_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ErrorCode", func() {
+	DescribeTable("returns a stable code for the error",
+		func(err error, expectedCode string) {
+			Expect(application.ErrorCode(err)).To(Equal(expectedCode))
+		},
+		Entry("application bbl not found", application.BBLNotFound, "bbl_not_found"),
+		Entry("commands bbl not found", commands.BBLNotFound, "bbl_not_found"),
+		Entry("lb not found", commands.LBNotFound, "lb_not_found"),
+		Entry("environment unhealthy", commands.EnvironmentUnhealthy, "environment_unhealthy"),
+		Entry("doctor checks failed", commands.DoctorChecksFailed, "doctor_checks_failed"),
+		Entry("drift detected", commands.DriftDetected, "drift_detected"),
+		Entry("migration drift detected", commands.MigrationDriftDetected, "drift_detected"),
+		Entry("certificates expiring", commands.CertificatesExpiring, "certificates_expiring"),
+		Entry("terraform manager error", terraform.ManagerError{}, "terraform_failed"),
+		Entry("bosh create-env error", bosh.NewManagerCreateError(storage.State{}, storage.CreateEnvPhase, errors.New("failed")), "create_env_failed"),
+		Entry("bosh delete-env error", bosh.NewManagerDeleteError(storage.State{}, errors.New("failed")), "delete_env_failed"),
+		Entry("insufficient permissions", application.NewInsufficientPermissionsError("up", "denied"), "insufficient_permissions"),
+		Entry("insufficient quota", quota.NewShortfallError([]quota.Shortfall{}), "insufficient_quota"),
+		Entry("aws request failure", awserr.NewRequestFailure(awserr.New("InternalServerError", "failed", nil), 500, "some-request-id"), "iaas_request_failed"),
+		Entry("any other error", errors.New("something went wrong"), "error"),
+	)
+})
@@ -0,0 +1,25 @@
+package application
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type EventStateStore struct {
+	stateStore stateStore
+	events     *EventStream
+}
+
+func NewEventStateStore(stateStore stateStore, events *EventStream) EventStateStore {
+	return EventStateStore{
+		stateStore: stateStore,
+		events:     events,
+	}
+}
+
+func (s EventStateStore) Set(state storage.State) error {
+	err := s.stateStore.Set(state)
+	if err != nil {
+		return err
+	}
+
+	s.events.StateSaved()
+	return nil
+}
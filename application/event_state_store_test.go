@@ -0,0 +1,45 @@
+package application_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStateStore", func() {
+	var (
+		stateStore *fakes.StateStore
+		buffer     *bytes.Buffer
+		store      application.EventStateStore
+	)
+
+	BeforeEach(func() {
+		stateStore = &fakes.StateStore{}
+		buffer = bytes.NewBuffer([]byte{})
+		store = application.NewEventStateStore(stateStore, application.NewEventStream(buffer))
+	})
+
+	It("saves the state and emits a state saved event", func() {
+		err := store.Set(storage.State{EnvID: "some-env-id"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stateStore.SetCall.CallCount).To(Equal(1))
+		Expect(stateStore.SetCall.Receives[0].State).To(Equal(storage.State{EnvID: "some-env-id"}))
+		Expect(buffer.String()).To(ContainSubstring(`"event":"state_saved"`))
+	})
+
+	It("does not emit an event when the state cannot be saved", func() {
+		stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("failed to save")}}
+
+		err := store.Set(storage.State{})
+		Expect(err).To(MatchError("failed to save"))
+
+		Expect(buffer.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	NDJSONEventFormat = "ndjson"
+
+	CommandStartedEvent    = "command_started"
+	StepStartedEvent       = "step_started"
+	StepFinishedEvent      = "step_finished"
+	SubprocessStartedEvent = "subprocess_started"
+	SubprocessExitedEvent  = "subprocess_exited"
+	StateSavedEvent        = "state_saved"
+	WarningEvent           = "warning"
+	ResultEvent            = "result"
+)
+
+type Event struct {
+	Time       string `json:"time"`
+	Event      string `json:"event"`
+	Command    string `json:"command,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Step       string `json:"step,omitempty"`
+	Subprocess string `json:"subprocess,omitempty"`
+	Subcommand string `json:"subcommand,omitempty"`
+	Message    string `json:"message,omitempty"`
+	DurationMS *int64 `json:"duration_ms,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	Success    *bool  `json:"success,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type EventStream struct {
+	mutex          *sync.Mutex
+	writer         io.Writer
+	command        string
+	commandStarted time.Time
+	step           string
+	stepStarted    time.Time
+}
+
+func NewEventStream(writer io.Writer) *EventStream {
+	return &EventStream{
+		mutex:  &sync.Mutex{},
+		writer: writer,
+	}
+}
+
+func ParseEventsFlag(value string) (string, error) {
+	if value == NDJSONEventFormat {
+		return "", nil
+	}
+
+	if strings.HasPrefix(value, NDJSONEventFormat+"=") {
+		path := strings.TrimPrefix(value, NDJSONEventFormat+"=")
+		if path != "" {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%q is an invalid events format, supported values are: [%s, %s=<path>]", value, NDJSONEventFormat, NDJSONEventFormat)
+}
+
+func (e *EventStream) CommandStarted(command, version string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := timeNow()
+	e.command = command
+	e.commandStarted = now
+
+	e.emit(Event{
+		Time:    now.Format(logTimeFormat),
+		Event:   CommandStartedEvent,
+		Command: command,
+		Version: version,
+	})
+}
+
+func (e *EventStream) Step(message string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := timeNow()
+	e.finishStep(now)
+
+	e.step = message
+	e.stepStarted = now
+	e.emit(Event{
+		Time:  now.Format(logTimeFormat),
+		Event: StepStartedEvent,
+		Step:  message,
+	})
+}
+
+func (e *EventStream) Warning(message string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.emit(Event{
+		Time:    timeNow().Format(logTimeFormat),
+		Event:   WarningEvent,
+		Message: message,
+	})
+}
+
+func (e *EventStream) SubprocessStarted(name, subcommand string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.emit(Event{
+		Time:       timeNow().Format(logTimeFormat),
+		Event:      SubprocessStartedEvent,
+		Subprocess: name,
+		Subcommand: subcommand,
+	})
+}
+
+func (e *EventStream) SubprocessExited(name, subcommand string, duration time.Duration, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	exitCode := 0
+	event := Event{
+		Time:       timeNow().Format(logTimeFormat),
+		Event:      SubprocessExitedEvent,
+		Subprocess: name,
+		Subcommand: subcommand,
+		DurationMS: milliseconds(duration),
+		ExitCode:   &exitCode,
+	}
+
+	if err != nil {
+		exitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				exitCode = status.ExitStatus()
+			}
+		}
+		event.Error = err.Error()
+	}
+
+	e.emit(event)
+}
+
+func (e *EventStream) StateSaved() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.emit(Event{
+		Time:  timeNow().Format(logTimeFormat),
+		Event: StateSavedEvent,
+	})
+}
+
+func (e *EventStream) Result(err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := timeNow()
+	e.finishStep(now)
+
+	success := err == nil
+	event := Event{
+		Time:       now.Format(logTimeFormat),
+		Event:      ResultEvent,
+		Command:    e.command,
+		DurationMS: milliseconds(now.Sub(e.commandStarted)),
+		Success:    &success,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	e.emit(event)
+}
+
+func (e *EventStream) finishStep(now time.Time) {
+	if e.step == "" {
+		return
+	}
+
+	e.emit(Event{
+		Time:       now.Format(logTimeFormat),
+		Event:      StepFinishedEvent,
+		Step:       e.step,
+		DurationMS: milliseconds(now.Sub(e.stepStarted)),
+	})
+	e.step = ""
+}
+
+func (e *EventStream) emit(event Event) {
+	contents, err := json.Marshal(event)
+	if err != nil {
+		// not tested
+		return
+	}
+
+	fmt.Fprintf(e.writer, "%s\n", contents)
+}
+
+func milliseconds(duration time.Duration) *int64 {
+	ms := int64(duration / time.Millisecond)
+	return &ms
+}
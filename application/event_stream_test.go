@@ -0,0 +1,118 @@
+package application_test
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStream", func() {
+	var (
+		buffer *bytes.Buffer
+		events *application.EventStream
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		buffer = bytes.NewBuffer([]byte{})
+		events = application.NewEventStream(buffer)
+
+		now = time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+		application.SetTimeNow(func() time.Time {
+			return now
+		})
+	})
+
+	AfterEach(func() {
+		application.ResetTimeNow()
+	})
+
+	It("emits an event per line for the lifetime of a command", func() {
+		events.CommandStarted("up", "1.2.3")
+		events.Step("generating terraform variables")
+		now = now.Add(1500 * time.Millisecond)
+		events.Step("applying terraform template")
+		events.SubprocessStarted("terraform", "apply")
+		events.SubprocessExited("terraform", "apply", 2*time.Second, nil)
+		events.StateSaved()
+		events.Warning("certificate expires soon")
+		now = now.Add(2 * time.Second)
+		events.Result(nil)
+
+		Expect(buffer.String()).To(Equal(`{"time":"2017-06-01T12:30:00.000Z","event":"command_started","command":"up","version":"1.2.3"}
+{"time":"2017-06-01T12:30:00.000Z","event":"step_started","step":"generating terraform variables"}
+{"time":"2017-06-01T12:30:01.500Z","event":"step_finished","step":"generating terraform variables","duration_ms":1500}
+{"time":"2017-06-01T12:30:01.500Z","event":"step_started","step":"applying terraform template"}
+{"time":"2017-06-01T12:30:01.500Z","event":"subprocess_started","subprocess":"terraform","subcommand":"apply"}
+{"time":"2017-06-01T12:30:01.500Z","event":"subprocess_exited","subprocess":"terraform","subcommand":"apply","duration_ms":2000,"exit_code":0}
+{"time":"2017-06-01T12:30:01.500Z","event":"state_saved"}
+{"time":"2017-06-01T12:30:01.500Z","event":"warning","message":"certificate expires soon"}
+{"time":"2017-06-01T12:30:03.500Z","event":"step_finished","step":"applying terraform template","duration_ms":2000}
+{"time":"2017-06-01T12:30:03.500Z","event":"result","command":"up","duration_ms":3500,"success":true}
+`))
+	})
+
+	It("emits a failed result with the error", func() {
+		events.CommandStarted("destroy", "1.2.3")
+		events.Result(errors.New("failed to destroy"))
+
+		Expect(buffer.String()).To(HaveSuffix(`{"time":"2017-06-01T12:30:00.000Z","event":"result","command":"destroy","duration_ms":0,"success":false,"error":"failed to destroy"}
+`))
+	})
+
+	Describe("SubprocessExited", func() {
+		It("emits the exit code of a failed subprocess", func() {
+			err := exec.Command("sh", "-c", "exit 3").Run()
+
+			events.SubprocessExited("bosh", "create-env", time.Second, err)
+
+			Expect(buffer.String()).To(Equal(`{"time":"2017-06-01T12:30:00.000Z","event":"subprocess_exited","subprocess":"bosh","subcommand":"create-env","duration_ms":1000,"exit_code":3,"error":"exit status 3"}
+`))
+		})
+
+		It("emits an exit code of -1 when the subprocess could not be run", func() {
+			events.SubprocessExited("bosh", "create-env", 0, errors.New("executable file not found"))
+
+			Expect(buffer.String()).To(ContainSubstring(`"exit_code":-1,"error":"executable file not found"`))
+		})
+	})
+
+	Describe("ParseEventsFlag", func() {
+		It("returns an empty path for ndjson", func() {
+			path, err := application.ParseEventsFlag("ndjson")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal(""))
+		})
+
+		It("returns the path for ndjson=<path>", func() {
+			path, err := application.ParseEventsFlag("ndjson=/tmp/events.ndjson")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/tmp/events.ndjson"))
+		})
+
+		It("returns an error for other formats", func() {
+			_, err := application.ParseEventsFlag("ndjson=")
+			Expect(err).To(MatchError(`"ndjson=" is an invalid events format, supported values are: [ndjson, ndjson=<path>]`))
+		})
+	})
+
+	Describe("with a logger", func() {
+		It("emits step and warning events for logged steps and warnings", func() {
+			logger := application.NewLogger(bytes.NewBuffer([]byte{}))
+			logger.SetEventStream(events)
+
+			logger.Step("creating %s", "keypair")
+			logger.Warn("something odd")
+
+			Expect(buffer.String()).To(Equal(`{"time":"2017-06-01T12:30:00.000Z","event":"step_started","step":"creating keypair"}
+{"time":"2017-06-01T12:30:00.000Z","event":"warning","message":"something odd"}
+`))
+		})
+	})
+})
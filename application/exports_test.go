@@ -2,6 +2,7 @@ package application
 
 import (
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -21,3 +22,19 @@ func SetGetState(f func(string) (storage.State, error)) {
 func ResetGetState() {
 	getState = storage.GetState
 }
+
+func SetTimeNow(f func() time.Time) {
+	timeNow = f
+}
+
+func ResetTimeNow() {
+	timeNow = time.Now
+}
+
+func SetExit(f func(int)) {
+	exit = f
+}
+
+func ResetExit() {
+	exit = os.Exit
+}
@@ -0,0 +1,21 @@
+package application
+
+import "fmt"
+
+type InsufficientPermissionsError struct {
+	command string
+	message string
+}
+
+func NewInsufficientPermissionsError(command, message string) InsufficientPermissionsError {
+	return InsufficientPermissionsError{
+		command: command,
+		message: message,
+	}
+}
+
+func (e InsufficientPermissionsError) Error() string {
+	return fmt.Sprintf(
+		"The AWS credentials provided have insufficient permissions to perform the operation `bbl %s`.\nPlease refer to the bbl README:\nhttps://github.com/cloudfoundry/bosh-bootloader#configure-aws.\nOriginal error message from AWS:\n\n%s",
+		e.command, e.message)
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"context"
+	"os"
+)
+
+var exit func(int) = os.Exit
+
+type warnLogger interface {
+	Warn(message string, a ...interface{})
+}
+
+// CancelOnInterrupt returns a context that is cancelled on the first signal so
+// that running terraform and bosh processes can exit cleanly and their state
+// can be saved. A second signal exits immediately.
+func CancelOnInterrupt(signals <-chan os.Signal, logger warnLogger) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		signal := <-signals
+		logger.Warn("received %s, waiting for running processes to exit and saving state (interrupt again to exit immediately)", signal)
+		cancel()
+
+		signal = <-signals
+		logger.Warn("received %s, exiting without saving state", signal)
+		exit(130)
+	}()
+
+	return ctx
+}
@@ -0,0 +1,56 @@
+package application_test
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("CancelOnInterrupt", func() {
+	var (
+		signals   chan os.Signal
+		buffer    *gbytes.Buffer
+		exitCodes chan int
+	)
+
+	BeforeEach(func() {
+		signals = make(chan os.Signal, 2)
+		buffer = gbytes.NewBuffer()
+
+		exitCodes = make(chan int, 1)
+		application.SetExit(func(code int) {
+			exitCodes <- code
+		})
+	})
+
+	AfterEach(func() {
+		application.ResetExit()
+	})
+
+	It("cancels the context when a signal is received", func() {
+		ctx := application.CancelOnInterrupt(signals, application.NewLogger(buffer))
+		Consistently(ctx.Done()).ShouldNot(BeClosed())
+
+		signals <- os.Interrupt
+
+		Eventually(ctx.Done()).Should(BeClosed())
+		Expect(buffer).To(gbytes.Say("received interrupt, waiting for running processes to exit and saving state"))
+		Consistently(exitCodes).ShouldNot(Receive())
+	})
+
+	It("exits when a second signal is received", func() {
+		ctx := application.CancelOnInterrupt(signals, application.NewLogger(buffer))
+
+		signals <- syscall.SIGTERM
+		Eventually(ctx.Done()).Should(BeClosed())
+
+		signals <- os.Interrupt
+		Eventually(exitCodes).Should(Receive(Equal(130)))
+		Expect(buffer).To(gbytes.Say("received interrupt, exiting without saving state"))
+	})
+})
@@ -0,0 +1,57 @@
+package application
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	LogDirectoryName = "logs"
+	MaxLogFiles      = 20
+)
+
+func NewLogFile(stateDir, command string) (*os.File, error) {
+	logDir := filepath.Join(stateDir, LogDirectoryName)
+	err := os.MkdirAll(logDir, os.FileMode(0700))
+	if err != nil {
+		return nil, err
+	}
+
+	err = pruneLogFiles(logDir, MaxLogFiles-1)
+	if err != nil {
+		return nil, err
+	}
+
+	logFileName := fmt.Sprintf("bbl-%s-%s.log", timeNow().UTC().Format("20060102T150405.000000000Z"), command)
+
+	return os.OpenFile(filepath.Join(logDir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(0600))
+}
+
+func pruneLogFiles(logDir string, keep int) error {
+	fileInfos, err := ioutil.ReadDir(logDir)
+	if err != nil {
+		return err
+	}
+
+	var logFileNames []string
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() && strings.HasPrefix(fileInfo.Name(), "bbl-") && strings.HasSuffix(fileInfo.Name(), ".log") {
+			logFileNames = append(logFileNames, fileInfo.Name())
+		}
+	}
+	sort.Strings(logFileNames)
+
+	for len(logFileNames) > keep {
+		err = os.Remove(filepath.Join(logDir, logFileNames[0]))
+		if err != nil {
+			return err
+		}
+		logFileNames = logFileNames[1:]
+	}
+
+	return nil
+}
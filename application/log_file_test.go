@@ -0,0 +1,88 @@
+package application_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/application"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewLogFile", func() {
+	var (
+		stateDir string
+		logDir   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		stateDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		logDir = filepath.Join(stateDir, "logs")
+
+		application.SetTimeNow(func() time.Time {
+			return time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+		})
+	})
+
+	AfterEach(func() {
+		application.ResetTimeNow()
+		os.RemoveAll(stateDir)
+	})
+
+	It("creates a log file for the command in the logs directory of the state dir", func() {
+		logFile, err := application.NewLogFile(stateDir, "up")
+		Expect(err).NotTo(HaveOccurred())
+		defer logFile.Close()
+
+		Expect(logFile.Name()).To(Equal(filepath.Join(logDir, "bbl-20170601T123000.000000000Z-up.log")))
+
+		fileInfo, err := os.Stat(logFile.Name())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileInfo.Mode()).To(Equal(os.FileMode(0600)))
+
+		dirInfo, err := os.Stat(logDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dirInfo.Mode().Perm()).To(Equal(os.FileMode(0700)))
+	})
+
+	It("removes the oldest log files so that no more than the maximum are kept", func() {
+		err := os.MkdirAll(logDir, os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < application.MaxLogFiles; i++ {
+			err = ioutil.WriteFile(filepath.Join(logDir, fmt.Sprintf("bbl-20170101T0000%02d.000000000Z-up.log", i)), []byte{}, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		err = ioutil.WriteFile(filepath.Join(logDir, "some-other-file"), []byte{}, os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		logFile, err := application.NewLogFile(stateDir, "destroy")
+		Expect(err).NotTo(HaveOccurred())
+		defer logFile.Close()
+
+		fileInfos, err := ioutil.ReadDir(logDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileInfos).To(HaveLen(application.MaxLogFiles + 1))
+
+		Expect(filepath.Join(logDir, "bbl-20170101T000000.000000000Z-up.log")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(logDir, "bbl-20170101T000001.000000000Z-up.log")).To(BeAnExistingFile())
+		Expect(filepath.Join(logDir, "some-other-file")).To(BeAnExistingFile())
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the logs directory cannot be created", func() {
+			err := ioutil.WriteFile(logDir, []byte{}, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = application.NewLogFile(stateDir, "up")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,20 +1,112 @@
 package application
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
 )
 
+type LogLevel int
+
+const (
+	DebugLogLevel LogLevel = iota
+	InfoLogLevel
+	WarnLogLevel
+	ErrorLogLevel
+)
+
+const (
+	TextLogFormat = "text"
+	JSONLogFormat = "json"
+
+	logTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+var logLevelNames = []string{"debug", "info", "warn", "error"}
+
+var timeNow = time.Now
+
 type Logger struct {
-	newline bool
-	writer  io.Writer
+	mutex             *sync.Mutex
+	newline           bool
+	writer            io.Writer
+	level             LogLevel
+	json              bool
+	logFile           io.Writer
+	subprocessWriters []*subprocessWriter
+	events            *EventStream
+}
+
+type logEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
 }
 
 func NewLogger(writer io.Writer) *Logger {
 	return &Logger{
+		mutex:   &sync.Mutex{},
 		newline: true,
 		writer:  writer,
+		level:   InfoLogLevel,
+		logFile: ioutil.Discard,
+	}
+}
+
+func ParseLogLevel(name string) (LogLevel, error) {
+	for level, levelName := range logLevelNames {
+		if name == levelName {
+			return LogLevel(level), nil
+		}
 	}
+
+	return InfoLogLevel, fmt.Errorf("%q is an invalid log level, supported values are: [%s]", name, strings.Join(logLevelNames, ", "))
+}
+
+func ValidateLogFormat(format string) error {
+	if format == TextLogFormat || format == JSONLogFormat {
+		return nil
+	}
+
+	return fmt.Errorf("%q is an invalid log format, supported values are: [%s, %s]", format, TextLogFormat, JSONLogFormat)
+}
+
+func (l LogLevel) String() string {
+	return logLevelNames[l]
+}
+
+func (l *Logger) SetLevel(level LogLevel) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.level = level
+}
+
+func (l *Logger) SetFormat(format string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.json = format == JSONLogFormat
+}
+
+func (l *Logger) SetLogFile(logFile io.Writer) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.logFile = logFile
+}
+
+func (l *Logger) SetEventStream(events *EventStream) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.events = events
 }
 
 func (l *Logger) clear() {
@@ -27,28 +119,207 @@ func (l *Logger) clear() {
 }
 
 func (l *Logger) Step(message string, a ...interface{}) {
-	l.clear()
-	fmt.Fprintf(l.writer, "step: %s\n", fmt.Sprintf(message, a...))
-	l.newline = true
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stepMessage := fmt.Sprintf(message, a...)
+	l.log(InfoLogLevel, "step", stepMessage)
+
+	if l.events != nil {
+		l.events.Step(stepMessage)
+	}
 }
 
 func (l *Logger) Dot() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.json || l.level > InfoLogLevel {
+		return
+	}
+
 	l.writer.Write([]byte("\u2022"))
 	l.newline = false
 }
 
 func (l *Logger) Printf(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	l.clear()
 	fmt.Fprintf(l.writer, "%s", fmt.Sprintf(message, a...))
 }
 
 func (l *Logger) Println(message string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	l.clear()
 	fmt.Fprintf(l.writer, "%s\n", message)
 }
 
 func (l *Logger) Prompt(message string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	l.clear()
 	fmt.Fprintf(l.writer, "%s (y/N): ", message)
 	l.newline = true
 }
+
+func (l *Logger) Debug(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.log(DebugLogLevel, "", fmt.Sprintf(message, a...))
+}
+
+func (l *Logger) Info(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.log(InfoLogLevel, "", fmt.Sprintf(message, a...))
+}
+
+func (l *Logger) Warn(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	warning := fmt.Sprintf(message, a...)
+	l.log(WarnLogLevel, "", warning)
+
+	if l.events != nil {
+		l.events.Warning(warning)
+	}
+}
+
+func (l *Logger) Error(message string, a ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.log(ErrorLogLevel, "", fmt.Sprintf(message, a...))
+}
+
+func (l *Logger) SubprocessWriter(name string) io.Writer {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	writer := &subprocessWriter{
+		mutex:  &sync.Mutex{},
+		name:   name,
+		logger: l,
+		buffer: bytes.NewBuffer([]byte{}),
+	}
+	l.subprocessWriters = append(l.subprocessWriters, writer)
+
+	return writer
+}
+
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	subprocessWriters := l.subprocessWriters
+	l.mutex.Unlock()
+
+	for _, writer := range subprocessWriters {
+		writer.flush()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if closer, ok := l.logFile.(io.Closer); ok {
+		l.logFile = ioutil.Discard
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (l *Logger) log(level LogLevel, entryType, message string) {
+	now := timeNow()
+	l.writeLogFile(now, fmt.Sprintf("[%s] %s", level, prefixMessage(entryType, message)))
+
+	if level < l.level {
+		return
+	}
+
+	if l.json {
+		l.clear()
+		contents, err := json.Marshal(logEntry{
+			Time:    now.Format(logTimeFormat),
+			Level:   level.String(),
+			Type:    entryType,
+			Message: message,
+		})
+		if err != nil {
+			// not tested
+			return
+		}
+		fmt.Fprintf(l.writer, "%s\n", contents)
+		return
+	}
+
+	l.clear()
+	switch {
+	case entryType != "":
+		fmt.Fprintf(l.writer, "%s\n", prefixMessage(entryType, message))
+	case level == InfoLogLevel:
+		fmt.Fprintf(l.writer, "%s\n", message)
+	default:
+		fmt.Fprintf(l.writer, "%s: %s\n", level, message)
+	}
+	l.newline = true
+}
+
+func (l *Logger) writeLogFile(now time.Time, line string) {
+	fmt.Fprintf(l.logFile, "%s %s\n", now.Format(logTimeFormat), line)
+}
+
+func prefixMessage(prefix, message string) string {
+	if prefix == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s: %s", prefix, message)
+}
+
+type subprocessWriter struct {
+	mutex  *sync.Mutex
+	name   string
+	logger *Logger
+	buffer *bytes.Buffer
+}
+
+func (w *subprocessWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(p)
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			w.buffer.WriteString(line)
+			break
+		}
+		w.writeLine(strings.TrimSuffix(line, "\n"))
+	}
+
+	return len(p), nil
+}
+
+func (w *subprocessWriter) flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.buffer.Len() > 0 {
+		w.writeLine(w.buffer.String())
+		w.buffer.Reset()
+	}
+}
+
+func (w *subprocessWriter) writeLine(line string) {
+	w.logger.mutex.Lock()
+	defer w.logger.mutex.Unlock()
+
+	w.logger.writeLogFile(timeNow(), fmt.Sprintf("[%s] %s", w.name, line))
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"math/rand"
+	"sync"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/application"
 
@@ -20,6 +22,14 @@ var _ = Describe("Logger", func() {
 	BeforeEach(func() {
 		buffer = bytes.NewBuffer([]byte{})
 		logger = application.NewLogger(buffer)
+
+		application.SetTimeNow(func() time.Time {
+			return time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+		})
+	})
+
+	AfterEach(func() {
+		application.ResetTimeNow()
 	})
 
 	Describe("Step", func() {
@@ -92,4 +102,147 @@ SUCCESS!
 `))
 		})
 	})
+
+	Describe("levels", func() {
+		It("prints debug messages only when the level is debug", func() {
+			logger.Debug("hidden %d", 1)
+			logger.SetLevel(application.DebugLogLevel)
+			logger.Debug("shown %d", 2)
+
+			Expect(buffer.String()).To(Equal("debug: shown 2\n"))
+		})
+
+		It("prints warnings and errors with a prefix and info without", func() {
+			logger.Info("some info")
+			logger.Warn("some warning")
+			logger.Error("some error")
+
+			Expect(buffer.String()).To(Equal("some info\nwarn: some warning\nerror: some error\n"))
+		})
+
+		It("suppresses steps and dots when the level is above info", func() {
+			logger.SetLevel(application.WarnLogLevel)
+
+			logger.Step("creating keypair")
+			logger.Dot()
+			logger.Info("some info")
+			logger.Warn("some warning")
+
+			Expect(buffer.String()).To(Equal("warn: some warning\n"))
+		})
+
+		It("ends a line of dots before printing a message", func() {
+			logger.Dot()
+			logger.Warn("some warning")
+
+			Expect(buffer.String()).To(Equal("\u2022\nwarn: some warning\n"))
+		})
+	})
+
+	Describe("ParseLogLevel", func() {
+		It("returns the level for the given name", func() {
+			level, err := application.ParseLogLevel("warn")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level).To(Equal(application.WarnLogLevel))
+			Expect(level.String()).To(Equal("warn"))
+		})
+
+		It("returns an error when the name is invalid", func() {
+			_, err := application.ParseLogLevel("loud")
+			Expect(err).To(MatchError(`"loud" is an invalid log level, supported values are: [debug, info, warn, error]`))
+		})
+	})
+
+	Describe("json format", func() {
+		BeforeEach(func() {
+			logger.SetFormat(application.JSONLogFormat)
+		})
+
+		It("prints steps and messages as json lines", func() {
+			logger.Step("creating keypair")
+			logger.Warn("some warning")
+
+			Expect(buffer.String()).To(Equal(`{"time":"2017-06-01T12:30:00.000Z","level":"info","type":"step","message":"creating keypair"}
+{"time":"2017-06-01T12:30:00.000Z","level":"warn","message":"some warning"}
+`))
+		})
+
+		It("does not print dots", func() {
+			logger.Dot()
+			logger.Dot()
+
+			Expect(buffer.String()).To(BeEmpty())
+		})
+
+		It("prints println output unchanged", func() {
+			logger.Println("some-output")
+
+			Expect(buffer.String()).To(Equal("some-output\n"))
+		})
+	})
+
+	Describe("log file", func() {
+		var logFile *bytes.Buffer
+
+		BeforeEach(func() {
+			logFile = bytes.NewBuffer([]byte{})
+			logger.SetLogFile(logFile)
+		})
+
+		It("writes every leveled message with a timestamp regardless of the level", func() {
+			logger.SetLevel(application.ErrorLogLevel)
+
+			logger.Step("creating keypair")
+			logger.Debug("some debug")
+			logger.Error("some error")
+
+			Expect(logFile.String()).To(Equal(`2017-06-01T12:30:00.000Z [info] step: creating keypair
+2017-06-01T12:30:00.000Z [debug] some debug
+2017-06-01T12:30:00.000Z [error] some error
+`))
+			Expect(buffer.String()).To(Equal("error: some error\n"))
+		})
+
+		It("does not write println output", func() {
+			logger.Println("some-secret")
+
+			Expect(logFile.String()).To(BeEmpty())
+		})
+
+		Describe("SubprocessWriter", func() {
+			It("writes each line of subprocess output with a timestamp and name", func() {
+				writer := logger.SubprocessWriter("terraform")
+
+				fmt.Fprint(writer, "first line\nsecond ")
+				fmt.Fprint(writer, "line\nthird")
+
+				Expect(logFile.String()).To(Equal(`2017-06-01T12:30:00.000Z [terraform] first line
+2017-06-01T12:30:00.000Z [terraform] second line
+`))
+
+				logger.Close()
+
+				Expect(logFile.String()).To(HaveSuffix("2017-06-01T12:30:00.000Z [terraform] third\n"))
+			})
+		})
+
+		It("can be written to from multiple goroutines", func() {
+			writer := logger.SubprocessWriter("bosh")
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					logger.Step("step %d", i)
+					logger.Dot()
+					fmt.Fprintf(writer, "line %d\n", i)
+				}(i)
+			}
+			wg.Wait()
+
+			Expect(logFile.String()).To(ContainSubstring("[info] step: step 9\n"))
+			Expect(logFile.String()).To(ContainSubstring("[bosh] line 9\n"))
+		})
+	})
 })
@@ -4,7 +4,9 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
+	"github.com/cloudfoundry/bosh-bootloader/aws/elb"
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/aws/route53"
 )
 
 type ClientProvider struct {
@@ -12,6 +14,8 @@ type ClientProvider struct {
 	ec2Client            ec2.Client
 	cloudformationClient cloudformation.Client
 	iamClient            iam.Client
+	route53Client        route53.Client
+	elbClient            elb.Client
 }
 
 func (c *ClientProvider) SetConfig(config aws.Config) {
@@ -19,6 +23,8 @@ func (c *ClientProvider) SetConfig(config aws.Config) {
 	c.ec2Client = ec2.NewClient(config)
 	c.cloudformationClient = cloudformation.NewClient(config)
 	c.iamClient = iam.NewClient(config)
+	c.route53Client = route53.NewClient(config)
+	c.elbClient = elb.NewClient(config)
 }
 
 func (c *ClientProvider) GetEC2Client() ec2.Client {
@@ -32,3 +38,11 @@ func (c *ClientProvider) GetCloudFormationClient() cloudformation.Client {
 func (c *ClientProvider) GetIAMClient() iam.Client {
 	return c.iamClient
 }
+
+func (c *ClientProvider) GetRoute53Client() route53.Client {
+	return c.route53Client
+}
+
+func (c *ClientProvider) GetELBClient() elb.Client {
+	return c.elbClient
+}
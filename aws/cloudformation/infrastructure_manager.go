@@ -2,10 +2,12 @@ package cloudformation
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation/templates"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
 )
 
 const bblTagKey = "bbl-env-id"
@@ -109,6 +111,85 @@ func (m InfrastructureManager) Describe(stackName string) (Stack, error) {
 	return m.stackManager.Describe(stackName)
 }
 
+// Drift compares the stack with the template bbl would apply to it.
+// CloudFormation only reports the status and outputs of a stack, so drift in
+// the underlying resources shows up as missing or extra outputs.
+func (m InfrastructureManager) Drift(keyPairName string, azs []string, stackName, boshAZ, lbType,
+	lbCertificateARN, envID string) ([]drift.Resource, error) {
+
+	stack, err := m.stackManager.Describe(stackName)
+	switch err {
+	case nil:
+	case StackNotFound:
+		return []drift.Resource{{Name: stackName, Status: drift.Missing}}, nil
+	default:
+		return nil, err
+	}
+
+	iamUserName, err := m.stackManager.GetPhysicalIDForResource(stackName, "BOSHUser")
+	if err != nil {
+		return nil, err
+	}
+
+	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ)
+
+	var attributes []drift.Attribute
+	if stack.Status != "CREATE_COMPLETE" && stack.Status != "UPDATE_COMPLETE" {
+		attributes = append(attributes, drift.Attribute{
+			Name:     "status",
+			Status:   drift.Changed,
+			Expected: "UPDATE_COMPLETE",
+			Actual:   stack.Status,
+		})
+	}
+
+	for _, name := range sortedOutputNames(template.Outputs) {
+		value, ok := stack.Outputs[name]
+		if !ok {
+			attributes = append(attributes, drift.Attribute{
+				Name:   "outputs." + name,
+				Status: drift.Missing,
+			})
+			continue
+		}
+
+		if expected, ok := template.Outputs[name].Value.(string); ok && expected != value {
+			attributes = append(attributes, drift.Attribute{
+				Name:     "outputs." + name,
+				Status:   drift.Changed,
+				Expected: expected,
+				Actual:   value,
+			})
+		}
+	}
+
+	var extraOutputs []string
+	for name := range stack.Outputs {
+		if _, ok := template.Outputs[name]; !ok {
+			extraOutputs = append(extraOutputs, name)
+		}
+	}
+	sort.Strings(extraOutputs)
+
+	for _, name := range extraOutputs {
+		attributes = append(attributes, drift.Attribute{
+			Name:   "outputs." + name,
+			Status: drift.Extra,
+			Actual: stack.Outputs[name],
+		})
+	}
+
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	return []drift.Resource{{
+		Name:       stackName,
+		Status:     drift.Changed,
+		Attributes: attributes,
+	}}, nil
+}
+
 func (m InfrastructureManager) Delete(stackName string) error {
 	err := m.stackManager.Delete(stackName)
 	if err != nil {
@@ -123,6 +204,43 @@ func (m InfrastructureManager) Delete(stackName string) error {
 	return nil
 }
 
+// Detach deletes the stack but keeps every resource in it, so that the
+// resources can be managed by something else.
+func (m InfrastructureManager) Detach(keyPairName string, azs []string, stackName, boshAZ, lbType,
+	lbCertificateARN, envID string) error {
+
+	iamUserName, err := m.stackManager.GetPhysicalIDForResource(stackName, "BOSHUser")
+	if err != nil {
+		return err
+	}
+
+	template := m.templateBuilder.Build(keyPairName, azs, lbType, lbCertificateARN, iamUserName, envID, boshAZ)
+	for name, resource := range template.Resources {
+		resource.DeletionPolicy = "Retain"
+		template.Resources[name] = resource
+	}
+
+	if err := m.stackManager.Update(stackName, template, Tags{{Key: bblTagKey, Value: envID}}); err != nil {
+		return err
+	}
+
+	if err := m.stackManager.WaitForCompletion(stackName, 15*time.Second, "retaining cloudformation resources"); err != nil {
+		return err
+	}
+
+	return m.Delete(stackName)
+}
+
+func sortedOutputNames(outputs map[string]templates.Output) []string {
+	var names []string
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func generateIAMUserName(envID string) string {
 	return fmt.Sprintf("bosh-iam-user-%s", strings.Replace(envID, ":", "-", -1))
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation/templates"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 
 	. "github.com/onsi/ginkgo"
@@ -241,6 +242,75 @@ var _ = Describe("InfrastructureManager", func() {
 		})
 	})
 
+	Describe("Detach", func() {
+		BeforeEach(func() {
+			builder.BuildCall.Returns.Template = templates.Template{
+				Resources: map[string]templates.Resource{
+					"VPC":     {Type: "AWS::EC2::VPC"},
+					"BOSHEIP": {Type: "AWS::EC2::EIP"},
+				},
+			}
+			stackManager.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID = "some-bosh-user-id"
+		})
+
+		It("retains every resource in the stack and then deletes the stack", func() {
+			err := infrastructureManager.Detach("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "cf", "some-lb-certificate-arn", "some-env-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.BuildCall.Receives.KeyPairName).To(Equal("some-key-pair-name"))
+			Expect(builder.BuildCall.Receives.AZs).To(Equal(azs))
+			Expect(builder.BuildCall.Receives.LBType).To(Equal("cf"))
+			Expect(builder.BuildCall.Receives.LBCertificateARN).To(Equal("some-lb-certificate-arn"))
+			Expect(builder.BuildCall.Receives.IAMUserName).To(Equal("some-bosh-user-id"))
+			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id"))
+			Expect(builder.BuildCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+
+			Expect(stackManager.UpdateCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(stackManager.UpdateCall.Receives.Template).To(Equal(templates.Template{
+				Resources: map[string]templates.Resource{
+					"VPC":     {Type: "AWS::EC2::VPC", DeletionPolicy: "Retain"},
+					"BOSHEIP": {Type: "AWS::EC2::EIP", DeletionPolicy: "Retain"},
+				},
+			}))
+			Expect(stackManager.UpdateCall.Receives.Tags).To(Equal(cloudformation.Tags{{Key: "bbl-env-id", Value: "some-env-id"}}))
+
+			Expect(stackManager.DeleteCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(stackManager.WaitForCompletionCall.Receives.Action).To(Equal("deleting cloudformation stack"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when it cannot get physical id for BOSHUser", func() {
+				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to get physical id for resource")
+
+				err := infrastructureManager.Detach("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("failed to get physical id for resource"))
+			})
+
+			It("does not delete the stack when the update fails", func() {
+				stackManager.UpdateCall.Returns.Error = errors.New("stack update call failed")
+
+				err := infrastructureManager.Detach("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("stack update call failed"))
+				Expect(stackManager.DeleteCall.Receives.StackName).To(BeEmpty())
+			})
+
+			It("does not delete the stack when the update does not complete", func() {
+				stackManager.WaitForCompletionCall.Returns.Error = errors.New("failed to wait for completion")
+
+				err := infrastructureManager.Detach("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("failed to wait for completion"))
+				Expect(stackManager.DeleteCall.Receives.StackName).To(BeEmpty())
+			})
+
+			It("returns an error when the stack fails to delete", func() {
+				stackManager.DeleteCall.Returns.Error = errors.New("failed to delete stack")
+
+				err := infrastructureManager.Detach("some-key-pair-name", azs, "some-stack-name", "some-bosh-az", "cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("failed to delete stack"))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		It("deletes the underlying infrastructure", func() {
 			err := infrastructureManager.Delete("some-stack-name")
@@ -302,4 +372,105 @@ var _ = Describe("InfrastructureManager", func() {
 			Expect(stackManager.DescribeCall.Receives.StackName).To(Equal("some-stack-name"))
 		})
 	})
+
+	Describe("Drift", func() {
+		BeforeEach(func() {
+			stackManager.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID = "some-iam-user-name"
+			builder.BuildCall.Returns.Template = templates.Template{
+				Outputs: map[string]templates.Output{
+					"VPCID":          {Value: templates.Ref{Ref: "VPC"}},
+					"BOSHEIP":        {Value: templates.Ref{Ref: "BOSHEIP"}},
+					"LBType":         {Value: "cf"},
+					"CFRouterLBName": {Value: templates.Ref{Ref: "CFRouterLoadBalancer"}},
+				},
+			}
+			stackManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+				Name:   "some-stack-name",
+				Status: "UPDATE_COMPLETE",
+				Outputs: map[string]string{
+					"VPCID":          "some-vpc-id",
+					"BOSHEIP":        "some-eip",
+					"LBType":         "cf",
+					"CFRouterLBName": "some-router-lb",
+				},
+			}
+		})
+
+		It("builds the template with the iam user of the stack", func() {
+			_, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+				"cf", "some-lb-certificate-arn", "some-env-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stackManager.GetPhysicalIDForResourceCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(stackManager.GetPhysicalIDForResourceCall.Receives.LogicalResourceID).To(Equal("BOSHUser"))
+			Expect(builder.BuildCall.Receives.KeyPairName).To(Equal("some-key-pair-name"))
+			Expect(builder.BuildCall.Receives.AZs).To(Equal(azs))
+			Expect(builder.BuildCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+			Expect(builder.BuildCall.Receives.LBType).To(Equal("cf"))
+			Expect(builder.BuildCall.Receives.LBCertificateARN).To(Equal("some-lb-certificate-arn"))
+			Expect(builder.BuildCall.Receives.IAMUserName).To(Equal("some-iam-user-name"))
+			Expect(builder.BuildCall.Receives.EnvID).To(Equal("some-env-id"))
+		})
+
+		It("returns no drift when the stack matches the template", func() {
+			resources, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+				"cf", "some-lb-certificate-arn", "some-env-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(BeEmpty())
+		})
+
+		It("returns the status and outputs that differ from the template", func() {
+			stackManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+				Name:   "some-stack-name",
+				Status: "UPDATE_ROLLBACK_COMPLETE",
+				Outputs: map[string]string{
+					"VPCID":           "some-vpc-id",
+					"LBType":          "concourse",
+					"CFRouterLBName":  "some-router-lb",
+					"ConcourseLBName": "some-concourse-lb",
+				},
+			}
+
+			resources, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+				"cf", "some-lb-certificate-arn", "some-env-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]drift.Resource{{
+				Name:   "some-stack-name",
+				Status: drift.Changed,
+				Attributes: []drift.Attribute{
+					{Name: "status", Status: drift.Changed, Expected: "UPDATE_COMPLETE", Actual: "UPDATE_ROLLBACK_COMPLETE"},
+					{Name: "outputs.BOSHEIP", Status: drift.Missing},
+					{Name: "outputs.LBType", Status: drift.Changed, Expected: "cf", Actual: "concourse"},
+					{Name: "outputs.ConcourseLBName", Status: drift.Extra, Actual: "some-concourse-lb"},
+				},
+			}}))
+		})
+
+		It("returns the stack as missing when it does not exist", func() {
+			stackManager.DescribeCall.Returns.Error = cloudformation.StackNotFound
+
+			resources, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+				"cf", "some-lb-certificate-arn", "some-env-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal([]drift.Resource{{Name: "some-stack-name", Status: drift.Missing}}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the stack cannot be described", func() {
+				stackManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
+
+				_, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+					"cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("failed to describe stack"))
+			})
+
+			It("returns an error when the iam user cannot be found", func() {
+				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to get iam user")
+
+				_, err := infrastructureManager.Drift("some-key-pair-name", azs, "some-stack-name", "some-bosh-az",
+					"cf", "some-lb-certificate-arn", "some-env-id")
+				Expect(err).To(MatchError("failed to get iam user"))
+			})
+		})
+	})
 })
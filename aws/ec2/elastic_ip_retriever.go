@@ -0,0 +1,37 @@
+package ec2
+
+import (
+	"fmt"
+
+	goaws "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+)
+
+type ElasticIPRetriever struct {
+	ec2ClientProvider ec2ClientProvider
+}
+
+func NewElasticIPRetriever(ec2ClientProvider ec2ClientProvider) ElasticIPRetriever {
+	return ElasticIPRetriever{
+		ec2ClientProvider: ec2ClientProvider,
+	}
+}
+
+// AllocationID returns the allocation id of the vpc elastic ip with the given
+// public ip. Cloudformation refers to elastic ips by their public ip.
+func (r ElasticIPRetriever) AllocationID(publicIP string) (string, error) {
+	output, err := r.ec2ClientProvider.GetEC2Client().DescribeAddresses(&awsec2.DescribeAddressesInput{
+		PublicIps: []*string{goaws.String(publicIP)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range output.Addresses {
+		if goaws.StringValue(address.AllocationId) != "" {
+			return goaws.StringValue(address.AllocationId), nil
+		}
+	}
+
+	return "", fmt.Errorf("no vpc elastic ip found for %s", publicIP)
+}
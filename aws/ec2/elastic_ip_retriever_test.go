@@ -0,0 +1,66 @@
+package ec2_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ElasticIPRetriever", func() {
+	var (
+		retriever         ec2.ElasticIPRetriever
+		ec2Client         *fakes.EC2Client
+		awsClientProvider *fakes.AWSClientProvider
+	)
+
+	BeforeEach(func() {
+		awsClientProvider = &fakes.AWSClientProvider{}
+		ec2Client = &fakes.EC2Client{}
+		awsClientProvider.GetEC2ClientCall.Returns.EC2Client = ec2Client
+		retriever = ec2.NewElasticIPRetriever(awsClientProvider)
+	})
+
+	Describe("AllocationID", func() {
+		It("returns the allocation id of the elastic ip", func() {
+			ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{
+				Addresses: []*awsec2.Address{{
+					PublicIp:     aws.String("52.0.0.1"),
+					AllocationId: aws.String("eipalloc-12345"),
+				}},
+			}
+
+			allocationID, err := retriever.AllocationID("52.0.0.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allocationID).To(Equal("eipalloc-12345"))
+
+			Expect(ec2Client.DescribeAddressesCall.Receives.Input).To(Equal(&awsec2.DescribeAddressesInput{
+				PublicIps: []*string{aws.String("52.0.0.1")},
+			}))
+		})
+
+		It("returns an error when the elastic ip is not in a vpc", func() {
+			ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{
+				Addresses: []*awsec2.Address{{
+					PublicIp: aws.String("52.0.0.1"),
+				}},
+			}
+
+			_, err := retriever.AllocationID("52.0.0.1")
+			Expect(err).To(MatchError("no vpc elastic ip found for 52.0.0.1"))
+		})
+
+		It("returns an error when the addresses cannot be described", func() {
+			ec2Client.DescribeAddressesCall.Returns.Error = errors.New("failed to describe addresses")
+
+			_, err := retriever.AllocationID("52.0.0.1")
+			Expect(err).To(MatchError("failed to describe addresses"))
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package ec2
+
+import (
+	"fmt"
+
+	goaws "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+)
+
+type RouteTableAssociationRetriever struct {
+	ec2ClientProvider ec2ClientProvider
+}
+
+func NewRouteTableAssociationRetriever(ec2ClientProvider ec2ClientProvider) RouteTableAssociationRetriever {
+	return RouteTableAssociationRetriever{
+		ec2ClientProvider: ec2ClientProvider,
+	}
+}
+
+// AssociationID returns the id of the association between the route table
+// and the subnet. Cloudformation does not report it.
+func (r RouteTableAssociationRetriever) AssociationID(routeTableID, subnetID string) (string, error) {
+	output, err := r.ec2ClientProvider.GetEC2Client().DescribeRouteTables(&awsec2.DescribeRouteTablesInput{
+		RouteTableIds: []*string{goaws.String(routeTableID)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, routeTable := range output.RouteTables {
+		for _, association := range routeTable.Associations {
+			if goaws.StringValue(association.SubnetId) == subnetID {
+				return goaws.StringValue(association.RouteTableAssociationId), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("subnet %s is not associated with route table %s", subnetID, routeTableID)
+}
@@ -0,0 +1,65 @@
+package ec2_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RouteTableAssociationRetriever", func() {
+	var (
+		retriever         ec2.RouteTableAssociationRetriever
+		ec2Client         *fakes.EC2Client
+		awsClientProvider *fakes.AWSClientProvider
+	)
+
+	BeforeEach(func() {
+		awsClientProvider = &fakes.AWSClientProvider{}
+		ec2Client = &fakes.EC2Client{}
+		awsClientProvider.GetEC2ClientCall.Returns.EC2Client = ec2Client
+		retriever = ec2.NewRouteTableAssociationRetriever(awsClientProvider)
+	})
+
+	Describe("AssociationID", func() {
+		BeforeEach(func() {
+			ec2Client.DescribeRouteTablesCall.Returns.Output = &awsec2.DescribeRouteTablesOutput{
+				RouteTables: []*awsec2.RouteTable{{
+					RouteTableId: aws.String("rtb-12345"),
+					Associations: []*awsec2.RouteTableAssociation{
+						{SubnetId: aws.String("subnet-1"), RouteTableAssociationId: aws.String("rtbassoc-1")},
+						{SubnetId: aws.String("subnet-2"), RouteTableAssociationId: aws.String("rtbassoc-2")},
+					},
+				}},
+			}
+		})
+
+		It("returns the id of the association with the subnet", func() {
+			associationID, err := retriever.AssociationID("rtb-12345", "subnet-2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(associationID).To(Equal("rtbassoc-2"))
+
+			Expect(ec2Client.DescribeRouteTablesCall.Receives.Input).To(Equal(&awsec2.DescribeRouteTablesInput{
+				RouteTableIds: []*string{aws.String("rtb-12345")},
+			}))
+		})
+
+		It("returns an error when the subnet is not associated with the route table", func() {
+			_, err := retriever.AssociationID("rtb-12345", "subnet-3")
+			Expect(err).To(MatchError("subnet subnet-3 is not associated with route table rtb-12345"))
+		})
+
+		It("returns an error when the route tables cannot be described", func() {
+			ec2Client.DescribeRouteTablesCall.Returns.Error = errors.New("failed to describe route tables")
+
+			_, err := retriever.AssociationID("rtb-12345", "subnet-2")
+			Expect(err).To(MatchError("failed to describe route tables"))
+		})
+	})
+})
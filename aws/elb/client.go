@@ -0,0 +1,17 @@
+package elb
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+)
+
+type Client interface {
+	DescribeAccountLimits(*awselb.DescribeAccountLimitsInput) (*awselb.DescribeAccountLimitsOutput, error)
+	DescribeLoadBalancers(*awselb.DescribeLoadBalancersInput) (*awselb.DescribeLoadBalancersOutput, error)
+}
+
+func NewClient(config aws.Config) Client {
+	return awselb.New(session.New(config.ClientConfig()))
+}
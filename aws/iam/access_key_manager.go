@@ -0,0 +1,59 @@
+package iam
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+)
+
+type AccessKeyManager struct {
+	iamClientProvider iamClientProvider
+}
+
+func NewAccessKeyManager(iamClientProvider iamClientProvider) AccessKeyManager {
+	return AccessKeyManager{
+		iamClientProvider: iamClientProvider,
+	}
+}
+
+// Create creates a new access key for the user that owns the given one. A
+// user can have at most two access keys, so the given key has to be deleted
+// before Create is called again.
+func (m AccessKeyManager) Create(accessKeyID string) (string, string, error) {
+	userName, err := m.userName(accessKeyID)
+	if err != nil {
+		return "", "", err
+	}
+
+	output, err := m.iamClientProvider.GetIAMClient().CreateAccessKey(&awsiam.CreateAccessKeyInput{
+		UserName: userName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return aws.StringValue(output.AccessKey.AccessKeyId), aws.StringValue(output.AccessKey.SecretAccessKey), nil
+}
+
+func (m AccessKeyManager) Delete(accessKeyID string) error {
+	userName, err := m.userName(accessKeyID)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.iamClientProvider.GetIAMClient().DeleteAccessKey(&awsiam.DeleteAccessKeyInput{
+		AccessKeyId: aws.String(accessKeyID),
+		UserName:    userName,
+	})
+	return err
+}
+
+func (m AccessKeyManager) userName(accessKeyID string) (*string, error) {
+	output, err := m.iamClientProvider.GetIAMClient().GetAccessKeyLastUsed(&awsiam.GetAccessKeyLastUsedInput{
+		AccessKeyId: aws.String(accessKeyID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.UserName, nil
+}
@@ -0,0 +1,99 @@
+package iam_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsiam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AccessKeyManager", func() {
+	var (
+		iamClient         *fakes.IAMClient
+		awsClientProvider *fakes.AWSClientProvider
+		manager           iam.AccessKeyManager
+	)
+
+	BeforeEach(func() {
+		iamClient = &fakes.IAMClient{}
+		awsClientProvider = &fakes.AWSClientProvider{}
+		awsClientProvider.GetIAMClientCall.Returns.IAMClient = iamClient
+
+		iamClient.GetAccessKeyLastUsedCall.Returns.Output = &awsiam.GetAccessKeyLastUsedOutput{
+			UserName: aws.String("some-user"),
+		}
+
+		manager = iam.NewAccessKeyManager(awsClientProvider)
+	})
+
+	Describe("Create", func() {
+		BeforeEach(func() {
+			iamClient.CreateAccessKeyCall.Returns.Output = &awsiam.CreateAccessKeyOutput{
+				AccessKey: &awsiam.AccessKey{
+					AccessKeyId:     aws.String("some-new-access-key-id"),
+					SecretAccessKey: aws.String("some-new-secret-access-key"),
+				},
+			}
+		})
+
+		It("creates an access key for the user that owns the given one", func() {
+			accessKeyID, secretAccessKey, err := manager.Create("some-access-key-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamClient.GetAccessKeyLastUsedCall.Receives.Input.AccessKeyId).To(Equal(aws.String("some-access-key-id")))
+			Expect(iamClient.CreateAccessKeyCall.Receives.Input.UserName).To(Equal(aws.String("some-user")))
+
+			Expect(accessKeyID).To(Equal("some-new-access-key-id"))
+			Expect(secretAccessKey).To(Equal("some-new-secret-access-key"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the user cannot be found", func() {
+				iamClient.GetAccessKeyLastUsedCall.Returns.Error = errors.New("failed to get user")
+
+				_, _, err := manager.Create("some-access-key-id")
+				Expect(err).To(MatchError("failed to get user"))
+			})
+
+			It("returns an error when the access key cannot be created", func() {
+				iamClient.CreateAccessKeyCall.Returns.Error = errors.New("failed to create access key")
+
+				_, _, err := manager.Create("some-access-key-id")
+				Expect(err).To(MatchError("failed to create access key"))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the access key", func() {
+			iamClient.DeleteAccessKeyCall.Returns.Output = &awsiam.DeleteAccessKeyOutput{}
+
+			err := manager.Delete("some-access-key-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamClient.DeleteAccessKeyCall.Receives.Input.AccessKeyId).To(Equal(aws.String("some-access-key-id")))
+			Expect(iamClient.DeleteAccessKeyCall.Receives.Input.UserName).To(Equal(aws.String("some-user")))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the user cannot be found", func() {
+				iamClient.GetAccessKeyLastUsedCall.Returns.Error = errors.New("failed to get user")
+
+				err := manager.Delete("some-access-key-id")
+				Expect(err).To(MatchError("failed to get user"))
+			})
+
+			It("returns an error when the access key cannot be deleted", func() {
+				iamClient.DeleteAccessKeyCall.Returns.Error = errors.New("failed to delete access key")
+
+				err := manager.Delete("some-access-key-id")
+				Expect(err).To(MatchError("failed to delete access key"))
+			})
+		})
+	})
+})
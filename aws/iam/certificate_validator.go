@@ -1,6 +1,9 @@
 package iam
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -10,25 +13,41 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/multierror"
 )
 
 var readAll func(r io.Reader) ([]byte, error) = ioutil.ReadAll
 var stat func(name string) (os.FileInfo, error) = os.Stat
+var now func() time.Time = time.Now
 
-type CertificateValidator struct{}
+const expiryWarningPeriod = 30 * 24 * time.Hour
 
-func NewCertificateValidator() CertificateValidator {
-	return CertificateValidator{}
+type CertificateValidator struct {
+	logger warnLogger
 }
 
-func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath string) error {
+type warnLogger interface {
+	Warn(string, ...interface{})
+}
+
+func NewCertificateValidator(logger warnLogger) CertificateValidator {
+	return CertificateValidator{
+		logger: logger,
+	}
+}
+
+// Validate checks that the certificate, key and chain can be used by an AWS or
+// GCP load balancer. When a domain is given the certificate must cover every
+// host under it.
+func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath, domain string) error {
 	var err error
 	var certificateData []byte
 	var chainData []byte
 	var certificate *x509.Certificate
-	var privateKey *rsa.PrivateKey
+	var privateKey crypto.PrivateKey
 
 	validateErrors := multierror.NewMultiError(command)
 
@@ -54,7 +73,7 @@ func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath str
 	if err != nil {
 		validateErrors.Add(err)
 	} else {
-		privateKey = tlsCertificateStruct.PrivateKey.(*rsa.PrivateKey)
+		privateKey = tlsCertificateStruct.PrivateKey
 	}
 	if certificate == nil {
 		loadKeyPairError := err
@@ -84,6 +103,16 @@ func (c CertificateValidator) Validate(command, certPath, keyPath, chainPath str
 		}
 	}
 
+	if domain != "" && certificate != nil {
+		if err := c.validateCertAndDomain(certificate, domain); err != nil {
+			validateErrors.Add(err)
+		}
+	}
+
+	if certificate != nil {
+		c.warnAboutExpiry(certificate)
+	}
+
 	if validateErrors.Length() > 0 {
 		return validateErrors
 	}
@@ -125,18 +154,55 @@ func (CertificateValidator) validateFileAndFormat(propertyName string, flagName
 	return fileData, nil
 }
 
-func (c CertificateValidator) validateCertAndKey(certificate *x509.Certificate, privateKey *rsa.PrivateKey) error {
-	publicKey := certificate.PublicKey.(*rsa.PublicKey)
-	if privateKey.PublicKey.N.Cmp(publicKey.N) != 0 || privateKey.PublicKey.E != publicKey.E {
-		return errors.New("certificate and key mismatch")
+func (c CertificateValidator) validateCertAndKey(certificate *x509.Certificate, privateKey crypto.PrivateKey) error {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		if !ok || key.PublicKey.N.Cmp(publicKey.N) != 0 || key.PublicKey.E != publicKey.E {
+			return errors.New("certificate and key mismatch")
+		}
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() && key.Curve != elliptic.P384() {
+			return fmt.Errorf("ECDSA keys must use the P-256 or P-384 curve, got %s", key.Curve.Params().Name)
+		}
+
+		publicKey, ok := certificate.PublicKey.(*ecdsa.PublicKey)
+		if !ok || key.Curve != publicKey.Curve || key.X.Cmp(publicKey.X) != 0 || key.Y.Cmp(publicKey.Y) != 0 {
+			return errors.New("certificate and key mismatch")
+		}
+	default:
+		return fmt.Errorf("unsupported private key type %T, load balancers accept RSA and ECDSA P-256 or P-384 keys", privateKey)
 	}
 
 	return nil
 }
 
+func (CertificateValidator) validateCertAndDomain(certificate *x509.Certificate, domain string) error {
+	wildcard := "*." + strings.ToLower(domain)
+	for _, name := range certificate.DNSNames {
+		if strings.ToLower(name) == wildcard {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("certificate does not cover %s, its subject alternative names are [%s]", wildcard, strings.Join(certificate.DNSNames, ", "))
+}
+
+func (c CertificateValidator) warnAboutExpiry(certificate *x509.Certificate) {
+	expiry := certificate.NotAfter.UTC().Format("2006-01-02")
+
+	switch {
+	case now().After(certificate.NotAfter):
+		c.logger.Warn("certificate expired on %s", expiry)
+	case now().Add(expiryWarningPeriod).After(certificate.NotAfter):
+		c.logger.Warn("certificate expires on %s", expiry)
+	}
+}
+
 func (CertificateValidator) validateCertAndChain(certificate *x509.Certificate, certPool *x509.CertPool) error {
 	opts := x509.VerifyOptions{
-		Roots: certPool,
+		Roots:       certPool,
+		CurrentTime: now(),
 	}
 
 	if _, err := certificate.Verify(opts); err != nil {
@@ -6,8 +6,10 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
 	"github.com/cloudfoundry/multierror"
 
@@ -18,6 +20,7 @@ import (
 var _ = Describe("CertificateValidator", func() {
 	Describe("Validate", func() {
 		var (
+			logger               *fakes.Logger
 			certificateValidator iam.CertificateValidator
 			certFilePath         string
 			keyFilePath          string
@@ -32,7 +35,8 @@ var _ = Describe("CertificateValidator", func() {
 
 		BeforeEach(func() {
 			var err error
-			certificateValidator = iam.NewCertificateValidator()
+			logger = &fakes.Logger{}
+			certificateValidator = iam.NewCertificateValidator(logger)
 			chainFilePath, err = testhelpers.WriteContentsToTempFile(testhelpers.BBL_CHAIN)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -62,25 +66,26 @@ var _ = Describe("CertificateValidator", func() {
 
 			iam.ResetStat()
 			iam.ResetReadAll()
+			iam.ResetNow()
 		})
 
 		Context("when using a PKCS#1 key", func() {
 			Context("when cert and key are valid", func() {
 				It("does not return an error", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "")
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "", "")
 
 					Expect(err).NotTo(HaveOccurred())
 				})
 			})
 
 			It("does not return an error when cert, key, and chain are valid", func() {
-				err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath)
+				err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath, "")
 
 				Expect(err).NotTo(HaveOccurred())
 			})
 
 			It("returns an error if cert and key are not provided", func() {
-				err := certificateValidator.Validate("some-command-name", "", "", "")
+				err := certificateValidator.Validate("some-command-name", "", "", "", "")
 				expectedErr := multierror.NewMultiError("some-command-name")
 				expectedErr.Add(errors.New("--cert is required"))
 				expectedErr.Add(errors.New("--key is required"))
@@ -89,7 +94,7 @@ var _ = Describe("CertificateValidator", func() {
 			})
 
 			It("returns an error if the cert key file does not exist", func() {
-				err := certificateValidator.Validate("some-command-name", "/some/fake/cert/path", "/some/fake/key/path", "")
+				err := certificateValidator.Validate("some-command-name", "/some/fake/cert/path", "/some/fake/key/path", "", "")
 				expectedErr := multierror.NewMultiError("some-command-name")
 				expectedErr.Add(errors.New(`certificate file not found: "/some/fake/cert/path"`))
 				expectedErr.Add(errors.New(`key file not found: "/some/fake/key/path"`))
@@ -98,7 +103,7 @@ var _ = Describe("CertificateValidator", func() {
 			})
 
 			It("returns an error if the cert and key are not regular files", func() {
-				err := certificateValidator.Validate("some-command-name", "/dev/null", "/dev/null", "")
+				err := certificateValidator.Validate("some-command-name", "/dev/null", "/dev/null", "", "")
 				expectedErr := multierror.NewMultiError("some-command-name")
 				expectedErr.Add(errors.New(`certificate is not a regular file: "/dev/null"`))
 				expectedErr.Add(errors.New(`key is not a regular file: "/dev/null"`))
@@ -107,7 +112,7 @@ var _ = Describe("CertificateValidator", func() {
 			})
 
 			It("returns an error if the cert and key are not PEM encoded", func() {
-				err := certificateValidator.Validate("some-command-name", certNonPEMFilePath, keyNonPEMFilePath, "")
+				err := certificateValidator.Validate("some-command-name", certNonPEMFilePath, keyNonPEMFilePath, "", "")
 
 				expectedErr := multierror.NewMultiError("some-command-name")
 				expectedErr.Add(fmt.Errorf(`certificate is not PEM encoded: %q`, certNonPEMFilePath))
@@ -117,7 +122,7 @@ var _ = Describe("CertificateValidator", func() {
 			})
 
 			It("returns an error if the key and cert are not compatible", func() {
-				err := certificateValidator.Validate("some-command-name", certFilePath, otherKeyFilePath, "")
+				err := certificateValidator.Validate("some-command-name", certFilePath, otherKeyFilePath, "", "")
 
 				expectedErr := multierror.NewMultiError("some-command-name")
 				expectedErr.Add(errors.New("tls: private key does not match public key"))
@@ -126,7 +131,7 @@ var _ = Describe("CertificateValidator", func() {
 
 			Context("chain is provided", func() {
 				It("returns an error when chain file does not exist", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "/some/fake/chain/path")
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "/some/fake/chain/path", "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New(`chain file not found: "/some/fake/chain/path"`))
 
@@ -134,7 +139,7 @@ var _ = Describe("CertificateValidator", func() {
 				})
 
 				It("returns an error when chain file is not a regular file", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "/dev/null")
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, "/dev/null", "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New(`chain is not a regular file: "/dev/null"`))
 
@@ -142,7 +147,7 @@ var _ = Describe("CertificateValidator", func() {
 				})
 
 				It("returns an error if the chain is not PEM encoded", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainNonPEMFilePath)
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainNonPEMFilePath, "")
 
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(fmt.Errorf(`chain is not PEM encoded: %q`, chainNonPEMFilePath))
@@ -151,7 +156,7 @@ var _ = Describe("CertificateValidator", func() {
 				})
 
 				It("returns an error if the chain and cert are not compatible", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, otherChainFilePath)
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, otherChainFilePath, "")
 
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New("certificate and chain mismatch: x509: certificate signed by unknown authority"))
@@ -159,7 +164,7 @@ var _ = Describe("CertificateValidator", func() {
 				})
 
 				It("returns multiple errors if the cert, key and chain are incompatiable", func() {
-					err := certificateValidator.Validate("some-command-name", certFilePath, otherKeyFilePath, otherChainFilePath)
+					err := certificateValidator.Validate("some-command-name", certFilePath, otherKeyFilePath, otherChainFilePath, "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New("tls: private key does not match public key"))
 					expectedErr.Add(errors.New("certificate and chain mismatch: x509: certificate signed by unknown authority"))
@@ -185,7 +190,7 @@ var _ = Describe("CertificateValidator", func() {
 					keyFile := createTempFile()
 					chainFile := createTempFile()
 
-					err := certificateValidator.Validate("some-command-name", certFile, keyFile, chainFile)
+					err := certificateValidator.Validate("some-command-name", certFile, keyFile, chainFile, "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(fmt.Errorf("open %s: permission denied", certFile))
 					expectedErr.Add(fmt.Errorf("open %s: permission denied", keyFile))
@@ -199,7 +204,7 @@ var _ = Describe("CertificateValidator", func() {
 						return nil, errors.New("failed to retrieve file info")
 					})
 
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath)
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath, "")
 
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(fmt.Errorf("failed to retrieve file info: %s", certFilePath))
@@ -214,7 +219,7 @@ var _ = Describe("CertificateValidator", func() {
 						return []byte{}, errors.New("bad read")
 					})
 
-					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath)
+					err := certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, chainFilePath, "")
 
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(fmt.Errorf("bad read: %s", certFilePath))
@@ -235,7 +240,7 @@ var _ = Describe("CertificateValidator", func() {
 				`), os.ModePerm)
 					Expect(err).NotTo(HaveOccurred())
 
-					err = certificateValidator.Validate("some-command-name", certFilePath, file.Name(), chainFilePath)
+					err = certificateValidator.Validate("some-command-name", certFilePath, file.Name(), chainFilePath, "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New("tls: failed to parse private key"))
 
@@ -253,7 +258,7 @@ var _ = Describe("CertificateValidator", func() {
 				`), os.ModePerm)
 					Expect(err).NotTo(HaveOccurred())
 
-					err = certificateValidator.Validate("some-command-name", file.Name(), keyFilePath, chainFilePath)
+					err = certificateValidator.Validate("some-command-name", file.Name(), keyFilePath, chainFilePath, "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New("asn1: syntax error: sequence truncated"))
 
@@ -271,7 +276,7 @@ var _ = Describe("CertificateValidator", func() {
 				`), os.ModePerm)
 					Expect(err).NotTo(HaveOccurred())
 
-					err = certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, file.Name())
+					err = certificateValidator.Validate("some-command-name", certFilePath, keyFilePath, file.Name(), "")
 					expectedErr := multierror.NewMultiError("some-command-name")
 					expectedErr.Add(errors.New("failed to parse chain"))
 
@@ -283,11 +288,91 @@ var _ = Describe("CertificateValidator", func() {
 		Context("when using a PKCS#8 key", func() {
 			Context("when cert and key are valid", func() {
 				It("does not return an error", func() {
-					err := certificateValidator.Validate("some-command-name", "fixtures/pkcs8.crt", "fixtures/pkcs8.key", "")
+					err := certificateValidator.Validate("some-command-name", "fixtures/pkcs8.crt", "fixtures/pkcs8.key", "", "")
 
 					Expect(err).NotTo(HaveOccurred())
 				})
 			})
 		})
+
+		Context("when using an ECDSA key", func() {
+			It("does not return an error for a P-256 key and its chain", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p256.crt", "fixtures/ecdsa-p256.key", "fixtures/ecdsa-ca.crt", "")
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not return an error for a P-384 key", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p384.crt", "fixtures/ecdsa-p384.key", "", "")
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error if the key and cert are not compatible", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p256.crt", "fixtures/ecdsa-p384.key", "", "")
+
+				expectedErr := multierror.NewMultiError("some-command-name")
+				expectedErr.Add(errors.New("tls: private key does not match public key"))
+				Expect(err).To(Equal(expectedErr))
+			})
+
+			It("returns an error for curves the load balancers do not accept", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p521.crt", "fixtures/ecdsa-p521.key", "", "")
+
+				expectedErr := multierror.NewMultiError("some-command-name")
+				expectedErr.Add(errors.New("ECDSA keys must use the P-256 or P-384 curve, got P-521"))
+				Expect(err).To(Equal(expectedErr))
+			})
+		})
+
+		It("returns an error for key types the load balancers do not accept", func() {
+			err := certificateValidator.Validate("some-command-name", "fixtures/ed25519.crt", "fixtures/ed25519.key", "", "")
+
+			expectedErr := multierror.NewMultiError("some-command-name")
+			expectedErr.Add(errors.New("unsupported private key type ed25519.PrivateKey, load balancers accept RSA and ECDSA P-256 or P-384 keys"))
+			Expect(err).To(Equal(expectedErr))
+		})
+
+		Context("when a domain is provided", func() {
+			It("does not return an error when the certificate covers every host in the domain", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p256.crt", "fixtures/ecdsa-p256.key", "", "Example.com")
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error when the certificate does not cover the domain", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p256.crt", "fixtures/ecdsa-p256.key", "", "cf.example.org")
+
+				expectedErr := multierror.NewMultiError("some-command-name")
+				expectedErr.Add(errors.New("certificate does not cover *.cf.example.org, its subject alternative names are [*.example.com, example.com]"))
+				Expect(err).To(Equal(expectedErr))
+			})
+		})
+
+		Context("expiry", func() {
+			It("does not warn when the certificate is valid for more than 30 days", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p384.crt", "fixtures/ecdsa-p384.key", "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.WarnCall.CallCount).To(Equal(0))
+			})
+
+			It("warns when the certificate expires within 30 days", func() {
+				iam.SetNow(func() time.Time {
+					return time.Date(2126, 1, 1, 0, 0, 0, 0, time.UTC)
+				})
+				err := certificateValidator.Validate("some-command-name", "fixtures/ecdsa-p384.crt", "fixtures/ecdsa-p384.key", "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.WarnCall.Messages).To(ConsistOf(MatchRegexp(`^certificate expires on \d{4}-\d{2}-\d{2}$`)))
+			})
+
+			It("warns when the certificate has expired", func() {
+				err := certificateValidator.Validate("some-command-name", "fixtures/pkcs8.crt", "fixtures/pkcs8.key", "", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.WarnCall.Messages).To(Equal([]string{"certificate expired on 2017-07-22"}))
+			})
+		})
 	})
 })
@@ -11,6 +11,9 @@ type Client interface {
 	UploadServerCertificate(*awsiam.UploadServerCertificateInput) (*awsiam.UploadServerCertificateOutput, error)
 	GetServerCertificate(*awsiam.GetServerCertificateInput) (*awsiam.GetServerCertificateOutput, error)
 	DeleteServerCertificate(*awsiam.DeleteServerCertificateInput) (*awsiam.DeleteServerCertificateOutput, error)
+	CreateAccessKey(*awsiam.CreateAccessKeyInput) (*awsiam.CreateAccessKeyOutput, error)
+	DeleteAccessKey(*awsiam.DeleteAccessKeyInput) (*awsiam.DeleteAccessKeyOutput, error)
+	GetAccessKeyLastUsed(*awsiam.GetAccessKeyLastUsedInput) (*awsiam.GetAccessKeyLastUsedOutput, error)
 }
 
 func NewClient(config aws.Config) Client {
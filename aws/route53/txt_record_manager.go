@@ -0,0 +1,71 @@
+package route53
+
+import (
+	"fmt"
+
+	goaws "github.com/aws/aws-sdk-go/aws"
+	awsroute53 "github.com/aws/aws-sdk-go/service/route53"
+)
+
+const txtRecordTTL = 60
+
+type route53ClientProvider interface {
+	GetRoute53Client() Client
+}
+
+type TXTRecordManager struct {
+	route53ClientProvider route53ClientProvider
+}
+
+func NewTXTRecordManager(route53ClientProvider route53ClientProvider) TXTRecordManager {
+	return TXTRecordManager{
+		route53ClientProvider: route53ClientProvider,
+	}
+}
+
+// SetTXTRecord creates or replaces the TXT record in the hosted zone and waits
+// until the change has reached every Route53 name server.
+func (m TXTRecordManager) SetTXTRecord(zoneID, fqdn string, values []string) error {
+	return m.change(awsroute53.ChangeActionUpsert, zoneID, fqdn, values)
+}
+
+func (m TXTRecordManager) DeleteTXTRecord(zoneID, fqdn string, values []string) error {
+	return m.change(awsroute53.ChangeActionDelete, zoneID, fqdn, values)
+}
+
+func (m TXTRecordManager) change(action, zoneID, fqdn string, values []string) error {
+	client := m.route53ClientProvider.GetRoute53Client()
+
+	var records []*awsroute53.ResourceRecord
+	for _, value := range values {
+		records = append(records, &awsroute53.ResourceRecord{
+			Value: goaws.String(fmt.Sprintf("%q", value)),
+		})
+	}
+
+	output, err := client.ChangeResourceRecordSets(&awsroute53.ChangeResourceRecordSetsInput{
+		HostedZoneId: goaws.String(zoneID),
+		ChangeBatch: &awsroute53.ChangeBatch{
+			Changes: []*awsroute53.Change{{
+				Action: goaws.String(action),
+				ResourceRecordSet: &awsroute53.ResourceRecordSet{
+					Name:            goaws.String(fqdn),
+					Type:            goaws.String(awsroute53.RRTypeTxt),
+					TTL:             goaws.Int64(txtRecordTTL),
+					ResourceRecords: records,
+				},
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change the TXT record %s: %s", fqdn, err)
+	}
+
+	if action == awsroute53.ChangeActionDelete {
+		return nil
+	}
+
+	return client.WaitUntilResourceRecordSetsChanged(&awsroute53.GetChangeInput{
+		Id: output.ChangeInfo.Id,
+	})
+}
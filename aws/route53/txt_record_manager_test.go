@@ -0,0 +1,90 @@
+package route53_test
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsroute53 "github.com/aws/aws-sdk-go/service/route53"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/route53"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TXTRecordManager", func() {
+	var (
+		manager           route53.TXTRecordManager
+		route53Client     *fakes.Route53Client
+		awsClientProvider *fakes.AWSClientProvider
+	)
+
+	BeforeEach(func() {
+		route53Client = &fakes.Route53Client{}
+		route53Client.ChangeResourceRecordSetsCall.Returns.Output = &awsroute53.ChangeResourceRecordSetsOutput{
+			ChangeInfo: &awsroute53.ChangeInfo{Id: aws.String("some-change-id")},
+		}
+
+		awsClientProvider = &fakes.AWSClientProvider{}
+		awsClientProvider.GetRoute53ClientCall.Returns.Route53Client = route53Client
+
+		manager = route53.NewTXTRecordManager(awsClientProvider)
+	})
+
+	Describe("SetTXTRecord", func() {
+		It("upserts the quoted values and waits for the change", func() {
+			err := manager.SetTXTRecord("some-zone-id", "_acme-challenge.example.com", []string{"value-1", "value-2"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(route53Client.ChangeResourceRecordSetsCall.Receives.Input).To(Equal(&awsroute53.ChangeResourceRecordSetsInput{
+				HostedZoneId: aws.String("some-zone-id"),
+				ChangeBatch: &awsroute53.ChangeBatch{
+					Changes: []*awsroute53.Change{{
+						Action: aws.String("UPSERT"),
+						ResourceRecordSet: &awsroute53.ResourceRecordSet{
+							Name: aws.String("_acme-challenge.example.com"),
+							Type: aws.String("TXT"),
+							TTL:  aws.Int64(60),
+							ResourceRecords: []*awsroute53.ResourceRecord{
+								{Value: aws.String(`"value-1"`)},
+								{Value: aws.String(`"value-2"`)},
+							},
+						},
+					}},
+				},
+			}))
+
+			Expect(route53Client.WaitUntilResourceRecordSetsChangedCall.Receives.Input).To(Equal(&awsroute53.GetChangeInput{
+				Id: aws.String("some-change-id"),
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the change fails", func() {
+				route53Client.ChangeResourceRecordSetsCall.Returns.Error = errors.New("access denied")
+
+				err := manager.SetTXTRecord("some-zone-id", "_acme-challenge.example.com", []string{"value-1"})
+				Expect(err).To(MatchError("failed to change the TXT record _acme-challenge.example.com: access denied"))
+			})
+
+			It("returns an error when waiting for the change fails", func() {
+				route53Client.WaitUntilResourceRecordSetsChangedCall.Returns.Error = errors.New("timed out")
+
+				err := manager.SetTXTRecord("some-zone-id", "_acme-challenge.example.com", []string{"value-1"})
+				Expect(err).To(MatchError("timed out"))
+			})
+		})
+	})
+
+	Describe("DeleteTXTRecord", func() {
+		It("deletes the record without waiting", func() {
+			err := manager.DeleteTXTRecord("some-zone-id", "_acme-challenge.example.com", []string{"value-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			change := route53Client.ChangeResourceRecordSetsCall.Receives.Input.ChangeBatch.Changes[0]
+			Expect(change.Action).To(Equal(aws.String("DELETE")))
+			Expect(route53Client.WaitUntilResourceRecordSetsChangedCall.CallCount).To(Equal(0))
+		})
+	})
+})
@@ -118,6 +118,46 @@ func (b *Backend) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.Des
 	}, nil
 }
 
+func (b *Backend) DescribeAccountAttributes(input *ec2.DescribeAccountAttributesInput) (*ec2.DescribeAccountAttributesOutput, error) {
+	return &ec2.DescribeAccountAttributesOutput{
+		AccountAttributes: []*ec2.AccountAttribute{
+			{
+				AttributeName:   aws.String("max-instances"),
+				AttributeValues: []*ec2.AccountAttributeValue{{AttributeValue: aws.String("20")}},
+			},
+			{
+				AttributeName:   aws.String("vpc-max-elastic-ips"),
+				AttributeValues: []*ec2.AccountAttributeValue{{AttributeValue: aws.String("5")}},
+			},
+		},
+	}, nil
+}
+
+func (b *Backend) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	return &ec2.DescribeAddressesOutput{}, nil
+}
+
+func (b *Backend) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{}, nil
+}
+
+func (b *Backend) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return &ec2.DescribeSecurityGroupsOutput{}, nil
+}
+
+func (b *Backend) DescribeRouteTables(input *ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error) {
+	return &ec2.DescribeRouteTablesOutput{}, nil
+}
+
+func (b *Backend) DescribeAccountLimits(input *elb.DescribeAccountLimitsInput) (*elb.DescribeAccountLimitsOutput, error) {
+	return &elb.DescribeAccountLimitsOutput{
+		Limits: []*elb.Limit{{
+			Name: aws.String("classic-load-balancers"),
+			Max:  aws.String("20"),
+		}},
+	}, nil
+}
+
 func (b *Backend) CreateStack(input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
 	stack := Stack{
 		Name:     *input.StackName,
@@ -43,6 +43,7 @@ var _ = Describe("bbl-env-id", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Eventually(session).Should(gexec.Exit(0))
 		Expect(session.Out.Contents()).To(ContainSubstring("some-env-id"))
+		Expect(filepath.Join(tempDirectory, "logs")).To(BeADirectory())
 	})
 
 	Context("when bbl environment does not have any bosh director", func() {
@@ -85,6 +86,7 @@ var _ = Describe("bbl-env-id", func() {
 
 			expectedErrorMessage := fmt.Sprintf("bbl-state.json not found in %q, ensure you're running this command in the proper state directory or create a new environment with bbl up", tempDirectory)
 			Expect(session.Err.Contents()).To(ContainSubstring(expectedErrorMessage))
+			Expect(filepath.Join(tempDirectory, "logs")).NotTo(BeAnExistingFile())
 		})
 
 		It("returns a non zero exit code when the bbl env id does not exist", func() {
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
@@ -32,6 +33,11 @@ func main() {
 
 	if os.Args[1] == "create-env" {
 		incrementCallCountOnBackendServer(os.Args[1])
+		if testhelpers.Contains(os.Args, "wait-for-interrupt") {
+			waitForInterrupt()
+			writeStateToFile(`{"partial":"interrupted-bosh-state"}`)
+			log.Fatal("interrupted bosh")
+		}
 		if checkFastFail(os.Args[1]) {
 			log.Fatal("failed to bosh")
 		}
@@ -147,6 +153,16 @@ func calculateArgMD5(args []string) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(argString)))
 }
 
+func waitForInterrupt() {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	err := ioutil.WriteFile("waiting-for-interrupt", []byte{}, os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+	<-interrupts
+}
+
 func removeBrackets(contents string) string {
 	contents = strings.Replace(contents, "[", "", -1)
 	contents = strings.Replace(contents, "]", "", -1)
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/testhelpers"
@@ -21,6 +22,17 @@ func main() {
 		log.Fatal("failed to terraform")
 	}
 
+	if testhelpers.Contains(os.Args, "region=wait-for-interrupt") {
+		waitForInterrupt()
+
+		err := ioutil.WriteFile("terraform.tfstate", []byte(`{"key":"interrupted-apply"}`), os.ModePerm)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Fatal("interrupted terraform")
+	}
+
 	if testhelpers.Contains(os.Args, "region=fail-to-terraform") {
 		err := ioutil.WriteFile("terraform.tfstate", []byte(`{"key":"partial-apply"}`), os.ModePerm)
 		if err != nil {
@@ -91,6 +103,16 @@ func main() {
 	}
 }
 
+func waitForInterrupt() {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	err := ioutil.WriteFile("waiting-for-interrupt", []byte{}, os.ModePerm)
+	if err != nil {
+		panic(err)
+	}
+	<-interrupts
+}
+
 func removeBrackets(contents string) string {
 	contents = strings.Replace(contents, "[", "", -1)
 	contents = strings.Replace(contents, "]", "", -1)
@@ -563,6 +563,36 @@ var _ = Describe("bbl up gcp", func() {
 			})
 		})
 
+		Context("when terraform apply times out", func() {
+			var (
+				session *gexec.Session
+			)
+
+			BeforeEach(func() {
+				args := []string{
+					"--state-dir", tempDirectory,
+					"--terraform-timeout", "1s",
+					"up",
+					"--iaas", "gcp",
+					"--gcp-service-account-key", serviceAccountKeyPath,
+					"--gcp-project-id", "some-project-id",
+					"--gcp-zone", "some-zone",
+					"--gcp-region", "wait-for-interrupt",
+				}
+
+				session = executeCommand(args, 1)
+			})
+
+			It("saves the tf state written by the interrupted terraform", func() {
+				state := readStateJson(tempDirectory)
+				Expect(state.TFState).To(Equal(`{"key":"interrupted-apply"}`))
+			})
+
+			It("names the phase that timed out", func() {
+				Expect(session.Err.Contents()).To(ContainSubstring("terraform timed out after 1s"))
+			})
+		})
+
 		Context("when bosh fails", func() {
 			BeforeEach(func() {
 				fakeBOSHCLIBackendServer.SetCreateEnvFastFail(true)
@@ -76,6 +76,12 @@ func (g *GCPBackend) StartFakeGCPBackend() (*httptest.Server, string) {
 				w.Write([]byte(`{}`))
 			}
 		default:
+			if strings.HasPrefix(req.URL.Path, "/some-project-id/regions/") {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"quotas": [ { "limit": 8, "metric": "STATIC_ADDRESSES", "usage": 0 } ]}`))
+				return
+			}
+
 			log.Println("unexpected request recieved: ", req.URL.Path)
 			w.WriteHeader(http.StatusTeapot)
 		}
@@ -6,11 +6,17 @@ import (
 	"crypto/rsa"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
+	"github.com/square/certstrap/pkix"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/cloudfoundry/bosh-bootloader/acme"
 	"github.com/cloudfoundry/bosh-bootloader/application"
 	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/clientmanager"
@@ -18,13 +24,18 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation/templates"
 	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/aws/route53"
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
 	"github.com/cloudfoundry/bosh-bootloader/cloudconfig"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/gcp"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/iampolicy"
 	"github.com/cloudfoundry/bosh-bootloader/keypair"
 	"github.com/cloudfoundry/bosh-bootloader/proxy"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
 
@@ -34,6 +45,8 @@ import (
 	gcpcloudconfig "github.com/cloudfoundry/bosh-bootloader/cloudconfig/gcp"
 	awskeypair "github.com/cloudfoundry/bosh-bootloader/keypair/aws"
 	gcpkeypair "github.com/cloudfoundry/bosh-bootloader/keypair/gcp"
+	awsquota "github.com/cloudfoundry/bosh-bootloader/quota/aws"
+	gcpquota "github.com/cloudfoundry/bosh-bootloader/quota/gcp"
 	awsterraform "github.com/cloudfoundry/bosh-bootloader/terraform/aws"
 	gcpterraform "github.com/cloudfoundry/bosh-bootloader/terraform/gcp"
 )
@@ -66,6 +79,14 @@ func main() {
 		commands.CloudConfigCommand:        nil,
 		commands.BOSHDeploymentVarsCommand: nil,
 		commands.RotateCommand:             nil,
+		commands.StatusCommand:             nil,
+		commands.OutputsCommand:            nil,
+		commands.StateCommand:              nil,
+		commands.DoctorCommand:             nil,
+		commands.IAMPolicyCommand:          nil,
+		commands.DriftCommand:              nil,
+		commands.MigrateCommand:            nil,
+		commands.CertsCommand:              nil,
 	}
 
 	// Utilities
@@ -81,9 +102,39 @@ func main() {
 
 	configuration := getConfiguration(usage.Print, commandSet, envGetter)
 
+	logger.SetLevel(configuration.Global.LogLevel)
+	logger.SetFormat(configuration.Global.LogFormat)
+	stderrLogger.SetLevel(configuration.Global.LogLevel)
+	stderrLogger.SetFormat(configuration.Global.LogFormat)
+
+	if writesLogFile(configuration) {
+		logFile, err := application.NewLogFile(configuration.Global.StateDir, configuration.Command)
+		if err != nil {
+			stderrLogger.Warn("could not create log file: %s", err)
+		} else {
+			logger.SetLogFile(logFile)
+			stderrLogger.SetLogFile(logFile)
+			logger.Debug("writing logs to %s", logFile.Name())
+		}
+	}
+
+	events := application.NewEventStream(ioutil.Discard)
+	if configuration.Global.Events {
+		eventsWriter, err := getEventsWriter(configuration.Global.EventsPath)
+		if err != nil {
+			fail(err, configuration.Global.Output)
+		}
+		events = application.NewEventStream(eventsWriter)
+	}
+	logger.SetEventStream(events)
+	stderrLogger.SetEventStream(events)
+	events.CommandStarted(configuration.Command, Version)
+
 	storage.GetStateLogger = stderrLogger
 
-	stateStore := storage.NewStore(configuration.Global.StateDir)
+	outputWriter := commands.NewOutputWriter(logger, configuration.Global.Output)
+
+	stateStore := application.NewEventStateStore(storage.NewStore(configuration.Global.StateDir), events)
 	stateValidator := application.NewStateValidator(configuration.Global.StateDir)
 
 	awsCredentialValidator := awsapplication.NewCredentialValidator(configuration)
@@ -108,6 +159,8 @@ func main() {
 	keyPairSynchronizer := ec2.NewKeyPairSynchronizer(awsKeyPairCreator, keyPairChecker, logger)
 	awsKeyPairManager := awskeypair.NewManager(keyPairSynchronizer, awsKeyPairDeleter, clientProvider)
 	availabilityZoneRetriever := ec2.NewAvailabilityZoneRetriever(clientProvider)
+	elasticIPRetriever := ec2.NewElasticIPRetriever(clientProvider)
+	routeTableAssociationRetriever := ec2.NewRouteTableAssociationRetriever(clientProvider)
 	templateBuilder := templates.NewTemplateBuilder(logger)
 	stackManager := cloudformation.NewStackManager(clientProvider, logger)
 	infrastructureManager := cloudformation.NewInfrastructureManager(templateBuilder, stackManager)
@@ -115,7 +168,10 @@ func main() {
 	certificateDescriber := iam.NewCertificateDescriber(clientProvider)
 	certificateDeleter := iam.NewCertificateDeleter(clientProvider)
 	certificateManager := iam.NewCertificateManager(certificateUploader, certificateDescriber, certificateDeleter)
-	certificateValidator := iam.NewCertificateValidator()
+	accessKeyManager := iam.NewAccessKeyManager(clientProvider)
+	certificateValidator := iam.NewCertificateValidator(logger)
+	certificateInventory := certificates.NewInventory(certificateDescriber)
+	certificateGenerator := ssl.NewKeyPairGenerator(rsa.GenerateKey, pkix.CreateCertificateAuthority, pkix.CreateCertificateSigningRequest, pkix.CreateCertificateHost)
 
 	// GCP
 	gcpClientProvider := gcp.NewClientProvider(gcpBasePath)
@@ -135,8 +191,8 @@ func main() {
 	// Terraform
 	terraformOutputBuffer := bytes.NewBuffer([]byte{})
 
-	terraformCmd := terraform.NewCmd(os.Stderr, terraformOutputBuffer)
-	terraformExecutor := terraform.NewExecutor(terraformCmd, configuration.Global.Debug)
+	terraformCmd := terraform.NewCmd(os.Stderr, terraformOutputBuffer, logger.SubprocessWriter("terraform"), events)
+	terraformExecutor := terraform.NewExecutor(terraformCmd, configuration.Global.Debug, configuration.Global.TerraformTimeout)
 	gcpTemplateGenerator := gcpterraform.NewTemplateGenerator(zones)
 	gcpInputGenerator := gcpterraform.NewInputGenerator()
 	gcpOutputGenerator := gcpterraform.NewOutputGenerator(terraformExecutor)
@@ -155,13 +211,30 @@ func main() {
 		Logger:                logger,
 	})
 
+	// ACME
+	route53TXTRecordManager := route53.NewTXTRecordManager(clientProvider)
+	gcpTXTRecordManager := gcp.NewTXTRecordManager(gcpClientProvider)
+	gcpServiceAccountKeyManager := gcp.NewServiceAccountKeyManager(gcpClientProvider)
+	acmeIssuer := acme.NewIssuer(acme.NewClient, rsa.GenerateKey, logger)
+	acmeManager := acme.NewManager(acmeIssuer, terraformManager, route53TXTRecordManager, gcpTXTRecordManager)
+
+	// Quotas
+	awsQuotaRetriever := awsquota.NewRetriever(clientProvider)
+	gcpQuotaRetriever := gcpquota.NewRetriever(gcpClientProvider)
+	quotaChecker := quota.NewChecker(logger, templateGenerator, awsQuotaRetriever, gcpQuotaRetriever)
+	gcpPermissionsChecker := gcp.NewPermissionsChecker(gcpClientProvider)
+
+	// IAM Policy
+	iamPolicyGenerator := iampolicy.NewGenerator(templateGenerator)
+
 	// BOSH
 	hostKeyGetter := proxy.NewHostKeyGetter()
 	socks5Proxy := proxy.NewSocks5Proxy(logger, hostKeyGetter, 0)
-	boshCommand := bosh.NewCmd(os.Stderr)
+	boshCommand := bosh.NewCmd(os.Stderr, logger.SubprocessWriter("bosh"), events)
 	boshExecutor := bosh.NewExecutor(boshCommand, ioutil.TempDir, ioutil.ReadFile, json.Unmarshal,
-		json.Marshal, ioutil.WriteFile)
+		json.Marshal, ioutil.WriteFile, configuration.Global.CreateEnvTimeout)
 	boshManager := bosh.NewManager(boshExecutor, terraformManager, stackManager, logger, socks5Proxy)
+	credentialRotator := bosh.NewCredentialRotator(certificateGenerator)
 	boshClientProvider := bosh.NewClientProvider()
 
 	// Environment Validators
@@ -175,7 +248,7 @@ func main() {
 	awsTerraformOpsGenerator := awscloudconfig.NewTerraformOpsGenerator(availabilityZoneRetriever, terraformManager)
 	gcpOpsGenerator := gcpcloudconfig.NewOpsGenerator(terraformManager, zones)
 	cloudConfigOpsGenerator := cloudconfig.NewOpsGenerator(awsCloudFormationOpsGenerator, awsTerraformOpsGenerator, gcpOpsGenerator)
-	cloudConfigManager := cloudconfig.NewManager(logger, boshCommand, cloudConfigOpsGenerator, boshClientProvider, socks5Proxy, terraformManager, sshKeyGetter)
+	cloudConfigManager := cloudconfig.NewManager(logger, boshCommand, cloudConfigOpsGenerator, boshClientProvider, socks5Proxy, terraformManager, sshKeyGetter, configuration.Global.CloudConfigTimeout)
 
 	// Subcommands
 	awsUp := commands.NewAWSUp(
@@ -186,10 +259,10 @@ func main() {
 	awsCreateLBs := commands.NewAWSCreateLBs(
 		logger, awsCredentialValidator, certificateManager, infrastructureManager,
 		availabilityZoneRetriever, cloudConfigManager, certificateValidator,
-		uuidGenerator, stateStore, terraformManager, awsEnvironmentValidator,
+		uuidGenerator, stateStore, terraformManager, awsEnvironmentValidator, certificateGenerator, acmeManager,
 	)
 
-	awsLBs := commands.NewAWSLBs(awsCredentialValidator, infrastructureManager, terraformManager, logger)
+	awsLBs := commands.NewAWSLBs(awsCredentialValidator, infrastructureManager, terraformManager, logger, outputWriter, certificateInventory)
 
 	awsUpdateLBs := commands.NewAWSUpdateLBs(awsCreateLBs, awsCredentialValidator, certificateManager, availabilityZoneRetriever, infrastructureManager,
 		logger, uuidGenerator, stateStore, awsEnvironmentValidator)
@@ -213,57 +286,116 @@ func main() {
 		CloudConfigManager: cloudConfigManager,
 	})
 
-	gcpCreateLBs := commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, gcpEnvironmentValidator)
+	gcpCreateLBs := commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, gcpEnvironmentValidator, certificateValidator, certificateGenerator, acmeManager)
 
-	gcpLBs := commands.NewGCPLBs(terraformManager, logger)
+	gcpLBs := commands.NewGCPLBs(terraformManager, logger, outputWriter, certificateInventory)
 
 	gcpUpdateLBs := commands.NewGCPUpdateLBs(gcpCreateLBs)
 
+	awsRotateIAASCredentials := commands.NewAWSRotateIAASCredentials(accessKeyManager, terraformManager, boshManager, stateStore, logger)
+	gcpRotateIAASCredentials := commands.NewGCPRotateIAASCredentials(gcpServiceAccountKeyManager, terraformManager, boshManager, stateStore, logger)
+
 	// Commands
 	commandSet[commands.HelpCommand] = usage
 	commandSet[commands.VersionCommand] = commands.NewVersion(Version, logger)
-	commandSet[commands.UpCommand] = commands.NewUp(awsUp, gcpUp, envGetter, boshManager)
+	commandSet[commands.UpCommand] = commands.NewUp(awsUp, gcpUp, envGetter, boshManager, quotaChecker, gcpPermissionsChecker, acmeManager)
 	commandSet[commands.DestroyCommand] = commands.NewDestroy(
 		credentialValidator, logger, os.Stdin, boshManager, vpcStatusChecker, stackManager,
 		stringGenerator, infrastructureManager, awsKeyPairDeleter, gcpKeyPairDeleter, certificateDeleter,
 		stateStore, stateValidator, terraformManager, gcpNetworkInstancesChecker,
 	)
 	commandSet[commands.DownCommand] = commandSet[commands.DestroyCommand]
-	commandSet[commands.CreateLBsCommand] = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager)
-	commandSet[commands.UpdateLBsCommand] = commands.NewUpdateLBs(awsUpdateLBs, gcpUpdateLBs, certificateValidator, stateValidator, logger, boshManager)
+	commandSet[commands.CreateLBsCommand] = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager, quotaChecker)
+	commandSet[commands.UpdateLBsCommand] = commands.NewUpdateLBs(awsUpdateLBs, gcpUpdateLBs, certificateValidator, stateValidator, logger, boshManager,
+		acmeManager, terraformManager, stateStore)
 	commandSet[commands.DeleteLBsCommand] = commands.NewDeleteLBs(gcpDeleteLBs, awsDeleteLBs, logger, stateValidator, boshManager)
 	commandSet[commands.LBsCommand] = commands.NewLBs(gcpLBs, awsLBs, stateValidator, logger)
-	commandSet[commands.DirectorAddressCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorAddressPropertyName)
-	commandSet[commands.DirectorUsernameCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorUsernamePropertyName)
-	commandSet[commands.DirectorPasswordCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorPasswordPropertyName)
-	commandSet[commands.DirectorCACertCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.DirectorCACertPropertyName)
-	commandSet[commands.SSHKeyCommand] = commands.NewSSHKey(logger, stateValidator, sshKeyGetter)
-	commandSet[commands.EnvIDCommand] = commands.NewStateQuery(logger, stateValidator, terraformManager, infrastructureManager, commands.EnvIDPropertyName)
-	commandSet[commands.LatestErrorCommand] = commands.NewLatestError(logger, stateValidator)
-	commandSet[commands.PrintEnvCommand] = commands.NewPrintEnv(logger, stateValidator, terraformManager, infrastructureManager)
+	commandSet[commands.DirectorAddressCommand] = commands.NewStateQuery(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, commands.DirectorAddressPropertyName)
+	commandSet[commands.DirectorUsernameCommand] = commands.NewStateQuery(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, commands.DirectorUsernamePropertyName)
+	commandSet[commands.DirectorPasswordCommand] = commands.NewStateQuery(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, commands.DirectorPasswordPropertyName)
+	commandSet[commands.DirectorCACertCommand] = commands.NewStateQuery(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, commands.DirectorCACertPropertyName)
+	commandSet[commands.SSHKeyCommand] = commands.NewSSHKey(logger, outputWriter, stateValidator, sshKeyGetter)
+	commandSet[commands.EnvIDCommand] = commands.NewStateQuery(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, commands.EnvIDPropertyName)
+	commandSet[commands.LatestErrorCommand] = commands.NewLatestError(logger, outputWriter, stateValidator)
+	commandSet[commands.PrintEnvCommand] = commands.NewPrintEnv(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, sshKeyGetter, configuration.Global.StateDir)
 	commandSet[commands.CloudConfigCommand] = commands.NewCloudConfig(logger, stateValidator, cloudConfigManager)
-	commandSet[commands.BOSHDeploymentVarsCommand] = commands.NewBOSHDeploymentVars(logger, boshManager, stateValidator)
-	commandSet[commands.RotateCommand] = commands.NewRotate(stateStore, keyPairManager, boshManager, stateValidator)
-
-	app := application.New(commandSet, configuration, stateStore, usage)
-
-	err := app.Run()
+	commandSet[commands.BOSHDeploymentVarsCommand] = commands.NewBOSHDeploymentVars(logger, outputWriter, boshManager, stateValidator)
+	commandSet[commands.RotateCommand] = commands.NewRotate(stateStore, keyPairManager, boshManager, stateValidator, credentialRotator, awsRotateIAASCredentials, gcpRotateIAASCredentials, logger)
+	commandSet[commands.OutputsCommand] = commands.NewOutputs(logger, outputWriter, stateValidator, terraformManager, infrastructureManager)
+	commandSet[commands.StateCommand] = commands.NewStateGet(logger, outputWriter, stateValidator)
+	commandSet[commands.StatusCommand] = commands.NewStatus(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, boshClientProvider, socks5Proxy, sshKeyGetter, certificateInventory)
+	commandSet[commands.CertsCommand] = commands.NewCerts(logger, outputWriter, stateValidator, certificateInventory)
+	commandSet[commands.IAMPolicyCommand] = commands.NewIAMPolicy(logger, outputWriter, iamPolicyGenerator)
+	commandSet[commands.DoctorCommand] = commands.NewDoctor(logger, outputWriter, boshManager, terraformManager, credentialValidator, availabilityZoneRetriever, gcpClientProvider, configuration.Global.StateDir)
+	commandSet[commands.DriftCommand] = commands.NewDrift(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, availabilityZoneRetriever, certificateDescriber)
+	commandSet[commands.MigrateCommand] = commands.NewMigrate(logger, stateStore, stateValidator, terraformManager, stackManager,
+		infrastructureManager, availabilityZoneRetriever, certificateDescriber, elasticIPRetriever, routeTableAssociationRetriever)
+
+	app := application.New(commandSet, configuration, stateStore, usage, certificates.NewExpiryWarner(stderrLogger))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ctx := application.CancelOnInterrupt(signals, stderrLogger)
+
+	err := app.Run(ctx)
+	events.Result(err)
 	if err != nil {
-		fail(err)
+		if ctx.Err() != nil {
+			stderrLogger.Warn("%s was interrupted, any partial state has been saved to %s", configuration.Command, configuration.Global.StateDir)
+		}
+		logger.Debug("%s failed: %s", configuration.Command, err)
+		logger.Close()
+		fail(err, configuration.Global.Output)
 	}
+
+	logger.Close()
 }
 
-func fail(err error) {
+func fail(err error, outputFormat string) {
+	if outputFormat != commands.TextOutputFormat {
+		contents, marshalErr := commands.MarshalOutput(outputFormat, commands.ErrorOutput{
+			Error: err.Error(),
+			Code:  application.ErrorCode(err),
+		})
+		if marshalErr == nil {
+			fmt.Fprintln(os.Stderr, contents)
+			os.Exit(1)
+		}
+	}
+
 	fmt.Fprintf(os.Stderr, "\n\n%s\n", err)
 	os.Exit(1)
 }
 
+// writesLogFile keeps read-only queries run outside of an environment from
+// creating a logs directory wherever they are run.
+func writesLogFile(configuration application.Configuration) bool {
+	switch configuration.Command {
+	case commands.HelpCommand, commands.VersionCommand:
+		return false
+	case commands.UpCommand, commands.DestroyCommand, commands.DownCommand, commands.CreateLBsCommand,
+		commands.UpdateLBsCommand, commands.DeleteLBsCommand, commands.RotateCommand, commands.MigrateCommand:
+		return true
+	}
+
+	_, err := os.Stat(filepath.Join(configuration.Global.StateDir, storage.StateFileName))
+	return err == nil
+}
+
+func getEventsWriter(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, os.FileMode(0644))
+}
+
 func getConfiguration(printUsage func(), commandSet application.CommandSet, envGetter helpers.EnvGetter) application.Configuration {
 	commandLineParser := application.NewCommandLineParser(printUsage, commandSet, envGetter)
 	configurationParser := application.NewConfigurationParser(commandLineParser)
 	configuration, err := configurationParser.Parse(os.Args[1:])
 	if err != nil {
-		fail(err)
+		fail(err, commands.TextOutputFormat)
 	}
 
 	return configuration
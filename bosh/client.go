@@ -2,6 +2,7 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -13,7 +14,7 @@ import (
 )
 
 type Client interface {
-	UpdateCloudConfig(yaml []byte) error
+	UpdateCloudConfig(ctx context.Context, yaml []byte) error
 	ConfigureHTTPClient(proxy.Dialer)
 	Info() (Info, error)
 }
@@ -84,11 +85,12 @@ func (c client) Info() (Info, error) {
 	return info, nil
 }
 
-func (c client) UpdateCloudConfig(yaml []byte) error {
+func (c client) UpdateCloudConfig(ctx context.Context, yaml []byte) error {
 	request, err := http.NewRequest("POST", fmt.Sprintf("%s/cloud_configs", c.directorAddress), bytes.NewBuffer(yaml))
 	if err != nil {
 		return err
 	}
+	request = request.WithContext(ctx)
 	request.Header.Set("Content-Type", "text/yaml")
 	request.SetBasicAuth(c.username, c.password)
 
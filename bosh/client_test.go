@@ -1,6 +1,7 @@
 package bosh_test
 
 import (
+	"context"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -135,7 +136,7 @@ var _ = Describe("Client", func() {
 
 			client := bosh.NewClient(fakeBOSH.URL, "some-username", "some-password")
 
-			err := client.UpdateCloudConfig([]byte("cloud: config"))
+			err := client.UpdateCloudConfig(context.Background(), []byte("cloud: config"))
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(cloudConfig).To(Equal([]byte("cloud: config")))
@@ -152,14 +153,14 @@ var _ = Describe("Client", func() {
 
 				client := bosh.NewClient(fakeBOSH.URL, "", "")
 
-				err := client.UpdateCloudConfig([]byte("cloud: config"))
+				err := client.UpdateCloudConfig(context.Background(), []byte("cloud: config"))
 				Expect(err).To(MatchError("unexpected http response 500 Internal Server Error"))
 			})
 
 			It("returns an error when the director address is malformed", func() {
 				client := bosh.NewClient("%%%%%%%%%%%%%%%", "", "")
 
-				err := client.UpdateCloudConfig([]byte("cloud: config"))
+				err := client.UpdateCloudConfig(context.Background(), []byte("cloud: config"))
 				Expect(err.(*url.Error).Op).To(Equal("parse"))
 			})
 
@@ -172,9 +173,26 @@ var _ = Describe("Client", func() {
 
 				fakeBOSH.Close()
 
-				err := client.UpdateCloudConfig([]byte("cloud: config"))
+				err := client.UpdateCloudConfig(context.Background(), []byte("cloud: config"))
 				Expect(err).To(MatchError(ContainSubstring("connection refused")))
 			})
+
+			It("returns an error when the context is done before the director responds", func() {
+				done := make(chan struct{})
+				fakeBOSH := httptest.NewTLSServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+					<-done
+				}))
+				defer fakeBOSH.Close()
+				defer close(done)
+
+				client := bosh.NewClient(fakeBOSH.URL, "", "")
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := client.UpdateCloudConfig(ctx, []byte("cloud: config"))
+				Expect(err).To(MatchError(ContainSubstring("context canceled")))
+			})
 		})
 	})
 })
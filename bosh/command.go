@@ -1,26 +1,63 @@
 package bosh
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 )
 
 type Cmd struct {
-	stderr io.Writer
+	stderr    io.Writer
+	logWriter io.Writer
+	events    subprocessEvents
+}
+
+type subprocessEvents interface {
+	SubprocessStarted(name, subcommand string)
+	SubprocessExited(name, subcommand string, duration time.Duration, err error)
 }
 
-func NewCmd(stderr io.Writer) Cmd {
+func NewCmd(stderr, logWriter io.Writer, events subprocessEvents) Cmd {
 	return Cmd{
-		stderr: stderr,
+		stderr:    stderr,
+		logWriter: logWriter,
+		events:    events,
 	}
 }
 
-func (c Cmd) Run(stdout io.Writer, workingDirectory string, args []string) error {
+func (c Cmd) Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 	command := exec.Command("bosh", args...)
 	command.Dir = workingDirectory
 
-	command.Stdout = stdout
-	command.Stderr = c.stderr
+	var subcommand string
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
+
+	// interpolate prints manifests and variables with their secrets, so only
+	// the output of create-env and delete-env is written to the log file.
+	if subcommand == "create-env" || subcommand == "delete-env" {
+		command.Stdout = io.MultiWriter(stdout, c.logWriter)
+	} else {
+		command.Stdout = stdout
+	}
+	command.Stderr = io.MultiWriter(c.stderr, c.logWriter)
+
+	fmt.Fprintf(c.logWriter, "running bosh %s\n", subcommand)
+	c.events.SubprocessStarted("bosh", subcommand)
+	started := time.Now()
+
+	err := helpers.RunCommand(ctx, command)
+	c.events.SubprocessExited("bosh", subcommand, time.Since(started), err)
+	if err != nil {
+		fmt.Fprintf(c.logWriter, "bosh %s failed: %s\n", subcommand, err)
+		return err
+	}
 
-	return command.Run()
+	fmt.Fprintf(c.logWriter, "bosh %s succeeded\n", subcommand)
+	return nil
 }
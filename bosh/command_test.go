@@ -2,6 +2,7 @@ package bosh_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,8 +22,10 @@ import (
 
 var _ = Describe("Cmd", func() {
 	var (
-		stdout *bytes.Buffer
-		stderr *bytes.Buffer
+		stdout    *bytes.Buffer
+		stderr    *bytes.Buffer
+		logWriter *bytes.Buffer
+		events    *fakes.SubprocessEvents
 
 		cmd bosh.Cmd
 
@@ -54,7 +58,11 @@ var _ = Describe("Cmd", func() {
 		stdout = bytes.NewBuffer([]byte{})
 		stderr = bytes.NewBuffer([]byte{})
 
-		cmd = bosh.NewCmd(stderr)
+		logWriter = bytes.NewBuffer([]byte{})
+
+		events = &fakes.SubprocessEvents{}
+
+		cmd = bosh.NewCmd(stderr, logWriter, events)
 
 		fakeBOSHBackendServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 			switch request.URL.Path {
@@ -96,7 +104,7 @@ var _ = Describe("Cmd", func() {
 	})
 
 	It("runs bosh with args", func() {
-		err := cmd.Run(stdout, tempDir, []string{"create-env", "some-arg"})
+		err := cmd.Run(context.Background(), stdout, tempDir, []string{"create-env", "some-arg"})
 		Expect(err).NotTo(HaveOccurred())
 
 		boshArgsMutex.Lock()
@@ -107,6 +115,55 @@ var _ = Describe("Cmd", func() {
 		Expect(stdout).To(ContainSubstring("create-env some-arg"))
 	})
 
+	It("writes the create-env output to the log writer", func() {
+		err := cmd.Run(context.Background(), stdout, tempDir, []string{"create-env", "some-arg"})
+		Expect(err).NotTo(HaveOccurred())
+
+		logContents := logWriter.String()
+		Expect(logContents).To(HavePrefix("running bosh create-env\n"))
+		Expect(logContents).To(ContainSubstring("create-env some-arg"))
+		Expect(logContents).To(HaveSuffix("bosh create-env succeeded\n"))
+	})
+
+	It("reports the start and exit of the subprocess", func() {
+		err := cmd.Run(context.Background(), stdout, tempDir, []string{"create-env", "some-arg"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(events.SubprocessStartedCall.CallCount).To(Equal(1))
+		Expect(events.SubprocessStartedCall.Receives.Name).To(Equal("bosh"))
+		Expect(events.SubprocessStartedCall.Receives.Subcommand).To(Equal("create-env"))
+
+		Expect(events.SubprocessExitedCall.CallCount).To(Equal(1))
+		Expect(events.SubprocessExitedCall.Receives.Name).To(Equal("bosh"))
+		Expect(events.SubprocessExitedCall.Receives.Subcommand).To(Equal("create-env"))
+		Expect(events.SubprocessExitedCall.Receives.Error).NotTo(HaveOccurred())
+	})
+
+	It("does not write the output of other commands to the log writer", func() {
+		err := cmd.Run(context.Background(), stdout, tempDir, []string{"interpolate", "some-arg"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logWriter.String()).To(Equal("running bosh interpolate\nbosh interpolate succeeded\n"))
+	})
+
+	Context("when the context is cancelled", func() {
+		It("interrupts bosh and waits for it to write its state", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				defer GinkgoRecover()
+				Eventually(filepath.Join(tempDir, "waiting-for-interrupt")).Should(BeAnExistingFile())
+				cancel()
+			}()
+
+			err := cmd.Run(ctx, stdout, tempDir, []string{"create-env", "wait-for-interrupt"})
+			Expect(err).To(Equal(context.Canceled))
+
+			boshState, err := ioutil.ReadFile(filepath.Join(tempDir, "state.json"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(boshState)).To(Equal(`{"partial":"interrupted-bosh-state"}`))
+		})
+	})
+
 	Context("failure case", func() {
 		BeforeEach(func() {
 			setFastFailBOSH(true)
@@ -117,9 +174,11 @@ var _ = Describe("Cmd", func() {
 		})
 
 		It("returns an error when bosh fails", func() {
-			err := cmd.Run(stdout, tempDir, []string{"create-env"})
+			err := cmd.Run(context.Background(), stdout, tempDir, []string{"create-env"})
 			Expect(err).To(MatchError("exit status 1"))
 			Expect(stderr.String()).To(ContainSubstring("failed to bosh"))
+			Expect(logWriter.String()).To(ContainSubstring("failed to bosh"))
+			Expect(logWriter.String()).To(HaveSuffix("bosh create-env failed: exit status 1\n"))
 		})
 	})
 })
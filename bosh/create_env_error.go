@@ -2,12 +2,14 @@ package bosh
 
 type CreateEnvError struct {
 	boshState map[string]interface{}
+	output    string
 	err       error
 }
 
-func NewCreateEnvError(boshState map[string]interface{}, err error) CreateEnvError {
+func NewCreateEnvError(boshState map[string]interface{}, output string, err error) CreateEnvError {
 	return CreateEnvError{
 		boshState: boshState,
+		output:    output,
 		err:       err,
 	}
 }
@@ -19,3 +21,7 @@ func (b CreateEnvError) Error() string {
 func (b CreateEnvError) BOSHState() map[string]interface{} {
 	return b.boshState
 }
+
+func (b CreateEnvError) Output() string {
+	return b.output
+}
@@ -0,0 +1,233 @@
+package bosh
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// agentCredentials are the credentials that the agents on deployed VMs use to
+// reach the director. The agents only learn new ones when their VMs are
+// recreated.
+var agentCredentials = map[string]bool{
+	"nats_password":            true,
+	"blobstore_agent_password": true,
+	"mbus_bootstrap_password":  true,
+}
+
+type caGenerator interface {
+	GenerateCA(commonName string) (ssl.CAData, error)
+}
+
+// CredentialRotator removes entries from the director vars-store so that the
+// next bosh create-env generates new values for them.
+type CredentialRotator struct {
+	caGenerator caGenerator
+}
+
+func NewCredentialRotator(caGenerator caGenerator) CredentialRotator {
+	return CredentialRotator{
+		caGenerator: caGenerator,
+	}
+}
+
+// RotateCredentials removes every password and every certificate that is not
+// a certificate authority. The certificates are issued again by the same
+// certificate authorities, so deployed VMs keep trusting the director. The
+// credentials of the agents are only removed with rotateAgentCredentials.
+func (r CredentialRotator) RotateCredentials(state storage.State, rotateAgentCredentials bool) (storage.State, error) {
+	variables, err := unmarshalVariables(state.BOSH.Variables)
+	if err != nil {
+		return state, err
+	}
+
+	for name, value := range variables {
+		if agentCredentials[name] && !rotateAgentCredentials {
+			continue
+		}
+
+		switch value.(type) {
+		case string:
+			delete(variables, name)
+		case map[interface{}]interface{}:
+			certificate, ok, err := variableCertificate(value)
+			if err != nil {
+				return state, fmt.Errorf("failed to parse the certificate %s: %s", name, err)
+			}
+
+			if ok && !certificate.IsCA {
+				delete(variables, name)
+			}
+		}
+	}
+
+	state.BOSH.Variables, err = marshalVariables(variables)
+	if err != nil {
+		return state, err
+	}
+
+	return state, nil
+}
+
+// BeginCARotation generates a new certificate authority for every one in the
+// vars-store and adds it to the CAs that the certificates issued by the old
+// one are trusted with. The new certificate authorities are kept in the state
+// until CompleteCARotation.
+func (r CredentialRotator) BeginCARotation(state storage.State) (storage.State, error) {
+	if len(state.BOSH.PendingCAs) > 0 {
+		return state, errors.New("a director CA rotation is already in progress")
+	}
+
+	variables, err := unmarshalVariables(state.BOSH.Variables)
+	if err != nil {
+		return state, err
+	}
+
+	pendingCAs := map[string]storage.PendingCA{}
+	for _, name := range sortedVariableNames(variables) {
+		certificate, ok, err := variableCertificate(variables[name])
+		if err != nil {
+			return state, fmt.Errorf("failed to parse the certificate %s: %s", name, err)
+		}
+
+		if !ok || !certificate.IsCA {
+			continue
+		}
+
+		ca, err := r.caGenerator.GenerateCA(certificate.Subject.CommonName)
+		if err != nil {
+			return state, err
+		}
+
+		pendingCAs[name] = storage.PendingCA{
+			Certificate: string(ca.CA),
+			PrivateKey:  string(ca.PrivateKey),
+		}
+	}
+
+	if len(pendingCAs) == 0 {
+		return state, errors.New("no certificate authorities were found in the director vars-store")
+	}
+
+	for name, pendingCA := range pendingCAs {
+		oldCA := variableField(variables[name], "certificate")
+		for _, value := range variables {
+			ca := variableField(value, "ca")
+			if ca == "" || !strings.Contains(ca, strings.TrimSpace(oldCA)) {
+				continue
+			}
+
+			value.(map[interface{}]interface{})["ca"] = strings.TrimSpace(ca) + "\n" + pendingCA.Certificate
+		}
+	}
+
+	state.BOSH.Variables, err = marshalVariables(variables)
+	if err != nil {
+		return state, err
+	}
+	state.BOSH.PendingCAs = pendingCAs
+
+	return state, nil
+}
+
+// CompleteCARotation replaces the old certificate authorities with the ones
+// generated by BeginCARotation and removes every certificate they issued, so
+// that they are issued again by the new ones and no longer trust the old ones.
+func (r CredentialRotator) CompleteCARotation(state storage.State) (storage.State, error) {
+	if len(state.BOSH.PendingCAs) == 0 {
+		return state, errors.New("no director CA rotation is in progress")
+	}
+
+	variables, err := unmarshalVariables(state.BOSH.Variables)
+	if err != nil {
+		return state, err
+	}
+
+	for name, pendingCA := range state.BOSH.PendingCAs {
+		oldCA := strings.TrimSpace(variableField(variables[name], "certificate"))
+		for certificateName, value := range variables {
+			if certificateName == name {
+				continue
+			}
+
+			if oldCA != "" && strings.Contains(variableField(value, "ca"), oldCA) {
+				delete(variables, certificateName)
+			}
+		}
+
+		variables[name] = map[interface{}]interface{}{
+			"ca":          pendingCA.Certificate,
+			"certificate": pendingCA.Certificate,
+			"private_key": pendingCA.PrivateKey,
+		}
+	}
+
+	state.BOSH.Variables, err = marshalVariables(variables)
+	if err != nil {
+		return state, err
+	}
+	state.BOSH.PendingCAs = nil
+
+	return state, nil
+}
+
+func unmarshalVariables(vars string) (map[string]interface{}, error) {
+	variables := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(vars), &variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the director vars-store: %s", err)
+	}
+
+	return variables, nil
+}
+
+func marshalVariables(variables map[string]interface{}) (string, error) {
+	contents, err := yaml.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
+
+func sortedVariableNames(variables map[string]interface{}) []string {
+	var names []string
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func variableField(value interface{}, field string) string {
+	fields, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return ""
+	}
+
+	contents, _ := fields[field].(string)
+	return contents
+}
+
+func variableCertificate(value interface{}) (*x509.Certificate, bool, error) {
+	block, _ := pem.Decode([]byte(variableField(value, "certificate")))
+	if block == nil {
+		return nil, false, nil
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return certificate, true, nil
+}
@@ -0,0 +1,259 @@
+package bosh_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CredentialRotator", func() {
+	var (
+		caGenerator *fakes.CertificateGenerator
+		rotator     bosh.CredentialRotator
+
+		oldCA string
+		newCA string
+		state storage.State
+	)
+
+	BeforeEach(func() {
+		caGenerator = &fakes.CertificateGenerator{}
+		rotator = bosh.NewCredentialRotator(caGenerator)
+
+		oldCA = testCertificate("default-ca", true)
+		newCA = testCertificate("default-ca", true)
+		caGenerator.GenerateCACall.Returns.CAData = ssl.CAData{
+			CA:         []byte(newCA),
+			PrivateKey: []byte("some-new-ca-key"),
+		}
+
+		state = storage.State{
+			BOSH: storage.BOSH{
+				Variables: marshalTestVariables(map[string]interface{}{
+					"admin_password": "some-admin-password",
+					"default_ca": map[string]string{
+						"ca":          oldCA,
+						"certificate": oldCA,
+						"private_key": "some-old-ca-key",
+					},
+					"director_ssl": map[string]string{
+						"ca":          oldCA,
+						"certificate": testCertificate("10.0.0.6", false),
+						"private_key": "some-director-key",
+					},
+					"jumpbox_ssh": map[string]string{
+						"private_key": "some-ssh-key",
+						"public_key":  "some-ssh-public-key",
+					},
+				}),
+			},
+		}
+	})
+
+	Describe("RotateCredentials", func() {
+		It("removes passwords and certificates that are not CAs", func() {
+			state, err := rotator.RotateCredentials(state, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			variables := unmarshalTestVariables(state.BOSH.Variables)
+			Expect(variables).NotTo(HaveKey("admin_password"))
+			Expect(variables).NotTo(HaveKey("director_ssl"))
+			Expect(variables).To(HaveKey("jumpbox_ssh"))
+			Expect(variables["default_ca"]).To(HaveKeyWithValue("certificate", oldCA))
+		})
+
+		It("returns an error when the vars-store cannot be parsed", func() {
+			state.BOSH.Variables = "- some-item"
+			_, err := rotator.RotateCredentials(state, false)
+			Expect(err).To(MatchError(ContainSubstring("failed to read the director vars-store")))
+		})
+
+		Context("with the vars-store of a bosh-deployment director", func() {
+			BeforeEach(func() {
+				certificate := func(commonName string) map[string]string {
+					return map[string]string{
+						"ca":          oldCA,
+						"certificate": testCertificate(commonName, false),
+						"private_key": "some-" + commonName + "-key",
+					}
+				}
+
+				state.BOSH.Variables = marshalTestVariables(map[string]interface{}{
+					"admin_password":              "some-admin-password",
+					"blobstore_agent_password":    "some-blobstore-agent-password",
+					"blobstore_director_password": "some-blobstore-director-password",
+					"hm_password":                 "some-hm-password",
+					"mbus_bootstrap_password":     "some-mbus-bootstrap-password",
+					"nats_password":               "some-nats-password",
+					"postgres_password":           "some-postgres-password",
+					"default_ca": map[string]string{
+						"ca":          oldCA,
+						"certificate": oldCA,
+						"private_key": "some-old-ca-key",
+					},
+					"director_ssl":              certificate("10.0.0.6"),
+					"mbus_bootstrap_ssl":        certificate("10.0.0.6"),
+					"nats_server_tls":           certificate("default.nats.bosh-internal"),
+					"nats_clients_director_tls": certificate("default.director.bosh-internal"),
+				})
+			})
+
+			It("keeps the credentials of the agents on deployed VMs", func() {
+				state, err := rotator.RotateCredentials(state, false)
+				Expect(err).NotTo(HaveOccurred())
+
+				variables := unmarshalTestVariables(state.BOSH.Variables)
+				Expect(variables).To(HaveKeyWithValue("nats_password", "some-nats-password"))
+				Expect(variables).To(HaveKeyWithValue("blobstore_agent_password", "some-blobstore-agent-password"))
+				Expect(variables).To(HaveKeyWithValue("mbus_bootstrap_password", "some-mbus-bootstrap-password"))
+				Expect(variables).To(HaveKey("default_ca"))
+
+				for _, name := range []string{
+					"admin_password", "blobstore_director_password", "hm_password", "postgres_password",
+					"director_ssl", "mbus_bootstrap_ssl", "nats_server_tls", "nats_clients_director_tls",
+				} {
+					Expect(variables).NotTo(HaveKey(name))
+				}
+			})
+
+			It("removes the credentials of the agents when asked to", func() {
+				state, err := rotator.RotateCredentials(state, true)
+				Expect(err).NotTo(HaveOccurred())
+
+				variables := unmarshalTestVariables(state.BOSH.Variables)
+				Expect(variables).NotTo(HaveKey("nats_password"))
+				Expect(variables).NotTo(HaveKey("blobstore_agent_password"))
+				Expect(variables).NotTo(HaveKey("mbus_bootstrap_password"))
+				Expect(variables).To(HaveKey("default_ca"))
+			})
+		})
+	})
+
+	Describe("BeginCARotation", func() {
+		It("trusts the new CA alongside the old one without reissuing certificates", func() {
+			state, err := rotator.BeginCARotation(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(caGenerator.GenerateCACall.Receives.CommonName).To(Equal("default-ca"))
+			Expect(state.BOSH.PendingCAs).To(Equal(map[string]storage.PendingCA{
+				"default_ca": {Certificate: newCA, PrivateKey: "some-new-ca-key"},
+			}))
+
+			variables := unmarshalTestVariables(state.BOSH.Variables)
+			Expect(variables["director_ssl"]["ca"]).To(ContainSubstring(oldCA))
+			Expect(variables["director_ssl"]["ca"]).To(ContainSubstring(newCA))
+			Expect(variables["default_ca"]["certificate"]).To(Equal(oldCA))
+			Expect(variables["default_ca"]["private_key"]).To(Equal("some-old-ca-key"))
+			Expect(variables).To(HaveKey("admin_password"))
+		})
+
+		It("returns an error when a rotation is already in progress", func() {
+			state.BOSH.PendingCAs = map[string]storage.PendingCA{"default_ca": {}}
+			_, err := rotator.BeginCARotation(state)
+			Expect(err).To(MatchError("a director CA rotation is already in progress"))
+		})
+
+		It("returns an error when there are no CAs", func() {
+			state.BOSH.Variables = "admin_password: some-admin-password\n"
+			_, err := rotator.BeginCARotation(state)
+			Expect(err).To(MatchError("no certificate authorities were found in the director vars-store"))
+		})
+
+		It("returns an error when the CA cannot be generated", func() {
+			caGenerator.GenerateCACall.Returns.Error = errors.New("failed to generate")
+			_, err := rotator.BeginCARotation(state)
+			Expect(err).To(MatchError("failed to generate"))
+		})
+	})
+
+	Describe("CompleteCARotation", func() {
+		BeforeEach(func() {
+			var err error
+			state, err = rotator.BeginCARotation(state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("replaces the old CA and removes the certificates it issued", func() {
+			state, err := rotator.CompleteCARotation(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(state.BOSH.PendingCAs).To(BeNil())
+
+			variables := unmarshalTestVariables(state.BOSH.Variables)
+			Expect(variables).NotTo(HaveKey("director_ssl"))
+			Expect(variables["default_ca"]).To(Equal(map[string]string{
+				"ca":          newCA,
+				"certificate": newCA,
+				"private_key": "some-new-ca-key",
+			}))
+			Expect(variables).To(HaveKey("admin_password"))
+			Expect(variables).To(HaveKey("jumpbox_ssh"))
+		})
+
+		It("returns an error when no rotation is in progress", func() {
+			state.BOSH.PendingCAs = nil
+			_, err := rotator.CompleteCARotation(state)
+			Expect(err).To(MatchError("no director CA rotation is in progress"))
+		})
+	})
+})
+
+func testCertificate(commonName string, isCA bool) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func marshalTestVariables(variables map[string]interface{}) string {
+	contents, err := yaml.Marshal(variables)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(contents)
+}
+
+func unmarshalTestVariables(contents string) map[string]map[string]string {
+	var variables map[string]interface{}
+	Expect(yaml.Unmarshal([]byte(contents), &variables)).To(Succeed())
+
+	typed := map[string]map[string]string{}
+	for name, value := range variables {
+		fields := map[string]string{}
+		if mapping, ok := value.(map[interface{}]interface{}); ok {
+			for key, field := range mapping {
+				fields[key.(string)] = field.(string)
+			}
+		}
+		typed[name] = fields
+	}
+
+	return typed
+}
@@ -2,12 +2,14 @@ package bosh
 
 type DeleteEnvError struct {
 	boshState map[string]interface{}
+	output    string
 	err       error
 }
 
-func NewDeleteEnvError(boshState map[string]interface{}, err error) DeleteEnvError {
+func NewDeleteEnvError(boshState map[string]interface{}, output string, err error) DeleteEnvError {
 	return DeleteEnvError{
 		boshState: boshState,
+		output:    output,
 		err:       err,
 	}
 }
@@ -19,3 +21,7 @@ func (b DeleteEnvError) Error() string {
 func (b DeleteEnvError) BOSHState() map[string]interface{} {
 	return b.boshState
 }
+
+func (b DeleteEnvError) Output() string {
+	return b.output
+}
@@ -2,14 +2,18 @@ package bosh
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	yaml "gopkg.in/yaml.v2"
 )
 
 type Executor struct {
@@ -19,6 +23,7 @@ type Executor struct {
 	unmarshalJSON func([]byte, interface{}) error
 	marshalJSON   func(interface{}) ([]byte, error)
 	writeFile     func(string, []byte, os.FileMode) error
+	timeout       time.Duration
 }
 
 type InterpolateInput struct {
@@ -28,6 +33,7 @@ type InterpolateInput struct {
 	BOSHState             map[string]interface{}
 	Variables             string
 	OpsFile               string
+	IAMInstanceProfile    bool
 }
 
 type InterpolateOutput struct {
@@ -44,6 +50,14 @@ type CreateEnvInput struct {
 	Manifest  string
 	Variables string
 	State     map[string]interface{}
+	// CPICredentials is the access key of bosh create-env when the manifest
+	// has none, because the director uses an IAM instance profile.
+	CPICredentials *CPICredentials
+}
+
+type CPICredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
 }
 
 type CreateEnvOutput struct {
@@ -51,20 +65,22 @@ type CreateEnvOutput struct {
 }
 
 type DeleteEnvInput struct {
-	Manifest  string
-	Variables string
-	State     map[string]interface{}
+	Manifest       string
+	Variables      string
+	State          map[string]interface{}
+	CPICredentials *CPICredentials
 }
 
 type command interface {
-	Run(stdout io.Writer, workingDirectory string, args []string) error
+	Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error
 }
 
 const VERSION_DEV_BUILD = "[DEV BUILD]"
 
 func NewExecutor(cmd command, tempDir func(string, string) (string, error), readFile func(string) ([]byte, error),
 	unmarshalJSON func([]byte, interface{}) error,
-	marshalJSON func(interface{}) ([]byte, error), writeFile func(string, []byte, os.FileMode) error) Executor {
+	marshalJSON func(interface{}) ([]byte, error), writeFile func(string, []byte, os.FileMode) error,
+	timeout time.Duration) Executor {
 	return Executor{
 		command:       cmd,
 		tempDir:       tempDir,
@@ -72,6 +88,7 @@ func NewExecutor(cmd command, tempDir func(string, string) (string, error), read
 		unmarshalJSON: unmarshalJSON,
 		marshalJSON:   marshalJSON,
 		writeFile:     writeFile,
+		timeout:       timeout,
 	}
 }
 
@@ -127,9 +144,9 @@ func (e Executor) JumpboxInterpolate(interpolateInput InterpolateInput) (Jumpbox
 	}
 
 	buffer := bytes.NewBuffer([]byte{})
-	err = e.command.Run(buffer, tempDir, args)
+	err = e.command.Run(context.Background(), buffer, tempDir, args)
 	if err != nil {
-		return JumpboxInterpolateOutput{}, err
+		return JumpboxInterpolateOutput{}, NewInterpolateError(storage.JumpboxPhase, buffer.String(), err)
 	}
 
 	varsStore, err := e.readFile(variablesPath)
@@ -192,18 +209,23 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 		return InterpolateOutput{}, err
 	}
 
-	var args []string
+	args := []string{
+		"interpolate", boshManifestPath,
+		"--var-errs",
+		"--var-errs-unused",
+		"-o", cpiOpsFilePath,
+	}
 
-	if interpolateInput.JumpboxDeploymentVars != "" {
-		args = []string{
-			"interpolate", boshManifestPath,
-			"--var-errs",
-			"--var-errs-unused",
-			"-o", cpiOpsFilePath,
-			"--vars-store", variablesPath,
-			"--vars-file", deploymentVarsPath,
+	if interpolateInput.IAMInstanceProfile {
+		iamInstanceProfileOpsFilePath := filepath.Join(tempDir, "iam-instance-profile.yml")
+		err = e.writeFile(iamInstanceProfileOpsFilePath, []byte(iamInstanceProfileOpsFile), os.ModePerm)
+		if err != nil {
+			return InterpolateOutput{}, err
 		}
-	} else {
+		args = append(args, "-o", iamInstanceProfileOpsFilePath)
+	}
+
+	if interpolateInput.JumpboxDeploymentVars == "" {
 		jumpboxUserOpsFilePath := filepath.Join(tempDir, "jumpbox-user.yml")
 		jumpboxUserOpsFileContents, err := Asset("vendor/github.com/cloudfoundry/bosh-deployment/jumpbox-user.yml")
 		if err != nil {
@@ -236,22 +258,21 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 			return InterpolateOutput{}, err
 		}
 
-		args = []string{
-			"interpolate", boshManifestPath,
-			"--var-errs",
-			"--var-errs-unused",
-			"-o", cpiOpsFilePath,
+		args = append(args,
 			"-o", jumpboxUserOpsFilePath,
 			"-o", externalIPNotRecommendedOpsFilePath,
-			"--vars-store", variablesPath,
-			"--vars-file", deploymentVarsPath,
-		}
+		)
 	}
 
+	args = append(args,
+		"--vars-store", variablesPath,
+		"--vars-file", deploymentVarsPath,
+	)
+
 	buffer := bytes.NewBuffer([]byte{})
-	err = e.command.Run(buffer, tempDir, args)
+	err = e.command.Run(context.Background(), buffer, tempDir, args)
 	if err != nil {
-		return InterpolateOutput{}, err
+		return InterpolateOutput{}, NewInterpolateError(storage.CreateEnvPhase, buffer.String(), err)
 	}
 
 	if interpolateInput.OpsFile != "" {
@@ -270,9 +291,9 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 		}
 
 		buffer = bytes.NewBuffer([]byte{})
-		err = e.command.Run(buffer, tempDir, args)
+		err = e.command.Run(context.Background(), buffer, tempDir, args)
 		if err != nil {
-			return InterpolateOutput{}, err
+			return InterpolateOutput{}, NewInterpolateError(storage.CreateEnvPhase, buffer.String(), err)
 		}
 	}
 
@@ -287,7 +308,7 @@ func (e Executor) Interpolate(interpolateInput InterpolateInput) (InterpolateOut
 	}, nil
 }
 
-func (e Executor) CreateEnv(createEnvInput CreateEnvInput) (CreateEnvOutput, error) {
+func (e Executor) CreateEnv(ctx context.Context, createEnvInput CreateEnvInput) (CreateEnvOutput, error) {
 	tempDir, err := e.writePreviousFiles(createEnvInput.State, createEnvInput.Variables, createEnvInput.Manifest)
 	if err != nil {
 		return CreateEnvOutput{}, err
@@ -303,7 +324,14 @@ func (e Executor) CreateEnv(createEnvInput CreateEnvInput) (CreateEnvOutput, err
 		"--state", statePath,
 	}
 
-	err = e.command.Run(os.Stdout, tempDir, args)
+	credentialsArgs, err := e.writeCPICredentials(tempDir, createEnvInput.CPICredentials)
+	if err != nil {
+		return CreateEnvOutput{}, err
+	}
+	args = append(args, credentialsArgs...)
+
+	output := bytes.NewBuffer([]byte{})
+	err = e.run(ctx, storage.CreateEnvPhase, io.MultiWriter(os.Stdout, output), tempDir, args)
 	if err != nil {
 		state, readErr := e.readBOSHState(statePath)
 		if readErr != nil {
@@ -313,7 +341,7 @@ func (e Executor) CreateEnv(createEnvInput CreateEnvInput) (CreateEnvOutput, err
 			return CreateEnvOutput{}, errorList
 		}
 
-		return CreateEnvOutput{}, NewCreateEnvError(state, err)
+		return CreateEnvOutput{}, NewCreateEnvError(state, output.String(), err)
 	}
 
 	state, err := e.readBOSHState(statePath)
@@ -341,7 +369,7 @@ func (e Executor) readBOSHState(statePath string) (map[string]interface{}, error
 	return state, nil
 }
 
-func (e Executor) DeleteEnv(deleteEnvInput DeleteEnvInput) error {
+func (e Executor) DeleteEnv(ctx context.Context, deleteEnvInput DeleteEnvInput) error {
 	tempDir, err := e.writePreviousFiles(deleteEnvInput.State, deleteEnvInput.Variables, deleteEnvInput.Manifest)
 	if err != nil {
 		return err
@@ -357,7 +385,14 @@ func (e Executor) DeleteEnv(deleteEnvInput DeleteEnvInput) error {
 		"--state", statePath,
 	}
 
-	err = e.command.Run(os.Stdout, tempDir, args)
+	credentialsArgs, err := e.writeCPICredentials(tempDir, deleteEnvInput.CPICredentials)
+	if err != nil {
+		return err
+	}
+	args = append(args, credentialsArgs...)
+
+	output := bytes.NewBuffer([]byte{})
+	err = e.run(ctx, storage.DeleteEnvPhase, io.MultiWriter(os.Stdout, output), tempDir, args)
 	if err != nil {
 		state, readErr := e.readBOSHState(statePath)
 		if readErr != nil {
@@ -366,12 +401,53 @@ func (e Executor) DeleteEnv(deleteEnvInput DeleteEnvInput) error {
 			errorList.Add(readErr)
 			return errorList
 		}
-		return NewDeleteEnvError(state, err)
+		return NewDeleteEnvError(state, output.String(), err)
 	}
 
 	return nil
 }
 
+func (e Executor) writeCPICredentials(tempDir string, credentials *CPICredentials) ([]string, error) {
+	if credentials == nil {
+		return nil, nil
+	}
+
+	opsFilePath := filepath.Join(tempDir, "cpi-credentials.yml")
+	err := e.writeFile(opsFilePath, []byte(cpiCredentialsOpsFile), os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	varsContents, err := yaml.Marshal(map[string]string{
+		"access_key_id":     credentials.AccessKeyID,
+		"secret_access_key": credentials.SecretAccessKey,
+	})
+	if err != nil {
+		//not tested
+		return nil, err
+	}
+
+	varsPath := filepath.Join(tempDir, "cpi-credentials-vars.yml")
+	err = e.writeFile(varsPath, varsContents, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{"-o", opsFilePath, "--vars-file", varsPath}, nil
+}
+
+func (e Executor) run(ctx context.Context, phase string, stdout io.Writer, workingDirectory string, args []string) error {
+	runCtx, cancel := helpers.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	err := e.command.Run(runCtx, stdout, workingDirectory, args)
+	if err != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return helpers.NewTimeoutError(phase, e.timeout)
+	}
+
+	return err
+}
+
 func (e Executor) Version() (string, error) {
 	tempDir, err := e.tempDir("", "")
 	if err != nil {
@@ -381,7 +457,7 @@ func (e Executor) Version() (string, error) {
 	args := []string{"-v"}
 
 	buffer := bytes.NewBuffer([]byte{})
-	err = e.command.Run(buffer, tempDir, args)
+	err = e.command.Run(context.Background(), buffer, tempDir, args)
 	if err != nil {
 		return "", err
 	}
@@ -1,15 +1,18 @@
 package bosh_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/pivotal-cf-experimental/gomegamatchers"
 
 	. "github.com/onsi/ginkgo"
@@ -93,7 +96,7 @@ gcp_credentials_json: 'some-credential-json'`,
 				OpsFile:   "some-ops-file",
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 		})
 
 		AfterEach(func() {
@@ -101,7 +104,7 @@ gcp_credentials_json: 'some-credential-json'`,
 		})
 
 		DescribeTable("generates a bosh manifest", func(interpolateInputFunc func() bosh.InterpolateInput) {
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 				stdout.Write([]byte("some-manifest"))
 				return nil
 			}
@@ -122,7 +125,7 @@ gcp_credentials_json: 'some-credential-json'`,
 				"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 				"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir)})
 
-			_, _, args := cmd.RunArgsForCall(0)
+			_, _, _, args := cmd.RunArgsForCall(0)
 			Expect(args).To(Equal(expectedArgs))
 
 			expectedArgs = append([]string{
@@ -132,7 +135,7 @@ gcp_credentials_json: 'some-credential-json'`,
 				"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 				"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir)})
 
-			_, _, args = cmd.RunArgsForCall(1)
+			_, _, _, args = cmd.RunArgsForCall(1)
 			Expect(args).To(Equal(expectedArgs))
 
 			Expect(interpolateOutput.Manifest).To(Equal("some-manifest"))
@@ -146,6 +149,49 @@ gcp_credentials_json: 'some-credential-json'`,
 			}),
 		)
 
+		Context("when the director uses an IAM instance profile", func() {
+			BeforeEach(func() {
+				awsInterpolateInput.IAMInstanceProfile = true
+			})
+
+			It("applies the iam instance profile ops file after the cpi ops file", func() {
+				_, err := executor.Interpolate(awsInterpolateInput)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, args := cmd.RunArgsForCall(0)
+				Expect(args).To(Equal([]string{
+					"interpolate", fmt.Sprintf("%s/bosh.yml", tempDir),
+					"--var-errs",
+					"--var-errs-unused",
+					"-o", fmt.Sprintf("%s/cpi.yml", tempDir),
+					"-o", fmt.Sprintf("%s/iam-instance-profile.yml", tempDir),
+					"-o", fmt.Sprintf("%s/jumpbox-user.yml", tempDir),
+					"-o", fmt.Sprintf("%s/external-ip-not-recommended.yml", tempDir),
+					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
+					"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir),
+				}))
+
+				opsFile, err := ioutil.ReadFile(fmt.Sprintf("%s/iam-instance-profile.yml", tempDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(opsFile)).To(ContainSubstring("value: ((iam_instance_profile))"))
+				Expect(string(opsFile)).To(ContainSubstring("path: /instance_groups/name=bosh/properties/aws/access_key_id"))
+				Expect(string(opsFile)).To(ContainSubstring("path: /cloud_provider/properties/aws/access_key_id"))
+			})
+
+			It("returns an error when the ops file cannot be written", func() {
+				writeFileFunc := func(path string, contents []byte, fileMode os.FileMode) error {
+					if path == fmt.Sprintf("%s/iam-instance-profile.yml", tempDir) {
+						return errors.New("failed to write iam instance profile ops file")
+					}
+					return nil
+				}
+
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
+				_, err := executor.Interpolate(awsInterpolateInput)
+				Expect(err).To(MatchError("failed to write iam instance profile ops file"))
+			})
+		})
+
 		Context("when there are jumpbox deployment vars", func() {
 			It("interpolates the jumpbox and bosh manifests", func() {
 				interpolateInput := bosh.InterpolateInput{
@@ -177,7 +223,7 @@ gcp_credentials_json: 'some-credential-json'`,
 					Variables: variablesYMLContents,
 				}
 
-				cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+				cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 					stdout.Write([]byte("some-manifest"))
 					return nil
 				}
@@ -195,7 +241,7 @@ gcp_credentials_json: 'some-credential-json'`,
 					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 					"--vars-file", fmt.Sprintf("%s/jumpbox-deployment-vars.yml", tempDir)})
 
-				_, _, args := cmd.RunArgsForCall(0)
+				_, _, _, args := cmd.RunArgsForCall(0)
 				Expect(args).To(Equal(expectedArgs))
 
 				Expect(jumpboxInterpolateOutput.Manifest).To(Equal("some-manifest"))
@@ -215,7 +261,7 @@ gcp_credentials_json: 'some-credential-json'`,
 					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 					"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir)})
 
-				_, _, args = cmd.RunArgsForCall(1)
+				_, _, _, args = cmd.RunArgsForCall(1)
 				Expect(args).To(Equal(expectedArgs))
 
 				Expect(interpolateOutput.Manifest).To(Equal("some-manifest"))
@@ -275,7 +321,7 @@ networks
 `
 
 				writtenManifest := []byte{}
-				cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+				cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 					for _, arg := range args {
 						if arg == fmt.Sprintf("%s/user-ops-file.yml", tempDir) {
 							var err error
@@ -307,7 +353,7 @@ networks
 					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 					"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir)})
 
-				_, _, args := cmd.RunArgsForCall(0)
+				_, _, _, args := cmd.RunArgsForCall(0)
 				Expect(args).To(Equal(expectedArgs))
 
 				expectedArgsWithUserOpsfile := append([]string{
@@ -317,7 +363,7 @@ networks
 					"--vars-store", fmt.Sprintf("%s/variables.yml", tempDir),
 					"--vars-file", fmt.Sprintf("%s/deployment-vars.yml", tempDir)})
 
-				_, _, args = cmd.RunArgsForCall(1)
+				_, _, _, args = cmd.RunArgsForCall(1)
 				Expect(args).To(Equal(expectedArgsWithUserOpsfile))
 
 				opsFileContents, err := ioutil.ReadFile(fmt.Sprintf("%s/user-ops-file.yml", tempDir))
@@ -331,7 +377,7 @@ networks
 		})
 
 		It("does not pass in false to run command on interpolate", func() {
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 			_, err := executor.Interpolate(awsInterpolateInput)
 			Expect(err).NotTo(HaveOccurred())
 		})
@@ -342,7 +388,7 @@ networks
 					return "", errors.New("failed to create temp dir")
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 				_, err := executor.Interpolate(gcpInterpolateInput)
 				Expect(err).To(MatchError("failed to create temp dir"))
 			})
@@ -355,7 +401,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(gcpInterpolateInput)
 				Expect(err).To(MatchError("failed to write variables"))
 			})
@@ -368,7 +414,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{})
 				Expect(err).To(MatchError("failed to write user ops file"))
 			})
@@ -381,7 +427,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{})
 				Expect(err).To(MatchError("failed to write bosh manifest"))
 			})
@@ -394,7 +440,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "gcp",
 				})
@@ -409,7 +455,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "gcp",
 				})
@@ -424,7 +470,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "gcp",
 				})
@@ -439,7 +485,7 @@ networks
 					return nil
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "aws",
 				})
@@ -449,17 +495,34 @@ networks
 			It("fails when trying to run command", func() {
 				cmd.RunReturnsOnCall(0, errors.New("failed to run command"))
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "aws",
 				})
 				Expect(err).To(MatchError("failed to run command"))
 			})
 
+			It("returns an interpolate error with the create-env phase and command output", func() {
+				cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+					stdout.Write([]byte("Expected to find variables"))
+					return errors.New("failed to run command")
+				}
+
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
+				_, err := executor.Interpolate(bosh.InterpolateInput{
+					IAAS: "aws",
+				})
+
+				interpolateError, ok := err.(bosh.InterpolateError)
+				Expect(ok).To(BeTrue())
+				Expect(interpolateError.Phase()).To(Equal("create-env"))
+				Expect(interpolateError.Output()).To(Equal("Expected to find variables"))
+			})
+
 			It("fails when trying to run the command to interpolate with the user opsfile", func() {
 				cmd.RunReturnsOnCall(1, errors.New("failed to run command"))
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS:    "aws",
 					OpsFile: "some-ops-file",
@@ -472,7 +535,7 @@ networks
 					return []byte{}, errors.New("failed to read variables file")
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, readFileFunc, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, readFileFunc, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 				_, err := executor.Interpolate(bosh.InterpolateInput{
 					IAAS: "aws",
 				})
@@ -504,7 +567,7 @@ networks
 				return tempDir, nil
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 		})
 
 		It("fails when the temporary directory cannot be created", func() {
@@ -512,7 +575,7 @@ networks
 				return "", errors.New("failed to create temp dir")
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 			err := callback(executor)
 			Expect(err).To(MatchError("failed to create temp dir"))
 		})
@@ -522,7 +585,7 @@ networks
 				return []byte{}, errors.New("failed to marshal state")
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, marshalFunc, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, marshalFunc, ioutil.WriteFile, 0)
 			err := callback(executor)
 			Expect(err).To(MatchError("failed to marshal state"))
 		})
@@ -532,7 +595,7 @@ networks
 				return errors.New("failed to write file")
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFile, 0)
 			err := callback(executor)
 			Expect(err).To(MatchError("failed to write file"))
 		})
@@ -572,7 +635,7 @@ networks
 				return tempDir, nil
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 
 			createEnvInput = bosh.CreateEnvInput{
 				Manifest:  "some-manifest",
@@ -584,7 +647,7 @@ networks
 			variablesPath = fmt.Sprintf("%s/variables.yml", tempDir)
 			statePath = fmt.Sprintf("%s/state.json", tempDir)
 
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 				return ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
 			}
 		})
@@ -594,7 +657,7 @@ networks
 		})
 
 		It("creates a bosh environment", func() {
-			createEnvOutput, err := executor.CreateEnv(createEnvInput)
+			createEnvOutput, err := executor.CreateEnv(context.Background(), createEnvInput)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(tempDirCallCount).To(Equal(1))
@@ -607,8 +670,7 @@ networks
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(variablesContents)).To(Equal("some-variables"))
 
-			writer, dir, args := cmd.RunArgsForCall(0)
-			Expect(writer).To(Equal(os.Stdout))
+			_, _, dir, args := cmd.RunArgsForCall(0)
 			Expect(dir).To(Equal(tempDir))
 			Expect(args).To(Equal([]string{
 				"create-env", manifestPath,
@@ -621,6 +683,85 @@ networks
 			}))
 		})
 
+		Context("when bosh create-env needs an access key the manifest does not have", func() {
+			BeforeEach(func() {
+				createEnvInput.CPICredentials = &bosh.CPICredentials{
+					AccessKeyID:     "some-access-key-id",
+					SecretAccessKey: "some-secret/access+key",
+				}
+			})
+
+			It("gives it to the cpi through an ops file and a vars file", func() {
+				_, err := executor.CreateEnv(context.Background(), createEnvInput)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, args := cmd.RunArgsForCall(0)
+				Expect(args).To(Equal([]string{
+					"create-env", manifestPath,
+					"--vars-store", variablesPath,
+					"--state", statePath,
+					"-o", fmt.Sprintf("%s/cpi-credentials.yml", tempDir),
+					"--vars-file", fmt.Sprintf("%s/cpi-credentials-vars.yml", tempDir),
+				}))
+
+				opsFile, err := ioutil.ReadFile(fmt.Sprintf("%s/cpi-credentials.yml", tempDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(opsFile)).To(ContainSubstring("path: /cloud_provider/properties/aws/access_key_id?"))
+				Expect(string(opsFile)).To(ContainSubstring("path: /cloud_provider/properties/aws/secret_access_key?"))
+
+				vars, err := ioutil.ReadFile(fmt.Sprintf("%s/cpi-credentials-vars.yml", tempDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(vars)).To(gomegamatchers.MatchYAML("access_key_id: some-access-key-id\nsecret_access_key: some-secret/access+key"))
+
+				manifestContents, err := ioutil.ReadFile(manifestPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(manifestContents)).To(Equal("some-manifest"))
+			})
+
+			It("returns an error when the credentials cannot be written", func() {
+				writeFileFunc := func(path string, contents []byte, fileMode os.FileMode) error {
+					if path == fmt.Sprintf("%s/cpi-credentials-vars.yml", tempDir) {
+						return errors.New("failed to write cpi credentials")
+					}
+					return ioutil.WriteFile(path, contents, fileMode)
+				}
+
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, writeFileFunc, 0)
+				_, err := executor.CreateEnv(context.Background(), createEnvInput)
+				Expect(err).To(MatchError("failed to write cpi credentials"))
+				Expect(cmd.RunCallCount()).To(Equal(0))
+			})
+		})
+
+		It("returns the partial bosh state when the command is interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+				cancel()
+				ioutil.WriteFile(statePath, []byte(`{"key": "partial-value"}`), os.ModePerm)
+				return ctx.Err()
+			}
+
+			_, err := executor.CreateEnv(ctx, createEnvInput)
+			Expect(err).To(MatchError(bosh.NewCreateEnvError(map[string]interface{}{
+				"key": "partial-value",
+			}, "", context.Canceled)))
+		})
+
+		It("returns the partial bosh state and an error naming the phase when the command times out", func() {
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, time.Millisecond)
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+				<-ctx.Done()
+				ioutil.WriteFile(statePath, []byte(`{"key": "partial-value"}`), os.ModePerm)
+				return ctx.Err()
+			}
+
+			_, err := executor.CreateEnv(context.Background(), createEnvInput)
+			Expect(err).To(MatchError(bosh.NewCreateEnvError(map[string]interface{}{
+				"key": "partial-value",
+			}, "", helpers.NewTimeoutError("create-env", time.Millisecond))))
+			Expect(err).To(MatchError("create-env timed out after 1ms"))
+		})
+
 		Context("failure cases", func() {
 			createEnvDeleteEnvFailureCases(func(executor bosh.Executor) error {
 				createEnvInput := bosh.CreateEnvInput{
@@ -628,17 +769,18 @@ networks
 					Variables: "some-variables",
 					State:     map[string]interface{}{},
 				}
-				_, err := executor.CreateEnv(createEnvInput)
+				_, err := executor.CreateEnv(context.Background(), createEnvInput)
 				return err
 			})
 
 			Context("when command run fails", func() {
 				BeforeEach(func() {
 					cmd.RunReturns(errors.New("failed to run"))
-					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 
-					cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+					cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 						ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
+						stdout.Write([]byte("some-create-env-output"))
 						return errors.New("failed to run")
 					}
 				})
@@ -646,8 +788,8 @@ networks
 				It("returns a create env error with a valid bosh state", func() {
 					expectedError := bosh.NewCreateEnvError(map[string]interface{}{
 						"key": "value",
-					}, errors.New("failed to run"))
-					_, err := executor.CreateEnv(createEnvInput)
+					}, "some-create-env-output", errors.New("failed to run"))
+					_, err := executor.CreateEnv(context.Background(), createEnvInput)
 					Expect(err).To(MatchError(expectedError))
 				})
 
@@ -657,11 +799,11 @@ networks
 							return []byte{}, errors.New("failed to read file")
 						}
 
-						executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+						executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 					})
 
 					It("returns an error", func() {
-						_, err := executor.CreateEnv(createEnvInput)
+						_, err := executor.CreateEnv(context.Background(), createEnvInput)
 						Expect(err).To(MatchError("the following errors occurred:\nfailed to run,\nfailed to read file"))
 					})
 				})
@@ -672,11 +814,11 @@ networks
 							return errors.New("failed to unmarshal")
 						}
 
-						executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, unmarshalFunc, json.Marshal, ioutil.WriteFile)
+						executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, unmarshalFunc, json.Marshal, ioutil.WriteFile, 0)
 					})
 
 					It("returns an error", func() {
-						_, err := executor.CreateEnv(createEnvInput)
+						_, err := executor.CreateEnv(context.Background(), createEnvInput)
 						Expect(err).To(MatchError("the following errors occurred:\nfailed to run,\nfailed to unmarshal"))
 					})
 				})
@@ -687,8 +829,8 @@ networks
 					return []byte{}, errors.New("failed to read file")
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
-				_, err := executor.CreateEnv(createEnvInput)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
+				_, err := executor.CreateEnv(context.Background(), createEnvInput)
 				Expect(err).To(MatchError("failed to read file"))
 			})
 
@@ -697,8 +839,8 @@ networks
 					return errors.New("failed to unmarshal")
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, unmarshalFunc, json.Marshal, ioutil.WriteFile)
-				_, err := executor.CreateEnv(createEnvInput)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, unmarshalFunc, json.Marshal, ioutil.WriteFile, 0)
+				_, err := executor.CreateEnv(context.Background(), createEnvInput)
 				Expect(err).To(MatchError("failed to unmarshal"))
 			})
 		})
@@ -732,7 +874,7 @@ networks
 				return tempDir, nil
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 
 			deleteEnvInput = bosh.DeleteEnvInput{
 				Manifest:  "some-manifest",
@@ -744,7 +886,7 @@ networks
 			variablesPath = fmt.Sprintf("%s/variables.yml", tempDir)
 			statePath = fmt.Sprintf("%s/state.json", tempDir)
 
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 				return ioutil.WriteFile(statePath, []byte(`{"key": "value"}`), os.ModePerm)
 			}
 		})
@@ -754,7 +896,7 @@ networks
 		})
 
 		It("deletes a bosh environment", func() {
-			err := executor.DeleteEnv(deleteEnvInput)
+			err := executor.DeleteEnv(context.Background(), deleteEnvInput)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(tempDirCallCount).To(Equal(1))
@@ -767,8 +909,7 @@ networks
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(variablesContents)).To(Equal("some-variables"))
 
-			writer, dir, args := cmd.RunArgsForCall(0)
-			Expect(writer).To(Equal(os.Stdout))
+			_, _, dir, args := cmd.RunArgsForCall(0)
 			Expect(dir).To(Equal(tempDir))
 			Expect(args).To(Equal([]string{
 				"delete-env", manifestPath,
@@ -777,6 +918,44 @@ networks
 			}))
 		})
 
+		It("gives bosh delete-env the access key the manifest does not have", func() {
+			deleteEnvInput.CPICredentials = &bosh.CPICredentials{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+			}
+
+			err := executor.DeleteEnv(context.Background(), deleteEnvInput)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, _, args := cmd.RunArgsForCall(0)
+			Expect(args).To(Equal([]string{
+				"delete-env", manifestPath,
+				"--vars-store", variablesPath,
+				"--state", statePath,
+				"-o", fmt.Sprintf("%s/cpi-credentials.yml", tempDir),
+				"--vars-file", fmt.Sprintf("%s/cpi-credentials-vars.yml", tempDir),
+			}))
+
+			vars, err := ioutil.ReadFile(fmt.Sprintf("%s/cpi-credentials-vars.yml", tempDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(vars)).To(gomegamatchers.MatchYAML("access_key_id: some-access-key-id\nsecret_access_key: some-secret-access-key"))
+		})
+
+		It("returns the partial bosh state and an error naming the phase when the command times out", func() {
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, time.Millisecond)
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+				<-ctx.Done()
+				ioutil.WriteFile(statePath, []byte(`{"partial": "state"}`), os.ModePerm)
+				return ctx.Err()
+			}
+
+			err := executor.DeleteEnv(context.Background(), deleteEnvInput)
+			Expect(err).To(MatchError(bosh.NewDeleteEnvError(map[string]interface{}{
+				"partial": "state",
+			}, "", helpers.NewTimeoutError("delete-env", time.Millisecond))))
+			Expect(err).To(MatchError("delete-env timed out after 1ms"))
+		})
+
 		Context("failure cases", func() {
 			createEnvDeleteEnvFailureCases(func(executor bosh.Executor) error {
 				deleteEnvInput := bosh.DeleteEnvInput{
@@ -784,16 +963,17 @@ networks
 					Variables: "some-variables",
 					State:     map[string]interface{}{},
 				}
-				return executor.DeleteEnv(deleteEnvInput)
+				return executor.DeleteEnv(context.Background(), deleteEnvInput)
 			})
 
 			Context("when command run fails", func() {
 				BeforeEach(func() {
 					cmd.RunReturnsOnCall(0, errors.New("failed to run"))
-					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+					executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 
-					cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+					cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 						ioutil.WriteFile(statePath, []byte(`{"partial": "state"}`), os.ModePerm)
+						stdout.Write([]byte("some-delete-env-output"))
 						return errors.New("failed to run")
 					}
 				})
@@ -801,8 +981,8 @@ networks
 				It("returns a create env error with a valid bosh state", func() {
 					expectedError := bosh.NewDeleteEnvError(map[string]interface{}{
 						"partial": "state",
-					}, errors.New("failed to run"))
-					err := executor.DeleteEnv(deleteEnvInput)
+					}, "some-delete-env-output", errors.New("failed to run"))
+					err := executor.DeleteEnv(context.Background(), deleteEnvInput)
 					Expect(err).To(MatchError(expectedError))
 				})
 
@@ -812,11 +992,11 @@ networks
 							return []byte{}, errors.New("failed to read file")
 						}
 
-						executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+						executor = bosh.NewExecutor(cmd, tempDirFunc, readFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 					})
 
 					It("returns an error", func() {
-						err := executor.DeleteEnv(deleteEnvInput)
+						err := executor.DeleteEnv(context.Background(), deleteEnvInput)
 						Expect(err).To(MatchError("the following errors occurred:\nfailed to run,\nfailed to read file"))
 					})
 				})
@@ -835,7 +1015,7 @@ networks
 		)
 		BeforeEach(func() {
 			cmd = &fakes.BOSHCommand{}
-			cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+			cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 				stdout.Write([]byte("some-text version 2.0.0 some-other-text"))
 				return nil
 			}
@@ -849,14 +1029,14 @@ networks
 				return tempDir, nil
 			}
 
-			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+			executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 		})
 
 		It("passes the correct args and dir to run command", func() {
 			_, err := executor.Version()
 			Expect(err).NotTo(HaveOccurred())
 
-			_, _, args := cmd.RunArgsForCall(0)
+			_, _, _, args := cmd.RunArgsForCall(0)
 			Expect(args).To(Equal([]string{"-v"}))
 		})
 
@@ -872,7 +1052,7 @@ networks
 					return "", errors.New("failed to create temp dir")
 				}
 
-				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile)
+				executor = bosh.NewExecutor(cmd, tempDirFunc, ioutil.ReadFile, json.Unmarshal, json.Marshal, ioutil.WriteFile, 0)
 				_, err := executor.Version()
 				Expect(err).To(MatchError("failed to create temp dir"))
 			})
@@ -885,7 +1065,7 @@ networks
 
 			It("returns a bosh version error when the version cannot be parsed", func() {
 				expectedError := bosh.NewBOSHVersionError(errors.New("BOSH version could not be parsed"))
-				cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+				cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 					stdout.Write([]byte(""))
 					return nil
 				}
@@ -0,0 +1,27 @@
+package bosh
+
+type InterpolateError struct {
+	phase  string
+	output string
+	err    error
+}
+
+func NewInterpolateError(phase, output string, err error) InterpolateError {
+	return InterpolateError{
+		phase:  phase,
+		output: output,
+		err:    err,
+	}
+}
+
+func (b InterpolateError) Error() string {
+	return b.err.Error()
+}
+
+func (b InterpolateError) Phase() string {
+	return b.phase
+}
+
+func (b InterpolateError) Output() string {
+	return b.output
+}
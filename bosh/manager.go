@@ -1,6 +1,7 @@
 package bosh
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -64,8 +65,8 @@ type iaasInputs struct {
 type executor interface {
 	Interpolate(InterpolateInput) (InterpolateOutput, error)
 	JumpboxInterpolate(InterpolateInput) (JumpboxInterpolateOutput, error)
-	CreateEnv(CreateEnvInput) (CreateEnvOutput, error)
-	DeleteEnv(DeleteEnvInput) error
+	CreateEnv(context.Context, CreateEnvInput) (CreateEnvOutput, error)
+	DeleteEnv(context.Context, DeleteEnvInput) error
 	Version() (string, error)
 }
 
@@ -106,7 +107,7 @@ func (m Manager) Version() (string, error) {
 	return version, err
 }
 
-func (m Manager) Create(state storage.State) (storage.State, error) {
+func (m Manager) Create(ctx context.Context, state storage.State) (storage.State, error) {
 	iaasInputs, err := m.generateIAASInputs(state)
 	if err != nil {
 		return storage.State{}, err
@@ -132,7 +133,7 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 		}
 
 		osUnsetenv("BOSH_ALL_PROXY")
-		createEnvOutputs, err := m.executor.CreateEnv(CreateEnvInput{
+		createEnvOutputs, err := m.executor.CreateEnv(ctx, CreateEnvInput{
 			Manifest:  interpolateOutputs.Manifest,
 			State:     state.Jumpbox.State,
 			Variables: string(variables),
@@ -146,7 +147,7 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 				State:     ceErr.BOSHState(),
 				Manifest:  interpolateOutputs.Manifest,
 			}
-			return storage.State{}, NewManagerCreateError(state, err)
+			return storage.State{}, NewManagerCreateError(state, storage.JumpboxPhase, err)
 		case error:
 			return storage.State{}, err
 		}
@@ -179,7 +180,8 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 
 		osSetenv("BOSH_ALL_PROXY", fmt.Sprintf("socks5://%s", m.socks5Proxy.Addr()))
 
-		iaasInputs.DirectorAddress = fmt.Sprintf("https://%s:25555", DIRECTOR_INTERNAL_IP)
+		_, _, directorInternalIP := directorNetwork(terraformOutputs)
+		iaasInputs.DirectorAddress = fmt.Sprintf("https://%s:25555", directorInternalIP)
 	}
 
 	m.logger.Step("creating bosh director")
@@ -196,20 +198,22 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 		return storage.State{}, err
 	}
 
-	createEnvOutputs, err := m.executor.CreateEnv(CreateEnvInput{
-		Manifest:  interpolateOutputs.Manifest,
-		State:     state.BOSH.State,
-		Variables: interpolateOutputs.Variables,
+	createEnvOutputs, err := m.executor.CreateEnv(ctx, CreateEnvInput{
+		Manifest:       interpolateOutputs.Manifest,
+		State:          state.BOSH.State,
+		Variables:      interpolateOutputs.Variables,
+		CPICredentials: cpiCredentials(state),
 	})
 	switch err.(type) {
 	case CreateEnvError:
 		ceErr := err.(CreateEnvError)
 		state.BOSH = storage.BOSH{
-			Variables: interpolateOutputs.Variables,
-			State:     ceErr.BOSHState(),
-			Manifest:  interpolateOutputs.Manifest,
+			Variables:  interpolateOutputs.Variables,
+			State:      ceErr.BOSHState(),
+			Manifest:   interpolateOutputs.Manifest,
+			PendingCAs: state.BOSH.PendingCAs,
 		}
-		return storage.State{}, NewManagerCreateError(state, err)
+		return storage.State{}, NewManagerCreateError(state, storage.CreateEnvPhase, err)
 	case error:
 		return storage.State{}, err
 	}
@@ -230,6 +234,7 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 		Variables:              interpolateOutputs.Variables,
 		State:                  createEnvOutputs.State,
 		Manifest:               interpolateOutputs.Manifest,
+		PendingCAs:             state.BOSH.PendingCAs,
 	}
 
 	m.logger.Step("created bosh director")
@@ -237,11 +242,12 @@ func (m Manager) Create(state storage.State) (storage.State, error) {
 	return state, nil
 }
 
-func (m Manager) Delete(state storage.State) error {
-	err := m.executor.DeleteEnv(DeleteEnvInput{
-		Manifest:  state.BOSH.Manifest,
-		State:     state.BOSH.State,
-		Variables: state.BOSH.Variables,
+func (m Manager) Delete(ctx context.Context, state storage.State) error {
+	err := m.executor.DeleteEnv(ctx, DeleteEnvInput{
+		Manifest:       state.BOSH.Manifest,
+		State:          state.BOSH.State,
+		Variables:      state.BOSH.Variables,
+		CPICredentials: cpiCredentials(state),
 	})
 	switch err.(type) {
 	case DeleteEnvError:
@@ -262,10 +268,16 @@ func (m Manager) GetJumpboxDeploymentVars(state storage.State) (string, error) {
 		return "", err
 	}
 
+	internalCIDR, internalGateway, _ := directorNetwork(terraformOutputs)
+	jumpboxInternalIP := "10.0.0.5"
+	if value, ok := terraformOutputs["jumpbox_internal_ip"].(string); ok {
+		jumpboxInternalIP = value
+	}
+
 	vars := strings.Join([]string{
-		"internal_cidr: 10.0.0.0/24",
-		"internal_gw: 10.0.0.1",
-		"internal_ip: 10.0.0.5",
+		fmt.Sprintf("internal_cidr: %s", internalCIDR),
+		fmt.Sprintf("internal_gw: %s", internalGateway),
+		fmt.Sprintf("internal_ip: %s", jumpboxInternalIP),
 		fmt.Sprintf("director_name: %s", fmt.Sprintf("bosh-%s", state.EnvID)),
 		fmt.Sprintf("external_ip: %s", terraformOutputs["external_ip"]),
 		fmt.Sprintf("zone: %s", state.GCP.Zone),
@@ -273,7 +285,7 @@ func (m Manager) GetJumpboxDeploymentVars(state storage.State) (string, error) {
 		fmt.Sprintf("subnetwork: %s", terraformOutputs["subnetwork_name"]),
 		fmt.Sprintf("tags: [%s, %s]", terraformOutputs["bosh_open_tag_name"], terraformOutputs["internal_tag_name"]),
 		fmt.Sprintf("project_id: %s", state.GCP.ProjectID),
-		fmt.Sprintf("gcp_credentials_json: '%s'", state.GCP.ServiceAccountKey),
+		fmt.Sprintf("gcp_credentials_json: '%s'", directorServiceAccountKey(state, terraformOutputs)),
 	}, "\n")
 
 	return strings.TrimSuffix(vars, "\n"), nil
@@ -289,24 +301,25 @@ func (m Manager) GetDeploymentVars(state storage.State) (string, error) {
 			return "", err
 		}
 
+		internalCIDR, internalGateway, internalIP := directorNetwork(terraformOutputs)
 		if state.Jumpbox.Enabled {
 			vars = strings.Join([]string{
-				"internal_cidr: 10.0.0.0/24",
-				"internal_gw: 10.0.0.1",
-				fmt.Sprintf("internal_ip: %s", DIRECTOR_INTERNAL_IP),
+				fmt.Sprintf("internal_cidr: %s", internalCIDR),
+				fmt.Sprintf("internal_gw: %s", internalGateway),
+				fmt.Sprintf("internal_ip: %s", internalIP),
 				fmt.Sprintf("director_name: %s", fmt.Sprintf("bosh-%s", state.EnvID)),
 				fmt.Sprintf("zone: %s", state.GCP.Zone),
 				fmt.Sprintf("network: %s", terraformOutputs["network_name"]),
 				fmt.Sprintf("subnetwork: %s", terraformOutputs["subnetwork_name"]),
 				fmt.Sprintf("tags: [%s]", terraformOutputs["internal_tag_name"]),
 				fmt.Sprintf("project_id: %s", state.GCP.ProjectID),
-				fmt.Sprintf("gcp_credentials_json: '%s'", state.GCP.ServiceAccountKey),
+				fmt.Sprintf("gcp_credentials_json: '%s'", directorServiceAccountKey(state, terraformOutputs)),
 			}, "\n")
 		} else {
 			vars = strings.Join([]string{
-				"internal_cidr: 10.0.0.0/24",
-				"internal_gw: 10.0.0.1",
-				fmt.Sprintf("internal_ip: %s", DIRECTOR_INTERNAL_IP),
+				fmt.Sprintf("internal_cidr: %s", internalCIDR),
+				fmt.Sprintf("internal_gw: %s", internalGateway),
+				fmt.Sprintf("internal_ip: %s", internalIP),
 				fmt.Sprintf("director_name: %s", fmt.Sprintf("bosh-%s", state.EnvID)),
 				fmt.Sprintf("external_ip: %s", terraformOutputs["external_ip"]),
 				fmt.Sprintf("zone: %s", state.GCP.Zone),
@@ -314,7 +327,7 @@ func (m Manager) GetDeploymentVars(state storage.State) (string, error) {
 				fmt.Sprintf("subnetwork: %s", terraformOutputs["subnetwork_name"]),
 				fmt.Sprintf("tags: [%s, %s]", terraformOutputs["bosh_open_tag_name"], terraformOutputs["internal_tag_name"]),
 				fmt.Sprintf("project_id: %s", state.GCP.ProjectID),
-				fmt.Sprintf("gcp_credentials_json: '%s'", state.GCP.ServiceAccountKey),
+				fmt.Sprintf("gcp_credentials_json: '%s'", directorServiceAccountKey(state, terraformOutputs)),
 			}, "\n")
 		}
 	case "aws":
@@ -323,21 +336,38 @@ func (m Manager) GetDeploymentVars(state storage.State) (string, error) {
 			if err != nil {
 				return "", err
 			}
-			vars = strings.Join([]string{
-				"internal_cidr: 10.0.0.0/24",
-				"internal_gw: 10.0.0.1",
-				fmt.Sprintf("internal_ip: %s", DIRECTOR_INTERNAL_IP),
+			internalCIDR, internalGateway, internalIP := directorNetwork(terraformOutputs)
+			accessKeyID, secretAccessKey := terraformOutputs["access_key_id"], terraformOutputs["secret_access_key"]
+			if state.AWS.DirectorAccessKeyID != "" {
+				accessKeyID, secretAccessKey = state.AWS.DirectorAccessKeyID, state.AWS.DirectorSecretAccessKey
+			}
+			lines := []string{
+				fmt.Sprintf("internal_cidr: %s", internalCIDR),
+				fmt.Sprintf("internal_gw: %s", internalGateway),
+				fmt.Sprintf("internal_ip: %s", internalIP),
 				fmt.Sprintf("director_name: %s", fmt.Sprintf("bosh-%s", state.EnvID)),
 				fmt.Sprintf("external_ip: %s", terraformOutputs["external_ip"]),
 				fmt.Sprintf("az: %s", terraformOutputs["az"]),
 				fmt.Sprintf("subnet_id: %s", terraformOutputs["subnet_id"]),
-				fmt.Sprintf("access_key_id: %s", terraformOutputs["access_key_id"]),
-				fmt.Sprintf("secret_access_key: %s", terraformOutputs["secret_access_key"]),
+			}
+			// With an instance profile the manifest has no access key, bosh
+			// create-env gets the operator's through cpiCredentials.
+			if !state.AWS.IAMInstanceProfile {
+				lines = append(lines,
+					fmt.Sprintf("access_key_id: %s", accessKeyID),
+					fmt.Sprintf("secret_access_key: %s", secretAccessKey),
+				)
+			}
+			lines = append(lines,
 				fmt.Sprintf("default_key_name: %s", state.KeyPair.Name),
 				fmt.Sprintf("default_security_groups: [%s]", terraformOutputs["default_security_groups"]),
 				fmt.Sprintf("region: %s", state.AWS.Region),
 				fmt.Sprintf("private_key: |-\n  %s", strings.Replace(state.KeyPair.PrivateKey, "\n", "\n  ", -1)),
-			}, "\n")
+			)
+			if state.AWS.IAMInstanceProfile {
+				lines = append(lines, fmt.Sprintf("iam_instance_profile: %s", terraformOutputs["iam_instance_profile"]))
+			}
+			vars = strings.Join(lines, "\n")
 		} else {
 			stack, err := m.stackManager.Describe(state.Stack.Name)
 			if err != nil {
@@ -364,6 +394,52 @@ func (m Manager) GetDeploymentVars(state storage.State) (string, error) {
 	return strings.TrimSuffix(vars, "\n"), nil
 }
 
+// directorServiceAccountKey prefers the key bbl created for the director
+// service account over the one terraform created. Environments without a
+// director service account yet keep using the key bbl was given.
+func directorServiceAccountKey(state storage.State, terraformOutputs map[string]interface{}) string {
+	if state.GCP.DirectorServiceAccountKey != "" {
+		return state.GCP.DirectorServiceAccountKey
+	}
+
+	if key, _ := terraformOutputs["director_service_account_key"].(string); key != "" {
+		return key
+	}
+
+	return state.GCP.ServiceAccountKey
+}
+
+// cpiCredentials is the key bbl was given, which bosh create-env and
+// delete-env need when the director uses an IAM instance profile.
+func cpiCredentials(state storage.State) *CPICredentials {
+	if state.IAAS != "aws" || !state.AWS.IAMInstanceProfile {
+		return nil
+	}
+
+	return &CPICredentials{
+		AccessKeyID:     state.AWS.AccessKeyID,
+		SecretAccessKey: state.AWS.SecretAccessKey,
+	}
+}
+
+// directorNetwork falls back to the subnet bbl creates for the director
+// when terraform does not report the network of an existing subnet.
+func directorNetwork(terraformOutputs map[string]interface{}) (string, string, string) {
+	cidr, gateway, ip := "10.0.0.0/24", "10.0.0.1", DIRECTOR_INTERNAL_IP
+
+	if value, ok := terraformOutputs["internal_cidr"].(string); ok {
+		cidr = value
+	}
+	if value, ok := terraformOutputs["internal_gw"].(string); ok {
+		gateway = value
+	}
+	if value, ok := terraformOutputs["internal_ip"].(string); ok {
+		ip = value
+	}
+
+	return cidr, gateway, ip
+}
+
 func (m Manager) generateIAASInputs(state storage.State) (iaasInputs, error) {
 	switch state.IAAS {
 	case "gcp":
@@ -387,9 +463,10 @@ func (m Manager) generateIAASInputs(state storage.State) (iaasInputs, error) {
 			}
 			return iaasInputs{
 				InterpolateInput: InterpolateInput{
-					IAAS:      state.IAAS,
-					BOSHState: state.BOSH.State,
-					Variables: state.BOSH.Variables,
+					IAAS:               state.IAAS,
+					BOSHState:          state.BOSH.State,
+					Variables:          state.BOSH.Variables,
+					IAMInstanceProfile: state.AWS.IAMInstanceProfile,
 				},
 				DirectorAddress: terraformOutputs["director_address"].(string),
 			}, nil
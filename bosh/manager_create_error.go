@@ -4,12 +4,14 @@ import "github.com/cloudfoundry/bosh-bootloader/storage"
 
 type ManagerCreateError struct {
 	state storage.State
+	phase string
 	err   error
 }
 
-func NewManagerCreateError(state storage.State, err error) ManagerCreateError {
+func NewManagerCreateError(state storage.State, phase string, err error) ManagerCreateError {
 	return ManagerCreateError{
 		state: state,
+		phase: phase,
 		err:   err,
 	}
 }
@@ -21,3 +23,14 @@ func (b ManagerCreateError) Error() string {
 func (b ManagerCreateError) State() storage.State {
 	return b.state
 }
+
+func (b ManagerCreateError) Phase() string {
+	return b.phase
+}
+
+func (b ManagerCreateError) Output() string {
+	if createEnvError, ok := b.err.(CreateEnvError); ok {
+		return createEnvError.Output()
+	}
+	return ""
+}
@@ -21,3 +21,14 @@ func (b ManagerDeleteError) Error() string {
 func (b ManagerDeleteError) State() storage.State {
 	return b.state
 }
+
+func (b ManagerDeleteError) Phase() string {
+	return storage.DeleteEnvPhase
+}
+
+func (b ManagerDeleteError) Output() string {
+	if deleteEnvError, ok := b.err.(DeleteEnvError); ok {
+		return deleteEnvError.Output()
+	}
+	return ""
+}
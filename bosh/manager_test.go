@@ -1,6 +1,7 @@
 package bosh_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -60,13 +61,14 @@ var _ = Describe("Manager", func() {
 			})
 
 			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
-				"network_name":       "some-network",
-				"subnetwork_name":    "some-subnetwork",
-				"bosh_open_tag_name": "some-bosh-tag",
-				"internal_tag_name":  "some-internal-tag",
-				"external_ip":        "some-external-ip",
-				"director_address":   "some-director-address",
-				"jumpbox_url":        "some-jumpbox-url",
+				"network_name":                 "some-network",
+				"subnetwork_name":              "some-subnetwork",
+				"bosh_open_tag_name":           "some-bosh-tag",
+				"internal_tag_name":            "some-internal-tag",
+				"external_ip":                  "some-external-ip",
+				"director_address":             "some-director-address",
+				"jumpbox_url":                  "some-jumpbox-url",
+				"director_service_account_key": "some-director-service-account-key",
 			}
 
 			incomingGCPState = storage.State{
@@ -123,7 +125,7 @@ var _ = Describe("Manager", func() {
 				Variables: variablesYAML,
 			}
 
-			_, err := boshManager.Create(incomingGCPState)
+			_, err := boshManager.Create(context.Background(), incomingGCPState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(logger.StepCall.Messages).To(ContainSequence([]string{"creating bosh director", "created bosh director"}))
@@ -136,7 +138,7 @@ var _ = Describe("Manager", func() {
 					Variables: variablesYAML,
 				}
 
-				_, err := boshManager.Create(incomingGCPState)
+				_, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.GetOutputsCall.Receives.BBLState).To(Equal(incomingGCPState))
@@ -155,7 +157,7 @@ var _ = Describe("Manager", func() {
 				}
 
 				incomingGCPState.BOSH.UserOpsFile = "some-ops-file"
-				_, err := boshManager.Create(incomingGCPState)
+				_, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(boshExecutor.CreateEnvCall.CallCount).To(Equal(1))
@@ -171,7 +173,7 @@ network: some-network
 subnetwork: some-subnetwork
 tags: [some-bosh-tag, some-internal-tag]
 project_id: some-project-id
-gcp_credentials_json: 'some-credential-json'`,
+gcp_credentials_json: 'some-director-service-account-key'`,
 					BOSHState: map[string]interface{}{
 						"some-key": "some-value",
 					},
@@ -195,7 +197,7 @@ gcp_credentials_json: 'some-credential-json'`,
 					},
 				}
 
-				state, err := boshManager.Create(incomingGCPState)
+				state, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(state).To(Equal(storage.State{
@@ -230,6 +232,23 @@ gcp_credentials_json: 'some-credential-json'`,
 				}))
 			})
 
+			It("keeps the pending CAs of a director CA rotation", func() {
+				pendingCAs := map[string]storage.PendingCA{
+					"default_ca": {Certificate: "some-new-ca", PrivateKey: "some-new-ca-key"},
+				}
+				incomingGCPState.BOSH.PendingCAs = pendingCAs
+
+				boshExecutor.InterpolateCall.Returns.Output = bosh.InterpolateOutput{
+					Manifest:  "some-manifest",
+					Variables: variablesYAML,
+				}
+
+				state, err := boshManager.Create(context.Background(), incomingGCPState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(state.BOSH.PendingCAs).To(Equal(pendingCAs))
+			})
+
 			Context("when jumpbox enabled is true", func() {
 				var jumpboxDeploymentVars string
 				var deploymentVars string
@@ -274,7 +293,7 @@ network: some-network
 subnetwork: some-subnetwork
 tags: [some-bosh-tag, some-internal-tag]
 project_id: some-project-id
-gcp_credentials_json: 'some-credential-json'`
+gcp_credentials_json: 'some-director-service-account-key'`
 
 					deploymentVars = `internal_cidr: 10.0.0.0/24
 internal_gw: 10.0.0.1
@@ -285,7 +304,7 @@ network: some-network
 subnetwork: some-subnetwork
 tags: [some-internal-tag]
 project_id: some-project-id
-gcp_credentials_json: 'some-credential-json'`
+gcp_credentials_json: 'some-director-service-account-key'`
 
 					boshExecutor.JumpboxInterpolateCall.Returns.Output = bosh.JumpboxInterpolateOutput{
 						Manifest:  "name: jumpbox",
@@ -304,21 +323,21 @@ gcp_credentials_json: 'some-credential-json'`
 							"some-key": "some-value",
 						},
 					}
-					_, err := boshManager.Create(incomingGCPState)
+					_, err := boshManager.Create(context.Background(), incomingGCPState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(terraformManager.GetOutputsCall.Receives.BBLState).To(Equal(incomingGCPState))
 				})
 
 				It("logs jumpbox status messages", func() {
-					_, err := boshManager.Create(incomingGCPState)
+					_, err := boshManager.Create(context.Background(), incomingGCPState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.StepCall.Messages).To(ContainSequence([]string{"creating jumpbox", "created jumpbox"}))
 				})
 
 				It("generates a jumpbox and bosh manifest", func() {
-					_, err := boshManager.Create(incomingGCPState)
+					_, err := boshManager.Create(context.Background(), incomingGCPState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput).To(Equal(bosh.InterpolateInput{
@@ -336,7 +355,7 @@ gcp_credentials_json: 'some-credential-json'`
 					socks5ProxyAddr := "localhost:1234"
 					socks5Proxy.AddrCall.Returns.Addr = socks5ProxyAddr
 
-					_, err := boshManager.Create(incomingGCPState)
+					_, err := boshManager.Create(context.Background(), incomingGCPState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(osUnsetenvKey).To(Equal("BOSH_ALL_PROXY"))
@@ -362,7 +381,7 @@ gcp_credentials_json: 'some-credential-json'`
 						},
 					}
 
-					state, err := boshManager.Create(incomingGCPState)
+					state, err := boshManager.Create(context.Background(), incomingGCPState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(state).To(Equal(storage.State{
@@ -412,16 +431,28 @@ gcp_credentials_json: 'some-credential-json'`
 						})
 
 						It("returns an error", func() {
-							_, err := boshManager.Create(incomingGCPState)
+							_, err := boshManager.Create(context.Background(), incomingGCPState)
 							Expect(err).To(MatchError("yaml: could not find expected directive name"))
 						})
 					})
 
 					It("returns an error when the socks5Proxy fails to start", func() {
 						socks5Proxy.StartCall.Returns.Error = errors.New("failed to start socks5Proxy")
-						_, err := boshManager.Create(incomingGCPState)
+						_, err := boshManager.Create(context.Background(), incomingGCPState)
 						Expect(err).To(MatchError("failed to start socks5Proxy"))
 					})
+
+					It("returns a bosh manager create error in the jumpbox phase when the jumpbox create env fails", func() {
+						boshExecutor.CreateEnvCall.Returns.Error = bosh.NewCreateEnvError(map[string]interface{}{}, "some-output", errors.New("failed to create env"))
+
+						_, err := boshManager.Create(context.Background(), incomingGCPState)
+						Expect(err).To(MatchError("failed to create env"))
+
+						managerCreateError, ok := err.(bosh.ManagerCreateError)
+						Expect(ok).To(BeTrue())
+						Expect(managerCreateError.Phase()).To(Equal("jumpbox"))
+						Expect(managerCreateError.Output()).To(Equal("some-output"))
+					})
 				})
 			})
 		})
@@ -459,7 +490,7 @@ gcp_credentials_json: 'some-credential-json'`
 
 				It("generates a bosh manifest", func() {
 					incomingAWSState.BOSH.UserOpsFile = "some-ops-file"
-					_, err := boshManager.Create(incomingAWSState)
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(0))
@@ -517,7 +548,7 @@ private_key: |-
 
 				It("generates a bosh manifest", func() {
 					incomingAWSState.BOSH.UserOpsFile = "some-ops-file"
-					_, err := boshManager.Create(incomingAWSState)
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(2))
@@ -548,8 +579,39 @@ private_key: |-
 					}))
 				})
 
+				It("applies the iam instance profile ops file when the director uses an instance profile", func() {
+					incomingAWSState.AWS.IAMInstanceProfile = true
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.IAMInstanceProfile).To(BeTrue())
+				})
+
+				It("gives bosh create-env the access key bbl was given, not the manifest, when the director uses an instance profile", func() {
+					incomingAWSState.AWS.IAMInstanceProfile = true
+					incomingAWSState.AWS.AccessKeyID = "some-access-key-id"
+					incomingAWSState.AWS.SecretAccessKey = "some-secret-access-key"
+
+					state, err := boshManager.Create(context.Background(), incomingAWSState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.InterpolateCall.Receives.InterpolateInput.DeploymentVars).NotTo(ContainSubstring("some-secret-access-key"))
+					Expect(boshExecutor.CreateEnvCall.Receives.Input.CPICredentials).To(Equal(&bosh.CPICredentials{
+						AccessKeyID:     "some-access-key-id",
+						SecretAccessKey: "some-secret-access-key",
+					}))
+					Expect(state.BOSH.Manifest).To(Equal("some-manifest"))
+				})
+
+				It("does not give bosh create-env an access key when the manifest has one", func() {
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(boshExecutor.CreateEnvCall.Receives.Input.CPICredentials).To(BeNil())
+				})
+
 				It("returns a state with a proper bosh state", func() {
-					state, err := boshManager.Create(incomingAWSState)
+					state, err := boshManager.Create(context.Background(), incomingAWSState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(state).To(Equal(storage.State{
@@ -591,9 +653,13 @@ private_key: |-
 				Variables: variablesYAML,
 			}
 
-			_, err := boshManager.Create(incomingGCPState)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := boshManager.Create(ctx, incomingGCPState)
 			Expect(err).NotTo(HaveOccurred())
 
+			Expect(boshExecutor.CreateEnvCall.Receives.Context).To(BeIdenticalTo(ctx))
 			Expect(boshExecutor.CreateEnvCall.Receives.Input).To(Equal(bosh.CreateEnvInput{
 				Manifest: "some-manifest",
 				State: map[string]interface{}{
@@ -606,24 +672,24 @@ private_key: |-
 		Context("failure cases", func() {
 			It("returns an error when terraform output provider fails", func() {
 				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to output")
-				_, err := boshManager.Create(incomingGCPState)
+				_, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).To(MatchError("failed to output"))
 			})
 
 			It("returns an error when an invalid iaas is provided", func() {
-				_, err := boshManager.Create(storage.State{})
+				_, err := boshManager.Create(context.Background(), storage.State{})
 				Expect(err).To(MatchError("A valid IAAS was not provided"))
 			})
 
 			It("returns an error when the executor's interpolate call fails", func() {
 				boshExecutor.InterpolateCall.Returns.Error = errors.New("failed to interpolate")
-				_, err := boshManager.Create(incomingGCPState)
+				_, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).To(MatchError("failed to interpolate"))
 			})
 
 			It("returns an error when the executor's create env call fails with non create env error", func() {
 				boshExecutor.CreateEnvCall.Returns.Error = errors.New("failed to create")
-				_, err := boshManager.Create(incomingGCPState)
+				_, err := boshManager.Create(context.Background(), incomingGCPState)
 				Expect(err).To(MatchError("failed to create"))
 			})
 
@@ -634,7 +700,7 @@ private_key: |-
 						Variables: "%%%",
 					}
 
-					_, err := boshManager.Create(incomingAWSState)
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
 					Expect(err).To(MatchError("failed to get director outputs:\nyaml: could not find expected directive name"))
 				})
 			})
@@ -655,7 +721,7 @@ private_key: |-
 						Variables: variablesYAML,
 					}
 
-					createEnvError := bosh.NewCreateEnvError(boshState, errors.New("failed to create env"))
+					createEnvError := bosh.NewCreateEnvError(boshState, "some-output", errors.New("failed to create env"))
 					boshExecutor.CreateEnvCall.Returns.Error = createEnvError
 
 					expectedState = incomingAWSState
@@ -664,13 +730,22 @@ private_key: |-
 						State:     boshState,
 						Variables: variablesYAML,
 					}
-					expectedError = bosh.NewManagerCreateError(expectedState, createEnvError)
+					expectedError = bosh.NewManagerCreateError(expectedState, storage.CreateEnvPhase, createEnvError)
 				})
 
 				It("returns a bosh manager create error with a valid state", func() {
-					_, err := boshManager.Create(incomingAWSState)
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
 					Expect(err).To(MatchError(expectedError))
 				})
+
+				It("returns the create-env phase and output with the error", func() {
+					_, err := boshManager.Create(context.Background(), incomingAWSState)
+
+					managerCreateError, ok := err.(bosh.ManagerCreateError)
+					Expect(ok).To(BeTrue())
+					Expect(managerCreateError.Phase()).To(Equal("create-env"))
+					Expect(managerCreateError.Output()).To(Equal("some-output"))
+				})
 			})
 
 			Context("when the stack manager returns an error", func() {
@@ -679,7 +754,7 @@ private_key: |-
 				})
 
 				It("returns the error", func() {
-					_, err := boshManager.Create(storage.State{
+					_, err := boshManager.Create(context.Background(), storage.State{
 						IAAS: "aws",
 					})
 					Expect(err).To(MatchError("stack manager describe failed"))
@@ -708,7 +783,10 @@ private_key: |-
 		})
 
 		It("calls delete env", func() {
-			err := boshManager.Delete(storage.State{
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := boshManager.Delete(ctx, storage.State{
 				BOSH: storage.BOSH{
 					Manifest: "some-manifest",
 					State: map[string]interface{}{
@@ -718,6 +796,7 @@ private_key: |-
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(boshExecutor.DeleteEnvCall.Receives.Context).To(BeIdenticalTo(ctx))
 			Expect(boshExecutor.DeleteEnvCall.Receives.Input).To(Equal(bosh.DeleteEnvInput{
 				Manifest: "some-manifest",
 				State: map[string]interface{}{
@@ -727,6 +806,25 @@ private_key: |-
 			}))
 		})
 
+		It("gives bosh delete-env the access key bbl was given when the director uses an instance profile", func() {
+			err := boshManager.Delete(context.Background(), storage.State{
+				IAAS: "aws",
+				AWS: storage.AWS{
+					IAMInstanceProfile: true,
+					AccessKeyID:        "some-access-key-id",
+					SecretAccessKey:    "some-secret-access-key",
+				},
+				BOSH: storage.BOSH{
+					Manifest: "some-manifest",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(boshExecutor.DeleteEnvCall.Receives.Input.CPICredentials).To(Equal(&bosh.CPICredentials{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+			}))
+		})
+
 		Context("failure cases", func() {
 			Context("when the executor's delete env call fails with delete env error", func() {
 				var (
@@ -750,7 +848,7 @@ private_key: |-
 					boshState := map[string]interface{}{
 						"partial": "bosh-state",
 					}
-					deleteEnvError := bosh.NewDeleteEnvError(boshState, errors.New("failed to delete env"))
+					deleteEnvError := bosh.NewDeleteEnvError(boshState, "some-output", errors.New("failed to delete env"))
 					boshExecutor.DeleteEnvCall.Returns.Error = deleteEnvError
 
 					expectedState = incomingState
@@ -763,14 +861,23 @@ private_key: |-
 				})
 
 				It("returns a bosh manager delete error with a valid state", func() {
-					err := boshManager.Delete(incomingState)
+					err := boshManager.Delete(context.Background(), incomingState)
 					Expect(err).To(MatchError(expectedError))
 				})
+
+				It("returns the delete-env phase and output with the error", func() {
+					err := boshManager.Delete(context.Background(), incomingState)
+
+					managerDeleteError, ok := err.(bosh.ManagerDeleteError)
+					Expect(ok).To(BeTrue())
+					Expect(managerDeleteError.Phase()).To(Equal("delete-env"))
+					Expect(managerDeleteError.Output()).To(Equal("some-output"))
+				})
 			})
 
 			It("returns an error when the delete env fails", func() {
 				boshExecutor.DeleteEnvCall.Returns.Error = errors.New("failed to delete")
-				err := boshManager.Delete(storage.State{})
+				err := boshManager.Delete(context.Background(), storage.State{})
 				Expect(err).To(MatchError("failed to delete"))
 			})
 		})
@@ -824,12 +931,13 @@ private_key: |-
 				}
 
 				terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
-					"network_name":       "some-network",
-					"subnetwork_name":    "some-subnetwork",
-					"bosh_open_tag_name": "some-bosh-tag",
-					"internal_tag_name":  "some-internal-tag",
-					"external_ip":        "some-external-ip",
-					"director_address":   "some-director-address",
+					"network_name":                 "some-network",
+					"subnetwork_name":              "some-subnetwork",
+					"bosh_open_tag_name":           "some-bosh-tag",
+					"internal_tag_name":            "some-internal-tag",
+					"external_ip":                  "some-external-ip",
+					"director_address":             "some-director-address",
+					"director_service_account_key": "some-director-service-account-key",
 				}
 			})
 
@@ -846,7 +954,44 @@ network: some-network
 subnetwork: some-subnetwork
 tags: [some-bosh-tag, some-internal-tag]
 project_id: some-project-id
-gcp_credentials_json: 'some-credential-json'`))
+gcp_credentials_json: 'some-director-service-account-key'`))
+			})
+
+			It("uses the key that bbl created for the director service account once it manages the keys", func() {
+				incomingState.GCP.DirectorServiceAccountKey = "some-director-credential-json"
+
+				vars, err := boshManager.GetDeploymentVars(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vars).To(HaveSuffix("gcp_credentials_json: 'some-director-credential-json'"))
+			})
+
+			It("uses the key bbl was given when the director has no service account yet", func() {
+				incomingState.GCP.ServiceAccountKey = "some-service-account-key"
+				delete(terraformManager.GetOutputsCall.Returns.Outputs, "director_service_account_key")
+
+				vars, err := boshManager.GetDeploymentVars(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vars).To(HaveSuffix("gcp_credentials_json: 'some-service-account-key'"))
+			})
+
+			Context("when the director is deployed into an existing subnetwork", func() {
+				BeforeEach(func() {
+					incomingState.GCP.ExistingNetwork = "some-network"
+					incomingState.GCP.ExistingSubnetwork = "some-subnetwork"
+					terraformManager.GetOutputsCall.Returns.Outputs["internal_cidr"] = "172.16.0.0/20"
+					terraformManager.GetOutputsCall.Returns.Outputs["internal_gw"] = "172.16.0.1"
+					terraformManager.GetOutputsCall.Returns.Outputs["internal_ip"] = "172.16.0.6"
+				})
+
+				It("uses the network of the subnetwork", func() {
+					vars, err := boshManager.GetDeploymentVars(incomingState)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vars).To(HavePrefix(`internal_cidr: 172.16.0.0/20
+internal_gw: 172.16.0.1
+internal_ip: 172.16.0.6
+director_name: bosh-some-env-id`))
+					Expect(vars).To(ContainSubstring("network: some-network\nsubnetwork: some-subnetwork"))
+				})
 			})
 		})
 
@@ -909,6 +1054,58 @@ region: some-region
 private_key: |-
   some-private-key`))
 				})
+
+				It("uses the access key bbl manages when there is one", func() {
+					incomingState.AWS.DirectorAccessKeyID = "some-director-access-key"
+					incomingState.AWS.DirectorSecretAccessKey = "some-director-secret-access-key"
+
+					vars, err := boshManager.GetDeploymentVars(incomingState)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vars).To(ContainSubstring("access_key_id: some-director-access-key\nsecret_access_key: some-director-secret-access-key\n"))
+				})
+
+				It("leaves out the access keys when the director uses an instance profile", func() {
+					incomingState.AWS.IAMInstanceProfile = true
+					incomingState.AWS.AccessKeyID = "some-access-key-id"
+					incomingState.AWS.SecretAccessKey = "some-secret-access-key"
+					terraformManager.GetOutputsCall.Returns.Outputs["iam_instance_profile"] = "some-instance-profile"
+					delete(terraformManager.GetOutputsCall.Returns.Outputs, "access_key_id")
+					delete(terraformManager.GetOutputsCall.Returns.Outputs, "secret_access_key")
+
+					vars, err := boshManager.GetDeploymentVars(incomingState)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vars).NotTo(ContainSubstring("access_key_id"))
+					Expect(vars).NotTo(ContainSubstring("some-secret-access-key"))
+					Expect(vars).To(ContainSubstring("subnet_id: some-bosh-subnet\ndefault_key_name: some-keypair-name\n"))
+					Expect(vars).To(HaveSuffix("\niam_instance_profile: some-instance-profile"))
+				})
+			})
+
+			Context("when the director is deployed into an existing subnet", func() {
+				BeforeEach(func() {
+					incomingState.TFState = "some-tf-state"
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+						"az":                      "some-bosh-subnet-az",
+						"access_key_id":           "some-bosh-user-access-key",
+						"secret_access_key":       "some-bosh-user-secret-access-key",
+						"default_security_groups": "some-bosh-security-group",
+						"subnet_id":               "some-bosh-subnet",
+						"external_ip":             "some-bosh-elastic-ip",
+						"director_address":        "some-bosh-url",
+						"internal_cidr":           "172.16.4.0/24",
+						"internal_gw":             "172.16.4.1",
+						"internal_ip":             "172.16.4.6",
+					}
+				})
+
+				It("uses the network of the subnet", func() {
+					vars, err := boshManager.GetDeploymentVars(incomingState)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vars).To(HavePrefix(`internal_cidr: 172.16.4.0/24
+internal_gw: 172.16.4.1
+internal_ip: 172.16.4.6
+director_name: bosh-some-env-id`))
+				})
 			})
 
 			Context("when cloudformation was used to standup infrastructure", func() {
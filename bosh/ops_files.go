@@ -0,0 +1,35 @@
+package bosh
+
+// iamInstanceProfileOpsFile is aws/iam-instance-profile.yml of bosh-deployment,
+// which the vendored bosh-deployment predates. The cloud provider loses its
+// access key as well, so the manifest bbl stores has none; bosh create-env
+// gets the operator's key through cpiCredentialsOpsFile instead.
+const iamInstanceProfileOpsFile = `- type: replace
+  path: /resource_pools/name=vms/cloud_properties/iam_instance_profile?
+  value: ((iam_instance_profile))
+
+- type: replace
+  path: /instance_groups/name=bosh/properties/aws/credentials_source?
+  value: env_or_profile
+
+- type: remove
+  path: /instance_groups/name=bosh/properties/aws/access_key_id
+
+- type: remove
+  path: /instance_groups/name=bosh/properties/aws/secret_access_key
+
+- type: remove
+  path: /cloud_provider/properties/aws/access_key_id
+
+- type: remove
+  path: /cloud_provider/properties/aws/secret_access_key
+`
+
+const cpiCredentialsOpsFile = `- type: replace
+  path: /cloud_provider/properties/aws/access_key_id?
+  value: ((access_key_id))
+
+- type: replace
+  path: /cloud_provider/properties/aws/secret_access_key?
+  value: ((secret_access_key))
+`
@@ -0,0 +1,46 @@
+package certificates
+
+import (
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const WarningPeriod = 14 * 24 * time.Hour
+
+var now func() time.Time = time.Now
+
+type logger interface {
+	Warn(string, ...interface{})
+}
+
+type ExpiryWarner struct {
+	logger logger
+}
+
+func NewExpiryWarner(logger logger) ExpiryWarner {
+	return ExpiryWarner{
+		logger: logger,
+	}
+}
+
+// Warn logs a warning for every certificate in the state that expires within
+// 14 days. It does not call the IaaS, so it is cheap enough to run before
+// every command.
+func (w ExpiryWarner) Warn(state storage.State) {
+	certificates, err := Local(state)
+	if err != nil {
+		return
+	}
+
+	currentTime := now()
+	for _, certificate := range ExpiringWithin(certificates, WarningPeriod, currentTime) {
+		expires := certificate.Expires.Format("2006-01-02")
+		if certificate.Expires.Before(currentTime) {
+			w.logger.Warn("the %s certificate expired on %s", certificate.Name, expires)
+			continue
+		}
+
+		w.logger.Warn("the %s certificate expires on %s", certificate.Name, expires)
+	}
+}
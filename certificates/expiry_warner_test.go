@@ -0,0 +1,57 @@
+package certificates_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExpiryWarner", func() {
+	var (
+		logger *fakes.Logger
+		warner certificates.ExpiryWarner
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		warner = certificates.NewExpiryWarner(logger)
+
+		certificates.SetNow(func() time.Time {
+			return time.Date(2017, time.July, 20, 0, 0, 0, 0, time.UTC)
+		})
+	})
+
+	AfterEach(func() {
+		certificates.ResetNow()
+	})
+
+	It("warns about certificates that expire within 14 days", func() {
+		warner.Warn(storage.State{
+			LB: storage.LB{
+				Cert: certificateExpiringAt("some-lb", time.Date(2017, time.July, 30, 0, 0, 0, 0, time.UTC)),
+			},
+			BOSH: storage.BOSH{
+				Variables: vars("director_ssl", certificateExpiringAt("some-director", time.Date(2017, time.July, 1, 0, 0, 0, 0, time.UTC))) +
+					vars("default_ca", certificateExpiringAt("some-ca", time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC))),
+			},
+		})
+
+		Expect(logger.WarnCall.Messages).To(Equal([]string{
+			"the load balancer certificate expires on 2017-07-30",
+			"the director/director_ssl certificate expired on 2017-07-01",
+		}))
+	})
+
+	It("does not warn when the certificates cannot be read", func() {
+		warner.Warn(storage.State{
+			BOSH: storage.BOSH{Variables: "%%%"},
+		})
+
+		Expect(logger.WarnCall.CallCount).To(Equal(0))
+	})
+})
@@ -0,0 +1,218 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const lbCertificateName = "load balancer"
+
+type Certificate struct {
+	Name    string    `json:"name" yaml:"name"`
+	Subject string    `json:"subject" yaml:"subject"`
+	Expires time.Time `json:"expires" yaml:"expires"`
+}
+
+type certificateDescriber interface {
+	Describe(certificateName string) (iam.Certificate, error)
+}
+
+type Inventory struct {
+	certificateDescriber certificateDescriber
+}
+
+func NewInventory(certificateDescriber certificateDescriber) Inventory {
+	return Inventory{
+		certificateDescriber: certificateDescriber,
+	}
+}
+
+// List returns every certificate bbl manages, including the load balancer
+// certificate that CloudFormation environments keep in IAM.
+func (i Inventory) List(state storage.State) ([]Certificate, error) {
+	certificates, err := Local(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.IAAS == "aws" && state.Stack.CertificateName != "" {
+		iamCertificates, err := i.describe(state.Stack.CertificateName)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(iamCertificates, certificates...)
+	}
+
+	return certificates, nil
+}
+
+// LoadBalancer returns the certificate served by the load balancers, if any.
+func (i Inventory) LoadBalancer(state storage.State) (Certificate, bool, error) {
+	var (
+		certificates []Certificate
+		err          error
+	)
+
+	switch {
+	case state.LB.Cert != "":
+		certificates, err = parse(lbCertificateName, state.LB.Cert)
+	case state.IAAS == "aws" && state.Stack.CertificateName != "":
+		certificates, err = i.describe(state.Stack.CertificateName)
+	}
+	if err != nil {
+		return Certificate{}, false, err
+	}
+
+	if len(certificates) == 0 {
+		return Certificate{}, false, nil
+	}
+
+	return certificates[0], true, nil
+}
+
+func (i Inventory) describe(certificateName string) ([]Certificate, error) {
+	certificate, err := i.certificateDescriber.Describe(certificateName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the iam certificate %s: %s", certificateName, err)
+	}
+
+	name := fmt.Sprintf("iam/%s", certificateName)
+	certificates, err := parse(name, certificate.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := parse(name+" chain", certificate.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(certificates, chain...), nil
+}
+
+// Local returns the certificates bbl keeps in the state: the load balancer
+// certificate and chain, and the certificates in the director and jumpbox
+// vars-stores.
+func Local(state storage.State) ([]Certificate, error) {
+	var certificates []Certificate
+
+	lbCertificates, err := parse(lbCertificateName, state.LB.Cert)
+	if err != nil {
+		return nil, err
+	}
+	certificates = append(certificates, lbCertificates...)
+
+	chain, err := parse(lbCertificateName+" chain", state.LB.Chain)
+	if err != nil {
+		return nil, err
+	}
+	certificates = append(certificates, chain...)
+
+	if state.Jumpbox.Enabled {
+		jumpboxCertificates, err := parseVariables("jumpbox", state.Jumpbox.Variables)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, jumpboxCertificates...)
+	}
+
+	if state.BOSH.Variables == "" {
+		directorCertificates, err := parse("director/director_ssl", state.BOSH.DirectorSSLCertificate)
+		if err != nil {
+			return nil, err
+		}
+		return append(certificates, directorCertificates...), nil
+	}
+
+	directorCertificates, err := parseVariables("director", state.BOSH.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(certificates, directorCertificates...), nil
+}
+
+// ExpiringWithin returns the certificates that expire before now plus the
+// window, including those that have already expired.
+func ExpiringWithin(certificates []Certificate, window time.Duration, now time.Time) []Certificate {
+	var expiring []Certificate
+	for _, certificate := range certificates {
+		if certificate.Expires.Before(now.Add(window)) {
+			expiring = append(expiring, certificate)
+		}
+	}
+
+	return expiring
+}
+
+func parseVariables(prefix, vars string) ([]Certificate, error) {
+	variables := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(vars), &variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s vars-store: %s", prefix, err)
+	}
+
+	var names []string
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var certificates []Certificate
+	for _, name := range names {
+		variable, ok := variables[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		certificate, ok := variable["certificate"].(string)
+		if !ok {
+			continue
+		}
+
+		parsed, err := parse(fmt.Sprintf("%s/%s", prefix, name), certificate)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, parsed...)
+	}
+
+	return certificates, nil
+}
+
+func parse(name, data string) ([]Certificate, error) {
+	var certificates []Certificate
+
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s certificate: %s", name, err)
+		}
+
+		certificates = append(certificates, Certificate{
+			Name:    name,
+			Subject: certificate.Subject.CommonName,
+			Expires: certificate.NotAfter,
+		})
+	}
+
+	return certificates, nil
+}
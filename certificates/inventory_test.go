@@ -0,0 +1,223 @@
+package certificates_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inventory", func() {
+	var (
+		certificateDescriber *fakes.CertificateDescriber
+		inventory            certificates.Inventory
+
+		lbExpiry       time.Time
+		chainExpiry    time.Time
+		directorExpiry time.Time
+		caExpiry       time.Time
+		jumpboxExpiry  time.Time
+	)
+
+	BeforeEach(func() {
+		certificateDescriber = &fakes.CertificateDescriber{}
+		inventory = certificates.NewInventory(certificateDescriber)
+
+		lbExpiry = time.Date(2017, time.July, 1, 0, 0, 0, 0, time.UTC)
+		chainExpiry = time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC)
+		directorExpiry = time.Date(2017, time.August, 1, 0, 0, 0, 0, time.UTC)
+		caExpiry = time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)
+		jumpboxExpiry = time.Date(2017, time.September, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	Describe("List", func() {
+		It("returns the load balancer and vars-store certificates", func() {
+			list, err := inventory.List(storage.State{
+				IAAS: "gcp",
+				LB: storage.LB{
+					Cert:  certificateExpiringAt("*.cf.example.com", lbExpiry),
+					Chain: certificateExpiringAt("some-intermediate", chainExpiry),
+				},
+				Jumpbox: storage.Jumpbox{
+					Enabled:   true,
+					Variables: vars("jumpbox_ssl", certificateExpiringAt("some-jumpbox", jumpboxExpiry)),
+				},
+				BOSH: storage.BOSH{
+					Variables: vars("director_ssl", certificateExpiringAt("some-director", directorExpiry)) +
+						vars("default_ca", certificateExpiringAt("some-ca", caExpiry)) +
+						"admin_password: some-password\n",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(list).To(Equal([]certificates.Certificate{
+				{Name: "load balancer", Subject: "*.cf.example.com", Expires: lbExpiry},
+				{Name: "load balancer chain", Subject: "some-intermediate", Expires: chainExpiry},
+				{Name: "jumpbox/jumpbox_ssl", Subject: "some-jumpbox", Expires: jumpboxExpiry},
+				{Name: "director/default_ca", Subject: "some-ca", Expires: caExpiry},
+				{Name: "director/director_ssl", Subject: "some-director", Expires: directorExpiry},
+			}))
+			Expect(certificateDescriber.DescribeCall.CallCount).To(Equal(0))
+		})
+
+		It("falls back to the director certificate in the state when there is no vars-store", func() {
+			list, err := inventory.List(storage.State{
+				BOSH: storage.BOSH{
+					DirectorSSLCertificate: certificateExpiringAt("some-director", directorExpiry),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(list).To(Equal([]certificates.Certificate{
+				{Name: "director/director_ssl", Subject: "some-director", Expires: directorExpiry},
+			}))
+		})
+
+		It("describes the iam certificate of a cloudformation environment", func() {
+			certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{
+				Body:  certificateExpiringAt("some-lb", lbExpiry),
+				Chain: certificateExpiringAt("some-intermediate", chainExpiry),
+			}
+
+			list, err := inventory.List(storage.State{
+				IAAS:  "aws",
+				Stack: storage.Stack{CertificateName: "some-certificate"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateDescriber.DescribeCall.Receives.CertificateName).To(Equal("some-certificate"))
+			Expect(list).To(Equal([]certificates.Certificate{
+				{Name: "iam/some-certificate", Subject: "some-lb", Expires: lbExpiry},
+				{Name: "iam/some-certificate chain", Subject: "some-intermediate", Expires: chainExpiry},
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the iam certificate cannot be described", func() {
+				certificateDescriber.DescribeCall.Returns.Error = errors.New("failed to describe")
+
+				_, err := inventory.List(storage.State{
+					IAAS:  "aws",
+					Stack: storage.Stack{CertificateName: "some-certificate"},
+				})
+				Expect(err).To(MatchError("failed to describe the iam certificate some-certificate: failed to describe"))
+			})
+
+			It("returns an error when a certificate cannot be parsed", func() {
+				_, err := inventory.List(storage.State{
+					LB: storage.LB{
+						Cert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})),
+					},
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse the load balancer certificate")))
+			})
+
+			It("returns an error when a vars-store cannot be read", func() {
+				_, err := inventory.List(storage.State{
+					BOSH: storage.BOSH{Variables: "%%%"},
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to read the director vars-store")))
+			})
+		})
+	})
+
+	Describe("LoadBalancer", func() {
+		It("returns the load balancer certificate from the state", func() {
+			certificate, ok, err := inventory.LoadBalancer(storage.State{
+				LB: storage.LB{
+					Cert:  certificateExpiringAt("some-lb", lbExpiry),
+					Chain: certificateExpiringAt("some-intermediate", chainExpiry),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ok).To(BeTrue())
+			Expect(certificate).To(Equal(certificates.Certificate{Name: "load balancer", Subject: "some-lb", Expires: lbExpiry}))
+		})
+
+		It("returns the iam certificate of a cloudformation environment", func() {
+			certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{
+				Body: certificateExpiringAt("some-lb", lbExpiry),
+			}
+
+			certificate, ok, err := inventory.LoadBalancer(storage.State{
+				IAAS:  "aws",
+				Stack: storage.Stack{CertificateName: "some-certificate"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ok).To(BeTrue())
+			Expect(certificate.Expires).To(Equal(lbExpiry))
+		})
+
+		It("returns false when there are no load balancers", func() {
+			_, ok, err := inventory.LoadBalancer(storage.State{IAAS: "aws"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ok).To(BeFalse())
+			Expect(certificateDescriber.DescribeCall.CallCount).To(Equal(0))
+		})
+	})
+
+	Describe("ExpiringWithin", func() {
+		It("returns the certificates that expire within the window, including expired ones", func() {
+			now := time.Date(2017, time.July, 20, 0, 0, 0, 0, time.UTC)
+			list := []certificates.Certificate{
+				{Name: "expired", Expires: now.Add(-time.Hour)},
+				{Name: "expiring", Expires: now.Add(29 * 24 * time.Hour)},
+				{Name: "valid", Expires: now.Add(31 * 24 * time.Hour)},
+			}
+
+			Expect(certificates.ExpiringWithin(list, 30*24*time.Hour, now)).To(Equal([]certificates.Certificate{
+				{Name: "expired", Expires: now.Add(-time.Hour)},
+				{Name: "expiring", Expires: now.Add(29 * 24 * time.Hour)},
+			}))
+		})
+	})
+})
+
+func certificateExpiringAt(commonName string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func vars(name, certificate string) string {
+	return fmt.Sprintf("%s:\n  certificate: |\n    %s\n", name, indent(certificate))
+}
+
+func indent(text string) string {
+	var indented []byte
+	for _, c := range []byte(text[:len(text)-1]) {
+		indented = append(indented, c)
+		if c == '\n' {
+			indented = append(indented, []byte("    ")...)
+		}
+	}
+	return string(indented)
+}
@@ -107,9 +107,31 @@ func createOp(opType, opPath string, value interface{}) op {
 }
 
 func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]op, error) {
-	azs, err := a.availabilityZoneRetriever.Retrieve(state.AWS.Region)
-	if err != nil {
-		return []op{}, err
+	var (
+		azs              []string
+		terraformOutputs map[string]interface{}
+		err              error
+	)
+	if state.AWS.ExistingVPCID != "" {
+		terraformOutputs, err = a.terraformManager.GetOutputs(state)
+		if err != nil {
+			return []op{}, err
+		}
+
+		azs, err = existingSubnetAZs(terraformOutputs)
+		if err != nil {
+			return []op{}, err
+		}
+	} else {
+		azs, err = a.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+		if err != nil {
+			return []op{}, err
+		}
+
+		terraformOutputs, err = a.terraformManager.GetOutputs(state)
+		if err != nil {
+			return []op{}, err
+		}
 	}
 
 	ops := []op{}
@@ -123,11 +145,6 @@ func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]o
 		ops = append(ops, op)
 	}
 
-	terraformOutputs, err := a.terraformManager.GetOutputs(state)
-	if err != nil {
-		return []op{}, err
-	}
-
 	subnetCIDRs, ok := terraformOutputs["internal_subnet_cidrs"].([]interface{})
 	if !ok {
 		return []op{}, errors.New("missing internal_subnet_cidrs terraform output")
@@ -155,6 +172,12 @@ func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]o
 			return []op{}, err
 		}
 
+		// An existing vpc has no separate subnet for the director, so it
+		// shares the first subnet with the deployments.
+		if directorIP, ok := terraformOutputs["internal_ip"].(string); ok && i == 0 && state.AWS.ExistingVPCID != "" {
+			subnet.Reserved = append(subnet.Reserved, directorIP)
+		}
+
 		subnets = append(subnets, subnet)
 	}
 
@@ -226,6 +249,22 @@ func (a TerraformOpsGenerator) generateTerraformAWSOps(state storage.State) ([]o
 	return ops, nil
 }
 
+// existingSubnetAZs returns one availability zone per subnet, so subnets that
+// share an availability zone become separate bosh azs.
+func existingSubnetAZs(terraformOutputs map[string]interface{}) ([]string, error) {
+	subnetAZs, ok := terraformOutputs["internal_subnet_availability_zones"].([]interface{})
+	if !ok {
+		return []string{}, errors.New("missing internal_subnet_availability_zones terraform output")
+	}
+
+	azs := []string{}
+	for _, subnetAZ := range subnetAZs {
+		azs = append(azs, subnetAZ.(string))
+	}
+
+	return azs, nil
+}
+
 func generateNetworkSubnet(az, cidr, subnet, securityGroup string) (networkSubnet, error) {
 	parsedCidr, err := bosh.ParseCIDRBlock(cidr)
 	if err != nil {
@@ -132,6 +132,50 @@ var _ = Describe("TerraformOpsGenerator", func() {
 			})
 		})
 
+		Context("when the environment uses an existing vpc", func() {
+			BeforeEach(func() {
+				incomingState.AWS.ExistingVPCID = "some-vpc-id"
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_subnet_cidrs"] = []interface{}{
+					"172.16.4.0/24",
+					"172.16.5.0/24",
+				}
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_subnet_ids"] = []interface{}{
+					"some-existing-subnet-1",
+					"some-existing-subnet-2",
+				}
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_subnet_availability_zones"] = []interface{}{
+					"us-east-1d",
+					"us-east-1e",
+				}
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_ip"] = "172.16.4.6"
+			})
+
+			It("uses the availability zones of the existing subnets", func() {
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(BeEmpty())
+				Expect(opsYAML).To(ContainSubstring("availability_zone: us-east-1d"))
+				Expect(opsYAML).To(ContainSubstring("availability_zone: us-east-1e"))
+				Expect(opsYAML).NotTo(ContainSubstring("us-east-1a"))
+				Expect(opsYAML).To(ContainSubstring("subnet: some-existing-subnet-1"))
+				Expect(opsYAML).To(ContainSubstring("subnet: some-existing-subnet-2"))
+			})
+
+			It("reserves the director ip in the first subnet", func() {
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(ContainSubstring("- 172.16.4.6"))
+			})
+
+			It("returns an error when the subnet availability zones output is missing", func() {
+				delete(terraformManager.GetOutputsCall.Returns.Outputs, "internal_subnet_availability_zones")
+				_, err := opsGenerator.Generate(incomingState)
+				Expect(err).To(MatchError("missing internal_subnet_availability_zones terraform output"))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when az retriever fails to retrieve", func() {
 				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve")
@@ -1,6 +1,7 @@
 package gcp
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -45,7 +46,8 @@ type network struct {
 }
 
 type networkSubnet struct {
-	AZ              string
+	AZ              string   `yaml:"az,omitempty"`
+	AZs             []string `yaml:"azs,omitempty"`
 	Gateway         string
 	Range           string
 	Reserved        []string
@@ -57,6 +59,7 @@ type subnetCloudProperties struct {
 	EphemeralExternalIP bool   `yaml:"ephemeral_external_ip"`
 	NetworkName         string `yaml:"network_name"`
 	SubnetworkName      string `yaml:"subnetwork_name"`
+	XPNHostProjectID    string `yaml:"xpn_host_project_id,omitempty"`
 	Tags                []string
 }
 
@@ -127,21 +130,30 @@ func (o *OpsGenerator) generateGCPOps(state storage.State) ([]op, error) {
 	}
 
 	var subnets []networkSubnet
-	for i, _ := range zones {
-		cidr := fmt.Sprintf("10.0.%d.0/20", 16*(i+1))
-		subnet, err := generateNetworkSubnet(
-			fmt.Sprintf("z%d", i+1),
-			cidr,
-			outputs["network_name"].(string),
-			outputs["subnetwork_name"].(string),
-			outputs["bosh_open_tag_name"].(string),
-			outputs["internal_tag_name"].(string),
-		)
+	if state.GCP.ExistingNetwork != "" {
+		subnet, err := generateExistingNetworkSubnet(state, zones, outputs)
 		if err != nil {
 			return []op{}, err
 		}
 
 		subnets = append(subnets, subnet)
+	} else {
+		for i, _ := range zones {
+			cidr := fmt.Sprintf("10.0.%d.0/20", 16*(i+1))
+			subnet, err := generateNetworkSubnet(
+				fmt.Sprintf("z%d", i+1),
+				cidr,
+				outputs["network_name"].(string),
+				outputs["subnetwork_name"].(string),
+				outputs["bosh_open_tag_name"].(string),
+				outputs["internal_tag_name"].(string),
+			)
+			if err != nil {
+				return []op{}, err
+			}
+
+			subnets = append(subnets, subnet)
+		}
 	}
 
 	ops = append(ops, createOp("replace", "/networks/-", network{
@@ -202,6 +214,48 @@ func (o *OpsGenerator) generateGCPOps(state storage.State) ([]op, error) {
 	return ops, nil
 }
 
+// generateExistingNetworkSubnet spans every zone with the existing
+// subnetwork, since bbl cannot carve per-zone ranges out of a network it does
+// not own.
+func generateExistingNetworkSubnet(state storage.State, zones []string, outputs map[string]interface{}) (networkSubnet, error) {
+	cidr, ok := outputs["internal_cidr"].(string)
+	if !ok {
+		return networkSubnet{}, errors.New("missing internal_cidr terraform output")
+	}
+
+	subnet, err := generateNetworkSubnet(
+		"",
+		cidr,
+		outputs["network_name"].(string),
+		outputs["subnetwork_name"].(string),
+		outputs["bosh_open_tag_name"].(string),
+		outputs["internal_tag_name"].(string),
+	)
+	if err != nil {
+		return networkSubnet{}, err
+	}
+
+	for i := range zones {
+		subnet.AZs = append(subnet.AZs, fmt.Sprintf("z%d", i+1))
+	}
+
+	if gateway, ok := outputs["internal_gw"].(string); ok {
+		subnet.Gateway = gateway
+	}
+
+	for _, name := range []string{"jumpbox_internal_ip", "internal_ip"} {
+		if ip, ok := outputs[name].(string); ok {
+			subnet.Reserved = append(subnet.Reserved, ip)
+		}
+	}
+
+	if state.GCP.NetworkProjectID != "" && state.GCP.NetworkProjectID != state.GCP.ProjectID {
+		subnet.CloudProperties.XPNHostProjectID = state.GCP.NetworkProjectID
+	}
+
+	return subnet, nil
+}
+
 func generateNetworkSubnet(az, cidr, networkName, subnetworkName, boshTag, internalTag string) (networkSubnet, error) {
 	parsedCidr, err := bosh.ParseCIDRBlock(cidr)
 	if err != nil {
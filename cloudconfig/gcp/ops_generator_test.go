@@ -65,6 +65,37 @@ var _ = Describe("GCPOpsGenerator", func() {
 			Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsFile))
 		})
 
+		Context("when the environment uses an existing network", func() {
+			BeforeEach(func() {
+				incomingState.GCP.ProjectID = "some-project-id"
+				incomingState.GCP.ExistingNetwork = "some-network-name"
+				incomingState.GCP.ExistingSubnetwork = "some-subnetwork-name"
+				incomingState.GCP.NetworkProjectID = "some-host-project-id"
+
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_cidr"] = "172.16.0.0/20"
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_gw"] = "172.16.0.1"
+				terraformManager.GetOutputsCall.Returns.Outputs["internal_ip"] = "172.16.0.6"
+				terraformManager.GetOutputsCall.Returns.Outputs["jumpbox_internal_ip"] = "172.16.0.5"
+			})
+
+			It("uses a single subnet across every zone in the existing subnetwork", func() {
+				expectedOpsFile, err := ioutil.ReadFile(filepath.Join("fixtures", "gcp-existing-network-ops.yml"))
+				Expect(err).NotTo(HaveOccurred())
+
+				opsYAML, err := opsGenerator.Generate(incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(opsYAML).To(gomegamatchers.MatchYAML(expectedOpsFile))
+			})
+
+			It("returns an error when the internal_cidr output is missing", func() {
+				delete(terraformManager.GetOutputsCall.Returns.Outputs, "internal_cidr")
+
+				_, err := opsGenerator.Generate(incomingState)
+				Expect(err).To(MatchError("missing internal_cidr terraform output"))
+			})
+		})
+
 		DescribeTable("returns an ops file with additional vm extensions to support lb",
 			func(lbType string, lbOutputs map[string]interface{}) {
 				incomingState.LB.Type = lbType
@@ -2,15 +2,18 @@ package cloudconfig
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"golang.org/x/net/proxy"
 
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -28,6 +31,7 @@ type Manager struct {
 	socks5Proxy        socks5Proxy
 	terraformManager   terraformManager
 	sshKeyGetter       sshKeyGetter
+	timeout            time.Duration
 }
 
 type logger interface {
@@ -35,7 +39,7 @@ type logger interface {
 }
 
 type command interface {
-	Run(stdout io.Writer, workingDirectory string, args []string) error
+	Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error
 }
 
 type opsGenerator interface {
@@ -60,7 +64,7 @@ type sshKeyGetter interface {
 }
 
 func NewManager(logger logger, cmd command, opsGenerator opsGenerator, boshClientProvider boshClientProvider,
-	socks5Proxy socks5Proxy, terraformManager terraformManager, sshKeyGetter sshKeyGetter) Manager {
+	socks5Proxy socks5Proxy, terraformManager terraformManager, sshKeyGetter sshKeyGetter, timeout time.Duration) Manager {
 	return Manager{
 		logger:             logger,
 		command:            cmd,
@@ -69,10 +73,11 @@ func NewManager(logger logger, cmd command, opsGenerator opsGenerator, boshClien
 		socks5Proxy:        socks5Proxy,
 		terraformManager:   terraformManager,
 		sshKeyGetter:       sshKeyGetter,
+		timeout:            timeout,
 	}
 }
 
-func (m Manager) Generate(state storage.State) (string, error) {
+func (m Manager) Generate(ctx context.Context, state storage.State) (string, error) {
 	buf := bytes.NewBuffer([]byte{})
 	workingDir, err := tempDir("", "")
 	if err != nil {
@@ -99,15 +104,15 @@ func (m Manager) Generate(state storage.State) (string, error) {
 		"-o", fmt.Sprintf("%s/ops.yml", workingDir),
 	}
 
-	err = m.command.Run(buf, workingDir, args)
+	err = m.command.Run(ctx, buf, workingDir, args)
 	if err != nil {
-		return "", err
+		return "", bosh.NewInterpolateError(storage.CloudConfigPhase, buf.String(), err)
 	}
 
 	return buf.String(), nil
 }
 
-func (m Manager) Update(state storage.State) error {
+func (m Manager) Update(ctx context.Context, state storage.State) error {
 	boshClient := m.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword)
 
 	if state.Jumpbox.Enabled {
@@ -134,17 +139,28 @@ func (m Manager) Update(state storage.State) error {
 		boshClient.ConfigureHTTPClient(socks5Client)
 	}
 
+	updateCtx, cancel := helpers.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
 	m.logger.Step("generating cloud config")
-	cloudConfig, err := m.Generate(state)
+	cloudConfig, err := m.Generate(updateCtx, state)
 	if err != nil {
-		return err
+		return NewManagerUpdateError(m.timeoutError(ctx, updateCtx, err))
 	}
 
 	m.logger.Step("applying cloud config")
-	err = boshClient.UpdateCloudConfig([]byte(cloudConfig))
+	err = boshClient.UpdateCloudConfig(updateCtx, []byte(cloudConfig))
 	if err != nil {
-		return err
+		return NewManagerUpdateError(m.timeoutError(ctx, updateCtx, err))
 	}
 
 	return nil
 }
+
+func (m Manager) timeoutError(ctx, updateCtx context.Context, err error) error {
+	if updateCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return helpers.NewTimeoutError(storage.CloudConfigPhase, m.timeout)
+	}
+
+	return err
+}
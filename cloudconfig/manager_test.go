@@ -1,12 +1,14 @@
 package cloudconfig_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/net/proxy"
 
@@ -56,7 +58,7 @@ var _ = Describe("Manager", func() {
 			return tempDir, nil
 		})
 
-		cmd.RunStub = func(stdout io.Writer, workingDirectory string, args []string) error {
+		cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 			stdout.Write([]byte("some-cloud-config"))
 			return nil
 		}
@@ -75,7 +77,7 @@ var _ = Describe("Manager", func() {
 		baseCloudConfig, err = ioutil.ReadFile("fixtures/base-cloud-config.yml")
 		Expect(err).NotTo(HaveOccurred())
 
-		manager = cloudconfig.NewManager(logger, cmd, opsGenerator, boshClientProvider, socks5Proxy, terraformManager, sshKeyGetter)
+		manager = cloudconfig.NewManager(logger, cmd, opsGenerator, boshClientProvider, socks5Proxy, terraformManager, sshKeyGetter, 0)
 	})
 
 	AfterEach(func() {
@@ -89,7 +91,7 @@ var _ = Describe("Manager", func() {
 				"-o", fmt.Sprintf("%s/ops.yml", tempDir),
 			}
 
-			cloudConfigYAML, err := manager.Generate(incomingState)
+			cloudConfigYAML, err := manager.Generate(context.Background(), incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			cloudConfig, err := ioutil.ReadFile(fmt.Sprintf("%s/cloud-config.yml", tempDir))
@@ -103,7 +105,7 @@ var _ = Describe("Manager", func() {
 			Expect(string(ops)).To(Equal("some-ops"))
 
 			Expect(cmd.RunCallCount()).To(Equal(1))
-			_, workingDirectory, args := cmd.RunArgsForCall(0)
+			_, _, workingDirectory, args := cmd.RunArgsForCall(0)
 			Expect(workingDirectory).To(Equal(tempDir))
 			Expect(args).To(Equal(expectedArgs))
 
@@ -123,7 +125,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
+					_, err := manager.Generate(context.Background(), storage.State{})
 					Expect(err).To(MatchError("failed to create temp dir"))
 				})
 			})
@@ -143,7 +145,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
+					_, err := manager.Generate(context.Background(), storage.State{})
 					Expect(err).To(MatchError("failed to write file"))
 				})
 			})
@@ -154,7 +156,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
+					_, err := manager.Generate(context.Background(), storage.State{})
 					Expect(err).To(MatchError("failed to generate"))
 				})
 			})
@@ -174,7 +176,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
+					_, err := manager.Generate(context.Background(), storage.State{})
 					Expect(err).To(MatchError("failed to write file"))
 				})
 			})
@@ -185,7 +187,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := manager.Generate(storage.State{})
+					_, err := manager.Generate(context.Background(), storage.State{})
 					Expect(err).To(MatchError("failed to run"))
 				})
 			})
@@ -195,7 +197,7 @@ var _ = Describe("Manager", func() {
 	Describe("Update", func() {
 		Context("when no jumpbox exists", func() {
 			It("logs steps taken", func() {
-				err := manager.Update(incomingState)
+				err := manager.Update(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(logger.StepCall.Messages).To(Equal([]string{
 					"generating cloud config",
@@ -204,7 +206,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("updates the bosh director with a cloud config provided a valid bbl state", func() {
-				err := manager.Update(incomingState)
+				err := manager.Update(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("some-director-address"))
@@ -214,6 +216,23 @@ var _ = Describe("Manager", func() {
 				Expect(boshClient.UpdateCloudConfigCall.Receives.Yaml).To(Equal([]byte("some-cloud-config")))
 			})
 
+			It("generates the cloud config with a context that is cancelled along with the provided context", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				var runContextErr error
+				cmd.RunStub = func(runCtx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+					cancel()
+					runContextErr = runCtx.Err()
+					stdout.Write([]byte("some-cloud-config"))
+					return nil
+				}
+
+				err := manager.Update(ctx, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(runContextErr).To(Equal(context.Canceled))
+			})
+
 			Context("failure cases", func() {
 				Context("when manager generate's command fails to run", func() {
 					BeforeEach(func() {
@@ -221,9 +240,23 @@ var _ = Describe("Manager", func() {
 					})
 
 					It("returns an error", func() {
-						err := manager.Update(storage.State{})
+						err := manager.Update(context.Background(), storage.State{})
 						Expect(err).To(MatchError("failed to run"))
 					})
+
+					It("returns the cloud-config phase and the interpolate output with the error", func() {
+						cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+							stdout.Write([]byte("some-interpolate-output"))
+							return errors.New("failed to run")
+						}
+
+						err := manager.Update(context.Background(), storage.State{})
+
+						updateError, ok := err.(cloudconfig.ManagerUpdateError)
+						Expect(ok).To(BeTrue())
+						Expect(updateError.Phase()).To(Equal("cloud-config"))
+						Expect(updateError.Output()).To(Equal("some-interpolate-output"))
+					})
 				})
 
 				Context("when bosh client fails to update cloud config", func() {
@@ -232,9 +265,37 @@ var _ = Describe("Manager", func() {
 					})
 
 					It("returns an error", func() {
-						err := manager.Update(storage.State{})
+						err := manager.Update(context.Background(), storage.State{})
 						Expect(err).To(MatchError("failed to update"))
 					})
+
+					It("returns the cloud-config phase with the error", func() {
+						err := manager.Update(context.Background(), storage.State{})
+
+						updateError, ok := err.(cloudconfig.ManagerUpdateError)
+						Expect(ok).To(BeTrue())
+						Expect(updateError.Phase()).To(Equal("cloud-config"))
+						Expect(updateError.Output()).To(BeEmpty())
+					})
+				})
+
+				Context("when the cloud config is not applied before the timeout", func() {
+					BeforeEach(func() {
+						manager = cloudconfig.NewManager(logger, cmd, opsGenerator, boshClientProvider, socks5Proxy, terraformManager, sshKeyGetter, time.Millisecond)
+						cmd.RunStub = func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
+							<-ctx.Done()
+							return ctx.Err()
+						}
+					})
+
+					It("returns an error naming the cloud-config phase", func() {
+						err := manager.Update(context.Background(), storage.State{})
+						Expect(err).To(MatchError("cloud-config timed out after 1ms"))
+
+						updateError, ok := err.(cloudconfig.ManagerUpdateError)
+						Expect(ok).To(BeTrue())
+						Expect(updateError.Phase()).To(Equal("cloud-config"))
+					})
 				})
 			})
 		})
@@ -271,7 +332,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("logs steps taken", func() {
-				err := manager.Update(incomingState)
+				err := manager.Update(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(logger.StepCall.Messages).To(Equal([]string{
 					"starting socks5 proxy",
@@ -281,7 +342,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("starts a socks5 proxy", func() {
-				err := manager.Update(incomingState)
+				err := manager.Update(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(sshKeyGetter.GetCall.Receives.State).To(Equal(incomingState))
 				Expect(terraformManager.GetOutputsCall.Receives.BBLState).To(Equal(incomingState))
@@ -293,7 +354,7 @@ var _ = Describe("Manager", func() {
 
 			It("configures the bosh client", func() {
 				socks5Proxy.AddrCall.Returns.Addr = "some-socks-proxy-addr"
-				err := manager.Update(incomingState)
+				err := manager.Update(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(boshClient.ConfigureHTTPClientCall.CallCount).To(Equal(1))
@@ -310,19 +371,19 @@ var _ = Describe("Manager", func() {
 			Context("failure cases", func() {
 				It("returns an error when sshKeyGetter.Get fails", func() {
 					sshKeyGetter.GetCall.Returns.Error = errors.New("failed to get jumpbox ssh key")
-					err := manager.Update(incomingState)
+					err := manager.Update(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to get jumpbox ssh key"))
 				})
 
 				It("returns an error when terraformManager.GetOutputs fails", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get terraform outputs")
-					err := manager.Update(incomingState)
+					err := manager.Update(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to get terraform outputs"))
 				})
 
 				It("returns an error when the socks5Proxy fails to start", func() {
 					socks5Proxy.StartCall.Returns.Error = errors.New("failed to start socks5 proxy")
-					err := manager.Update(incomingState)
+					err := manager.Update(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to start socks5 proxy"))
 				})
 
@@ -330,7 +391,7 @@ var _ = Describe("Manager", func() {
 					cloudconfig.SetProxySOCKS5(func(network, addr string, auth *proxy.Auth, forward proxy.Dialer) (proxy.Dialer, error) {
 						return nil, errors.New("failed to create socks5 proxy client")
 					})
-					err := manager.Update(incomingState)
+					err := manager.Update(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to create socks5 proxy client"))
 				})
 			})
@@ -0,0 +1,30 @@
+package cloudconfig
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type ManagerUpdateError struct {
+	err error
+}
+
+func NewManagerUpdateError(err error) ManagerUpdateError {
+	return ManagerUpdateError{
+		err: err,
+	}
+}
+
+func (c ManagerUpdateError) Error() string {
+	return c.err.Error()
+}
+
+func (c ManagerUpdateError) Phase() string {
+	return storage.CloudConfigPhase
+}
+
+func (c ManagerUpdateError) Output() string {
+	if outputError, ok := c.err.(interface {
+		Output() string
+	}); ok {
+		return outputError.Output()
+	}
+	return ""
+}
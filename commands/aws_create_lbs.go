@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 
@@ -21,15 +22,23 @@ type AWSCreateLBs struct {
 	stateValidator            stateValidator
 	terraformManager          terraformManager
 	environmentValidator      environmentValidator
+	certificateGenerator      certificateGenerator
+	acmeManager               acmeManager
 }
 
 type AWSCreateLBsConfig struct {
-	LBType       string
-	CertPath     string
-	KeyPath      string
-	ChainPath    string
-	Domain       string
-	SkipIfExists bool
+	LBType           string
+	CertPath         string
+	KeyPath          string
+	ChainPath        string
+	Domain           string
+	SkipIfExists     bool
+	GenerateCert     bool
+	CACertPath       string
+	CAKeyPath        string
+	ACME             bool
+	ACMEDirectoryURL string
+	ACMEEmail        string
 }
 
 type certificateManager interface {
@@ -39,7 +48,7 @@ type certificateManager interface {
 }
 
 type certificateValidator interface {
-	Validate(command, certPath, keyPath, chainPath string) error
+	Validate(command, certPath, keyPath, chainPath, domain string) error
 }
 
 type environmentValidator interface {
@@ -53,7 +62,8 @@ type guidGenerator interface {
 func NewAWSCreateLBs(logger logger, credentialValidator credentialValidator, certificateManager certificateManager,
 	infrastructureManager infrastructureManager, availabilityZoneRetriever availabilityZoneRetriever,
 	cloudConfigManager cloudConfigManager, certificateValidator certificateValidator,
-	guidGenerator guidGenerator, stateStore stateStore, terraformManager terraformManager, environmentValidator environmentValidator) AWSCreateLBs {
+	guidGenerator guidGenerator, stateStore stateStore, terraformManager terraformManager, environmentValidator environmentValidator,
+	certificateGenerator certificateGenerator, acmeManager acmeManager) AWSCreateLBs {
 	return AWSCreateLBs{
 		logger:                    logger,
 		certificateManager:        certificateManager,
@@ -66,10 +76,12 @@ func NewAWSCreateLBs(logger logger, credentialValidator credentialValidator, cer
 		stateStore:                stateStore,
 		terraformManager:          terraformManager,
 		environmentValidator:      environmentValidator,
+		certificateGenerator:      certificateGenerator,
+		acmeManager:               acmeManager,
 	}
 }
 
-func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) error {
+func (c AWSCreateLBs) Execute(ctx context.Context, config AWSCreateLBsConfig, state storage.State) error {
 	err := c.credentialValidator.Validate()
 	if err != nil {
 		return err
@@ -89,7 +101,12 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 	}
 
 	if state.TFState != "" {
-		if config.LBType == "cf" || config.LBType == "concourse" {
+		if !config.GenerateCert && !config.ACME {
+			err = c.certificateValidator.Validate(CreateLBsCommand, config.CertPath, config.KeyPath, config.ChainPath, config.Domain)
+			if err != nil {
+				return err
+			}
+
 			certContents, err := ioutil.ReadFile(config.CertPath)
 			if err != nil {
 				return err
@@ -102,6 +119,8 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 
 			state.LB.Cert = string(certContents)
 			state.LB.Key = string(keyContents)
+			state.LB.CA = ""
+			state.LB.ACME = nil
 
 			if config.ChainPath != "" {
 				chainContents, err := ioutil.ReadFile(config.ChainPath)
@@ -118,13 +137,26 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 		}
 
 		state.LB.Type = config.LBType
-
-		state, err = c.terraformManager.Apply(state)
+		// The new certificate is named by terraform rather than after the
+		// one bbl migrate imported from the stack.
+		state.Stack.CertificateName = ""
+
+		switch {
+		case config.ACME:
+			state, err = applyWithACMECertificate(ctx, c.certificateGenerator, c.acmeManager, c.terraformManager, c.stateStore,
+				storage.ACME{DirectoryURL: config.ACMEDirectoryURL, Email: config.ACMEEmail}, state)
+		case config.GenerateCert:
+			state.LB.ACME = nil
+			state, err = applyWithGeneratedCertificate(ctx, c.certificateGenerator, c.terraformManager, c.stateStore,
+				generatedCertificateConfig{caCertPath: config.CACertPath, caKeyPath: config.CAKeyPath}, state)
+		default:
+			state, err = c.terraformManager.Apply(ctx, state)
+		}
 		if err != nil {
 			return handleTerraformError(err, c.stateStore)
 		}
 	} else {
-		err = c.certificateValidator.Validate(CreateLBsCommand, config.CertPath, config.KeyPath, config.ChainPath)
+		err = c.certificateValidator.Validate(CreateLBsCommand, config.CertPath, config.KeyPath, config.ChainPath, config.Domain)
 		if err != nil {
 			return err
 		}
@@ -155,7 +187,7 @@ func (c AWSCreateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 	}
 
 	if !state.NoDirector {
-		err = c.cloudConfigManager.Update(state)
+		err = c.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
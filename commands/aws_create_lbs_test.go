@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +10,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	. "github.com/onsi/ginkgo"
@@ -31,6 +33,8 @@ var _ = Describe("AWS Create LBs", func() {
 			guidGenerator             *fakes.GuidGenerator
 			stateStore                *fakes.StateStore
 			environmentValidator      *fakes.EnvironmentValidator
+			certificateGenerator      *fakes.CertificateGenerator
+			acmeManager               *fakes.ACMEManager
 			incomingState             storage.State
 		)
 
@@ -46,6 +50,8 @@ var _ = Describe("AWS Create LBs", func() {
 			guidGenerator = &fakes.GuidGenerator{}
 			stateStore = &fakes.StateStore{}
 			environmentValidator = &fakes.EnvironmentValidator{}
+			certificateGenerator = &fakes.CertificateGenerator{}
+			acmeManager = &fakes.ACMEManager{}
 
 			infrastructureManager.ExistsCall.Returns.Exists = true
 
@@ -74,17 +80,17 @@ var _ = Describe("AWS Create LBs", func() {
 
 			command = commands.NewAWSCreateLBs(logger, credentialValidator, certificateManager, infrastructureManager,
 				availabilityZoneRetriever, cloudConfigManager, certificateValidator, guidGenerator,
-				stateStore, terraformManager, environmentValidator)
+				stateStore, terraformManager, environmentValidator, certificateGenerator, acmeManager)
 		})
 
 		It("returns an error if credential validator fails", func() {
 			credentialValidator.ValidateCall.Returns.Error = errors.New("failed to validate aws credentials")
-			err := command.Execute(commands.AWSCreateLBsConfig{}, storage.State{})
+			err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{}, storage.State{})
 			Expect(err).To(MatchError("failed to validate aws credentials"))
 		})
 
 		It("uploads a cert and key", func() {
-			err := command.Execute(commands.AWSCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 				LBType:   "concourse",
 				CertPath: "temp/some-cert.crt",
 				KeyPath:  "temp/some-key.key",
@@ -99,11 +105,12 @@ var _ = Describe("AWS Create LBs", func() {
 		})
 
 		It("uploads a cert and key with chain", func() {
-			err := command.Execute(commands.AWSCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 				LBType:    "concourse",
 				CertPath:  "temp/some-cert.crt",
 				KeyPath:   "temp/some-key.key",
 				ChainPath: "temp/some-chain.crt",
+				Domain:    "some-domain",
 			}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -113,6 +120,7 @@ var _ = Describe("AWS Create LBs", func() {
 			Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal("temp/some-cert.crt"))
 			Expect(certificateValidator.ValidateCall.Receives.KeyPath).To(Equal("temp/some-key.key"))
 			Expect(certificateValidator.ValidateCall.Receives.ChainPath).To(Equal("temp/some-chain.crt"))
+			Expect(certificateValidator.ValidateCall.Receives.Domain).To(Equal("some-domain"))
 		})
 
 		Context("when cloudformation was used to create infrastructure", func() {
@@ -122,7 +130,7 @@ var _ = Describe("AWS Create LBs", func() {
 					ARN: "some-certificate-arn",
 				}
 
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -217,20 +225,52 @@ var _ = Describe("AWS Create LBs", func() {
 				})
 
 				It("creates a load balancer with certificate using terraform", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "cf",
 						CertPath: certPath,
 						KeyPath:  keyPath,
 					}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(1))
+					Expect(certificateValidator.ValidateCall.Receives.Command).To(Equal("create-lbs"))
+					Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal(certPath))
+					Expect(certificateValidator.ValidateCall.Receives.KeyPath).To(Equal(keyPath))
 					Expect(infrastructureManager.UpdateCall.CallCount).To(Equal(0))
 					Expect(certificateManager.CreateCall.CallCount).To(Equal(0))
 					Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(statePassedToTerraform))
 					Expect(stateStore.SetCall.Receives[0].State).To(Equal(stateReturnedFromTerraform))
 				})
 
+				It("does not apply a certificate that fails validation", func() {
+					certificateValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
+
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+						Domain:   "some-domain",
+					}, incomingState)
+					Expect(err).To(MatchError("failed to validate"))
+
+					Expect(certificateValidator.ValidateCall.Receives.Domain).To(Equal("some-domain"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+					Expect(stateStore.SetCall.CallCount).To(Equal(0))
+				})
+
+				It("forgets the certificate that bbl migrate imported from the stack", func() {
+					incomingState.Stack = storage.Stack{CertificateName: "some-certificate-name", Migrated: true}
+
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.Stack).To(Equal(storage.Stack{Migrated: true}))
+				})
+
 				Context("when the optional chain is provided", func() {
 					BeforeEach(func() {
 						statePassedToTerraform.LB.Chain = "some-chain"
@@ -241,7 +281,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("creates a load balancer with certificate using terraform", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:    "cf",
 							CertPath:  certPath,
 							KeyPath:   keyPath,
@@ -249,6 +289,7 @@ var _ = Describe("AWS Create LBs", func() {
 						}, incomingState)
 						Expect(err).NotTo(HaveOccurred())
 
+						Expect(certificateValidator.ValidateCall.Receives.ChainPath).To(Equal(chainPath))
 						Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(statePassedToTerraform))
 						Expect(stateStore.SetCall.Receives[0].State).To(Equal(stateReturnedFromTerraform))
 					})
@@ -269,7 +310,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("creates dns records for provided domain", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:   "cf",
 							CertPath: certPath,
 							KeyPath:  keyPath,
@@ -298,7 +339,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("does not change domain", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:   "cf",
 							CertPath: certPath,
 							KeyPath:  keyPath,
@@ -326,14 +367,17 @@ var _ = Describe("AWS Create LBs", func() {
 				})
 
 				It("creates a load balancer with certificate using terraform", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: certPath,
 						KeyPath:  keyPath,
 					}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
-					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(1))
+					Expect(certificateValidator.ValidateCall.Receives.Command).To(Equal("create-lbs"))
+					Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal(certPath))
+					Expect(certificateValidator.ValidateCall.Receives.KeyPath).To(Equal(keyPath))
 					Expect(infrastructureManager.UpdateCall.CallCount).To(Equal(0))
 					Expect(certificateManager.CreateCall.CallCount).To(Equal(0))
 					Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(statePassedToTerraform))
@@ -350,7 +394,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("creates a load balancer with certificate using terraform", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:    "concourse",
 							CertPath:  certPath,
 							KeyPath:   keyPath,
@@ -358,11 +402,247 @@ var _ = Describe("AWS Create LBs", func() {
 						}, incomingState)
 						Expect(err).NotTo(HaveOccurred())
 
+						Expect(certificateValidator.ValidateCall.Receives.ChainPath).To(Equal(chainPath))
 						Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(statePassedToTerraform))
 						Expect(stateStore.SetCall.Receives[0].State).To(Equal(stateReturnedFromTerraform))
 					})
 				})
 			})
+
+			Context("when --generate-cert is provided", func() {
+				var appliedStates []storage.State
+
+				BeforeEach(func() {
+					appliedStates = []storage.State{}
+					terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+						appliedStates = append(appliedStates, state)
+						state.TFState = "some-updated-tf-state"
+						return state, nil
+					}
+
+					certificateGenerator.GenerateCACall.Returns.CAData = ssl.CAData{
+						CA:         []byte("some-ca"),
+						PrivateKey: []byte("some-ca-key"),
+					}
+					certificateGenerator.GenerateFromCACall.Stub = func(commonName string) (ssl.KeyPair, error) {
+						return ssl.KeyPair{
+							CA:          []byte("some-ca"),
+							Certificate: []byte("cert-for-" + commonName),
+							PrivateKey:  []byte("key-for-" + commonName),
+						}, nil
+					}
+				})
+
+				Context("when a domain is provided for a cf lb", func() {
+					It("applies a wildcard certificate for the domain issued by a new CA", func() {
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+							LBType:       "cf",
+							Domain:       "example.com",
+							GenerateCert: true,
+						}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(certificateGenerator.GenerateCACall.Receives.CommonName).To(Equal("some-env-id-timestamp load balancer CA"))
+						Expect(certificateGenerator.GenerateFromCACall.CallCount).To(Equal(1))
+						Expect(certificateGenerator.GenerateFromCACall.Receives[0].CommonName).To(Equal("*.example.com"))
+
+						Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+						Expect(appliedStates[0].LB).To(Equal(storage.LB{
+							Type:   "cf",
+							Cert:   "cert-for-*.example.com",
+							Key:    "key-for-*.example.com",
+							Domain: "example.com",
+							CA:     "some-ca",
+						}))
+
+						Expect(stateStore.SetCall.Receives[0].State.LB.CA).To(Equal("some-ca"))
+						Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+					})
+				})
+
+				Context("when there is no domain", func() {
+					BeforeEach(func() {
+						terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+							"concourse_load_balancer_url": "some-concourse-lb.elb.amazonaws.com",
+						}
+					})
+
+					It("applies a certificate for the address of the load balancer", func() {
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+							LBType:       "concourse",
+							GenerateCert: true,
+						}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(terraformManager.ApplyCall.CallCount).To(Equal(2))
+						Expect(appliedStates[0].LB.Cert).To(Equal("cert-for-concourse-lb.some-env-id-timestamp"))
+						Expect(terraformManager.GetOutputsCall.Receives.BBLState.TFState).To(Equal("some-updated-tf-state"))
+						Expect(appliedStates[1].LB.Cert).To(Equal("cert-for-some-concourse-lb.elb.amazonaws.com"))
+						Expect(appliedStates[1].LB.Key).To(Equal("key-for-some-concourse-lb.elb.amazonaws.com"))
+
+						Expect(stateStore.SetCall.CallCount).To(Equal(2))
+						Expect(stateStore.SetCall.Receives[1].State.LB.Cert).To(Equal("cert-for-some-concourse-lb.elb.amazonaws.com"))
+					})
+
+					It("returns an error when the load balancer has no address", func() {
+						terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{}
+
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+							LBType:       "concourse",
+							GenerateCert: true,
+						}, incomingState)
+						Expect(err).To(MatchError("could not find the address of the concourse load balancer"))
+					})
+				})
+
+				Context("when a CA is provided", func() {
+					var caCertPath, caKeyPath string
+
+					BeforeEach(func() {
+						caCertFile, err := ioutil.TempFile("", "ca-cert")
+						Expect(err).NotTo(HaveOccurred())
+						caCertPath = caCertFile.Name()
+						err = ioutil.WriteFile(caCertPath, []byte("some-internal-ca"), os.ModePerm)
+						Expect(err).NotTo(HaveOccurred())
+
+						caKeyFile, err := ioutil.TempFile("", "ca-key")
+						Expect(err).NotTo(HaveOccurred())
+						caKeyPath = caKeyFile.Name()
+						err = ioutil.WriteFile(caKeyPath, []byte("some-internal-ca-key"), os.ModePerm)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("issues the certificate from the provided CA", func() {
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+							LBType:       "cf",
+							Domain:       "example.com",
+							GenerateCert: true,
+							CACertPath:   caCertPath,
+							CAKeyPath:    caKeyPath,
+						}, incomingState)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(certificateGenerator.GenerateCACall.CallCount).To(Equal(0))
+						Expect(certificateGenerator.GenerateFromCACall.Receives[0].CA).To(Equal(ssl.CAData{
+							CA:         []byte("some-internal-ca"),
+							PrivateKey: []byte("some-internal-ca-key"),
+						}))
+						Expect(appliedStates[0].LB.CA).To(Equal("some-internal-ca"))
+					})
+
+					It("returns an error when the CA key cannot be read", func() {
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+							LBType:       "cf",
+							GenerateCert: true,
+							CACertPath:   caCertPath,
+							CAKeyPath:    "/some/missing/ca.key",
+						}, incomingState)
+						Expect(err).To(MatchError("open /some/missing/ca.key: no such file or directory"))
+						Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+					})
+				})
+
+				It("returns an error when the CA cannot be generated", func() {
+					certificateGenerator.GenerateCACall.Returns.Error = errors.New("failed to generate ca")
+
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:       "cf",
+						GenerateCert: true,
+					}, incomingState)
+					Expect(err).To(MatchError("failed to generate ca"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+
+				It("returns an error when the certificate cannot be issued", func() {
+					certificateGenerator.GenerateFromCACall.Stub = func(string) (ssl.KeyPair, error) {
+						return ssl.KeyPair{}, errors.New("failed to issue certificate")
+					}
+
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:       "cf",
+						Domain:       "example.com",
+						GenerateCert: true,
+					}, incomingState)
+					Expect(err).To(MatchError("failed to issue certificate"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
+			})
+
+			Context("when --acme is provided", func() {
+				var appliedStates []storage.State
+
+				BeforeEach(func() {
+					appliedStates = []storage.State{}
+					terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+						appliedStates = append(appliedStates, state)
+						return state, nil
+					}
+
+					certificateGenerator.GenerateCACall.Returns.CAData = ssl.CAData{CA: []byte("some-ca")}
+					certificateGenerator.GenerateFromCACall.Returns.KeyPair = ssl.KeyPair{
+						Certificate: []byte("some-placeholder-cert"),
+						PrivateKey:  []byte("some-placeholder-key"),
+					}
+
+					acmeManager.IssueCall.Returns.State = storage.State{
+						IAAS: "aws",
+						LB: storage.LB{
+							Type:   "cf",
+							Cert:   "some-acme-cert",
+							Key:    "some-acme-key",
+							Chain:  "some-acme-chain",
+							Domain: "example.com",
+							ACME: &storage.ACME{
+								DirectoryURL: "https://localhost:14000/dir",
+								AccountKey:   "some-account-key",
+							},
+						},
+					}
+				})
+
+				It("applies a placeholder certificate and then the one from the acme directory", func() {
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:           "cf",
+						Domain:           "example.com",
+						ACME:             true,
+						ACMEDirectoryURL: "https://localhost:14000/dir",
+						ACMEEmail:        "ops@example.com",
+					}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(2))
+					Expect(appliedStates[0].LB.Cert).To(Equal("some-placeholder-cert"))
+					Expect(appliedStates[0].LB.ACME).To(Equal(&storage.ACME{
+						DirectoryURL: "https://localhost:14000/dir",
+						Email:        "ops@example.com",
+					}))
+
+					Expect(acmeManager.IssueCall.Receives.State.LB.Cert).To(Equal("some-placeholder-cert"))
+					Expect(appliedStates[1]).To(Equal(acmeManager.IssueCall.Returns.State))
+
+					Expect(stateStore.SetCall.CallCount).To(Equal(2))
+					Expect(stateStore.SetCall.Receives[0].State.LB.Cert).To(Equal("some-placeholder-cert"))
+					Expect(stateStore.SetCall.Receives[1].State.LB.Cert).To(Equal("some-acme-cert"))
+					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+				})
+
+				It("keeps the placeholder certificate and explains how to retry when issuing fails", func() {
+					acmeManager.IssueCall.Returns.Error = errors.New("NXDOMAIN")
+
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
+						LBType:           "cf",
+						Domain:           "example.com",
+						ACME:             true,
+						ACMEDirectoryURL: "https://localhost:14000/dir",
+					}, incomingState)
+					Expect(err).To(MatchError("failed to issue the acme certificate: NXDOMAIN\n" +
+						"once the name servers shown by `bbl lbs` are delegated for example.com, run `bbl update-lbs --acme-renew`"))
+
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+					Expect(stateStore.SetCall.CallCount).To(Equal(1))
+					Expect(stateStore.SetCall.Receives[0].State.LB.ACME).NotTo(BeNil())
+				})
+			})
 		})
 
 		It("names the loadbalancer without EnvID when EnvID is not set", func() {
@@ -373,7 +653,7 @@ var _ = Describe("AWS Create LBs", func() {
 				ARN: "some-certificate-arn",
 			}
 
-			err := command.Execute(commands.AWSCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 				LBType:   "concourse",
 				CertPath: "temp/some-cert.crt",
 				KeyPath:  "temp/some-key.key",
@@ -386,7 +666,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 		Context("when the bbl environment has a BOSH director", func() {
 			It("updates the cloud config with a state that has lb type", func() {
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -415,7 +695,7 @@ var _ = Describe("AWS Create LBs", func() {
 					},
 					EnvID: "some-env-id-timestamp",
 				}
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -440,7 +720,7 @@ var _ = Describe("AWS Create LBs", func() {
 					},
 					EnvID: "some-env-id-timestamp",
 				}
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -454,7 +734,7 @@ var _ = Describe("AWS Create LBs", func() {
 		Context("when --skip-if-exists is provided", func() {
 			It("no-ops when lb exists", func() {
 				incomingState.Stack.LBType = "cf"
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:       "concourse",
 					CertPath:     "temp/some-cert.crt",
 					KeyPath:      "temp/some-key.key",
@@ -471,7 +751,7 @@ var _ = Describe("AWS Create LBs", func() {
 			DescribeTable("creates the lb if the lb does not exist",
 				func(currentLBType string) {
 					incomingState.Stack.LBType = currentLBType
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:       "concourse",
 						CertPath:     "temp/some-cert.crt",
 						KeyPath:      "temp/some-key.key",
@@ -489,7 +769,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 		Context("invalid lb type", func() {
 			It("returns an error", func() {
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "some-invalid-lb",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -498,7 +778,7 @@ var _ = Describe("AWS Create LBs", func() {
 			})
 
 			It("returns a helpful error when no lb type is provided", func() {
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "",
 					CertPath: "temp/some-cert.crt",
 					KeyPath:  "temp/some-key.key",
@@ -510,7 +790,7 @@ var _ = Describe("AWS Create LBs", func() {
 		It("returns an error when the environment validator fails", func() {
 			environmentValidator.ValidateCall.Returns.Error = errors.New("environment not found")
 
-			err := command.Execute(commands.AWSCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 				LBType:   "concourse",
 				CertPath: "temp/some-cert.crt",
 				KeyPath:  "temp/some-key.key",
@@ -523,7 +803,7 @@ var _ = Describe("AWS Create LBs", func() {
 		Context("state manipulation", func() {
 			Context("when the env id does not exist", func() {
 				It("saves state with new certificate name and lb type", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "temp/some-cert.crt",
 						KeyPath:  "temp/some-key.key",
@@ -539,7 +819,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 			Context("when the env id exists", func() {
 				It("saves state with new certificate name and lb type", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "temp/some-cert.crt",
 						KeyPath:  "temp/some-key.key",
@@ -559,7 +839,7 @@ var _ = Describe("AWS Create LBs", func() {
 		Context("required args", func() {
 			It("returns an error when certificate validator fails for cert and key", func() {
 				certificateValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "/path/to/cert",
 					KeyPath:  "/path/to/key",
@@ -578,7 +858,7 @@ var _ = Describe("AWS Create LBs", func() {
 		Context("failure cases", func() {
 			DescribeTable("returns an error when an lb already exists",
 				func(newLbType, oldLbType string) {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "/path/to/cert",
 						KeyPath:  "/path/to/key",
@@ -597,7 +877,7 @@ var _ = Describe("AWS Create LBs", func() {
 				It("returns an error", func() {
 					availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve azs")
 
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "/path/to/cert",
 						KeyPath:  "/path/to/key",
@@ -610,7 +890,7 @@ var _ = Describe("AWS Create LBs", func() {
 				It("returns an error", func() {
 					infrastructureManager.UpdateCall.Returns.Error = errors.New("failed to update infrastructure")
 
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "/path/to/cert",
 						KeyPath:  "/path/to/key",
@@ -621,7 +901,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 			Context("when lb is cf and cert path is invalid", func() {
 				It("returns an error", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "cf",
 						CertPath: "/fake/cert/path",
 						KeyPath:  "/some/key/path",
@@ -642,7 +922,7 @@ var _ = Describe("AWS Create LBs", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "cf",
 						CertPath: certPath,
 						KeyPath:  "/fake/key/path",
@@ -670,7 +950,7 @@ var _ = Describe("AWS Create LBs", func() {
 				})
 
 				It("returns an error", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:    "cf",
 						CertPath:  certPath,
 						KeyPath:   keyPath,
@@ -701,7 +981,7 @@ var _ = Describe("AWS Create LBs", func() {
 				It("returns an error", func() {
 					terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
 
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: certPath,
 						KeyPath:  keyPath,
@@ -738,7 +1018,7 @@ var _ = Describe("AWS Create LBs", func() {
 				})
 
 				It("saves the bbl state and returns the error", func() {
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: certPath,
 						KeyPath:  keyPath,
@@ -759,7 +1039,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("saves the bbl state and returns the error", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:   "concourse",
 							CertPath: certPath,
 							KeyPath:  keyPath,
@@ -781,7 +1061,7 @@ var _ = Describe("AWS Create LBs", func() {
 					})
 
 					It("saves the bbl state and returns the error", func() {
-						err := command.Execute(commands.AWSCreateLBsConfig{
+						err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 							LBType:   "concourse",
 							CertPath: certPath,
 							KeyPath:  keyPath,
@@ -797,7 +1077,7 @@ var _ = Describe("AWS Create LBs", func() {
 				It("returns an error", func() {
 					certificateManager.CreateCall.Returns.Error = errors.New("failed to create cert")
 
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "/path/to/cert",
 						KeyPath:  "/path/to/key",
@@ -810,7 +1090,7 @@ var _ = Describe("AWS Create LBs", func() {
 				It("returns an error", func() {
 					cloudConfigManager.UpdateCall.Returns.Error = errors.New("failed to update cloud config")
 
-					err := command.Execute(commands.AWSCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 						LBType:   "concourse",
 						CertPath: "/path/to/cert",
 						KeyPath:  "/path/to/key",
@@ -821,7 +1101,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 			It("returns an error when a GUID cannot be generated", func() {
 				guidGenerator.GenerateCall.Returns.Error = errors.New("Out of entropy in the universe")
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "/path/to/cert",
 					KeyPath:  "/path/to/key",
@@ -831,7 +1111,7 @@ var _ = Describe("AWS Create LBs", func() {
 
 			It("returns an error when the state fails to save", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to save state")}}
-				err := command.Execute(commands.AWSCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 					LBType:   "concourse",
 					CertPath: "/path/to/cert",
 					KeyPath:  "/path/to/key",
@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 const (
 	DeleteLBsCommand = "delete-lbs"
@@ -40,7 +44,7 @@ func NewAWSDeleteLBs(credentialValidator credentialValidator, availabilityZoneRe
 	}
 }
 
-func (c AWSDeleteLBs) Execute(state storage.State) error {
+func (c AWSDeleteLBs) Execute(ctx context.Context, state storage.State) error {
 	err := c.credentialValidator.Validate()
 	if err != nil {
 		return err
@@ -59,6 +63,7 @@ func (c AWSDeleteLBs) Execute(state storage.State) error {
 		state.LB.Type = ""
 		state.LB.Cert = ""
 		state.LB.Key = ""
+		state.Stack.CertificateName = ""
 	} else {
 		if !lbExists(state.Stack.LBType) {
 			return LBNotFound
@@ -73,14 +78,14 @@ func (c AWSDeleteLBs) Execute(state storage.State) error {
 	}
 
 	if !state.NoDirector {
-		err = c.cloudConfigManager.Update(state)
+		err = c.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
 	}
 
 	if state.TFState != "" {
-		state, err = c.terraformManager.Apply(state)
+		state, err = c.terraformManager.Apply(ctx, state)
 		if err != nil {
 			return handleTerraformError(err, c.stateStore)
 		}
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
@@ -99,7 +100,7 @@ var _ = Describe("Delete LBs", func() {
 						Name: "some-stack-name",
 					}
 
-					err := command.Execute(incomingCloudformationState)
+					err := command.Execute(context.Background(), incomingCloudformationState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(infrastructureManager.DescribeCall.Receives.StackName).To(Equal("some-stack-name"))
@@ -110,7 +111,7 @@ var _ = Describe("Delete LBs", func() {
 				It("delete lbs from cloudformation and deletes certificate", func() {
 					availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"a", "b", "c"}
 
-					err := command.Execute(incomingCloudformationState)
+					err := command.Execute(context.Background(), incomingCloudformationState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(credentialValidator.ValidateCall.CallCount).To(Equal(1))
@@ -132,7 +133,7 @@ var _ = Describe("Delete LBs", func() {
 
 				It("returns an error if the environment validator fails", func() {
 					environmentValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
-					err := command.Execute(incomingCloudformationState)
+					err := command.Execute(context.Background(), incomingCloudformationState)
 					Expect(err).To(MatchError("failed to validate"))
 					Expect(environmentValidator.ValidateCall.Receives.State).To(Equal(incomingCloudformationState))
 					Expect(environmentValidator.ValidateCall.CallCount).To(Equal(1))
@@ -141,7 +142,7 @@ var _ = Describe("Delete LBs", func() {
 
 			Context("when terraform is used for infrastructure", func() {
 				It("updates cloud config", func() {
-					err := command.Execute(incomingTerraformState)
+					err := command.Execute(context.Background(), incomingTerraformState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(cloudConfigManager.UpdateCall.Receives.State.LB.Type).To(BeEmpty())
@@ -150,7 +151,7 @@ var _ = Describe("Delete LBs", func() {
 				})
 
 				It("runs terraform apply to delete lbs and certificate", func() {
-					err := command.Execute(incomingTerraformState)
+					err := command.Execute(context.Background(), incomingTerraformState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(credentialValidator.ValidateCall.CallCount).To(Equal(1))
@@ -169,6 +170,15 @@ var _ = Describe("Delete LBs", func() {
 
 					Expect(logger.StepCall.Messages).NotTo(ContainElement("deleting certificate"))
 				})
+
+				It("forgets the certificate that bbl migrate imported from the stack", func() {
+					incomingTerraformState.Stack = storage.Stack{CertificateName: "some-certificate", Migrated: true}
+
+					err := command.Execute(context.Background(), incomingTerraformState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(terraformManager.ApplyCall.Receives.BBLState.Stack).To(Equal(storage.Stack{Migrated: true}))
+				})
 			})
 		})
 
@@ -190,7 +200,7 @@ var _ = Describe("Delete LBs", func() {
 					},
 					EnvID: "some-env-id",
 				}
-				err := command.Execute(state)
+				err := command.Execute(context.Background(), state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(cloudConfigManager.UpdateCall.CallCount).To(Equal(0))
@@ -199,7 +209,7 @@ var _ = Describe("Delete LBs", func() {
 
 		Context("when cloudformation is used for infrastructure", func() {
 			It("returns an error if there is no lb", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					Stack: storage.Stack{
 						LBType: "none",
 					},
@@ -210,7 +220,7 @@ var _ = Describe("Delete LBs", func() {
 
 		Context("when terraform is used for infrastructure", func() {
 			It("returns an error if there is no lb", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					TFState: "some-tf-state",
 				})
 				Expect(err).To(MatchError(commands.LBNotFound))
@@ -220,7 +230,7 @@ var _ = Describe("Delete LBs", func() {
 		Context("state management", func() {
 			It("saves state with no lb type before deleting certificate", func() {
 				certificateManager.DeleteCall.Returns.Error = errors.New("failed to delete")
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					Stack: storage.Stack{
 						Name:            "some-stack",
 						LBType:          "cf",
@@ -240,7 +250,7 @@ var _ = Describe("Delete LBs", func() {
 			})
 
 			It("saves state with no lb type nor certificate", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					Stack: storage.Stack{
 						Name:            "some-stack",
 						LBType:          "cf",
@@ -263,20 +273,20 @@ var _ = Describe("Delete LBs", func() {
 		Context("failure cases", func() {
 			It("returns an error when aws credential validator fails to validate", func() {
 				credentialValidator.ValidateCall.Returns.Error = errors.New("validate failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("validate failed"))
 			})
 
 			It("return an error when availability zone retriever fails to retrieve", func() {
 				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("retrieve failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("retrieve failed"))
 			})
 
 			Context("when terraform manager fails to apply with terraformManagerError", func() {
 				It("return an error", func() {
 					terraformManager.ApplyCall.Returns.Error = errors.New("apply failed")
-					err := command.Execute(incomingTerraformState)
+					err := command.Execute(context.Background(), incomingTerraformState)
 					Expect(err).To(MatchError("apply failed"))
 				})
 			})
@@ -296,7 +306,7 @@ var _ = Describe("Delete LBs", func() {
 				})
 
 				It("return an error", func() {
-					err := command.Execute(incomingTerraformState)
+					err := command.Execute(context.Background(), incomingTerraformState)
 					Expect(err).To(MatchError("cannot apply"))
 
 					Expect(stateStore.SetCall.CallCount).To(Equal(1))
@@ -311,7 +321,7 @@ var _ = Describe("Delete LBs", func() {
 					})
 
 					It("saves the bbl state and returns the error", func() {
-						err := command.Execute(incomingTerraformState)
+						err := command.Execute(context.Background(), incomingTerraformState)
 						Expect(err).To(MatchError("the following errors occurred:\ncannot apply,\nfailed to retrieve bbl state"))
 					})
 				})
@@ -319,36 +329,36 @@ var _ = Describe("Delete LBs", func() {
 
 			It("return an error when infrastructure manager fails to describe", func() {
 				infrastructureManager.DescribeCall.Returns.Error = errors.New("describe failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("describe failed"))
 			})
 
 			It("return an error when cloud config manager fails to update", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("update failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("update failed"))
 			})
 
 			It("return an error when infrastructure manager fails to update", func() {
 				infrastructureManager.UpdateCall.Returns.Error = errors.New("update failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("update failed"))
 			})
 
 			It("return an error when certificate manager fails to delete", func() {
 				certificateManager.DeleteCall.Returns.Error = errors.New("delete failed")
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("delete failed"))
 			})
 
 			It("returns an error when the state fails to save lb type", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to save state")}}
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("failed to save state"))
 			})
 			It("returns an error when the state fails to save certificate deletion", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to save state")}}
-				err := command.Execute(incomingCloudformationState)
+				err := command.Execute(context.Background(), incomingCloudformationState)
 				Expect(err).To(MatchError("failed to save state"))
 			})
 		})
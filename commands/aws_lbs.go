@@ -13,14 +13,32 @@ type AWSLBs struct {
 	infrastructureManager infrastructureManager
 	terraformManager      terraformManager
 	logger                logger
+	outputWriter          outputWriter
+	certificateInventory  certificateInventory
 }
 
-func NewAWSLBs(credentialValidator credentialValidator, infrastructureManager infrastructureManager, terraformManager terraformManager, logger logger) AWSLBs {
+type AWSLBsOutput struct {
+	RouterLBName           string   `json:"cf_router_lb,omitempty" yaml:"cf_router_lb,omitempty"`
+	RouterLBURL            string   `json:"cf_router_lb_url,omitempty" yaml:"cf_router_lb_url,omitempty"`
+	SSHProxyLBName         string   `json:"cf_ssh_proxy_lb,omitempty" yaml:"cf_ssh_proxy_lb,omitempty"`
+	SSHProxyLBURL          string   `json:"cf_ssh_proxy_lb_url,omitempty" yaml:"cf_ssh_proxy_lb_url,omitempty"`
+	TCPRouterLBName        string   `json:"cf_tcp_router_lb,omitempty" yaml:"cf_tcp_router_lb,omitempty"`
+	TCPRouterLBURL         string   `json:"cf_tcp_router_lb_url,omitempty" yaml:"cf_tcp_router_lb_url,omitempty"`
+	ConcourseLBName        string   `json:"concourse_lb,omitempty" yaml:"concourse_lb,omitempty"`
+	ConcourseLBURL         string   `json:"concourse_lb_url,omitempty" yaml:"concourse_lb_url,omitempty"`
+	SystemDomainDNSServers []string `json:"env_dns_zone_name_servers,omitempty" yaml:"env_dns_zone_name_servers,omitempty"`
+	CertificateExpires     string   `json:"certificate_expires,omitempty" yaml:"certificate_expires,omitempty"`
+}
+
+func NewAWSLBs(credentialValidator credentialValidator, infrastructureManager infrastructureManager, terraformManager terraformManager, logger logger, outputWriter outputWriter,
+	certificateInventory certificateInventory) AWSLBs {
 	return AWSLBs{
 		credentialValidator:   credentialValidator,
 		infrastructureManager: infrastructureManager,
 		terraformManager:      terraformManager,
 		logger:                logger,
+		outputWriter:          outputWriter,
+		certificateInventory:  certificateInventory,
 	}
 }
 
@@ -36,57 +54,103 @@ func (l AWSLBs) Execute(subcommandFlags []string, state storage.State) error {
 			return err
 		}
 
+		var lbOutput AWSLBsOutput
 		switch state.LB.Type {
 		case "cf":
-			if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
-				lbOutput, err := json.Marshal(struct {
-					RouterLBName           string   `json:"cf_router_lb,omitempty"`
-					RouterLBURL            string   `json:"cf_router_lb_url,omitempty"`
-					SSHProxyLBName         string   `json:"cf_ssh_proxy_lb,omitempty"`
-					SSHProxyLBURL          string   `json:"cf_ssh_proxy_lb_url,omitempty"`
-					SystemDomainDNSServers []string `json:"env_dns_zone_name_servers,omitempty"`
-				}{
-					RouterLBName:           terraformOutputs["cf_router_load_balancer"].(string),
-					RouterLBURL:            terraformOutputs["cf_router_load_balancer_url"].(string),
-					SSHProxyLBName:         terraformOutputs["cf_ssh_proxy_load_balancer"].(string),
-					SSHProxyLBURL:          terraformOutputs["cf_ssh_proxy_load_balancer_url"].(string),
-					SystemDomainDNSServers: terraformOutputs["cf_system_domain_dns_servers"].([]string),
-				})
-				if err != nil {
-					// not tested
-					return err
-				}
-
-				l.logger.Println(string(lbOutput))
-			} else {
-				l.logger.Printf("CF Router LB: %s [%s]\n", terraformOutputs["cf_router_load_balancer"], terraformOutputs["cf_router_load_balancer_url"])
-				l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", terraformOutputs["cf_ssh_proxy_load_balancer"], terraformOutputs["cf_ssh_proxy_load_balancer_url"])
-
-				if dnsServers, ok := terraformOutputs["cf_system_domain_dns_servers"]; ok {
-					l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
-				}
+			lbOutput = AWSLBsOutput{
+				RouterLBName:    outputString(terraformOutputs, "cf_router_load_balancer"),
+				RouterLBURL:     outputString(terraformOutputs, "cf_router_load_balancer_url"),
+				SSHProxyLBName:  outputString(terraformOutputs, "cf_ssh_proxy_load_balancer"),
+				SSHProxyLBURL:   outputString(terraformOutputs, "cf_ssh_proxy_load_balancer_url"),
+				TCPRouterLBName: outputString(terraformOutputs, "cf_tcp_router_load_balancer"),
+				TCPRouterLBURL:  outputString(terraformOutputs, "cf_tcp_router_load_balancer_url"),
+			}
+			if dnsServers, ok := terraformOutputs["cf_system_domain_dns_servers"].([]string); ok {
+				lbOutput.SystemDomainDNSServers = dnsServers
 			}
 		case "concourse":
-			l.logger.Printf("Concourse LB: %s [%s]\n", terraformOutputs["concourse_load_balancer"], terraformOutputs["concourse_load_balancer_url"])
+			lbOutput = AWSLBsOutput{
+				ConcourseLBName: outputString(terraformOutputs, "concourse_load_balancer"),
+				ConcourseLBURL:  outputString(terraformOutputs, "concourse_load_balancer_url"),
+			}
 		default:
 			return errors.New("no lbs found")
 		}
 
-	} else {
-		stack, err := l.infrastructureManager.Describe(state.Stack.Name)
+		lbOutput.CertificateExpires, err = lbCertificateExpiry(l.certificateInventory, state)
 		if err != nil {
 			return err
 		}
 
-		switch state.Stack.LBType {
-		case "cf":
-			l.logger.Printf("CF Router LB: %s [%s]\n", stack.Outputs["CFRouterLoadBalancer"], stack.Outputs["CFRouterLoadBalancerURL"])
-			l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", stack.Outputs["CFSSHProxyLoadBalancer"], stack.Outputs["CFSSHProxyLoadBalancerURL"])
-		case "concourse":
-			l.logger.Printf("Concourse LB: %s [%s]\n", stack.Outputs["ConcourseLoadBalancer"], stack.Outputs["ConcourseLoadBalancerURL"])
-		default:
-			return errors.New("no lbs found")
+		return l.print(subcommandFlags, state.LB.Type, lbOutput)
+	}
+
+	stack, err := l.infrastructureManager.Describe(state.Stack.Name)
+	if err != nil {
+		return err
+	}
+
+	var lbOutput AWSLBsOutput
+	switch state.Stack.LBType {
+	case "cf":
+		lbOutput = AWSLBsOutput{
+			RouterLBName:   stack.Outputs["CFRouterLoadBalancer"],
+			RouterLBURL:    stack.Outputs["CFRouterLoadBalancerURL"],
+			SSHProxyLBName: stack.Outputs["CFSSHProxyLoadBalancer"],
+			SSHProxyLBURL:  stack.Outputs["CFSSHProxyLoadBalancerURL"],
+		}
+	case "concourse":
+		lbOutput = AWSLBsOutput{
+			ConcourseLBName: stack.Outputs["ConcourseLoadBalancer"],
+			ConcourseLBURL:  stack.Outputs["ConcourseLoadBalancerURL"],
+		}
+	default:
+		return errors.New("no lbs found")
+	}
+
+	lbOutput.CertificateExpires, err = lbCertificateExpiry(l.certificateInventory, state)
+	if err != nil {
+		return err
+	}
+
+	return l.print(subcommandFlags, state.Stack.LBType, lbOutput)
+}
+
+func (l AWSLBs) print(subcommandFlags []string, lbType string, lbOutput AWSLBsOutput) error {
+	config, err := parseLBsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.json {
+		contents, err := json.Marshal(lbOutput)
+		if err != nil {
+			// not tested
+			return err
 		}
+
+		l.logger.Println(string(contents))
+		return nil
 	}
+
+	if !l.outputWriter.IsText() {
+		return l.outputWriter.Write(lbOutput)
+	}
+
+	if lbType == "concourse" {
+		l.logger.Printf("Concourse LB: %s [%s]\n", lbOutput.ConcourseLBName, lbOutput.ConcourseLBURL)
+	} else {
+		l.logger.Printf("CF Router LB: %s [%s]\n", lbOutput.RouterLBName, lbOutput.RouterLBURL)
+		l.logger.Printf("CF SSH Proxy LB: %s [%s]\n", lbOutput.SSHProxyLBName, lbOutput.SSHProxyLBURL)
+
+		if len(lbOutput.SystemDomainDNSServers) > 0 {
+			l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(lbOutput.SystemDomainDNSServers, " "))
+		}
+	}
+
+	if lbOutput.CertificateExpires != "" {
+		l.logger.Printf("LB certificate expires: %s\n", lbOutput.CertificateExpires)
+	}
+
 	return nil
 }
@@ -2,8 +2,10 @@ package commands_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -20,6 +22,8 @@ var _ = Describe("AWSLBs", func() {
 		infrastructureManager *fakes.InfrastructureManager
 		terraformManager      *fakes.TerraformManager
 		logger                *fakes.Logger
+		outputWriter          *fakes.OutputWriter
+		certificateInventory  *fakes.CertificateInventory
 
 		incomingState storage.State
 	)
@@ -29,8 +33,11 @@ var _ = Describe("AWSLBs", func() {
 		infrastructureManager = &fakes.InfrastructureManager{}
 		terraformManager = &fakes.TerraformManager{}
 		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		certificateInventory = &fakes.CertificateInventory{}
 
-		command = commands.NewAWSLBs(credentialValidator, infrastructureManager, terraformManager, logger)
+		command = commands.NewAWSLBs(credentialValidator, infrastructureManager, terraformManager, logger, outputWriter, certificateInventory)
 	})
 
 	Describe("Execute", func() {
@@ -197,6 +204,41 @@ var _ = Describe("AWSLBs", func() {
 							}`))
 						})
 					})
+
+					Context("when the output format is not text", func() {
+						It("writes the LB names, URLs, and DNS servers to the output writer", func() {
+							incomingState.LB = storage.LB{
+								Type:   "cf",
+								Domain: "some-domain",
+							}
+							outputWriter.IsTextCall.Returns.IsText = false
+
+							err := command.Execute([]string{}, incomingState)
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(logger.PrintfCall.CallCount).To(Equal(0))
+							Expect(outputWriter.WriteCall.CallCount).To(Equal(1))
+							Expect(outputWriter.WriteCall.Receives.Value).To(Equal(commands.AWSLBsOutput{
+								RouterLBName:           "some-router-lb-name",
+								RouterLBURL:            "some-router-lb-url",
+								SSHProxyLBName:         "some-ssh-proxy-lb-name",
+								SSHProxyLBURL:          "some-ssh-proxy-lb-url",
+								SystemDomainDNSServers: []string{"name-server-1.", "name-server-2."},
+							}))
+						})
+
+						It("returns an error when the output writer fails", func() {
+							incomingState.LB = storage.LB{
+								Type:   "cf",
+								Domain: "some-domain",
+							}
+							outputWriter.IsTextCall.Returns.IsText = false
+							outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+
+							err := command.Execute([]string{}, incomingState)
+							Expect(err).To(MatchError("failed to write"))
+						})
+					})
 				})
 			})
 
@@ -225,6 +267,29 @@ var _ = Describe("AWSLBs", func() {
 						"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
 					}))
 				})
+
+				It("prints when the LB certificate expires", func() {
+					certificateInventory.LoadBalancerCall.Returns.Found = true
+					certificateInventory.LoadBalancerCall.Returns.Certificate = certificates.Certificate{
+						Expires: time.Date(2017, time.July, 20, 0, 0, 0, 0, time.UTC),
+					}
+
+					err := command.Execute([]string{}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(certificateInventory.LoadBalancerCall.Receives.State).To(Equal(incomingState))
+					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
+						"Concourse LB: some-concourse-lb-name [some-concourse-lb-url]\n",
+						"LB certificate expires: 2017-07-20\n",
+					}))
+				})
+
+				It("returns an error when the LB certificate cannot be read", func() {
+					certificateInventory.LoadBalancerCall.Returns.Error = errors.New("failed to describe")
+
+					err := command.Execute([]string{}, incomingState)
+					Expect(err).To(MatchError("failed to describe"))
+				})
 			})
 
 			It("returns error when lb type is not cf or concourse", func() {
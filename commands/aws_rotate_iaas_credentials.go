@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type AWSRotateIAASCredentials struct {
+	accessKeyManager accessKeyManager
+	terraformManager terraformManager
+	rotation         directorKeyRotation
+}
+
+type accessKeyManager interface {
+	Create(accessKeyID string) (string, string, error)
+	Delete(accessKeyID string) error
+}
+
+func NewAWSRotateIAASCredentials(accessKeyManager accessKeyManager, terraformManager terraformManager, boshManager boshManager,
+	stateStore stateStore, logger logger) AWSRotateIAASCredentials {
+	return AWSRotateIAASCredentials{
+		accessKeyManager: accessKeyManager,
+		terraformManager: terraformManager,
+		rotation: directorKeyRotation{
+			terraformManager: terraformManager,
+			boshManager:      boshManager,
+			stateStore:       stateStore,
+			logger:           logger,
+		},
+	}
+}
+
+func (r AWSRotateIAASCredentials) Execute(ctx context.Context, state storage.State) error {
+	return r.rotation.rotate(ctx, awsDirectorKey{
+		accessKeyManager: r.accessKeyManager,
+		terraformManager: r.terraformManager,
+	}, state)
+}
+
+type awsDirectorKey struct {
+	accessKeyManager accessKeyManager
+	terraformManager terraformManager
+}
+
+func (k awsDirectorKey) description() string {
+	return "access key of the director"
+}
+
+// current returns the access key of the director twice, because a new access
+// key is created for the user that owns it.
+func (k awsDirectorKey) current(state storage.State) (string, string, bool, error) {
+	if state.AWS.DirectorAccessKeyID != "" {
+		return state.AWS.DirectorAccessKeyID, state.AWS.DirectorAccessKeyID, false, nil
+	}
+
+	outputs, err := k.terraformManager.GetOutputs(state)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	accessKeyID := outputString(outputs, "access_key_id")
+	if accessKeyID == "" {
+		return "", "", false, errors.New("could not find the access key of the director")
+	}
+
+	return accessKeyID, accessKeyID, true, nil
+}
+
+func (k awsDirectorKey) create(state storage.State, accessKeyID string) (storage.State, error) {
+	newAccessKeyID, secretAccessKey, err := k.accessKeyManager.Create(accessKeyID)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	state.AWS.DirectorAccessKeyID = newAccessKeyID
+	state.AWS.DirectorSecretAccessKey = secretAccessKey
+	return state, nil
+}
+
+func (k awsDirectorKey) previous(state storage.State) string {
+	return state.AWS.PreviousDirectorAccessKeyID
+}
+
+func (k awsDirectorKey) setPrevious(state storage.State, accessKeyID string) storage.State {
+	state.AWS.PreviousDirectorAccessKeyID = accessKeyID
+	return state
+}
+
+func (k awsDirectorKey) delete(accessKeyID string) error {
+	return k.accessKeyManager.Delete(accessKeyID)
+}
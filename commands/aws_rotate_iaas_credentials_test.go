@@ -0,0 +1,196 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AWSRotateIAASCredentials", func() {
+	var (
+		accessKeyManager *fakes.AccessKeyManager
+		terraformManager *fakes.TerraformManager
+		boshManager      *fakes.BOSHManager
+		stateStore       *fakes.StateStore
+		logger           *fakes.Logger
+
+		command commands.AWSRotateIAASCredentials
+
+		incomingState storage.State
+	)
+
+	BeforeEach(func() {
+		accessKeyManager = &fakes.AccessKeyManager{}
+		terraformManager = &fakes.TerraformManager{}
+		boshManager = &fakes.BOSHManager{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+
+		accessKeyManager.CreateCall.Returns.AccessKeyID = "some-new-access-key-id"
+		accessKeyManager.CreateCall.Returns.SecretAccessKey = "some-new-secret-access-key"
+		terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+			"access_key_id": "some-terraform-access-key-id",
+		}
+		terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+			return state, nil
+		}
+
+		incomingState = storage.State{
+			IAAS:    "aws",
+			TFState: "some-tf-state",
+		}
+
+		command = commands.NewAWSRotateIAASCredentials(accessKeyManager, terraformManager, boshManager, stateStore, logger)
+	})
+
+	Context("when terraform manages the access key of the director", func() {
+		It("creates a new access key and has terraform delete the previous one", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(accessKeyManager.CreateCall.Receives.AccessKeyID).To(Equal("some-terraform-access-key-id"))
+
+			Expect(stateStore.SetCall.Receives[0].State.AWS.DirectorAccessKeyID).To(Equal("some-new-access-key-id"))
+			Expect(stateStore.SetCall.Receives[0].State.AWS.DirectorSecretAccessKey).To(Equal("some-new-secret-access-key"))
+			Expect(stateStore.SetCall.Receives[0].State.AWS.PreviousDirectorAccessKeyID).To(Equal("some-terraform-access-key-id"))
+
+			Expect(boshManager.CreateCall.CallCount).To(Equal(1))
+			Expect(boshManager.CreateCall.Receives.State.AWS.DirectorAccessKeyID).To(Equal("some-new-access-key-id"))
+
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.DirectorAccessKeyID).To(Equal("some-new-access-key-id"))
+			Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.PreviousDirectorAccessKeyID).To(BeEmpty())
+			Expect(accessKeyManager.DeleteCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.CallCount).To(Equal(3))
+
+			Expect(logger.StepCall.Messages).To(Equal([]string{
+				"creating a new access key of the director",
+				"deleting the previous access key of the director",
+			}))
+		})
+
+		It("returns an error when the access key cannot be found", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{}
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("could not find the access key of the director"))
+		})
+
+		It("returns an error when the terraform outputs cannot be read", func() {
+			terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to get outputs"))
+		})
+
+		It("returns an error when terraform fails to delete the previous key", func() {
+			terraformManager.ApplyCall.Stub = nil
+			terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to apply"))
+		})
+	})
+
+	Context("when bbl manages the access key of the director", func() {
+		BeforeEach(func() {
+			incomingState.AWS.DirectorAccessKeyID = "some-access-key-id"
+			incomingState.AWS.DirectorSecretAccessKey = "some-secret-access-key"
+		})
+
+		It("creates a new access key and deletes the previous one", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(0))
+			Expect(accessKeyManager.CreateCall.Receives.AccessKeyID).To(Equal("some-access-key-id"))
+			Expect(boshManager.CreateCall.Receives.State.AWS.DirectorAccessKeyID).To(Equal("some-new-access-key-id"))
+
+			Expect(accessKeyManager.DeleteCall.Receives.AccessKeyID).To(Equal("some-access-key-id"))
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.CallCount).To(Equal(3))
+			Expect(stateStore.SetCall.Receives[0].State.AWS.PreviousDirectorAccessKeyID).To(Equal("some-access-key-id"))
+			Expect(stateStore.SetCall.Receives[2].State.AWS.PreviousDirectorAccessKeyID).To(BeEmpty())
+		})
+
+		It("returns an error when the previous key cannot be deleted", func() {
+			accessKeyManager.DeleteCall.Returns.Error = errors.New("failed to delete")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to delete"))
+		})
+	})
+
+	Context("when a previous rotation did not finish", func() {
+		BeforeEach(func() {
+			incomingState.AWS.DirectorAccessKeyID = "some-new-access-key-id"
+			incomingState.AWS.DirectorSecretAccessKey = "some-new-secret-access-key"
+			incomingState.AWS.PreviousDirectorAccessKeyID = "some-access-key-id"
+		})
+
+		It("redeploys the director without another key and deletes the previous one", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.GetOutputsCall.CallCount).To(Equal(0))
+			Expect(accessKeyManager.CreateCall.CallCount).To(Equal(0))
+			Expect(boshManager.CreateCall.Receives.State.AWS.DirectorAccessKeyID).To(Equal("some-new-access-key-id"))
+			Expect(accessKeyManager.DeleteCall.Receives.AccessKeyID).To(Equal("some-access-key-id"))
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[1].State.AWS.PreviousDirectorAccessKeyID).To(BeEmpty())
+
+			Expect(logger.StepCall.Messages).To(Equal([]string{
+				"resuming the rotation of the access key of the director",
+				"deleting the previous access key of the director",
+			}))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the access key cannot be created", func() {
+			accessKeyManager.CreateCall.Returns.Error = errors.New("failed to create")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to create"))
+			Expect(stateStore.SetCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the state cannot be saved", func() {
+			stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to set")}}
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to set"))
+			Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("does not delete the previous key when the director cannot be redeployed", func() {
+			boshManager.CreateCall.Returns.Error = errors.New("failed to create director")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to create director"))
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			Expect(accessKeyManager.DeleteCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.Receives[0].State.AWS.PreviousDirectorAccessKeyID).To(Equal("some-terraform-access-key-id"))
+		})
+
+		It("saves the state that bosh create-env left behind", func() {
+			failedState := incomingState
+			failedState.BOSH.State = map[string]interface{}{"some-key": "some-value"}
+			boshManager.CreateCall.Returns.Error = bosh.NewManagerCreateError(failedState, storage.CreateEnvPhase, errors.New("failed to create"))
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError(ContainSubstring("failed to create")))
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[1].State).To(Equal(failedState))
+		})
+	})
+})
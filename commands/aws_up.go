@@ -1,9 +1,11 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws"
@@ -60,8 +62,8 @@ type configProvider interface {
 }
 
 type cloudConfigManager interface {
-	Update(state storage.State) error
-	Generate(state storage.State) (string, error)
+	Update(ctx context.Context, state storage.State) error
+	Generate(ctx context.Context, state storage.State) (string, error)
 }
 
 type brokenEnvironmentValidator interface {
@@ -84,14 +86,17 @@ type AWSUp struct {
 }
 
 type AWSUpConfig struct {
-	AccessKeyID     string
-	SecretAccessKey string
-	Region          string
-	OpsFilePath     string
-	BOSHAZ          string
-	Name            string
-	NoDirector      bool
-	Terraform       bool
+	AccessKeyID        string
+	SecretAccessKey    string
+	Region             string
+	OpsFilePath        string
+	BOSHAZ             string
+	VPCID              string
+	SubnetIDs          []string
+	IAMInstanceProfile bool
+	Name               string
+	NoDirector         bool
+	Terraform          bool
 }
 
 func NewAWSUp(
@@ -118,7 +123,7 @@ func NewAWSUp(
 	}
 }
 
-func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
+func (u AWSUp) Execute(ctx context.Context, config AWSUpConfig, state storage.State) error {
 	state.IAAS = "aws"
 
 	if u.awsCredentialsPresent(config) {
@@ -155,6 +160,15 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		return err
 	}
 
+	if config.VPCID != "" {
+		state.AWS.ExistingVPCID = config.VPCID
+		state.AWS.ExistingSubnetIDs = config.SubnetIDs
+	}
+
+	if config.IAMInstanceProfile {
+		state.AWS.IAMInstanceProfile = true
+	}
+
 	state, err = u.envIDManager.Sync(state, config.Name)
 	if err != nil {
 		return err
@@ -200,7 +214,7 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 	}
 
 	if config.Terraform || state.TFState != "" {
-		state, err = u.terraformManager.Apply(state)
+		state, err = u.terraformManager.Apply(ctx, state)
 		if err != nil {
 			return handleTerraformError(err, u.stateStore)
 		}
@@ -234,7 +248,7 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 		}
 		state.BOSH.UserOpsFile = string(opsFile)
 
-		state, err = u.boshManager.Create(state)
+		state, err = u.boshManager.Create(ctx, state)
 		switch err.(type) {
 		case bosh.ManagerCreateError:
 			bcErr := err.(bosh.ManagerCreateError)
@@ -254,7 +268,7 @@ func (u AWSUp) Execute(config AWSUpConfig, state storage.State) error {
 			return err
 		}
 
-		err = u.cloudConfigManager.Update(state)
+		err = u.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
@@ -272,6 +286,34 @@ func (u AWSUp) checkForFastFails(state storage.State, config AWSUpConfig) error
 		return errors.New("The --aws-bosh-az cannot be changed for existing environments.")
 	}
 
+	if (config.VPCID == "") != (len(config.SubnetIDs) == 0) {
+		return errors.New("--aws-vpc-id and --aws-subnet-ids must be provided together")
+	}
+
+	if config.VPCID != "" {
+		if !config.Terraform && state.TFState == "" {
+			return errors.New("--aws-vpc-id requires --terraform")
+		}
+
+		if state.AWS.ExistingVPCID == "" && (state.TFState != "" || state.Stack.Name != "") {
+			return errors.New("--aws-vpc-id cannot be used on an environment that created its own vpc")
+		}
+
+		if state.AWS.ExistingVPCID != "" && (state.AWS.ExistingVPCID != config.VPCID || !reflect.DeepEqual(state.AWS.ExistingSubnetIDs, config.SubnetIDs)) {
+			return errors.New("The --aws-vpc-id and --aws-subnet-ids cannot be changed for existing environments.")
+		}
+	}
+
+	if config.IAMInstanceProfile {
+		if !config.Terraform && state.TFState == "" {
+			return errors.New("--aws-iam-instance-profile requires --terraform")
+		}
+
+		if state.AWS.DirectorAccessKeyID != "" {
+			return errors.New("--aws-iam-instance-profile cannot be used while bbl manages the access key of the director")
+		}
+	}
+
 	return nil
 }
 
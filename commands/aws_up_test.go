@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -107,12 +108,12 @@ var _ = Describe("AWSUp", func() {
 
 		It("returns an error when aws credential validator fails", func() {
 			credentialValidator.ValidateCall.Returns.Error = errors.New("failed to validate aws credentials")
-			err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+			err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 			Expect(err).To(MatchError("failed to validate aws credentials"))
 		})
 
 		It("retrieves a client with the provided credentials", func() {
-			err := command.Execute(commands.AWSUpConfig{
+			err := command.Execute(context.Background(), commands.AWSUpConfig{
 				AccessKeyID:     "new-aws-access-key-id",
 				SecretAccessKey: "new-aws-secret-access-key",
 				Region:          "new-aws-region",
@@ -129,7 +130,7 @@ var _ = Describe("AWSUp", func() {
 		})
 
 		It("calls the env id manager and saves the env id", func() {
-			err := command.Execute(commands.AWSUpConfig{
+			err := command.Execute(context.Background(), commands.AWSUpConfig{
 				AccessKeyID:     "new-aws-access-key-id",
 				SecretAccessKey: "new-aws-secret-access-key",
 				Region:          "new-aws-region",
@@ -143,7 +144,7 @@ var _ = Describe("AWSUp", func() {
 
 		Context("when a name is passed in for env-id", func() {
 			It("passes that name in for the env id manager to use", func() {
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "new-aws-access-key-id",
 					SecretAccessKey: "new-aws-secret-access-key",
 					Region:          "new-aws-region",
@@ -157,7 +158,7 @@ var _ = Describe("AWSUp", func() {
 		})
 
 		It("syncs the keypair", func() {
-			err := command.Execute(commands.AWSUpConfig{}, storage.State{
+			err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 				IAAS: "aws",
 				AWS: storage.AWS{
 					Region:          "some-aws-region",
@@ -201,7 +202,7 @@ var _ = Describe("AWSUp", func() {
 
 			availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"some-retrieved-az"}
 
-			err := command.Execute(commands.AWSUpConfig{}, incomingState)
+			err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
@@ -242,7 +243,7 @@ var _ = Describe("AWSUp", func() {
 					EnvID: "bbl-lake-time-stamp",
 				}
 
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					Terraform: true,
 				}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
@@ -300,7 +301,7 @@ var _ = Describe("AWSUp", func() {
 				})
 
 				It("creates infrastructure with terraform again", func() {
-					err := command.Execute(commands.AWSUpConfig{}, incomingState)
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(infrastructureManager.CreateCall.CallCount).To(Equal(0))
@@ -324,7 +325,7 @@ var _ = Describe("AWSUp", func() {
 					})
 
 					It("saves the bbl state and returns the error", func() {
-						err := command.Execute(commands.AWSUpConfig{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{
 							Terraform: true,
 						}, storage.State{})
 						Expect(err).To(MatchError("cannot apply"))
@@ -341,7 +342,7 @@ var _ = Describe("AWSUp", func() {
 						})
 
 						It("saves the bbl state and returns the error", func() {
-							err := command.Execute(commands.AWSUpConfig{
+							err := command.Execute(context.Background(), commands.AWSUpConfig{
 								Terraform: true,
 							}, storage.State{})
 							Expect(err).To(MatchError("the following errors occurred:\ncannot apply,\nfailed to retrieve bbl state"))
@@ -361,7 +362,7 @@ var _ = Describe("AWSUp", func() {
 						})
 
 						It("saves the bbl state and returns the error", func() {
-							err := command.Execute(commands.AWSUpConfig{
+							err := command.Execute(context.Background(), commands.AWSUpConfig{
 								Terraform: true,
 							}, storage.State{})
 							Expect(err).To(MatchError("the following errors occurred:\ncannot apply,\nfailed to set bbl state"))
@@ -373,7 +374,7 @@ var _ = Describe("AWSUp", func() {
 					It("returns the error", func() {
 						terraformManager.ApplyCall.Returns.Error = errors.New("cannot apply")
 
-						err := command.Execute(commands.AWSUpConfig{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{
 							Terraform: true,
 						}, storage.State{})
 						Expect(err).To(MatchError("cannot apply"))
@@ -388,7 +389,7 @@ var _ = Describe("AWSUp", func() {
 							{errors.New("failed to set the state")},
 						}
 
-						err := command.Execute(commands.AWSUpConfig{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{
 							Terraform: true,
 						}, storage.State{})
 						Expect(err).To(MatchError("failed to set the state"))
@@ -399,7 +400,7 @@ var _ = Describe("AWSUp", func() {
 
 		Context("when the no-director flag is provided", func() {
 			It("does not create a bosh or cloud config", func() {
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "new-aws-access-key-id",
 					SecretAccessKey: "new-aws-secret-access-key",
 					Region:          "new-aws-region",
@@ -416,7 +417,7 @@ var _ = Describe("AWSUp", func() {
 
 			Context("when a bbl environment exists with no bosh director", func() {
 				It("does not create a bosh director on subsequent runs", func() {
-					err := command.Execute(commands.AWSUpConfig{
+					err := command.Execute(context.Background(), commands.AWSUpConfig{
 						AccessKeyID:     "new-aws-access-key-id",
 						SecretAccessKey: "new-aws-secret-access-key",
 						Region:          "new-aws-region",
@@ -435,7 +436,7 @@ var _ = Describe("AWSUp", func() {
 
 			Context("when a bbl environment exists with a bosh director", func() {
 				It("fast fails before creating any infrastructure", func() {
-					err := command.Execute(commands.AWSUpConfig{
+					err := command.Execute(context.Background(), commands.AWSUpConfig{
 						AccessKeyID:     "new-aws-access-key-id",
 						SecretAccessKey: "new-aws-secret-access-key",
 						Region:          "new-aws-region",
@@ -468,7 +469,7 @@ var _ = Describe("AWSUp", func() {
 				EnvID: "bbl-lake-time-stamp",
 			}
 
-			err := command.Execute(commands.AWSUpConfig{}, incomingState)
+			err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(boshManager.CreateCall.Receives.State).To(Equal(incomingState))
@@ -484,7 +485,7 @@ var _ = Describe("AWSUp", func() {
 				err = ioutil.WriteFile(opsFilePath, []byte(opsFileContents), os.ModePerm)
 				Expect(err).NotTo(HaveOccurred())
 
-				err = command.Execute(commands.AWSUpConfig{
+				err = command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "some-aws-access-key-id",
 					SecretAccessKey: "some-aws-secret-access-key",
 					Region:          "some-aws-region",
@@ -500,7 +501,7 @@ var _ = Describe("AWSUp", func() {
 
 		Context("when bosh az is provided via --aws-bosh-az flag", func() {
 			It("passes the bosh az to the infrastructure manager", func() {
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "some-aws-access-key-id",
 					SecretAccessKey: "some-aws-secret-access-key",
 					Region:          "some-aws-region",
@@ -513,7 +514,7 @@ var _ = Describe("AWSUp", func() {
 
 			Context("when a stack exists and the aws-bosh-az is provided and different", func() {
 				It("returns an error message", func() {
-					err := command.Execute(commands.AWSUpConfig{
+					err := command.Execute(context.Background(), commands.AWSUpConfig{
 						AccessKeyID:     "some-aws-access-key-id",
 						SecretAccessKey: "some-aws-secret-access-key",
 						Region:          "some-aws-region",
@@ -529,6 +530,127 @@ var _ = Describe("AWSUp", func() {
 			})
 		})
 
+		Context("when an existing vpc is provided via --aws-vpc-id and --aws-subnet-ids", func() {
+			var config commands.AWSUpConfig
+
+			BeforeEach(func() {
+				config = commands.AWSUpConfig{
+					AccessKeyID:     "some-aws-access-key-id",
+					SecretAccessKey: "some-aws-secret-access-key",
+					Region:          "some-aws-region",
+					VPCID:           "some-vpc-id",
+					SubnetIDs:       []string{"some-subnet-1", "some-subnet-2"},
+					Terraform:       true,
+				}
+			})
+
+			It("saves the vpc and subnets to the state before creating infrastructure", func() {
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ExistingVPCID).To(Equal("some-vpc-id"))
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ExistingSubnetIDs).To(Equal([]string{"some-subnet-1", "some-subnet-2"}))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			})
+
+			It("returns an error when the subnets are not provided", func() {
+				config.SubnetIDs = nil
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).To(MatchError("--aws-vpc-id and --aws-subnet-ids must be provided together"))
+			})
+
+			It("returns an error when the vpc is not provided", func() {
+				config.VPCID = ""
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).To(MatchError("--aws-vpc-id and --aws-subnet-ids must be provided together"))
+			})
+
+			It("returns an error when terraform is not used", func() {
+				config.Terraform = false
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).To(MatchError("--aws-vpc-id requires --terraform"))
+			})
+
+			It("returns an error when the environment created its own vpc", func() {
+				err := command.Execute(context.Background(), config, storage.State{
+					TFState: "some-tf-state",
+				})
+				Expect(err).To(MatchError("--aws-vpc-id cannot be used on an environment that created its own vpc"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the vpc is changed", func() {
+				err := command.Execute(context.Background(), config, storage.State{
+					AWS: storage.AWS{
+						ExistingVPCID:     "other-vpc-id",
+						ExistingSubnetIDs: []string{"some-subnet-1", "some-subnet-2"},
+					},
+					TFState: "some-tf-state",
+				})
+				Expect(err).To(MatchError("The --aws-vpc-id and --aws-subnet-ids cannot be changed for existing environments."))
+			})
+
+			It("keeps using the existing vpc when the flags are omitted on later runs", func() {
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
+					AWS: storage.AWS{
+						ExistingVPCID:     "some-vpc-id",
+						ExistingSubnetIDs: []string{"some-subnet-1", "some-subnet-2"},
+					},
+					TFState: "some-tf-state",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.ExistingVPCID).To(Equal("some-vpc-id"))
+			})
+		})
+
+		Context("when an IAM instance profile is requested via --aws-iam-instance-profile", func() {
+			var config commands.AWSUpConfig
+
+			BeforeEach(func() {
+				config = commands.AWSUpConfig{
+					AccessKeyID:        "some-aws-access-key-id",
+					SecretAccessKey:    "some-aws-secret-access-key",
+					Region:             "some-aws-region",
+					IAMInstanceProfile: true,
+					Terraform:          true,
+				}
+			})
+
+			It("saves it to the state before creating infrastructure", func() {
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.AWS.IAMInstanceProfile).To(BeTrue())
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.IAMInstanceProfile).To(BeTrue())
+			})
+
+			It("keeps using it when the flag is omitted on later runs", func() {
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
+					AWS:     storage.AWS{IAMInstanceProfile: true},
+					TFState: "some-tf-state",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ApplyCall.Receives.BBLState.AWS.IAMInstanceProfile).To(BeTrue())
+			})
+
+			It("returns an error when terraform is not used", func() {
+				config.Terraform = false
+				err := command.Execute(context.Background(), config, storage.State{})
+				Expect(err).To(MatchError("--aws-iam-instance-profile requires --terraform"))
+			})
+
+			It("returns an error when bbl manages the access key of the director", func() {
+				err := command.Execute(context.Background(), config, storage.State{
+					AWS:     storage.AWS{DirectorAccessKeyID: "some-director-access-key-id"},
+					TFState: "some-tf-state",
+				})
+				Expect(err).To(MatchError("--aws-iam-instance-profile cannot be used while bbl manages the access key of the director"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when there is an lb", func() {
 			It("attaches the lb certificate to the lb type in cloudformation", func() {
 				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{
@@ -537,7 +659,7 @@ var _ = Describe("AWSUp", func() {
 					Body: "some-certificate-body",
 				}
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 					Stack: storage.Stack{
 						Name:            "some-stack-name",
 						LBType:          "concourse",
@@ -553,7 +675,7 @@ var _ = Describe("AWSUp", func() {
 
 		Describe("cloud config", func() {
 			It("updates the bosh director with a cloud config provided an up-to-date state", func() {
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).NotTo(HaveOccurred())
 				Expect(cloudConfigManager.UpdateCall.Receives.State).To(Equal(storage.State{
 					EnvID: "bbl-lake-time-stamp",
@@ -593,7 +715,7 @@ var _ = Describe("AWSUp", func() {
 						},
 					}, errors.New("error syncing key pair"))
 
-					err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 					Expect(err).To(MatchError("error syncing key pair"))
 					Expect(stateStore.SetCall.CallCount).To(Equal(2))
 					Expect(stateStore.SetCall.Receives[1].State.KeyPair.Name).To(Equal("keypair-bbl-lake-time-stamp"))
@@ -608,7 +730,7 @@ var _ = Describe("AWSUp", func() {
 							},
 						}, errors.New("error syncing key pair"))
 
-						err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+						err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 						Expect(err).To(MatchError("the following errors occurred:\nerror syncing key pair,\nfailed to set"))
 						Expect(stateStore.SetCall.CallCount).To(Equal(2))
 						Expect(stateStore.SetCall.Receives[1].State.KeyPair.Name).To(Equal("keypair-bbl-lake-time-stamp"))
@@ -620,7 +742,7 @@ var _ = Describe("AWSUp", func() {
 				It("saves the public/private key and returns an error", func() {
 					availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("availability zone retrieve failed")
 
-					err := command.Execute(commands.AWSUpConfig{}, storage.State{
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 						EnvID: "bbl-lake-time:stamp",
 					})
 					Expect(err).To(MatchError("availability zone retrieve failed"))
@@ -634,7 +756,7 @@ var _ = Describe("AWSUp", func() {
 				It("saves the stack name and bosh az and returns an error", func() {
 					infrastructureManager.CreateCall.Returns.Error = errors.New("infrastructure creation failed")
 
-					err := command.Execute(commands.AWSUpConfig{
+					err := command.Execute(context.Background(), commands.AWSUpConfig{
 						BOSHAZ: "some-bosh-az",
 					}, storage.State{
 						EnvID: "bbl-lake-time-stamp",
@@ -648,7 +770,7 @@ var _ = Describe("AWSUp", func() {
 				It("saves the private/public key and returns an error", func() {
 					infrastructureManager.CreateCall.Returns.Error = errors.New("infrastructure creation failed")
 
-					err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 					Expect(err).To(MatchError("infrastructure creation failed"))
 					Expect(stateStore.SetCall.CallCount).To(Equal(3))
 					Expect(stateStore.SetCall.Receives[2].State.KeyPair.PrivateKey).To(Equal("some-private-key"))
@@ -660,7 +782,7 @@ var _ = Describe("AWSUp", func() {
 		Describe("state manipulation", func() {
 			Context("iaas", func() {
 				It("writes iaas aws to state", func() {
-					err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(stateStore.SetCall.CallCount).To(Equal(4))
@@ -671,7 +793,7 @@ var _ = Describe("AWSUp", func() {
 			Context("aws credentials", func() {
 				Context("when the credentials do not exist", func() {
 					It("saves the credentials", func() {
-						err := command.Execute(commands.AWSUpConfig{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{
 							AccessKeyID:     "some-aws-access-key-id",
 							SecretAccessKey: "some-aws-secret-access-key",
 							Region:          "some-aws-region",
@@ -688,7 +810,7 @@ var _ = Describe("AWSUp", func() {
 				})
 				Context("when the credentials do exist", func() {
 					It("overrides the credentials when they're passed in", func() {
-						err := command.Execute(commands.AWSUpConfig{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{
 							AccessKeyID:     "new-aws-access-key-id",
 							SecretAccessKey: "new-aws-secret-access-key",
 							Region:          "new-aws-region",
@@ -710,7 +832,7 @@ var _ = Describe("AWSUp", func() {
 					})
 
 					It("does not override the credentials when they're not passed in", func() {
-						err := command.Execute(commands.AWSUpConfig{}, storage.State{
+						err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 							AWS: storage.AWS{
 								AccessKeyID:     "aws-access-key-id",
 								SecretAccessKey: "aws-secret-access-key",
@@ -735,7 +857,7 @@ var _ = Describe("AWSUp", func() {
 						incomingState := storage.State{
 							EnvID: "bbl-lake-time-stamp",
 						}
-						err := command.Execute(commands.AWSUpConfig{}, incomingState)
+						err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(stateStore.SetCall.CallCount).To(Equal(4))
@@ -751,7 +873,7 @@ var _ = Describe("AWSUp", func() {
 								Name: "some-other-stack-name",
 							},
 						}
-						err := command.Execute(commands.AWSUpConfig{}, incomingState)
+						err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(stateStore.SetCall.CallCount).To(Equal(3))
@@ -768,7 +890,7 @@ var _ = Describe("AWSUp", func() {
 
 				Context("bosh state", func() {
 					It("writes the bosh state", func() {
-						err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+						err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(stateStore.SetCall.CallCount).To(Equal(4))
@@ -795,7 +917,7 @@ var _ = Describe("AWSUp", func() {
 			It("returns an error when the env id manager fails", func() {
 				envIDManager.SyncCall.Returns.Error = errors.New("env id manager failed")
 
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "some-aws-access-key-id",
 					SecretAccessKey: "some-aws-secret-access-key",
 					Region:          "some-aws-region",
@@ -810,7 +932,7 @@ var _ = Describe("AWSUp", func() {
 						Error: errors.New("saving the state failed"),
 					},
 				}
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					AccessKeyID:     "some-aws-access-key-id",
 					SecretAccessKey: "some-aws-secret-access-key",
 					Region:          "some-aws-region",
@@ -820,7 +942,7 @@ var _ = Describe("AWSUp", func() {
 
 			It("returns an error when the certificate cannot be described", func() {
 				certificateDescriber.DescribeCall.Returns.Error = errors.New("failed to describe")
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 					Stack: storage.Stack{
 						LBType: "concourse",
 					},
@@ -830,13 +952,13 @@ var _ = Describe("AWSUp", func() {
 
 			It("returns an error when the cloud config cannot be uploaded", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("failed to update")
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("failed to update"))
 			})
 
 			It("returns an error when the broken environment validator fails", func() {
 				brokenEnvironmentValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{
 					IAAS: "aws",
 					AWS: storage.AWS{
 						Region: "some-aws-region",
@@ -870,12 +992,12 @@ var _ = Describe("AWSUp", func() {
 			It("returns an error when infrastructure cannot be created", func() {
 				infrastructureManager.CreateCall.Returns.Error = errors.New("infrastructure creation failed")
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("infrastructure creation failed"))
 			})
 
 			It("returns an error when the ops file cannot be read", func() {
-				err := command.Execute(commands.AWSUpConfig{
+				err := command.Execute(context.Background(), commands.AWSUpConfig{
 					OpsFilePath: "some/fake/path",
 				}, storage.State{})
 				Expect(err).To(MatchError("open some/fake/path: no such file or directory"))
@@ -884,47 +1006,47 @@ var _ = Describe("AWSUp", func() {
 			It("returns an error when bosh cannot be deployed", func() {
 				boshManager.CreateCall.Returns.Error = errors.New("cannot deploy bosh")
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("cannot deploy bosh"))
 			})
 
 			It("returns an error when availability zones cannot be retrieved", func() {
 				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("availability zone could not be retrieved")
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("availability zone could not be retrieved"))
 			})
 
 			It("returns an error when state store fails to set the state before syncing the keypair", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to set state")}}
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("failed to set state"))
 			})
 
 			It("returns an error when state store fails to set the state before retrieving availability zones", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set state")}}
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("failed to set state"))
 			})
 
 			It("returns an error when state store fails to set the state before creating the stack", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {errors.New("failed to set state")}}
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("failed to set state"))
 			})
 
 			It("returns an error when state store fails to set the state before updating the cloud config", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {}, {errors.New("failed to set state")}}
 
-				err := command.Execute(commands.AWSUpConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{}, storage.State{})
 				Expect(err).To(MatchError("failed to set state"))
 			})
 
 			It("returns an error when only some of the AWS parameters are provided", func() {
-				err := command.Execute(commands.AWSUpConfig{AccessKeyID: "some-key-id", Region: "some-region"}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSUpConfig{AccessKeyID: "some-key-id", Region: "some-region"}, storage.State{})
 				Expect(err).To(MatchError("AWS secret access key must be provided"))
 			})
 
@@ -950,12 +1072,12 @@ var _ = Describe("AWSUp", func() {
 
 					newState := incomingState
 					newState.BOSH.State = expectedBOSHState
-					expectedError := bosh.NewManagerCreateError(newState, errors.New("failed to create"))
+					expectedError := bosh.NewManagerCreateError(newState, storage.CreateEnvPhase, errors.New("failed to create"))
 					boshManager.CreateCall.Returns.Error = expectedError
 				})
 
 				It("returns the error and saves the state", func() {
-					err := command.Execute(commands.AWSUpConfig{}, incomingState)
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 					Expect(err).To(MatchError("failed to create"))
 					Expect(stateStore.SetCall.CallCount).To(Equal(4))
 					Expect(stateStore.SetCall.Receives[3].State.BOSH.State).To(Equal(expectedBOSHState))
@@ -963,7 +1085,7 @@ var _ = Describe("AWSUp", func() {
 
 				It("returns a compound error when it fails to save the state", func() {
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {}, {errors.New("state failed to be set")}}
-					err := command.Execute(commands.AWSUpConfig{}, incomingState)
+					err := command.Execute(context.Background(), commands.AWSUpConfig{}, incomingState)
 					Expect(err).To(MatchError("the following errors occurred:\nfailed to create,\nstate failed to be set"))
 					Expect(stateStore.SetCall.CallCount).To(Equal(4))
 					Expect(stateStore.SetCall.Receives[3].State.BOSH.State).To(Equal(expectedBOSHState))
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"strings"
@@ -38,7 +39,7 @@ func NewAWSUpdateLBs(awsCreateLBs awsCreateLBs, credentialValidator credentialVa
 	}
 }
 
-func (c AWSUpdateLBs) Execute(config AWSCreateLBsConfig, state storage.State) error {
+func (c AWSUpdateLBs) Execute(ctx context.Context, config AWSCreateLBsConfig, state storage.State) error {
 	err := c.credentialValidator.Validate()
 	if err != nil {
 		return err
@@ -57,7 +58,7 @@ func (c AWSUpdateLBs) Execute(config AWSCreateLBsConfig, state storage.State) er
 			config.LBType = state.LB.Type
 		}
 
-		return c.awsCreateLBs.Execute(config, state)
+		return c.awsCreateLBs.Execute(ctx, config, state)
 	}
 
 	if !lbExists(state.Stack.LBType) {
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
@@ -35,7 +36,7 @@ var _ = Describe("AWS Update LBs", func() {
 	)
 
 	var updateLBs = func(certificatePath, keyPath, chainPath string, state storage.State) error {
-		return command.Execute(commands.AWSCreateLBsConfig{
+		return command.Execute(context.Background(), commands.AWSCreateLBsConfig{
 			CertPath:  certificatePath,
 			KeyPath:   keyPath,
 			ChainPath: chainPath,
@@ -111,7 +112,7 @@ var _ = Describe("AWS Update LBs", func() {
 					LBType:   "cf",
 					Domain:   "some-domain",
 				}
-				err := command.Execute(config, incomingTerraformState)
+				err := command.Execute(context.Background(), config, incomingTerraformState)
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(awsCreateLBs.ExecuteCall.CallCount).To(Equal(1))
@@ -132,7 +133,7 @@ var _ = Describe("AWS Update LBs", func() {
 						LBType:   "cf",
 						Domain:   "",
 					}
-					err := command.Execute(config, incomingTerraformState)
+					err := command.Execute(context.Background(), config, incomingTerraformState)
 
 					Expect(err).NotTo(HaveOccurred())
 					Expect(awsCreateLBs.ExecuteCall.CallCount).To(Equal(1))
@@ -154,7 +155,7 @@ var _ = Describe("AWS Update LBs", func() {
 						LBType:   "",
 						Domain:   "some-domain",
 					}
-					err := command.Execute(config, incomingTerraformState)
+					err := command.Execute(context.Background(), config, incomingTerraformState)
 
 					Expect(err).NotTo(HaveOccurred())
 					Expect(awsCreateLBs.ExecuteCall.CallCount).To(Equal(1))
@@ -343,7 +344,7 @@ var _ = Describe("AWS Update LBs", func() {
 			It("returns an error when aws credential validator fails", func() {
 				credentialValidator.ValidateCall.Returns.Error = errors.New("aws credentials validator failed")
 
-				err := command.Execute(commands.AWSCreateLBsConfig{}, storage.State{})
+				err := command.Execute(context.Background(), commands.AWSCreateLBsConfig{}, storage.State{})
 
 				Expect(err).To(MatchError("aws credentials validator failed"))
 			})
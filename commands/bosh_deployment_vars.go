@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 const (
 	BOSHDeploymentVarsCommand = "bosh-deployment-vars"
@@ -8,13 +12,15 @@ const (
 
 type BOSHDeploymentVars struct {
 	logger         logger
+	outputWriter   outputWriter
 	boshManager    boshManager
 	stateValidator stateValidator
 }
 
-func NewBOSHDeploymentVars(logger logger, boshManager boshManager, stateValidator stateValidator) BOSHDeploymentVars {
+func NewBOSHDeploymentVars(logger logger, outputWriter outputWriter, boshManager boshManager, stateValidator stateValidator) BOSHDeploymentVars {
 	return BOSHDeploymentVars{
 		logger:         logger,
+		outputWriter:   outputWriter,
 		boshManager:    boshManager,
 		stateValidator: stateValidator,
 	}
@@ -36,11 +42,22 @@ func (b BOSHDeploymentVars) CheckFastFails(subcommandFlags []string, state stora
 	return nil
 }
 
-func (b BOSHDeploymentVars) Execute(args []string, state storage.State) error {
+func (b BOSHDeploymentVars) Execute(ctx context.Context, args []string, state storage.State) error {
 	vars, err := b.boshManager.GetDeploymentVars(state)
 	if err != nil {
 		return err
 	}
+
+	if !b.outputWriter.IsText() {
+		structuredVars := map[string]interface{}{}
+		err = unmarshal([]byte(vars), &structuredVars)
+		if err != nil {
+			return err
+		}
+
+		return b.outputWriter.Write(stringKeys(structuredVars))
+	}
+
 	b.logger.Println(vars)
 	return nil
 }
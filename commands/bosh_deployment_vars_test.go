@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -16,6 +17,7 @@ var _ = Describe("BOSHDeploymentVars", func() {
 		logger         *fakes.Logger
 		boshManager    *fakes.BOSHManager
 		stateValidator *fakes.StateValidator
+		outputWriter   *fakes.OutputWriter
 
 		boshDeploymentVars commands.BOSHDeploymentVars
 	)
@@ -27,7 +29,10 @@ var _ = Describe("BOSHDeploymentVars", func() {
 
 		boshManager.VersionCall.Returns.Version = "2.0.0"
 
-		boshDeploymentVars = commands.NewBOSHDeploymentVars(logger, boshManager, stateValidator)
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+
+		boshDeploymentVars = commands.NewBOSHDeploymentVars(logger, outputWriter, boshManager, stateValidator)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -61,16 +66,42 @@ var _ = Describe("BOSHDeploymentVars", func() {
 	Describe("Execute", func() {
 		It("calls out to bosh manager and prints the resulting information", func() {
 			boshManager.GetDeploymentVarsCall.Returns.Vars = "some-vars-yaml"
-			err := boshDeploymentVars.Execute([]string{}, storage.State{})
+			err := boshDeploymentVars.Execute(context.Background(), []string{}, storage.State{})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(boshManager.GetDeploymentVarsCall.CallCount).To(Equal(1))
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("some-vars-yaml"))
 		})
 
+		Context("when the output format is not text", func() {
+			BeforeEach(func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+			})
+
+			It("writes the deployment vars to the output writer", func() {
+				boshManager.GetDeploymentVarsCall.Returns.Vars = "internal_ip: 10.0.0.6\ntags:\n  some-key: some-value\n"
+				err := boshDeploymentVars.Execute(context.Background(), []string{}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string]interface{}{
+					"internal_ip": "10.0.0.6",
+					"tags": map[string]interface{}{
+						"some-key": "some-value",
+					},
+				}))
+			})
+
+			It("returns an error when the deployment vars are not valid yaml", func() {
+				boshManager.GetDeploymentVarsCall.Returns.Vars = "%%%"
+				err := boshDeploymentVars.Execute(context.Background(), []string{}, storage.State{})
+				Expect(err).To(MatchError(ContainSubstring("yaml")))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when we fail to get deployment vars", func() {
 				boshManager.GetDeploymentVarsCall.Returns.Error = errors.New("failed to get deployment vars")
-				err := boshDeploymentVars.Execute([]string{}, storage.State{})
+				err := boshDeploymentVars.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("failed to get deployment vars"))
 			})
 		})
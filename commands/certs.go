@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const CertsCommand = "certs"
+
+var CertificatesExpiring error = errors.New("one or more certificates are expiring")
+
+type Certs struct {
+	logger               logger
+	outputWriter         outputWriter
+	stateValidator       stateValidator
+	certificateInventory certificateInventory
+}
+
+type certificateInventory interface {
+	List(storage.State) ([]certificates.Certificate, error)
+	LoadBalancer(storage.State) (certificates.Certificate, bool, error)
+}
+
+type CertsReport struct {
+	Certificates []certificates.Certificate `json:"certificates" yaml:"certificates"`
+}
+
+func NewCerts(logger logger, outputWriter outputWriter, stateValidator stateValidator, certificateInventory certificateInventory) Certs {
+	return Certs{
+		logger:               logger,
+		outputWriter:         outputWriter,
+		stateValidator:       stateValidator,
+		certificateInventory: certificateInventory,
+	}
+}
+
+func (c Certs) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	_, _, err := parseCertsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	return c.stateValidator.Validate()
+}
+
+func (c Certs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	expiringWithin, window, err := parseCertsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	list, err := c.certificateInventory.List(state)
+	if err != nil {
+		return err
+	}
+
+	if expiringWithin != "" {
+		list = certificates.ExpiringWithin(list, window, time.Now())
+	}
+
+	report := CertsReport{Certificates: list}
+	if report.Certificates == nil {
+		report.Certificates = []certificates.Certificate{}
+	}
+
+	switch {
+	case !c.outputWriter.IsText():
+		err = c.outputWriter.Write(report)
+		if err != nil {
+			return err
+		}
+	case len(list) == 0 && expiringWithin != "":
+		c.logger.Println(fmt.Sprintf("no certificates expire within %s", expiringWithin))
+	case len(list) == 0:
+		c.logger.Println("no certificates found")
+	default:
+		c.logger.Println(formatCertificates(list))
+	}
+
+	if expiringWithin != "" && len(list) > 0 {
+		return CertificatesExpiring
+	}
+
+	return nil
+}
+
+func parseCertsFlags(subcommandFlags []string) (string, time.Duration, error) {
+	var expiringWithin string
+
+	certsFlags := flags.New("certs")
+	certsFlags.String(&expiringWithin, "expiring-within", "")
+
+	err := certsFlags.Parse(subcommandFlags)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if expiringWithin == "" {
+		return "", 0, nil
+	}
+
+	window, err := parseExpiryWindow(expiringWithin)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return expiringWithin, window, nil
+}
+
+// parseExpiryWindow accepts a number of days, such as 30d, as well as any
+// duration understood by time.ParseDuration.
+func parseExpiryWindow(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	} else if window, err := time.ParseDuration(value); err == nil && window >= 0 {
+		return window, nil
+	}
+
+	return 0, fmt.Errorf("invalid --expiring-within %q, use a number of days such as 30d or a duration such as 72h", value)
+}
+
+func formatCertificates(list []certificates.Certificate) string {
+	buffer := bytes.NewBuffer([]byte{})
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "NAME\tSUBJECT\tEXPIRES")
+	for _, certificate := range list {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", certificate.Name, certificate.Subject, certificate.Expires.Format("2006-01-02"))
+	}
+	writer.Flush()
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
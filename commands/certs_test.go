@@ -0,0 +1,123 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Certs", func() {
+	var (
+		logger               *fakes.Logger
+		outputWriter         *fakes.OutputWriter
+		stateValidator       *fakes.StateValidator
+		certificateInventory *fakes.CertificateInventory
+
+		command commands.Certs
+		state   storage.State
+
+		expiring time.Time
+		valid    time.Time
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		stateValidator = &fakes.StateValidator{}
+		certificateInventory = &fakes.CertificateInventory{}
+
+		command = commands.NewCerts(logger, outputWriter, stateValidator, certificateInventory)
+		state = storage.State{IAAS: "gcp", EnvID: "some-env-id"}
+
+		expiring = time.Now().Add(10 * 24 * time.Hour).UTC().Truncate(24 * time.Hour)
+		valid = time.Now().Add(100 * 24 * time.Hour).UTC().Truncate(24 * time.Hour)
+		certificateInventory.ListCall.Returns.Certificates = []certificates.Certificate{
+			{Name: "load balancer", Subject: "*.cf.example.com", Expires: expiring},
+			{Name: "director/director_ssl", Subject: "10.0.0.6", Expires: valid},
+		}
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state is invalid", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
+
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("state validator failed"))
+		})
+
+		It("returns an error when the window cannot be parsed", func() {
+			err := command.CheckFastFails([]string{"--expiring-within", "a month"}, state)
+			Expect(err).To(MatchError(`invalid --expiring-within "a month", use a number of days such as 30d or a duration such as 72h`))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints every certificate", func() {
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(certificateInventory.ListCall.Receives.State).To(Equal(state))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`NAME\s+SUBJECT\s+EXPIRES`))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`load balancer\s+\*\.cf\.example\.com\s+` + expiring.Format("2006-01-02")))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`director/director_ssl\s+10\.0\.0\.6\s+` + valid.Format("2006-01-02")))
+		})
+
+		Context("when --expiring-within is provided", func() {
+			It("prints the expiring certificates and returns an error", func() {
+				err := command.Execute(context.Background(), []string{"--expiring-within", "30d"}, state)
+				Expect(err).To(Equal(commands.CertificatesExpiring))
+
+				Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring("load balancer"))
+				Expect(logger.PrintlnCall.Receives.Message).NotTo(ContainSubstring("director/director_ssl"))
+			})
+
+			It("accepts a duration", func() {
+				err := command.Execute(context.Background(), []string{"--expiring-within", "72h"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("no certificates expire within 72h"))
+			})
+		})
+
+		Context("when the output format is not text", func() {
+			It("writes the certificates to the output writer", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+
+				err := command.Execute(context.Background(), []string{"--expiring-within", "30d"}, state)
+				Expect(err).To(Equal(commands.CertificatesExpiring))
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(commands.CertsReport{
+					Certificates: []certificates.Certificate{
+						{Name: "load balancer", Subject: "*.cf.example.com", Expires: expiring},
+					},
+				}))
+			})
+		})
+
+		It("prints when there are no certificates", func() {
+			certificateInventory.ListCall.Returns.Certificates = nil
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal("no certificates found"))
+		})
+
+		It("returns an error when the certificates cannot be listed", func() {
+			certificateInventory.ListCall.Returns.Error = errors.New("failed to describe")
+
+			err := command.Execute(context.Background(), []string{}, state)
+			Expect(err).To(MatchError("failed to describe"))
+		})
+	})
+})
@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 const (
 	CloudConfigCommand = "cloud-config"
@@ -29,8 +33,8 @@ func (c CloudConfig) CheckFastFails(subcommandFlags []string, state storage.Stat
 	return nil
 }
 
-func (c CloudConfig) Execute(args []string, state storage.State) error {
-	contents, err := c.cloudConfigManager.Generate(state)
+func (c CloudConfig) Execute(ctx context.Context, args []string, state storage.State) error {
+	contents, err := c.cloudConfigManager.Generate(ctx, state)
 	if err != nil {
 		return err
 	}
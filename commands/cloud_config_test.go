@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -48,7 +49,7 @@ var _ = Describe("CloudConfig", func() {
 
 	Describe("Execute", func() {
 		It("prints the cloud configuration for the bbl environment", func() {
-			err := cloudConfig.Execute([]string{}, state)
+			err := cloudConfig.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cloudConfigManager.GenerateCall.CallCount).To(Equal(1))
 			Expect(cloudConfigManager.GenerateCall.Receives.State).To(Equal(state))
@@ -58,7 +59,7 @@ var _ = Describe("CloudConfig", func() {
 		Context("failure cases", func() {
 			It("returns an error when the cloud config manager fails to generate", func() {
 				cloudConfigManager.GenerateCall.Returns.Error = errors.New("failed to generate cloud configuration")
-				err := cloudConfig.Execute([]string{}, state)
+				err := cloudConfig.Execute(context.Background(), []string{}, state)
 				Expect(err).To(MatchError("failed to generate cloud configuration"))
 			})
 		})
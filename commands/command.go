@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -9,7 +10,7 @@ import (
 
 type Command interface {
 	CheckFastFails(subcommandFlags []string, state storage.State) error
-	Execute(subcommandFlags []string, state storage.State) error
+	Execute(ctx context.Context, subcommandFlags []string, state storage.State) error
 	Usage() string
 }
 
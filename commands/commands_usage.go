@@ -13,11 +13,17 @@ const (
   --aws-secret-access-key    AWS Secret Access Key to use (Defaults to environment variable BBL_AWS_SECRET_ACCESS_KEY)
   --aws-region               AWS region to use (Defaults to environment variable BBL_AWS_REGION)
   [--aws-bosh-az]            AWS availability zone to use for BOSH director (Defaults to environment variable BBL_AWS_BOSH_AZ)
+  [--aws-vpc-id]             Existing AWS VPC to deploy into instead of creating one, requires --terraform (Defaults to environment variable BBL_AWS_VPC_ID)
+  [--aws-subnet-ids]         Comma-separated subnets in the existing VPC to use (Defaults to environment variable BBL_AWS_SUBNET_IDS)
+  [--aws-iam-instance-profile] Gives the director an IAM instance profile instead of an access key, requires --terraform
 
   --gcp-service-account-key  GCP Service Access Key to use (Defaults to environment variable BBL_GCP_SERVICE_ACCOUNT_KEY)
   --gcp-project-id           GCP Project ID to use (Defaults to environment variable BBL_GCP_PROJECT_ID)
   --gcp-zone                 GCP Zone to use (Defaults to environment variable BBL_GCP_ZONE)
-  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)`
+  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)
+  [--gcp-network]            Existing GCP network to deploy into instead of creating one (Defaults to environment variable BBL_GCP_NETWORK)
+  [--gcp-subnetwork]         Subnetwork of the existing GCP network to use (Defaults to environment variable BBL_GCP_SUBNETWORK)
+  [--gcp-network-project-id] Shared VPC host project that owns the existing network (Defaults to environment variable BBL_GCP_NETWORK_PROJECT_ID)`
 
 	DestroyCommandUsage = `Tears down BOSH director infrastructure
 
@@ -26,12 +32,18 @@ const (
 
 	CreateLBsCommandUsage = `Attaches load balancer(s) with a certificate, key, and optional chain
 
-  --type              Load balancer(s) type. Valid options: "concourse" or "cf"
-  [--cert]            Path to SSL certificate (conditionally required; refer to table below)
-  [--key]             Path to SSL certificate key (conditionally required; refer to table below)
-  [--chain]           Path to SSL certificate chain (optional; applicable if --cert/--key are required; refer to table below)
-  [--domain]          Creates a nameserver with a zone for given domain (supported when type="cf")
-  [--skip-if-exists]  Skips creating load balancer(s) if it is already attached (optional)
+  --type                  Load balancer(s) type. Valid options: "concourse" or "cf"
+  [--cert]                Path to SSL certificate (conditionally required; refer to table below)
+  [--key]                 Path to SSL certificate key (conditionally required; refer to table below)
+  [--chain]               Path to SSL certificate chain (optional; applicable if --cert/--key are required; refer to table below)
+  [--domain]              Creates a nameserver with a zone for given domain (supported when type="cf")
+  [--skip-if-exists]      Skips creating load balancer(s) if it is already attached (optional)
+  [--generate-cert]       Generates a CA and a certificate for the domain, or for the load balancer address, instead of --cert/--key (optional; requires terraform on aws)
+  [--ca-cert]             Path to a CA certificate to issue the generated certificate from (optional; requires --generate-cert)
+  [--ca-key]              Path to the private key of --ca-cert (required with --ca-cert)
+  [--acme]                Obtains the certificate for the domain from an ACME directory using DNS-01 challenges, instead of --cert/--key (optional; requires type="cf" and --domain, and terraform on aws)
+  [--acme-directory-url]  ACME directory to use with --acme (optional; defaults to Let's Encrypt)
+  [--acme-email]          Contact email for the ACME account (optional)
 
   --cert/--key requirements:
   ------------------------------
@@ -48,13 +60,16 @@ const (
   --key                Path to SSL certificate key
   [--chain]            Path to SSL certificate chain (optional)
   [--domain]           Updates domain in the nameserver zone (supported when type="cf", optional)
-  [--skip-if-missing]  Skips updating load balancer(s) if it is not attached (optional)`
+  [--skip-if-missing]  Skips updating load balancer(s) if it is not attached (optional)
+  [--acme-renew]       Renews a certificate obtained with create-lbs --acme instead of using --cert/--key (optional)`
 
 	DeleteLBsCommandUsage = `Deletes load balancer(s)
 
   [--skip-if-missing]  Skips deleting load balancer(s) if it is not attached (optional)`
 
-	LBsCommandUsage = "Prints attached load balancer(s)"
+	LBsCommandUsage = `Prints attached load balancer(s)
+
+  [--ca]  Prints the CA of a certificate generated by create-lbs --generate-cert (optional)`
 
 	VersionCommandUsage = "Prints version"
 
@@ -64,7 +79,12 @@ const (
 
 	SSHKeyCommandUsage = "Prints SSH private key for the jumpbox user. This can be used to ssh to the director/use the director as a gateway host."
 
-	RotateCommandUsage = "Rotates the keypair for BOSH"
+	RotateCommandUsage = `Rotates the keypair for BOSH
+
+  [--director-credentials]  Regenerates the director passwords and the certificates issued by its CAs (optional)
+  [--agent-credentials]     Regenerates the passwords the agents on deployed VMs use as well, with --director-credentials (optional)
+  [--director-ca]           Adds a new CA next to each director CA; run again, once deployed VMs trust it, to remove the old CAs (optional)
+  [--iaas-credentials]      Gives the director a new AWS access key, or a new key for a GCP service account of its own, and deletes the previous one (optional)`
 
 	DirectorUsernameCommandUsage = "Prints BOSH director username"
 
@@ -74,13 +94,52 @@ const (
 
 	DirectorCACertCommandUsage = "Prints BOSH director CA certificate"
 
-	PrintEnvCommandUsage = "Prints required BOSH environment variables"
+	PrintEnvCommandUsage = `Prints required BOSH environment variables
+
+  [--shell]  Shell syntax to print the variables in. Valid options: "bash", "fish", "powershell", "dotenv" (Defaults to "bash")`
+
+	LatestErrorCommandUsage = `Prints the output from the latest call to terraform
 
-	LatestErrorCommandUsage = "Prints the output from the latest call to terraform"
+  [--phase]  Prints the latest failure of a phase instead. Valid options: "terraform", "create-env", "delete-env", "jumpbox", "cloud-config"
+  [--all]    Prints all recorded failures, oldest first (optional, combine with --phase to filter)`
 
 	BOSHDeploymentVarsCommandUsage = "Prints required variables for BOSH deployment"
 
 	CloudConfigUsage = "Prints suggested cloud configuration for BOSH environment"
+
+	OutputsCommandUsage = `Prints all terraform outputs, or CloudFormation stack outputs for legacy AWS environments
+
+  [--reveal]  Prints sensitive outputs instead of masking them (optional)`
+
+	StateCommandUsage = `Prints a field of the bbl state
+
+  get [<path>]  Dot separated path of the field to print, e.g. bosh.directorAddress (optional, prints the whole state)
+  [--reveal]    Prints secret fields instead of masking them (optional)`
+
+	IAMPolicyCommandUsage = `Prints the AWS policy document or GCP custom role with the permissions bbl needs
+
+  --iaas       IAAS to print the permissions for. Valid options: "gcp", "aws" (Defaults to the iaas of the current environment)
+  [--lb-type]  Includes the permissions for a load balancer type. Valid options: "concourse" or "cf" (Defaults to the current load balancer)
+  [--domain]   Includes the permissions for a cf load balancer domain (Defaults to the current domain)`
+
+	DoctorCommandUsage = "Checks that bosh, terraform, the state directory and the IaaS credentials are ready for bbl up"
+
+	DriftCommandUsage = "Compares the infrastructure with the bbl state and lists changed, missing and extra resources. Exits non-zero when drift is found"
+
+	MigrateCommandUsage = `Moves an AWS environment from its CloudFormation stack to terraform without changing its resources
+
+  --to-terraform  Imports the stack's resources into terraform, checks that terraform would not change them and then deletes the stack but keeps its resources
+  [--cert]        Path to the load balancer certificate, required when the stack has a load balancer
+  [--key]         Path to the load balancer certificate's private key, required when the stack has a load balancer
+  [--chain]       Path to the load balancer certificate chain (optional)`
+
+	StatusCommandUsage = `Checks the health of the BOSH director and its infrastructure
+
+  [--json]  Prints the health report as JSON, the same as --output json (optional)`
+
+	CertsCommandUsage = `Prints the expiry dates of the certificates bbl manages
+
+  [--expiring-within]  Only prints the certificates that expire within a number of days, e.g. 30d, or a duration, e.g. 72h, and exits non-zero if there are any (optional)`
 )
 
 func (Up) Usage() string { return UpCommandUsage }
@@ -111,6 +170,22 @@ func (Rotate) Usage() string { return RotateCommandUsage }
 
 func (SSHKey) Usage() string { return SSHKeyCommandUsage }
 
+func (Status) Usage() string { return StatusCommandUsage }
+
+func (Certs) Usage() string { return CertsCommandUsage }
+
+func (Doctor) Usage() string { return DoctorCommandUsage }
+
+func (IAMPolicy) Usage() string { return IAMPolicyCommandUsage }
+
+func (Drift) Usage() string { return DriftCommandUsage }
+
+func (Migrate) Usage() string { return MigrateCommandUsage }
+
+func (Outputs) Usage() string { return OutputsCommandUsage }
+
+func (StateGet) Usage() string { return StateCommandUsage }
+
 func (s StateQuery) Usage() string {
 	switch s.propertyName {
 	case EnvIDPropertyName:
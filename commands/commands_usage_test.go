@@ -26,11 +26,17 @@ var _ = Describe("Commands Usage", func() {
   --aws-secret-access-key    AWS Secret Access Key to use (Defaults to environment variable BBL_AWS_SECRET_ACCESS_KEY)
   --aws-region               AWS region to use (Defaults to environment variable BBL_AWS_REGION)
   [--aws-bosh-az]            AWS availability zone to use for BOSH director (Defaults to environment variable BBL_AWS_BOSH_AZ)
+  [--aws-vpc-id]             Existing AWS VPC to deploy into instead of creating one, requires --terraform (Defaults to environment variable BBL_AWS_VPC_ID)
+  [--aws-subnet-ids]         Comma-separated subnets in the existing VPC to use (Defaults to environment variable BBL_AWS_SUBNET_IDS)
+  [--aws-iam-instance-profile] Gives the director an IAM instance profile instead of an access key, requires --terraform
 
   --gcp-service-account-key  GCP Service Access Key to use (Defaults to environment variable BBL_GCP_SERVICE_ACCOUNT_KEY)
   --gcp-project-id           GCP Project ID to use (Defaults to environment variable BBL_GCP_PROJECT_ID)
   --gcp-zone                 GCP Zone to use (Defaults to environment variable BBL_GCP_ZONE)
-  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)`))
+  --gcp-region               GCP Region to use (Defaults to environment variable BBL_GCP_REGION)
+  [--gcp-network]            Existing GCP network to deploy into instead of creating one (Defaults to environment variable BBL_GCP_NETWORK)
+  [--gcp-subnetwork]         Subnetwork of the existing GCP network to use (Defaults to environment variable BBL_GCP_SUBNETWORK)
+  [--gcp-network-project-id] Shared VPC host project that owns the existing network (Defaults to environment variable BBL_GCP_NETWORK_PROJECT_ID)`))
 			})
 		})
 	})
@@ -42,12 +48,18 @@ var _ = Describe("Commands Usage", func() {
 				usageText := command.Usage()
 				Expect(usageText).To(Equal(`Attaches load balancer(s) with a certificate, key, and optional chain
 
-  --type              Load balancer(s) type. Valid options: "concourse" or "cf"
-  [--cert]            Path to SSL certificate (conditionally required; refer to table below)
-  [--key]             Path to SSL certificate key (conditionally required; refer to table below)
-  [--chain]           Path to SSL certificate chain (optional; applicable if --cert/--key are required; refer to table below)
-  [--domain]          Creates a nameserver with a zone for given domain (supported when type="cf")
-  [--skip-if-exists]  Skips creating load balancer(s) if it is already attached (optional)
+  --type                  Load balancer(s) type. Valid options: "concourse" or "cf"
+  [--cert]                Path to SSL certificate (conditionally required; refer to table below)
+  [--key]                 Path to SSL certificate key (conditionally required; refer to table below)
+  [--chain]               Path to SSL certificate chain (optional; applicable if --cert/--key are required; refer to table below)
+  [--domain]              Creates a nameserver with a zone for given domain (supported when type="cf")
+  [--skip-if-exists]      Skips creating load balancer(s) if it is already attached (optional)
+  [--generate-cert]       Generates a CA and a certificate for the domain, or for the load balancer address, instead of --cert/--key (optional; requires terraform on aws)
+  [--ca-cert]             Path to a CA certificate to issue the generated certificate from (optional; requires --generate-cert)
+  [--ca-key]              Path to the private key of --ca-cert (required with --ca-cert)
+  [--acme]                Obtains the certificate for the domain from an ACME directory using DNS-01 challenges, instead of --cert/--key (optional; requires type="cf" and --domain, and terraform on aws)
+  [--acme-directory-url]  ACME directory to use with --acme (optional; defaults to Let's Encrypt)
+  [--acme-email]          Contact email for the ACME account (optional)
 
   --cert/--key requirements:
   ------------------------------
@@ -61,6 +73,18 @@ var _ = Describe("Commands Usage", func() {
 		})
 	})
 
+	Describe("LBs", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.LBs{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints attached load balancer(s)
+
+  [--ca]  Prints the CA of a certificate generated by create-lbs --generate-cert (optional)`))
+			})
+		})
+	})
+
 	Describe("Update LBs", func() {
 		Describe("Usage", func() {
 			It("returns string describing usage", func() {
@@ -72,7 +96,8 @@ var _ = Describe("Commands Usage", func() {
   --key                Path to SSL certificate key
   [--chain]            Path to SSL certificate chain (optional)
   [--domain]           Updates domain in the nameserver zone (supported when type="cf", optional)
-  [--skip-if-missing]  Skips updating load balancer(s) if it is not attached (optional)`))
+  [--skip-if-missing]  Skips updating load balancer(s) if it is not attached (optional)
+  [--acme-renew]       Renews a certificate obtained with create-lbs --acme instead of using --cert/--key (optional)`))
 			})
 		})
 	})
@@ -102,6 +127,124 @@ var _ = Describe("Commands Usage", func() {
 		})
 	})
 
+	Describe("Status", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Status{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Checks the health of the BOSH director and its infrastructure
+
+  [--json]  Prints the health report as JSON, the same as --output json (optional)`))
+			})
+		})
+	})
+
+	Describe("Certs", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Certs{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints the expiry dates of the certificates bbl manages
+
+  [--expiring-within]  Only prints the certificates that expire within a number of days, e.g. 30d, or a duration, e.g. 72h, and exits non-zero if there are any (optional)`))
+			})
+		})
+	})
+
+	Describe("Rotate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Rotate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Rotates the keypair for BOSH
+
+  [--director-credentials]  Regenerates the director passwords and the certificates issued by its CAs (optional)
+  [--agent-credentials]     Regenerates the passwords the agents on deployed VMs use as well, with --director-credentials (optional)
+  [--director-ca]           Adds a new CA next to each director CA; run again, once deployed VMs trust it, to remove the old CAs (optional)
+  [--iaas-credentials]      Gives the director a new AWS access key, or a new key for a GCP service account of its own, and deletes the previous one (optional)`))
+			})
+		})
+	})
+
+	Describe("PrintEnv", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.PrintEnv{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints required BOSH environment variables
+
+  [--shell]  Shell syntax to print the variables in. Valid options: "bash", "fish", "powershell", "dotenv" (Defaults to "bash")`))
+			})
+		})
+	})
+
+	Describe("LatestError", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.LatestError{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints the output from the latest call to terraform
+
+  [--phase]  Prints the latest failure of a phase instead. Valid options: "terraform", "create-env", "delete-env", "jumpbox", "cloud-config"
+  [--all]    Prints all recorded failures, oldest first (optional, combine with --phase to filter)`))
+			})
+		})
+	})
+
+	Describe("Migrate", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Migrate{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Moves an AWS environment from its CloudFormation stack to terraform without changing its resources
+
+  --to-terraform  Imports the stack's resources into terraform, checks that terraform would not change them and then deletes the stack but keeps its resources
+  [--cert]        Path to the load balancer certificate, required when the stack has a load balancer
+  [--key]         Path to the load balancer certificate's private key, required when the stack has a load balancer
+  [--chain]       Path to the load balancer certificate chain (optional)`))
+			})
+		})
+	})
+
+	Describe("IAMPolicy", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.IAMPolicy{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints the AWS policy document or GCP custom role with the permissions bbl needs
+
+  --iaas       IAAS to print the permissions for. Valid options: "gcp", "aws" (Defaults to the iaas of the current environment)
+  [--lb-type]  Includes the permissions for a load balancer type. Valid options: "concourse" or "cf" (Defaults to the current load balancer)
+  [--domain]   Includes the permissions for a cf load balancer domain (Defaults to the current domain)`))
+			})
+		})
+	})
+
+	Describe("Outputs", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.Outputs{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints all terraform outputs, or CloudFormation stack outputs for legacy AWS environments
+
+  [--reveal]  Prints sensitive outputs instead of masking them (optional)`))
+			})
+		})
+	})
+
+	Describe("StateGet", func() {
+		Describe("Usage", func() {
+			It("returns string describing usage", func() {
+				command := commands.StateGet{}
+				usageText := command.Usage()
+				Expect(usageText).To(Equal(`Prints a field of the bbl state
+
+  get [<path>]  Dot separated path of the field to print, e.g. bosh.directorAddress (optional, prints the whole state)
+  [--reveal]    Prints secret fields instead of masking them (optional)`))
+			})
+		})
+	})
+
 	Describe("Usage", func() {
 		Describe("Usage", func() {
 			It("returns string describing usage", func() {
@@ -116,15 +259,12 @@ var _ = Describe("Commands Usage", func() {
 		usageText := command.Usage()
 		Expect(usageText).To(Equal(expectedDescription))
 	},
-		Entry("LBs", commands.LBs{}, "Prints attached load balancer(s)"),
 		Entry("director-address", newStateQuery("director address"), "Prints BOSH director address"),
 		Entry("director-password", newStateQuery("director password"), "Prints BOSH director password"),
 		Entry("director-username", newStateQuery("director username"), "Prints BOSH director username"),
 		Entry("director-ca-cert", newStateQuery("director ca cert"), "Prints BOSH director CA certificate"),
 		Entry("env-id", newStateQuery("environment id"), "Prints environment ID"),
 		Entry("ssh-key", commands.SSHKey{}, "Prints SSH private key for the jumpbox user. This can be used to ssh to the director/use the director as a gateway host."),
-		Entry("print-env", commands.PrintEnv{}, "Prints required BOSH environment variables"),
-		Entry("latest-error", commands.LatestError{}, "Prints the output from the latest call to terraform"),
 		Entry("bosh-deployment-vars", commands.BOSHDeploymentVars{}, "Prints required variables for BOSH deployment"),
 		Entry("version", commands.Version{}, "Prints version"),
 		Entry("cloud-config", commands.CloudConfig{}, "Prints suggested cloud configuration for BOSH environment"),
@@ -132,5 +272,5 @@ var _ = Describe("Commands Usage", func() {
 })
 
 func newStateQuery(propertyName string) commands.StateQuery {
-	return commands.NewStateQuery(nil, nil, nil, nil, propertyName)
+	return commands.NewStateQuery(nil, nil, nil, nil, nil, propertyName)
 }
@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/acme"
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -12,31 +16,39 @@ type CreateLBs struct {
 	gcpCreateLBs   gcpCreateLBs
 	stateValidator stateValidator
 	boshManager    boshManager
+	quotaChecker   quotaChecker
 }
 
 type lbConfig struct {
-	lbType       string
-	certPath     string
-	keyPath      string
-	chainPath    string
-	domain       string
-	skipIfExists bool
+	lbType           string
+	certPath         string
+	keyPath          string
+	chainPath        string
+	domain           string
+	skipIfExists     bool
+	generateCert     bool
+	caCertPath       string
+	caKeyPath        string
+	acme             bool
+	acmeDirectoryURL string
+	acmeEmail        string
 }
 
 type gcpCreateLBs interface {
-	Execute(GCPCreateLBsConfig, storage.State) error
+	Execute(context.Context, GCPCreateLBsConfig, storage.State) error
 }
 
 type awsCreateLBs interface {
-	Execute(AWSCreateLBsConfig, storage.State) error
+	Execute(context.Context, AWSCreateLBsConfig, storage.State) error
 }
 
-func NewCreateLBs(awsCreateLBs awsCreateLBs, gcpCreateLBs gcpCreateLBs, stateValidator stateValidator, boshManager boshManager) CreateLBs {
+func NewCreateLBs(awsCreateLBs awsCreateLBs, gcpCreateLBs gcpCreateLBs, stateValidator stateValidator, boshManager boshManager, quotaChecker quotaChecker) CreateLBs {
 	return CreateLBs{
 		awsCreateLBs:   awsCreateLBs,
 		gcpCreateLBs:   gcpCreateLBs,
 		stateValidator: stateValidator,
 		boshManager:    boshManager,
+		quotaChecker:   quotaChecker,
 	}
 }
 
@@ -52,10 +64,92 @@ func (c CreateLBs) CheckFastFails(subcommandFlags []string, state storage.State)
 		}
 	}
 
+	config, err := c.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkGenerateCert(config, state)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkACME(config, state)
+	if err != nil {
+		return err
+	}
+
+	return c.checkQuotas(config, state)
+}
+
+func (CreateLBs) checkGenerateCert(config lbConfig, state storage.State) error {
+	if !config.generateCert {
+		if config.caCertPath != "" || config.caKeyPath != "" {
+			return errors.New("--ca-cert and --ca-key can only be used with --generate-cert")
+		}
+		return nil
+	}
+
+	if config.certPath != "" || config.keyPath != "" || config.chainPath != "" {
+		return errors.New("--generate-cert cannot be used with --cert, --key or --chain")
+	}
+
+	if (config.caCertPath == "") != (config.caKeyPath == "") {
+		return errors.New("--ca-cert and --ca-key must be provided together")
+	}
+
+	if state.IAAS == "aws" && state.TFState == "" {
+		return errors.New("--generate-cert requires an environment created with --terraform")
+	}
+
+	if state.IAAS == "gcp" && config.lbType == "concourse" {
+		return errors.New("--generate-cert is not supported for gcp concourse load balancers, which do not use a certificate")
+	}
+
+	return nil
+}
+
+func (CreateLBs) checkACME(config lbConfig, state storage.State) error {
+	if !config.acme {
+		if config.acmeDirectoryURL != "" || config.acmeEmail != "" {
+			return errors.New("--acme-directory-url and --acme-email can only be used with --acme")
+		}
+		return nil
+	}
+
+	if config.certPath != "" || config.keyPath != "" || config.chainPath != "" || config.generateCert {
+		return errors.New("--acme cannot be used with --cert, --key, --chain or --generate-cert")
+	}
+
+	if config.lbType != "cf" || config.domain == "" {
+		return errors.New("--acme requires a cf load balancer with --domain")
+	}
+
+	if state.IAAS == "aws" && state.TFState == "" {
+		return errors.New("--acme requires an environment created with --terraform")
+	}
+
 	return nil
 }
 
-func (c CreateLBs) Execute(args []string, state storage.State) error {
+func (c CreateLBs) checkQuotas(config lbConfig, state storage.State) error {
+	if state.IAAS == "aws" && state.TFState == "" {
+		return nil
+	}
+
+	if config.skipIfExists && config.lbType == state.LB.Type {
+		return nil
+	}
+
+	state.LB.Type = config.lbType
+	if config.domain != "" {
+		state.LB.Domain = config.domain
+	}
+
+	return c.quotaChecker.Check(state)
+}
+
+func (c CreateLBs) Execute(ctx context.Context, args []string, state storage.State) error {
 	config, err := c.parseFlags(args)
 	if err != nil {
 		return err
@@ -63,23 +157,35 @@ func (c CreateLBs) Execute(args []string, state storage.State) error {
 
 	switch state.IAAS {
 	case "gcp":
-		if err := c.gcpCreateLBs.Execute(GCPCreateLBsConfig{
-			LBType:       config.lbType,
-			CertPath:     config.certPath,
-			KeyPath:      config.keyPath,
-			Domain:       config.domain,
-			SkipIfExists: config.skipIfExists,
+		if err := c.gcpCreateLBs.Execute(ctx, GCPCreateLBsConfig{
+			LBType:           config.lbType,
+			CertPath:         config.certPath,
+			KeyPath:          config.keyPath,
+			Domain:           config.domain,
+			SkipIfExists:     config.skipIfExists,
+			GenerateCert:     config.generateCert,
+			CACertPath:       config.caCertPath,
+			CAKeyPath:        config.caKeyPath,
+			ACME:             config.acme,
+			ACMEDirectoryURL: config.acmeDirectoryURL,
+			ACMEEmail:        config.acmeEmail,
 		}, state); err != nil {
 			return err
 		}
 	case "aws":
-		if err := c.awsCreateLBs.Execute(AWSCreateLBsConfig{
-			LBType:       config.lbType,
-			CertPath:     config.certPath,
-			KeyPath:      config.keyPath,
-			ChainPath:    config.chainPath,
-			Domain:       config.domain,
-			SkipIfExists: config.skipIfExists,
+		if err := c.awsCreateLBs.Execute(ctx, AWSCreateLBsConfig{
+			LBType:           config.lbType,
+			CertPath:         config.certPath,
+			KeyPath:          config.keyPath,
+			ChainPath:        config.chainPath,
+			Domain:           config.domain,
+			SkipIfExists:     config.skipIfExists,
+			GenerateCert:     config.generateCert,
+			CACertPath:       config.caCertPath,
+			CAKeyPath:        config.caKeyPath,
+			ACME:             config.acme,
+			ACMEDirectoryURL: config.acmeDirectoryURL,
+			ACMEEmail:        config.acmeEmail,
 		}, state); err != nil {
 			return err
 		}
@@ -98,10 +204,20 @@ func (CreateLBs) parseFlags(subcommandFlags []string) (lbConfig, error) {
 	lbFlags.String(&config.chainPath, "chain", "")
 	lbFlags.String(&config.domain, "domain", "")
 	lbFlags.Bool(&config.skipIfExists, "skip-if-exists", "", false)
+	lbFlags.Bool(&config.generateCert, "", "generate-cert", false)
+	lbFlags.String(&config.caCertPath, "ca-cert", "")
+	lbFlags.String(&config.caKeyPath, "ca-key", "")
+	lbFlags.Bool(&config.acme, "", "acme", false)
+	lbFlags.String(&config.acmeDirectoryURL, "acme-directory-url", "")
+	lbFlags.String(&config.acmeEmail, "acme-email", "")
 
 	if err := lbFlags.Parse(subcommandFlags); err != nil {
 		return config, err
 	}
 
+	if config.acme && config.acmeDirectoryURL == "" {
+		config.acmeDirectoryURL = acme.LetsEncryptDirectoryURL
+	}
+
 	return config, nil
 }
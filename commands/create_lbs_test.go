@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -8,6 +9,7 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
@@ -18,6 +20,7 @@ var _ = Describe("create-lbs", func() {
 		gcpCreateLBs   *fakes.GCPCreateLBs
 		stateValidator *fakes.StateValidator
 		boshManager    *fakes.BOSHManager
+		quotaChecker   *fakes.QuotaChecker
 	)
 
 	BeforeEach(func() {
@@ -26,8 +29,9 @@ var _ = Describe("create-lbs", func() {
 		stateValidator = &fakes.StateValidator{}
 		boshManager = &fakes.BOSHManager{}
 		boshManager.VersionCall.Returns.Version = "2.0.0"
+		quotaChecker = &fakes.QuotaChecker{}
 
-		command = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager)
+		command = commands.NewCreateLBs(awsCreateLBs, gcpCreateLBs, stateValidator, boshManager, quotaChecker)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -65,11 +69,122 @@ var _ = Describe("create-lbs", func() {
 			})
 		})
 
+		Describe("quotas", func() {
+			It("checks the quotas for the state with the requested lb", func() {
+				err := command.CheckFastFails([]string{
+					"--type", "cf",
+					"--domain", "some-domain",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.Receives.State).To(Equal(storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						Type:   "cf",
+						Domain: "some-domain",
+					},
+				}))
+			})
+
+			It("returns an error when there is not enough quota", func() {
+				quotaChecker.CheckCall.Returns.Error = errors.New("not enough quota")
+
+				err := command.CheckFastFails([]string{"--type", "cf"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("not enough quota"))
+			})
+
+			It("does not check the quotas when the lb exists and --skip-if-exists is provided", func() {
+				err := command.CheckFastFails([]string{
+					"--type", "cf",
+					"--skip-if-exists",
+				}, storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						Type: "cf",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(0))
+			})
+
+			It("does not check the quotas of an aws environment that uses cloudformation", func() {
+				err := command.CheckFastFails([]string{"--type", "cf"}, storage.State{IAAS: "aws"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Describe("--generate-cert", func() {
+			It("does not return an error for a terraform environment", func() {
+				err := command.CheckFastFails([]string{
+					"--type", "cf",
+					"--generate-cert",
+					"--ca-cert", "ca.crt",
+					"--ca-key", "ca.key",
+				}, storage.State{IAAS: "aws", TFState: "some-tf-state"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			DescribeTable("returns an error for invalid flag combinations",
+				func(flags []string, state storage.State, expectedErr string) {
+					err := command.CheckFastFails(append([]string{"--type", "cf"}, flags...), state)
+					Expect(err).To(MatchError(expectedErr))
+				},
+				Entry("with --cert", []string{"--generate-cert", "--cert", "my-cert"}, storage.State{IAAS: "gcp"},
+					"--generate-cert cannot be used with --cert, --key or --chain"),
+				Entry("with only --ca-cert", []string{"--generate-cert", "--ca-cert", "ca.crt"}, storage.State{IAAS: "gcp"},
+					"--ca-cert and --ca-key must be provided together"),
+				Entry("with --ca-key but no --generate-cert", []string{"--ca-key", "ca.key"}, storage.State{IAAS: "gcp"},
+					"--ca-cert and --ca-key can only be used with --generate-cert"),
+				Entry("in a cloudformation environment", []string{"--generate-cert"}, storage.State{IAAS: "aws"},
+					"--generate-cert requires an environment created with --terraform"),
+			)
+
+			It("returns an error for a gcp concourse lb", func() {
+				err := command.CheckFastFails([]string{"--type", "concourse", "--generate-cert"}, storage.State{IAAS: "gcp"})
+				Expect(err).To(MatchError("--generate-cert is not supported for gcp concourse load balancers, which do not use a certificate"))
+			})
+		})
+
+		Describe("--acme", func() {
+			It("does not return an error for a cf lb with a domain in a terraform environment", func() {
+				err := command.CheckFastFails([]string{
+					"--type", "cf",
+					"--domain", "example.com",
+					"--acme",
+					"--acme-email", "ops@example.com",
+				}, storage.State{IAAS: "aws", TFState: "some-tf-state"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			DescribeTable("returns an error for invalid flag combinations",
+				func(flags []string, state storage.State, expectedErr string) {
+					err := command.CheckFastFails(flags, state)
+					Expect(err).To(MatchError(expectedErr))
+				},
+				Entry("with --cert", []string{"--type", "cf", "--domain", "example.com", "--acme", "--cert", "my-cert"}, storage.State{IAAS: "gcp"},
+					"--acme cannot be used with --cert, --key, --chain or --generate-cert"),
+				Entry("with --generate-cert", []string{"--type", "cf", "--domain", "example.com", "--acme", "--generate-cert"}, storage.State{IAAS: "gcp"},
+					"--acme cannot be used with --cert, --key, --chain or --generate-cert"),
+				Entry("without --domain", []string{"--type", "cf", "--acme"}, storage.State{IAAS: "gcp"},
+					"--acme requires a cf load balancer with --domain"),
+				Entry("for a concourse lb", []string{"--type", "concourse", "--domain", "example.com", "--acme"}, storage.State{IAAS: "gcp"},
+					"--acme requires a cf load balancer with --domain"),
+				Entry("in a cloudformation environment", []string{"--type", "cf", "--domain", "example.com", "--acme"}, storage.State{IAAS: "aws"},
+					"--acme requires an environment created with --terraform"),
+				Entry("with --acme-email but no --acme", []string{"--type", "cf", "--acme-email", "ops@example.com"}, storage.State{IAAS: "gcp"},
+					"--acme-directory-url and --acme-email can only be used with --acme"),
+			)
+		})
 	})
 
 	Describe("Execute", func() {
 		It("creates a GCP lb type if the iaas if GCP", func() {
-			err := command.Execute([]string{
+			err := command.Execute(context.Background(), []string{
 				"--type", "concourse",
 				"--skip-if-exists",
 			}, storage.State{
@@ -83,7 +198,7 @@ var _ = Describe("create-lbs", func() {
 		})
 
 		It("creates a GCP cf lb type is the iaas if GCP and type is cf", func() {
-			err := command.Execute([]string{
+			err := command.Execute(context.Background(), []string{
 				"--type", "cf",
 				"--cert", "my-cert",
 				"--key", "my-key",
@@ -103,7 +218,7 @@ var _ = Describe("create-lbs", func() {
 		})
 
 		It("creates an AWS lb type if the iaas is AWS", func() {
-			err := command.Execute([]string{
+			err := command.Execute(context.Background(), []string{
 				"--type", "concourse",
 				"--cert", "my-cert",
 				"--key", "my-key",
@@ -125,16 +240,74 @@ var _ = Describe("create-lbs", func() {
 			}))
 		})
 
+		It("passes --generate-cert and the CA to the iaas specific command", func() {
+			err := command.Execute(context.Background(), []string{
+				"--type", "cf",
+				"--generate-cert",
+				"--ca-cert", "ca.crt",
+				"--ca-key", "ca.key",
+			}, storage.State{
+				IAAS: "gcp",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpCreateLBs.ExecuteCall.Receives.Config).Should(Equal(commands.GCPCreateLBsConfig{
+				LBType:       "cf",
+				GenerateCert: true,
+				CACertPath:   "ca.crt",
+				CAKeyPath:    "ca.key",
+			}))
+		})
+
+		It("passes --acme to the iaas specific command with the let's encrypt directory by default", func() {
+			err := command.Execute(context.Background(), []string{
+				"--type", "cf",
+				"--domain", "example.com",
+				"--acme",
+				"--acme-email", "ops@example.com",
+			}, storage.State{
+				IAAS: "aws",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(awsCreateLBs.ExecuteCall.Receives.Config).Should(Equal(commands.AWSCreateLBsConfig{
+				LBType:           "cf",
+				Domain:           "example.com",
+				ACME:             true,
+				ACMEDirectoryURL: "https://acme-v02.api.letsencrypt.org/directory",
+				ACMEEmail:        "ops@example.com",
+			}))
+		})
+
+		It("passes a custom acme directory to the iaas specific command", func() {
+			err := command.Execute(context.Background(), []string{
+				"--type", "cf",
+				"--domain", "example.com",
+				"--acme",
+				"--acme-directory-url", "https://localhost:14000/dir",
+			}, storage.State{
+				IAAS: "gcp",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpCreateLBs.ExecuteCall.Receives.Config).Should(Equal(commands.GCPCreateLBsConfig{
+				LBType:           "cf",
+				Domain:           "example.com",
+				ACME:             true,
+				ACMEDirectoryURL: "https://localhost:14000/dir",
+			}))
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when an invalid command line flag is supplied", func() {
-				err := command.Execute([]string{"--invalid-flag"}, storage.State{})
+				err := command.Execute(context.Background(), []string{"--invalid-flag"}, storage.State{})
 				Expect(err).To(MatchError("flag provided but not defined: -invalid-flag"))
 			})
 
 			It("returns an error when the AWSCreateLBs fails", func() {
 				awsCreateLBs.ExecuteCall.Returns.Error = errors.New("something bad happened")
 
-				err := command.Execute([]string{"some-aws-args"}, storage.State{
+				err := command.Execute(context.Background(), []string{"some-aws-args"}, storage.State{
 					IAAS: "aws",
 				})
 				Expect(err).To(MatchError("something bad happened"))
@@ -143,7 +316,7 @@ var _ = Describe("create-lbs", func() {
 			It("returns an error when the GCPCreateLBs fails", func() {
 				gcpCreateLBs.ExecuteCall.Returns.Error = errors.New("something bad happened")
 
-				err := command.Execute([]string{"some-gcp-args"}, storage.State{
+				err := command.Execute(context.Background(), []string{"some-gcp-args"}, storage.State{
 					IAAS: "gcp",
 				})
 				Expect(err).To(MatchError("something bad happened"))
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cloudfoundry/bosh-bootloader/flags"
@@ -16,11 +17,11 @@ type DeleteLBs struct {
 }
 
 type gcpDeleteLBs interface {
-	Execute(state storage.State) error
+	Execute(ctx context.Context, state storage.State) error
 }
 
 type awsDeleteLBs interface {
-	Execute(state storage.State) error
+	Execute(ctx context.Context, state storage.State) error
 }
 
 func NewDeleteLBs(gcpDeleteLBs gcpDeleteLBs, awsDeleteLBs awsDeleteLBs,
@@ -50,7 +51,7 @@ func (d DeleteLBs) CheckFastFails(subcommandFlags []string, state storage.State)
 	return nil
 }
 
-func (d DeleteLBs) Execute(subcommandFlags []string, state storage.State) error {
+func (d DeleteLBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	config, err := d.parseFlags(subcommandFlags)
 	if err != nil {
 		return err
@@ -63,9 +64,9 @@ func (d DeleteLBs) Execute(subcommandFlags []string, state storage.State) error
 
 	switch state.IAAS {
 	case "gcp":
-		return d.gcpDeleteLBs.Execute(state)
+		return d.gcpDeleteLBs.Execute(ctx, state)
 	case "aws":
-		return d.awsDeleteLBs.Execute(state)
+		return d.awsDeleteLBs.Execute(ctx, state)
 	default:
 		return fmt.Errorf("%q is an invalid iaas type in state, supported iaas types are: [gcp, aws]", state.IAAS)
 	}
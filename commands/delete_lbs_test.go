@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -75,7 +76,7 @@ var _ = Describe("DeleteLBs", func() {
 	Describe("Execute", func() {
 		Context("when iaas is gcp", func() {
 			It("calls gcp delete lbs", func() {
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS: "gcp",
 					LB: storage.LB{
 						Type: "concourse",
@@ -95,7 +96,7 @@ var _ = Describe("DeleteLBs", func() {
 
 		Context("when iaas is aws", func() {
 			It("calls aws delete lbs", func() {
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS: "aws",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -115,7 +116,7 @@ var _ = Describe("DeleteLBs", func() {
 
 		Context("when --skip-if-missing is provided", func() {
 			DescribeTable("no-ops", func(state storage.State) {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--skip-if-missing",
 				}, state)
 				Expect(err).NotTo(HaveOccurred())
@@ -138,7 +139,7 @@ var _ = Describe("DeleteLBs", func() {
 			)
 
 			DescribeTable("deletes the LB", func(state storage.State) {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--skip-if-missing",
 				}, state)
 				Expect(err).NotTo(HaveOccurred())
@@ -166,7 +167,7 @@ var _ = Describe("DeleteLBs", func() {
 
 		Context("failure cases", func() {
 			It("returns an error when an unknown flag is provided", func() {
-				err := command.Execute([]string{"--unknown-flag"}, storage.State{})
+				err := command.Execute(context.Background(), []string{"--unknown-flag"}, storage.State{})
 				Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
 
 				Expect(awsDeleteLBs.ExecuteCall.CallCount).To(Equal(0))
@@ -174,7 +175,7 @@ var _ = Describe("DeleteLBs", func() {
 			})
 
 			It("returns an error when an unknown iaas is in the state", func() {
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS: "some-unknown-iaas",
 				})
 				Expect(err).To(MatchError(`"some-unknown-iaas" is an invalid iaas type in state, supported iaas types are: [gcp, aws]`))
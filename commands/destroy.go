@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"reflect"
@@ -133,7 +134,9 @@ func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) e
 	}
 
 	var terraformOutputs map[string]interface{}
-	if state.IAAS == "gcp" {
+	// bbl does not own an existing network, so other instances in it are
+	// expected and destroy leaves the network in place.
+	if state.IAAS == "gcp" && state.GCP.ExistingNetwork == "" {
 		terraformOutputs, err = d.terraformManager.GetOutputs(state)
 		if err == nil {
 			networkName, ok := terraformOutputs["network_name"].(string)
@@ -147,6 +150,12 @@ func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) e
 	}
 
 	if state.IAAS == "aws" {
+		// bbl does not own an existing vpc, so other instances in it are expected
+		// and destroy leaves the vpc in place.
+		if state.AWS.ExistingVPCID != "" {
+			return nil
+		}
+
 		if state.TFState != "" {
 			outputs, err := d.terraformManager.GetOutputs(state)
 			if err == nil {
@@ -182,7 +191,7 @@ func (d Destroy) CheckFastFails(subcommandFlags []string, state storage.State) e
 	return nil
 }
 
-func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
+func (d Destroy) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	config, err := d.parseFlags(subcommandFlags)
 	if err != nil {
 		return err
@@ -216,7 +225,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 		return err
 	}
 
-	state, err = d.deleteBOSH(state, stack)
+	state, err = d.deleteBOSH(ctx, state, stack)
 	switch err.(type) {
 	case bosh.ManagerDeleteError:
 		mdErr := err.(bosh.ManagerDeleteError)
@@ -238,7 +247,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 
 	if state.IAAS == "aws" {
 		if state.TFState != "" {
-			state, err = d.terraformManager.Destroy(state)
+			state, err = d.terraformManager.Destroy(ctx, state)
 			if err != nil {
 				return handleTerraformError(err, d.stateStore)
 			}
@@ -251,7 +260,7 @@ func (d Destroy) Execute(subcommandFlags []string, state storage.State) error {
 	}
 
 	if state.IAAS == "gcp" {
-		state, err = d.terraformManager.Destroy(state)
+		state, err = d.terraformManager.Destroy(ctx, state)
 		if err != nil {
 			return handleTerraformError(err, d.stateStore)
 		}
@@ -314,7 +323,7 @@ func (d Destroy) parseFlags(subcommandFlags []string) (destroyConfig, error) {
 	return config, nil
 }
 
-func (d Destroy) deleteBOSH(state storage.State, stack cloudformation.Stack) (storage.State, error) {
+func (d Destroy) deleteBOSH(ctx context.Context, state storage.State, stack cloudformation.Stack) (storage.State, error) {
 	emptyBOSH := storage.BOSH{}
 	if reflect.DeepEqual(state.BOSH, emptyBOSH) {
 		d.logger.Println("no BOSH director, skipping...")
@@ -323,7 +332,7 @@ func (d Destroy) deleteBOSH(state storage.State, stack cloudformation.Stack) (st
 
 	d.logger.Step("destroying bosh director")
 
-	err := d.boshManager.Delete(state)
+	err := d.boshManager.Delete(ctx, state)
 	if err != nil {
 		return state, err
 	}
@@ -2,6 +2,7 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -199,6 +200,19 @@ var _ = Describe("Destroy", func() {
 				Expect(err).To(MatchError("validation failed"))
 			})
 
+			Context("when the environment was deployed into an existing network", func() {
+				It("does not check the network for other instances", func() {
+					networkInstancesChecker.ValidateSafeToDeleteCall.Returns.Error = errors.New("validation failed")
+					bblState.GCP.ExistingNetwork = "some-network-name"
+					bblState.GCP.ExistingSubnetwork = "some-subnetwork-name"
+
+					err := destroy.CheckFastFails([]string{}, bblState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(networkInstancesChecker.ValidateSafeToDeleteCall.CallCount).To(Equal(0))
+				})
+			})
+
 			Context("when terraform output provider fails to get terraform outputs", func() {
 				It("does not fast fail", func() {
 					terraformManager.GetOutputsCall.Returns.Error = errors.New("terraform output provider failed")
@@ -319,13 +333,28 @@ var _ = Describe("Destroy", func() {
 					Expect(vpcStatusChecker.ValidateSafeToDeleteCall.Receives.VPCID).To(Equal("some-vpc-id"))
 					Expect(vpcStatusChecker.ValidateSafeToDeleteCall.Receives.EnvID).To(Equal("some-env-id"))
 				})
+
+				Context("when the environment was deployed into an existing vpc", func() {
+					It("does not check the vpc for other instances", func() {
+						state.AWS.ExistingVPCID = "some-vpc-id"
+						terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+							"vpc_id": "some-vpc-id",
+						}
+						vpcStatusChecker.ValidateSafeToDeleteCall.Returns.Error = errors.New("vpc some-vpc-id is not safe to delete")
+
+						err := destroy.CheckFastFails([]string{}, state)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(vpcStatusChecker.ValidateSafeToDeleteCall.CallCount).To(Equal(0))
+					})
+				})
 			})
 		})
 	})
 
 	Describe("Execute", func() {
 		It("returns when there is no state and --skip-if-missing flag is provided", func() {
-			err := destroy.Execute([]string{"--skip-if-missing"}, storage.State{})
+			err := destroy.Execute(context.Background(), []string{"--skip-if-missing"}, storage.State{})
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(logger.StepCall.Receives.Message).To(Equal("state file not found, and --skip-if-missing flag provided, exiting"))
@@ -335,7 +364,7 @@ var _ = Describe("Destroy", func() {
 			func(response string, proceed bool) {
 				fmt.Fprintf(stdin, "%s\n", response)
 
-				err := destroy.Execute([]string{}, storage.State{
+				err := destroy.Execute(context.Background(), []string{}, storage.State{
 					BOSH: storage.BOSH{
 						DirectorName: "some-director",
 					},
@@ -364,7 +393,7 @@ var _ = Describe("Destroy", func() {
 
 		Context("when the --no-confirm flag is supplied", func() {
 			DescribeTable("destroys without prompting the user for confirmation", func(flag string) {
-				err := destroy.Execute([]string{flag}, storage.State{
+				err := destroy.Execute(context.Background(), []string{flag}, storage.State{
 					BOSH: storage.BOSH{
 						DirectorName: "some-director",
 					},
@@ -387,7 +416,7 @@ var _ = Describe("Destroy", func() {
 				},
 			}
 
-			err := destroy.Execute([]string{}, state)
+			err := destroy.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(boshManager.DeleteCall.CallCount).To(Equal(1))
@@ -396,7 +425,7 @@ var _ = Describe("Destroy", func() {
 
 		It("clears the state", func() {
 			stdin.Write([]byte("yes\n"))
-			err := destroy.Execute([]string{}, storage.State{
+			err := destroy.Execute(context.Background(), []string{}, storage.State{
 				Stack: storage.Stack{
 					Name:            "some-stack-name",
 					LBType:          "some-lb-type",
@@ -416,7 +445,7 @@ var _ = Describe("Destroy", func() {
 
 			Context("when an invalid command line flag is supplied", func() {
 				It("returns an error", func() {
-					err := destroy.Execute([]string{"--invalid-flag"}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{"--invalid-flag"}, storage.State{})
 					Expect(err).To(MatchError("flag provided but not defined: -invalid-flag"))
 					Expect(credentialValidator.ValidateCall.CallCount).To(Equal(0))
 				})
@@ -426,7 +455,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					boshManager.DeleteCall.Returns.Error = errors.New("bosh delete-env failed")
 
-					err := destroy.Execute([]string{}, storage.State{
+					err := destroy.Execute(context.Background(), []string{}, storage.State{
 						BOSH: storage.BOSH{
 							DirectorName: "some-director",
 						},
@@ -439,7 +468,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to set state")}}
 
-					err := destroy.Execute([]string{}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("failed to set state"))
 				})
 			})
@@ -448,7 +477,7 @@ var _ = Describe("Destroy", func() {
 				It("returns an error", func() {
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set state")}}
 
-					err := destroy.Execute([]string{}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("failed to set state"))
 				})
 			})
@@ -457,7 +486,7 @@ var _ = Describe("Destroy", func() {
 				It("return an error", func() {
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {errors.New("failed to set state")}}
 
-					err := destroy.Execute([]string{}, storage.State{})
+					err := destroy.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).To(MatchError("failed to set state"))
 				})
 			})
@@ -506,7 +535,7 @@ var _ = Describe("Destroy", func() {
 
 				Context("when infrastructure was created with cloudformation", func() {
 					It("deletes the stack", func() {
-						err := destroy.Execute([]string{}, state)
+						err := destroy.Execute(context.Background(), []string{}, state)
 						Expect(err).NotTo(HaveOccurred())
 
 						Expect(logger.StepCall.Messages).To(ContainElement("destroying AWS stack"))
@@ -522,7 +551,7 @@ var _ = Describe("Destroy", func() {
 					})
 
 					It("deletes infrastructure with terraform", func() {
-						err := destroy.Execute([]string{}, state)
+						err := destroy.Execute(context.Background(), []string{}, state)
 						Expect(err).NotTo(HaveOccurred())
 
 						expectedState := state
@@ -553,7 +582,7 @@ var _ = Describe("Destroy", func() {
 						})
 
 						It("saves the partially destroyed tf state", func() {
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(Equal(terraformManagerError))
 
 							Expect(terraformManager.DestroyCall.CallCount).To(Equal(1))
@@ -571,7 +600,7 @@ var _ = Describe("Destroy", func() {
 							})
 
 							It("returns an error containing both messages", func() {
-								err := destroy.Execute([]string{}, state)
+								err := destroy.Execute(context.Background(), []string{}, state)
 
 								Expect(err).To(MatchError("the following errors occurred:\nfailed to destroy,\nsome-bbl-state-error"))
 								Expect(stateStore.SetCall.CallCount).To(Equal(1))
@@ -581,7 +610,7 @@ var _ = Describe("Destroy", func() {
 						Context("and the state fails to be set", func() {
 							It("returns an error containing both messages", func() {
 								stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set state")}}
-								err := destroy.Execute([]string{}, storage.State{
+								err := destroy.Execute(context.Background(), []string{}, storage.State{
 									IAAS: "gcp",
 								})
 
@@ -592,7 +621,7 @@ var _ = Describe("Destroy", func() {
 				})
 
 				It("deletes the certificate", func() {
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(certificateDeleter.DeleteCall.Receives.CertificateName).To(Equal("some-certificate-name"))
@@ -601,21 +630,21 @@ var _ = Describe("Destroy", func() {
 
 				It("doesn't call delete certificate if there is no certificate to delete", func() {
 					state.Stack.CertificateName = ""
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(certificateDeleter.DeleteCall.CallCount).To(Equal(0))
 				})
 
 				It("deletes the keypair", func() {
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(awsKeyPairDeleter.DeleteCall.Receives.Name).To(Equal("some-ec2-key-pair-name"))
 				})
 
 				It("logs the bosh deletion", func() {
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.StepCall.Messages).To(ContainElement("destroying bosh director"))
@@ -626,7 +655,7 @@ var _ = Describe("Destroy", func() {
 						It("removes the bosh properties from state and returns an error", func() {
 							infrastructureManager.DeleteCall.Returns.Error = errors.New("failed to delete stack")
 
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("failed to delete stack"))
 
 							Expect(stateStore.SetCall.CallCount).To(Equal(1))
@@ -656,7 +685,7 @@ var _ = Describe("Destroy", func() {
 					Context("when there is no bosh to delete", func() {
 						It("does not attempt to delete the bosh", func() {
 							state.BOSH = storage.BOSH{}
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).NotTo(HaveOccurred())
 
 							Expect(logger.PrintlnCall.Receives.Message).To(Equal("no BOSH director, skipping..."))
@@ -669,7 +698,7 @@ var _ = Describe("Destroy", func() {
 						It("removes the stack from the state and returns an error", func() {
 							certificateDeleter.DeleteCall.Returns.Error = errors.New("failed to delete certificate")
 
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("failed to delete certificate"))
 
 							Expect(stateStore.SetCall.CallCount).To(Equal(2))
@@ -700,7 +729,7 @@ var _ = Describe("Destroy", func() {
 						It("removes the certificate from the state and returns an error", func() {
 							awsKeyPairDeleter.DeleteCall.Returns.Error = errors.New("failed to delete keypair")
 
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("failed to delete keypair"))
 
 							Expect(stateStore.SetCall.CallCount).To(Equal(3))
@@ -734,7 +763,7 @@ var _ = Describe("Destroy", func() {
 
 						It("does not validate the vpc", func() {
 							state.Stack = storage.Stack{}
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).NotTo(HaveOccurred())
 
 							Expect(vpcStatusChecker.ValidateSafeToDeleteCall.CallCount).To(Equal(0))
@@ -742,7 +771,7 @@ var _ = Describe("Destroy", func() {
 
 						It("does not attempt to delete the stack", func() {
 							state.Stack = storage.Stack{}
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).NotTo(HaveOccurred())
 
 							Expect(logger.PrintlnCall.Receives.Message).To(Equal("No infrastructure found, skipping..."))
@@ -761,7 +790,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						stackManager.DescribeCall.Returns.Error = errors.New("cannot describe stack")
 
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 						})
 						Expect(err).To(MatchError("cannot describe stack"))
@@ -772,7 +801,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						infrastructureManager.DeleteCall.Returns.Error = errors.New("failed to delete stack")
 
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 							Stack: storage.Stack{
 								Name: "some-stack-name",
@@ -786,7 +815,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						awsKeyPairDeleter.DeleteCall.Returns.Error = errors.New("failed to delete keypair")
 
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 						})
 						Expect(err).To(MatchError("failed to delete keypair"))
@@ -797,7 +826,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						certificateDeleter.DeleteCall.Returns.Error = errors.New("failed to delete certificate")
 
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 							Stack: storage.Stack{
 								CertificateName: "some-certificate",
@@ -810,7 +839,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {errors.New("failed to set state")}}
 
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 							Stack: storage.Stack{
 								CertificateName: "some-certificate-name",
@@ -853,7 +882,7 @@ var _ = Describe("Destroy", func() {
 						})
 
 						It("saves the bosh state and returns an error", func() {
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("deletion failed"))
 							Expect(stateStore.SetCall.CallCount).To(Equal(1))
 							Expect(stateStore.SetCall.Receives[0].State).To(Equal(errState))
@@ -863,14 +892,14 @@ var _ = Describe("Destroy", func() {
 							stateStore.SetCall.Returns = []fakes.SetCallReturn{{
 								errors.New("saving state failed"),
 							}}
-							err := destroy.Execute([]string{}, state)
+							err := destroy.Execute(context.Background(), []string{}, state)
 							Expect(err).To(MatchError("the following errors occurred:\ndeletion failed,\nsaving state failed"))
 						})
 					})
 
 					It("returns an error", func() {
 						boshManager.DeleteCall.Returns.Error = errors.New("deletion failed")
-						err := destroy.Execute([]string{}, state)
+						err := destroy.Execute(context.Background(), []string{}, state)
 						Expect(err).To(MatchError("deletion failed"))
 					})
 				})
@@ -887,7 +916,7 @@ var _ = Describe("Destroy", func() {
 						},
 					}
 					stdin.Write([]byte("yes\n"))
-					err := destroy.Execute([]string{}, state)
+					err := destroy.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(awsKeyPairDeleter.DeleteCall.CallCount).To(Equal(1))
@@ -898,7 +927,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						stdin.Write([]byte("yes\n"))
 						awsKeyPairDeleter.DeleteCall.Returns.Error = errors.New("failed to destroy")
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "aws",
 						})
 
@@ -949,7 +978,7 @@ var _ = Describe("Destroy", func() {
 
 			It("calls terraform destroy", func() {
 				stdin.Write([]byte("yes\n"))
-				err := destroy.Execute([]string{}, bblState)
+				err := destroy.Execute(context.Background(), []string{}, bblState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(terraformManager.DestroyCall.CallCount).To(Equal(1))
@@ -975,7 +1004,7 @@ var _ = Describe("Destroy", func() {
 				})
 
 				It("saves the partially destroyed tf state", func() {
-					err := destroy.Execute([]string{}, bblState)
+					err := destroy.Execute(context.Background(), []string{}, bblState)
 					Expect(err).To(Equal(terraformManagerError))
 
 					Expect(terraformManager.DestroyCall.CallCount).To(Equal(1))
@@ -993,7 +1022,7 @@ var _ = Describe("Destroy", func() {
 					})
 
 					It("returns an error containing both messages", func() {
-						err := destroy.Execute([]string{}, bblState)
+						err := destroy.Execute(context.Background(), []string{}, bblState)
 
 						Expect(err).To(MatchError("the following errors occurred:\nfailed to destroy,\nsome-bbl-state-error"))
 						Expect(stateStore.SetCall.CallCount).To(Equal(1))
@@ -1003,7 +1032,7 @@ var _ = Describe("Destroy", func() {
 				Context("and the state fails to be set", func() {
 					It("returns an error containing both messages", func() {
 						stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set state")}}
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "gcp",
 						})
 
@@ -1015,7 +1044,7 @@ var _ = Describe("Destroy", func() {
 			Context("deleting the keypair", func() {
 				It("deletes the keypair", func() {
 					stdin.Write([]byte("yes\n"))
-					err := destroy.Execute([]string{}, storage.State{
+					err := destroy.Execute(context.Background(), []string{}, storage.State{
 						IAAS: "gcp",
 						KeyPair: storage.KeyPair{
 							PublicKey: "some-public-key",
@@ -1034,7 +1063,7 @@ var _ = Describe("Destroy", func() {
 					It("returns an error", func() {
 						stdin.Write([]byte("yes\n"))
 						gcpKeyPairDeleter.DeleteCall.Returns.Error = errors.New("failed to destroy")
-						err := destroy.Execute([]string{}, storage.State{
+						err := destroy.Execute(context.Background(), []string{}, storage.State{
 							IAAS: "gcp",
 						})
 
@@ -0,0 +1,269 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	DoctorCommand = "doctor"
+
+	DoctorCheckPassed  = "pass"
+	DoctorCheckFailed  = "fail"
+	DoctorCheckSkipped = "skip"
+)
+
+var DoctorChecksFailed error = errors.New("one or more doctor checks failed, see the hints above to fix them")
+
+type Doctor struct {
+	logger                    logger
+	outputWriter              outputWriter
+	boshManager               boshManager
+	terraformManager          terraformManager
+	credentialValidator       credentialValidator
+	availabilityZoneRetriever availabilityZoneRetriever
+	gcpClientProvider         gcpClientProvider
+	stateDir                  string
+}
+
+type gcpClientProvider interface {
+	SetConfig(serviceAccountKey, projectID, zone string) error
+	Client() gcp.Client
+}
+
+type DoctorCheck struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	Hint    string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+type DoctorReport struct {
+	Passed bool          `json:"passed" yaml:"passed"`
+	Checks []DoctorCheck `json:"checks" yaml:"checks"`
+}
+
+func NewDoctor(logger logger, outputWriter outputWriter, boshManager boshManager, terraformManager terraformManager,
+	credentialValidator credentialValidator, availabilityZoneRetriever availabilityZoneRetriever,
+	gcpClientProvider gcpClientProvider, stateDir string) Doctor {
+	return Doctor{
+		logger:                    logger,
+		outputWriter:              outputWriter,
+		boshManager:               boshManager,
+		terraformManager:          terraformManager,
+		credentialValidator:       credentialValidator,
+		availabilityZoneRetriever: availabilityZoneRetriever,
+		gcpClientProvider:         gcpClientProvider,
+		stateDir:                  stateDir,
+	}
+}
+
+func (d Doctor) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	return flags.New("doctor").Parse(subcommandFlags)
+}
+
+func (d Doctor) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	report := d.Report(state)
+
+	if d.outputWriter.IsText() {
+		d.logger.Println(formatDoctorReport(report))
+	} else {
+		err := d.outputWriter.Write(report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !report.Passed {
+		return DoctorChecksFailed
+	}
+
+	return nil
+}
+
+func (d Doctor) Report(state storage.State) DoctorReport {
+	stateFileCheck := d.checkStateFile()
+
+	checks := []DoctorCheck{
+		d.checkBOSH(),
+		d.checkTerraform(),
+		d.checkStateDir(),
+		stateFileCheck,
+	}
+
+	if stateFileCheck.Status == DoctorCheckFailed {
+		checks = append(checks, DoctorCheck{Name: "iaas credentials"}.skip(
+			fmt.Sprintf("credentials cannot be checked until %s can be read", storage.StateFileName)))
+	} else {
+		checks = append(checks, d.checkCredentials(state))
+	}
+
+	report := DoctorReport{
+		Passed: true,
+		Checks: checks,
+	}
+	for _, check := range checks {
+		if check.Status == DoctorCheckFailed {
+			report.Passed = false
+		}
+	}
+
+	return report
+}
+
+func (d Doctor) checkBOSH() DoctorCheck {
+	check := DoctorCheck{Name: "bosh cli"}
+
+	version, err := d.boshManager.Version()
+	switch err.(type) {
+	case bosh.BOSHVersionError:
+		return check.pass("development build of the bosh cli found")
+	case error:
+		return check.fail(fmt.Sprintf("bosh cli could not be run: %s", err),
+			"install the bosh cli v2.0.0 or later and make sure it is on your PATH as bosh")
+	}
+
+	err = validateBOSHVersion(version)
+	if err != nil {
+		return check.fail(fmt.Sprintf("bosh cli %s is incompatible: %s", version, err),
+			"upgrade the bosh cli to v2.0.0 or later")
+	}
+
+	return check.pass(fmt.Sprintf("bosh cli %s found", version))
+}
+
+func (d Doctor) checkTerraform() DoctorCheck {
+	check := DoctorCheck{Name: "terraform"}
+
+	version, err := d.terraformManager.Version()
+	if err != nil {
+		return check.fail(fmt.Sprintf("terraform could not be run: %s", err),
+			"install terraform v0.8.5 or later and make sure it is on your PATH")
+	}
+
+	err = d.terraformManager.ValidateVersion()
+	if err != nil {
+		return check.fail(fmt.Sprintf("terraform %s is incompatible: %s", version, err),
+			"install terraform v0.8.5 or later, excluding v0.9.0")
+	}
+
+	return check.pass(fmt.Sprintf("terraform %s found", version))
+}
+
+func (d Doctor) checkStateDir() DoctorCheck {
+	check := DoctorCheck{Name: "state directory"}
+	hint := "make sure the state directory exists and is writable, or choose another one with --state-dir"
+
+	file, err := ioutil.TempFile(d.stateDir, ".bbl-doctor-")
+	if err != nil {
+		return check.fail(fmt.Sprintf("%s is not writable: %s", d.stateDir, err), hint)
+	}
+	file.Close()
+
+	err = os.Remove(file.Name())
+	if err != nil {
+		return check.fail(fmt.Sprintf("%s is not writable: %s", d.stateDir, err), hint)
+	}
+
+	return check.pass(fmt.Sprintf("%s is writable", d.stateDir))
+}
+
+func (d Doctor) checkStateFile() DoctorCheck {
+	check := DoctorCheck{Name: storage.StateFileName}
+
+	_, err := os.Stat(filepath.Join(d.stateDir, storage.StateFileName))
+	if os.IsNotExist(err) {
+		return check.skip(fmt.Sprintf("no %s found in %s, bbl up will create a new environment", storage.StateFileName, d.stateDir))
+	}
+
+	_, err = storage.GetState(d.stateDir)
+	if err != nil {
+		return check.fail(fmt.Sprintf("%s could not be read: %s", storage.StateFileName, err),
+			fmt.Sprintf("restore %s from a backup or fix the reported problem by hand", storage.StateFileName))
+	}
+
+	return check.pass(fmt.Sprintf("%s is valid", storage.StateFileName))
+}
+
+func (d Doctor) checkCredentials(state storage.State) DoctorCheck {
+	check := DoctorCheck{Name: "iaas credentials"}
+
+	if state.IAAS == "" {
+		return check.skip("no iaas found in state, bbl up will check the credentials it is given")
+	}
+
+	hint := fmt.Sprintf("update the %s credentials by running bbl up with the credential flags or BBL_%s_* environment variables", state.IAAS, strings.ToUpper(state.IAAS))
+
+	err := d.credentialValidator.Validate()
+	if err != nil {
+		return check.fail(err.Error(), hint)
+	}
+
+	switch state.IAAS {
+	case "aws":
+		zones, err := d.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+		if err != nil {
+			return check.fail(fmt.Sprintf("failed to describe availability zones in %s: %s", state.AWS.Region, err),
+				hint+", and make sure the credentials are allowed to call ec2:DescribeAvailabilityZones")
+		}
+
+		return check.pass(fmt.Sprintf("aws credentials can see %d availability zones in %s", len(zones), state.AWS.Region))
+	case "gcp":
+		err := d.gcpClientProvider.SetConfig(state.GCP.ServiceAccountKey, state.GCP.ProjectID, state.GCP.Zone)
+		if err != nil {
+			return check.fail(fmt.Sprintf("gcp service account key is invalid: %s", err), hint)
+		}
+
+		_, err = d.gcpClientProvider.Client().GetProject()
+		if err != nil {
+			return check.fail(fmt.Sprintf("failed to get project %s: %s", state.GCP.ProjectID, err),
+				hint+", and make sure the service account has access to the project")
+		}
+
+		return check.pass(fmt.Sprintf("gcp credentials can access project %s", state.GCP.ProjectID))
+	}
+
+	return check.fail(fmt.Sprintf("%q is an invalid iaas type, supported values are: [gcp, aws]", state.IAAS),
+		fmt.Sprintf("restore %s from a backup", storage.StateFileName))
+}
+
+func (c DoctorCheck) pass(message string) DoctorCheck {
+	c.Status = DoctorCheckPassed
+	c.Message = message
+	return c
+}
+
+func (c DoctorCheck) skip(message string) DoctorCheck {
+	c.Status = DoctorCheckSkipped
+	c.Message = message
+	return c
+}
+
+func (c DoctorCheck) fail(message, hint string) DoctorCheck {
+	c.Status = DoctorCheckFailed
+	c.Message = message
+	c.Hint = hint
+	return c
+}
+
+func formatDoctorReport(report DoctorReport) string {
+	var lines []string
+	for _, check := range report.Checks {
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", check.Status, check.Name, check.Message))
+		if check.Hint != "" {
+			lines = append(lines, fmt.Sprintf("       hint: %s", check.Hint))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
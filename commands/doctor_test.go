@@ -0,0 +1,290 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Doctor", func() {
+	var (
+		logger                    *fakes.Logger
+		outputWriter              *fakes.OutputWriter
+		boshManager               *fakes.BOSHManager
+		terraformManager          *fakes.TerraformManager
+		credentialValidator       *fakes.CredentialValidator
+		availabilityZoneRetriever *fakes.AvailabilityZoneRetriever
+		gcpClientProvider         *fakes.GCPClientProvider
+		gcpClient                 *fakes.GCPClient
+
+		stateDir string
+		state    storage.State
+		doctor   commands.Doctor
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+
+		boshManager = &fakes.BOSHManager{}
+		boshManager.VersionCall.Returns.Version = "2.0.16"
+
+		terraformManager = &fakes.TerraformManager{}
+		terraformManager.VersionCall.Returns.Version = "0.9.5"
+
+		credentialValidator = &fakes.CredentialValidator{}
+		availabilityZoneRetriever = &fakes.AvailabilityZoneRetriever{}
+		availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"us-east-1a", "us-east-1b"}
+
+		gcpClient = &fakes.GCPClient{}
+		gcpClientProvider = &fakes.GCPClientProvider{}
+		gcpClientProvider.ClientCall.Returns.Client = gcpClient
+
+		var err error
+		stateDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(stateDir, "bbl-state.json"), []byte(`{"version":3,"iaas":"aws"}`), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		state = storage.State{
+			Version: 3,
+			IAAS:    "aws",
+			AWS: storage.AWS{
+				Region: "us-east-1",
+			},
+		}
+
+		doctor = commands.NewDoctor(logger, outputWriter, boshManager, terraformManager, credentialValidator,
+			availabilityZoneRetriever, gcpClientProvider, stateDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
+	})
+
+	findCheck := func(report commands.DoctorReport, name string) commands.DoctorCheck {
+		for _, check := range report.Checks {
+			if check.Name == name {
+				return check
+			}
+		}
+		Fail("no check named " + name)
+		return commands.DoctorCheck{}
+	}
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when unknown flags are provided", func() {
+			err := doctor.CheckFastFails([]string{"--some-flag"}, state)
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints a checklist and succeeds when every check passes", func() {
+			err := doctor.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+				"[pass] bosh cli: bosh cli 2.0.16 found\n" +
+					"[pass] terraform: terraform 0.9.5 found\n" +
+					"[pass] state directory: " + stateDir + " is writable\n" +
+					"[pass] bbl-state.json: bbl-state.json is valid\n" +
+					"[pass] iaas credentials: aws credentials can see 2 availability zones in us-east-1",
+			}))
+		})
+
+		It("prints remediation hints and returns an error when a check fails", func() {
+			terraformManager.VersionCall.Returns.Error = errors.New("executable file not found in $PATH")
+
+			err := doctor.Execute(context.Background(), []string{}, state)
+			Expect(err).To(Equal(commands.DoctorChecksFailed))
+
+			Expect(logger.PrintlnCall.Messages[0]).To(ContainSubstring("[fail] terraform: terraform could not be run: executable file not found in $PATH\n" +
+				"       hint: install terraform v0.8.5 or later and make sure it is on your PATH\n"))
+		})
+
+		Context("when the output format is not text", func() {
+			It("writes the report to the output writer", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+
+				err := doctor.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				report := outputWriter.WriteCall.Receives.Value.(commands.DoctorReport)
+				Expect(report.Passed).To(BeTrue())
+				Expect(report.Checks).To(HaveLen(5))
+			})
+
+			It("returns an error when the report cannot be written", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+				outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+
+				err := doctor.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to write"))
+			})
+		})
+	})
+
+	Describe("Report", func() {
+		Describe("bosh cli", func() {
+			It("passes for a development build of the bosh cli", func() {
+				boshManager.VersionCall.Returns.Error = bosh.NewBOSHVersionError(errors.New("BOSH version could not be parsed"))
+
+				check := findCheck(doctor.Report(state), "bosh cli")
+				Expect(check.Status).To(Equal("pass"))
+			})
+
+			It("fails when the bosh cli cannot be run", func() {
+				boshManager.VersionCall.Returns.Error = errors.New("executable file not found in $PATH")
+
+				check := findCheck(doctor.Report(state), "bosh cli")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(Equal("bosh cli could not be run: executable file not found in $PATH"))
+				Expect(check.Hint).To(ContainSubstring("install the bosh cli v2.0.0 or later"))
+			})
+
+			It("fails when the bosh cli is too old", func() {
+				boshManager.VersionCall.Returns.Version = "1.9.0"
+
+				check := findCheck(doctor.Report(state), "bosh cli")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(Equal("bosh cli 1.9.0 is incompatible: BOSH version must be at least v2.0.0"))
+			})
+		})
+
+		Describe("terraform", func() {
+			It("fails when the terraform version is incompatible", func() {
+				terraformManager.VersionCall.Returns.Version = "0.9.0"
+				terraformManager.ValidateVersionCall.Returns.Error = errors.New("Version 0.9.0 of terraform is incompatible with bbl, please try a later version.")
+
+				check := findCheck(doctor.Report(state), "terraform")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(Equal("terraform 0.9.0 is incompatible: Version 0.9.0 of terraform is incompatible with bbl, please try a later version."))
+			})
+		})
+
+		Describe("state directory", func() {
+			It("fails when the state directory is not writable", func() {
+				doctor = commands.NewDoctor(logger, outputWriter, boshManager, terraformManager, credentialValidator,
+					availabilityZoneRetriever, gcpClientProvider, filepath.Join(stateDir, "missing"))
+
+				check := findCheck(doctor.Report(state), "state directory")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(ContainSubstring("is not writable"))
+				Expect(check.Hint).To(ContainSubstring("--state-dir"))
+			})
+
+			It("does not leave any files behind", func() {
+				doctor.Report(state)
+
+				files, err := ioutil.ReadDir(stateDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(HaveLen(1))
+				Expect(files[0].Name()).To(Equal("bbl-state.json"))
+			})
+		})
+
+		Describe("bbl-state.json", func() {
+			It("is skipped when there is no state yet", func() {
+				err := os.Remove(filepath.Join(stateDir, "bbl-state.json"))
+				Expect(err).NotTo(HaveOccurred())
+
+				report := doctor.Report(storage.State{})
+				Expect(report.Passed).To(BeTrue())
+				Expect(findCheck(report, "bbl-state.json").Status).To(Equal("skip"))
+				Expect(findCheck(report, "iaas credentials").Status).To(Equal("skip"))
+			})
+
+			It("fails and skips the credentials when the state cannot be parsed", func() {
+				err := ioutil.WriteFile(filepath.Join(stateDir, "bbl-state.json"), []byte("%%%"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				report := doctor.Report(storage.State{})
+				Expect(report.Passed).To(BeFalse())
+
+				check := findCheck(report, "bbl-state.json")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(ContainSubstring("bbl-state.json could not be read: invalid character"))
+
+				Expect(findCheck(report, "iaas credentials").Status).To(Equal("skip"))
+				Expect(credentialValidator.ValidateCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Describe("iaas credentials", func() {
+			It("fails when the credentials are incomplete", func() {
+				credentialValidator.ValidateCall.Returns.Error = errors.New("AWS secret access key must be provided")
+
+				check := findCheck(doctor.Report(state), "iaas credentials")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(Equal("AWS secret access key must be provided"))
+				Expect(check.Hint).To(ContainSubstring("BBL_AWS_* environment variables"))
+			})
+
+			It("fails when aws rejects the credentials", func() {
+				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("AuthFailure")
+
+				check := findCheck(doctor.Report(state), "iaas credentials")
+				Expect(check.Status).To(Equal("fail"))
+				Expect(check.Message).To(Equal("failed to describe availability zones in us-east-1: AuthFailure"))
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("us-east-1"))
+			})
+
+			Context("when the iaas is gcp", func() {
+				BeforeEach(func() {
+					state = storage.State{
+						Version: 3,
+						IAAS:    "gcp",
+						GCP: storage.GCP{
+							ServiceAccountKey: "some-service-account-key",
+							ProjectID:         "some-project-id",
+							Zone:              "some-zone",
+						},
+					}
+				})
+
+				It("gets the project with the configured credentials", func() {
+					check := findCheck(doctor.Report(state), "iaas credentials")
+					Expect(check.Status).To(Equal("pass"))
+					Expect(check.Message).To(Equal("gcp credentials can access project some-project-id"))
+
+					Expect(gcpClientProvider.SetConfigCall.Receives.ServiceAccountKey).To(Equal("some-service-account-key"))
+					Expect(gcpClientProvider.SetConfigCall.Receives.ProjectID).To(Equal("some-project-id"))
+					Expect(gcpClientProvider.SetConfigCall.Receives.Zone).To(Equal("some-zone"))
+					Expect(gcpClient.GetProjectCall.CallCount).To(Equal(1))
+				})
+
+				It("fails when the service account key is invalid", func() {
+					gcpClientProvider.SetConfigCall.Returns.Error = errors.New("invalid character")
+
+					check := findCheck(doctor.Report(state), "iaas credentials")
+					Expect(check.Status).To(Equal("fail"))
+					Expect(check.Message).To(Equal("gcp service account key is invalid: invalid character"))
+					Expect(gcpClient.GetProjectCall.CallCount).To(Equal(0))
+				})
+
+				It("fails when the project cannot be retrieved", func() {
+					gcpClient.GetProjectCall.Returns.Error = errors.New("permission denied")
+
+					check := findCheck(doctor.Report(state), "iaas credentials")
+					Expect(check.Status).To(Equal("fail"))
+					Expect(check.Message).To(Equal("failed to get project some-project-id: permission denied"))
+					Expect(check.Hint).To(ContainSubstring("BBL_GCP_* environment variables"))
+				})
+			})
+		})
+	})
+})
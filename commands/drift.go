@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const DriftCommand = "drift"
+
+var DriftDetected error = errors.New("the infrastructure has drifted from the bbl state, run bbl up to bring it back in line")
+
+type Drift struct {
+	logger                    logger
+	outputWriter              outputWriter
+	stateValidator            stateValidator
+	terraformDriftDetector    terraformDriftDetector
+	stackDriftDetector        stackDriftDetector
+	availabilityZoneRetriever availabilityZoneRetriever
+	certificateDescriber      certificateDescriber
+}
+
+type terraformDriftDetector interface {
+	Drift(context.Context, storage.State) ([]drift.Resource, error)
+}
+
+type stackDriftDetector interface {
+	Drift(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) ([]drift.Resource, error)
+}
+
+type DriftReport struct {
+	Drifted   bool             `json:"drifted" yaml:"drifted"`
+	Resources []drift.Resource `json:"resources" yaml:"resources"`
+}
+
+func NewDrift(logger logger, outputWriter outputWriter, stateValidator stateValidator,
+	terraformDriftDetector terraformDriftDetector, stackDriftDetector stackDriftDetector,
+	availabilityZoneRetriever availabilityZoneRetriever, certificateDescriber certificateDescriber) Drift {
+	return Drift{
+		logger:                    logger,
+		outputWriter:              outputWriter,
+		stateValidator:            stateValidator,
+		terraformDriftDetector:    terraformDriftDetector,
+		stackDriftDetector:        stackDriftDetector,
+		availabilityZoneRetriever: availabilityZoneRetriever,
+		certificateDescriber:      certificateDescriber,
+	}
+}
+
+func (d Drift) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := flags.New("drift").Parse(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = d.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.TFState == "" && state.Stack.Name == "" {
+		return errors.New("no infrastructure has been created for this environment, run bbl up first")
+	}
+
+	return nil
+}
+
+func (d Drift) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	var (
+		resources []drift.Resource
+		err       error
+	)
+	if state.TFState != "" {
+		resources, err = d.terraformDriftDetector.Drift(ctx, state)
+	} else {
+		resources, err = d.stackDrift(state)
+	}
+	if err != nil {
+		return err
+	}
+
+	report := DriftReport{
+		Drifted:   len(resources) > 0,
+		Resources: resources,
+	}
+	if report.Resources == nil {
+		report.Resources = []drift.Resource{}
+	}
+
+	if d.outputWriter.IsText() {
+		d.logger.Println(formatDriftReport(report))
+	} else {
+		err = d.outputWriter.Write(report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if report.Drifted {
+		return DriftDetected
+	}
+
+	return nil
+}
+
+func (d Drift) stackDrift(state storage.State) ([]drift.Resource, error) {
+	availabilityZones, err := d.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificateARN string
+	if lbExists(state.Stack.LBType) {
+		certificate, err := d.certificateDescriber.Describe(state.Stack.CertificateName)
+		if err != nil {
+			return nil, err
+		}
+		certificateARN = certificate.ARN
+	}
+
+	return d.stackDriftDetector.Drift(state.KeyPair.Name, availabilityZones, state.Stack.Name, state.Stack.BOSHAZ,
+		state.Stack.LBType, certificateARN, state.EnvID)
+}
+
+func formatDriftReport(report DriftReport) string {
+	if !report.Drifted {
+		return "no drift detected"
+	}
+
+	var lines []string
+	for _, resource := range report.Resources {
+		lines = append(lines, fmt.Sprintf("%s: %s", resource.Name, resource.Status))
+
+		for _, attribute := range resource.Attributes {
+			line := fmt.Sprintf("  %s: %s", attribute.Name, attribute.Status)
+			switch attribute.Status {
+			case drift.Changed:
+				line = fmt.Sprintf("%s, expected %q, actual %q", line, attribute.Expected, attribute.Actual)
+			case drift.Missing:
+				if attribute.Expected != "" {
+					line = fmt.Sprintf("%s, expected %q", line, attribute.Expected)
+				}
+			case drift.Extra:
+				if attribute.Actual != "" {
+					line = fmt.Sprintf("%s, actual %q", line, attribute.Actual)
+				}
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,217 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drift", func() {
+	var (
+		logger                    *fakes.Logger
+		outputWriter              *fakes.OutputWriter
+		stateValidator            *fakes.StateValidator
+		terraformManager          *fakes.TerraformManager
+		infrastructureManager     *fakes.InfrastructureManager
+		availabilityZoneRetriever *fakes.AvailabilityZoneRetriever
+		certificateDescriber      *fakes.CertificateDescriber
+
+		command commands.Drift
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		infrastructureManager = &fakes.InfrastructureManager{}
+		availabilityZoneRetriever = &fakes.AvailabilityZoneRetriever{}
+		certificateDescriber = &fakes.CertificateDescriber{}
+
+		command = commands.NewDrift(logger, outputWriter, stateValidator, terraformManager, infrastructureManager,
+			availabilityZoneRetriever, certificateDescriber)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state is invalid", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
+
+			err := command.CheckFastFails([]string{}, storage.State{TFState: "some-tf-state"})
+			Expect(err).To(MatchError("state validator failed"))
+		})
+
+		It("returns an error when no infrastructure has been created", func() {
+			err := command.CheckFastFails([]string{}, storage.State{IAAS: "gcp"})
+			Expect(err).To(MatchError("no infrastructure has been created for this environment, run bbl up first"))
+		})
+
+		It("accepts environments created with cloudformation", func() {
+			err := command.CheckFastFails([]string{}, storage.State{
+				IAAS:  "aws",
+				Stack: storage.Stack{Name: "some-stack-name"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when unknown flags are provided", func() {
+			err := command.CheckFastFails([]string{"--some-flag"}, storage.State{TFState: "some-tf-state"})
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
+	})
+
+	Describe("Execute", func() {
+		Context("when the environment was created with terraform", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS:    "gcp",
+					TFState: "some-tf-state",
+				}
+			})
+
+			It("prints that there is no drift", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.DriftCall.Receives.BBLState).To(Equal(state))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"no drift detected"}))
+			})
+
+			It("prints the drifted resources and returns an error", func() {
+				terraformManager.DriftCall.Returns.Resources = []drift.Resource{
+					{
+						Name:   "google_compute_firewall.bosh-open",
+						Status: drift.Changed,
+						Attributes: []drift.Attribute{
+							{Name: "source_ranges.#", Status: drift.Changed, Expected: "1", Actual: "2"},
+							{Name: "target_tags.1234", Status: drift.Missing, Expected: "some-env-id-bosh-open"},
+							{Name: "target_tags.5678", Status: drift.Extra, Actual: "some-tag"},
+						},
+					},
+					{
+						Name:   "google_compute_address.bosh-external-ip",
+						Status: drift.Missing,
+					},
+				}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(Equal(commands.DriftDetected))
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`google_compute_firewall.bosh-open: changed
+  source_ranges.#: changed, expected "1", actual "2"
+  target_tags.1234: missing, expected "some-env-id-bosh-open"
+  target_tags.5678: extra, actual "some-tag"
+google_compute_address.bosh-external-ip: missing`}))
+			})
+
+			It("writes the report to the output writer when the output format is not text", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+				terraformManager.DriftCall.Returns.Resources = []drift.Resource{
+					{Name: "google_compute_address.bosh-external-ip", Status: drift.Missing},
+				}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(Equal(commands.DriftDetected))
+
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(commands.DriftReport{
+					Drifted:   true,
+					Resources: terraformManager.DriftCall.Returns.Resources,
+				}))
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when terraform fails to plan", func() {
+				terraformManager.DriftCall.Returns.Error = errors.New("failed to plan")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to plan"))
+			})
+		})
+
+		Context("when the environment was created with cloudformation", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS:  "aws",
+					EnvID: "some-env-id",
+					AWS: storage.AWS{
+						Region: "some-region",
+					},
+					KeyPair: storage.KeyPair{
+						Name: "some-keypair-name",
+					},
+					Stack: storage.Stack{
+						Name:            "some-stack-name",
+						BOSHAZ:          "some-bosh-az",
+						LBType:          "cf",
+						CertificateName: "some-certificate-name",
+					},
+				}
+				availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"some-az-1", "some-az-2"}
+				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{ARN: "some-certificate-arn"}
+			})
+
+			It("compares the stack with the template", func() {
+				infrastructureManager.DriftCall.Returns.Resources = []drift.Resource{{
+					Name:   "some-stack-name",
+					Status: drift.Changed,
+					Attributes: []drift.Attribute{
+						{Name: "outputs.CFRouterLoadBalancer", Status: drift.Missing},
+					},
+				}}
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(Equal(commands.DriftDetected))
+
+				Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("some-region"))
+				Expect(certificateDescriber.DescribeCall.Receives.CertificateName).To(Equal("some-certificate-name"))
+				Expect(infrastructureManager.DriftCall.Receives.KeyPairName).To(Equal("some-keypair-name"))
+				Expect(infrastructureManager.DriftCall.Receives.AZs).To(Equal([]string{"some-az-1", "some-az-2"}))
+				Expect(infrastructureManager.DriftCall.Receives.StackName).To(Equal("some-stack-name"))
+				Expect(infrastructureManager.DriftCall.Receives.BOSHAZ).To(Equal("some-bosh-az"))
+				Expect(infrastructureManager.DriftCall.Receives.LBType).To(Equal("cf"))
+				Expect(infrastructureManager.DriftCall.Receives.LBCertificateARN).To(Equal("some-certificate-arn"))
+				Expect(infrastructureManager.DriftCall.Receives.EnvID).To(Equal("some-env-id"))
+				Expect(terraformManager.DriftCall.CallCount).To(Equal(0))
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{`some-stack-name: changed
+  outputs.CFRouterLoadBalancer: missing`}))
+			})
+
+			It("does not describe a certificate when there is no load balancer", func() {
+				state.Stack.LBType = ""
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateDescriber.DescribeCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DriftCall.Receives.LBCertificateARN).To(Equal(""))
+			})
+
+			It("returns an error when the availability zones cannot be retrieved", func() {
+				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve azs")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to retrieve azs"))
+			})
+
+			It("returns an error when the stack cannot be compared", func() {
+				infrastructureManager.DriftCall.Returns.Error = errors.New("failed to describe stack")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to describe stack"))
+			})
+		})
+	})
+})
@@ -16,6 +16,10 @@ func fastFailBOSHVersion(boshManager boshManager) error {
 		return err
 	}
 
+	return validateBOSHVersion(version)
+}
+
+func validateBOSHVersion(version string) error {
 	currentVersion, err := semver.NewVersion(version)
 	if err != nil {
 		return err
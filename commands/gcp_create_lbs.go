@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 
@@ -16,29 +17,42 @@ type GCPCreateLBs struct {
 	stateStore           stateStore
 	logger               logger
 	environmentValidator environmentValidator
+	certificateValidator certificateValidator
+	certificateGenerator certificateGenerator
+	acmeManager          acmeManager
 }
 
 type GCPCreateLBsConfig struct {
-	LBType       string
-	CertPath     string
-	KeyPath      string
-	Domain       string
-	SkipIfExists bool
+	LBType           string
+	CertPath         string
+	KeyPath          string
+	Domain           string
+	SkipIfExists     bool
+	GenerateCert     bool
+	CACertPath       string
+	CAKeyPath        string
+	ACME             bool
+	ACMEDirectoryURL string
+	ACMEEmail        string
 }
 
 func NewGCPCreateLBs(terraformManager terraformManager,
 	cloudConfigManager cloudConfigManager,
-	stateStore stateStore, logger logger, environmentValidator environmentValidator) GCPCreateLBs {
+	stateStore stateStore, logger logger, environmentValidator environmentValidator,
+	certificateValidator certificateValidator, certificateGenerator certificateGenerator, acmeManager acmeManager) GCPCreateLBs {
 	return GCPCreateLBs{
 		terraformManager:     terraformManager,
 		cloudConfigManager:   cloudConfigManager,
 		stateStore:           stateStore,
 		logger:               logger,
 		environmentValidator: environmentValidator,
+		certificateValidator: certificateValidator,
+		certificateGenerator: certificateGenerator,
+		acmeManager:          acmeManager,
 	}
 }
 
-func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) error {
+func (c GCPCreateLBs) Execute(ctx context.Context, config GCPCreateLBsConfig, state storage.State) error {
 	err := c.terraformManager.ValidateVersion()
 	if err != nil {
 		return err
@@ -65,22 +79,36 @@ func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) er
 	if config.LBType == "cf" {
 		state.LB.Domain = config.Domain
 
-		cert, err = ioutil.ReadFile(config.CertPath)
-		if err != nil {
-			return err
-		}
+		if !config.GenerateCert && !config.ACME {
+			cert, err = ioutil.ReadFile(config.CertPath)
+			if err != nil {
+				return err
+			}
 
-		state.LB.Cert = string(cert)
+			state.LB.Cert = string(cert)
 
-		key, err = ioutil.ReadFile(config.KeyPath)
-		if err != nil {
-			return err
-		}
+			key, err = ioutil.ReadFile(config.KeyPath)
+			if err != nil {
+				return err
+			}
 
-		state.LB.Key = string(key)
+			state.LB.Key = string(key)
+			state.LB.CA = ""
+			state.LB.ACME = nil
+		}
 	}
 
-	state, err = c.terraformManager.Apply(state)
+	switch {
+	case config.ACME:
+		state, err = applyWithACMECertificate(ctx, c.certificateGenerator, c.acmeManager, c.terraformManager, c.stateStore,
+			storage.ACME{DirectoryURL: config.ACMEDirectoryURL, Email: config.ACMEEmail}, state)
+	case config.GenerateCert:
+		state.LB.ACME = nil
+		state, err = applyWithGeneratedCertificate(ctx, c.certificateGenerator, c.terraformManager, c.stateStore,
+			generatedCertificateConfig{caCertPath: config.CACertPath, caKeyPath: config.CAKeyPath}, state)
+	default:
+		state, err = c.terraformManager.Apply(ctx, state)
+	}
 	switch err.(type) {
 	case terraform.ManagerError:
 		taError := err.(terraform.ManagerError)
@@ -108,7 +136,7 @@ func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) er
 	}
 
 	if !state.NoDirector {
-		err = c.cloudConfigManager.Update(state)
+		err = c.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
@@ -117,7 +145,7 @@ func (c GCPCreateLBs) Execute(config GCPCreateLBsConfig, state storage.State) er
 	return nil
 }
 
-func (GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.State) error {
+func (c GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.State) error {
 	if config.LBType == "" {
 		return fmt.Errorf("--type is a required flag")
 	}
@@ -126,7 +154,7 @@ func (GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.Stat
 		return fmt.Errorf("%q is not a valid lb type, valid lb types are: concourse, cf", config.LBType)
 	}
 
-	if config.LBType == "cf" {
+	if config.LBType == "cf" && !config.GenerateCert && !config.ACME {
 		errs := multierror.NewMultiError("create-lbs")
 		if err := validateCertOrKeyFlag("cert", config.CertPath); err != nil {
 			errs.Add(err)
@@ -138,6 +166,11 @@ func (GCPCreateLBs) checkFastFails(config GCPCreateLBsConfig, state storage.Stat
 		if errs.Length() > 0 {
 			return errs
 		}
+
+		err := c.certificateValidator.Validate(CreateLBsCommand, config.CertPath, config.KeyPath, "", config.Domain)
+		if err != nil {
+			return err
+		}
 	}
 
 	if state.IAAS != "gcp" {
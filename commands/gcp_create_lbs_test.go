@@ -1,12 +1,14 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
 	"github.com/cloudfoundry/multierror"
@@ -23,6 +25,9 @@ var _ = Describe("GCPCreateLBs", func() {
 		logger                 *fakes.Logger
 		terraformExecutorError *fakes.TerraformExecutorError
 		environmentValidator   *fakes.EnvironmentValidator
+		certificateValidator   *fakes.CertificateValidator
+		certificateGenerator   *fakes.CertificateGenerator
+		acmeManager            *fakes.ACMEManager
 
 		command     commands.GCPCreateLBs
 		certPath    string
@@ -38,8 +43,12 @@ var _ = Describe("GCPCreateLBs", func() {
 		logger = &fakes.Logger{}
 		terraformExecutorError = &fakes.TerraformExecutorError{}
 		environmentValidator = &fakes.EnvironmentValidator{}
+		certificateValidator = &fakes.CertificateValidator{}
+		certificateGenerator = &fakes.CertificateGenerator{}
+		acmeManager = &fakes.ACMEManager{}
 
-		command = commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, environmentValidator)
+		command = commands.NewGCPCreateLBs(terraformManager, cloudConfigManager, stateStore, logger, environmentValidator,
+			certificateValidator, certificateGenerator, acmeManager)
 
 		tempCertFile, err := ioutil.TempFile("", "cert")
 		Expect(err).NotTo(HaveOccurred())
@@ -65,7 +74,7 @@ var _ = Describe("GCPCreateLBs", func() {
 	Describe("Execute", func() {
 		Context("when lb type is cf", func() {
 			It("calls terraform manager apply", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType:   "cf",
 					CertPath: certPath,
 					KeyPath:  keyPath,
@@ -85,11 +94,111 @@ var _ = Describe("GCPCreateLBs", func() {
 					},
 				}))
 			})
+
+			It("validates the certificate and key", func() {
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
+					LBType:   "cf",
+					CertPath: certPath,
+					KeyPath:  keyPath,
+					Domain:   "some-domain",
+				}, storage.State{
+					IAAS: "gcp",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ValidateCall.CallCount).To(Equal(1))
+				Expect(certificateValidator.ValidateCall.Receives.Command).To(Equal("create-lbs"))
+				Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal(certPath))
+				Expect(certificateValidator.ValidateCall.Receives.KeyPath).To(Equal(keyPath))
+				Expect(certificateValidator.ValidateCall.Receives.ChainPath).To(Equal(""))
+				Expect(certificateValidator.ValidateCall.Receives.Domain).To(Equal("some-domain"))
+			})
+
+			Context("when --generate-cert is provided", func() {
+				BeforeEach(func() {
+					terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+						return state, nil
+					}
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+						"router_lb_ip": "10.0.0.1",
+					}
+
+					certificateGenerator.GenerateCACall.Returns.CAData = ssl.CAData{CA: []byte("some-ca")}
+					certificateGenerator.GenerateFromCACall.Stub = func(commonName string) (ssl.KeyPair, error) {
+						return ssl.KeyPair{
+							Certificate: []byte("cert-for-" + commonName),
+							PrivateKey:  []byte("key-for-" + commonName),
+						}, nil
+					}
+				})
+
+				It("applies a certificate for the router lb ip without reading or validating cert files", func() {
+					err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
+						LBType:       "cf",
+						GenerateCert: true,
+					}, storage.State{
+						IAAS:  "gcp",
+						EnvID: "some-env-id",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(2))
+					Expect(certificateGenerator.GenerateFromCACall.Receives[1].CommonName).To(Equal("10.0.0.1"))
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LB).To(Equal(storage.LB{
+						Type: "cf",
+						Cert: "cert-for-10.0.0.1",
+						Key:  "key-for-10.0.0.1",
+						CA:   "some-ca",
+					}))
+				})
+			})
+
+			Context("when --acme is provided", func() {
+				BeforeEach(func() {
+					terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+						return state, nil
+					}
+					certificateGenerator.GenerateFromCACall.Returns.KeyPair = ssl.KeyPair{
+						Certificate: []byte("some-placeholder-cert"),
+						PrivateKey:  []byte("some-placeholder-key"),
+					}
+					acmeManager.IssueCall.Returns.State = storage.State{
+						IAAS: "gcp",
+						LB: storage.LB{
+							Type:   "cf",
+							Cert:   "some-acme-cert",
+							Domain: "example.com",
+							ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+						},
+					}
+				})
+
+				It("issues the certificate from the acme directory without reading or validating cert files", func() {
+					err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
+						LBType:           "cf",
+						Domain:           "example.com",
+						ACME:             true,
+						ACMEDirectoryURL: "https://localhost:14000/dir",
+					}, storage.State{
+						IAAS:  "gcp",
+						EnvID: "some-env-id",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+					Expect(acmeManager.IssueCall.Receives.State.LB.Cert).To(Equal("some-placeholder-cert"))
+					Expect(acmeManager.IssueCall.Receives.State.LB.ACME).To(Equal(&storage.ACME{DirectoryURL: "https://localhost:14000/dir"}))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(2))
+					Expect(terraformManager.ApplyCall.Receives.BBLState.LB.Cert).To(Equal("some-acme-cert"))
+					Expect(stateStore.SetCall.Receives[1].State.LB.Cert).To(Equal("some-acme-cert"))
+				})
+			})
 		})
 
 		Context("when lb type is concourse", func() {
 			It("calls terraform manager apply", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS: "gcp",
@@ -119,7 +228,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				TFState: "some-new-tfstate",
 			}
 
-			err := command.Execute(commands.GCPCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 				LBType: "concourse",
 			}, storage.State{
 				IAAS:    "gcp",
@@ -160,7 +269,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				},
 			}
 
-			err := command.Execute(commands.GCPCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 				LBType: "concourse",
 			}, storage.State{
 				IAAS: "gcp",
@@ -187,7 +296,7 @@ var _ = Describe("GCPCreateLBs", func() {
 		})
 
 		It("no-ops if SkipIfExists is supplied and the LBType does not change", func() {
-			err := command.Execute(commands.GCPCreateLBsConfig{
+			err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 				LBType:       "concourse",
 				SkipIfExists: true,
 			}, storage.State{
@@ -205,7 +314,7 @@ var _ = Describe("GCPCreateLBs", func() {
 
 		Context("when there is no BOSH director", func() {
 			It("creates the LBs", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS:       "gcp",
@@ -224,7 +333,7 @@ var _ = Describe("GCPCreateLBs", func() {
 			It("does not call the CloudConfigManager", func() {
 				terraformManager.ApplyCall.Returns.BBLState.NoDirector = true
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS:       "gcp",
@@ -258,7 +367,7 @@ var _ = Describe("GCPCreateLBs", func() {
 					expectedErrors.Add(errors.New("provided cert file is empty"))
 					expectedErrors.Add(errors.New("provided key file is empty"))
 
-					err := command.Execute(commands.GCPCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 						LBType:   "cf",
 						CertPath: certPath,
 						KeyPath:  keyPath,
@@ -273,7 +382,7 @@ var _ = Describe("GCPCreateLBs", func() {
 			It("returns an error if terraform manager version validator fails", func() {
 				terraformManager.ValidateVersionCall.Returns.Error = errors.New("cannot validate version")
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS: "gcp",
@@ -283,14 +392,14 @@ var _ = Describe("GCPCreateLBs", func() {
 			})
 
 			It("returns a helpful error when no lb type is provided", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "",
 				}, storage.State{IAAS: "gcp"})
 				Expect(err).To(MatchError("--type is a required flag"))
 			})
 
 			It("returns an error when the lb type is not concourse or cf", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "some-fake-lb",
 				}, storage.State{IAAS: "gcp"})
 				Expect(err).To(MatchError(`"some-fake-lb" is not a valid lb type, valid lb types are: concourse, cf`))
@@ -302,24 +411,36 @@ var _ = Describe("GCPCreateLBs", func() {
 					expectedErrors.Add(errors.New("--cert is required"))
 					expectedErrors.Add(errors.New("--key is required"))
 
-					err := command.Execute(commands.GCPCreateLBsConfig{
+					err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 						LBType: "cf",
 					}, storage.State{IAAS: "gcp"})
 					Expect(err).To(MatchError(expectedErrors))
 				})
+
+				It("returns an error when the certificate validator fails", func() {
+					certificateValidator.ValidateCall.Returns.Error = errors.New("failed to validate")
+
+					err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
+						LBType:   "cf",
+						CertPath: certPath,
+						KeyPath:  keyPath,
+					}, storage.State{IAAS: "gcp"})
+					Expect(err).To(MatchError("failed to validate"))
+					Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				})
 			})
 
 			It("returns an error when environment validator fails", func() {
 				environmentValidator.ValidateCall.Returns.Error = errors.New("failed to validate environment")
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{IAAS: "gcp"})
 				Expect(err).To(MatchError("failed to validate environment"))
 			})
 
 			It("returns an error when the iaas type is not gcp", func() {
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS: "aws",
@@ -331,7 +452,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				expectedErrors := multierror.NewMultiError("create-lbs")
 				expectedErrors.Add(errors.New("open some/fake/path: no such file or directory"))
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType:   "cf",
 					CertPath: "some/fake/path",
 					KeyPath:  keyPath,
@@ -343,7 +464,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				expectedErrors := multierror.NewMultiError("create-lbs")
 				expectedErrors.Add(errors.New("open some/fake/path: no such file or directory"))
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType:   "cf",
 					CertPath: certPath,
 					KeyPath:  "some/fake/path",
@@ -359,7 +480,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				}, terraformExecutorError)
 				terraformManager.ApplyCall.Returns.Error = expectedError
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{
 					IAAS:    "gcp",
@@ -379,7 +500,7 @@ var _ = Describe("GCPCreateLBs", func() {
 
 			It("returns an error if terraform manager apply fails with non terraform manager apply error", func() {
 				terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType:   "cf",
 					CertPath: certPath,
 					KeyPath:  keyPath,
@@ -402,7 +523,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				}, terraformExecutorError)
 				terraformManager.ApplyCall.Returns.Error = expectedError
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{IAAS: "gcp"})
 
@@ -423,7 +544,7 @@ var _ = Describe("GCPCreateLBs", func() {
 				terraformManager.ApplyCall.Returns.Error = expectedError
 
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("state failed to be set")}}
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{IAAS: "gcp"})
 
@@ -440,7 +561,7 @@ var _ = Describe("GCPCreateLBs", func() {
 
 			It("returns an error when the state store fails to save the state after applying terraform", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{fakes.SetCallReturn{Error: errors.New("failed to save state")}}
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{IAAS: "gcp"})
 
@@ -450,7 +571,7 @@ var _ = Describe("GCPCreateLBs", func() {
 			It("returns an error when the cloud config fails to be updated", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("failed to update cloud config")
 
-				err := command.Execute(commands.GCPCreateLBsConfig{
+				err := command.Execute(context.Background(), commands.GCPCreateLBsConfig{
 					LBType: "concourse",
 				}, storage.State{IAAS: "gcp"})
 				Expect(err).To(MatchError("failed to update cloud config"))
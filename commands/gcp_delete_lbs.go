@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"github.com/cloudfoundry/bosh-bootloader/helpers"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -21,7 +22,7 @@ func NewGCPDeleteLBs(stateStore stateStore,
 	}
 }
 
-func (g GCPDeleteLBs) Execute(state storage.State) error {
+func (g GCPDeleteLBs) Execute(ctx context.Context, state storage.State) error {
 	err := g.terraformManager.ValidateVersion()
 	if err != nil {
 		return err
@@ -29,13 +30,13 @@ func (g GCPDeleteLBs) Execute(state storage.State) error {
 	state.LB.Type = ""
 
 	if !state.NoDirector {
-		err = g.cloudConfigManager.Update(state)
+		err = g.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
 	}
 
-	state, err = g.terraformManager.Apply(state)
+	state, err = g.terraformManager.Apply(ctx, state)
 	switch err.(type) {
 	case terraform.ManagerError:
 		taErr := err.(terraform.ManagerError)
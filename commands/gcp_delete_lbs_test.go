@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 
@@ -42,7 +43,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("when bbl has a bosh director", func() {
 			It("updates the cloud config", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					BOSH: storage.BOSH{
 						DirectorUsername: "some-director-username",
@@ -74,7 +75,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("when bbl does not have a bosh director", func() {
 			It("does not update the cloud config", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
 					GCP: storage.GCP{
@@ -98,7 +99,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			region := "some-region"
 			tfState := "some-tf-state"
 
-			err := command.Execute(storage.State{
+			err := command.Execute(context.Background(), storage.State{
 				EnvID: envID,
 				GCP: storage.GCP{
 					ServiceAccountKey: credentials,
@@ -128,7 +129,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 
 		Context("state manipulation", func() {
 			It("removes the lb from the state", func() {
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					LB: storage.LB{
 						Type: "concourse",
@@ -145,7 +146,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 					IAAS: "gcp",
 				}
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					LB: storage.LB{
 						Type: "concourse",
@@ -167,7 +168,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 				}, terraformExecutorError)
 				terraformManager.ApplyCall.Returns.Error = expectedError
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -186,7 +187,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("fast fails if the terraform version is invalid", func() {
 				terraformManager.ValidateVersionCall.Returns.Error = errors.New("invalid")
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -198,7 +199,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("returns an error if applier fails with non terraform apply error", func() {
 				terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -225,7 +226,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 						{errors.New("failed to set state")},
 					}
 
-					err := command.Execute(storage.State{
+					err := command.Execute(context.Background(), storage.State{
 						IAAS: "gcp",
 						Stack: storage.Stack{
 							LBType: "concourse",
@@ -251,7 +252,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 						{errors.New("failed to set state")},
 					}
 
-					err := command.Execute(storage.State{
+					err := command.Execute(context.Background(), storage.State{
 						IAAS: "gcp",
 						Stack: storage.Stack{
 							LBType: "concourse",
@@ -264,7 +265,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 			It("returns an error when updating cloud config fails", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("updating cloud config failed")
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -278,7 +279,7 @@ var _ = Describe("GCPDeleteLBs", func() {
 					{errors.New("failed to set state")},
 				}
 
-				err := command.Execute(storage.State{
+				err := command.Execute(context.Background(), storage.State{
 					IAAS: "gcp",
 					Stack: storage.Stack{
 						LBType: "concourse",
@@ -9,14 +9,28 @@ import (
 )
 
 type GCPLBs struct {
-	terraformManager terraformManager
-	logger           logger
+	terraformManager     terraformManager
+	logger               logger
+	outputWriter         outputWriter
+	certificateInventory certificateInventory
 }
 
-func NewGCPLBs(terraformManager terraformManager, logger logger) GCPLBs {
+type GCPLBsOutput struct {
+	RouterLBIP             string   `json:"cf_router_lb,omitempty" yaml:"cf_router_lb,omitempty"`
+	SSHProxyLBIP           string   `json:"cf_ssh_proxy_lb,omitempty" yaml:"cf_ssh_proxy_lb,omitempty"`
+	TCPRouterLBIP          string   `json:"cf_tcp_router_lb,omitempty" yaml:"cf_tcp_router_lb,omitempty"`
+	WebSocketLBIP          string   `json:"cf_websocket_lb,omitempty" yaml:"cf_websocket_lb,omitempty"`
+	ConcourseLBIP          string   `json:"concourse_lb,omitempty" yaml:"concourse_lb,omitempty"`
+	SystemDomainDNSServers []string `json:"cf_system_domain_dns_servers,omitempty" yaml:"cf_system_domain_dns_servers,omitempty"`
+	CertificateExpires     string   `json:"certificate_expires,omitempty" yaml:"certificate_expires,omitempty"`
+}
+
+func NewGCPLBs(terraformManager terraformManager, logger logger, outputWriter outputWriter, certificateInventory certificateInventory) GCPLBs {
 	return GCPLBs{
-		terraformManager: terraformManager,
-		logger:           logger,
+		terraformManager:     terraformManager,
+		logger:               logger,
+		outputWriter:         outputWriter,
+		certificateInventory: certificateInventory,
 	}
 }
 
@@ -26,43 +40,67 @@ func (l GCPLBs) Execute(subcommandFlags []string, state storage.State) error {
 		return err
 	}
 
+	var lbOutput GCPLBsOutput
 	switch state.LB.Type {
 	case "cf":
-		if len(subcommandFlags) > 0 && subcommandFlags[0] == "--json" {
-			lbOutput, err := json.Marshal(struct {
-				RouterLBIP             string   `json:"cf_router_lb,omitempty"`
-				SSHProxyLBIP           string   `json:"cf_ssh_proxy_lb,omitempty"`
-				TCPRouterLBIP          string   `json:"cf_tcp_router_lb,omitempty"`
-				WebSocketLBIP          string   `json:"cf_websocket_lb,omitempty"`
-				SystemDomainDNSServers []string `json:"cf_system_domain_dns_servers,omitempty"`
-			}{
-				RouterLBIP:             terraformOutputs["router_lb_ip"].(string),
-				SSHProxyLBIP:           terraformOutputs["ssh_proxy_lb_ip"].(string),
-				TCPRouterLBIP:          terraformOutputs["tcp_router_lb_ip"].(string),
-				WebSocketLBIP:          terraformOutputs["ws_lb_ip"].(string),
-				SystemDomainDNSServers: terraformOutputs["system_domain_dns_servers"].([]string),
-			})
-			if err != nil {
-				// not tested
-				return err
-			}
-
-			l.logger.Println(string(lbOutput))
-		} else {
-			l.logger.Printf("CF Router LB: %s\n", terraformOutputs["router_lb_ip"])
-			l.logger.Printf("CF SSH Proxy LB: %s\n", terraformOutputs["ssh_proxy_lb_ip"])
-			l.logger.Printf("CF TCP Router LB: %s\n", terraformOutputs["tcp_router_lb_ip"])
-			l.logger.Printf("CF WebSocket LB: %s\n", terraformOutputs["ws_lb_ip"])
-
-			if dnsServers, ok := terraformOutputs["system_domain_dns_servers"]; ok {
-				l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(dnsServers.([]string), " "))
-			}
+		lbOutput = GCPLBsOutput{
+			RouterLBIP:    outputString(terraformOutputs, "router_lb_ip"),
+			SSHProxyLBIP:  outputString(terraformOutputs, "ssh_proxy_lb_ip"),
+			TCPRouterLBIP: outputString(terraformOutputs, "tcp_router_lb_ip"),
+			WebSocketLBIP: outputString(terraformOutputs, "ws_lb_ip"),
+		}
+		if dnsServers, ok := terraformOutputs["system_domain_dns_servers"].([]string); ok {
+			lbOutput.SystemDomainDNSServers = dnsServers
 		}
 	case "concourse":
-		l.logger.Printf("Concourse LB: %s\n", terraformOutputs["concourse_lb_ip"])
+		lbOutput = GCPLBsOutput{
+			ConcourseLBIP: outputString(terraformOutputs, "concourse_lb_ip"),
+		}
 	default:
 		return errors.New("no lbs found")
 	}
 
+	lbOutput.CertificateExpires, err = lbCertificateExpiry(l.certificateInventory, state)
+	if err != nil {
+		return err
+	}
+
+	config, err := parseLBsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.json {
+		contents, err := json.Marshal(lbOutput)
+		if err != nil {
+			// not tested
+			return err
+		}
+
+		l.logger.Println(string(contents))
+		return nil
+	}
+
+	if !l.outputWriter.IsText() {
+		return l.outputWriter.Write(lbOutput)
+	}
+
+	if state.LB.Type == "concourse" {
+		l.logger.Printf("Concourse LB: %s\n", lbOutput.ConcourseLBIP)
+	} else {
+		l.logger.Printf("CF Router LB: %s\n", lbOutput.RouterLBIP)
+		l.logger.Printf("CF SSH Proxy LB: %s\n", lbOutput.SSHProxyLBIP)
+		l.logger.Printf("CF TCP Router LB: %s\n", lbOutput.TCPRouterLBIP)
+		l.logger.Printf("CF WebSocket LB: %s\n", lbOutput.WebSocketLBIP)
+
+		if len(lbOutput.SystemDomainDNSServers) > 0 {
+			l.logger.Printf("CF System Domain DNS servers: %s\n", strings.Join(lbOutput.SystemDomainDNSServers, " "))
+		}
+	}
+
+	if lbOutput.CertificateExpires != "" {
+		l.logger.Printf("LB certificate expires: %s\n", lbOutput.CertificateExpires)
+	}
+
 	return nil
 }
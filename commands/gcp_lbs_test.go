@@ -2,7 +2,9 @@ package commands_test
 
 import (
 	"errors"
+	"time"
 
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -16,8 +18,10 @@ var _ = Describe("GCPLBs", func() {
 	var (
 		command commands.GCPLBs
 
-		terraformManager *fakes.TerraformManager
-		logger           *fakes.Logger
+		terraformManager     *fakes.TerraformManager
+		logger               *fakes.Logger
+		outputWriter         *fakes.OutputWriter
+		certificateInventory *fakes.CertificateInventory
 
 		incomingState storage.State
 	)
@@ -32,8 +36,12 @@ var _ = Describe("GCPLBs", func() {
 			"concourse_lb_ip":  "some-concourse-lb-ip",
 		}
 		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
 
-		command = commands.NewGCPLBs(terraformManager, logger)
+		certificateInventory = &fakes.CertificateInventory{}
+
+		command = commands.NewGCPLBs(terraformManager, logger, outputWriter, certificateInventory)
 	})
 
 	Describe("Execute", func() {
@@ -106,6 +114,51 @@ var _ = Describe("GCPLBs", func() {
 			})
 		})
 
+		Context("when the output format is not text", func() {
+			BeforeEach(func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+			})
+
+			It("writes the LB ips for lb type cf to the output writer", func() {
+				incomingState.LB = storage.LB{
+					Type: "cf",
+				}
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintfCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.CallCount).To(Equal(1))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(commands.GCPLBsOutput{
+					RouterLBIP:    "some-router-lb-ip",
+					SSHProxyLBIP:  "some-ssh-proxy-lb-ip",
+					TCPRouterLBIP: "some-tcp-router-lb-ip",
+					WebSocketLBIP: "some-ws-lb-ip",
+				}))
+			})
+
+			It("writes the LB ip for lb type concourse to the output writer", func() {
+				incomingState.LB = storage.LB{
+					Type: "concourse",
+				}
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(commands.GCPLBsOutput{
+					ConcourseLBIP: "some-concourse-lb-ip",
+				}))
+			})
+
+			It("returns an error when the output writer fails", func() {
+				incomingState.LB = storage.LB{
+					Type: "cf",
+				}
+				outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to write"))
+			})
+		})
+
 		It("prints LB ips for lb type concourse", func() {
 			incomingState.LB = storage.LB{
 				Type: "concourse",
@@ -119,7 +172,35 @@ var _ = Describe("GCPLBs", func() {
 			}))
 		})
 
+		It("prints when the LB certificate expires", func() {
+			incomingState.LB = storage.LB{
+				Type: "concourse",
+			}
+			certificateInventory.LoadBalancerCall.Returns.Found = true
+			certificateInventory.LoadBalancerCall.Returns.Certificate = certificates.Certificate{
+				Expires: time.Date(2017, time.July, 20, 0, 0, 0, 0, time.UTC),
+			}
+
+			err := command.Execute([]string{"--json"}, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+				"concourse_lb": "some-concourse-lb-ip",
+				"certificate_expires": "2017-07-20"
+			}`))
+		})
+
 		Context("failure cases", func() {
+			It("returns an error when the LB certificate cannot be read", func() {
+				incomingState.LB = storage.LB{
+					Type: "cf",
+				}
+				certificateInventory.LoadBalancerCall.Returns.Error = errors.New("failed to parse")
+
+				err := command.Execute([]string{}, incomingState)
+				Expect(err).To(MatchError("failed to parse"))
+			})
+
 			It("returns an error when terraform output provider fails", func() {
 				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to return terraform output")
 				err := command.Execute([]string{}, incomingState)
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type GCPRotateIAASCredentials struct {
+	serviceAccountKeyManager serviceAccountKeyManager
+	terraformManager         terraformManager
+	rotation                 directorKeyRotation
+}
+
+type serviceAccountKeyManager interface {
+	Create(email string) (string, error)
+	Delete(keyFile string) error
+}
+
+func NewGCPRotateIAASCredentials(serviceAccountKeyManager serviceAccountKeyManager, terraformManager terraformManager, boshManager boshManager,
+	stateStore stateStore, logger logger) GCPRotateIAASCredentials {
+	return GCPRotateIAASCredentials{
+		serviceAccountKeyManager: serviceAccountKeyManager,
+		terraformManager:         terraformManager,
+		rotation: directorKeyRotation{
+			terraformManager: terraformManager,
+			boshManager:      boshManager,
+			stateStore:       stateStore,
+			logger:           logger,
+		},
+	}
+}
+
+func (r GCPRotateIAASCredentials) Execute(ctx context.Context, state storage.State) error {
+	return r.rotation.rotate(ctx, gcpDirectorKey{
+		serviceAccountKeyManager: r.serviceAccountKeyManager,
+		terraformManager:         r.terraformManager,
+	}, state)
+}
+
+type gcpDirectorKey struct {
+	serviceAccountKeyManager serviceAccountKeyManager
+	terraformManager         terraformManager
+}
+
+func (k gcpDirectorKey) description() string {
+	return "key of the director service account"
+}
+
+// current returns the email of the director service account, which is only
+// known to terraform, along with the key of the director.
+func (k gcpDirectorKey) current(state storage.State) (string, string, bool, error) {
+	outputs, err := k.terraformManager.GetOutputs(state)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	email := outputString(outputs, "director_service_account_email")
+	if email == "" {
+		return "", "", false, errors.New("could not find the service account of the director")
+	}
+
+	if state.GCP.DirectorServiceAccountKey != "" {
+		return email, state.GCP.DirectorServiceAccountKey, false, nil
+	}
+
+	key := outputString(outputs, "director_service_account_key")
+	if key == "" {
+		return "", "", false, errors.New("could not find the key of the director service account")
+	}
+
+	return email, key, true, nil
+}
+
+func (k gcpDirectorKey) create(state storage.State, email string) (storage.State, error) {
+	key, err := k.serviceAccountKeyManager.Create(email)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	state.GCP.DirectorServiceAccountKey = key
+	return state, nil
+}
+
+func (k gcpDirectorKey) previous(state storage.State) string {
+	return state.GCP.PreviousDirectorServiceAccountKey
+}
+
+func (k gcpDirectorKey) setPrevious(state storage.State, key string) storage.State {
+	state.GCP.PreviousDirectorServiceAccountKey = key
+	return state
+}
+
+func (k gcpDirectorKey) delete(keyFile string) error {
+	return k.serviceAccountKeyManager.Delete(keyFile)
+}
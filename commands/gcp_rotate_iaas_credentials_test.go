@@ -0,0 +1,199 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GCPRotateIAASCredentials", func() {
+	var (
+		serviceAccountKeyManager *fakes.ServiceAccountKeyManager
+		terraformManager         *fakes.TerraformManager
+		boshManager              *fakes.BOSHManager
+		stateStore               *fakes.StateStore
+		logger                   *fakes.Logger
+
+		command commands.GCPRotateIAASCredentials
+
+		incomingState storage.State
+	)
+
+	BeforeEach(func() {
+		serviceAccountKeyManager = &fakes.ServiceAccountKeyManager{}
+		terraformManager = &fakes.TerraformManager{}
+		boshManager = &fakes.BOSHManager{}
+		stateStore = &fakes.StateStore{}
+		logger = &fakes.Logger{}
+
+		serviceAccountKeyManager.CreateCall.Returns.KeyFile = "some-new-key-file"
+		terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+			"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+			"director_service_account_key":   "some-terraform-key-file",
+		}
+		terraformManager.ApplyCall.Stub = func(state storage.State) (storage.State, error) {
+			state.TFState = "some-updated-tf-state"
+			return state, nil
+		}
+
+		incomingState = storage.State{
+			IAAS:    "gcp",
+			TFState: "some-tf-state",
+			GCP: storage.GCP{
+				ServiceAccountKey: "some-service-account-key",
+			},
+		}
+
+		command = commands.NewGCPRotateIAASCredentials(serviceAccountKeyManager, terraformManager, boshManager, stateStore, logger)
+	})
+
+	Context("when the director uses the key that terraform created", func() {
+		It("gives the director a new key and has terraform delete its key", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serviceAccountKeyManager.CreateCall.Receives.Email).To(Equal("some-director@some-project.iam.gserviceaccount.com"))
+			Expect(stateStore.SetCall.Receives[0].State.GCP.DirectorServiceAccountKey).To(Equal("some-new-key-file"))
+			Expect(stateStore.SetCall.Receives[0].State.GCP.PreviousDirectorServiceAccountKey).To(Equal("some-terraform-key-file"))
+			Expect(boshManager.CreateCall.Receives.State.GCP.DirectorServiceAccountKey).To(Equal("some-new-key-file"))
+			Expect(boshManager.CreateCall.Receives.State.GCP.ServiceAccountKey).To(Equal("some-service-account-key"))
+
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(1))
+			Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.DirectorServiceAccountKey).To(Equal("some-new-key-file"))
+			Expect(terraformManager.ApplyCall.Receives.BBLState.GCP.PreviousDirectorServiceAccountKey).To(BeEmpty())
+			Expect(stateStore.SetCall.Receives[2].State.TFState).To(Equal("some-updated-tf-state"))
+			Expect(stateStore.SetCall.CallCount).To(Equal(3))
+
+			Expect(serviceAccountKeyManager.DeleteCall.CallCount).To(Equal(0))
+		})
+
+		It("does not remove the key of terraform when the director cannot be redeployed", func() {
+			boshManager.CreateCall.Returns.Error = errors.New("failed to create director")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to create director"))
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when terraform fails to delete its key", func() {
+			terraformManager.ApplyCall.Stub = nil
+			terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to apply"))
+		})
+
+		It("returns an error when the key of terraform cannot be found", func() {
+			delete(terraformManager.GetOutputsCall.Returns.Outputs, "director_service_account_key")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("could not find the key of the director service account"))
+			Expect(serviceAccountKeyManager.CreateCall.CallCount).To(Equal(0))
+		})
+	})
+
+	Context("when the director already has a key that bbl manages", func() {
+		BeforeEach(func() {
+			incomingState.GCP.DirectorServiceAccountKey = "some-key-file"
+		})
+
+		It("gives the director a new key and deletes the previous one", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			Expect(boshManager.CreateCall.Receives.State.GCP.DirectorServiceAccountKey).To(Equal("some-new-key-file"))
+			Expect(serviceAccountKeyManager.DeleteCall.Receives.KeyFile).To(Equal("some-key-file"))
+			Expect(stateStore.SetCall.CallCount).To(Equal(3))
+			Expect(stateStore.SetCall.Receives[2].State.GCP.PreviousDirectorServiceAccountKey).To(BeEmpty())
+
+			Expect(logger.StepCall.Messages).To(Equal([]string{
+				"creating a new key for the director service account",
+				"deleting the previous key of the director service account",
+			}))
+		})
+
+		It("keeps the previous key in the state when the director cannot be redeployed", func() {
+			boshManager.CreateCall.Returns.Error = errors.New("failed to create director")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to create director"))
+			Expect(serviceAccountKeyManager.DeleteCall.CallCount).To(Equal(0))
+			Expect(stateStore.SetCall.Receives[0].State.GCP.PreviousDirectorServiceAccountKey).To(Equal("some-key-file"))
+		})
+
+		It("saves the state that bosh create-env left behind", func() {
+			failedState := incomingState
+			failedState.BOSH.State = map[string]interface{}{"some-key": "some-value"}
+			boshManager.CreateCall.Returns.Error = bosh.NewManagerCreateError(failedState, storage.CreateEnvPhase, errors.New("failed to create"))
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError(ContainSubstring("failed to create")))
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[1].State).To(Equal(failedState))
+		})
+
+		It("returns an error when the previous key cannot be deleted", func() {
+			serviceAccountKeyManager.DeleteCall.Returns.Error = errors.New("failed to delete")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to delete"))
+		})
+	})
+
+	Context("when a previous rotation did not finish", func() {
+		BeforeEach(func() {
+			incomingState.GCP.DirectorServiceAccountKey = "some-new-key-file"
+			incomingState.GCP.PreviousDirectorServiceAccountKey = "some-key-file"
+		})
+
+		It("redeploys the director without another key and deletes the previous one", func() {
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serviceAccountKeyManager.CreateCall.CallCount).To(Equal(0))
+			Expect(boshManager.CreateCall.Receives.State.GCP.DirectorServiceAccountKey).To(Equal("some-new-key-file"))
+			Expect(serviceAccountKeyManager.DeleteCall.Receives.KeyFile).To(Equal("some-key-file"))
+			Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[1].State.GCP.PreviousDirectorServiceAccountKey).To(BeEmpty())
+
+			Expect(logger.StepCall.Messages).To(Equal([]string{
+				"resuming the rotation of the key of the director service account",
+				"deleting the previous key of the director service account",
+			}))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the service account cannot be found", func() {
+			terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{}
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("could not find the service account of the director"))
+		})
+
+		It("returns an error when the terraform outputs cannot be read", func() {
+			terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to get outputs"))
+		})
+
+		It("returns an error when the key cannot be created", func() {
+			serviceAccountKeyManager.CreateCall.Returns.Error = errors.New("failed to create key")
+
+			err := command.Execute(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to create key"))
+			Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+		})
+	})
+})
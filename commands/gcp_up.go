@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -38,6 +39,9 @@ type GCPUpConfig struct {
 	ProjectID         string
 	Zone              string
 	Region            string
+	Network           string
+	Subnetwork        string
+	NetworkProjectID  string
 	OpsFilePath       string
 	Name              string
 	NoDirector        bool
@@ -57,8 +61,8 @@ type gcpProvider interface {
 }
 
 type terraformManager interface {
-	Destroy(storage.State) (storage.State, error)
-	Apply(storage.State) (storage.State, error)
+	Destroy(context.Context, storage.State) (storage.State, error)
+	Apply(context.Context, storage.State) (storage.State, error)
 	GetOutputs(storage.State) (map[string]interface{}, error)
 	Version() (string, error)
 	ValidateVersion() error
@@ -70,8 +74,8 @@ type terraformManagerError interface {
 }
 
 type boshManager interface {
-	Create(storage.State) (storage.State, error)
-	Delete(storage.State) error
+	Create(context.Context, storage.State) (storage.State, error)
+	Delete(context.Context, storage.State) error
 	GetDeploymentVars(storage.State) (string, error)
 	Version() (string, error)
 }
@@ -104,7 +108,7 @@ func NewGCPUp(args NewGCPUpArgs) GCPUp {
 	}
 }
 
-func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
+func (u GCPUp) Execute(ctx context.Context, upConfig GCPUpConfig, state storage.State) error {
 	state.IAAS = "gcp"
 	state.Jumpbox.Enabled = upConfig.Jumpbox
 
@@ -130,6 +134,10 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 		return err
 	}
 
+	if err := fastFailExistingNetwork(upConfig, state); err != nil {
+		return err
+	}
+
 	state.GCP = gcpDetails
 
 	if upConfig.NoDirector {
@@ -166,7 +174,7 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 		return err
 	}
 
-	state, err = u.terraformManager.Apply(state)
+	state, err = u.terraformManager.Apply(ctx, state)
 	if err != nil {
 		return handleTerraformError(err, u.stateStore)
 	}
@@ -178,7 +186,7 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 
 	if !state.NoDirector {
 		state.BOSH.UserOpsFile = string(opsFileContents)
-		state, err = u.boshManager.Create(state)
+		state, err = u.boshManager.Create(ctx, state)
 		switch err.(type) {
 		case bosh.ManagerCreateError:
 			bcErr := err.(bosh.ManagerCreateError)
@@ -198,7 +206,7 @@ func (u GCPUp) Execute(upConfig GCPUpConfig, state storage.State) error {
 			return err
 		}
 
-		err := u.cloudConfigManager.Update(state)
+		err := u.cloudConfigManager.Update(ctx, state)
 		if err != nil {
 			return err
 		}
@@ -245,6 +253,11 @@ func parseUpConfig(upConfig GCPUpConfig, store storage.GCP) (storage.GCP, error)
 	if upConfig.Region != "" {
 		gcpState.Region = upConfig.Region
 	}
+	if upConfig.Network != "" {
+		gcpState.ExistingNetwork = upConfig.Network
+		gcpState.ExistingSubnetwork = upConfig.Subnetwork
+		gcpState.NetworkProjectID = upConfig.NetworkProjectID
+	}
 
 	return gcpState, nil
 }
@@ -265,6 +278,31 @@ func fastFailConflictingGCPState(configGCP storage.GCP, stateGCP storage.GCP) er
 	return nil
 }
 
+func fastFailExistingNetwork(upConfig GCPUpConfig, state storage.State) error {
+	if (upConfig.Network == "") != (upConfig.Subnetwork == "") {
+		return errors.New("--gcp-network and --gcp-subnetwork must be provided together")
+	}
+
+	if upConfig.NetworkProjectID != "" && upConfig.Network == "" {
+		return errors.New("--gcp-network-project-id requires --gcp-network")
+	}
+
+	if upConfig.Network == "" {
+		return nil
+	}
+
+	if state.GCP.ExistingNetwork == "" && state.TFState != "" {
+		return errors.New("--gcp-network cannot be used on an environment that created its own network")
+	}
+
+	if state.GCP.ExistingNetwork != "" && (state.GCP.ExistingNetwork != upConfig.Network ||
+		state.GCP.ExistingSubnetwork != upConfig.Subnetwork || state.GCP.NetworkProjectID != upConfig.NetworkProjectID) {
+		return errors.New("The --gcp-network, --gcp-subnetwork and --gcp-network-project-id cannot be changed for existing environments.")
+	}
+
+	return nil
+}
+
 func parseServiceAccountKey(serviceAccountKey string) (string, error) {
 	var key string
 
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -143,7 +144,7 @@ var _ = Describe("GCPUp", func() {
 
 	Describe("Execute", func() {
 		It("sets the GCP configuration", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -157,8 +158,66 @@ var _ = Describe("GCPUp", func() {
 			Expect(gcpClientProvider.SetConfigCall.Receives.Zone).To(Equal("some-zone"))
 		})
 
+		Context("when an existing network is provided", func() {
+			var upConfig commands.GCPUpConfig
+
+			BeforeEach(func() {
+				upConfig = commands.GCPUpConfig{
+					ServiceAccountKey: serviceAccountKeyPath,
+					ProjectID:         "some-project-id",
+					Zone:              "some-zone",
+					Region:            "us-west1",
+					Network:           "some-network",
+					Subnetwork:        "some-subnetwork",
+					NetworkProjectID:  "some-host-project-id",
+				}
+			})
+
+			It("saves the network, subnetwork and host project to the state", func() {
+				err := gcpUp.Execute(context.Background(), upConfig, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(envIDManager.SyncCall.Receives.State.GCP.ExistingNetwork).To(Equal("some-network"))
+				Expect(envIDManager.SyncCall.Receives.State.GCP.ExistingSubnetwork).To(Equal("some-subnetwork"))
+				Expect(envIDManager.SyncCall.Receives.State.GCP.NetworkProjectID).To(Equal("some-host-project-id"))
+			})
+
+			It("returns an error when the subnetwork is not provided", func() {
+				upConfig.Subnetwork = ""
+				err := gcpUp.Execute(context.Background(), upConfig, storage.State{})
+				Expect(err).To(MatchError("--gcp-network and --gcp-subnetwork must be provided together"))
+			})
+
+			It("returns an error when a host project is provided without a network", func() {
+				upConfig.Network = ""
+				upConfig.Subnetwork = ""
+				err := gcpUp.Execute(context.Background(), upConfig, storage.State{})
+				Expect(err).To(MatchError("--gcp-network-project-id requires --gcp-network"))
+			})
+
+			It("returns an error when the environment created its own network", func() {
+				err := gcpUp.Execute(context.Background(), upConfig, storage.State{
+					TFState: "some-tf-state",
+				})
+				Expect(err).To(MatchError("--gcp-network cannot be used on an environment that created its own network"))
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the network is changed", func() {
+				err := gcpUp.Execute(context.Background(), upConfig, storage.State{
+					GCP: storage.GCP{
+						ExistingNetwork:    "some-other-network",
+						ExistingSubnetwork: "some-subnetwork",
+						NetworkProjectID:   "some-host-project-id",
+					},
+					TFState: "some-tf-state",
+				})
+				Expect(err).To(MatchError("The --gcp-network, --gcp-subnetwork and --gcp-network-project-id cannot be changed for existing environments."))
+			})
+		})
+
 		It("sets the serviceAccountKey from the path", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -173,7 +232,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("sets the serviceAccountKey from the given JSON string", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKey,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -188,7 +247,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("retrieves the env ID", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -202,7 +261,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("saves the resulting state with the env ID", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -215,7 +274,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("syncs the keypair", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -246,7 +305,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("saves the key pair to the state", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -259,7 +318,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("creates gcp resources via terraform", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -271,8 +330,25 @@ var _ = Describe("GCPUp", func() {
 			Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(expectedKeyPairState))
 		})
 
+		It("passes the context to terraform and bosh so that they can be interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := gcpUp.Execute(ctx, commands.GCPUpConfig{
+				ServiceAccountKey: serviceAccountKeyPath,
+				ProjectID:         "some-project-id",
+				Zone:              "some-zone",
+				Region:            "us-west1",
+			}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.ApplyCall.Receives.Context).To(BeIdenticalTo(ctx))
+			Expect(boshManager.CreateCall.Receives.Context).To(BeIdenticalTo(ctx))
+			Expect(cloudConfigManager.UpdateCall.Receives.Context).To(BeIdenticalTo(ctx))
+		})
+
 		It("saves the terraform state to the state", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -285,7 +361,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("creates a bosh", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -296,7 +372,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("saves the bosh state to the state", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -309,7 +385,7 @@ var _ = Describe("GCPUp", func() {
 		})
 
 		It("updates the cloud config", func() {
-			err := gcpUp.Execute(commands.GCPUpConfig{
+			err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 				ServiceAccountKey: serviceAccountKeyPath,
 				ProjectID:         "some-project-id",
 				Zone:              "some-zone",
@@ -325,7 +401,7 @@ var _ = Describe("GCPUp", func() {
 
 		Context("when a name is passed in for env-id", func() {
 			It("passes that name in for the env id manager to use", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -349,7 +425,7 @@ var _ = Describe("GCPUp", func() {
 				err = ioutil.WriteFile(opsFilePath, []byte(opsFileContents), os.ModePerm)
 				Expect(err).NotTo(HaveOccurred())
 
-				err = gcpUp.Execute(commands.GCPUpConfig{
+				err = gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -368,7 +444,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("does not create a bosh or update cloud config", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -386,7 +462,7 @@ var _ = Describe("GCPUp", func() {
 
 			Context("when re-bbling up an environment with no director", func() {
 				It("does not create a bosh director", func() {
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-zone",
@@ -411,7 +487,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("creates a jumpbox", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -446,7 +522,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("does not require details from up config", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{}, storage.State{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, storage.State{
 					IAAS: "gcp",
 					GCP: storage.GCP{
 						ServiceAccountKey: serviceAccountKeyPath,
@@ -459,7 +535,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("should not store the state if the provided flags are not valid", func() {
-				err := gcpUp.Execute(
+				err := gcpUp.Execute(context.Background(),
 					commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 					}, storage.State{})
@@ -470,7 +546,7 @@ var _ = Describe("GCPUp", func() {
 			It("saves the keypair when the terraform fails", func() {
 				terraformManager.ApplyCall.Returns.Error = errors.New("terraform manager failed")
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -484,7 +560,7 @@ var _ = Describe("GCPUp", func() {
 
 			It("calls terraform manager with previous state", func() {
 				expectedKeyPairState.TFState = "existing-tf-state"
-				err := gcpUp.Execute(commands.GCPUpConfig{}, storage.State{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, storage.State{
 					IAAS: "gcp",
 					GCP: storage.GCP{
 						ServiceAccountKey: serviceAccountKey,
@@ -505,7 +581,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error if terraform manager version validator fails", func() {
 				terraformManager.ValidateVersionCall.Returns.Error = errors.New("cannot validate version")
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -516,7 +592,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("returns an error when the service account key passed in is neither an existent filename or valid json", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: "/some/non/existent/file",
 					ProjectID:         "p",
 					Zone:              "z",
@@ -533,7 +609,7 @@ var _ = Describe("GCPUp", func() {
 				err = ioutil.WriteFile(invalidServiceAccountKeyPath, []byte(`%%%not-valid-json%%%`), os.ModePerm)
 				Expect(err).NotTo(HaveOccurred())
 
-				err = gcpUp.Execute(commands.GCPUpConfig{
+				err = gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: invalidServiceAccountKeyPath,
 					ProjectID:         "p",
 					Zone:              "z",
@@ -543,7 +619,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			It("returns an error when the ops file cannot be read", func() {
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					OpsFilePath:       "some/fake/path",
 				}, storage.State{})
@@ -552,7 +628,7 @@ var _ = Describe("GCPUp", func() {
 
 			Context("when calling up with different gcp flags then the state", func() {
 				It("returns an error when the --gcp-region is different", func() {
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-zone",
@@ -569,7 +645,7 @@ var _ = Describe("GCPUp", func() {
 				})
 
 				It("returns an error when the --gcp-zone is different", func() {
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-other-zone",
@@ -586,7 +662,7 @@ var _ = Describe("GCPUp", func() {
 				})
 
 				It("returns an error when the --gcp-project-id is different", func() {
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-other-project-id",
 						Zone:              "some-zone",
@@ -605,7 +681,7 @@ var _ = Describe("GCPUp", func() {
 
 			Context("when a bbl environment exists with a bosh director", func() {
 				It("fast fails before creating any infrastructure", func() {
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-zone",
@@ -626,7 +702,7 @@ var _ = Describe("GCPUp", func() {
 			})
 
 			DescribeTable("up config validation", func(upConfig func() commands.GCPUpConfig, expectedErr string) {
-				err := gcpUp.Execute(upConfig(), storage.State{})
+				err := gcpUp.Execute(context.Background(), upConfig(), storage.State{})
 				Expect(err).To(MatchError(expectedErr))
 			},
 				Entry("returns an error when no flags are passed in", func() commands.GCPUpConfig {
@@ -666,7 +742,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error when setting config fails", func() {
 				gcpClientProvider.SetConfigCall.Returns.Error = errors.New("setting config failed")
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -677,7 +753,7 @@ var _ = Describe("GCPUp", func() {
 
 			It("fast fails if a gcp environment with the same name already exists", func() {
 				envIDManager.SyncCall.Returns.Error = errors.New("environment already exists")
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -689,7 +765,7 @@ var _ = Describe("GCPUp", func() {
 
 			It("returns an error when state store fails to set after syncing env id", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("set call failed")}}
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "p",
 					Zone:              "z",
@@ -701,7 +777,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error when the keypair could not be updated", func() {
 				keyPairManager.SyncCall.Returns.Error = errors.New("keypair sync failed")
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -713,7 +789,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error when the state fails to be set after updating keypair", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("state failed to be set")}}
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -733,7 +809,7 @@ var _ = Describe("GCPUp", func() {
 				It("saves the tf state when the applier fails", func() {
 					terraformManager.ApplyCall.Returns.Error = terraformManagerError
 
-					err := gcpUp.Execute(commands.GCPUpConfig{}, storage.State{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, storage.State{
 						IAAS: "gcp",
 						GCP: storage.GCP{
 							ServiceAccountKey: serviceAccountKey,
@@ -753,7 +829,7 @@ var _ = Describe("GCPUp", func() {
 					terraformManagerError.BBLStateCall.Returns.Error = errors.New("some-bbl-state-error")
 					terraformManager.ApplyCall.Returns.Error = terraformManagerError
 
-					err := gcpUp.Execute(commands.GCPUpConfig{}, storage.State{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, storage.State{
 						IAAS: "gcp",
 						GCP: storage.GCP{
 							ServiceAccountKey: serviceAccountKey,
@@ -770,7 +846,7 @@ var _ = Describe("GCPUp", func() {
 
 				It("returns an error if applier fails with non terraform manager apply error", func() {
 					terraformManager.ApplyCall.Returns.Error = errors.New("failed to apply")
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-zone",
@@ -798,7 +874,7 @@ var _ = Describe("GCPUp", func() {
 					terraformManager.ApplyCall.Returns.Error = terraformManagerError
 
 					stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {errors.New("state failed to be set")}}
-					err := gcpUp.Execute(commands.GCPUpConfig{}, incomingState)
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, incomingState)
 
 					Expect(err).To(MatchError("the following errors occurred:\nfailed to apply,\nstate failed to be set"))
 					Expect(stateStore.SetCall.CallCount).To(Equal(3))
@@ -809,7 +885,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error when the state fails to be set after applying terraform", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {errors.New("state failed to be set")}}
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -843,12 +919,12 @@ var _ = Describe("GCPUp", func() {
 						newState := incomingState
 						newState.BOSH.State = expectedBOSHState
 
-						expectedError := bosh.NewManagerCreateError(newState, errors.New("failed to create"))
+						expectedError := bosh.NewManagerCreateError(newState, storage.CreateEnvPhase, errors.New("failed to create"))
 						boshManager.CreateCall.Returns.Error = expectedError
 					})
 
 					It("returns the error and saves the state", func() {
-						err := gcpUp.Execute(commands.GCPUpConfig{}, incomingState)
+						err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{}, incomingState)
 						Expect(err).To(MatchError("failed to create"))
 						Expect(stateStore.SetCall.CallCount).To(Equal(4))
 						Expect(stateStore.SetCall.Receives[3].State.BOSH.State).To(Equal(expectedBOSHState))
@@ -857,7 +933,7 @@ var _ = Describe("GCPUp", func() {
 					It("returns a compound error when it fails to save the state", func() {
 						stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {}, {errors.New("state failed to be set")}}
 
-						err := gcpUp.Execute(commands.GCPUpConfig{
+						err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 							ServiceAccountKey: serviceAccountKeyPath,
 							ProjectID:         "some-project-id",
 							Zone:              "some-zone",
@@ -872,7 +948,7 @@ var _ = Describe("GCPUp", func() {
 				It("returns an error when bosh manager fails to create a bosh with a non bosh manager create error", func() {
 					boshManager.CreateCall.Returns.Error = errors.New("failed to create")
 
-					err := gcpUp.Execute(commands.GCPUpConfig{
+					err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 						ServiceAccountKey: serviceAccountKeyPath,
 						ProjectID:         "some-project-id",
 						Zone:              "some-zone",
@@ -885,7 +961,7 @@ var _ = Describe("GCPUp", func() {
 			It("returns an error when the state fails to be set after deploying bosh", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {}, {}, {errors.New("state failed to be set")}}
 
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -896,7 +972,7 @@ var _ = Describe("GCPUp", func() {
 
 			It("returns an error when the cloud config manager fails to update", func() {
 				cloudConfigManager.UpdateCall.Returns.Error = errors.New("failed to update")
-				err := gcpUp.Execute(commands.GCPUpConfig{
+				err := gcpUp.Execute(context.Background(), commands.GCPUpConfig{
 					ServiceAccountKey: serviceAccountKeyPath,
 					ProjectID:         "some-project-id",
 					Zone:              "some-zone",
@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type GCPUpdateLBs struct {
 	gcpCreateLBs gcpCreateLBs
@@ -12,10 +16,10 @@ func NewGCPUpdateLBs(gcpCreateLBs gcpCreateLBs) GCPUpdateLBs {
 	}
 }
 
-func (g GCPUpdateLBs) Execute(config GCPCreateLBsConfig, state storage.State) error {
+func (g GCPUpdateLBs) Execute(ctx context.Context, config GCPCreateLBsConfig, state storage.State) error {
 	if config.Domain == "" {
 		config.Domain = state.LB.Domain
 	}
 
-	return g.gcpCreateLBs.Execute(config, state)
+	return g.gcpCreateLBs.Execute(ctx, config, state)
 }
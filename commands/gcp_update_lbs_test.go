@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -40,7 +41,7 @@ var _ = Describe("GCP Update LBs", func() {
 				LBType:   "cf",
 				Domain:   "some-domain",
 			}
-			err := command.Execute(config, state)
+			err := command.Execute(context.Background(), config, state)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(1))
@@ -61,7 +62,7 @@ var _ = Describe("GCP Update LBs", func() {
 					LBType:   "cf",
 					Domain:   "",
 				}
-				err := command.Execute(config, state)
+				err := command.Execute(context.Background(), config, state)
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(gcpCreateLBs.ExecuteCall.CallCount).To(Equal(1))
@@ -1,6 +1,9 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/helpers"
+import (
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+)
 
 func handleTerraformError(err error, stateStore stateStore) error {
 	switch err.(type) {
@@ -24,3 +27,20 @@ func handleTerraformError(err error, stateStore stateStore) error {
 
 	return err
 }
+
+// handleBOSHManagerError saves the state that bosh create-env left behind, so
+// that the next run picks up where it failed.
+func handleBOSHManagerError(err error, stateStore stateStore) error {
+	switch err.(type) {
+	case bosh.ManagerCreateError:
+		managerCreateError := err.(bosh.ManagerCreateError)
+		if setErr := stateStore.Set(managerCreateError.State()); setErr != nil {
+			errorList := helpers.Errors{}
+			errorList.Add(err)
+			errorList.Add(setErr)
+			return errorList
+		}
+	}
+
+	return err
+}
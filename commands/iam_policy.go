@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/iampolicy"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const IAMPolicyCommand = "iam-policy"
+
+type IAMPolicy struct {
+	logger             logger
+	outputWriter       outputWriter
+	iamPolicyGenerator iamPolicyGenerator
+}
+
+type iamPolicyGenerator interface {
+	AWS(lbType, domain string) (iampolicy.AWSPolicy, error)
+	GCP(lbType, domain, region string) (iampolicy.GCPRole, error)
+}
+
+type iamPolicyConfig struct {
+	iaas   string
+	lbType string
+	domain string
+}
+
+func NewIAMPolicy(logger logger, outputWriter outputWriter, iamPolicyGenerator iamPolicyGenerator) IAMPolicy {
+	return IAMPolicy{
+		logger:             logger,
+		outputWriter:       outputWriter,
+		iamPolicyGenerator: iamPolicyGenerator,
+	}
+}
+
+func (i IAMPolicy) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := i.parseFlags(subcommandFlags, state)
+	if err != nil {
+		return err
+	}
+
+	switch config.iaas {
+	case "aws", "gcp":
+	case "":
+		return errors.New("--iaas [gcp, aws] must be provided")
+	default:
+		return fmt.Errorf("%q is an invalid iaas type, supported values are: [gcp, aws]", config.iaas)
+	}
+
+	if !lbExists(config.lbType) && config.lbType != "" {
+		return fmt.Errorf("%q is an invalid lb type, supported values are: [cf, concourse]", config.lbType)
+	}
+
+	if config.domain != "" && config.lbType != "cf" {
+		return errors.New("--domain is only supported with --lb-type cf")
+	}
+
+	return nil
+}
+
+func (i IAMPolicy) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := i.parseFlags(subcommandFlags, state)
+	if err != nil {
+		return err
+	}
+
+	var (
+		policy interface{}
+		format string
+	)
+	switch config.iaas {
+	case "aws":
+		policy, err = i.iamPolicyGenerator.AWS(config.lbType, config.domain)
+		format = JSONOutputFormat
+	case "gcp":
+		policy, err = i.iamPolicyGenerator.GCP(config.lbType, config.domain, state.GCP.Region)
+		format = YAMLOutputFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	if !i.outputWriter.IsText() {
+		return i.outputWriter.Write(policy)
+	}
+
+	contents, err := MarshalOutput(format, policy)
+	if err != nil {
+		return err
+	}
+
+	i.logger.Println(contents)
+	return nil
+}
+
+// parseFlags defaults to the iaas and load balancer of the current
+// environment, if there is one.
+func (IAMPolicy) parseFlags(subcommandFlags []string, state storage.State) (iamPolicyConfig, error) {
+	lbType := state.LB.Type
+	if lbType == "" {
+		lbType = state.Stack.LBType
+	}
+
+	config := iamPolicyConfig{}
+	policyFlags := flags.New("iam-policy")
+	policyFlags.String(&config.iaas, "iaas", state.IAAS)
+	policyFlags.String(&config.lbType, "lb-type", lbType)
+	policyFlags.String(&config.domain, "domain", state.LB.Domain)
+
+	err := policyFlags.Parse(subcommandFlags)
+	if err != nil {
+		return iamPolicyConfig{}, err
+	}
+
+	return config, nil
+}
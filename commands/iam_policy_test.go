@@ -0,0 +1,165 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/iampolicy"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IAMPolicy", func() {
+	var (
+		logger             *fakes.Logger
+		outputWriter       *fakes.OutputWriter
+		iamPolicyGenerator *fakes.IAMPolicyGenerator
+
+		command commands.IAMPolicy
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+
+		iamPolicyGenerator = &fakes.IAMPolicyGenerator{}
+		iamPolicyGenerator.AWSCall.Returns.Policy = iampolicy.AWSPolicy{
+			Version: "2012-10-17",
+			Statement: []iampolicy.AWSStatement{{
+				Effect:   "Allow",
+				Action:   []string{"ec2:CreateVpc", "ec2:DeleteVpc"},
+				Resource: "*",
+			}},
+		}
+		iamPolicyGenerator.GCPCall.Returns.Role = iampolicy.GCPRole{
+			Title:               "bbl",
+			Description:         "some-description",
+			Stage:               "GA",
+			IncludedPermissions: []string{"compute.networks.create"},
+		}
+
+		command = commands.NewIAMPolicy(logger, outputWriter, iamPolicyGenerator)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when no iaas is provided and there is no environment", func() {
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("--iaas [gcp, aws] must be provided"))
+		})
+
+		It("uses the iaas of the current environment", func() {
+			err := command.CheckFastFails([]string{}, storage.State{IAAS: "gcp"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the iaas is invalid", func() {
+			err := command.CheckFastFails([]string{"--iaas", "azure"}, storage.State{})
+			Expect(err).To(MatchError(`"azure" is an invalid iaas type, supported values are: [gcp, aws]`))
+		})
+
+		It("returns an error when the lb type is invalid", func() {
+			err := command.CheckFastFails([]string{"--iaas", "aws", "--lb-type", "diego"}, storage.State{})
+			Expect(err).To(MatchError(`"diego" is an invalid lb type, supported values are: [cf, concourse]`))
+		})
+
+		It("returns an error when a domain is provided without a cf lb", func() {
+			err := command.CheckFastFails([]string{"--iaas", "aws", "--lb-type", "concourse", "--domain", "some-domain"}, storage.State{})
+			Expect(err).To(MatchError("--domain is only supported with --lb-type cf"))
+		})
+
+		It("returns an error when unknown flags are provided", func() {
+			err := command.CheckFastFails([]string{"--some-flag"}, storage.State{})
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints the aws policy document as json", func() {
+			err := command.Execute(context.Background(), []string{
+				"--iaas", "aws",
+				"--lb-type", "cf",
+				"--domain", "some-domain",
+			}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamPolicyGenerator.AWSCall.Receives.LBType).To(Equal("cf"))
+			Expect(iamPolicyGenerator.AWSCall.Receives.Domain).To(Equal("some-domain"))
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{`{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [
+        "ec2:CreateVpc",
+        "ec2:DeleteVpc"
+      ],
+      "Resource": "*"
+    }
+  ]
+}`}))
+		})
+
+		It("prints the gcp custom role definition as yaml", func() {
+			err := command.Execute(context.Background(), []string{"--iaas", "gcp"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{`title: bbl
+description: some-description
+stage: GA
+includedPermissions:
+- compute.networks.create`}))
+		})
+
+		It("defaults to the load balancer and region of the current environment", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{
+				IAAS: "gcp",
+				GCP: storage.GCP{
+					Region: "some-region",
+				},
+				LB: storage.LB{
+					Type:   "cf",
+					Domain: "some-domain",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamPolicyGenerator.GCPCall.Receives.LBType).To(Equal("cf"))
+			Expect(iamPolicyGenerator.GCPCall.Receives.Domain).To(Equal("some-domain"))
+			Expect(iamPolicyGenerator.GCPCall.Receives.Region).To(Equal("some-region"))
+		})
+
+		It("defaults to the load balancer of a legacy aws environment", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{
+				IAAS: "aws",
+				Stack: storage.Stack{
+					LBType: "concourse",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(iamPolicyGenerator.AWSCall.Receives.LBType).To(Equal("concourse"))
+		})
+
+		It("writes the policy to the output writer when the output format is not text", func() {
+			outputWriter.IsTextCall.Returns.IsText = false
+
+			err := command.Execute(context.Background(), []string{"--iaas", "aws"}, storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputWriter.WriteCall.Receives.Value).To(Equal(iamPolicyGenerator.AWSCall.Returns.Policy))
+			Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the policy cannot be generated", func() {
+			iamPolicyGenerator.GCPCall.Returns.Error = errors.New("no permissions are known")
+
+			err := command.Execute(context.Background(), []string{"--iaas", "gcp"}, storage.State{})
+			Expect(err).To(MatchError("no permissions are known"))
+		})
+	})
+})
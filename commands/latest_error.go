@@ -1,17 +1,32 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 const LatestErrorCommand = "latest-error"
 
 type LatestError struct {
 	logger         logger
+	outputWriter   outputWriter
 	stateValidator stateValidator
 }
 
-func NewLatestError(logger logger, stateValidator stateValidator) LatestError {
+type latestErrorConfig struct {
+	phase string
+	all   bool
+}
+
+func NewLatestError(logger logger, outputWriter outputWriter, stateValidator stateValidator) LatestError {
 	return LatestError{
 		logger:         logger,
+		outputWriter:   outputWriter,
 		stateValidator: stateValidator,
 	}
 }
@@ -25,7 +40,124 @@ func (l LatestError) CheckFastFails(subcommandFlags []string, state storage.Stat
 	return nil
 }
 
-func (l LatestError) Execute(subcommandFlags []string, bblState storage.State) error {
+func (l LatestError) Execute(ctx context.Context, subcommandFlags []string, bblState storage.State) error {
+	config, err := l.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.all {
+		return l.printAll(config.phase, bblState)
+	}
+
+	if config.phase != "" {
+		return l.printPhase(config.phase, bblState)
+	}
+
+	if !l.outputWriter.IsText() {
+		return l.outputWriter.Write(map[string]string{
+			"latest_tf_output": bblState.LatestTFOutput,
+		})
+	}
+
 	l.logger.Println(bblState.LatestTFOutput)
 	return nil
 }
+
+func (l LatestError) printAll(phase string, bblState storage.State) error {
+	failures := []storage.Failure{}
+	for _, failure := range bblState.Failures {
+		if phase == "" || failure.Phase == phase {
+			failures = append(failures, failure)
+		}
+	}
+
+	if !l.outputWriter.IsText() {
+		return l.outputWriter.Write(map[string][]storage.Failure{
+			"failures": failures,
+		})
+	}
+
+	if len(failures) == 0 {
+		l.logger.Println("No failures have been recorded.")
+		return nil
+	}
+
+	formattedFailures := []string{}
+	for _, failure := range failures {
+		formattedFailures = append(formattedFailures, formatFailure(failure))
+	}
+
+	l.logger.Println(strings.Join(formattedFailures, "\n\n"))
+	return nil
+}
+
+func (l LatestError) printPhase(phase string, bblState storage.State) error {
+	failure, ok := bblState.LatestFailure(phase)
+	if !ok {
+		if phase == storage.TerraformPhase && bblState.LatestTFOutput != "" {
+			failure = storage.Failure{
+				Phase:  storage.TerraformPhase,
+				Output: bblState.LatestTFOutput,
+			}
+		} else {
+			return fmt.Errorf("No %s failures have been recorded.", phase)
+		}
+	}
+
+	if !l.outputWriter.IsText() {
+		return l.outputWriter.Write(failure)
+	}
+
+	l.logger.Println(formatFailure(failure))
+	return nil
+}
+
+func (LatestError) parseFlags(subcommandFlags []string) (latestErrorConfig, error) {
+	config := latestErrorConfig{}
+
+	latestErrorFlags := flags.New("latest-error")
+	latestErrorFlags.String(&config.phase, "phase", "")
+	latestErrorFlags.Bool(&config.all, "", "all", false)
+
+	err := latestErrorFlags.Parse(subcommandFlags)
+	if err != nil {
+		return latestErrorConfig{}, err
+	}
+
+	if config.phase != "" && !validPhase(config.phase) {
+		return latestErrorConfig{}, fmt.Errorf("%q is an invalid phase, supported values are: [%s]", config.phase, strings.Join(storage.Phases, ", "))
+	}
+
+	return config, nil
+}
+
+func validPhase(phase string) bool {
+	for _, validPhase := range storage.Phases {
+		if phase == validPhase {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFailure(failure storage.Failure) string {
+	var header []string
+	if !failure.Time.IsZero() {
+		header = append(header, failure.Time.Format(time.RFC3339))
+	}
+	if failure.Command != "" {
+		header = append(header, failure.Command)
+	}
+	header = append(header, failure.Phase)
+
+	lines := []string{strings.Join(header, " ")}
+	if failure.Error != "" {
+		lines[0] = fmt.Sprintf("%s: %s", lines[0], failure.Error)
+	}
+	if failure.Output != "" {
+		lines = append(lines, strings.TrimSuffix(failure.Output, "\n"))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,7 +1,9 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
@@ -15,6 +17,7 @@ var _ = Describe("latest-error", func() {
 	var (
 		logger         *fakes.Logger
 		stateValidator *fakes.StateValidator
+		outputWriter   *fakes.OutputWriter
 
 		command commands.LatestError
 	)
@@ -23,7 +26,10 @@ var _ = Describe("latest-error", func() {
 		logger = &fakes.Logger{}
 		stateValidator = &fakes.StateValidator{}
 
-		command = commands.NewLatestError(logger, stateValidator)
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+
+		command = commands.NewLatestError(logger, outputWriter, stateValidator)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -40,10 +46,138 @@ var _ = Describe("latest-error", func() {
 				LatestTFOutput: "some tf output",
 			}
 
-			err := command.Execute([]string{}, bblState)
+			err := command.Execute(context.Background(), []string{}, bblState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("some tf output"))
 		})
+
+		Context("when the output format is not text", func() {
+			It("writes the latest terraform output to the output writer", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+				bblState := storage.State{
+					LatestTFOutput: "some tf output",
+				}
+
+				err := command.Execute(context.Background(), []string{}, bblState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string]string{
+					"latest_tf_output": "some tf output",
+				}))
+			})
+		})
+
+		Context("when failures have been recorded", func() {
+			var bblState storage.State
+
+			BeforeEach(func() {
+				bblState = storage.State{
+					LatestTFOutput: "some tf output",
+					Failures: []storage.Failure{
+						{
+							Time:    time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC),
+							Command: "up",
+							Phase:   "terraform",
+							Error:   "exit status 1",
+							Output:  "some tf output\n",
+						},
+						{
+							Time:    time.Date(2017, time.June, 2, 8, 0, 0, 0, time.UTC),
+							Command: "up",
+							Phase:   "create-env",
+							Error:   "exit status 1",
+							Output:  "some create-env output",
+						},
+						{
+							Time:    time.Date(2017, time.June, 3, 9, 15, 0, 0, time.UTC),
+							Command: "create-lbs",
+							Phase:   "cloud-config",
+							Error:   "failed to update cloud config",
+						},
+					},
+				}
+			})
+
+			It("prints the latest failure of the given phase", func() {
+				err := command.Execute(context.Background(), []string{"--phase", "create-env"}, bblState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"2017-06-02T08:00:00Z up create-env: exit status 1\nsome create-env output",
+				}))
+			})
+
+			It("prints all failures oldest first", func() {
+				err := command.Execute(context.Background(), []string{"--all"}, bblState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"2017-06-01T12:30:00Z up terraform: exit status 1\nsome tf output\n\n" +
+						"2017-06-02T08:00:00Z up create-env: exit status 1\nsome create-env output\n\n" +
+						"2017-06-03T09:15:00Z create-lbs cloud-config: failed to update cloud config",
+				}))
+			})
+
+			It("prints all failures of the given phase", func() {
+				err := command.Execute(context.Background(), []string{"--all", "--phase=cloud-config"}, bblState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"2017-06-03T09:15:00Z create-lbs cloud-config: failed to update cloud config",
+				}))
+			})
+
+			It("returns an error when no failure of the phase has been recorded", func() {
+				err := command.Execute(context.Background(), []string{"--phase", "jumpbox"}, bblState)
+				Expect(err).To(MatchError("No jumpbox failures have been recorded."))
+			})
+
+			It("returns an error when the phase is invalid", func() {
+				err := command.Execute(context.Background(), []string{"--phase", "lbs"}, bblState)
+				Expect(err).To(MatchError(`"lbs" is an invalid phase, supported values are: [terraform, create-env, delete-env, jumpbox, cloud-config]`))
+			})
+
+			Context("when the output format is not text", func() {
+				BeforeEach(func() {
+					outputWriter.IsTextCall.Returns.IsText = false
+				})
+
+				It("writes the latest failure of the given phase to the output writer", func() {
+					err := command.Execute(context.Background(), []string{"--phase", "create-env"}, bblState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(outputWriter.WriteCall.Receives.Value).To(Equal(bblState.Failures[1]))
+				})
+
+				It("writes all failures to the output writer", func() {
+					err := command.Execute(context.Background(), []string{"--all"}, bblState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string][]storage.Failure{
+						"failures": bblState.Failures,
+					}))
+				})
+			})
+		})
+
+		Context("when no failures have been recorded", func() {
+			It("prints the latest terraform output for the terraform phase", func() {
+				err := command.Execute(context.Background(), []string{"--phase", "terraform"}, storage.State{
+					LatestTFOutput: "some tf output",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"terraform\nsome tf output"}))
+			})
+
+			It("says so when printing all failures", func() {
+				err := command.Execute(context.Background(), []string{"--all"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"No failures have been recorded."}))
+			})
+		})
 	})
 })
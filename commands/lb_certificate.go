@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type certificateGenerator interface {
+	GenerateCA(commonName string) (ssl.CAData, error)
+	GenerateFromCA(ca ssl.CAData, commonName string) (ssl.KeyPair, error)
+}
+
+type acmeManager interface {
+	Issue(context.Context, storage.State) (storage.State, error)
+	NeedsRenewal(storage.State) (bool, error)
+}
+
+type generatedCertificateConfig struct {
+	caCertPath string
+	caKeyPath  string
+}
+
+// applyWithGeneratedCertificate applies the load balancers with a certificate
+// issued by bbl. A cf load balancer with a domain gets a wildcard certificate
+// for it. Otherwise the certificate is for the address of the load balancer,
+// which only exists after the first apply, so terraform is applied twice.
+func applyWithGeneratedCertificate(ctx context.Context, generator certificateGenerator, terraformManager terraformManager,
+	stateStore stateStore, config generatedCertificateConfig, state storage.State) (storage.State, error) {
+
+	ca, err := loadOrGenerateCA(generator, config, state.EnvID)
+	if err != nil {
+		return state, err
+	}
+	state.LB.CA = string(ca.CA)
+
+	if state.LB.Type == "cf" && state.LB.Domain != "" {
+		state, err = issueLBCertificate(generator, ca, fmt.Sprintf("*.%s", state.LB.Domain), state)
+		if err != nil {
+			return state, err
+		}
+
+		return terraformManager.Apply(ctx, state)
+	}
+
+	state, err = issueLBCertificate(generator, ca, fmt.Sprintf("%s-lb.%s", state.LB.Type, state.EnvID), state)
+	if err != nil {
+		return state, err
+	}
+
+	state, err = terraformManager.Apply(ctx, state)
+	if err != nil {
+		return state, err
+	}
+
+	if err := stateStore.Set(state); err != nil {
+		return state, err
+	}
+
+	outputs, err := terraformManager.GetOutputs(state)
+	if err != nil {
+		return state, err
+	}
+
+	address := outputString(outputs, lbAddressOutput(state.IAAS, state.LB.Type))
+	if address == "" {
+		return state, fmt.Errorf("could not find the address of the %s load balancer", state.LB.Type)
+	}
+
+	state, err = issueLBCertificate(generator, ca, address, state)
+	if err != nil {
+		return state, err
+	}
+
+	return terraformManager.Apply(ctx, state)
+}
+
+// applyWithACMECertificate applies the load balancers with a certificate issued
+// by bbl first, because the dns zone that answers the acme challenges is
+// created along with them, and then replaces it with one from the directory.
+func applyWithACMECertificate(ctx context.Context, generator certificateGenerator, acmeManager acmeManager,
+	terraformManager terraformManager, stateStore stateStore, account storage.ACME, state storage.State) (storage.State, error) {
+
+	state.LB.ACME = &account
+
+	state, err := applyWithGeneratedCertificate(ctx, generator, terraformManager, stateStore, generatedCertificateConfig{}, state)
+	if err != nil {
+		return state, err
+	}
+
+	if err := stateStore.Set(state); err != nil {
+		return state, err
+	}
+
+	return renewACMECertificate(ctx, acmeManager, terraformManager, state)
+}
+
+func renewACMECertificate(ctx context.Context, acmeManager acmeManager, terraformManager terraformManager, state storage.State) (storage.State, error) {
+	issuedState, err := acmeManager.Issue(ctx, state)
+	if err != nil {
+		return state, fmt.Errorf("failed to issue the acme certificate: %s\nonce the name servers shown by `bbl lbs` are delegated for %s, run `bbl update-lbs --acme-renew`", err, state.LB.Domain)
+	}
+
+	return terraformManager.Apply(ctx, issuedState)
+}
+
+func loadOrGenerateCA(generator certificateGenerator, config generatedCertificateConfig, envID string) (ssl.CAData, error) {
+	if config.caCertPath == "" {
+		return generator.GenerateCA(fmt.Sprintf("%s load balancer CA", envID))
+	}
+
+	caCert, err := ioutil.ReadFile(config.caCertPath)
+	if err != nil {
+		return ssl.CAData{}, err
+	}
+
+	caKey, err := ioutil.ReadFile(config.caKeyPath)
+	if err != nil {
+		return ssl.CAData{}, err
+	}
+
+	return ssl.CAData{CA: caCert, PrivateKey: caKey}, nil
+}
+
+func issueLBCertificate(generator certificateGenerator, ca ssl.CAData, commonName string, state storage.State) (storage.State, error) {
+	keyPair, err := generator.GenerateFromCA(ca, commonName)
+	if err != nil {
+		return state, err
+	}
+
+	state.LB.Cert = string(keyPair.Certificate)
+	state.LB.Key = string(keyPair.PrivateKey)
+	state.LB.Chain = ""
+
+	return state, nil
+}
+
+func lbAddressOutput(iaas, lbType string) string {
+	switch {
+	case iaas == "gcp":
+		return "router_lb_ip"
+	case lbType == "cf":
+		return "cf_router_load_balancer_url"
+	default:
+		return "concourse_load_balancer_url"
+	}
+}
@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -32,8 +36,18 @@ func NewLBs(gcpLBs gcpLBs, awsLBs awsLBs, stateValidator stateValidator, logger
 	}
 }
 
+type lbsConfig struct {
+	ca   bool
+	json bool
+}
+
 func (l LBs) CheckFastFails(subcommandFlags []string, state storage.State) error {
-	err := l.stateValidator.Validate()
+	_, err := parseLBsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = l.stateValidator.Validate()
 	if err != nil {
 		return err
 	}
@@ -41,7 +55,21 @@ func (l LBs) CheckFastFails(subcommandFlags []string, state storage.State) error
 	return nil
 }
 
-func (l LBs) Execute(subcommandFlags []string, state storage.State) error {
+func (l LBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseLBsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.ca {
+		if state.LB.CA == "" {
+			return errors.New("no load balancer CA found, the load balancer certificate was not generated by bbl")
+		}
+
+		l.logger.Println(state.LB.CA)
+		return nil
+	}
+
 	switch state.IAAS {
 	case "aws":
 		if err := l.awsLBs.Execute(subcommandFlags, state); err != nil {
@@ -55,3 +83,33 @@ func (l LBs) Execute(subcommandFlags []string, state storage.State) error {
 
 	return nil
 }
+
+// parseLBsFlags is shared with the aws and gcp lbs commands, which print the
+// load balancers in json with --json.
+func parseLBsFlags(subcommandFlags []string) (lbsConfig, error) {
+	lbsFlags := flags.New("lbs")
+
+	config := lbsConfig{}
+	lbsFlags.Bool(&config.ca, "", "ca", false)
+	lbsFlags.Bool(&config.json, "", "json", false)
+
+	err := lbsFlags.Parse(subcommandFlags)
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+func lbCertificateExpiry(certificateInventory certificateInventory, state storage.State) (string, error) {
+	certificate, ok, err := certificateInventory.LoadBalancer(state)
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		return "", nil
+	}
+
+	return certificate.Expires.Format("2006-01-02"), nil
+}
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -41,6 +42,10 @@ var _ = Describe("LBs", func() {
 			Expect(err).To(MatchError("state validator failed"))
 		})
 
+		It("returns an error when an unknown flag is provided", func() {
+			err := lbsCommand.CheckFastFails([]string{"--some-flag"}, storage.State{})
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
 	})
 
 	Describe("Execute", func() {
@@ -49,7 +54,7 @@ var _ = Describe("LBs", func() {
 				incomingState := storage.State{
 					IAAS: "aws",
 				}
-				err := lbsCommand.Execute([]string{}, incomingState)
+				err := lbsCommand.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(awsLBs.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -62,7 +67,7 @@ var _ = Describe("LBs", func() {
 				incomingState := storage.State{
 					IAAS: "gcp",
 				}
-				err := lbsCommand.Execute([]string{}, incomingState)
+				err := lbsCommand.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(gcpLBs.ExecuteCall.Receives.SubcommandFlags).To(Equal([]string{}))
@@ -70,11 +75,45 @@ var _ = Describe("LBs", func() {
 			})
 		})
 
+		Context("when --ca is provided", func() {
+			It("prints the CA of the generated load balancer certificate", func() {
+				err := lbsCommand.Execute(context.Background(), []string{"--ca"}, storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						CA: "some-ca",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("some-ca"))
+				Expect(gcpLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when bbl did not generate the certificate", func() {
+				err := lbsCommand.Execute(context.Background(), []string{"--ca"}, storage.State{IAAS: "aws"})
+				Expect(err).To(MatchError("no load balancer CA found, the load balancer certificate was not generated by bbl"))
+				Expect(awsLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("prints the CA when it follows other flags", func() {
+				err := lbsCommand.Execute(context.Background(), []string{"--json", "--ca"}, storage.State{
+					IAAS: "aws",
+					LB: storage.LB{
+						CA: "some-ca",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(Equal("some-ca"))
+				Expect(awsLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when the AWSLBs fails", func() {
 				awsLBs.ExecuteCall.Returns.Error = errors.New("something bad happened")
 
-				err := lbsCommand.Execute([]string{}, storage.State{
+				err := lbsCommand.Execute(context.Background(), []string{}, storage.State{
 					IAAS: "aws",
 				})
 				Expect(err).To(MatchError("something bad happened"))
@@ -83,7 +122,7 @@ var _ = Describe("LBs", func() {
 			It("returns an error when the GCPLBs fails", func() {
 				gcpLBs.ExecuteCall.Returns.Error = errors.New("something bad happened")
 
-				err := lbsCommand.Execute([]string{}, storage.State{
+				err := lbsCommand.Execute(context.Background(), []string{}, storage.State{
 					IAAS: "gcp",
 				})
 				Expect(err).To(MatchError("something bad happened"))
@@ -0,0 +1,456 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+	awsterraform "github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+)
+
+const (
+	MigrateCommand = "migrate"
+
+	stackStatusDeleteInProgress = "DELETE_IN_PROGRESS"
+	stackStatusDeleteFailed     = "DELETE_FAILED"
+	stackStatusDeleteComplete   = "DELETE_COMPLETE"
+)
+
+var MigrationDriftDetected error = errors.New("terraform would create, replace or destroy imported infrastructure, the cloudformation stack has not been changed")
+
+type Migrate struct {
+	logger                    logger
+	stateStore                stateStore
+	stateValidator            stateValidator
+	terraformImporter         terraformImporter
+	stackResourceGetter       stackResourceGetter
+	stackDetacher             stackDetacher
+	availabilityZoneRetriever availabilityZoneRetriever
+	certificateDescriber      certificateDescriber
+	elasticIPRetriever        elasticIPRetriever
+	associationRetriever      routeTableAssociationRetriever
+}
+
+type terraformImporter interface {
+	ValidateVersion() error
+	Import(context.Context, storage.State, []terraform.Import) (storage.State, error)
+	Drift(context.Context, storage.State) ([]drift.Resource, error)
+}
+
+type stackResourceGetter interface {
+	GetPhysicalIDForResource(stackName string, logicalResourceID string) (string, error)
+	Describe(stackName string) (cloudformation.Stack, error)
+}
+
+type stackDetacher interface {
+	Detach(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) error
+	Delete(stackName string) error
+}
+
+type elasticIPRetriever interface {
+	AllocationID(publicIP string) (string, error)
+}
+
+type routeTableAssociationRetriever interface {
+	AssociationID(routeTableID, subnetID string) (string, error)
+}
+
+type migrateConfig struct {
+	toTerraform bool
+	certPath    string
+	keyPath     string
+	chainPath   string
+}
+
+func NewMigrate(logger logger, stateStore stateStore, stateValidator stateValidator, terraformImporter terraformImporter,
+	stackResourceGetter stackResourceGetter, stackDetacher stackDetacher, availabilityZoneRetriever availabilityZoneRetriever,
+	certificateDescriber certificateDescriber, elasticIPRetriever elasticIPRetriever,
+	associationRetriever routeTableAssociationRetriever) Migrate {
+	return Migrate{
+		logger:                    logger,
+		stateStore:                stateStore,
+		stateValidator:            stateValidator,
+		terraformImporter:         terraformImporter,
+		stackResourceGetter:       stackResourceGetter,
+		stackDetacher:             stackDetacher,
+		availabilityZoneRetriever: availabilityZoneRetriever,
+		certificateDescriber:      certificateDescriber,
+		elasticIPRetriever:        elasticIPRetriever,
+		associationRetriever:      associationRetriever,
+	}
+}
+
+func (m Migrate) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	config, err := m.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	err = m.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	if !config.toTerraform {
+		return errors.New("--to-terraform is required")
+	}
+
+	if state.IAAS != "aws" {
+		return errors.New("bbl migrate only supports aws environments")
+	}
+
+	if state.TFState != "" && !state.Stack.MigrationInProgress {
+		return errors.New("this environment is already managed by terraform")
+	}
+
+	if state.Stack.Name == "" {
+		return errors.New("this environment has no cloudformation stack to migrate")
+	}
+
+	if lbExists(state.Stack.LBType) && !state.Stack.MigrationInProgress && (config.certPath == "" || config.keyPath == "") {
+		return errors.New("--cert and --key are required to migrate an environment with load balancers")
+	}
+
+	return m.terraformImporter.ValidateVersion()
+}
+
+func (m Migrate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := m.parseFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	availabilityZones, err := m.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+	if err != nil {
+		return err
+	}
+
+	var certificateARN string
+	if lbExists(state.Stack.LBType) {
+		certificate, err := m.certificateDescriber.Describe(state.Stack.CertificateName)
+		if err != nil {
+			return err
+		}
+		certificateARN = certificate.ARN
+	}
+
+	if state.Stack.MigrationInProgress {
+		m.logger.Step("resuming the migration of cloudformation stack %s", state.Stack.Name)
+		err = m.resumeDetach(state, availabilityZones, certificateARN)
+		if err != nil {
+			return err
+		}
+	} else {
+		stack, err := m.stackResourceGetter.Describe(state.Stack.Name)
+		if err != nil {
+			return err
+		}
+
+		state, err = m.importStack(ctx, state, stack, config, len(availabilityZones))
+		if err != nil {
+			return err
+		}
+
+		err = m.detach(state, availabilityZones, certificateARN)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The bosh az and the certificate name are inputs to the terraform
+	// templates as well.
+	migratedStack := storage.Stack{
+		BOSHAZ:   state.Stack.BOSHAZ,
+		Migrated: true,
+	}
+	if lbExists(state.Stack.LBType) {
+		migratedStack.CertificateName = state.Stack.CertificateName
+	}
+	state.Stack = migratedStack
+
+	return m.stateStore.Set(state)
+}
+
+// resumeDetach finishes detaching a stack after an earlier migrate failed to.
+// Detach only deletes the stack once its resources are retained, so a stack
+// that is being deleted only needs the delete to finish.
+func (m Migrate) resumeDetach(state storage.State, availabilityZones []string, certificateARN string) error {
+	stack, err := m.stackResourceGetter.Describe(state.Stack.Name)
+	switch err {
+	case nil:
+	case cloudformation.StackNotFound:
+		return nil
+	default:
+		return err
+	}
+
+	switch stack.Status {
+	case stackStatusDeleteComplete:
+		return nil
+	case stackStatusDeleteInProgress, stackStatusDeleteFailed:
+		m.logger.Step("deleting cloudformation stack %s", state.Stack.Name)
+		return m.stackDetacher.Delete(state.Stack.Name)
+	}
+
+	return m.detach(state, availabilityZones, certificateARN)
+}
+
+func (m Migrate) detach(state storage.State, availabilityZones []string, certificateARN string) error {
+	m.logger.Step("detaching cloudformation stack %s", state.Stack.Name)
+	return m.stackDetacher.Detach(state.KeyPair.Name, availabilityZones, state.Stack.Name, state.Stack.BOSHAZ,
+		state.Stack.LBType, certificateARN, state.EnvID)
+}
+
+// importStack imports the stack resources into terraform and saves the tf
+// state before the stack is detached, so that a migration that fails to
+// detach the stack can be resumed.
+func (m Migrate) importStack(ctx context.Context, state storage.State, stack cloudformation.Stack, config migrateConfig,
+	availabilityZoneCount int) (storage.State, error) {
+
+	// The terraform templates create an access key for the iam user, unless
+	// the director already has one. Terraform cannot import the one of the
+	// stack, so the director keeps using it.
+	migratedState := state
+	migratedState.AWS.DirectorAccessKeyID = stack.Outputs["BOSHUserAccessKey"]
+	migratedState.AWS.DirectorSecretAccessKey = stack.Outputs["BOSHUserSecretAccessKey"]
+	migratedState.Stack.Migrated = true
+
+	imports, err := m.stackImports(state.Stack, availabilityZoneCount)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	generatedImports, err := m.generatedImports(state.Stack, availabilityZoneCount)
+	if err != nil {
+		return storage.State{}, err
+	}
+	imports = append(imports, generatedImports...)
+
+	if lbExists(state.Stack.LBType) {
+		migratedState.LB, err = readLB(state.Stack.LBType, config)
+		if err != nil {
+			return storage.State{}, err
+		}
+
+		imports = append(imports, terraform.Import{
+			Address: "aws_iam_server_certificate.lb_cert",
+			ID:      state.Stack.CertificateName,
+		})
+	}
+
+	migratedState, err = m.terraformImporter.Import(ctx, migratedState, imports)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	resources, err := m.terraformImporter.Drift(ctx, migratedState)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	blocking := blockingDrift(resources, state.Stack.LBType)
+	if len(blocking) > 0 {
+		m.logger.Println(formatDriftReport(DriftReport{Drifted: true, Resources: blocking}))
+		return storage.State{}, MigrationDriftDetected
+	}
+
+	if len(resources) > 0 {
+		m.logger.Step("the next bbl up will apply the following changes")
+		m.logger.Println(formatDriftReport(DriftReport{Drifted: true, Resources: resources}))
+	}
+
+	migratedState.Stack.MigrationInProgress = true
+	err = m.stateStore.Set(migratedState)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	return migratedState, nil
+}
+
+func (m Migrate) stackImports(stack storage.Stack, availabilityZoneCount int) ([]terraform.Import, error) {
+	var imports []terraform.Import
+	for _, stackImport := range awsterraform.StackImports(availabilityZoneCount, stack.LBType) {
+		id, err := m.stackResourceGetter.GetPhysicalIDForResource(stack.Name, stackImport.LogicalID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Cloudformation identifies elastic ips by their public ip, terraform
+		// by their allocation id.
+		if strings.HasPrefix(stackImport.Address, "aws_eip.") {
+			id, err = m.elasticIPRetriever.AllocationID(id)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		imports = append(imports, terraform.Import{Address: stackImport.Address, ID: id})
+	}
+
+	return imports, nil
+}
+
+// generatedImports returns the stack resources that terraform cannot import,
+// with the attributes to write to the tf state instead.
+func (m Migrate) generatedImports(stack storage.Stack, availabilityZoneCount int) ([]terraform.Import, error) {
+	physicalIDs := map[string]string{}
+	physicalID := func(logicalID string) (string, error) {
+		if logicalID == "" {
+			return "", nil
+		}
+
+		if id, ok := physicalIDs[logicalID]; ok {
+			return id, nil
+		}
+
+		id, err := m.stackResourceGetter.GetPhysicalIDForResource(stack.Name, logicalID)
+		if err != nil {
+			return "", err
+		}
+		physicalIDs[logicalID] = id
+
+		return id, nil
+	}
+
+	var imports []terraform.Import
+	for _, rule := range awsterraform.StackSecurityGroupRules() {
+		securityGroupID, err := physicalID(rule.SecurityGroupLogicalID)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceSecurityGroupID, err := physicalID(rule.SourceSecurityGroupLogicalID)
+		if err != nil {
+			return nil, err
+		}
+
+		attributes := rule.Attributes(securityGroupID, sourceSecurityGroupID)
+		imports = append(imports, terraform.Import{Address: rule.Address, ID: attributes["id"], Attributes: attributes})
+	}
+
+	for _, association := range awsterraform.StackRouteTableAssociations(availabilityZoneCount, stack.LBType) {
+		subnetID, err := physicalID(association.SubnetLogicalID)
+		if err != nil {
+			return nil, err
+		}
+
+		routeTableID, err := physicalID(association.RouteTableLogicalID)
+		if err != nil {
+			return nil, err
+		}
+
+		associationID, err := m.associationRetriever.AssociationID(routeTableID, subnetID)
+		if err != nil {
+			return nil, err
+		}
+
+		imports = append(imports, terraform.Import{
+			Address:    association.Address,
+			ID:         associationID,
+			Attributes: association.Attributes(associationID, subnetID, routeTableID),
+		})
+	}
+
+	userName, err := physicalID("BOSHUser")
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := awsterraform.StackUserPolicyAttributes(userName)
+	imports = append(imports, terraform.Import{Address: "aws_iam_user_policy.bosh", ID: attributes["id"], Attributes: attributes})
+
+	return imports, nil
+}
+
+// blockingDrift returns the resources that terraform would create, replace,
+// destroy or change other than by their tags. Only the resources that stacks
+// never had may be created, the next bbl up applies them along with the tags.
+func blockingDrift(resources []drift.Resource, lbType string) []drift.Resource {
+	additions := map[string]bool{}
+	for _, address := range awsterraform.StackAdditions(lbType) {
+		additions[address] = true
+	}
+
+	var blocking []drift.Resource
+	for _, resource := range resources {
+		switch resource.Status {
+		case drift.Changed:
+			if onlyTagsChanged(resource) {
+				continue
+			}
+		case drift.Missing:
+			if additions[resource.Name] {
+				continue
+			}
+		}
+		blocking = append(blocking, resource)
+	}
+
+	return blocking
+}
+
+func onlyTagsChanged(resource drift.Resource) bool {
+	if len(resource.Attributes) == 0 {
+		return false
+	}
+
+	for _, attribute := range resource.Attributes {
+		if !strings.HasPrefix(attribute.Name, "tags.") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func readLB(lbType string, config migrateConfig) (storage.LB, error) {
+	cert, err := ioutil.ReadFile(config.certPath)
+	if err != nil {
+		return storage.LB{}, err
+	}
+
+	key, err := ioutil.ReadFile(config.keyPath)
+	if err != nil {
+		return storage.LB{}, err
+	}
+
+	lb := storage.LB{
+		Type: lbType,
+		Cert: string(cert),
+		Key:  string(key),
+	}
+
+	if config.chainPath != "" {
+		chain, err := ioutil.ReadFile(config.chainPath)
+		if err != nil {
+			return storage.LB{}, err
+		}
+		lb.Chain = string(chain)
+	}
+
+	return lb, nil
+}
+
+func (Migrate) parseFlags(subcommandFlags []string) (migrateConfig, error) {
+	migrateFlags := flags.New("migrate")
+
+	config := migrateConfig{}
+	migrateFlags.Bool(&config.toTerraform, "", "to-terraform", false)
+	migrateFlags.String(&config.certPath, "cert", "")
+	migrateFlags.String(&config.keyPath, "key", "")
+	migrateFlags.String(&config.chainPath, "chain", "")
+
+	err := migrateFlags.Parse(subcommandFlags)
+	if err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
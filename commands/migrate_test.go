@@ -0,0 +1,549 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migrate", func() {
+	var (
+		logger                    *fakes.Logger
+		stateStore                *fakes.StateStore
+		stateValidator            *fakes.StateValidator
+		terraformManager          *fakes.TerraformManager
+		stackManager              *fakes.StackManager
+		infrastructureManager     *fakes.InfrastructureManager
+		availabilityZoneRetriever *fakes.AvailabilityZoneRetriever
+		certificateDescriber      *fakes.CertificateDescriber
+		elasticIPRetriever        *fakes.ElasticIPRetriever
+		associationRetriever      *fakes.RouteTableAssociationRetriever
+
+		command commands.Migrate
+		state   storage.State
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		stateStore = &fakes.StateStore{}
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		stackManager = &fakes.StackManager{}
+		infrastructureManager = &fakes.InfrastructureManager{}
+		availabilityZoneRetriever = &fakes.AvailabilityZoneRetriever{}
+		certificateDescriber = &fakes.CertificateDescriber{}
+		elasticIPRetriever = &fakes.ElasticIPRetriever{}
+		associationRetriever = &fakes.RouteTableAssociationRetriever{}
+
+		availabilityZoneRetriever.RetrieveCall.Returns.AZs = []string{"us-east-1a"}
+		stackManager.GetPhysicalIDForResourceCall.Stub = func(logicalID string) (string, error) {
+			return "physical-" + logicalID, nil
+		}
+		stackManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+			Outputs: map[string]string{
+				"BOSHUserAccessKey":       "some-stack-access-key-id",
+				"BOSHUserSecretAccessKey": "some-stack-secret-access-key",
+			},
+		}
+		elasticIPRetriever.AllocationIDCall.Stub = func(publicIP string) (string, error) {
+			return "eipalloc-" + publicIP, nil
+		}
+		associationRetriever.AssociationIDCall.Stub = func(routeTableID, subnetID string) (string, error) {
+			return "rtbassoc-" + subnetID, nil
+		}
+
+		state = storage.State{
+			IAAS:    "aws",
+			EnvID:   "some-env-id",
+			AWS:     storage.AWS{Region: "us-east-1"},
+			KeyPair: storage.KeyPair{Name: "some-keypair"},
+			Stack: storage.Stack{
+				Name:   "some-stack-name",
+				BOSHAZ: "us-east-1a",
+			},
+		}
+
+		command = commands.NewMigrate(logger, stateStore, stateValidator, terraformManager, stackManager,
+			infrastructureManager, availabilityZoneRetriever, certificateDescriber, elasticIPRetriever, associationRetriever)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("validates the state and the terraform version", func() {
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stateValidator.ValidateCall.CallCount).To(Equal(1))
+			Expect(terraformManager.ValidateVersionCall.CallCount).To(Equal(1))
+		})
+
+		It("returns an error when --to-terraform is not provided", func() {
+			err := command.CheckFastFails([]string{}, state)
+			Expect(err).To(MatchError("--to-terraform is required"))
+		})
+
+		It("returns an error when the environment is not on aws", func() {
+			state.IAAS = "gcp"
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("bbl migrate only supports aws environments"))
+		})
+
+		It("returns an error when the environment is already managed by terraform", func() {
+			state.TFState = "some-tf-state"
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("this environment is already managed by terraform"))
+		})
+
+		Context("when an earlier migration imported the stack but did not detach it", func() {
+			BeforeEach(func() {
+				state.TFState = "some-tf-state"
+				state.Stack.MigrationInProgress = true
+				state.Stack.LBType = "concourse"
+			})
+
+			It("accepts the environment without a certificate", func() {
+				err := command.CheckFastFails([]string{"--to-terraform"}, state)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		It("returns an error when there is no stack", func() {
+			state.Stack.Name = ""
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("this environment has no cloudformation stack to migrate"))
+		})
+
+		It("returns an error when the stack has a load balancer and no certificate is provided", func() {
+			state.Stack.LBType = "concourse"
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("--cert and --key are required to migrate an environment with load balancers"))
+		})
+
+		It("returns an error when the state is invalid", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("state validator failed"))
+		})
+
+		It("returns an error when the terraform version is invalid", func() {
+			terraformManager.ValidateVersionCall.Returns.Error = errors.New("terraform too old")
+			err := command.CheckFastFails([]string{"--to-terraform"}, state)
+			Expect(err).To(MatchError("terraform too old"))
+		})
+	})
+
+	Describe("Execute", func() {
+		var (
+			stateToImport storage.State
+			importedState storage.State
+		)
+
+		BeforeEach(func() {
+			stateToImport = state
+			stateToImport.AWS.DirectorAccessKeyID = "some-stack-access-key-id"
+			stateToImport.AWS.DirectorSecretAccessKey = "some-stack-secret-access-key"
+			stateToImport.Stack.Migrated = true
+
+			importedState = stateToImport
+			importedState.TFState = "some-imported-tf-state"
+			terraformManager.ImportCall.Returns.BBLState = importedState
+		})
+
+		It("imports the stack resources into terraform", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal("us-east-1"))
+			Expect(terraformManager.ImportCall.Receives.BBLState).To(Equal(stateToImport))
+
+			imports := terraformManager.ImportCall.Receives.Imports
+			Expect(imports).To(ContainElement(terraform.Import{Address: "aws_vpc.vpc", ID: "physical-VPC"}))
+			Expect(imports).To(ContainElement(terraform.Import{Address: "aws_subnet.internal_subnets[0]", ID: "physical-InternalSubnet1"}))
+			Expect(imports).To(ContainElement(terraform.Import{Address: "aws_iam_user.bosh", ID: "physical-BOSHUser"}))
+			Expect(imports).NotTo(ContainElement(terraform.Import{Address: "aws_subnet.internal_subnets[1]", ID: "physical-InternalSubnet2"}))
+		})
+
+		It("imports elastic ips by their allocation id", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			imports := terraformManager.ImportCall.Receives.Imports
+			Expect(imports).To(ContainElement(terraform.Import{Address: "aws_eip.bosh_eip", ID: "eipalloc-physical-BOSHEIP"}))
+			Expect(imports).To(ContainElement(terraform.Import{Address: "aws_eip.nat_eip", ID: "eipalloc-physical-NATEIP"}))
+		})
+
+		It("writes the resources that terraform cannot import to the tf state", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			imports := terraformManager.ImportCall.Receives.Imports
+			Expect(imports).To(ContainElement(terraform.Import{
+				Address: "aws_route_table_association.route_internal_subnets[0]",
+				ID:      "rtbassoc-physical-InternalSubnet1",
+				Attributes: map[string]string{
+					"id":             "rtbassoc-physical-InternalSubnet1",
+					"subnet_id":      "physical-InternalSubnet1",
+					"route_table_id": "physical-InternalRouteTable",
+				},
+			}))
+			Expect(imports).To(ContainElement(terraform.Import{
+				Address: "aws_iam_user_policy.bosh",
+				ID:      "physical-BOSHUser:aws-cpi",
+				Attributes: map[string]string{
+					"id":   "physical-BOSHUser:aws-cpi",
+					"name": "aws-cpi",
+					"user": "physical-BOSHUser",
+				},
+			}))
+
+			var rule terraform.Import
+			for _, i := range imports {
+				if i.Address == "aws_security_group_rule.bosh_security_group_rule_tcp" {
+					rule = i
+				}
+			}
+			Expect(rule.ID).To(HavePrefix("sgrule-"))
+			Expect(rule.Attributes).To(HaveKeyWithValue("security_group_id", "physical-BOSHSecurityGroup"))
+			Expect(rule.Attributes).To(HaveKeyWithValue("source_security_group_id", "physical-InternalSecurityGroup"))
+
+			Expect(associationRetriever.AssociationIDCall.CallCount).To(Equal(2))
+		})
+
+		It("keeps the access key of the stack for the director", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stackManager.DescribeCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(terraformManager.ImportCall.Receives.BBLState.AWS.DirectorAccessKeyID).To(Equal("some-stack-access-key-id"))
+			Expect(terraformManager.ImportCall.Receives.BBLState.AWS.DirectorSecretAccessKey).To(Equal("some-stack-secret-access-key"))
+		})
+
+		It("plans the imported state and detaches the stack", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformManager.DriftCall.Receives.BBLState).To(Equal(importedState))
+
+			Expect(infrastructureManager.DetachCall.CallCount).To(Equal(1))
+			Expect(infrastructureManager.DetachCall.Receives.KeyPairName).To(Equal("some-keypair"))
+			Expect(infrastructureManager.DetachCall.Receives.AZs).To(Equal([]string{"us-east-1a"}))
+			Expect(infrastructureManager.DetachCall.Receives.StackName).To(Equal("some-stack-name"))
+			Expect(infrastructureManager.DetachCall.Receives.BOSHAZ).To(Equal("us-east-1a"))
+			Expect(infrastructureManager.DetachCall.Receives.EnvID).To(Equal("some-env-id"))
+		})
+
+		It("saves the terraform state before detaching the stack and keeps the bosh az", func() {
+			err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			inProgressState := importedState
+			inProgressState.Stack.MigrationInProgress = true
+
+			expectedState := importedState
+			expectedState.Stack = storage.Stack{BOSHAZ: "us-east-1a", Migrated: true}
+
+			Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			Expect(stateStore.SetCall.Receives[0].State).To(Equal(inProgressState))
+			Expect(stateStore.SetCall.Receives[1].State).To(Equal(expectedState))
+		})
+
+		Context("when the stack has a load balancer", func() {
+			var tempDir string
+
+			BeforeEach(func() {
+				var err error
+				tempDir, err = ioutil.TempDir("", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = ioutil.WriteFile(filepath.Join(tempDir, "cert"), []byte("some-cert"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(filepath.Join(tempDir, "key"), []byte("some-key"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				state.Stack.LBType = "cf"
+				state.Stack.CertificateName = "some-certificate-name"
+				certificateDescriber.DescribeCall.Returns.Certificate = iam.Certificate{ARN: "some-certificate-arn"}
+			})
+
+			It("imports the load balancers and the certificate", func() {
+				err := command.Execute(context.Background(), []string{
+					"--to-terraform",
+					"--cert", filepath.Join(tempDir, "cert"),
+					"--key", filepath.Join(tempDir, "key"),
+				}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.ImportCall.Receives.BBLState.LB).To(Equal(storage.LB{
+					Type: "cf",
+					Cert: "some-cert",
+					Key:  "some-key",
+				}))
+
+				imports := terraformManager.ImportCall.Receives.Imports
+				Expect(imports).To(ContainElement(terraform.Import{Address: "aws_elb.cf_router_lb", ID: "physical-CFRouterLoadBalancer"}))
+				Expect(imports).To(ContainElement(terraform.Import{Address: "aws_iam_server_certificate.lb_cert", ID: "some-certificate-name"}))
+
+				Expect(certificateDescriber.DescribeCall.Receives.CertificateName).To(Equal("some-certificate-name"))
+				Expect(infrastructureManager.DetachCall.Receives.LBType).To(Equal("cf"))
+				Expect(infrastructureManager.DetachCall.Receives.LBCertificateARN).To(Equal("some-certificate-arn"))
+			})
+
+			It("routes the load balancer subnets and keeps the certificate name", func() {
+				err := command.Execute(context.Background(), []string{
+					"--to-terraform",
+					"--cert", filepath.Join(tempDir, "cert"),
+					"--key", filepath.Join(tempDir, "key"),
+				}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				imports := terraformManager.ImportCall.Receives.Imports
+				Expect(imports).To(ContainElement(terraform.Import{
+					Address: "aws_route_table_association.route_lb_subnets[0]",
+					ID:      "rtbassoc-physical-LoadBalancerSubnet1",
+					Attributes: map[string]string{
+						"id":             "rtbassoc-physical-LoadBalancerSubnet1",
+						"subnet_id":      "physical-LoadBalancerSubnet1",
+						"route_table_id": "physical-LoadBalancerRouteTable",
+					},
+				}))
+
+				Expect(stateStore.SetCall.Receives[1].State.Stack).To(Equal(storage.Stack{
+					BOSHAZ:          "us-east-1a",
+					CertificateName: "some-certificate-name",
+					Migrated:        true,
+				}))
+			})
+
+			Context("when terraform would create the cf tcp load balancer", func() {
+				BeforeEach(func() {
+					terraformManager.DriftCall.Returns.Resources = []drift.Resource{
+						{Name: "aws_elb.cf_tcp_lb", Status: drift.Missing},
+						{Name: "aws_security_group.cf_tcp_lb_security_group", Status: drift.Missing},
+					}
+				})
+
+				It("detaches the stack and leaves the load balancer to the next bbl up", func() {
+					err := command.Execute(context.Background(), []string{
+						"--to-terraform",
+						"--cert", filepath.Join(tempDir, "cert"),
+						"--key", filepath.Join(tempDir, "key"),
+					}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.StepCall.Messages).To(ContainElement("the next bbl up will apply the following changes"))
+					Expect(infrastructureManager.DetachCall.CallCount).To(Equal(1))
+				})
+			})
+
+			It("returns an error when the certificate cannot be read", func() {
+				err := command.Execute(context.Background(), []string{
+					"--to-terraform",
+					"--cert", filepath.Join(tempDir, "missing-cert"),
+					"--key", filepath.Join(tempDir, "key"),
+				}, state)
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+
+		Context("when terraform would only change the tags of the imported infrastructure", func() {
+			BeforeEach(func() {
+				terraformManager.DriftCall.Returns.Resources = []drift.Resource{{
+					Name:   "aws_vpc.vpc",
+					Status: drift.Changed,
+					Attributes: []drift.Attribute{
+						{Name: "tags.%", Status: drift.Changed, Expected: "1", Actual: "2"},
+						{Name: "tags.Name", Status: drift.Missing, Expected: "vpc-some-env-id"},
+					},
+				}}
+			})
+
+			It("prints the changes and detaches the stack", func() {
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"aws_vpc.vpc: changed\n" +
+					"  tags.%: changed, expected \"1\", actual \"2\"\n" +
+					"  tags.Name: missing, expected \"vpc-some-env-id\""}))
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			})
+		})
+
+		Context("when terraform would create, replace, destroy or change more than the tags of imported infrastructure", func() {
+			BeforeEach(func() {
+				terraformManager.DriftCall.Returns.Resources = []drift.Resource{
+					{Name: "aws_iam_user_policy.bosh", Status: drift.Changed, Attributes: []drift.Attribute{
+						{Name: "policy", Status: drift.Changed, Expected: "some-policy", Actual: "some-other-policy"},
+					}},
+					{Name: "aws_vpc.vpc", Status: drift.Changed, Attributes: []drift.Attribute{
+						{Name: "tags.Name", Status: drift.Changed, Expected: "vpc-some-env-id", Actual: "some-vpc"},
+					}},
+					{Name: "aws_security_group.bosh_security_group", Status: drift.Replaced},
+					{Name: "aws_route_table_association.route_bosh_subnets", Status: drift.Missing},
+					{Name: "aws_security_group_rule.bosh_security_group-1", Status: drift.Extra},
+					{Name: "aws_elb.cf_tcp_lb", Status: drift.Missing},
+				}
+			})
+
+			It("prints those resources and leaves the stack and the state alone", func() {
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(Equal(commands.MigrationDriftDetected))
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"aws_iam_user_policy.bosh: changed\n" +
+						"  policy: changed, expected \"some-policy\", actual \"some-other-policy\"\n" +
+						"aws_security_group.bosh_security_group: replaced\n" +
+						"aws_route_table_association.route_bosh_subnets: missing\n" +
+						"aws_security_group_rule.bosh_security_group-1: extra\n" +
+						"aws_elb.cf_tcp_lb: missing",
+				}))
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when an earlier migration imported the stack but did not detach it", func() {
+			BeforeEach(func() {
+				state.TFState = "some-imported-tf-state"
+				state.AWS.DirectorAccessKeyID = "some-stack-access-key-id"
+				state.Stack.Migrated = true
+				state.Stack.MigrationInProgress = true
+				stackManager.DescribeCall.Returns.Stack.Status = "UPDATE_COMPLETE"
+			})
+
+			It("detaches the stack without importing it again", func() {
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.StepCall.Messages).To(ContainElement("resuming the migration of cloudformation stack some-stack-name"))
+				Expect(terraformManager.ImportCall.CallCount).To(Equal(0))
+				Expect(terraformManager.DriftCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(1))
+				Expect(infrastructureManager.DetachCall.Receives.StackName).To(Equal("some-stack-name"))
+
+				expectedState := state
+				expectedState.Stack = storage.Stack{BOSHAZ: "us-east-1a", Migrated: true}
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(expectedState))
+			})
+
+			It("finishes deleting a stack whose resources are retained already", func() {
+				stackManager.DescribeCall.Returns.Stack.Status = "DELETE_FAILED"
+
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DeleteCall.CallCount).To(Equal(1))
+				Expect(infrastructureManager.DeleteCall.Receives.StackName).To(Equal("some-stack-name"))
+				Expect(stateStore.SetCall.Receives[0].State.Stack).To(Equal(storage.Stack{BOSHAZ: "us-east-1a", Migrated: true}))
+			})
+
+			It("only clears the stack from the state when it is gone already", func() {
+				stackManager.DescribeCall.Returns.Error = cloudformation.StackNotFound
+
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(0))
+				Expect(infrastructureManager.DeleteCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.Receives[0].State.Stack).To(Equal(storage.Stack{BOSHAZ: "us-east-1a", Migrated: true}))
+			})
+
+			It("keeps the stack in the state when it still cannot be detached", func() {
+				infrastructureManager.DetachCall.Returns.Error = errors.New("failed to detach")
+
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to detach"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the stack cannot be described", func() {
+				stackManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
+
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to describe stack"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the availability zones cannot be retrieved", func() {
+				availabilityZoneRetriever.RetrieveCall.Returns.Error = errors.New("failed to retrieve azs")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to retrieve azs"))
+			})
+
+			It("returns an error when a stack resource cannot be found", func() {
+				stackManager.GetPhysicalIDForResourceCall.Stub = nil
+				stackManager.GetPhysicalIDForResourceCall.Returns.Error = errors.New("failed to get physical id")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to get physical id"))
+			})
+
+			It("returns an error when the stack cannot be described", func() {
+				stackManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to describe stack"))
+			})
+
+			It("returns an error when a route table association cannot be found", func() {
+				associationRetriever.AssociationIDCall.Stub = nil
+				associationRetriever.AssociationIDCall.Returns.Error = errors.New("failed to find association")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to find association"))
+			})
+
+			It("returns an error when an elastic ip cannot be found", func() {
+				elasticIPRetriever.AllocationIDCall.Stub = nil
+				elasticIPRetriever.AllocationIDCall.Returns.Error = errors.New("failed to find eip")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to find eip"))
+			})
+
+			It("does not save the state when the import fails", func() {
+				terraformManager.ImportCall.Returns.Error = errors.New("failed to import")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to import"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the plan fails", func() {
+				terraformManager.DriftCall.Returns.Error = errors.New("failed to plan")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to plan"))
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(0))
+			})
+
+			It("keeps the imported state and the stack when the stack cannot be detached", func() {
+				infrastructureManager.DetachCall.Returns.Error = errors.New("failed to detach")
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to detach"))
+
+				Expect(stateStore.SetCall.CallCount).To(Equal(1))
+				Expect(stateStore.SetCall.Receives[0].State.TFState).To(Equal("some-imported-tf-state"))
+				Expect(stateStore.SetCall.Receives[0].State.Stack.Name).To(Equal("some-stack-name"))
+				Expect(stateStore.SetCall.Receives[0].State.Stack.MigrationInProgress).To(BeTrue())
+			})
+
+			It("does not detach the stack when the imported state cannot be saved", func() {
+				stateStore.SetCall.Returns = []fakes.SetCallReturn{{Error: errors.New("failed to save state")}}
+				err := command.Execute(context.Background(), []string{"--to-terraform"}, state)
+				Expect(err).To(MatchError("failed to save state"))
+				Expect(infrastructureManager.DetachCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+})
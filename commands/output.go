@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	TextOutputFormat = "text"
+	JSONOutputFormat = "json"
+	YAMLOutputFormat = "yaml"
+)
+
+var OutputFormats = []string{TextOutputFormat, JSONOutputFormat, YAMLOutputFormat}
+
+type outputWriter interface {
+	IsText() bool
+	Write(value interface{}) error
+}
+
+type OutputWriter struct {
+	logger logger
+	format string
+}
+
+type ErrorOutput struct {
+	Error string `json:"error" yaml:"error"`
+	Code  string `json:"code" yaml:"code"`
+}
+
+func NewOutputWriter(logger logger, format string) OutputWriter {
+	if format == "" {
+		format = TextOutputFormat
+	}
+
+	return OutputWriter{
+		logger: logger,
+		format: format,
+	}
+}
+
+func ValidateOutputFormat(format string) error {
+	for _, validFormat := range OutputFormats {
+		if format == validFormat {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%q is an invalid output format, supported values are: [%s]", format, strings.Join(OutputFormats, ", "))
+}
+
+func (o OutputWriter) IsText() bool {
+	return o.format == TextOutputFormat
+}
+
+func (o OutputWriter) Write(value interface{}) error {
+	contents, err := MarshalOutput(o.format, value)
+	if err != nil {
+		return err
+	}
+
+	o.logger.Println(contents)
+	return nil
+}
+
+func MarshalOutput(format string, value interface{}) (string, error) {
+	switch format {
+	case JSONOutputFormat:
+		contents, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(contents), nil
+	case YAMLOutputFormat:
+		contents, err := yaml.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(contents), "\n"), nil
+	}
+
+	return "", fmt.Errorf("output cannot be marshalled as %q", format)
+}
+
+func outputString(outputs map[string]interface{}, name string) string {
+	value, _ := outputs[name].(string)
+	return value
+}
+
+func stringKeys(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, nestedValue := range typedValue {
+			converted[fmt.Sprintf("%v", key)] = stringKeys(nestedValue)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := map[string]interface{}{}
+		for key, nestedValue := range typedValue {
+			converted[key] = stringKeys(nestedValue)
+		}
+		return converted
+	case []interface{}:
+		converted := []interface{}{}
+		for _, nestedValue := range typedValue {
+			converted = append(converted, stringKeys(nestedValue))
+		}
+		return converted
+	}
+
+	return value
+}
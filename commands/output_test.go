@@ -0,0 +1,79 @@
+package commands_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OutputWriter", func() {
+	var (
+		logger *fakes.Logger
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+	})
+
+	Describe("IsText", func() {
+		It("returns true when the format is text", func() {
+			Expect(commands.NewOutputWriter(logger, "text").IsText()).To(BeTrue())
+		})
+
+		It("defaults to text when no format is provided", func() {
+			Expect(commands.NewOutputWriter(logger, "").IsText()).To(BeTrue())
+		})
+
+		It("returns false when the format is json or yaml", func() {
+			Expect(commands.NewOutputWriter(logger, "json").IsText()).To(BeFalse())
+			Expect(commands.NewOutputWriter(logger, "yaml").IsText()).To(BeFalse())
+		})
+	})
+
+	Describe("Write", func() {
+		It("prints the value as json", func() {
+			err := commands.NewOutputWriter(logger, "json").Write(map[string]string{"director_address": "some-address"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal("{\n  \"director_address\": \"some-address\"\n}"))
+		})
+
+		It("prints the value as yaml", func() {
+			err := commands.NewOutputWriter(logger, "yaml").Write(map[string]string{"director_address": "some-address"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(Equal("director_address: some-address"))
+		})
+
+		It("returns an error when the format is text", func() {
+			err := commands.NewOutputWriter(logger, "text").Write(map[string]string{})
+			Expect(err).To(MatchError(`output cannot be marshalled as "text"`))
+		})
+	})
+
+	Describe("ValidateOutputFormat", func() {
+		It("accepts text, json and yaml", func() {
+			Expect(commands.ValidateOutputFormat("text")).To(Succeed())
+			Expect(commands.ValidateOutputFormat("json")).To(Succeed())
+			Expect(commands.ValidateOutputFormat("yaml")).To(Succeed())
+		})
+
+		It("returns an error for any other format", func() {
+			err := commands.ValidateOutputFormat("xml")
+			Expect(err).To(MatchError(`"xml" is an invalid output format, supported values are: [text, json, yaml]`))
+		})
+	})
+
+	Describe("MarshalOutput", func() {
+		It("marshals errors with their code", func() {
+			contents, err := commands.MarshalOutput("json", commands.ErrorOutput{
+				Error: "something went wrong",
+				Code:  "error",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents).To(MatchJSON(`{"error": "something went wrong", "code": "error"}`))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+const (
+	OutputsCommand = "outputs"
+)
+
+type Outputs struct {
+	logger                logger
+	outputWriter          outputWriter
+	stateValidator        stateValidator
+	terraformOutputter    terraformOutputter
+	infrastructureManager infrastructureManager
+}
+
+type terraformOutputter interface {
+	GetAllOutputs(storage.State) (map[string]terraform.Output, error)
+}
+
+func NewOutputs(logger logger, outputWriter outputWriter, stateValidator stateValidator,
+	terraformOutputter terraformOutputter, infrastructureManager infrastructureManager) Outputs {
+	return Outputs{
+		logger:                logger,
+		outputWriter:          outputWriter,
+		stateValidator:        stateValidator,
+		terraformOutputter:    terraformOutputter,
+		infrastructureManager: infrastructureManager,
+	}
+}
+
+func (o Outputs) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := o.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseOutputsFlags(subcommandFlags)
+	return err
+}
+
+func (o Outputs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	reveal, err := parseOutputsFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := o.outputs(state, reveal)
+	if err != nil {
+		return err
+	}
+
+	if !o.outputWriter.IsText() {
+		return o.outputWriter.Write(outputs)
+	}
+
+	var names []string
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		o.logger.Println(fmt.Sprintf("%s: %s", name, formatOutputValue(outputs[name])))
+	}
+
+	return nil
+}
+
+func (o Outputs) outputs(state storage.State, reveal bool) (map[string]interface{}, error) {
+	if state.TFState != "" {
+		terraformOutputs, err := o.terraformOutputter.GetAllOutputs(state)
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+
+		outputs := map[string]interface{}{}
+		for name, output := range terraformOutputs {
+			if output.Sensitive && !reveal {
+				outputs[name] = maskedStateValue
+			} else {
+				outputs[name] = output.Value
+			}
+		}
+		return outputs, nil
+	}
+
+	if state.Stack.Name != "" {
+		stack, err := o.infrastructureManager.Describe(state.Stack.Name)
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+
+		outputs := map[string]interface{}{}
+		for name, value := range stack.Outputs {
+			outputs[name] = value
+		}
+		return outputs, nil
+	}
+
+	return map[string]interface{}{}, errors.New("Could not retrieve outputs, please make sure you are targeting the proper state dir.")
+}
+
+func parseOutputsFlags(subcommandFlags []string) (bool, error) {
+	var reveal bool
+
+	outputsFlags := flags.New("outputs")
+	outputsFlags.Bool(&reveal, "", "reveal", false)
+
+	err := outputsFlags.Parse(subcommandFlags)
+	if err != nil {
+		return false, err
+	}
+
+	return reveal, nil
+}
+
+func formatOutputValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		var values []string
+		for _, element := range typedValue {
+			values = append(values, formatOutputValue(element))
+		}
+		return strings.Join(values, ",")
+	case []string:
+		return strings.Join(typedValue, ",")
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,177 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outputs", func() {
+	var (
+		logger                *fakes.Logger
+		outputWriter          *fakes.OutputWriter
+		stateValidator        *fakes.StateValidator
+		terraformManager      *fakes.TerraformManager
+		infrastructureManager *fakes.InfrastructureManager
+
+		command commands.Outputs
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		infrastructureManager = &fakes.InfrastructureManager{}
+
+		command = commands.NewOutputs(logger, outputWriter, stateValidator, terraformManager, infrastructureManager)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("failed to validate state")
+
+			err := command.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("failed to validate state"))
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.CheckFastFails([]string{"--unknown-flag"}, storage.State{})
+			Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+		})
+	})
+
+	Describe("Execute", func() {
+		Context("when the environment is managed by terraform", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS:    "gcp",
+					TFState: "some-tf-state",
+				}
+				terraformManager.GetAllOutputsCall.Returns.Outputs = map[string]terraform.Output{
+					"network_name":              {Value: "some-network-name"},
+					"external_ip":               {Value: "some-external-ip"},
+					"system_domain_dns_servers": {Value: []interface{}{"name-server-1.", "name-server-2."}},
+				}
+			})
+
+			It("prints every terraform output sorted by name", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(terraformManager.GetAllOutputsCall.Receives.BBLState).To(Equal(state))
+				Expect(infrastructureManager.DescribeCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"external_ip: some-external-ip",
+					"network_name: some-network-name",
+					"system_domain_dns_servers: name-server-1.,name-server-2.",
+				}))
+			})
+
+			It("writes the outputs to the output writer when the output format is not text", func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string]interface{}{
+					"network_name":              "some-network-name",
+					"external_ip":               "some-external-ip",
+					"system_domain_dns_servers": []interface{}{"name-server-1.", "name-server-2."},
+				}))
+			})
+
+			Context("when an output is sensitive", func() {
+				BeforeEach(func() {
+					terraformManager.GetAllOutputsCall.Returns.Outputs = map[string]terraform.Output{
+						"external_ip":                 {Value: "some-external-ip"},
+						"bosh_user_secret_access_key": {Value: "some-secret-access-key", Sensitive: true},
+					}
+				})
+
+				It("masks the output", func() {
+					err := command.Execute(context.Background(), []string{}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+						"bosh_user_secret_access_key: <redacted>",
+						"external_ip: some-external-ip",
+					}))
+				})
+
+				It("prints the output when --reveal is provided", func() {
+					err := command.Execute(context.Background(), []string{"--reveal"}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+						"bosh_user_secret_access_key: some-secret-access-key",
+						"external_ip: some-external-ip",
+					}))
+				})
+			})
+
+			It("returns an error when the terraform outputs cannot be read", func() {
+				terraformManager.GetAllOutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to get outputs"))
+			})
+		})
+
+		Context("when the environment is a legacy cloudformation stack", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS: "aws",
+					Stack: storage.Stack{
+						Name: "some-stack-name",
+					},
+				}
+				infrastructureManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+					Name: "some-stack-name",
+					Outputs: map[string]string{
+						"VPCID":   "some-vpc-id",
+						"BOSHEIP": "some-bosh-eip",
+					},
+				}
+			})
+
+			It("prints every stack output", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(infrastructureManager.DescribeCall.Receives.StackName).To(Equal("some-stack-name"))
+				Expect(terraformManager.GetAllOutputsCall.CallCount).To(Equal(0))
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"BOSHEIP: some-bosh-eip",
+					"VPCID: some-vpc-id",
+				}))
+			})
+
+			It("returns an error when the stack cannot be described", func() {
+				infrastructureManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to describe stack"))
+			})
+		})
+
+		It("returns an error when there is no infrastructure in the state", func() {
+			err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "gcp"})
+			Expect(err).To(MatchError("Could not retrieve outputs, please make sure you are targeting the proper state dir."))
+		})
+	})
+})
@@ -1,33 +1,63 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 const (
 	PrintEnvCommand = "print-env"
+
+	JumpboxPrivateKeyFileName = "jumpbox-private-key"
+
+	BashShell       = "bash"
+	FishShell       = "fish"
+	PowershellShell = "powershell"
+	DotenvShell     = "dotenv"
 )
 
+var Shells = []string{BashShell, FishShell, PowershellShell, DotenvShell}
+
 type PrintEnv struct {
 	stateValidator        stateValidator
 	logger                logger
+	outputWriter          outputWriter
 	terraformManager      terraformManager
 	infrastructureManager infrastructureManager
+	sshKeyGetter          sshKeyGetter
+	stateDir              string
+}
+
+type environmentVariable struct {
+	name   string
+	value  string
+	quoted bool
 }
 
 type envSetter interface {
 	Set(key, value string) error
 }
 
-func NewPrintEnv(logger logger, stateValidator stateValidator, terraformManager terraformManager, infrastructureManager infrastructureManager) PrintEnv {
+func NewPrintEnv(logger logger, outputWriter outputWriter, stateValidator stateValidator, terraformManager terraformManager,
+	infrastructureManager infrastructureManager, sshKeyGetter sshKeyGetter, stateDir string) PrintEnv {
 	return PrintEnv{
 		stateValidator:        stateValidator,
 		logger:                logger,
+		outputWriter:          outputWriter,
 		terraformManager:      terraformManager,
 		infrastructureManager: infrastructureManager,
+		sshKeyGetter:          sshKeyGetter,
+		stateDir:              stateDir,
 	}
 }
 
@@ -40,21 +70,153 @@ func (p PrintEnv) CheckFastFails(subcommandFlags []string, state storage.State)
 	return nil
 }
 
-func (p PrintEnv) Execute(args []string, state storage.State) error {
-	if !state.NoDirector {
-		p.logger.Println(fmt.Sprintf("export BOSH_CLIENT=%s", state.BOSH.DirectorUsername))
-		p.logger.Println(fmt.Sprintf("export BOSH_CLIENT_SECRET=%s", state.BOSH.DirectorPassword))
-		p.logger.Println(fmt.Sprintf("export BOSH_ENVIRONMENT=%s", state.BOSH.DirectorAddress))
-		p.logger.Println(fmt.Sprintf("export BOSH_CA_CERT='%s'", state.BOSH.DirectorSSLCA))
-	} else {
+func (p PrintEnv) Execute(ctx context.Context, args []string, state storage.State) error {
+	var shell string
+
+	printEnvFlags := flags.New("print-env")
+	printEnvFlags.String(&shell, "shell", BashShell)
+
+	err := printEnvFlags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if !isSupportedShell(shell) {
+		return fmt.Errorf("%q is not a supported shell, supported values are: [%s]", shell, strings.Join(Shells, ", "))
+	}
+
+	variables, err := p.environmentVariables(state)
+	if err != nil {
+		return err
+	}
+
+	if !p.outputWriter.IsText() {
+		structuredVariables := map[string]string{}
+		for _, variable := range variables {
+			structuredVariables[variable.name] = variable.value
+		}
+
+		return p.outputWriter.Write(structuredVariables)
+	}
+
+	for _, variable := range variables {
+		p.logger.Println(formatEnvironmentVariable(shell, variable))
+	}
+
+	return nil
+}
+
+func (p PrintEnv) environmentVariables(state storage.State) ([]environmentVariable, error) {
+	if state.NoDirector {
 		directorAddress, err := p.getExternalIP(state)
 		if err != nil {
-			return err
+			return []environmentVariable{}, err
 		}
-		p.logger.Println(fmt.Sprintf("export BOSH_ENVIRONMENT=https://%s:25555", directorAddress))
+
+		return []environmentVariable{
+			{name: "BOSH_ENVIRONMENT", value: fmt.Sprintf("https://%s:25555", directorAddress)},
+		}, nil
 	}
 
-	return nil
+	variables := []environmentVariable{
+		{name: "BOSH_CLIENT", value: state.BOSH.DirectorUsername},
+		{name: "BOSH_CLIENT_SECRET", value: state.BOSH.DirectorPassword},
+		{name: "BOSH_ENVIRONMENT", value: state.BOSH.DirectorAddress},
+		{name: "BOSH_CA_CERT", value: state.BOSH.DirectorSSLCA, quoted: true},
+	}
+
+	var allProxy string
+	if state.Jumpbox.Enabled {
+		jumpboxVariables, err := p.jumpboxVariables(state)
+		if err != nil {
+			return []environmentVariable{}, err
+		}
+		variables = append(variables, jumpboxVariables...)
+		allProxy = jumpboxVariables[0].value
+	}
+
+	credhubVariables, err := credhubVariables(state, allProxy)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	return append(variables, credhubVariables...), nil
+}
+
+func (p PrintEnv) jumpboxVariables(state storage.State) ([]environmentVariable, error) {
+	terraformOutputs, err := p.terraformManager.GetOutputs(state)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	jumpboxURL, ok := terraformOutputs["jumpbox_url"].(string)
+	if !ok || jumpboxURL == "" {
+		return []environmentVariable{}, errors.New("Could not retrieve the jumpbox url, please make sure you are targeting the proper state dir.")
+	}
+
+	jumpboxHost, _, err := net.SplitHostPort(jumpboxURL)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	privateKey, err := p.sshKeyGetter.Get(state)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	privateKeyPath := filepath.Join(p.stateDir, JumpboxPrivateKeyFileName)
+	err = writePrivateKey(privateKeyPath, privateKey)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	return []environmentVariable{
+		{name: "BOSH_ALL_PROXY", value: fmt.Sprintf("ssh+socks5://jumpbox@%s:22?private-key=%s", jumpboxHost, privateKeyPath), quoted: true},
+		{name: "BOSH_GW_HOST", value: jumpboxHost},
+		{name: "BOSH_GW_USER", value: "jumpbox"},
+		{name: "BOSH_GW_PRIVATE_KEY", value: privateKeyPath},
+	}, nil
+}
+
+func credhubVariables(state storage.State, allProxy string) ([]environmentVariable, error) {
+	var variables struct {
+		CredhubAdminClientSecret string `yaml:"credhub_admin_client_secret"`
+		CredhubCA                struct {
+			Certificate string `yaml:"certificate"`
+		} `yaml:"credhub_ca"`
+	}
+
+	err := unmarshal([]byte(state.BOSH.Variables), &variables)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	if variables.CredhubAdminClientSecret == "" {
+		return []environmentVariable{}, nil
+	}
+
+	directorURL, err := url.Parse(state.BOSH.DirectorAddress)
+	if err != nil {
+		return []environmentVariable{}, err
+	}
+
+	caCert := variables.CredhubCA.Certificate
+	if caCert == "" {
+		caCert = state.BOSH.DirectorSSLCA
+	}
+
+	credhubVariables := []environmentVariable{
+		{name: "CREDHUB_SERVER", value: fmt.Sprintf("https://%s:8844", directorURL.Hostname())},
+		{name: "CREDHUB_CLIENT", value: "credhub-admin"},
+		{name: "CREDHUB_SECRET", value: variables.CredhubAdminClientSecret},
+		{name: "CREDHUB_CA_CERT", value: caCert, quoted: true},
+	}
+
+	if allProxy != "" {
+		credhubVariables = append(credhubVariables, environmentVariable{name: "CREDHUB_PROXY", value: allProxy, quoted: true})
+	}
+
+	return credhubVariables, nil
 }
 
 func (p PrintEnv) getExternalIP(state storage.State) (string, error) {
@@ -76,3 +238,44 @@ func (p PrintEnv) getExternalIP(state storage.State) (string, error) {
 
 	return "", errors.New("Could not find external IP for given IAAS")
 }
+
+func writePrivateKey(path, privateKey string) error {
+	err := ioutil.WriteFile(path, []byte(privateKey), os.FileMode(0600))
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(path, os.FileMode(0600))
+}
+
+func isSupportedShell(shell string) bool {
+	for _, supportedShell := range Shells {
+		if shell == supportedShell {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatEnvironmentVariable(shell string, variable environmentVariable) string {
+	switch shell {
+	case FishShell:
+		if variable.quoted {
+			return fmt.Sprintf("set -x %s '%s'", variable.name, variable.value)
+		}
+		return fmt.Sprintf("set -x %s %s", variable.name, variable.value)
+	case PowershellShell:
+		return fmt.Sprintf("$env:%s='%s'", variable.name, strings.Replace(variable.value, "'", "''", -1))
+	case DotenvShell:
+		if variable.quoted {
+			return fmt.Sprintf("%s=\"%s\"", variable.name, strings.Replace(variable.value, "\n", "\\n", -1))
+		}
+		return fmt.Sprintf("%s=%s", variable.name, variable.value)
+	}
+
+	if variable.quoted {
+		return fmt.Sprintf("export %s='%s'", variable.name, variable.value)
+	}
+	return fmt.Sprintf("export %s=%s", variable.name, variable.value)
+}
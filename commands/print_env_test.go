@@ -1,7 +1,11 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -17,6 +21,9 @@ var _ = Describe("PrintEnv", func() {
 		stateValidator        *fakes.StateValidator
 		terraformManager      *fakes.TerraformManager
 		infrastructureManager *fakes.InfrastructureManager
+		outputWriter          *fakes.OutputWriter
+		sshKeyGetter          *fakes.SSHKeyGetter
+		stateDir              string
 		printEnv              commands.PrintEnv
 		state                 storage.State
 	)
@@ -26,6 +33,13 @@ var _ = Describe("PrintEnv", func() {
 		stateValidator = &fakes.StateValidator{}
 		terraformManager = &fakes.TerraformManager{}
 		infrastructureManager = &fakes.InfrastructureManager{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		sshKeyGetter = &fakes.SSHKeyGetter{}
+
+		var err error
+		stateDir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
 
 		state = storage.State{
 			BOSH: storage.BOSH{
@@ -36,7 +50,11 @@ var _ = Describe("PrintEnv", func() {
 			},
 		}
 
-		printEnv = commands.NewPrintEnv(logger, stateValidator, terraformManager, infrastructureManager)
+		printEnv = commands.NewPrintEnv(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, sshKeyGetter, stateDir)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(stateDir)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -49,7 +67,7 @@ var _ = Describe("PrintEnv", func() {
 
 	Describe("Execute", func() {
 		It("prints the correct environment variables for the bosh cli", func() {
-			err := printEnv.Execute([]string{}, state)
+			err := printEnv.Execute(context.Background(), []string{}, state)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("export BOSH_CLIENT=some-director-username"))
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("export BOSH_CLIENT_SECRET=some-director-password"))
@@ -57,6 +75,207 @@ var _ = Describe("PrintEnv", func() {
 			Expect(logger.PrintlnCall.Messages).To(ContainElement("export BOSH_ENVIRONMENT=some-director-address"))
 		})
 
+		Context("when a shell is provided", func() {
+			It("prints the environment variables for fish", func() {
+				err := printEnv.Execute(context.Background(), []string{"--shell", "fish"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"set -x BOSH_CLIENT some-director-username",
+					"set -x BOSH_CLIENT_SECRET some-director-password",
+					"set -x BOSH_ENVIRONMENT some-director-address",
+					"set -x BOSH_CA_CERT 'some-director-ca-cert'",
+				}))
+			})
+
+			It("prints the environment variables for powershell", func() {
+				err := printEnv.Execute(context.Background(), []string{"--shell", "powershell"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"$env:BOSH_CLIENT='some-director-username'",
+					"$env:BOSH_CLIENT_SECRET='some-director-password'",
+					"$env:BOSH_ENVIRONMENT='some-director-address'",
+					"$env:BOSH_CA_CERT='some-director-ca-cert'",
+				}))
+			})
+
+			It("prints the environment variables as a dotenv file", func() {
+				state.BOSH.DirectorSSLCA = "some-director-ca-cert\nsecond-line"
+
+				err := printEnv.Execute(context.Background(), []string{"--shell", "dotenv"}, state)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"BOSH_CLIENT=some-director-username",
+					"BOSH_CLIENT_SECRET=some-director-password",
+					"BOSH_ENVIRONMENT=some-director-address",
+					`BOSH_CA_CERT="some-director-ca-cert\nsecond-line"`,
+				}))
+			})
+
+			It("returns an error when the shell is not supported", func() {
+				err := printEnv.Execute(context.Background(), []string{"--shell", "tcsh"}, state)
+				Expect(err).To(MatchError(`"tcsh" is not a supported shell, supported values are: [bash, fish, powershell, dotenv]`))
+			})
+
+			It("returns an error when the flags cannot be parsed", func() {
+				err := printEnv.Execute(context.Background(), []string{"--unknown-flag"}, state)
+				Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+			})
+		})
+
+		Context("when the director is behind a jumpbox", func() {
+			BeforeEach(func() {
+				state.IAAS = "gcp"
+				state.Jumpbox.Enabled = true
+				state.BOSH.DirectorAddress = "https://10.0.0.6:25555"
+				terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+					"jumpbox_url": "some-jumpbox-ip:22",
+				}
+				sshKeyGetter.GetCall.Returns.PrivateKey = "some-jumpbox-private-key"
+			})
+
+			It("writes the jumpbox private key to the state dir", func() {
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sshKeyGetter.GetCall.Receives.State).To(Equal(state))
+
+				privateKeyPath := filepath.Join(stateDir, "jumpbox-private-key")
+				privateKey, err := ioutil.ReadFile(privateKeyPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(privateKey)).To(Equal("some-jumpbox-private-key"))
+
+				fileInfo, err := os.Stat(privateKeyPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fileInfo.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			})
+
+			It("prints the proxy and gateway environment variables", func() {
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				privateKeyPath := filepath.Join(stateDir, "jumpbox-private-key")
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{
+					"export BOSH_CLIENT=some-director-username",
+					"export BOSH_CLIENT_SECRET=some-director-password",
+					"export BOSH_ENVIRONMENT=https://10.0.0.6:25555",
+					"export BOSH_CA_CERT='some-director-ca-cert'",
+					"export BOSH_ALL_PROXY='ssh+socks5://jumpbox@some-jumpbox-ip:22?private-key=" + privateKeyPath + "'",
+					"export BOSH_GW_HOST=some-jumpbox-ip",
+					"export BOSH_GW_USER=jumpbox",
+					"export BOSH_GW_PRIVATE_KEY=" + privateKeyPath,
+				}))
+			})
+
+			Context("failure cases", func() {
+				It("returns an error when the terraform outputs cannot be read", func() {
+					terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get terraform output")
+					err := printEnv.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError("failed to get terraform output"))
+				})
+
+				It("returns an error when the jumpbox url is missing", func() {
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{}
+					err := printEnv.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError("Could not retrieve the jumpbox url, please make sure you are targeting the proper state dir."))
+				})
+
+				It("returns an error when the jumpbox url is not a host and port", func() {
+					terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+						"jumpbox_url": "some-jumpbox-ip",
+					}
+					err := printEnv.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError(ContainSubstring("missing port in address")))
+				})
+
+				It("returns an error when the ssh key getter fails", func() {
+					sshKeyGetter.GetCall.Returns.Error = errors.New("failed to get ssh key")
+					err := printEnv.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError("failed to get ssh key"))
+				})
+
+				It("returns an error when the private key cannot be written", func() {
+					printEnv = commands.NewPrintEnv(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, sshKeyGetter, filepath.Join(stateDir, "missing-dir"))
+					err := printEnv.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+		})
+
+		Context("when the director has credhub enabled", func() {
+			BeforeEach(func() {
+				state.BOSH.DirectorAddress = "https://10.0.0.6:25555"
+				state.BOSH.Variables = `credhub_admin_client_secret: some-credhub-secret
+credhub_ca:
+  certificate: some-credhub-ca-cert
+`
+			})
+
+			It("prints the credhub environment variables", func() {
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_SERVER=https://10.0.0.6:8844"))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_CLIENT=credhub-admin"))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_SECRET=some-credhub-secret"))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_CA_CERT='some-credhub-ca-cert'"))
+				Expect(logger.PrintlnCall.Messages).NotTo(ContainElement(ContainSubstring("CREDHUB_PROXY")))
+			})
+
+			It("uses the director CA when there is no credhub CA", func() {
+				state.BOSH.Variables = "credhub_admin_client_secret: some-credhub-secret\n"
+
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_CA_CERT='some-director-ca-cert'"))
+			})
+
+			It("proxies credhub through the jumpbox", func() {
+				state.Jumpbox.Enabled = true
+				terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+					"jumpbox_url": "some-jumpbox-ip:22",
+				}
+
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				privateKeyPath := filepath.Join(stateDir, "jumpbox-private-key")
+				Expect(logger.PrintlnCall.Messages).To(ContainElement("export CREDHUB_PROXY='ssh+socks5://jumpbox@some-jumpbox-ip:22?private-key=" + privateKeyPath + "'"))
+			})
+
+			It("returns an error when the director variables cannot be parsed", func() {
+				state.BOSH.Variables = "%%%"
+
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError(ContainSubstring("yaml")))
+			})
+		})
+
+		Context("when the output format is not text", func() {
+			BeforeEach(func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+			})
+
+			It("writes the environment variables to the output writer", func() {
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string]string{
+					"BOSH_CLIENT":        "some-director-username",
+					"BOSH_CLIENT_SECRET": "some-director-password",
+					"BOSH_ENVIRONMENT":   "some-director-address",
+					"BOSH_CA_CERT":       "some-director-ca-cert",
+				}))
+			})
+
+			It("returns an error when the output writer fails", func() {
+				outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+				err := printEnv.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to write"))
+			})
+		})
+
 		Context("when print-env is called on a bbl env with no director", func() {
 			Context("aws", func() {
 				It("prints only the BOSH_ENVIRONMENT", func() {
@@ -66,7 +285,7 @@ var _ = Describe("PrintEnv", func() {
 						},
 					}
 
-					err := printEnv.Execute([]string{}, storage.State{
+					err := printEnv.Execute(context.Background(), []string{}, storage.State{
 						IAAS:       "aws",
 						NoDirector: true,
 					})
@@ -83,7 +302,7 @@ var _ = Describe("PrintEnv", func() {
 						"external_ip": "some-external-ip",
 					}
 
-					err := printEnv.Execute([]string{}, storage.State{
+					err := printEnv.Execute(context.Background(), []string{}, storage.State{
 						IAAS:       "gcp",
 						NoDirector: true,
 					})
@@ -99,7 +318,7 @@ var _ = Describe("PrintEnv", func() {
 		Context("failure cases", func() {
 			It("returns an error when the terraform outputter fails", func() {
 				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get terraform output")
-				err := printEnv.Execute([]string{}, storage.State{
+				err := printEnv.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
 				})
@@ -108,7 +327,7 @@ var _ = Describe("PrintEnv", func() {
 
 			It("returns an error when the infrastructure manager fails to describe stack", func() {
 				infrastructureManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
-				err := printEnv.Execute([]string{}, storage.State{
+				err := printEnv.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "aws",
 					NoDirector: true,
 				})
@@ -116,7 +335,7 @@ var _ = Describe("PrintEnv", func() {
 			})
 
 			It("returns an error when the external ip cannot be found for a given IAAS", func() {
-				err := printEnv.Execute([]string{}, storage.State{
+				err := printEnv.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "lol",
 					NoDirector: true,
 				})
@@ -1,24 +1,61 @@
 package commands
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 const (
 	RotateCommand = "rotate"
 )
 
 type Rotate struct {
-	stateStore     stateStore
-	keyPairManager keyPairManager
-	boshManager    boshManager
-	stateValidator stateValidator
+	stateStore               stateStore
+	keyPairManager           keyPairManager
+	boshManager              boshManager
+	stateValidator           stateValidator
+	credentialRotator        credentialRotator
+	awsRotateIAASCredentials awsRotateIAASCredentials
+	gcpRotateIAASCredentials gcpRotateIAASCredentials
+	logger                   logger
+}
+
+type credentialRotator interface {
+	RotateCredentials(storage.State, bool) (storage.State, error)
+	BeginCARotation(storage.State) (storage.State, error)
+	CompleteCARotation(storage.State) (storage.State, error)
+}
+
+type awsRotateIAASCredentials interface {
+	Execute(context.Context, storage.State) error
 }
 
-func NewRotate(stateStore stateStore, keyPairManager keyPairManager, boshManager boshManager, stateValidator stateValidator) Rotate {
+type gcpRotateIAASCredentials interface {
+	Execute(context.Context, storage.State) error
+}
+
+type rotateConfig struct {
+	directorCredentials bool
+	agentCredentials    bool
+	directorCA          bool
+	iaasCredentials     bool
+}
+
+func NewRotate(stateStore stateStore, keyPairManager keyPairManager, boshManager boshManager, stateValidator stateValidator,
+	credentialRotator credentialRotator, awsRotateIAASCredentials awsRotateIAASCredentials, gcpRotateIAASCredentials gcpRotateIAASCredentials,
+	logger logger) Rotate {
 	return Rotate{
-		stateStore:     stateStore,
-		keyPairManager: keyPairManager,
-		boshManager:    boshManager,
-		stateValidator: stateValidator,
+		stateStore:               stateStore,
+		keyPairManager:           keyPairManager,
+		boshManager:              boshManager,
+		stateValidator:           stateValidator,
+		credentialRotator:        credentialRotator,
+		awsRotateIAASCredentials: awsRotateIAASCredentials,
+		gcpRotateIAASCredentials: gcpRotateIAASCredentials,
+		logger:                   logger,
 	}
 }
 
@@ -28,11 +65,65 @@ func (r Rotate) CheckFastFails(subcommandFlags []string, state storage.State) er
 		return err
 	}
 
+	config, err := parseRotateFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	selected := 0
+	for _, flag := range []bool{config.directorCredentials, config.directorCA, config.iaasCredentials} {
+		if flag {
+			selected++
+		}
+	}
+
+	if selected > 1 {
+		return errors.New("only one of --director-credentials, --director-ca and --iaas-credentials can be used at a time")
+	}
+
+	if config.agentCredentials && !config.directorCredentials {
+		return errors.New("--agent-credentials can only be used with --director-credentials")
+	}
+
+	if selected == 1 && state.NoDirector {
+		return errors.New("--director-credentials, --director-ca and --iaas-credentials require a director")
+	}
+
+	if config.iaasCredentials && state.IAAS == "aws" && state.TFState == "" {
+		return errors.New("--iaas-credentials requires an environment created with --terraform")
+	}
+
+	if config.iaasCredentials && state.AWS.IAMInstanceProfile {
+		return errors.New("--iaas-credentials is not needed for a director that uses an IAM instance profile")
+	}
+
 	return nil
 }
 
-func (r Rotate) Execute(args []string, state storage.State) error {
-	state, err := r.keyPairManager.Rotate(state)
+func (r Rotate) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	config, err := parseRotateFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	if config.iaasCredentials {
+		if state.IAAS == "aws" {
+			return r.awsRotateIAASCredentials.Execute(ctx, state)
+		}
+
+		return r.gcpRotateIAASCredentials.Execute(ctx, state)
+	}
+
+	switch {
+	case config.directorCredentials:
+		state, err = r.credentialRotator.RotateCredentials(state, config.agentCredentials)
+	case config.directorCA && len(state.BOSH.PendingCAs) == 0:
+		state, err = r.credentialRotator.BeginCARotation(state)
+	case config.directorCA:
+		state, err = r.credentialRotator.CompleteCARotation(state)
+	default:
+		state, err = r.keyPairManager.Rotate(state)
+	}
 	if err != nil {
 		return err
 	}
@@ -43,7 +134,7 @@ func (r Rotate) Execute(args []string, state storage.State) error {
 	}
 
 	if !state.NoDirector {
-		state, err = r.boshManager.Create(state)
+		state, err = r.boshManager.Create(ctx, state)
 		if err != nil {
 			return err
 		}
@@ -54,5 +145,30 @@ func (r Rotate) Execute(args []string, state storage.State) error {
 		}
 	}
 
+	if config.agentCredentials {
+		r.logger.Println("the agents on deployed VMs still use the old credentials; recreate every deployment with `bosh -d <deployment> recreate` so they can reach the director again")
+	}
+
+	if config.directorCA && len(state.BOSH.PendingCAs) > 0 {
+		r.logger.Println("the director now trusts both the old and the new CA; recreate the deployed VMs so they trust the new CA as well, then run `bbl rotate --director-ca` again to remove the old CA")
+	}
+
 	return nil
 }
+
+func parseRotateFlags(subcommandFlags []string) (rotateConfig, error) {
+	var config rotateConfig
+
+	rotateFlags := flags.New("rotate")
+	rotateFlags.Bool(&config.directorCredentials, "", "director-credentials", false)
+	rotateFlags.Bool(&config.agentCredentials, "", "agent-credentials", false)
+	rotateFlags.Bool(&config.directorCA, "", "director-ca", false)
+	rotateFlags.Bool(&config.iaasCredentials, "", "iaas-credentials", false)
+
+	err := rotateFlags.Parse(subcommandFlags)
+	if err != nil {
+		return rotateConfig{}, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type directorKey interface {
+	// description names the key in the steps that are logged.
+	description() string
+	// current returns what a new key is created for, the key that the
+	// director uses and whether terraform created that key.
+	current(state storage.State) (owner string, key string, terraformKey bool, err error)
+	create(state storage.State, owner string) (storage.State, error)
+	previous(state storage.State) string
+	setPrevious(state storage.State, key string) storage.State
+	delete(key string) error
+}
+
+type directorKeyRotation struct {
+	terraformManager terraformManager
+	boshManager      boshManager
+	stateStore       stateStore
+	logger           logger
+}
+
+// rotate replaces the key of the director with one that bbl manages. The
+// previous key is kept in the state until the director uses the new one, so
+// that a rotation that failed deletes it when run again. When the previous key
+// was created by terraform, terraform deletes it, because the template leaves
+// it out from now on.
+func (r directorKeyRotation) rotate(ctx context.Context, key directorKey, state storage.State) error {
+	terraformKey := false
+	if key.previous(state) == "" {
+		owner, currentKey, createdByTerraform, err := key.current(state)
+		if err != nil {
+			return err
+		}
+		terraformKey = createdByTerraform
+
+		r.logger.Step("creating a new " + key.description())
+		state, err = key.create(state, owner)
+		if err != nil {
+			return err
+		}
+
+		state = key.setPrevious(state, currentKey)
+		if err := r.stateStore.Set(state); err != nil {
+			return err
+		}
+	} else {
+		r.logger.Step("resuming the rotation of the " + key.description())
+	}
+
+	state, err := r.boshManager.Create(ctx, state)
+	if err != nil {
+		return handleBOSHManagerError(err, r.stateStore)
+	}
+
+	if err := r.stateStore.Set(state); err != nil {
+		return err
+	}
+
+	r.logger.Step("deleting the previous " + key.description())
+	previousKey := key.previous(state)
+	state = key.setPrevious(state, "")
+	if terraformKey {
+		state, err = r.terraformManager.Apply(ctx, state)
+		if err != nil {
+			return handleTerraformError(err, r.stateStore)
+		}
+
+		return r.stateStore.Set(state)
+	}
+
+	// A rotation that resumes deletes the key itself, even one that terraform
+	// created. Terraform forgets it on its next apply.
+	err = key.delete(previousKey)
+	if err != nil {
+		return err
+	}
+
+	return r.stateStore.Set(state)
+}
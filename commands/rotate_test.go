@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -17,6 +18,10 @@ var _ = Describe("Rotate", func() {
 		keyPairManager *fakes.KeyPairManager
 		boshManager    *fakes.BOSHManager
 		stateValidator *fakes.StateValidator
+		rotator        *fakes.CredentialRotator
+		awsRotateIAAS  *fakes.AWSRotateIAASCredentials
+		gcpRotateIAAS  *fakes.GCPRotateIAASCredentials
+		logger         *fakes.Logger
 
 		command commands.Rotate
 
@@ -28,8 +33,12 @@ var _ = Describe("Rotate", func() {
 		keyPairManager = &fakes.KeyPairManager{}
 		boshManager = &fakes.BOSHManager{}
 		stateValidator = &fakes.StateValidator{}
+		rotator = &fakes.CredentialRotator{}
+		awsRotateIAAS = &fakes.AWSRotateIAASCredentials{}
+		gcpRotateIAAS = &fakes.GCPRotateIAASCredentials{}
+		logger = &fakes.Logger{}
 
-		command = commands.NewRotate(stateStore, keyPairManager, boshManager, stateValidator)
+		command = commands.NewRotate(stateStore, keyPairManager, boshManager, stateValidator, rotator, awsRotateIAAS, gcpRotateIAAS, logger)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -38,6 +47,40 @@ var _ = Describe("Rotate", func() {
 			err := command.CheckFastFails([]string{}, incomingState)
 			Expect(err).To(MatchError("state validator failed"))
 		})
+
+		It("returns an error when the credential flags are used without a director", func() {
+			err := command.CheckFastFails([]string{"--director-ca"}, storage.State{NoDirector: true})
+			Expect(err).To(MatchError("--director-credentials, --director-ca and --iaas-credentials require a director"))
+		})
+
+		It("returns an error when more than one credential flag is used", func() {
+			err := command.CheckFastFails([]string{"--director-credentials", "--iaas-credentials"}, storage.State{})
+			Expect(err).To(MatchError("only one of --director-credentials, --director-ca and --iaas-credentials can be used at a time"))
+		})
+
+		It("returns an error when --iaas-credentials is used with a cloudformation environment", func() {
+			err := command.CheckFastFails([]string{"--iaas-credentials"}, storage.State{IAAS: "aws"})
+			Expect(err).To(MatchError("--iaas-credentials requires an environment created with --terraform"))
+		})
+
+		It("returns an error when --iaas-credentials is used with a director that uses an IAM instance profile", func() {
+			err := command.CheckFastFails([]string{"--iaas-credentials"}, storage.State{
+				IAAS:    "aws",
+				TFState: "some-tf-state",
+				AWS:     storage.AWS{IAMInstanceProfile: true},
+			})
+			Expect(err).To(MatchError("--iaas-credentials is not needed for a director that uses an IAM instance profile"))
+		})
+
+		It("returns an error when --agent-credentials is used without --director-credentials", func() {
+			err := command.CheckFastFails([]string{"--agent-credentials"}, storage.State{})
+			Expect(err).To(MatchError("--agent-credentials can only be used with --director-credentials"))
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := command.CheckFastFails([]string{"--unknown-flag"}, storage.State{})
+			Expect(err).To(MatchError(ContainSubstring("flag provided but not defined")))
+		})
 	})
 
 	Describe("Execute", func() {
@@ -71,7 +114,7 @@ var _ = Describe("Rotate", func() {
 		})
 
 		It("rotates the keys", func() {
-			err := command.Execute([]string{}, incomingState)
+			err := command.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(keyPairManager.RotateCall.CallCount).To(Equal(1))
@@ -87,7 +130,7 @@ var _ = Describe("Rotate", func() {
 		})
 
 		It("redeploys bosh", func() {
-			err := command.Execute([]string{}, incomingState)
+			err := command.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(boshManager.CreateCall.CallCount).To(Equal(1))
@@ -118,7 +161,7 @@ var _ = Describe("Rotate", func() {
 			})
 
 			It("does not deploy bosh", func() {
-				err := command.Execute([]string{}, incomingState)
+				err := command.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
@@ -126,28 +169,169 @@ var _ = Describe("Rotate", func() {
 			})
 		})
 
+		Context("when --iaas-credentials is passed", func() {
+			It("rotates the access key of the director on aws", func() {
+				incomingState.IAAS = "aws"
+
+				err := command.Execute(context.Background(), []string{"--iaas-credentials"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(awsRotateIAAS.ExecuteCall.CallCount).To(Equal(1))
+				Expect(awsRotateIAAS.ExecuteCall.Receives.State).To(Equal(incomingState))
+				Expect(gcpRotateIAAS.ExecuteCall.CallCount).To(Equal(0))
+				Expect(keyPairManager.RotateCall.CallCount).To(Equal(0))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+			})
+
+			It("rotates the service account key of the director on gcp", func() {
+				incomingState.IAAS = "gcp"
+
+				err := command.Execute(context.Background(), []string{"--iaas-credentials"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpRotateIAAS.ExecuteCall.CallCount).To(Equal(1))
+				Expect(gcpRotateIAAS.ExecuteCall.Receives.State).To(Equal(incomingState))
+				Expect(awsRotateIAAS.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the rotation fails", func() {
+				incomingState.IAAS = "gcp"
+				gcpRotateIAAS.ExecuteCall.Returns.Error = errors.New("failed to rotate")
+
+				err := command.Execute(context.Background(), []string{"--iaas-credentials"}, incomingState)
+				Expect(err).To(MatchError("failed to rotate"))
+			})
+		})
+
+		Context("when --director-credentials is passed", func() {
+			BeforeEach(func() {
+				rotator.RotateCredentialsCall.Returns.State = storage.State{
+					BOSH: storage.BOSH{Variables: "some-rotated-variables"},
+				}
+			})
+
+			It("regenerates the director credentials instead of the keypair", func() {
+				err := command.Execute(context.Background(), []string{"--director-credentials"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(keyPairManager.RotateCall.CallCount).To(Equal(0))
+				Expect(rotator.RotateCredentialsCall.CallCount).To(Equal(1))
+				Expect(rotator.RotateCredentialsCall.Receives.State).To(Equal(incomingState))
+				Expect(rotator.RotateCredentialsCall.Receives.RotateAgentCredentials).To(BeFalse())
+
+				Expect(stateStore.SetCall.Receives[0].State.BOSH.Variables).To(Equal("some-rotated-variables"))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(1))
+				Expect(boshManager.CreateCall.Receives.State.BOSH.Variables).To(Equal("some-rotated-variables"))
+				Expect(stateStore.SetCall.CallCount).To(Equal(2))
+			})
+
+			It("regenerates the agent credentials and tells the user to recreate the deployed VMs", func() {
+				err := command.Execute(context.Background(), []string{"--director-credentials", "--agent-credentials"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(rotator.RotateCredentialsCall.Receives.RotateAgentCredentials).To(BeTrue())
+				Expect(boshManager.CreateCall.CallCount).To(Equal(1))
+				Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring("recreate every deployment"))
+			})
+
+			It("returns an error when the rotation fails", func() {
+				rotator.RotateCredentialsCall.Returns.Error = errors.New("failed to rotate credentials")
+				err := command.Execute(context.Background(), []string{"--director-credentials"}, incomingState)
+				Expect(err).To(MatchError("failed to rotate credentials"))
+				Expect(boshManager.CreateCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when --director-ca is passed", func() {
+			var pendingState storage.State
+
+			BeforeEach(func() {
+				pendingState = storage.State{
+					BOSH: storage.BOSH{
+						Variables: "some-bundled-variables",
+						PendingCAs: map[string]storage.PendingCA{
+							"default_ca": {Certificate: "some-new-ca", PrivateKey: "some-new-ca-key"},
+						},
+					},
+				}
+				rotator.BeginCARotationCall.Returns.State = pendingState
+				boshManager.CreateCall.Returns.State = pendingState
+			})
+
+			It("begins the CA rotation and tells the user how to finish it", func() {
+				err := command.Execute(context.Background(), []string{"--director-ca"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(keyPairManager.RotateCall.CallCount).To(Equal(0))
+				Expect(rotator.BeginCARotationCall.CallCount).To(Equal(1))
+				Expect(rotator.BeginCARotationCall.Receives.State).To(Equal(incomingState))
+				Expect(rotator.CompleteCARotationCall.CallCount).To(Equal(0))
+
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(pendingState))
+				Expect(boshManager.CreateCall.Receives.State).To(Equal(pendingState))
+				Expect(logger.PrintlnCall.Messages).To(ContainElement(ContainSubstring("run `bbl rotate --director-ca` again")))
+			})
+
+			Context("when a CA rotation is in progress", func() {
+				BeforeEach(func() {
+					incomingState = pendingState
+					rotator.CompleteCARotationCall.Returns.State = storage.State{
+						BOSH: storage.BOSH{Variables: "some-new-variables"},
+					}
+					boshManager.CreateCall.Returns.State = storage.State{
+						BOSH: storage.BOSH{Variables: "some-new-variables"},
+					}
+				})
+
+				It("completes the CA rotation", func() {
+					err := command.Execute(context.Background(), []string{"--director-ca"}, incomingState)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(rotator.BeginCARotationCall.CallCount).To(Equal(0))
+					Expect(rotator.CompleteCARotationCall.CallCount).To(Equal(1))
+					Expect(rotator.CompleteCARotationCall.Receives.State).To(Equal(pendingState))
+
+					Expect(boshManager.CreateCall.Receives.State.BOSH.Variables).To(Equal("some-new-variables"))
+					Expect(stateStore.SetCall.CallCount).To(Equal(2))
+					Expect(logger.PrintlnCall.Messages).To(BeEmpty())
+				})
+
+				It("returns an error when completing the rotation fails", func() {
+					rotator.CompleteCARotationCall.Returns.Error = errors.New("failed to complete")
+					err := command.Execute(context.Background(), []string{"--director-ca"}, incomingState)
+					Expect(err).To(MatchError("failed to complete"))
+				})
+			})
+
+			It("returns an error when beginning the rotation fails", func() {
+				rotator.BeginCARotationCall.Returns.Error = errors.New("failed to begin")
+				err := command.Execute(context.Background(), []string{"--director-ca"}, incomingState)
+				Expect(err).To(MatchError("failed to begin"))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when key pair manager rotate fails", func() {
 				keyPairManager.RotateCall.Returns.Error = errors.New("failed to rotate")
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("failed to rotate"))
 			})
 
 			It("returns an error when stateStore set fails", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{errors.New("failed to set")}}
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("failed to set"))
 			})
 
 			It("returns an error when boshManager create fails", func() {
 				boshManager.CreateCall.Returns.Error = errors.New("failed to create")
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("failed to create"))
 			})
 
 			It("returns an error when stateStore set fails", func() {
 				stateStore.SetCall.Returns = []fakes.SetCallReturn{{}, {errors.New("failed to set")}}
-				err := command.Execute([]string{}, storage.State{})
+				err := command.Execute(context.Background(), []string{}, storage.State{})
 				Expect(err).To(MatchError("failed to set"))
 			})
 		})
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -13,6 +14,7 @@ const (
 
 type SSHKey struct {
 	logger         logger
+	outputWriter   outputWriter
 	stateValidator stateValidator
 	sshKeyGetter   sshKeyGetter
 }
@@ -23,9 +25,10 @@ type sshKeyGetter interface {
 
 var unmarshal = yaml.Unmarshal
 
-func NewSSHKey(logger logger, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
+func NewSSHKey(logger logger, outputWriter outputWriter, stateValidator stateValidator, sshKeyGetter sshKeyGetter) SSHKey {
 	return SSHKey{
 		logger:         logger,
+		outputWriter:   outputWriter,
 		stateValidator: stateValidator,
 		sshKeyGetter:   sshKeyGetter,
 	}
@@ -40,7 +43,7 @@ func (s SSHKey) CheckFastFails(subcommandFlags []string, state storage.State) er
 	return nil
 }
 
-func (s SSHKey) Execute(subcommandFlags []string, state storage.State) error {
+func (s SSHKey) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	privateKey, err := s.sshKeyGetter.Get(state)
 	if err != nil {
 		return err
@@ -50,6 +53,12 @@ func (s SSHKey) Execute(subcommandFlags []string, state storage.State) error {
 		return errors.New("Could not retrieve the ssh key, please make sure you are targeting the proper state dir.")
 	}
 
+	if !s.outputWriter.IsText() {
+		return s.outputWriter.Write(map[string]string{
+			"private_key": privateKey,
+		})
+	}
+
 	s.logger.Println(privateKey)
 
 	return nil
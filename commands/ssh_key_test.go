@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
@@ -19,6 +20,7 @@ var _ = Describe("SSHKey", func() {
 		stateValidator *fakes.StateValidator
 		logger         *fakes.Logger
 		sshKeyGetter   *fakes.SSHKeyGetter
+		outputWriter   *fakes.OutputWriter
 	)
 
 	BeforeEach(func() {
@@ -31,7 +33,10 @@ var _ = Describe("SSHKey", func() {
 		sshKeyGetter = &fakes.SSHKeyGetter{}
 		sshKeyGetter.GetCall.Returns.PrivateKey = "some-private-ssh-key"
 
-		sshKeyCommand = commands.NewSSHKey(logger, stateValidator, sshKeyGetter)
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+
+		sshKeyCommand = commands.NewSSHKey(logger, outputWriter, stateValidator, sshKeyGetter)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -44,7 +49,7 @@ var _ = Describe("SSHKey", func() {
 
 	Describe("Execute", func() {
 		It("prints the private ssh key of the jumpbox user", func() {
-			err := sshKeyCommand.Execute([]string{}, incomingState)
+			err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(sshKeyGetter.GetCall.CallCount).To(Equal(1))
@@ -52,16 +57,38 @@ var _ = Describe("SSHKey", func() {
 			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-private-ssh-key"}))
 		})
 
+		Context("when the output format is not text", func() {
+			BeforeEach(func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+			})
+
+			It("writes the private ssh key to the output writer", func() {
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				Expect(outputWriter.WriteCall.Receives.Value).To(Equal(map[string]string{
+					"private_key": "some-private-ssh-key",
+				}))
+			})
+
+			It("returns an error when the output writer fails", func() {
+				outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
+				Expect(err).To(MatchError("failed to write"))
+			})
+		})
+
 		Context("failure cases", func() {
 			It("returns an error when the ssh key getter fails", func() {
 				sshKeyGetter.GetCall.Returns.Error = errors.New("jumpbox ssh key getter failed")
-				err := sshKeyCommand.Execute([]string{}, incomingState)
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).To(MatchError("jumpbox ssh key getter failed"))
 			})
 
 			It("returns an error when the ssh private key is empty", func() {
 				sshKeyGetter.GetCall.Returns.PrivateKey = ""
-				err := sshKeyCommand.Execute([]string{}, incomingState)
+				err := sshKeyCommand.Execute(context.Background(), []string{}, incomingState)
 				Expect(err).To(MatchError("Could not retrieve the ssh key, please make sure you are targeting the proper state dir."))
 			})
 		})
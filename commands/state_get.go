@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	StateCommand = "state"
+
+	maskedStateValue = "<redacted>"
+)
+
+// secretStateFields are masked by name wherever they appear in the state, in
+// addition to any field whose name mentions a password, secret or key, so that
+// new secret fields are masked without being listed here.
+var secretStateFields = []string{
+	"variables",
+	"manifest",
+	"state",
+	"credentials",
+	"tfstate",
+}
+
+type StateGet struct {
+	logger         logger
+	outputWriter   outputWriter
+	stateValidator stateValidator
+}
+
+func NewStateGet(logger logger, outputWriter outputWriter, stateValidator stateValidator) StateGet {
+	return StateGet{
+		logger:         logger,
+		outputWriter:   outputWriter,
+		stateValidator: stateValidator,
+	}
+}
+
+func (s StateGet) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s StateGet) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	if len(subcommandFlags) == 0 || subcommandFlags[0] != "get" {
+		return errors.New("Invalid usage: bbl state get [<path>] [--reveal]")
+	}
+
+	var (
+		reveal    bool
+		arguments []string
+		options   []string
+	)
+	for _, argument := range subcommandFlags[1:] {
+		if strings.HasPrefix(argument, "-") {
+			options = append(options, argument)
+		} else {
+			arguments = append(arguments, argument)
+		}
+	}
+
+	stateFlags := flags.New("state")
+	stateFlags.Bool(&reveal, "", "reveal", false)
+
+	err := stateFlags.Parse(options)
+	if err != nil {
+		return err
+	}
+
+	if len(arguments) > 1 {
+		return errors.New("Invalid usage: bbl state get accepts a single path")
+	}
+
+	var path string
+	if len(arguments) == 1 {
+		path = arguments[0]
+	}
+
+	value, err := getStateValue(state, path, reveal)
+	if err != nil {
+		return err
+	}
+
+	if !s.outputWriter.IsText() {
+		return s.outputWriter.Write(value)
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		s.logger.Println(typedValue)
+	case map[string]interface{}, []interface{}:
+		contents, err := json.MarshalIndent(typedValue, "", "  ")
+		if err != nil {
+			// not tested
+			return err
+		}
+		s.logger.Println(string(contents))
+	default:
+		s.logger.Println(fmt.Sprintf("%v", typedValue))
+	}
+
+	return nil
+}
+
+func getStateValue(state storage.State, path string, reveal bool) (interface{}, error) {
+	contents, err := json.Marshal(state)
+	if err != nil {
+		// not tested
+		return nil, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal(contents, &value)
+	if err != nil {
+		// not tested
+		return nil, err
+	}
+
+	if !reveal {
+		value = maskStateValue(value, "")
+	}
+
+	if path == "" {
+		return value, nil
+	}
+
+	var currentPath []string
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%q is not a valid state path, %q has no fields", path, strings.Join(currentPath, "."))
+		}
+
+		value, ok = fields[key]
+		if !ok {
+			return nil, fmt.Errorf("%q is not a valid state path, valid fields are: [%s]", path, strings.Join(stateFieldNames(currentPath, fields), ", "))
+		}
+
+		currentPath = append(currentPath, key)
+	}
+
+	return value, nil
+}
+
+func maskStateValue(value interface{}, key string) interface{} {
+	if isSecretStateField(key) {
+		if isEmptyStateValue(value) {
+			return value
+		}
+		return maskedStateValue
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		masked := map[string]interface{}{}
+		for fieldKey, fieldValue := range typedValue {
+			masked[fieldKey] = maskStateValue(fieldValue, fieldKey)
+		}
+		return masked
+	case []interface{}:
+		masked := []interface{}{}
+		for _, element := range typedValue {
+			masked = append(masked, maskStateValue(element, ""))
+		}
+		return masked
+	}
+
+	return value
+}
+
+func isSecretStateField(key string) bool {
+	name := strings.ToLower(key)
+
+	for _, field := range secretStateFields {
+		if name == field {
+			return true
+		}
+	}
+
+	if strings.Contains(name, "password") || strings.Contains(name, "secret") {
+		return true
+	}
+
+	return strings.HasSuffix(name, "key") && !strings.HasSuffix(name, "publickey")
+}
+
+func isEmptyStateValue(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return true
+	case string:
+		return typedValue == ""
+	case map[string]interface{}:
+		return len(typedValue) == 0
+	}
+
+	return false
+}
+
+func stateFieldNames(parentPath []string, fields map[string]interface{}) []string {
+	var names []string
+	for key := range fields {
+		names = append(names, strings.Join(append(append([]string{}, parentPath...), key), "."))
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,235 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StateGet", func() {
+	var (
+		logger         *fakes.Logger
+		outputWriter   *fakes.OutputWriter
+		stateValidator *fakes.StateValidator
+
+		state   storage.State
+		command commands.StateGet
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		stateValidator = &fakes.StateValidator{}
+
+		state = storage.State{
+			IAAS:  "aws",
+			EnvID: "some-env-id",
+			AWS: storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "some-region",
+			},
+			BOSH: storage.BOSH{
+				DirectorAddress:  "some-director-address",
+				DirectorPassword: "some-director-password",
+				Credentials: map[string]string{
+					"mbus_password": "some-mbus-password",
+				},
+			},
+		}
+
+		command = commands.NewStateGet(logger, outputWriter, stateValidator)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state validator fails", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("failed to validate state")
+
+			err := command.CheckFastFails([]string{"get", "envID"}, storage.State{})
+			Expect(err).To(MatchError("failed to validate state"))
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints the value of a top level field", func() {
+			err := command.Execute(context.Background(), []string{"get", "envID"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-env-id"}))
+		})
+
+		It("prints the value of a nested field", func() {
+			err := command.Execute(context.Background(), []string{"get", "bosh.directorAddress"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-director-address"}))
+		})
+
+		It("prints non string values", func() {
+			err := command.Execute(context.Background(), []string{"get", "noDirector"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"false"}))
+		})
+
+		It("prints objects as json with secret fields masked", func() {
+			err := command.Execute(context.Background(), []string{"get", "aws"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+				"accessKeyId": "some-access-key-id",
+				"secretAccessKey": "<redacted>",
+				"region": "some-region"
+			}`))
+		})
+
+		It("masks secret fields", func() {
+			err := command.Execute(context.Background(), []string{"get", "bosh.directorPassword"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"<redacted>"}))
+		})
+
+		It("masks fields whose names mention a password, secret or key", func() {
+			state.KeyPair = storage.KeyPair{
+				PrivateKey: "some-private-key",
+				PublicKey:  "some-public-key",
+			}
+			state.BOSH.Manifest = "some-manifest"
+
+			err := command.Execute(context.Background(), []string{"get"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"privateKey": "<redacted>"`))
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"publicKey": "some-public-key"`))
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"manifest": "<redacted>"`))
+		})
+
+		Context("with the keys bbl keeps for the director and the load balancers", func() {
+			BeforeEach(func() {
+				state.AWS.DirectorAccessKeyID = "some-director-access-key-id"
+				state.AWS.DirectorSecretAccessKey = "some-director-secret-access-key"
+				state.GCP.DirectorServiceAccountKey = "some-director-service-account-key"
+				state.LB.ACME = &storage.ACME{
+					DirectoryURL: "some-directory-url",
+					AccountKey:   "some-account-key",
+				}
+				state.BOSH.PendingCAs = map[string]storage.PendingCA{
+					"default_ca": {Certificate: "some-ca-certificate", PrivateKey: "some-ca-private-key"},
+				}
+			})
+
+			DescribeTable("masks the key",
+				func(path string) {
+					err := command.Execute(context.Background(), []string{"get", path}, state)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logger.PrintlnCall.Messages).To(Equal([]string{"<redacted>"}))
+				},
+				Entry("of the director on aws", "aws.directorSecretAccessKey"),
+				Entry("of the director on gcp", "gcp.directorServiceAccountKey"),
+				Entry("of the acme account", "lb.acme.accountKey"),
+				Entry("of a pending CA", "bosh.pendingCAs.default_ca.privateKey"),
+			)
+
+			It("masks the keys inside the objects that hold them", func() {
+				err := command.Execute(context.Background(), []string{"get", "bosh.pendingCAs"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{
+					"default_ca": {
+						"certificate": "some-ca-certificate",
+						"privateKey": "<redacted>"
+					}
+				}`))
+			})
+
+			It("does not mask the ids that go with the keys", func() {
+				err := command.Execute(context.Background(), []string{"get", "aws.directorAccessKeyId"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-director-access-key-id"}))
+			})
+		})
+
+		It("does not mask secret fields that are empty", func() {
+			err := command.Execute(context.Background(), []string{"get", "lb.key"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{""}))
+		})
+
+		It("reveals secret fields when --reveal is provided", func() {
+			err := command.Execute(context.Background(), []string{"get", "bosh.directorPassword", "--reveal"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Messages).To(Equal([]string{"some-director-password"}))
+		})
+
+		It("accepts --reveal before the path", func() {
+			err := command.Execute(context.Background(), []string{"get", "--reveal", "bosh.credentials"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchJSON(`{"mbus_password": "some-mbus-password"}`))
+		})
+
+		It("prints the whole state when no path is provided", func() {
+			err := command.Execute(context.Background(), []string{"get"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"envID": "some-env-id"`))
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"directorPassword": "<redacted>"`))
+			Expect(logger.PrintlnCall.Receives.Message).NotTo(ContainSubstring("some-secret-access-key"))
+		})
+
+		It("writes the value to the output writer when the output format is not text", func() {
+			outputWriter.IsTextCall.Returns.IsText = false
+
+			err := command.Execute(context.Background(), []string{"get", "bosh.credentials"}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+			Expect(outputWriter.WriteCall.Receives.Value).To(Equal("<redacted>"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the subcommand is not get", func() {
+				err := command.Execute(context.Background(), []string{"set", "envID"}, state)
+				Expect(err).To(MatchError("Invalid usage: bbl state get [<path>] [--reveal]"))
+			})
+
+			It("returns an error when no subcommand is provided", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("Invalid usage: bbl state get [<path>] [--reveal]"))
+			})
+
+			It("returns an error when more than one path is provided", func() {
+				err := command.Execute(context.Background(), []string{"get", "envID", "iaas"}, state)
+				Expect(err).To(MatchError("Invalid usage: bbl state get accepts a single path"))
+			})
+
+			It("returns an error when an unknown flag is provided", func() {
+				err := command.Execute(context.Background(), []string{"get", "envID", "--unknown-flag"}, state)
+				Expect(err).To(MatchError("flag provided but not defined: -unknown-flag"))
+			})
+
+			It("returns an error listing the valid fields when the path does not exist", func() {
+				err := command.Execute(context.Background(), []string{"get", "keyPair.missing"}, state)
+				Expect(err).To(MatchError(`"keyPair.missing" is not a valid state path, valid fields are: [keyPair.name, keyPair.privateKey, keyPair.publicKey]`))
+			})
+
+			It("returns an error when the path goes past a value", func() {
+				err := command.Execute(context.Background(), []string{"get", "envID.missing"}, state)
+				Expect(err).To(MatchError(`"envID.missing" is not a valid state path, "envID" has no fields`))
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -23,6 +24,7 @@ const (
 
 type StateQuery struct {
 	logger                logger
+	outputWriter          outputWriter
 	stateValidator        stateValidator
 	terraformManager      terraformManager
 	infrastructureManager infrastructureManager
@@ -31,9 +33,18 @@ type StateQuery struct {
 
 type getPropertyFunc func(storage.State) string
 
-func NewStateQuery(logger logger, stateValidator stateValidator, terraformManager terraformManager, infrastructureManager infrastructureManager, propertyName string) StateQuery {
+var stateQueryOutputKeys = map[string]string{
+	EnvIDPropertyName:            "env_id",
+	DirectorUsernamePropertyName: "director_username",
+	DirectorPasswordPropertyName: "director_password",
+	DirectorAddressPropertyName:  "director_address",
+	DirectorCACertPropertyName:   "director_ca_cert",
+}
+
+func NewStateQuery(logger logger, outputWriter outputWriter, stateValidator stateValidator, terraformManager terraformManager, infrastructureManager infrastructureManager, propertyName string) StateQuery {
 	return StateQuery{
 		logger:                logger,
+		outputWriter:          outputWriter,
 		stateValidator:        stateValidator,
 		terraformManager:      terraformManager,
 		infrastructureManager: infrastructureManager,
@@ -54,7 +65,7 @@ func (s StateQuery) CheckFastFails(subcommandFlags []string, state storage.State
 	return nil
 }
 
-func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error {
+func (s StateQuery) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	var propertyValue string
 	switch s.propertyName {
 	case DirectorAddressPropertyName:
@@ -81,6 +92,12 @@ func (s StateQuery) Execute(subcommandFlags []string, state storage.State) error
 		return fmt.Errorf("Could not retrieve %s, please make sure you are targeting the proper state dir.", s.propertyName)
 	}
 
+	if !s.outputWriter.IsText() {
+		return s.outputWriter.Write(map[string]string{
+			stateQueryOutputKeys[s.propertyName]: propertyValue,
+		})
+	}
+
 	s.logger.Println(propertyValue)
 	return nil
 }
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -21,6 +22,7 @@ var _ = Describe("StateQuery", func() {
 		fakeStateValidator        *fakes.StateValidator
 		fakeTerraformManager      *fakes.TerraformManager
 		fakeInfrastructureManager *fakes.InfrastructureManager
+		fakeOutputWriter          *fakes.OutputWriter
 	)
 
 	BeforeEach(func() {
@@ -28,12 +30,14 @@ var _ = Describe("StateQuery", func() {
 		fakeStateValidator = &fakes.StateValidator{}
 		fakeTerraformManager = &fakes.TerraformManager{}
 		fakeInfrastructureManager = &fakes.InfrastructureManager{}
+		fakeOutputWriter = &fakes.OutputWriter{}
+		fakeOutputWriter.IsTextCall.Returns.IsText = true
 	})
 
 	Describe("CheckFastFails", func() {
 		It("returns an error when the state validator fails", func() {
 			fakeStateValidator.ValidateCall.Returns.Error = errors.New("state validator failed")
-			command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "")
+			command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "")
 
 			err := command.CheckFastFails([]string{}, storage.State{})
 
@@ -52,7 +56,7 @@ var _ = Describe("StateQuery", func() {
 
 			DescribeTable("prints out the director information",
 				func(propertyName string) {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
+					command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
 
 					err := command.CheckFastFails([]string{}, state)
 					Expect(err).To(MatchError("Error BBL does not manage this director."))
@@ -81,9 +85,9 @@ var _ = Describe("StateQuery", func() {
 
 			DescribeTable("prints out the director information",
 				func(propertyName, expectedOutput string) {
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
+					command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
 
-					err := command.Execute([]string{}, state)
+					err := command.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal(expectedOutput))
@@ -93,6 +97,38 @@ var _ = Describe("StateQuery", func() {
 				Entry("director-password", "director password", "some-director-password"),
 				Entry("director-ssl-ca", "director ca cert", "some-director-ssl-ca"),
 			)
+
+			Context("when the output format is not text", func() {
+				BeforeEach(func() {
+					fakeOutputWriter.IsTextCall.Returns.IsText = false
+				})
+
+				DescribeTable("writes the director information to the output writer",
+					func(propertyName, expectedKey, expectedValue string) {
+						command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
+
+						err := command.Execute(context.Background(), []string{}, state)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeLogger.PrintlnCall.CallCount).To(Equal(0))
+						Expect(fakeOutputWriter.WriteCall.Receives.Value).To(Equal(map[string]string{
+							expectedKey: expectedValue,
+						}))
+					},
+					Entry("director-address", "director address", "director_address", "some-director-address"),
+					Entry("director-username", "director username", "director_username", "some-director-username"),
+					Entry("director-password", "director password", "director_password", "some-director-password"),
+					Entry("director-ssl-ca", "director ca cert", "director_ca_cert", "some-director-ssl-ca"),
+				)
+
+				It("returns an error when the output writer fails", func() {
+					fakeOutputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+					command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+
+					err := command.Execute(context.Background(), []string{}, state)
+					Expect(err).To(MatchError("failed to write"))
+				})
+			})
 		})
 
 		Context("bbl does not manage the bosh director", func() {
@@ -106,9 +142,9 @@ var _ = Describe("StateQuery", func() {
 			})
 
 			It("prints the env id", func() {
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "environment id")
+				command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "environment id")
 
-				err := command.Execute([]string{}, state)
+				err := command.Execute(context.Background(), []string{}, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("some-env-id"))
@@ -122,8 +158,8 @@ var _ = Describe("StateQuery", func() {
 
 					state.IAAS = "gcp"
 
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
-					err := command.Execute([]string{}, state)
+					command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+					err := command.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("https://some-external-ip:25555"))
 				})
@@ -139,8 +175,8 @@ var _ = Describe("StateQuery", func() {
 
 					state.IAAS = "aws"
 
-					command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
-					err := command.Execute([]string{}, state)
+					command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+					err := command.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(fakeLogger.PrintlnCall.Receives.Message).To(Equal("https://some-external-ip:25555"))
 				})
@@ -150,9 +186,9 @@ var _ = Describe("StateQuery", func() {
 		Context("failure cases", func() {
 			It("returns an error when the terraform output provider fails", func() {
 				fakeTerraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get terraform output")
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+				command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
 
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "gcp",
 					NoDirector: true,
 				})
@@ -162,9 +198,9 @@ var _ = Describe("StateQuery", func() {
 
 			It("returns an error when the infrastructure manager fails", func() {
 				fakeInfrastructureManager.DescribeCall.Returns.Error = errors.New("failed to describe stack")
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+				command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
 
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "aws",
 					NoDirector: true,
 				})
@@ -173,9 +209,9 @@ var _ = Describe("StateQuery", func() {
 			})
 
 			It("returns an error when an external ip cannot be found", func() {
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
+				command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, "director address")
 
-				err := command.Execute([]string{}, storage.State{
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					IAAS:       "lol",
 					NoDirector: true,
 				})
@@ -185,8 +221,8 @@ var _ = Describe("StateQuery", func() {
 
 			It("returns an error when the state value is empty", func() {
 				propertyName := fmt.Sprintf("%s-%d", "some-name", rand.Int())
-				command := commands.NewStateQuery(fakeLogger, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
-				err := command.Execute([]string{}, storage.State{
+				command := commands.NewStateQuery(fakeLogger, fakeOutputWriter, fakeStateValidator, fakeTerraformManager, fakeInfrastructureManager, propertyName)
+				err := command.Execute(context.Background(), []string{}, storage.State{
 					BOSH: storage.BOSH{},
 				})
 				Expect(err).To(MatchError(fmt.Sprintf("Could not retrieve %s, please make sure you are targeting the proper state dir.", propertyName)))
@@ -0,0 +1,421 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/flags"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	StatusCommand = "status"
+)
+
+var proxySOCKS5 func(string, string, *proxy.Auth, proxy.Dialer) (proxy.Dialer, error) = proxy.SOCKS5
+
+var EnvironmentUnhealthy error = errors.New("one or more status checks failed, the bbl environment is unhealthy")
+
+type Status struct {
+	logger                logger
+	outputWriter          outputWriter
+	stateValidator        stateValidator
+	terraformManager      terraformManager
+	infrastructureManager infrastructureManager
+	boshClientProvider    boshClientProvider
+	socks5Proxy           socks5Proxy
+	sshKeyGetter          sshKeyGetter
+	certificateInventory  certificateInventory
+}
+
+type boshClientProvider interface {
+	Client(directorAddress, directorUsername, directorPassword string) bosh.Client
+}
+
+type socks5Proxy interface {
+	Start(string, string) error
+	Addr() string
+}
+
+type StatusCheck struct {
+	Name    string     `json:"name"`
+	Healthy bool       `json:"healthy"`
+	Message string     `json:"message"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type StatusReport struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []StatusCheck `json:"checks"`
+}
+
+func NewStatus(logger logger, outputWriter outputWriter, stateValidator stateValidator, terraformManager terraformManager,
+	infrastructureManager infrastructureManager, boshClientProvider boshClientProvider,
+	socks5Proxy socks5Proxy, sshKeyGetter sshKeyGetter, certificateInventory certificateInventory) Status {
+	return Status{
+		logger:                logger,
+		outputWriter:          outputWriter,
+		stateValidator:        stateValidator,
+		terraformManager:      terraformManager,
+		infrastructureManager: infrastructureManager,
+		boshClientProvider:    boshClientProvider,
+		socks5Proxy:           socks5Proxy,
+		sshKeyGetter:          sshKeyGetter,
+		certificateInventory:  certificateInventory,
+	}
+}
+
+func (s Status) CheckFastFails(subcommandFlags []string, state storage.State) error {
+	err := s.stateValidator.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = parseStatusFlags(subcommandFlags)
+	return err
+}
+
+func (s Status) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
+	jsonOutput, err := parseStatusFlags(subcommandFlags)
+	if err != nil {
+		return err
+	}
+
+	report := s.Report(state)
+
+	switch {
+	case jsonOutput:
+		contents, err := MarshalOutput(JSONOutputFormat, report)
+		if err != nil {
+			// not tested
+			return err
+		}
+		s.logger.Println(contents)
+	case s.outputWriter.IsText():
+		s.logger.Println(formatStatusReport(report))
+	default:
+		err := s.outputWriter.Write(report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !report.Healthy {
+		return EnvironmentUnhealthy
+	}
+
+	return nil
+}
+
+// parseStatusFlags accepts --json, which CI scripts use, as an alias for
+// --output json.
+func parseStatusFlags(subcommandFlags []string) (bool, error) {
+	var jsonOutput bool
+
+	statusFlags := flags.New("status")
+	statusFlags.Bool(&jsonOutput, "", "json", false)
+
+	err := statusFlags.Parse(subcommandFlags)
+	return jsonOutput, err
+}
+
+func (s Status) Report(state storage.State) StatusReport {
+	var checks []StatusCheck
+
+	outputs, infrastructureCheck := s.checkInfrastructure(state)
+	checks = append(checks, infrastructureCheck)
+
+	if lbType := statusLBType(state); lbExists(lbType) {
+		checks = append(checks, checkLoadBalancers(state, lbType, outputs, infrastructureCheck.Healthy))
+	}
+
+	var socks5Client proxy.Dialer
+	if state.Jumpbox.Enabled {
+		var jumpboxCheck StatusCheck
+		socks5Client, jumpboxCheck = s.checkJumpbox(state, outputs)
+		checks = append(checks, jumpboxCheck)
+	}
+
+	if !state.NoDirector {
+		checks = append(checks, s.checkDirector(state, socks5Client))
+		checks = append(checks, checkDirectorCertificate(state, time.Now()))
+	}
+
+	if certificatesCheck, ok := s.checkCertificates(state, time.Now()); ok {
+		checks = append(checks, certificatesCheck)
+	}
+
+	report := StatusReport{
+		Healthy: true,
+		Checks:  checks,
+	}
+	for _, check := range checks {
+		if !check.Healthy {
+			report.Healthy = false
+		}
+	}
+
+	return report
+}
+
+func (s Status) checkInfrastructure(state storage.State) (map[string]interface{}, StatusCheck) {
+	check := StatusCheck{Name: "infrastructure"}
+
+	switch {
+	case state.TFState != "":
+		outputs, err := s.terraformManager.GetOutputs(state)
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to read terraform outputs: %s", err)
+			return map[string]interface{}{}, check
+		}
+
+		if len(outputs) == 0 {
+			check.Message = "terraform state has no outputs"
+			return outputs, check
+		}
+
+		check.Healthy = true
+		check.Message = fmt.Sprintf("%d terraform outputs found", len(outputs))
+		return outputs, check
+	case state.Stack.Name != "":
+		stack, err := s.infrastructureManager.Describe(state.Stack.Name)
+		if err != nil {
+			check.Message = fmt.Sprintf("failed to describe stack %s: %s", state.Stack.Name, err)
+			return map[string]interface{}{}, check
+		}
+
+		outputs := map[string]interface{}{}
+		for key, value := range stack.Outputs {
+			outputs[key] = value
+		}
+
+		check.Healthy = true
+		check.Message = fmt.Sprintf("stack %s is %s", stack.Name, stack.Status)
+		return outputs, check
+	}
+
+	check.Message = "no infrastructure found in state"
+	return map[string]interface{}{}, check
+}
+
+func (s Status) checkJumpbox(state storage.State, outputs map[string]interface{}) (proxy.Dialer, StatusCheck) {
+	check := StatusCheck{Name: "jumpbox"}
+
+	jumpboxURL, ok := outputs["jumpbox_url"].(string)
+	if !ok || jumpboxURL == "" {
+		check.Message = "jumpbox url could not be found in terraform outputs"
+		return nil, check
+	}
+
+	privateKey, err := s.sshKeyGetter.Get(state)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to read jumpbox private key: %s", err)
+		return nil, check
+	}
+
+	err = s.socks5Proxy.Start(privateKey, jumpboxURL)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to connect to %s over ssh: %s", jumpboxURL, err)
+		return nil, check
+	}
+
+	socks5Client, err := proxySOCKS5("tcp", s.socks5Proxy.Addr(), nil, proxy.Direct)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to start socks5 proxy: %s", err)
+		return nil, check
+	}
+
+	check.Healthy = true
+	check.Message = fmt.Sprintf("ssh connection to %s established", jumpboxURL)
+	return socks5Client, check
+}
+
+func (s Status) checkDirector(state storage.State, socks5Client proxy.Dialer) StatusCheck {
+	check := StatusCheck{Name: "director"}
+
+	if state.BOSH.DirectorAddress == "" {
+		check.Message = "director address could not be found in state"
+		return check
+	}
+
+	if state.Jumpbox.Enabled && socks5Client == nil {
+		check.Message = "director is not reachable without a jumpbox connection"
+		return check
+	}
+
+	boshClient := s.boshClientProvider.Client(state.BOSH.DirectorAddress, state.BOSH.DirectorUsername, state.BOSH.DirectorPassword)
+	if socks5Client != nil {
+		boshClient.ConfigureHTTPClient(socks5Client)
+	}
+
+	info, err := boshClient.Info()
+	if err != nil {
+		check.Message = fmt.Sprintf("director at %s is unreachable: %s", state.BOSH.DirectorAddress, err)
+		return check
+	}
+
+	check.Healthy = true
+	check.Message = fmt.Sprintf("%s is running version %s", info.Name, info.Version)
+	return check
+}
+
+func checkLoadBalancers(state storage.State, lbType string, outputs map[string]interface{}, infrastructureHealthy bool) StatusCheck {
+	check := StatusCheck{Name: "load balancers"}
+
+	if !infrastructureHealthy {
+		check.Message = fmt.Sprintf("%s load balancers could not be checked without infrastructure outputs", lbType)
+		return check
+	}
+
+	var missing []string
+	for _, outputName := range expectedLBOutputs(state, lbType) {
+		if value, ok := outputs[outputName]; !ok || value == "" {
+			missing = append(missing, outputName)
+		}
+	}
+
+	if len(missing) > 0 {
+		check.Message = fmt.Sprintf("%s load balancers are missing outputs: %v", lbType, missing)
+		return check
+	}
+
+	check.Healthy = true
+	check.Message = fmt.Sprintf("%s load balancers exist", lbType)
+	return check
+}
+
+func checkDirectorCertificate(state storage.State, now time.Time) StatusCheck {
+	check := StatusCheck{Name: "director certificate"}
+
+	block, _ := pem.Decode([]byte(state.BOSH.DirectorSSLCertificate))
+	if block == nil {
+		check.Message = "director certificate could not be found in state"
+		return check
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		check.Message = fmt.Sprintf("director certificate could not be parsed: %s", err)
+		return check
+	}
+
+	expires := certificate.NotAfter
+	check.Expires = &expires
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(state.BOSH.DirectorSSLCA)) {
+		check.Message = "director CA certificate could not be found in state"
+		return check
+	}
+
+	_, err = certificate.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+	})
+	if err != nil {
+		check.Message = fmt.Sprintf("director certificate is invalid: %s", err)
+		return check
+	}
+
+	check.Healthy = true
+	check.Message = fmt.Sprintf("valid until %s", expires.Format("2006-01-02"))
+	return check
+}
+
+// checkCertificates summarizes every certificate bbl manages by the one that
+// expires first. bbl certs lists all of them.
+func (s Status) checkCertificates(state storage.State, now time.Time) (StatusCheck, bool) {
+	check := StatusCheck{Name: "certificates"}
+
+	list, err := s.certificateInventory.List(state)
+	if err != nil {
+		check.Message = fmt.Sprintf("failed to read certificates: %s", err)
+		return check, true
+	}
+
+	if len(list) == 0 {
+		return check, false
+	}
+
+	first := list[0]
+	for _, certificate := range list[1:] {
+		if certificate.Expires.Before(first.Expires) {
+			first = certificate
+		}
+	}
+	expires := first.Expires
+	check.Expires = &expires
+
+	var expired []string
+	for _, certificate := range certificates.ExpiringWithin(list, 0, now) {
+		expired = append(expired, certificate.Name)
+	}
+
+	if len(expired) > 0 {
+		check.Message = fmt.Sprintf("expired: %s", strings.Join(expired, ", "))
+		return check, true
+	}
+
+	check.Healthy = true
+	check.Message = fmt.Sprintf("%d certificates, %s expires first", len(list), first.Name)
+	return check, true
+}
+
+func statusLBType(state storage.State) string {
+	if state.IAAS == "aws" && state.TFState == "" {
+		return state.Stack.LBType
+	}
+	return state.LB.Type
+}
+
+func expectedLBOutputs(state storage.State, lbType string) []string {
+	switch {
+	case state.IAAS == "aws" && state.TFState == "" && lbType == "cf":
+		return []string{"CFRouterLoadBalancer", "CFSSHProxyLoadBalancer"}
+	case state.IAAS == "aws" && state.TFState == "" && lbType == "concourse":
+		return []string{"ConcourseLoadBalancer"}
+	case state.IAAS == "aws" && lbType == "cf":
+		return []string{"cf_router_load_balancer", "cf_ssh_proxy_load_balancer", "cf_tcp_router_load_balancer"}
+	case state.IAAS == "aws" && lbType == "concourse":
+		return []string{"concourse_load_balancer"}
+	case state.IAAS == "gcp" && lbType == "cf":
+		return []string{"router_lb_ip", "ssh_proxy_lb_ip", "tcp_router_lb_ip", "ws_lb_ip"}
+	case state.IAAS == "gcp" && lbType == "concourse":
+		return []string{"concourse_lb_ip"}
+	}
+	return []string{}
+}
+
+func formatStatusReport(report StatusReport) string {
+	buffer := bytes.NewBuffer([]byte{})
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "CHECK\tSTATUS\tEXPIRES\tDETAILS")
+	for _, check := range report.Checks {
+		status := "unhealthy"
+		if check.Healthy {
+			status = "ok"
+		}
+
+		expires := "-"
+		if check.Expires != nil {
+			expires = check.Expires.Format("2006-01-02")
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", check.Name, status, expires, check.Message)
+	}
+	writer.Flush()
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,458 @@
+package commands_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/commands"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"golang.org/x/net/proxy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		logger                *fakes.Logger
+		outputWriter          *fakes.OutputWriter
+		stateValidator        *fakes.StateValidator
+		terraformManager      *fakes.TerraformManager
+		infrastructureManager *fakes.InfrastructureManager
+		boshClientProvider    *fakes.BOSHClientProvider
+		boshClient            *fakes.BOSHClient
+		socks5Proxy           *fakes.Socks5Proxy
+		sshKeyGetter          *fakes.SSHKeyGetter
+		certificateInventory  *fakes.CertificateInventory
+
+		status  commands.Status
+		state   storage.State
+		expires time.Time
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		outputWriter = &fakes.OutputWriter{}
+		outputWriter.IsTextCall.Returns.IsText = true
+		stateValidator = &fakes.StateValidator{}
+		terraformManager = &fakes.TerraformManager{}
+		infrastructureManager = &fakes.InfrastructureManager{}
+		boshClient = &fakes.BOSHClient{}
+		boshClientProvider = &fakes.BOSHClientProvider{}
+		boshClientProvider.ClientCall.Returns.Client = boshClient
+		socks5Proxy = &fakes.Socks5Proxy{}
+		sshKeyGetter = &fakes.SSHKeyGetter{}
+		certificateInventory = &fakes.CertificateInventory{}
+
+		expires = time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+		ca, certificate := generateCertificate(expires)
+
+		state = storage.State{
+			IAAS:    "gcp",
+			TFState: "some-tf-state",
+			BOSH: storage.BOSH{
+				DirectorAddress:        "https://some-director-address:25555",
+				DirectorUsername:       "some-director-username",
+				DirectorPassword:       "some-director-password",
+				DirectorSSLCA:          ca,
+				DirectorSSLCertificate: certificate,
+			},
+		}
+
+		terraformManager.GetOutputsCall.Returns.Outputs = map[string]interface{}{
+			"external_ip": "some-external-ip",
+			"jumpbox_url": "some-jumpbox-url:22",
+		}
+		boshClient.InfoCall.Returns.Info = bosh.Info{
+			Name:    "some-director-name",
+			Version: "some-version",
+		}
+
+		status = commands.NewStatus(logger, outputWriter, stateValidator, terraformManager, infrastructureManager, boshClientProvider, socks5Proxy, sshKeyGetter, certificateInventory)
+	})
+
+	Describe("CheckFastFails", func() {
+		It("returns an error when the state does not exist", func() {
+			stateValidator.ValidateCall.Returns.Error = errors.New("failed to validate state")
+			err := status.CheckFastFails([]string{}, storage.State{})
+			Expect(err).To(MatchError("failed to validate state"))
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := status.CheckFastFails([]string{"--some-flag"}, state)
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
+
+		It("accepts --json", func() {
+			err := status.CheckFastFails([]string{"--json"}, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Report", func() {
+		It("checks the terraform outputs, director and director certificate", func() {
+			report := status.Report(state)
+			Expect(report.Healthy).To(BeTrue())
+			Expect(report.Checks).To(HaveLen(3))
+
+			Expect(report.Checks[0]).To(Equal(commands.StatusCheck{
+				Name:    "infrastructure",
+				Healthy: true,
+				Message: "2 terraform outputs found",
+			}))
+			Expect(terraformManager.GetOutputsCall.Receives.BBLState).To(Equal(state))
+
+			Expect(report.Checks[1]).To(Equal(commands.StatusCheck{
+				Name:    "director",
+				Healthy: true,
+				Message: "some-director-name is running version some-version",
+			}))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorAddress).To(Equal("https://some-director-address:25555"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorUsername).To(Equal("some-director-username"))
+			Expect(boshClientProvider.ClientCall.Receives.DirectorPassword).To(Equal("some-director-password"))
+			Expect(boshClient.ConfigureHTTPClientCall.CallCount).To(Equal(0))
+
+			Expect(report.Checks[2].Name).To(Equal("director certificate"))
+			Expect(report.Checks[2].Healthy).To(BeTrue())
+			Expect(report.Checks[2].Message).To(Equal("valid until " + expires.Format("2006-01-02")))
+			Expect(*report.Checks[2].Expires).To(Equal(expires))
+		})
+
+		Context("when bbl does not manage the director", func() {
+			It("does not check the director", func() {
+				state.NoDirector = true
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeTrue())
+				Expect(report.Checks).To(HaveLen(1))
+				Expect(boshClientProvider.ClientCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the environment is a legacy cloudformation stack", func() {
+			BeforeEach(func() {
+				state.IAAS = "aws"
+				state.TFState = ""
+				state.Stack = storage.Stack{
+					Name:   "some-stack-name",
+					LBType: "concourse",
+				}
+				infrastructureManager.DescribeCall.Returns.Stack = cloudformation.Stack{
+					Name:   "some-stack-name",
+					Status: "UPDATE_COMPLETE",
+					Outputs: map[string]string{
+						"ConcourseLoadBalancer": "some-concourse-lb",
+					},
+				}
+			})
+
+			It("checks the stack and its load balancers", func() {
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeTrue())
+				Expect(infrastructureManager.DescribeCall.Receives.StackName).To(Equal("some-stack-name"))
+				Expect(report.Checks[0].Message).To(Equal("stack some-stack-name is UPDATE_COMPLETE"))
+				Expect(report.Checks[1]).To(Equal(commands.StatusCheck{
+					Name:    "load balancers",
+					Healthy: true,
+					Message: "concourse load balancers exist",
+				}))
+			})
+
+			It("reports missing load balancers", func() {
+				infrastructureManager.DescribeCall.Returns.Stack.Outputs = map[string]string{}
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[1]).To(Equal(commands.StatusCheck{
+					Name:    "load balancers",
+					Message: "concourse load balancers are missing outputs: [ConcourseLoadBalancer]",
+				}))
+			})
+		})
+
+		Context("when load balancers are attached", func() {
+			BeforeEach(func() {
+				state.LB.Type = "cf"
+			})
+
+			It("reports load balancers that are missing from the terraform outputs", func() {
+				terraformManager.GetOutputsCall.Returns.Outputs["router_lb_ip"] = "some-router-lb-ip"
+				terraformManager.GetOutputsCall.Returns.Outputs["ssh_proxy_lb_ip"] = "some-ssh-proxy-lb-ip"
+				terraformManager.GetOutputsCall.Returns.Outputs["tcp_router_lb_ip"] = "some-tcp-router-lb-ip"
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[1]).To(Equal(commands.StatusCheck{
+					Name:    "load balancers",
+					Message: "cf load balancers are missing outputs: [ws_lb_ip]",
+				}))
+			})
+		})
+
+		Context("when bbl manages certificates", func() {
+			var first time.Time
+
+			BeforeEach(func() {
+				first = time.Now().Add(20 * 24 * time.Hour).UTC().Truncate(time.Second)
+				certificateInventory.ListCall.Returns.Certificates = []certificates.Certificate{
+					{Name: "director/director_ssl", Expires: expires},
+					{Name: "load balancer", Expires: first},
+				}
+			})
+
+			It("reports the certificate that expires first", func() {
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeTrue())
+				Expect(certificateInventory.ListCall.Receives.State).To(Equal(state))
+
+				Expect(report.Checks[3]).To(Equal(commands.StatusCheck{
+					Name:    "certificates",
+					Healthy: true,
+					Message: "2 certificates, load balancer expires first",
+					Expires: &first,
+				}))
+			})
+
+			It("reports expired certificates", func() {
+				expired := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+				certificateInventory.ListCall.Returns.Certificates[1].Expires = expired
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[3]).To(Equal(commands.StatusCheck{
+					Name:    "certificates",
+					Message: "expired: load balancer",
+					Expires: &expired,
+				}))
+			})
+
+			It("reports when the certificates cannot be read", func() {
+				certificateInventory.ListCall.Returns.Error = errors.New("failed to describe")
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[3]).To(Equal(commands.StatusCheck{
+					Name:    "certificates",
+					Message: "failed to read certificates: failed to describe",
+				}))
+			})
+		})
+
+		Context("when the jumpbox is enabled", func() {
+			var socks5Client *fakes.Socks5Client
+
+			BeforeEach(func() {
+				state.Jumpbox.Enabled = true
+				sshKeyGetter.GetCall.Returns.PrivateKey = "some-private-key"
+				socks5Proxy.AddrCall.Returns.Addr = "some-socks-proxy-addr"
+
+				socks5Client = &fakes.Socks5Client{}
+				commands.SetProxySOCKS5(func(network, addr string, auth *proxy.Auth, forward proxy.Dialer) (proxy.Dialer, error) {
+					return socks5Client, nil
+				})
+			})
+
+			AfterEach(func() {
+				commands.ResetProxySOCKS5()
+			})
+
+			It("checks the ssh connection and reaches the director through the jumpbox", func() {
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeTrue())
+
+				Expect(report.Checks[1]).To(Equal(commands.StatusCheck{
+					Name:    "jumpbox",
+					Healthy: true,
+					Message: "ssh connection to some-jumpbox-url:22 established",
+				}))
+				Expect(socks5Proxy.StartCall.Receives.JumpboxPrivateKey).To(Equal("some-private-key"))
+				Expect(socks5Proxy.StartCall.Receives.JumpboxExternalURL).To(Equal("some-jumpbox-url:22"))
+
+				Expect(boshClient.ConfigureHTTPClientCall.Receives.Socks5Client).To(Equal(socks5Client))
+				Expect(report.Checks[2].Healthy).To(BeTrue())
+			})
+
+			It("reports the director as unreachable when the ssh connection fails", func() {
+				socks5Proxy.StartCall.Returns.Error = errors.New("connection refused")
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[1].Message).To(Equal("failed to connect to some-jumpbox-url:22 over ssh: connection refused"))
+				Expect(report.Checks[2].Message).To(Equal("director is not reachable without a jumpbox connection"))
+				Expect(boshClient.InfoCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("failure cases", func() {
+			It("reports when the terraform outputs cannot be read", func() {
+				terraformManager.GetOutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[0].Message).To(Equal("failed to read terraform outputs: failed to get outputs"))
+			})
+
+			It("reports when there is no infrastructure", func() {
+				state.TFState = ""
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[0].Message).To(Equal("no infrastructure found in state"))
+			})
+
+			It("reports when the director is unreachable", func() {
+				boshClient.InfoCall.Returns.Error = errors.New("i/o timeout")
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[1].Message).To(Equal("director at https://some-director-address:25555 is unreachable: i/o timeout"))
+			})
+
+			It("reports when the director certificate has expired", func() {
+				expired := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+				state.BOSH.DirectorSSLCA, state.BOSH.DirectorSSLCertificate = generateCertificate(expired)
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[2].Message).To(ContainSubstring("director certificate is invalid"))
+				Expect(*report.Checks[2].Expires).To(Equal(expired))
+			})
+
+			It("reports when the director certificate is not signed by the director CA", func() {
+				state.BOSH.DirectorSSLCA, _ = generateCertificate(expires)
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[2].Message).To(ContainSubstring("director certificate is invalid"))
+			})
+
+			It("reports when the director certificate is missing", func() {
+				state.BOSH.DirectorSSLCertificate = ""
+
+				report := status.Report(state)
+				Expect(report.Healthy).To(BeFalse())
+				Expect(report.Checks[2].Message).To(Equal("director certificate could not be found in state"))
+			})
+		})
+	})
+
+	Describe("Execute", func() {
+		It("prints a table of the status checks", func() {
+			err := status.Execute(context.Background(), []string{}, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring("CHECK"))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`director\s+ok\s+-\s+some-director-name is running version some-version`))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`director certificate\s+ok\s+` + expires.Format("2006-01-02")))
+		})
+
+		Context("when the output is not text", func() {
+			BeforeEach(func() {
+				outputWriter.IsTextCall.Returns.IsText = false
+			})
+
+			It("writes the report with the output writer", func() {
+				err := status.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.PrintlnCall.CallCount).To(Equal(0))
+				report, ok := outputWriter.WriteCall.Receives.Value.(commands.StatusReport)
+				Expect(ok).To(BeTrue())
+				Expect(report.Healthy).To(BeTrue())
+				Expect(report.Checks).To(HaveLen(3))
+			})
+
+			It("returns an error when the report cannot be written", func() {
+				outputWriter.WriteCall.Returns.Error = errors.New("failed to write")
+
+				err := status.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to write"))
+			})
+		})
+
+		Context("when --json is provided", func() {
+			It("prints the report as json whatever the output format", func() {
+				err := status.Execute(context.Background(), []string{"--json"}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(outputWriter.WriteCall.CallCount).To(Equal(0))
+
+				var report commands.StatusReport
+				err = json.Unmarshal([]byte(logger.PrintlnCall.Receives.Message), &report)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report.Healthy).To(BeTrue())
+				Expect(report.Checks).To(HaveLen(3))
+				Expect(report.Checks[1].Name).To(Equal("director"))
+			})
+
+			It("returns an error when the environment is unhealthy", func() {
+				boshClient.InfoCall.Returns.Error = errors.New("i/o timeout")
+
+				err := status.Execute(context.Background(), []string{"--json"}, state)
+				Expect(err).To(Equal(commands.EnvironmentUnhealthy))
+				Expect(logger.PrintlnCall.Receives.Message).To(ContainSubstring(`"healthy": false`))
+			})
+		})
+
+		It("returns an error when the flags cannot be parsed", func() {
+			err := status.Execute(context.Background(), []string{"--some-flag"}, state)
+			Expect(err).To(MatchError("flag provided but not defined: -some-flag"))
+		})
+
+		It("returns an error when the environment is unhealthy", func() {
+			boshClient.InfoCall.Returns.Error = errors.New("i/o timeout")
+
+			err := status.Execute(context.Background(), []string{}, state)
+			Expect(err).To(Equal(commands.EnvironmentUnhealthy))
+			Expect(logger.PrintlnCall.Receives.Message).To(MatchRegexp(`director\s+unhealthy`))
+		})
+	})
+})
+
+func generateCertificate(notAfter time.Time) (string, string) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	Expect(err).NotTo(HaveOccurred())
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "some-ca"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "some-director"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER})
+
+	return string(ca), string(certificate)
+}
@@ -1,55 +1,78 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 type Up struct {
-	awsUp       awsUp
-	gcpUp       gcpUp
-	envGetter   envGetter
-	boshManager boshManager
+	awsUp                 awsUp
+	gcpUp                 gcpUp
+	envGetter             envGetter
+	boshManager           boshManager
+	quotaChecker          quotaChecker
+	gcpPermissionsChecker gcpPermissionsChecker
+	acmeManager           acmeManager
 }
 
 type awsUp interface {
-	Execute(awsUpConfig AWSUpConfig, state storage.State) error
+	Execute(ctx context.Context, awsUpConfig AWSUpConfig, state storage.State) error
 }
 
 type gcpUp interface {
-	Execute(gcpUpConfig GCPUpConfig, state storage.State) error
+	Execute(ctx context.Context, gcpUpConfig GCPUpConfig, state storage.State) error
 }
 
 type envGetter interface {
 	Get(name string) string
 }
 
+type quotaChecker interface {
+	Check(storage.State) error
+}
+
+type gcpPermissionsChecker interface {
+	Check(storage.State) error
+}
+
 type upConfig struct {
-	awsAccessKeyID       string
-	awsSecretAccessKey   string
-	awsRegion            string
-	awsBOSHAZ            string
-	gcpServiceAccountKey string
-	gcpProjectID         string
-	gcpZone              string
-	gcpRegion            string
-	iaas                 string
-	name                 string
-	opsFile              string
-	noDirector           bool
-	jumpbox              bool
-	terraform            bool
+	awsAccessKeyID        string
+	awsSecretAccessKey    string
+	awsRegion             string
+	awsBOSHAZ             string
+	awsVPCID              string
+	awsSubnetIDs          string
+	awsIAMInstanceProfile bool
+	gcpServiceAccountKey  string
+	gcpProjectID          string
+	gcpZone               string
+	gcpRegion             string
+	gcpNetwork            string
+	gcpSubnetwork         string
+	gcpNetworkProjectID   string
+	iaas                  string
+	name                  string
+	opsFile               string
+	noDirector            bool
+	jumpbox               bool
+	terraform             bool
 }
 
-func NewUp(awsUp awsUp, gcpUp gcpUp, envGetter envGetter, boshManager boshManager) Up {
+func NewUp(awsUp awsUp, gcpUp gcpUp, envGetter envGetter, boshManager boshManager, quotaChecker quotaChecker,
+	gcpPermissionsChecker gcpPermissionsChecker, acmeManager acmeManager) Up {
 	return Up{
-		awsUp:       awsUp,
-		gcpUp:       gcpUp,
-		envGetter:   envGetter,
-		boshManager: boshManager,
+		awsUp:                 awsUp,
+		gcpUp:                 gcpUp,
+		envGetter:             envGetter,
+		boshManager:           boshManager,
+		quotaChecker:          quotaChecker,
+		gcpPermissionsChecker: gcpPermissionsChecker,
+		acmeManager:           acmeManager,
 	}
 }
 
@@ -78,10 +101,10 @@ func (u Up) CheckFastFails(args []string, state storage.State) error {
 		return fmt.Errorf("The director name cannot be changed for an existing environment. Current name is %s.", state.EnvID)
 	}
 
-	return nil
+	return u.checkIAAS(config, state)
 }
 
-func (u Up) Execute(args []string, state storage.State) error {
+func (u Up) Execute(ctx context.Context, args []string, state storage.State) error {
 	var desiredIAAS string
 
 	config, err := u.parseArgs(args)
@@ -95,24 +118,35 @@ func (u Up) Execute(args []string, state storage.State) error {
 		desiredIAAS = config.iaas
 	}
 
+	state, err = u.renewACMECertificate(ctx, state)
+	if err != nil {
+		return err
+	}
+
 	switch desiredIAAS {
 	case "aws":
-		err = u.awsUp.Execute(AWSUpConfig{
-			AccessKeyID:     config.awsAccessKeyID,
-			SecretAccessKey: config.awsSecretAccessKey,
-			Region:          config.awsRegion,
-			BOSHAZ:          config.awsBOSHAZ,
-			OpsFilePath:     config.opsFile,
-			Name:            config.name,
-			NoDirector:      config.noDirector,
-			Terraform:       config.terraform,
+		err = u.awsUp.Execute(ctx, AWSUpConfig{
+			AccessKeyID:        config.awsAccessKeyID,
+			SecretAccessKey:    config.awsSecretAccessKey,
+			Region:             config.awsRegion,
+			BOSHAZ:             config.awsBOSHAZ,
+			VPCID:              config.awsVPCID,
+			SubnetIDs:          splitList(config.awsSubnetIDs),
+			IAMInstanceProfile: config.awsIAMInstanceProfile,
+			OpsFilePath:        config.opsFile,
+			Name:               config.name,
+			NoDirector:         config.noDirector,
+			Terraform:          config.terraform,
 		}, state)
 	case "gcp":
-		err = u.gcpUp.Execute(GCPUpConfig{
+		err = u.gcpUp.Execute(ctx, GCPUpConfig{
 			ServiceAccountKey: config.gcpServiceAccountKey,
 			ProjectID:         config.gcpProjectID,
 			Zone:              config.gcpZone,
 			Region:            config.gcpRegion,
+			Network:           config.gcpNetwork,
+			Subnetwork:        config.gcpSubnetwork,
+			NetworkProjectID:  config.gcpNetworkProjectID,
 			OpsFilePath:       config.opsFile,
 			Name:              config.name,
 			NoDirector:        config.noDirector,
@@ -129,6 +163,86 @@ func (u Up) Execute(args []string, state storage.State) error {
 	return nil
 }
 
+// renewACMECertificate issues a new load balancer certificate when the acme
+// certificate is about to expire. Up applies it along with everything else.
+func (u Up) renewACMECertificate(ctx context.Context, state storage.State) (storage.State, error) {
+	if state.LB.ACME == nil {
+		return state, nil
+	}
+
+	needsRenewal, err := u.acmeManager.NeedsRenewal(state)
+	if err != nil || !needsRenewal {
+		return state, err
+	}
+
+	renewedState, err := u.acmeManager.Issue(ctx, state)
+	if err != nil {
+		return state, fmt.Errorf("failed to renew the acme certificate: %s", err)
+	}
+
+	return renewedState, nil
+}
+
+// checkIAAS checks the quotas, and on gcp the permissions that terraform needs
+// for the director service account. It leaves missing or invalid credentials
+// for Execute to report.
+func (u Up) checkIAAS(config upConfig, state storage.State) error {
+	if state.IAAS == "" {
+		state.IAAS = config.iaas
+	}
+
+	switch state.IAAS {
+	case "aws":
+		if !config.terraform && state.TFState == "" {
+			return nil
+		}
+
+		if config.awsAccessKeyID != "" {
+			state.AWS.AccessKeyID = config.awsAccessKeyID
+		}
+		if config.awsSecretAccessKey != "" {
+			state.AWS.SecretAccessKey = config.awsSecretAccessKey
+		}
+		if config.awsRegion != "" {
+			state.AWS.Region = config.awsRegion
+		}
+		if config.awsVPCID != "" {
+			state.AWS.ExistingVPCID = config.awsVPCID
+			state.AWS.ExistingSubnetIDs = splitList(config.awsSubnetIDs)
+		}
+
+		if state.AWS.AccessKeyID == "" || state.AWS.SecretAccessKey == "" || state.AWS.Region == "" {
+			return nil
+		}
+	case "gcp":
+		gcpState, err := parseUpConfig(GCPUpConfig{
+			ServiceAccountKey: config.gcpServiceAccountKey,
+			ProjectID:         config.gcpProjectID,
+			Zone:              config.gcpZone,
+			Region:            config.gcpRegion,
+			Network:           config.gcpNetwork,
+			Subnetwork:        config.gcpSubnetwork,
+			NetworkProjectID:  config.gcpNetworkProjectID,
+		}, state.GCP)
+		if err != nil {
+			return nil
+		}
+		state.GCP = gcpState
+
+		if state.GCP.ServiceAccountKey == "" || state.GCP.ProjectID == "" || state.GCP.Zone == "" || state.GCP.Region == "" {
+			return nil
+		}
+
+		if err := u.gcpPermissionsChecker.Check(state); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	return u.quotaChecker.Check(state)
+}
+
 func (u Up) parseArgs(args []string) (upConfig, error) {
 	var config upConfig
 
@@ -140,11 +254,17 @@ func (u Up) parseArgs(args []string) (upConfig, error) {
 	upFlags.String(&config.awsSecretAccessKey, "aws-secret-access-key", u.envGetter.Get("BBL_AWS_SECRET_ACCESS_KEY"))
 	upFlags.String(&config.awsRegion, "aws-region", u.envGetter.Get("BBL_AWS_REGION"))
 	upFlags.String(&config.awsBOSHAZ, "aws-bosh-az", u.envGetter.Get("BBL_AWS_BOSH_AZ"))
+	upFlags.String(&config.awsVPCID, "aws-vpc-id", u.envGetter.Get("BBL_AWS_VPC_ID"))
+	upFlags.String(&config.awsSubnetIDs, "aws-subnet-ids", u.envGetter.Get("BBL_AWS_SUBNET_IDS"))
+	upFlags.Bool(&config.awsIAMInstanceProfile, "", "aws-iam-instance-profile", false)
 
 	upFlags.String(&config.gcpServiceAccountKey, "gcp-service-account-key", u.envGetter.Get("BBL_GCP_SERVICE_ACCOUNT_KEY"))
 	upFlags.String(&config.gcpProjectID, "gcp-project-id", u.envGetter.Get("BBL_GCP_PROJECT_ID"))
 	upFlags.String(&config.gcpZone, "gcp-zone", u.envGetter.Get("BBL_GCP_ZONE"))
 	upFlags.String(&config.gcpRegion, "gcp-region", u.envGetter.Get("BBL_GCP_REGION"))
+	upFlags.String(&config.gcpNetwork, "gcp-network", u.envGetter.Get("BBL_GCP_NETWORK"))
+	upFlags.String(&config.gcpSubnetwork, "gcp-subnetwork", u.envGetter.Get("BBL_GCP_SUBNETWORK"))
+	upFlags.String(&config.gcpNetworkProjectID, "gcp-network-project-id", u.envGetter.Get("BBL_GCP_NETWORK_PROJECT_ID"))
 
 	upFlags.String(&config.name, "name", "")
 	upFlags.String(&config.opsFile, "ops-file", "")
@@ -159,3 +279,14 @@ func (u Up) parseArgs(args []string) (upConfig, error) {
 
 	return config, nil
 }
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
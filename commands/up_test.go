@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -22,6 +23,9 @@ var _ = Describe("Up", func() {
 		fakeGCPUp       *fakes.GCPUp
 		fakeEnvGetter   *fakes.EnvGetter
 		fakeBOSHManager *fakes.BOSHManager
+		quotaChecker    *fakes.QuotaChecker
+		gcpPermissions  *fakes.GCPPermissionsChecker
+		acmeManager     *fakes.ACMEManager
 		state           storage.State
 	)
 
@@ -31,8 +35,11 @@ var _ = Describe("Up", func() {
 		fakeEnvGetter = &fakes.EnvGetter{}
 		fakeBOSHManager = &fakes.BOSHManager{}
 		fakeBOSHManager.VersionCall.Returns.Version = "2.0.0"
+		quotaChecker = &fakes.QuotaChecker{}
+		gcpPermissions = &fakes.GCPPermissionsChecker{}
+		acmeManager = &fakes.ACMEManager{}
 
-		command = commands.NewUp(fakeAWSUp, fakeGCPUp, fakeEnvGetter, fakeBOSHManager)
+		command = commands.NewUp(fakeAWSUp, fakeGCPUp, fakeEnvGetter, fakeBOSHManager, quotaChecker, gcpPermissions, acmeManager)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -141,6 +148,126 @@ var _ = Describe("Up", func() {
 				})
 			})
 		})
+
+		Describe("quotas", func() {
+			It("checks the quotas with the credentials from the flags for a new gcp environment", func() {
+				err := command.CheckFastFails([]string{
+					"--iaas", "gcp",
+					"--gcp-service-account-key", `{"real": "json"}`,
+					"--gcp-project-id", "some-project-id",
+					"--gcp-zone", "some-zone",
+					"--gcp-region", "some-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(1))
+				Expect(quotaChecker.CheckCall.Receives.State).To(Equal(storage.State{
+					IAAS: "gcp",
+					GCP: storage.GCP{
+						ServiceAccountKey: `{"real": "json"}`,
+						ProjectID:         "some-project-id",
+						Zone:              "some-zone",
+						Region:            "some-region",
+					},
+				}))
+			})
+
+			It("checks the quotas of an existing terraform aws environment", func() {
+				state := storage.State{
+					IAAS:    "aws",
+					TFState: "some-tf-state",
+					AWS: storage.AWS{
+						AccessKeyID:     "some-access-key-id",
+						SecretAccessKey: "some-secret-access-key",
+						Region:          "some-region",
+					},
+				}
+
+				err := command.CheckFastFails([]string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.Receives.State).To(Equal(state))
+			})
+
+			It("returns an error when there is not enough quota", func() {
+				quotaChecker.CheckCall.Returns.Error = errors.New("not enough quota")
+
+				err := command.CheckFastFails([]string{
+					"--iaas", "aws",
+					"--terraform",
+					"--aws-access-key-id", "some-access-key-id",
+					"--aws-secret-access-key", "some-secret-access-key",
+					"--aws-region", "some-region",
+				}, storage.State{})
+				Expect(err).To(MatchError("not enough quota"))
+			})
+
+			It("does not check the quotas of an aws environment that uses cloudformation", func() {
+				err := command.CheckFastFails([]string{
+					"--iaas", "aws",
+					"--aws-access-key-id", "some-access-key-id",
+					"--aws-secret-access-key", "some-secret-access-key",
+					"--aws-region", "some-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(0))
+			})
+
+			It("leaves incomplete credentials for execute to report", func() {
+				err := command.CheckFastFails([]string{
+					"--iaas", "gcp",
+					"--gcp-project-id", "some-project-id",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Describe("gcp permissions", func() {
+			var args []string
+
+			BeforeEach(func() {
+				args = []string{
+					"--iaas", "gcp",
+					"--gcp-service-account-key", `{"real": "json"}`,
+					"--gcp-project-id", "some-project-id",
+					"--gcp-zone", "some-zone",
+					"--gcp-region", "some-region",
+				}
+			})
+
+			It("checks the permissions of the credentials from the flags", func() {
+				err := command.CheckFastFails(args, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpPermissions.CheckCall.CallCount).To(Equal(1))
+				Expect(gcpPermissions.CheckCall.Receives.State.GCP.ServiceAccountKey).To(Equal(`{"real": "json"}`))
+				Expect(gcpPermissions.CheckCall.Receives.State.GCP.ProjectID).To(Equal("some-project-id"))
+			})
+
+			It("fails before the quotas are checked when the credentials lack permissions", func() {
+				gcpPermissions.CheckCall.Returns.Error = errors.New("missing permissions")
+
+				err := command.CheckFastFails(args, storage.State{})
+				Expect(err).To(MatchError("missing permissions"))
+				Expect(quotaChecker.CheckCall.CallCount).To(Equal(0))
+			})
+
+			It("does not check the permissions on aws", func() {
+				err := command.CheckFastFails([]string{
+					"--iaas", "aws",
+					"--terraform",
+					"--aws-access-key-id", "some-access-key-id",
+					"--aws-secret-access-key", "some-secret-access-key",
+					"--aws-region", "some-region",
+				}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(gcpPermissions.CheckCall.CallCount).To(Equal(0))
+			})
+		})
 	})
 
 	Describe("Execute", func() {
@@ -154,7 +281,7 @@ var _ = Describe("Up", func() {
 			})
 
 			It("uses the aws args provided by environment variables", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "aws",
 				}, storage.State{Version: 999})
 				Expect(err).NotTo(HaveOccurred())
@@ -169,9 +296,19 @@ var _ = Describe("Up", func() {
 				}))
 			})
 
+			It("passes the context to aws up so that it can be interrupted", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				err := command.Execute(ctx, []string{"--iaas", "aws"}, storage.State{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeAWSUp.ExecuteCall.Receives.Context).To(BeIdenticalTo(ctx))
+			})
+
 			DescribeTable("gives precedence to arguments passed as command line args", func(args []string, expectedConfig commands.AWSUpConfig) {
 				args = append(args, "--iaas", "aws")
-				err := command.Execute(args, state)
+				err := command.Execute(context.Background(), args, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig).To(Equal(expectedConfig))
@@ -211,7 +348,7 @@ var _ = Describe("Up", func() {
 					"BBL_AWS_REGION":            "region-from-env",
 				}
 
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "aws",
 					"--ops-file", "some-ops-file-path",
 				}, storage.State{})
@@ -233,7 +370,7 @@ var _ = Describe("Up", func() {
 					"BBL_GCP_REGION":              "some-region-env",
 				}
 
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "gcp",
 					"--ops-file", "some-ops-file-path",
 				}, storage.State{})
@@ -260,7 +397,7 @@ var _ = Describe("Up", func() {
 			})
 
 			It("uses the gcp args provided by environment variables", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "gcp",
 				}, storage.State{Version: 999})
 				Expect(err).NotTo(HaveOccurred())
@@ -279,7 +416,7 @@ var _ = Describe("Up", func() {
 			DescribeTable("gives precedence to arguments passed as command line args", func(args []string, expectedConfig commands.GCPUpConfig) {
 				args = append(args, "--iaas", "gcp")
 
-				err := command.Execute(args, state)
+				err := command.Execute(context.Background(), args, state)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig).To(Equal(expectedConfig))
@@ -328,7 +465,7 @@ var _ = Describe("Up", func() {
 				fakeEnvGetter.Values = map[string]string{
 					"BBL_IAAS": "gcp",
 				}
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--gcp-service-account-key", "some-service-account-key",
 					"--gcp-project-id", "some-project-id",
 					"--gcp-zone", "some-zone",
@@ -343,7 +480,7 @@ var _ = Describe("Up", func() {
 				fakeEnvGetter.Values = map[string]string{
 					"BBL_IAAS": "aws",
 				}
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "gcp",
 					"--gcp-service-account-key", "some-service-account-key",
 					"--gcp-project-id", "some-project-id",
@@ -357,7 +494,7 @@ var _ = Describe("Up", func() {
 
 			Context("when desired iaas is gcp", func() {
 				It("executes the GCP up with gcp details from args", func() {
-					err := command.Execute([]string{
+					err := command.Execute(context.Background(), []string{
 						"--iaas", "gcp",
 						"--gcp-service-account-key", "some-service-account-key",
 						"--gcp-project-id", "some-project-id",
@@ -382,7 +519,7 @@ var _ = Describe("Up", func() {
 						"BBL_GCP_ZONE":                "some-zone",
 						"BBL_GCP_REGION":              "some-region",
 					}
-					err := command.Execute([]string{
+					err := command.Execute(context.Background(), []string{
 						"--iaas", "gcp",
 					}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
@@ -396,9 +533,35 @@ var _ = Describe("Up", func() {
 					}))
 				})
 
+				Context("when an existing network is specified", func() {
+					It("executes the GCP up with the network, subnetwork and host project", func() {
+						err := command.Execute(context.Background(), []string{
+							"--iaas", "gcp",
+							"--gcp-service-account-key", "some-service-account-key",
+							"--gcp-project-id", "some-project-id",
+							"--gcp-zone", "some-zone",
+							"--gcp-region", "some-region",
+							"--gcp-network", "some-network",
+							"--gcp-subnetwork", "some-subnetwork",
+							"--gcp-network-project-id", "some-host-project-id",
+						}, storage.State{})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig).To(Equal(commands.GCPUpConfig{
+							ServiceAccountKey: "some-service-account-key",
+							ProjectID:         "some-project-id",
+							Zone:              "some-zone",
+							Region:            "some-region",
+							Network:           "some-network",
+							Subnetwork:        "some-subnetwork",
+							NetworkProjectID:  "some-host-project-id",
+						}))
+					})
+				})
+
 				Context("when the --jumpbox flag is specified", func() {
 					It("executes the GCP up with gcp details from args", func() {
-						err := command.Execute([]string{
+						err := command.Execute(context.Background(), []string{
 							"--iaas", "gcp",
 							"--jumpbox",
 							"--gcp-service-account-key", "some-service-account-key",
@@ -422,7 +585,7 @@ var _ = Describe("Up", func() {
 
 			Context("when desired iaas is aws", func() {
 				It("executes the AWS up", func() {
-					err := command.Execute([]string{
+					err := command.Execute(context.Background(), []string{
 						"--iaas", "aws",
 						"--aws-access-key-id", "some-access-key-id",
 						"--aws-secret-access-key", "some-secret-access-key",
@@ -440,9 +603,55 @@ var _ = Describe("Up", func() {
 					}))
 				})
 
+				Context("when an existing vpc is specified", func() {
+					It("executes the AWS up with the vpc and subnets", func() {
+						err := command.Execute(context.Background(), []string{
+							"--iaas", "aws",
+							"--aws-access-key-id", "some-access-key-id",
+							"--aws-secret-access-key", "some-secret-access-key",
+							"--aws-region", "some-region",
+							"--aws-vpc-id", "some-vpc-id",
+							"--aws-subnet-ids", "some-subnet-1, some-subnet-2",
+							"--terraform",
+						}, storage.State{})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig).To(Equal(commands.AWSUpConfig{
+							AccessKeyID:     "some-access-key-id",
+							SecretAccessKey: "some-secret-access-key",
+							Region:          "some-region",
+							VPCID:           "some-vpc-id",
+							SubnetIDs:       []string{"some-subnet-1", "some-subnet-2"},
+							Terraform:       true,
+						}))
+					})
+				})
+
+				Context("when --aws-iam-instance-profile is specified", func() {
+					It("executes the AWS up with an IAM instance profile for the director", func() {
+						err := command.Execute(context.Background(), []string{
+							"--iaas", "aws",
+							"--aws-access-key-id", "some-access-key-id",
+							"--aws-secret-access-key", "some-secret-access-key",
+							"--aws-region", "some-region",
+							"--aws-iam-instance-profile",
+							"--terraform",
+						}, storage.State{})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeAWSUp.ExecuteCall.Receives.AWSUpConfig).To(Equal(commands.AWSUpConfig{
+							AccessKeyID:        "some-access-key-id",
+							SecretAccessKey:    "some-secret-access-key",
+							Region:             "some-region",
+							IAMInstanceProfile: true,
+							Terraform:          true,
+						}))
+					})
+				})
+
 				Context("when the --terraform flag is specified", func() {
 					It("executes the AWS up with terraform", func() {
-						err := command.Execute([]string{
+						err := command.Execute(context.Background(), []string{
 							"--iaas", "aws",
 							"--aws-access-key-id", "some-access-key-id",
 							"--aws-secret-access-key", "some-secret-access-key",
@@ -466,7 +675,7 @@ var _ = Describe("Up", func() {
 
 			Context("when an invalid iaas is provided", func() {
 				It("returns an error", func() {
-					err := command.Execute([]string{"--iaas", "bad-iaas"}, storage.State{})
+					err := command.Execute(context.Background(), []string{"--iaas", "bad-iaas"}, storage.State{})
 					Expect(err).To(MatchError(`"bad-iaas" is an invalid iaas type, supported values are: [gcp, aws]`))
 				})
 			})
@@ -474,12 +683,12 @@ var _ = Describe("Up", func() {
 			Context("failure cases", func() {
 				It("returns an error when the desired up command fails", func() {
 					fakeAWSUp.ExecuteCall.Returns.Error = errors.New("failed execution")
-					err := command.Execute([]string{"--iaas", "aws"}, storage.State{})
+					err := command.Execute(context.Background(), []string{"--iaas", "aws"}, storage.State{})
 					Expect(err).To(MatchError("failed execution"))
 				})
 
 				It("returns an error when undefined flags are passed", func() {
-					err := command.Execute([]string{"--foo", "bar"}, storage.State{})
+					err := command.Execute(context.Background(), []string{"--foo", "bar"}, storage.State{})
 					Expect(err).To(MatchError("flag provided but not defined: -foo"))
 				})
 			})
@@ -501,7 +710,7 @@ var _ = Describe("Up", func() {
 				})
 
 				It("executes the AWS up", func() {
-					err := command.Execute([]string{}, state)
+					err := command.Execute(context.Background(), []string{}, state)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeAWSUp.ExecuteCall.CallCount).To(Equal(1))
@@ -520,7 +729,7 @@ var _ = Describe("Up", func() {
 
 			Context("when iaas is GCP", func() {
 				It("executes the GCP up", func() {
-					err := command.Execute([]string{}, storage.State{IAAS: "gcp"})
+					err := command.Execute(context.Background(), []string{}, storage.State{IAAS: "gcp"})
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(fakeGCPUp.ExecuteCall.CallCount).To(Equal(1))
@@ -533,7 +742,7 @@ var _ = Describe("Up", func() {
 
 		Context("when the user provides the name flag", func() {
 			It("passes the name flag in the up config", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "aws",
 					"--name", "a-better-name",
 				}, storage.State{})
@@ -545,7 +754,7 @@ var _ = Describe("Up", func() {
 
 		Context("when the user provides the no-director flag", func() {
 			It("passes no-director as true in the up config", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "aws",
 					"--no-director",
 				}, storage.State{})
@@ -555,7 +764,7 @@ var _ = Describe("Up", func() {
 			})
 
 			It("passes no-director as true in the up config", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "gcp",
 					"--no-director",
 				}, storage.State{})
@@ -567,7 +776,7 @@ var _ = Describe("Up", func() {
 
 		Context("when the user provides the jumpbox flag", func() {
 			It("passes jumpbox as true in the up config", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--iaas", "gcp",
 					"--jumpbox",
 				}, storage.State{})
@@ -576,5 +785,74 @@ var _ = Describe("Up", func() {
 				Expect(fakeGCPUp.ExecuteCall.Receives.GCPUpConfig.Jumpbox).To(Equal(true))
 			})
 		})
+
+		Context("when the load balancer certificate is managed by acme", func() {
+			BeforeEach(func() {
+				state = storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						Type:   "cf",
+						Cert:   "some-old-cert",
+						Domain: "example.com",
+						ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+					},
+				}
+
+				acmeManager.IssueCall.Returns.State = storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						Type:   "cf",
+						Cert:   "some-renewed-cert",
+						Domain: "example.com",
+						ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+					},
+				}
+			})
+
+			It("renews the certificate when it is about to expire", func() {
+				acmeManager.NeedsRenewalCall.Returns.NeedsRenewal = true
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(acmeManager.NeedsRenewalCall.Receives.State).To(Equal(state))
+				Expect(acmeManager.IssueCall.Receives.State).To(Equal(state))
+				Expect(fakeGCPUp.ExecuteCall.Receives.State.LB.Cert).To(Equal("some-renewed-cert"))
+			})
+
+			It("keeps the certificate when it is not about to expire", func() {
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(acmeManager.IssueCall.CallCount).To(Equal(0))
+				Expect(fakeGCPUp.ExecuteCall.Receives.State).To(Equal(state))
+			})
+
+			It("does not check certificates that are not managed by acme", func() {
+				state.LB.ACME = nil
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(acmeManager.NeedsRenewalCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be renewed", func() {
+				acmeManager.NeedsRenewalCall.Returns.NeedsRenewal = true
+				acmeManager.IssueCall.Returns.Error = errors.New("rate limited")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to renew the acme certificate: rate limited"))
+
+				Expect(fakeGCPUp.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the certificate cannot be parsed", func() {
+				acmeManager.NeedsRenewalCall.Returns.Error = errors.New("failed to parse the load balancer certificate")
+
+				err := command.Execute(context.Background(), []string{}, state)
+				Expect(err).To(MatchError("failed to parse the load balancer certificate"))
+			})
+		})
 	})
 })
@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"context"
+	"errors"
+
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -13,6 +16,7 @@ type updateLBConfig struct {
 	chainPath     string
 	domain        string
 	skipIfMissing bool
+	acmeRenew     bool
 }
 
 type UpdateLBs struct {
@@ -22,18 +26,22 @@ type UpdateLBs struct {
 	stateValidator       stateValidator
 	logger               logger
 	boshManager          boshManager
+	acmeManager          acmeManager
+	terraformManager     terraformManager
+	stateStore           stateStore
 }
 
 type awsUpdateLBs interface {
-	Execute(AWSCreateLBsConfig, storage.State) error
+	Execute(context.Context, AWSCreateLBsConfig, storage.State) error
 }
 
 type gcpUpdateLBs interface {
-	Execute(GCPCreateLBsConfig, storage.State) error
+	Execute(context.Context, GCPCreateLBsConfig, storage.State) error
 }
 
 func NewUpdateLBs(awsUpdateLBs awsUpdateLBs, gcpUpdateLBs gcpUpdateLBs, certificateValidator certificateValidator,
-	stateValidator stateValidator, logger logger, boshManager boshManager) UpdateLBs {
+	stateValidator stateValidator, logger logger, boshManager boshManager, acmeManager acmeManager,
+	terraformManager terraformManager, stateStore stateStore) UpdateLBs {
 
 	return UpdateLBs{
 		awsUpdateLBs:         awsUpdateLBs,
@@ -42,10 +50,13 @@ func NewUpdateLBs(awsUpdateLBs awsUpdateLBs, gcpUpdateLBs gcpUpdateLBs, certific
 		stateValidator:       stateValidator,
 		logger:               logger,
 		boshManager:          boshManager,
+		acmeManager:          acmeManager,
+		terraformManager:     terraformManager,
+		stateStore:           stateStore,
 	}
 }
 
-func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error {
+func (u UpdateLBs) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	config, err := u.parseFlags(subcommandFlags)
 	if err != nil {
 		return err
@@ -57,9 +68,13 @@ func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error
 		return nil
 	}
 
+	if config.acmeRenew {
+		return u.renewACMECertificate(ctx, state)
+	}
+
 	switch state.IAAS {
 	case "gcp":
-		if err := u.gcpUpdateLBs.Execute(GCPCreateLBsConfig{
+		if err := u.gcpUpdateLBs.Execute(ctx, GCPCreateLBsConfig{
 			LBType:   state.LB.Type,
 			CertPath: config.certPath,
 			KeyPath:  config.keyPath,
@@ -68,7 +83,7 @@ func (u UpdateLBs) Execute(subcommandFlags []string, state storage.State) error
 			return err
 		}
 	case "aws":
-		if err := u.awsUpdateLBs.Execute(AWSCreateLBsConfig{
+		if err := u.awsUpdateLBs.Execute(ctx, AWSCreateLBsConfig{
 			LBType:    state.Stack.LBType,
 			CertPath:  config.certPath,
 			KeyPath:   config.keyPath,
@@ -108,7 +123,16 @@ func (u UpdateLBs) CheckFastFails(subcommandFlags []string, state storage.State)
 		return LBNotFound
 	}
 
-	err = u.certificateValidator.Validate(UpdateLBsCommand, config.certPath, config.keyPath, config.chainPath)
+	if config.acmeRenew {
+		return u.checkACMERenew(config, state)
+	}
+
+	domain := config.domain
+	if domain == "" {
+		domain = state.LB.Domain
+	}
+
+	err = u.certificateValidator.Validate(UpdateLBsCommand, config.certPath, config.keyPath, config.chainPath, domain)
 	if err != nil {
 		return err
 	}
@@ -116,6 +140,29 @@ func (u UpdateLBs) CheckFastFails(subcommandFlags []string, state storage.State)
 	return nil
 }
 
+func (UpdateLBs) checkACMERenew(config updateLBConfig, state storage.State) error {
+	if config.certPath != "" || config.keyPath != "" || config.chainPath != "" || config.domain != "" {
+		return errors.New("--acme-renew cannot be used with --cert, --key, --chain or --domain")
+	}
+
+	if state.LB.ACME == nil {
+		return errors.New("--acme-renew requires a load balancer created with create-lbs --acme")
+	}
+
+	return nil
+}
+
+func (u UpdateLBs) renewACMECertificate(ctx context.Context, state storage.State) error {
+	u.logger.Step("renewing the acme certificate for %s", state.LB.Domain)
+
+	state, err := renewACMECertificate(ctx, u.acmeManager, u.terraformManager, state)
+	if err != nil {
+		return handleTerraformError(err, u.stateStore)
+	}
+
+	return u.stateStore.Set(state)
+}
+
 func (UpdateLBs) parseFlags(subcommandFlags []string) (updateLBConfig, error) {
 	lbFlags := flags.New("update-lbs")
 
@@ -125,6 +172,7 @@ func (UpdateLBs) parseFlags(subcommandFlags []string) (updateLBConfig, error) {
 	lbFlags.String(&config.chainPath, "chain", "")
 	lbFlags.String(&config.domain, "domain", "")
 	lbFlags.Bool(&config.skipIfMissing, "skip-if-missing", "", false)
+	lbFlags.Bool(&config.acmeRenew, "", "acme-renew", false)
 
 	err := lbFlags.Parse(subcommandFlags)
 	if err != nil {
@@ -1,11 +1,13 @@
 package commands_test
 
 import (
+	"context"
 	"errors"
 
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,6 +22,9 @@ var _ = Describe("Update LBs", func() {
 		logger               *fakes.Logger
 		awsUpdateLBs         *fakes.AWSUpdateLBs
 		gcpUpdateLBs         *fakes.GCPUpdateLBs
+		acmeManager          *fakes.ACMEManager
+		terraformManager     *fakes.TerraformManager
+		stateStore           *fakes.StateStore
 	)
 
 	BeforeEach(func() {
@@ -29,8 +34,12 @@ var _ = Describe("Update LBs", func() {
 		boshManager = &fakes.BOSHManager{}
 		awsUpdateLBs = &fakes.AWSUpdateLBs{}
 		gcpUpdateLBs = &fakes.GCPUpdateLBs{}
+		acmeManager = &fakes.ACMEManager{}
+		terraformManager = &fakes.TerraformManager{}
+		stateStore = &fakes.StateStore{}
 
-		command = commands.NewUpdateLBs(awsUpdateLBs, gcpUpdateLBs, certificateValidator, stateValidator, logger, boshManager)
+		command = commands.NewUpdateLBs(awsUpdateLBs, gcpUpdateLBs, certificateValidator, stateValidator, logger, boshManager,
+			acmeManager, terraformManager, stateStore)
 	})
 
 	Describe("CheckFastFails", func() {
@@ -71,6 +80,7 @@ var _ = Describe("Update LBs", func() {
 				"--cert", "/path/to/cert",
 				"--key", "/path/to/key",
 				"--chain", "/path/to/chain",
+				"--domain", "some-domain",
 			}, incomingState)
 
 			Expect(err).To(MatchError("failed to validate"))
@@ -78,6 +88,18 @@ var _ = Describe("Update LBs", func() {
 			Expect(certificateValidator.ValidateCall.Receives.CertificatePath).To(Equal("/path/to/cert"))
 			Expect(certificateValidator.ValidateCall.Receives.KeyPath).To(Equal("/path/to/key"))
 			Expect(certificateValidator.ValidateCall.Receives.ChainPath).To(Equal("/path/to/chain"))
+			Expect(certificateValidator.ValidateCall.Receives.Domain).To(Equal("some-domain"))
+		})
+
+		It("validates the certificate against the domain in the state when --domain is not provided", func() {
+			incomingState.LB.Domain = "some-state-domain"
+			err := command.CheckFastFails([]string{
+				"--cert", "/path/to/cert",
+				"--key", "/path/to/key",
+			}, incomingState)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(certificateValidator.ValidateCall.Receives.Domain).To(Equal("some-state-domain"))
 		})
 
 		It("returns an error if there is no lb", func() {
@@ -120,11 +142,43 @@ var _ = Describe("Update LBs", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
+
+		Context("when --acme-renew is provided", func() {
+			BeforeEach(func() {
+				incomingState = storage.State{
+					IAAS: "gcp",
+					LB: storage.LB{
+						Type:   "cf",
+						Domain: "example.com",
+						ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+					},
+				}
+			})
+
+			It("does not validate certificate files", func() {
+				err := command.CheckFastFails([]string{"--acme-renew"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(certificateValidator.ValidateCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when combined with certificate flags", func() {
+				err := command.CheckFastFails([]string{"--acme-renew", "--cert", "/path/to/cert"}, incomingState)
+				Expect(err).To(MatchError("--acme-renew cannot be used with --cert, --key, --chain or --domain"))
+			})
+
+			It("returns an error when the certificate is not managed by acme", func() {
+				incomingState.LB.ACME = nil
+
+				err := command.CheckFastFails([]string{"--acme-renew"}, incomingState)
+				Expect(err).To(MatchError("--acme-renew requires a load balancer created with create-lbs --acme"))
+			})
+		})
 	})
 
 	Describe("Execute", func() {
 		It("updates a GCP cf lb type is the iaas if GCP and type is cf", func() {
-			err := command.Execute([]string{
+			err := command.Execute(context.Background(), []string{
 				"--cert", "my-cert",
 				"--key", "my-key",
 				"--domain", "some-domain",
@@ -144,7 +198,7 @@ var _ = Describe("Update LBs", func() {
 		})
 
 		It("creates an AWS lb type if the iaas is AWS", func() {
-			err := command.Execute([]string{
+			err := command.Execute(context.Background(), []string{
 				"--cert", "my-cert",
 				"--key", "my-key",
 				"--chain", "my-chain",
@@ -166,7 +220,7 @@ var _ = Describe("Update LBs", func() {
 
 		Context("when --skip-if-missing is provided", func() {
 			It("returns no error when lb does not exist", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--skip-if-missing",
 				}, storage.State{})
 				Expect(err).NotTo(HaveOccurred())
@@ -175,9 +229,70 @@ var _ = Describe("Update LBs", func() {
 			})
 		})
 
+		Context("when --acme-renew is provided", func() {
+			var incomingState storage.State
+
+			BeforeEach(func() {
+				incomingState = storage.State{
+					IAAS: "aws",
+					LB: storage.LB{
+						Type:   "cf",
+						Cert:   "some-old-cert",
+						Domain: "example.com",
+						ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+					},
+				}
+
+				acmeManager.IssueCall.Returns.State = storage.State{
+					IAAS: "aws",
+					LB: storage.LB{
+						Type:   "cf",
+						Cert:   "some-new-cert",
+						Domain: "example.com",
+						ACME:   &storage.ACME{DirectoryURL: "https://localhost:14000/dir"},
+					},
+				}
+				terraformManager.ApplyCall.Returns.BBLState = storage.State{TFState: "some-tf-state"}
+			})
+
+			It("issues a new certificate, applies it and saves the state", func() {
+				err := command.Execute(context.Background(), []string{"--acme-renew"}, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger.StepCall.Messages).To(ContainElement("renewing the acme certificate for example.com"))
+				Expect(acmeManager.IssueCall.Receives.State).To(Equal(incomingState))
+				Expect(terraformManager.ApplyCall.Receives.BBLState).To(Equal(acmeManager.IssueCall.Returns.State))
+				Expect(stateStore.SetCall.Receives[0].State).To(Equal(storage.State{TFState: "some-tf-state"}))
+
+				Expect(awsUpdateLBs.ExecuteCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error and leaves the state alone when issuing fails", func() {
+				acmeManager.IssueCall.Returns.Error = errors.New("rate limited")
+
+				err := command.Execute(context.Background(), []string{"--acme-renew"}, incomingState)
+				Expect(err).To(MatchError(ContainSubstring("failed to issue the acme certificate: rate limited")))
+
+				Expect(terraformManager.ApplyCall.CallCount).To(Equal(0))
+				Expect(stateStore.SetCall.CallCount).To(Equal(0))
+			})
+
+			It("saves the state when terraform fails", func() {
+				terraformExecutorError := &fakes.TerraformExecutorError{}
+				terraformExecutorError.TFStateCall.Returns.TFState = "some-partial-tf-state"
+				terraformExecutorError.ErrorCall.Returns = "failed to apply"
+				terraformManager.ApplyCall.Returns.Error = terraform.NewManagerError(incomingState, terraformExecutorError)
+
+				err := command.Execute(context.Background(), []string{"--acme-renew"}, incomingState)
+				Expect(err).To(MatchError("failed to apply"))
+
+				Expect(stateStore.SetCall.Receives[0].State.TFState).To(Equal("some-partial-tf-state"))
+			})
+		})
+
 		Describe("failure cases", func() {
 			It("returns an error when invalid flags are provided", func() {
-				err := command.Execute([]string{
+				err := command.Execute(context.Background(), []string{
 					"--invalid-flag",
 				}, storage.State{})
 
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -18,7 +19,15 @@ Global Options:
   --help      [-h]       Prints usage
   --state-dir            Directory containing bbl-state.json
   --debug                Prints debugging output
+  --log-level            Minimum level of log messages to print: debug, info, warn or error (default: info)
+  --log-format           Format of log messages: text or json (default: text)
+  --events               Emits progress events as ndjson to stdout, or to a file with ndjson=<path>
+  --timeout              Maximum duration of each terraform, create-env and cloud-config phase, e.g. 45m (default: none)
+  --terraform-timeout    Maximum duration of terraform apply and destroy (default: --timeout)
+  --create-env-timeout   Maximum duration of bosh create-env and delete-env (default: --timeout)
+  --cloud-config-timeout Maximum duration of updating the cloud config (default: --timeout)
   --version              Prints version
+  --output    [-o]       Output format for query commands: text, json or yaml (default: text)
 %s
 `
 	CommandUsage = `
@@ -29,6 +38,7 @@ Global Options:
 const GlobalUsage = `
 Commands:
   bosh-deployment-vars   Prints required variables for BOSH deployment
+  certs                  Prints the expiry dates of the certificates bbl manages
   cloud-config           Prints suggested cloud configuration for BOSH environment
   create-lbs             Attaches load balancer(s)
   delete-lbs             Deletes attached load balancer(s)
@@ -37,13 +47,20 @@ Commands:
   director-username      Prints BOSH director username
   director-password      Prints BOSH director password
   director-ca-cert       Prints BOSH director CA certificate
+  doctor                 Checks local tooling, the state directory and IaaS credentials
+  drift                  Checks the infrastructure for changes made outside of bbl
   env-id                 Prints environment ID
   latest-error           Prints the output from the latest call to terraform
+  migrate                Moves a CloudFormation environment to terraform
+  outputs                Prints all terraform or stack outputs
   print-env              Prints BOSH friendly environment variables
   rotate                 Rotates the keypair for BOSH
   help                   Prints usage
+  iam-policy             Prints the AWS policy or GCP role with the permissions bbl needs
   lbs                    Prints attached load balancer(s)
   ssh-key                Prints SSH private key
+  state                  Prints a field of the bbl state
+  status                 Checks the health of the BOSH director and its infrastructure
   up                     Deploys BOSH director on AWS
   update-lbs             Updates load balancer(s)
   version                Prints version
@@ -64,7 +81,7 @@ func (u Usage) CheckFastFails(subcommandFlags []string, state storage.State) err
 	return nil
 }
 
-func (u Usage) Execute(subcommandFlags []string, state storage.State) error {
+func (u Usage) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	u.Print()
 	return nil
 }
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 
@@ -27,7 +28,7 @@ func NewVersion(version string, logger logger) Version {
 	}
 }
 
-func (v Version) Execute(subcommandFlags []string, state storage.State) error {
+func (v Version) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	v.logger.Printf("bbl %s\n", v.version)
 	return nil
 }
@@ -1,6 +1,7 @@
 package commands_test
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 
@@ -41,7 +42,7 @@ var _ = Describe("Version", func() {
 
 			Describe("Execute", func() {
 				It("prints out dev as the version", func() {
-					err := version.Execute([]string{}, storage.State{})
+					err := version.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
@@ -58,7 +59,7 @@ var _ = Describe("Version", func() {
 
 			Describe("Execute", func() {
 				It("prints out the passed in version information", func() {
-					err := version.Execute([]string{}, storage.State{})
+					err := version.Execute(context.Background(), []string{}, storage.State{})
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(logger.PrintfCall.Messages).To(ConsistOf([]string{
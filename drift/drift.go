@@ -0,0 +1,25 @@
+package drift
+
+const (
+	Changed  = "changed"
+	Replaced = "replaced"
+	Missing  = "missing"
+	Extra    = "extra"
+)
+
+// Resource describes how a piece of infrastructure differs from what bbl
+// would create. Missing resources are in the template but not in the IaaS,
+// extra resources are in the IaaS but no longer in the template. Replaced
+// resources differ in a way that can only be fixed by recreating them.
+type Resource struct {
+	Name       string      `json:"name" yaml:"name"`
+	Status     string      `json:"status" yaml:"status"`
+	Attributes []Attribute `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+type Attribute struct {
+	Name     string `json:"name" yaml:"name"`
+	Status   string `json:"status" yaml:"status"`
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
@@ -0,0 +1,36 @@
+package fakes
+
+type AccessKeyManager struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			AccessKeyID string
+		}
+		Returns struct {
+			AccessKeyID     string
+			SecretAccessKey string
+			Error           error
+		}
+	}
+	DeleteCall struct {
+		CallCount int
+		Receives  struct {
+			AccessKeyID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (m *AccessKeyManager) Create(accessKeyID string) (string, string, error) {
+	m.CreateCall.CallCount++
+	m.CreateCall.Receives.AccessKeyID = accessKeyID
+	return m.CreateCall.Returns.AccessKeyID, m.CreateCall.Returns.SecretAccessKey, m.CreateCall.Returns.Error
+}
+
+func (m *AccessKeyManager) Delete(accessKeyID string) error {
+	m.DeleteCall.CallCount++
+	m.DeleteCall.Receives.AccessKeyID = accessKeyID
+	return m.DeleteCall.Returns.Error
+}
@@ -0,0 +1,174 @@
+package fakes
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/cloudfoundry/bosh-bootloader/acme"
+
+	cryptoacme "golang.org/x/crypto/acme"
+)
+
+type ACMEClient struct {
+	RegisterCall struct {
+		CallCount int
+		Receives  struct {
+			Account *cryptoacme.Account
+		}
+		Returns struct {
+			Account *cryptoacme.Account
+			Error   error
+		}
+	}
+
+	AuthorizeOrderCall struct {
+		CallCount int
+		Receives  struct {
+			IDs []cryptoacme.AuthzID
+		}
+		Returns struct {
+			Order *cryptoacme.Order
+			Error error
+		}
+	}
+
+	GetAuthorizationCall struct {
+		CallCount int
+		Stub      func(url string) (*cryptoacme.Authorization, error)
+		Receives  struct {
+			URLs []string
+		}
+	}
+
+	DNS01ChallengeRecordCall struct {
+		CallCount int
+		Receives  struct {
+			Tokens []string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	AcceptCall struct {
+		CallCount int
+		Receives  struct {
+			Challenges []*cryptoacme.Challenge
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	WaitAuthorizationCall struct {
+		CallCount int
+		Receives  struct {
+			URLs []string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	WaitOrderCall struct {
+		CallCount int
+		Receives  struct {
+			URL string
+		}
+		Returns struct {
+			Order *cryptoacme.Order
+			Error error
+		}
+	}
+
+	CreateOrderCertCall struct {
+		CallCount int
+		Receives  struct {
+			URL    string
+			CSR    []byte
+			Bundle bool
+		}
+		Returns struct {
+			DER   [][]byte
+			Error error
+		}
+	}
+}
+
+type NewACMEClientCall struct {
+	CallCount int
+	Receives  struct {
+		Key          crypto.Signer
+		DirectoryURL string
+	}
+}
+
+// NewACMEClientFunc returns a constructor for acme.NewIssuer that records its
+// arguments and always returns the given client.
+func NewACMEClientFunc(client *ACMEClient, call *NewACMEClientCall) func(crypto.Signer, string) acme.Client {
+	return func(key crypto.Signer, directoryURL string) acme.Client {
+		call.CallCount++
+		call.Receives.Key = key
+		call.Receives.DirectoryURL = directoryURL
+
+		return client
+	}
+}
+
+func (c *ACMEClient) Register(ctx context.Context, account *cryptoacme.Account, prompt func(string) bool) (*cryptoacme.Account, error) {
+	c.RegisterCall.CallCount++
+	c.RegisterCall.Receives.Account = account
+
+	return c.RegisterCall.Returns.Account, c.RegisterCall.Returns.Error
+}
+
+func (c *ACMEClient) AuthorizeOrder(ctx context.Context, ids []cryptoacme.AuthzID, opts ...cryptoacme.OrderOption) (*cryptoacme.Order, error) {
+	c.AuthorizeOrderCall.CallCount++
+	c.AuthorizeOrderCall.Receives.IDs = ids
+
+	return c.AuthorizeOrderCall.Returns.Order, c.AuthorizeOrderCall.Returns.Error
+}
+
+func (c *ACMEClient) GetAuthorization(ctx context.Context, url string) (*cryptoacme.Authorization, error) {
+	c.GetAuthorizationCall.CallCount++
+	c.GetAuthorizationCall.Receives.URLs = append(c.GetAuthorizationCall.Receives.URLs, url)
+
+	return c.GetAuthorizationCall.Stub(url)
+}
+
+func (c *ACMEClient) DNS01ChallengeRecord(token string) (string, error) {
+	c.DNS01ChallengeRecordCall.CallCount++
+	c.DNS01ChallengeRecordCall.Receives.Tokens = append(c.DNS01ChallengeRecordCall.Receives.Tokens, token)
+
+	return "record-for-" + token, c.DNS01ChallengeRecordCall.Returns.Error
+}
+
+func (c *ACMEClient) Accept(ctx context.Context, challenge *cryptoacme.Challenge) (*cryptoacme.Challenge, error) {
+	c.AcceptCall.CallCount++
+	c.AcceptCall.Receives.Challenges = append(c.AcceptCall.Receives.Challenges, challenge)
+
+	return challenge, c.AcceptCall.Returns.Error
+}
+
+func (c *ACMEClient) WaitAuthorization(ctx context.Context, url string) (*cryptoacme.Authorization, error) {
+	c.WaitAuthorizationCall.CallCount++
+	c.WaitAuthorizationCall.Receives.URLs = append(c.WaitAuthorizationCall.Receives.URLs, url)
+
+	return &cryptoacme.Authorization{URI: url}, c.WaitAuthorizationCall.Returns.Error
+}
+
+func (c *ACMEClient) WaitOrder(ctx context.Context, url string) (*cryptoacme.Order, error) {
+	c.WaitOrderCall.CallCount++
+	c.WaitOrderCall.Receives.URL = url
+
+	return c.WaitOrderCall.Returns.Order, c.WaitOrderCall.Returns.Error
+}
+
+func (c *ACMEClient) CreateOrderCert(ctx context.Context, url string, csr []byte, bundle bool) ([][]byte, string, error) {
+	c.CreateOrderCertCall.CallCount++
+	c.CreateOrderCertCall.Receives.URL = url
+	c.CreateOrderCertCall.Receives.CSR = csr
+	c.CreateOrderCertCall.Receives.Bundle = bundle
+
+	return c.CreateOrderCertCall.Returns.DER, "", c.CreateOrderCertCall.Returns.Error
+}
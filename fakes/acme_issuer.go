@@ -0,0 +1,38 @@
+package fakes
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/cloudfoundry/bosh-bootloader/acme"
+)
+
+type ACMEIssuer struct {
+	IssueCall struct {
+		CallCount int
+		Receives  struct {
+			AccountKey   crypto.Signer
+			DirectoryURL string
+			Email        string
+			DNS          acme.DNSProvider
+			Zone         string
+			Domains      []string
+		}
+		Returns struct {
+			Certificate acme.Certificate
+			Error       error
+		}
+	}
+}
+
+func (i *ACMEIssuer) Issue(ctx context.Context, accountKey crypto.Signer, directoryURL, email string, dns acme.DNSProvider, zone string, domains []string) (acme.Certificate, error) {
+	i.IssueCall.CallCount++
+	i.IssueCall.Receives.AccountKey = accountKey
+	i.IssueCall.Receives.DirectoryURL = directoryURL
+	i.IssueCall.Receives.Email = email
+	i.IssueCall.Receives.DNS = dns
+	i.IssueCall.Receives.Zone = zone
+	i.IssueCall.Receives.Domains = domains
+
+	return i.IssueCall.Returns.Certificate, i.IssueCall.Returns.Error
+}
@@ -0,0 +1,45 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type ACMEManager struct {
+	IssueCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
+
+	NeedsRenewalCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			NeedsRenewal bool
+			Error        error
+		}
+	}
+}
+
+func (m *ACMEManager) Issue(ctx context.Context, state storage.State) (storage.State, error) {
+	m.IssueCall.CallCount++
+	m.IssueCall.Receives.State = state
+
+	return m.IssueCall.Returns.State, m.IssueCall.Returns.Error
+}
+
+func (m *ACMEManager) NeedsRenewal(state storage.State) (bool, error) {
+	m.NeedsRenewalCall.CallCount++
+	m.NeedsRenewalCall.Receives.State = state
+
+	return m.NeedsRenewalCall.Returns.NeedsRenewal, m.NeedsRenewalCall.Returns.Error
+}
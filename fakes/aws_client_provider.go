@@ -4,7 +4,9 @@ import (
 	"github.com/cloudfoundry/bosh-bootloader/aws"
 	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
 	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
+	"github.com/cloudfoundry/bosh-bootloader/aws/elb"
 	"github.com/cloudfoundry/bosh-bootloader/aws/iam"
+	"github.com/cloudfoundry/bosh-bootloader/aws/route53"
 )
 
 type AWSClientProvider struct {
@@ -32,6 +34,18 @@ type AWSClientProvider struct {
 			IAMClient iam.Client
 		}
 	}
+	GetRoute53ClientCall struct {
+		CallCount int
+		Returns   struct {
+			Route53Client route53.Client
+		}
+	}
+	GetELBClientCall struct {
+		CallCount int
+		Returns   struct {
+			ELBClient elb.Client
+		}
+	}
 }
 
 func (c *AWSClientProvider) SetConfig(config aws.Config) {
@@ -53,3 +67,13 @@ func (c *AWSClientProvider) GetIAMClient() iam.Client {
 	c.GetIAMClientCall.CallCount++
 	return c.GetIAMClientCall.Returns.IAMClient
 }
+
+func (c *AWSClientProvider) GetRoute53Client() route53.Client {
+	c.GetRoute53ClientCall.CallCount++
+	return c.GetRoute53ClientCall.Returns.Route53Client
+}
+
+func (c *AWSClientProvider) GetELBClient() elb.Client {
+	c.GetELBClientCall.CallCount++
+	return c.GetELBClientCall.Returns.ELBClient
+}
@@ -1,12 +1,17 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type AWSDeleteLBs struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 
 		Returns struct {
@@ -15,8 +20,9 @@ type AWSDeleteLBs struct {
 	}
 }
 
-func (a *AWSDeleteLBs) Execute(state storage.State) error {
+func (a *AWSDeleteLBs) Execute(ctx context.Context, state storage.State) error {
 	a.ExecuteCall.CallCount++
+	a.ExecuteCall.Receives.Context = ctx
 	a.ExecuteCall.Receives.State = state
 	return a.ExecuteCall.Returns.Error
 }
@@ -1,6 +1,7 @@
 package fakes
 
 import (
+	"context"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -10,6 +11,7 @@ type AWSUp struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
+			Context     context.Context
 			AWSUpConfig commands.AWSUpConfig
 			State       storage.State
 		}
@@ -19,8 +21,9 @@ type AWSUp struct {
 	}
 }
 
-func (u *AWSUp) Execute(awsUpConfig commands.AWSUpConfig, state storage.State) error {
+func (u *AWSUp) Execute(ctx context.Context, awsUpConfig commands.AWSUpConfig, state storage.State) error {
 	u.ExecuteCall.CallCount++
+	u.ExecuteCall.Receives.Context = ctx
 	u.ExecuteCall.Receives.AWSUpConfig = awsUpConfig
 	u.ExecuteCall.Receives.State = state
 	return u.ExecuteCall.Returns.Error
@@ -1,6 +1,7 @@
 package fakes
 
 import (
+	"context"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -10,8 +11,9 @@ type AWSUpdateLBs struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
-			Config commands.AWSCreateLBsConfig
-			State  storage.State
+			Context context.Context
+			Config  commands.AWSCreateLBsConfig
+			State   storage.State
 		}
 		Returns struct {
 			Error error
@@ -19,8 +21,9 @@ type AWSUpdateLBs struct {
 	}
 }
 
-func (u *AWSUpdateLBs) Execute(config commands.AWSCreateLBsConfig, state storage.State) error {
+func (u *AWSUpdateLBs) Execute(ctx context.Context, config commands.AWSCreateLBsConfig, state storage.State) error {
 	u.ExecuteCall.CallCount++
+	u.ExecuteCall.Receives.Context = ctx
 	u.ExecuteCall.Receives.Config = config
 	u.ExecuteCall.Receives.State = state
 	return u.ExecuteCall.Returns.Error
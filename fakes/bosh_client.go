@@ -1,6 +1,8 @@
 package fakes
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/bosh"
 	"golang.org/x/net/proxy"
 )
@@ -9,7 +11,8 @@ type BOSHClient struct {
 	UpdateCloudConfigCall struct {
 		CallCount int
 		Receives  struct {
-			Yaml []byte
+			Context context.Context
+			Yaml    []byte
 		}
 		Returns struct {
 			Error error
@@ -32,8 +35,9 @@ type BOSHClient struct {
 	}
 }
 
-func (c *BOSHClient) UpdateCloudConfig(yaml []byte) error {
+func (c *BOSHClient) UpdateCloudConfig(ctx context.Context, yaml []byte) error {
 	c.UpdateCloudConfigCall.CallCount++
+	c.UpdateCloudConfigCall.Receives.Context = ctx
 	c.UpdateCloudConfigCall.Receives.Yaml = yaml
 	return c.UpdateCloudConfigCall.Returns.Error
 }
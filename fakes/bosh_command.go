@@ -1,14 +1,16 @@
 package fakes
 
 import (
+	"context"
 	"io"
 	"sync"
 )
 
 type BOSHCommand struct {
-	RunStub        func(stdout io.Writer, workingDirectory string, args []string) error
+	RunStub        func(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error
 	runMutex       sync.RWMutex
 	runArgsForCall []struct {
+		ctx              context.Context
 		stdout           io.Writer
 		workingDirectory string
 		args             []string
@@ -23,7 +25,7 @@ type BOSHCommand struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *BOSHCommand) Run(stdout io.Writer, workingDirectory string, args []string) error {
+func (fake *BOSHCommand) Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string) error {
 	var argsCopy []string
 	if args != nil {
 		argsCopy = make([]string, len(args))
@@ -32,15 +34,16 @@ func (fake *BOSHCommand) Run(stdout io.Writer, workingDirectory string, args []s
 	fake.runMutex.Lock()
 	ret, specificReturn := fake.runReturnsOnCall[len(fake.runArgsForCall)]
 	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		ctx              context.Context
 		stdout           io.Writer
 		workingDirectory string
 		args             []string
-	}{stdout, workingDirectory, argsCopy})
-	fake.recordInvocation("Run", []interface{}{stdout, workingDirectory, argsCopy})
+	}{ctx, stdout, workingDirectory, argsCopy})
+	fake.recordInvocation("Run", []interface{}{ctx, stdout, workingDirectory, argsCopy})
 	fake.runMutex.Unlock()
 
 	if fake.RunStub != nil {
-		return fake.RunStub(stdout, workingDirectory, args)
+		return fake.RunStub(ctx, stdout, workingDirectory, args)
 	}
 	if specificReturn {
 		return ret.result1
@@ -54,10 +57,10 @@ func (fake *BOSHCommand) RunCallCount() int {
 	return len(fake.runArgsForCall)
 }
 
-func (fake *BOSHCommand) RunArgsForCall(i int) (io.Writer, string, []string) {
+func (fake *BOSHCommand) RunArgsForCall(i int) (context.Context, io.Writer, string, []string) {
 	fake.runMutex.RLock()
 	defer fake.runMutex.RUnlock()
-	return fake.runArgsForCall[i].stdout, fake.runArgsForCall[i].workingDirectory, fake.runArgsForCall[i].args
+	return fake.runArgsForCall[i].ctx, fake.runArgsForCall[i].stdout, fake.runArgsForCall[i].workingDirectory, fake.runArgsForCall[i].args
 }
 
 func (fake *BOSHCommand) RunReturns(result1 error) {
@@ -1,12 +1,17 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/bosh"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/bosh"
+)
 
 type BOSHExecutor struct {
 	CreateEnvCall struct {
 		CallCount int
 		Receives  struct {
-			Input bosh.CreateEnvInput
+			Context context.Context
+			Input   bosh.CreateEnvInput
 		}
 		Returns struct {
 			Output bosh.CreateEnvOutput
@@ -17,7 +22,8 @@ type BOSHExecutor struct {
 	DeleteEnvCall struct {
 		CallCount int
 		Receives  struct {
-			Input bosh.DeleteEnvInput
+			Context context.Context
+			Input   bosh.DeleteEnvInput
 		}
 		Returns struct {
 			Error error
@@ -55,15 +61,17 @@ type BOSHExecutor struct {
 	}
 }
 
-func (e *BOSHExecutor) CreateEnv(input bosh.CreateEnvInput) (bosh.CreateEnvOutput, error) {
+func (e *BOSHExecutor) CreateEnv(ctx context.Context, input bosh.CreateEnvInput) (bosh.CreateEnvOutput, error) {
 	e.CreateEnvCall.CallCount++
+	e.CreateEnvCall.Receives.Context = ctx
 	e.CreateEnvCall.Receives.Input = input
 
 	return e.CreateEnvCall.Returns.Output, e.CreateEnvCall.Returns.Error
 }
 
-func (e *BOSHExecutor) DeleteEnv(input bosh.DeleteEnvInput) error {
+func (e *BOSHExecutor) DeleteEnv(ctx context.Context, input bosh.DeleteEnvInput) error {
 	e.DeleteEnvCall.CallCount++
+	e.DeleteEnvCall.Receives.Context = ctx
 	e.DeleteEnvCall.Receives.Input = input
 
 	return e.DeleteEnvCall.Returns.Error
@@ -1,12 +1,17 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type BOSHManager struct {
 	CreateCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 		Returns struct {
 			State storage.State
@@ -23,7 +28,8 @@ type BOSHManager struct {
 	DeleteCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 		Returns struct {
 			Error error
@@ -41,15 +47,17 @@ type BOSHManager struct {
 	}
 }
 
-func (b *BOSHManager) Create(state storage.State) (storage.State, error) {
+func (b *BOSHManager) Create(ctx context.Context, state storage.State) (storage.State, error) {
 	b.CreateCall.CallCount++
+	b.CreateCall.Receives.Context = ctx
 	b.CreateCall.Receives.State = state
 	state.BOSH = b.CreateCall.Returns.State.BOSH
 	return state, b.CreateCall.Returns.Error
 }
 
-func (b *BOSHManager) Delete(state storage.State) error {
+func (b *BOSHManager) Delete(ctx context.Context, state storage.State) error {
 	b.DeleteCall.CallCount++
+	b.DeleteCall.Receives.Context = ctx
 	b.DeleteCall.Receives.State = state
 	return b.DeleteCall.Returns.Error
 }
@@ -0,0 +1,52 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/ssl"
+
+type CertificateGenerator struct {
+	GenerateCACall struct {
+		CallCount int
+		Receives  struct {
+			CommonName string
+		}
+		Returns struct {
+			CAData ssl.CAData
+			Error  error
+		}
+	}
+
+	GenerateFromCACall struct {
+		CallCount int
+		Stub      func(commonName string) (ssl.KeyPair, error)
+		Receives  []GenerateFromCACallReceives
+		Returns   struct {
+			KeyPair ssl.KeyPair
+			Error   error
+		}
+	}
+}
+
+type GenerateFromCACallReceives struct {
+	CA         ssl.CAData
+	CommonName string
+}
+
+func (c *CertificateGenerator) GenerateCA(commonName string) (ssl.CAData, error) {
+	c.GenerateCACall.CallCount++
+	c.GenerateCACall.Receives.CommonName = commonName
+
+	return c.GenerateCACall.Returns.CAData, c.GenerateCACall.Returns.Error
+}
+
+func (c *CertificateGenerator) GenerateFromCA(ca ssl.CAData, commonName string) (ssl.KeyPair, error) {
+	c.GenerateFromCACall.CallCount++
+	c.GenerateFromCACall.Receives = append(c.GenerateFromCACall.Receives, GenerateFromCACallReceives{
+		CA:         ca,
+		CommonName: commonName,
+	})
+
+	if c.GenerateFromCACall.Stub != nil {
+		return c.GenerateFromCACall.Stub(commonName)
+	}
+
+	return c.GenerateFromCACall.Returns.KeyPair, c.GenerateFromCACall.Returns.Error
+}
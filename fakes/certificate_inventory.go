@@ -0,0 +1,45 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/certificates"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type CertificateInventory struct {
+	ListCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Certificates []certificates.Certificate
+			Error        error
+		}
+	}
+
+	LoadBalancerCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Certificate certificates.Certificate
+			Found       bool
+			Error       error
+		}
+	}
+}
+
+func (i *CertificateInventory) List(state storage.State) ([]certificates.Certificate, error) {
+	i.ListCall.CallCount++
+	i.ListCall.Receives.State = state
+
+	return i.ListCall.Returns.Certificates, i.ListCall.Returns.Error
+}
+
+func (i *CertificateInventory) LoadBalancer(state storage.State) (certificates.Certificate, bool, error) {
+	i.LoadBalancerCall.CallCount++
+	i.LoadBalancerCall.Receives.State = state
+
+	return i.LoadBalancerCall.Returns.Certificate, i.LoadBalancerCall.Returns.Found, i.LoadBalancerCall.Returns.Error
+}
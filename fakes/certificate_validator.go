@@ -11,15 +11,17 @@ type CertificateValidator struct {
 			CertificatePath string
 			KeyPath         string
 			ChainPath       string
+			Domain          string
 		}
 	}
 }
 
-func (c *CertificateValidator) Validate(command, certificatePath, keyPath, chainPath string) error {
+func (c *CertificateValidator) Validate(command, certificatePath, keyPath, chainPath, domain string) error {
 	c.ValidateCall.CallCount++
 	c.ValidateCall.Receives.Command = command
 	c.ValidateCall.Receives.CertificatePath = certificatePath
 	c.ValidateCall.Receives.KeyPath = keyPath
 	c.ValidateCall.Receives.ChainPath = chainPath
+	c.ValidateCall.Receives.Domain = domain
 	return c.ValidateCall.Returns.Error
 }
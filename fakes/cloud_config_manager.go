@@ -1,6 +1,8 @@
 package fakes
 
 import (
+	"context"
+
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -8,7 +10,8 @@ type CloudConfigManager struct {
 	UpdateCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 		Returns struct {
 			Error error
@@ -17,7 +20,8 @@ type CloudConfigManager struct {
 	GenerateCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 		Returns struct {
 			CloudConfig string
@@ -26,14 +30,16 @@ type CloudConfigManager struct {
 	}
 }
 
-func (c *CloudConfigManager) Update(state storage.State) error {
+func (c *CloudConfigManager) Update(ctx context.Context, state storage.State) error {
 	c.UpdateCall.CallCount++
+	c.UpdateCall.Receives.Context = ctx
 	c.UpdateCall.Receives.State = state
 	return c.UpdateCall.Returns.Error
 }
 
-func (c *CloudConfigManager) Generate(state storage.State) (string, error) {
+func (c *CloudConfigManager) Generate(ctx context.Context, state storage.State) (string, error) {
 	c.GenerateCall.CallCount++
+	c.GenerateCall.Receives.Context = ctx
 	c.GenerateCall.Receives.State = state
 	return c.GenerateCall.Returns.CloudConfig, c.GenerateCall.Returns.Error
 }
@@ -1,6 +1,10 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type Command struct {
 	CheckFastFailsCall struct {
@@ -17,6 +21,7 @@ type Command struct {
 		CallCount int
 		PassState bool
 		Receives  struct {
+			Context         context.Context
 			State           storage.State
 			SubcommandFlags []string
 		}
@@ -40,8 +45,9 @@ func (c *Command) CheckFastFails(subcommandFlags []string, state storage.State)
 	return c.CheckFastFailsCall.Returns.Error
 }
 
-func (c *Command) Execute(subcommandFlags []string, state storage.State) error {
+func (c *Command) Execute(ctx context.Context, subcommandFlags []string, state storage.State) error {
 	c.ExecuteCall.CallCount++
+	c.ExecuteCall.Receives.Context = ctx
 	c.ExecuteCall.Receives.State = state
 	c.ExecuteCall.Receives.SubcommandFlags = subcommandFlags
 
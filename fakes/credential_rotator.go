@@ -0,0 +1,56 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type CredentialRotator struct {
+	RotateCredentialsCall struct {
+		CallCount int
+		Receives  struct {
+			State                  storage.State
+			RotateAgentCredentials bool
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
+	BeginCARotationCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
+	CompleteCARotationCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			State storage.State
+			Error error
+		}
+	}
+}
+
+func (c *CredentialRotator) RotateCredentials(state storage.State, rotateAgentCredentials bool) (storage.State, error) {
+	c.RotateCredentialsCall.CallCount++
+	c.RotateCredentialsCall.Receives.State = state
+	c.RotateCredentialsCall.Receives.RotateAgentCredentials = rotateAgentCredentials
+	return c.RotateCredentialsCall.Returns.State, c.RotateCredentialsCall.Returns.Error
+}
+
+func (c *CredentialRotator) BeginCARotation(state storage.State) (storage.State, error) {
+	c.BeginCARotationCall.CallCount++
+	c.BeginCARotationCall.Receives.State = state
+	return c.BeginCARotationCall.Returns.State, c.BeginCARotationCall.Returns.Error
+}
+
+func (c *CredentialRotator) CompleteCARotation(state storage.State) (storage.State, error) {
+	c.CompleteCARotationCall.CallCount++
+	c.CompleteCARotationCall.Receives.State = state
+	return c.CompleteCARotationCall.Returns.State, c.CompleteCARotationCall.Returns.Error
+}
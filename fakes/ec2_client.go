@@ -63,6 +63,56 @@ type EC2Client struct {
 			Error  error
 		}
 	}
+
+	DescribeAccountAttributesCall struct {
+		Receives struct {
+			Input *awsec2.DescribeAccountAttributesInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeAccountAttributesOutput
+			Error  error
+		}
+	}
+
+	DescribeAddressesCall struct {
+		Receives struct {
+			Input *awsec2.DescribeAddressesInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeAddressesOutput
+			Error  error
+		}
+	}
+
+	DescribeVpcsCall struct {
+		Receives struct {
+			Input *awsec2.DescribeVpcsInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeVpcsOutput
+			Error  error
+		}
+	}
+
+	DescribeSecurityGroupsCall struct {
+		Receives struct {
+			Input *awsec2.DescribeSecurityGroupsInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeSecurityGroupsOutput
+			Error  error
+		}
+	}
+
+	DescribeRouteTablesCall struct {
+		Receives struct {
+			Input *awsec2.DescribeRouteTablesInput
+		}
+		Returns struct {
+			Output *awsec2.DescribeRouteTablesOutput
+			Error  error
+		}
+	}
 }
 
 func (c *EC2Client) ImportKeyPair(input *awsec2.ImportKeyPairInput) (*awsec2.ImportKeyPairOutput, error) {
@@ -100,3 +150,33 @@ func (c *EC2Client) DescribeInstances(input *awsec2.DescribeInstancesInput) (*aw
 
 	return c.DescribeInstancesCall.Returns.Output, c.DescribeInstancesCall.Returns.Error
 }
+
+func (c *EC2Client) DescribeAccountAttributes(input *awsec2.DescribeAccountAttributesInput) (*awsec2.DescribeAccountAttributesOutput, error) {
+	c.DescribeAccountAttributesCall.Receives.Input = input
+
+	return c.DescribeAccountAttributesCall.Returns.Output, c.DescribeAccountAttributesCall.Returns.Error
+}
+
+func (c *EC2Client) DescribeAddresses(input *awsec2.DescribeAddressesInput) (*awsec2.DescribeAddressesOutput, error) {
+	c.DescribeAddressesCall.Receives.Input = input
+
+	return c.DescribeAddressesCall.Returns.Output, c.DescribeAddressesCall.Returns.Error
+}
+
+func (c *EC2Client) DescribeVpcs(input *awsec2.DescribeVpcsInput) (*awsec2.DescribeVpcsOutput, error) {
+	c.DescribeVpcsCall.Receives.Input = input
+
+	return c.DescribeVpcsCall.Returns.Output, c.DescribeVpcsCall.Returns.Error
+}
+
+func (c *EC2Client) DescribeSecurityGroups(input *awsec2.DescribeSecurityGroupsInput) (*awsec2.DescribeSecurityGroupsOutput, error) {
+	c.DescribeSecurityGroupsCall.Receives.Input = input
+
+	return c.DescribeSecurityGroupsCall.Returns.Output, c.DescribeSecurityGroupsCall.Returns.Error
+}
+
+func (c *EC2Client) DescribeRouteTables(input *awsec2.DescribeRouteTablesInput) (*awsec2.DescribeRouteTablesOutput, error) {
+	c.DescribeRouteTablesCall.Receives.Input = input
+
+	return c.DescribeRouteTablesCall.Returns.Output, c.DescribeRouteTablesCall.Returns.Error
+}
@@ -0,0 +1,26 @@
+package fakes
+
+type ElasticIPRetriever struct {
+	AllocationIDCall struct {
+		CallCount int
+		Stub      func(string) (string, error)
+		Receives  struct {
+			PublicIP string
+		}
+		Returns struct {
+			AllocationID string
+			Error        error
+		}
+	}
+}
+
+func (r *ElasticIPRetriever) AllocationID(publicIP string) (string, error) {
+	r.AllocationIDCall.CallCount++
+	r.AllocationIDCall.Receives.PublicIP = publicIP
+
+	if r.AllocationIDCall.Stub != nil {
+		return r.AllocationIDCall.Stub(publicIP)
+	}
+
+	return r.AllocationIDCall.Returns.AllocationID, r.AllocationIDCall.Returns.Error
+}
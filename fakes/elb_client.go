@@ -0,0 +1,41 @@
+package fakes
+
+import "github.com/aws/aws-sdk-go/service/elb"
+
+type ELBClient struct {
+	DescribeAccountLimitsCall struct {
+		CallCount int
+		Receives  struct {
+			Input *elb.DescribeAccountLimitsInput
+		}
+		Returns struct {
+			Output *elb.DescribeAccountLimitsOutput
+			Error  error
+		}
+	}
+
+	DescribeLoadBalancersCall struct {
+		CallCount int
+		Receives  struct {
+			Input *elb.DescribeLoadBalancersInput
+		}
+		Returns struct {
+			Output *elb.DescribeLoadBalancersOutput
+			Error  error
+		}
+	}
+}
+
+func (c *ELBClient) DescribeAccountLimits(input *elb.DescribeAccountLimitsInput) (*elb.DescribeAccountLimitsOutput, error) {
+	c.DescribeAccountLimitsCall.CallCount++
+	c.DescribeAccountLimitsCall.Receives.Input = input
+
+	return c.DescribeAccountLimitsCall.Returns.Output, c.DescribeAccountLimitsCall.Returns.Error
+}
+
+func (c *ELBClient) DescribeLoadBalancers(input *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	c.DescribeLoadBalancersCall.CallCount++
+	c.DescribeLoadBalancersCall.Receives.Input = input
+
+	return c.DescribeLoadBalancersCall.Returns.Output, c.DescribeLoadBalancersCall.Returns.Error
+}
@@ -0,0 +1,17 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type ExpiryWarner struct {
+	WarnCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+	}
+}
+
+func (w *ExpiryWarner) Warn(state storage.State) {
+	w.WarnCall.CallCount++
+	w.WarnCall.Receives.State = state
+}
@@ -1,6 +1,10 @@
 package fakes
 
-import compute "google.golang.org/api/compute/v1"
+import (
+	compute "google.golang.org/api/compute/v1"
+	dns "google.golang.org/api/dns/v1"
+	iam "google.golang.org/api/iam/v1"
+)
 
 type GCPClient struct {
 	ProjectIDCall struct {
@@ -16,6 +20,16 @@ type GCPClient struct {
 			Error   error
 		}
 	}
+	GetRegionCall struct {
+		CallCount int
+		Receives  struct {
+			Region string
+		}
+		Returns struct {
+			Region *compute.Region
+			Error  error
+		}
+	}
 	SetCommonInstanceMetadataCall struct {
 		CallCount int
 		Receives  struct {
@@ -43,6 +57,70 @@ type GCPClient struct {
 			Error       error
 		}
 	}
+	ListResourceRecordSetsCall struct {
+		CallCount int
+		Receives  struct {
+			ManagedZone string
+			Name        string
+			RecordType  string
+		}
+		Returns struct {
+			Response *dns.ResourceRecordSetsListResponse
+			Error    error
+		}
+	}
+	CreateDNSChangeCall struct {
+		CallCount int
+		Receives  struct {
+			ManagedZone string
+			Change      *dns.Change
+		}
+		Returns struct {
+			Change *dns.Change
+			Error  error
+		}
+	}
+	GetDNSChangeCall struct {
+		CallCount int
+		Receives  struct {
+			ManagedZone string
+			ChangeID    string
+		}
+		Returns struct {
+			Change *dns.Change
+			Error  error
+		}
+	}
+	CreateServiceAccountKeyCall struct {
+		CallCount int
+		Receives  struct {
+			Email string
+		}
+		Returns struct {
+			ServiceAccountKey *iam.ServiceAccountKey
+			Error             error
+		}
+	}
+	DeleteServiceAccountKeyCall struct {
+		CallCount int
+		Receives  struct {
+			Email string
+			KeyID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+	TestProjectPermissionsCall struct {
+		CallCount int
+		Receives  struct {
+			Permissions []string
+		}
+		Returns struct {
+			Permissions []string
+			Error       error
+		}
+	}
 }
 
 func (g *GCPClient) ProjectID() string {
@@ -55,6 +133,12 @@ func (g *GCPClient) GetProject() (*compute.Project, error) {
 	return g.GetProjectCall.Returns.Project, g.GetProjectCall.Returns.Error
 }
 
+func (g *GCPClient) GetRegion(region string) (*compute.Region, error) {
+	g.GetRegionCall.CallCount++
+	g.GetRegionCall.Receives.Region = region
+	return g.GetRegionCall.Returns.Region, g.GetRegionCall.Returns.Error
+}
+
 func (g *GCPClient) SetCommonInstanceMetadata(metadata *compute.Metadata) (*compute.Operation, error) {
 	g.SetCommonInstanceMetadataCall.CallCount++
 	g.SetCommonInstanceMetadataCall.Receives.Metadata = metadata
@@ -71,3 +155,44 @@ func (g *GCPClient) GetNetworks(name string) (*compute.NetworkList, error) {
 	g.GetNetworksCall.Receives.Name = name
 	return g.GetNetworksCall.Returns.NetworkList, g.GetNetworksCall.Returns.Error
 }
+
+func (g *GCPClient) ListResourceRecordSets(managedZone, name, recordType string) (*dns.ResourceRecordSetsListResponse, error) {
+	g.ListResourceRecordSetsCall.CallCount++
+	g.ListResourceRecordSetsCall.Receives.ManagedZone = managedZone
+	g.ListResourceRecordSetsCall.Receives.Name = name
+	g.ListResourceRecordSetsCall.Receives.RecordType = recordType
+	return g.ListResourceRecordSetsCall.Returns.Response, g.ListResourceRecordSetsCall.Returns.Error
+}
+
+func (g *GCPClient) CreateDNSChange(managedZone string, change *dns.Change) (*dns.Change, error) {
+	g.CreateDNSChangeCall.CallCount++
+	g.CreateDNSChangeCall.Receives.ManagedZone = managedZone
+	g.CreateDNSChangeCall.Receives.Change = change
+	return g.CreateDNSChangeCall.Returns.Change, g.CreateDNSChangeCall.Returns.Error
+}
+
+func (g *GCPClient) GetDNSChange(managedZone, changeID string) (*dns.Change, error) {
+	g.GetDNSChangeCall.CallCount++
+	g.GetDNSChangeCall.Receives.ManagedZone = managedZone
+	g.GetDNSChangeCall.Receives.ChangeID = changeID
+	return g.GetDNSChangeCall.Returns.Change, g.GetDNSChangeCall.Returns.Error
+}
+
+func (g *GCPClient) CreateServiceAccountKey(email string) (*iam.ServiceAccountKey, error) {
+	g.CreateServiceAccountKeyCall.CallCount++
+	g.CreateServiceAccountKeyCall.Receives.Email = email
+	return g.CreateServiceAccountKeyCall.Returns.ServiceAccountKey, g.CreateServiceAccountKeyCall.Returns.Error
+}
+
+func (g *GCPClient) DeleteServiceAccountKey(email, keyID string) error {
+	g.DeleteServiceAccountKeyCall.CallCount++
+	g.DeleteServiceAccountKeyCall.Receives.Email = email
+	g.DeleteServiceAccountKeyCall.Receives.KeyID = keyID
+	return g.DeleteServiceAccountKeyCall.Returns.Error
+}
+
+func (g *GCPClient) TestProjectPermissions(permissions []string) ([]string, error) {
+	g.TestProjectPermissionsCall.CallCount++
+	g.TestProjectPermissionsCall.Receives.Permissions = permissions
+	return g.TestProjectPermissionsCall.Returns.Permissions, g.TestProjectPermissionsCall.Returns.Error
+}
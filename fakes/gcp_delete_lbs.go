@@ -1,12 +1,17 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/storage"
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
 
 type GCPDeleteLBs struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
-			State storage.State
+			Context context.Context
+			State   storage.State
 		}
 
 		Returns struct {
@@ -15,8 +20,9 @@ type GCPDeleteLBs struct {
 	}
 }
 
-func (g *GCPDeleteLBs) Execute(state storage.State) error {
+func (g *GCPDeleteLBs) Execute(ctx context.Context, state storage.State) error {
 	g.ExecuteCall.CallCount++
+	g.ExecuteCall.Receives.Context = ctx
 	g.ExecuteCall.Receives.State = state
 	return g.ExecuteCall.Returns.Error
 }
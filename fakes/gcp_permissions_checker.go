@@ -0,0 +1,22 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type GCPPermissionsChecker struct {
+	CheckCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (g *GCPPermissionsChecker) Check(state storage.State) error {
+	g.CheckCall.CallCount++
+	g.CheckCall.Receives.State = state
+
+	return g.CheckCall.Returns.Error
+}
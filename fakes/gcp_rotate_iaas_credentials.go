@@ -0,0 +1,27 @@
+package fakes
+
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type GCPRotateIAASCredentials struct {
+	ExecuteCall struct {
+		CallCount int
+		Receives  struct {
+			Context context.Context
+			State   storage.State
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (r *GCPRotateIAASCredentials) Execute(ctx context.Context, state storage.State) error {
+	r.ExecuteCall.CallCount++
+	r.ExecuteCall.Receives.Context = ctx
+	r.ExecuteCall.Receives.State = state
+	return r.ExecuteCall.Returns.Error
+}
@@ -1,6 +1,7 @@
 package fakes
 
 import (
+	"context"
 	"github.com/cloudfoundry/bosh-bootloader/commands"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
@@ -10,6 +11,7 @@ type GCPUp struct {
 	ExecuteCall struct {
 		CallCount int
 		Receives  struct {
+			Context     context.Context
 			GCPUpConfig commands.GCPUpConfig
 			State       storage.State
 		}
@@ -19,8 +21,9 @@ type GCPUp struct {
 	}
 }
 
-func (u *GCPUp) Execute(gcpUpConfig commands.GCPUpConfig, state storage.State) error {
+func (u *GCPUp) Execute(ctx context.Context, gcpUpConfig commands.GCPUpConfig, state storage.State) error {
 	u.ExecuteCall.CallCount++
+	u.ExecuteCall.Receives.Context = ctx
 	u.ExecuteCall.Receives.GCPUpConfig = gcpUpConfig
 	u.ExecuteCall.Receives.State = state
 	return u.ExecuteCall.Returns.Error
@@ -35,6 +35,39 @@ type IAMClient struct {
 			Error  error
 		}
 	}
+
+	CreateAccessKeyCall struct {
+		CallCount int
+		Receives  struct {
+			Input *iam.CreateAccessKeyInput
+		}
+		Returns struct {
+			Output *iam.CreateAccessKeyOutput
+			Error  error
+		}
+	}
+
+	DeleteAccessKeyCall struct {
+		CallCount int
+		Receives  struct {
+			Input *iam.DeleteAccessKeyInput
+		}
+		Returns struct {
+			Output *iam.DeleteAccessKeyOutput
+			Error  error
+		}
+	}
+
+	GetAccessKeyLastUsedCall struct {
+		CallCount int
+		Receives  struct {
+			Input *iam.GetAccessKeyLastUsedInput
+		}
+		Returns struct {
+			Output *iam.GetAccessKeyLastUsedOutput
+			Error  error
+		}
+	}
 }
 
 func (c *IAMClient) UploadServerCertificate(input *iam.UploadServerCertificateInput) (*iam.UploadServerCertificateOutput, error) {
@@ -54,3 +87,21 @@ func (c *IAMClient) DeleteServerCertificate(input *iam.DeleteServerCertificateIn
 	c.DeleteServerCertificateCall.Receives.Input = input
 	return c.DeleteServerCertificateCall.Returns.Output, c.DeleteServerCertificateCall.Returns.Error
 }
+
+func (c *IAMClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
+	c.CreateAccessKeyCall.CallCount++
+	c.CreateAccessKeyCall.Receives.Input = input
+	return c.CreateAccessKeyCall.Returns.Output, c.CreateAccessKeyCall.Returns.Error
+}
+
+func (c *IAMClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
+	c.DeleteAccessKeyCall.CallCount++
+	c.DeleteAccessKeyCall.Receives.Input = input
+	return c.DeleteAccessKeyCall.Returns.Output, c.DeleteAccessKeyCall.Returns.Error
+}
+
+func (c *IAMClient) GetAccessKeyLastUsed(input *iam.GetAccessKeyLastUsedInput) (*iam.GetAccessKeyLastUsedOutput, error) {
+	c.GetAccessKeyLastUsedCall.CallCount++
+	c.GetAccessKeyLastUsedCall.Receives.Input = input
+	return c.GetAccessKeyLastUsedCall.Returns.Output, c.GetAccessKeyLastUsedCall.Returns.Error
+}
@@ -0,0 +1,46 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/iampolicy"
+
+type IAMPolicyGenerator struct {
+	AWSCall struct {
+		CallCount int
+		Receives  struct {
+			LBType string
+			Domain string
+		}
+		Returns struct {
+			Policy iampolicy.AWSPolicy
+			Error  error
+		}
+	}
+	GCPCall struct {
+		CallCount int
+		Receives  struct {
+			LBType string
+			Domain string
+			Region string
+		}
+		Returns struct {
+			Role  iampolicy.GCPRole
+			Error error
+		}
+	}
+}
+
+func (i *IAMPolicyGenerator) AWS(lbType, domain string) (iampolicy.AWSPolicy, error) {
+	i.AWSCall.CallCount++
+	i.AWSCall.Receives.LBType = lbType
+	i.AWSCall.Receives.Domain = domain
+
+	return i.AWSCall.Returns.Policy, i.AWSCall.Returns.Error
+}
+
+func (i *IAMPolicyGenerator) GCP(lbType, domain, region string) (iampolicy.GCPRole, error) {
+	i.GCPCall.CallCount++
+	i.GCPCall.Receives.LBType = lbType
+	i.GCPCall.Receives.Domain = domain
+	i.GCPCall.Receives.Region = region
+
+	return i.GCPCall.Returns.Role, i.GCPCall.Returns.Error
+}
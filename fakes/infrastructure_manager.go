@@ -1,6 +1,9 @@
 package fakes
 
-import "github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+import (
+	"github.com/cloudfoundry/bosh-bootloader/aws/cloudformation"
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+)
 
 type InfrastructureManager struct {
 	CreateCall struct {
@@ -60,7 +63,8 @@ type InfrastructureManager struct {
 	}
 
 	DescribeCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			StackName string
 		}
 		Returns struct {
@@ -68,6 +72,39 @@ type InfrastructureManager struct {
 			Error error
 		}
 	}
+
+	DriftCall struct {
+		CallCount int
+		Receives  struct {
+			KeyPairName      string
+			AZs              []string
+			StackName        string
+			LBType           string
+			LBCertificateARN string
+			BOSHAZ           string
+			EnvID            string
+		}
+		Returns struct {
+			Resources []drift.Resource
+			Error     error
+		}
+	}
+
+	DetachCall struct {
+		CallCount int
+		Receives  struct {
+			KeyPairName      string
+			AZs              []string
+			StackName        string
+			LBType           string
+			LBCertificateARN string
+			BOSHAZ           string
+			EnvID            string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func (m *InfrastructureManager) Create(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) (cloudformation.Stack, error) {
@@ -114,7 +151,34 @@ func (m *InfrastructureManager) Delete(stackName string) error {
 }
 
 func (m *InfrastructureManager) Describe(stackName string) (cloudformation.Stack, error) {
+	m.DescribeCall.CallCount++
 	m.DescribeCall.Receives.StackName = stackName
 
 	return m.DescribeCall.Returns.Stack, m.DescribeCall.Returns.Error
 }
+
+func (m *InfrastructureManager) Drift(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) ([]drift.Resource, error) {
+	m.DriftCall.CallCount++
+	m.DriftCall.Receives.KeyPairName = keyPairName
+	m.DriftCall.Receives.AZs = azs
+	m.DriftCall.Receives.StackName = stackName
+	m.DriftCall.Receives.LBType = lbType
+	m.DriftCall.Receives.LBCertificateARN = lbCertificateARN
+	m.DriftCall.Receives.BOSHAZ = boshAZ
+	m.DriftCall.Receives.EnvID = envID
+
+	return m.DriftCall.Returns.Resources, m.DriftCall.Returns.Error
+}
+
+func (m *InfrastructureManager) Detach(keyPairName string, azs []string, stackName, boshAZ, lbType, lbCertificateARN, envID string) error {
+	m.DetachCall.CallCount++
+	m.DetachCall.Receives.KeyPairName = keyPairName
+	m.DetachCall.Receives.AZs = azs
+	m.DetachCall.Receives.StackName = stackName
+	m.DetachCall.Receives.LBType = lbType
+	m.DetachCall.Receives.LBCertificateARN = lbCertificateARN
+	m.DetachCall.Receives.BOSHAZ = boshAZ
+	m.DetachCall.Receives.EnvID = envID
+
+	return m.DetachCall.Returns.Error
+}
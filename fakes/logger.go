@@ -45,6 +45,15 @@ type Logger struct {
 			Message string
 		}
 	}
+
+	WarnCall struct {
+		CallCount int
+		Receives  struct {
+			Message   string
+			Arguments []interface{}
+		}
+		Messages []string
+	}
 }
 
 func (l *Logger) Step(message string, a ...interface{}) {
@@ -86,6 +95,14 @@ func (l *Logger) Prompt(message string) {
 	l.PromptCall.Receives.Message = message
 }
 
+func (l *Logger) Warn(message string, a ...interface{}) {
+	l.WarnCall.CallCount++
+	l.WarnCall.Receives.Message = message
+	l.WarnCall.Receives.Arguments = a
+
+	l.WarnCall.Messages = append(l.WarnCall.Messages, fmt.Sprintf(message, a...))
+}
+
 func (l *Logger) PrintlnMessages() []string {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
@@ -0,0 +1,31 @@
+package fakes
+
+type OutputWriter struct {
+	IsTextCall struct {
+		CallCount int
+		Returns   struct {
+			IsText bool
+		}
+	}
+
+	WriteCall struct {
+		CallCount int
+		Receives  struct {
+			Value interface{}
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (o *OutputWriter) IsText() bool {
+	o.IsTextCall.CallCount++
+	return o.IsTextCall.Returns.IsText
+}
+
+func (o *OutputWriter) Write(value interface{}) error {
+	o.WriteCall.CallCount++
+	o.WriteCall.Receives.Value = value
+	return o.WriteCall.Returns.Error
+}
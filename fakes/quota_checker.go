@@ -0,0 +1,22 @@
+package fakes
+
+import "github.com/cloudfoundry/bosh-bootloader/storage"
+
+type QuotaChecker struct {
+	CheckCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (q *QuotaChecker) Check(state storage.State) error {
+	q.CheckCall.CallCount++
+	q.CheckCall.Receives.State = state
+
+	return q.CheckCall.Returns.Error
+}
@@ -0,0 +1,26 @@
+package fakes
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+type QuotaRetriever struct {
+	RetrieveCall struct {
+		CallCount int
+		Receives  struct {
+			State storage.State
+		}
+		Returns struct {
+			Quotas []quota.Quota
+			Error  error
+		}
+	}
+}
+
+func (q *QuotaRetriever) Retrieve(state storage.State) ([]quota.Quota, error) {
+	q.RetrieveCall.CallCount++
+	q.RetrieveCall.Receives.State = state
+
+	return q.RetrieveCall.Returns.Quotas, q.RetrieveCall.Returns.Error
+}
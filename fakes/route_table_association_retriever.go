@@ -0,0 +1,28 @@
+package fakes
+
+type RouteTableAssociationRetriever struct {
+	AssociationIDCall struct {
+		CallCount int
+		Stub      func(routeTableID, subnetID string) (string, error)
+		Receives  struct {
+			RouteTableID string
+			SubnetID     string
+		}
+		Returns struct {
+			AssociationID string
+			Error         error
+		}
+	}
+}
+
+func (r *RouteTableAssociationRetriever) AssociationID(routeTableID, subnetID string) (string, error) {
+	r.AssociationIDCall.CallCount++
+	r.AssociationIDCall.Receives.RouteTableID = routeTableID
+	r.AssociationIDCall.Receives.SubnetID = subnetID
+
+	if r.AssociationIDCall.Stub != nil {
+		return r.AssociationIDCall.Stub(routeTableID, subnetID)
+	}
+
+	return r.AssociationIDCall.Returns.AssociationID, r.AssociationIDCall.Returns.Error
+}
@@ -0,0 +1,35 @@
+package fakes
+
+type ServiceAccountKeyManager struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			Email string
+		}
+		Returns struct {
+			KeyFile string
+			Error   error
+		}
+	}
+	DeleteCall struct {
+		CallCount int
+		Receives  struct {
+			KeyFile string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func (m *ServiceAccountKeyManager) Create(email string) (string, error) {
+	m.CreateCall.CallCount++
+	m.CreateCall.Receives.Email = email
+	return m.CreateCall.Returns.KeyFile, m.CreateCall.Returns.Error
+}
+
+func (m *ServiceAccountKeyManager) Delete(keyFile string) error {
+	m.DeleteCall.CallCount++
+	m.DeleteCall.Receives.KeyFile = keyFile
+	return m.DeleteCall.Returns.Error
+}
@@ -71,6 +71,7 @@ type StackManager struct {
 			PhysicalResourceID string
 			Error              error
 		}
+		Stub func(string) (string, error)
 	}
 }
 
@@ -119,5 +120,9 @@ func (m *StackManager) GetPhysicalIDForResource(stackName string, logicalResourc
 	m.GetPhysicalIDForResourceCall.Receives.StackName = stackName
 	m.GetPhysicalIDForResourceCall.Receives.LogicalResourceID = logicalResourceID
 
+	if m.GetPhysicalIDForResourceCall.Stub != nil {
+		return m.GetPhysicalIDForResourceCall.Stub(logicalResourceID)
+	}
+
 	return m.GetPhysicalIDForResourceCall.Returns.PhysicalResourceID, m.GetPhysicalIDForResourceCall.Returns.Error
 }
@@ -0,0 +1,48 @@
+package fakes
+
+import (
+	"sync"
+	"time"
+)
+
+type SubprocessEvents struct {
+	mutex sync.Mutex
+
+	SubprocessStartedCall struct {
+		CallCount int
+		Receives  struct {
+			Name       string
+			Subcommand string
+		}
+	}
+
+	SubprocessExitedCall struct {
+		CallCount int
+		Receives  struct {
+			Name       string
+			Subcommand string
+			Duration   time.Duration
+			Error      error
+		}
+	}
+}
+
+func (s *SubprocessEvents) SubprocessStarted(name, subcommand string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.SubprocessStartedCall.CallCount++
+	s.SubprocessStartedCall.Receives.Name = name
+	s.SubprocessStartedCall.Receives.Subcommand = subcommand
+}
+
+func (s *SubprocessEvents) SubprocessExited(name, subcommand string, duration time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.SubprocessExitedCall.CallCount++
+	s.SubprocessExitedCall.Receives.Name = name
+	s.SubprocessExitedCall.Receives.Subcommand = subcommand
+	s.SubprocessExitedCall.Receives.Duration = duration
+	s.SubprocessExitedCall.Receives.Error = err
+}
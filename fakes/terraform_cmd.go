@@ -1,6 +1,9 @@
 package fakes
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 type TerraformCmd struct {
 	RunCall struct {
@@ -10,6 +13,7 @@ type TerraformCmd struct {
 			Error error
 		}
 		Receives struct {
+			Context          context.Context
 			Stdout           io.Writer
 			WorkingDirectory string
 			Args             []string
@@ -18,8 +22,9 @@ type TerraformCmd struct {
 	}
 }
 
-func (t *TerraformCmd) Run(stdout io.Writer, workingDirectory string, args []string, debug bool) error {
+func (t *TerraformCmd) Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string, debug bool) error {
 	t.RunCall.CallCount++
+	t.RunCall.Receives.Context = ctx
 	t.RunCall.Receives.Stdout = stdout
 	t.RunCall.Receives.WorkingDirectory = workingDirectory
 	t.RunCall.Receives.Args = args
@@ -1,9 +1,16 @@
 package fakes
 
+import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
 type TerraformExecutor struct {
 	ApplyCall struct {
 		CallCount int
 		Receives  struct {
+			Context  context.Context
 			Inputs   map[string]string
 			Template string
 			TFState  string
@@ -16,9 +23,39 @@ type TerraformExecutor struct {
 	DestroyCall struct {
 		CallCount int
 		Receives  struct {
+			Context  context.Context
+			Inputs   map[string]string
+			Template string
+			TFState  string
+		}
+		Returns struct {
+			TFState string
+			Error   error
+		}
+	}
+	PlanCall struct {
+		CallCount int
+		Receives  struct {
+			Context  context.Context
+			Inputs   map[string]string
+			Template string
+			TFState  string
+		}
+		Returns struct {
+			Plan  string
+			Error error
+		}
+	}
+	ImportCall struct {
+		CallCount int
+		Stub      func(tfState, address, id string) (string, error)
+		Receives  struct {
+			Context  context.Context
 			Inputs   map[string]string
 			Template string
 			TFState  string
+			Address  string
+			ID       string
 		}
 		Returns struct {
 			TFState string
@@ -55,24 +92,61 @@ type TerraformExecutor struct {
 			Error   error
 		}
 	}
+	DetailedOutputsCall struct {
+		CallCount int
+		Receives  struct {
+			TFState string
+		}
+		Returns struct {
+			Outputs map[string]terraform.Output
+			Error   error
+		}
+	}
 }
 
-func (t *TerraformExecutor) Apply(inputs map[string]string, template, tfState string) (string, error) {
+func (t *TerraformExecutor) Apply(ctx context.Context, inputs map[string]string, template, tfState string) (string, error) {
 	t.ApplyCall.CallCount++
+	t.ApplyCall.Receives.Context = ctx
 	t.ApplyCall.Receives.Inputs = inputs
 	t.ApplyCall.Receives.Template = template
 	t.ApplyCall.Receives.TFState = tfState
 	return t.ApplyCall.Returns.TFState, t.ApplyCall.Returns.Error
 }
 
-func (t *TerraformExecutor) Destroy(inputs map[string]string, template, tfState string) (string, error) {
+func (t *TerraformExecutor) Destroy(ctx context.Context, inputs map[string]string, template, tfState string) (string, error) {
 	t.DestroyCall.CallCount++
+	t.DestroyCall.Receives.Context = ctx
 	t.DestroyCall.Receives.Inputs = inputs
 	t.DestroyCall.Receives.Template = template
 	t.DestroyCall.Receives.TFState = tfState
 	return t.DestroyCall.Returns.TFState, t.DestroyCall.Returns.Error
 }
 
+func (t *TerraformExecutor) Import(ctx context.Context, inputs map[string]string, template, tfState, address, id string) (string, error) {
+	t.ImportCall.CallCount++
+	t.ImportCall.Receives.Context = ctx
+	t.ImportCall.Receives.Inputs = inputs
+	t.ImportCall.Receives.Template = template
+	t.ImportCall.Receives.TFState = tfState
+	t.ImportCall.Receives.Address = address
+	t.ImportCall.Receives.ID = id
+
+	if t.ImportCall.Stub != nil {
+		return t.ImportCall.Stub(tfState, address, id)
+	}
+
+	return t.ImportCall.Returns.TFState, t.ImportCall.Returns.Error
+}
+
+func (t *TerraformExecutor) Plan(ctx context.Context, inputs map[string]string, template, tfState string) (string, error) {
+	t.PlanCall.CallCount++
+	t.PlanCall.Receives.Context = ctx
+	t.PlanCall.Receives.Inputs = inputs
+	t.PlanCall.Receives.Template = template
+	t.PlanCall.Receives.TFState = tfState
+	return t.PlanCall.Returns.Plan, t.PlanCall.Returns.Error
+}
+
 func (t *TerraformExecutor) Version() (string, error) {
 	t.VersionCall.CallCount++
 	return t.VersionCall.Returns.Version, t.VersionCall.Returns.Error
@@ -100,3 +174,10 @@ func (t *TerraformExecutor) Outputs(tfState string) (map[string]interface{}, err
 
 	return t.OutputsCall.Returns.Outputs, t.OutputsCall.Returns.Error
 }
+
+func (t *TerraformExecutor) DetailedOutputs(tfState string) (map[string]terraform.Output, error) {
+	t.DetailedOutputsCall.CallCount++
+	t.DetailedOutputsCall.Receives.TFState = tfState
+
+	return t.DetailedOutputsCall.Returns.Outputs, t.DetailedOutputsCall.Returns.Error
+}
@@ -1,13 +1,18 @@
 package fakes
 
 import (
+	"context"
+
+	"github.com/cloudfoundry/bosh-bootloader/drift"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
 )
 
 type TerraformManager struct {
 	DestroyCall struct {
 		CallCount int
 		Receives  struct {
+			Context  context.Context
 			BBLState storage.State
 		}
 		Returns struct {
@@ -25,6 +30,16 @@ type TerraformManager struct {
 			Error   error
 		}
 	}
+	GetAllOutputsCall struct {
+		CallCount int
+		Receives  struct {
+			BBLState storage.State
+		}
+		Returns struct {
+			Outputs map[string]terraform.Output
+			Error   error
+		}
+	}
 	VersionCall struct {
 		CallCount int
 		Returns   struct {
@@ -33,10 +48,35 @@ type TerraformManager struct {
 		}
 	}
 	ApplyCall struct {
+		CallCount int
+		Stub      func(storage.State) (storage.State, error)
+		Receives  struct {
+			Context  context.Context
+			BBLState storage.State
+		}
+		Returns struct {
+			BBLState storage.State
+			Error    error
+		}
+	}
+	DriftCall struct {
 		CallCount int
 		Receives  struct {
+			Context  context.Context
 			BBLState storage.State
 		}
+		Returns struct {
+			Resources []drift.Resource
+			Error     error
+		}
+	}
+	ImportCall struct {
+		CallCount int
+		Receives  struct {
+			Context  context.Context
+			BBLState storage.State
+			Imports  []terraform.Import
+		}
 		Returns struct {
 			BBLState storage.State
 			Error    error
@@ -50,15 +90,21 @@ type TerraformManager struct {
 	}
 }
 
-func (t *TerraformManager) Apply(bblState storage.State) (storage.State, error) {
+func (t *TerraformManager) Apply(ctx context.Context, bblState storage.State) (storage.State, error) {
 	t.ApplyCall.CallCount++
+	t.ApplyCall.Receives.Context = ctx
 	t.ApplyCall.Receives.BBLState = bblState
 
+	if t.ApplyCall.Stub != nil {
+		return t.ApplyCall.Stub(bblState)
+	}
+
 	return t.ApplyCall.Returns.BBLState, t.ApplyCall.Returns.Error
 }
 
-func (t *TerraformManager) Destroy(bblState storage.State) (storage.State, error) {
+func (t *TerraformManager) Destroy(ctx context.Context, bblState storage.State) (storage.State, error) {
 	t.DestroyCall.CallCount++
+	t.DestroyCall.Receives.Context = ctx
 	t.DestroyCall.Receives.BBLState = bblState
 
 	return t.DestroyCall.Returns.BBLState, t.DestroyCall.Returns.Error
@@ -71,6 +117,13 @@ func (t *TerraformManager) GetOutputs(bblState storage.State) (map[string]interf
 	return t.GetOutputsCall.Returns.Outputs, t.GetOutputsCall.Returns.Error
 }
 
+func (t *TerraformManager) GetAllOutputs(bblState storage.State) (map[string]terraform.Output, error) {
+	t.GetAllOutputsCall.CallCount++
+	t.GetAllOutputsCall.Receives.BBLState = bblState
+
+	return t.GetAllOutputsCall.Returns.Outputs, t.GetAllOutputsCall.Returns.Error
+}
+
 func (t *TerraformManager) Version() (string, error) {
 	t.VersionCall.CallCount++
 	return t.VersionCall.Returns.Version, t.VersionCall.Returns.Error
@@ -80,3 +133,20 @@ func (t *TerraformManager) ValidateVersion() error {
 	t.ValidateVersionCall.CallCount++
 	return t.ValidateVersionCall.Returns.Error
 }
+
+func (t *TerraformManager) Drift(ctx context.Context, bblState storage.State) ([]drift.Resource, error) {
+	t.DriftCall.CallCount++
+	t.DriftCall.Receives.Context = ctx
+	t.DriftCall.Receives.BBLState = bblState
+
+	return t.DriftCall.Returns.Resources, t.DriftCall.Returns.Error
+}
+
+func (t *TerraformManager) Import(ctx context.Context, bblState storage.State, imports []terraform.Import) (storage.State, error) {
+	t.ImportCall.CallCount++
+	t.ImportCall.Receives.Context = ctx
+	t.ImportCall.Receives.BBLState = bblState
+	t.ImportCall.Receives.Imports = imports
+
+	return t.ImportCall.Returns.BBLState, t.ImportCall.Returns.Error
+}
@@ -0,0 +1,47 @@
+package fakes
+
+type TXTRecordManager struct {
+	SetTXTRecordCall struct {
+		CallCount int
+		Receives  []TXTRecordCallReceives
+		Returns   struct {
+			Error error
+		}
+	}
+
+	DeleteTXTRecordCall struct {
+		CallCount int
+		Receives  []TXTRecordCallReceives
+		Returns   struct {
+			Error error
+		}
+	}
+}
+
+type TXTRecordCallReceives struct {
+	Zone   string
+	FQDN   string
+	Values []string
+}
+
+func (m *TXTRecordManager) SetTXTRecord(zone, fqdn string, values []string) error {
+	m.SetTXTRecordCall.CallCount++
+	m.SetTXTRecordCall.Receives = append(m.SetTXTRecordCall.Receives, TXTRecordCallReceives{
+		Zone:   zone,
+		FQDN:   fqdn,
+		Values: values,
+	})
+
+	return m.SetTXTRecordCall.Returns.Error
+}
+
+func (m *TXTRecordManager) DeleteTXTRecord(zone, fqdn string, values []string) error {
+	m.DeleteTXTRecordCall.CallCount++
+	m.DeleteTXTRecordCall.Receives = append(m.DeleteTXTRecordCall.Receives, TXTRecordCallReceives{
+		Zone:   zone,
+		FQDN:   fqdn,
+		Values: values,
+	})
+
+	return m.DeleteTXTRecordCall.Returns.Error
+}
@@ -3,6 +3,7 @@ package flags
 import (
 	"flag"
 	"io/ioutil"
+	"time"
 )
 
 type Flags struct {
@@ -30,6 +31,10 @@ func (f Flags) String(v *string, name string, value string) {
 	f.set.StringVar(v, name, value, "")
 }
 
+func (f Flags) Duration(v *time.Duration, name string, value time.Duration) {
+	f.set.DurationVar(v, name, value, "")
+}
+
 func (f Flags) Parse(args []string) error {
 	return f.set.Parse(args)
 }
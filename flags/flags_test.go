@@ -1,6 +1,8 @@
 package flags_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry/bosh-bootloader/flags"
 
 	. "github.com/onsi/ginkgo"
@@ -9,15 +11,17 @@ import (
 
 var _ = Describe("Flags", func() {
 	var (
-		f         flags.Flags
-		boolVal   bool
-		stringVal string
+		f           flags.Flags
+		boolVal     bool
+		stringVal   string
+		durationVal time.Duration
 	)
 
 	BeforeEach(func() {
 		f = flags.New("test")
 		f.Bool(&boolVal, "b", "bool", false)
 		f.String(&stringVal, "string", "")
+		f.Duration(&durationVal, "duration", 0)
 	})
 
 	Describe("Parse", func() {
@@ -49,6 +53,19 @@ var _ = Describe("Flags", func() {
 				Expect(stringVal).To(Equal("string_value"))
 			})
 		})
+
+		Context("Duration flags", func() {
+			It("can parse durations from flags", func() {
+				err := f.Parse([]string{"--duration", "1h30m"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(durationVal).To(Equal(90 * time.Minute))
+			})
+
+			It("returns an error when the duration is invalid", func() {
+				err := f.Parse([]string{"--duration", "forever"})
+				Expect(err).To(MatchError(ContainSubstring(`invalid value "forever" for flag -duration`)))
+			})
+		})
 	})
 
 	Describe("Args", func() {
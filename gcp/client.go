@@ -3,21 +3,35 @@ package gcp
 import (
 	"fmt"
 
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
+	dns "google.golang.org/api/dns/v1"
+	iam "google.golang.org/api/iam/v1"
 )
 
 type Client interface {
 	ProjectID() string
 	GetProject() (*compute.Project, error)
+	GetRegion(region string) (*compute.Region, error)
 	SetCommonInstanceMetadata(metadata *compute.Metadata) (*compute.Operation, error)
 	ListInstances() (*compute.InstanceList, error)
 	GetNetworks(name string) (*compute.NetworkList, error)
+	ListResourceRecordSets(managedZone, name, recordType string) (*dns.ResourceRecordSetsListResponse, error)
+	CreateDNSChange(managedZone string, change *dns.Change) (*dns.Change, error)
+	GetDNSChange(managedZone, changeID string) (*dns.Change, error)
+	CreateServiceAccountKey(email string) (*iam.ServiceAccountKey, error)
+	DeleteServiceAccountKey(email, keyID string) error
+	TestProjectPermissions(permissions []string) ([]string, error)
 }
 
 type GCPClient struct {
-	service   *compute.Service
-	projectID string
-	zone      string
+	service    *compute.Service
+	dnsService *dns.Service
+	iamService *iam.Service
+	projectID  string
+	zone       string
+
+	resourceManagerService *cloudresourcemanager.Service
 }
 
 func (c GCPClient) ProjectID() string {
@@ -28,6 +42,10 @@ func (c GCPClient) GetProject() (*compute.Project, error) {
 	return c.service.Projects.Get(c.projectID).Do()
 }
 
+func (c GCPClient) GetRegion(region string) (*compute.Region, error) {
+	return c.service.Regions.Get(c.projectID, region).Do()
+}
+
 func (c GCPClient) SetCommonInstanceMetadata(metadata *compute.Metadata) (*compute.Operation, error) {
 	return c.service.Projects.SetCommonInstanceMetadata(c.projectID, metadata).Do()
 }
@@ -40,3 +58,39 @@ func (c GCPClient) GetNetworks(name string) (*compute.NetworkList, error) {
 	networksListCall := c.service.Networks.List(c.projectID)
 	return networksListCall.Filter(fmt.Sprintf("name eq %s", name)).Do()
 }
+
+func (c GCPClient) ListResourceRecordSets(managedZone, name, recordType string) (*dns.ResourceRecordSetsListResponse, error) {
+	return c.dnsService.ResourceRecordSets.List(c.projectID, managedZone).Name(name).Type(recordType).Do()
+}
+
+func (c GCPClient) CreateDNSChange(managedZone string, change *dns.Change) (*dns.Change, error) {
+	return c.dnsService.Changes.Create(c.projectID, managedZone, change).Do()
+}
+
+func (c GCPClient) GetDNSChange(managedZone, changeID string) (*dns.Change, error) {
+	return c.dnsService.Changes.Get(c.projectID, managedZone, changeID).Do()
+}
+
+func (c GCPClient) CreateServiceAccountKey(email string) (*iam.ServiceAccountKey, error) {
+	name := fmt.Sprintf("projects/%s/serviceAccounts/%s", c.projectID, email)
+	return c.iamService.Projects.ServiceAccounts.Keys.Create(name, &iam.CreateServiceAccountKeyRequest{}).Do()
+}
+
+func (c GCPClient) DeleteServiceAccountKey(email, keyID string) error {
+	name := fmt.Sprintf("projects/%s/serviceAccounts/%s/keys/%s", c.projectID, email, keyID)
+	_, err := c.iamService.Projects.ServiceAccounts.Keys.Delete(name).Do()
+	return err
+}
+
+// TestProjectPermissions returns the permissions that the caller has on the
+// project, out of the ones given.
+func (c GCPClient) TestProjectPermissions(permissions []string) ([]string, error) {
+	response, err := c.resourceManagerService.Projects.TestIamPermissions(c.projectID, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: permissions,
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Permissions, nil
+}
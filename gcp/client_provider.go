@@ -7,11 +7,18 @@ import (
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/jwt"
 
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
+	dns "google.golang.org/api/dns/v1"
+	iam "google.golang.org/api/iam/v1"
 )
 
 const (
 	GoogleComputeAuth = "https://www.googleapis.com/auth/compute"
+	GoogleDNSAuth     = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+	GoogleIAMAuth     = "https://www.googleapis.com/auth/iam"
+
+	GoogleCloudPlatformReadOnlyAuth = "https://www.googleapis.com/auth/cloud-platform.read-only"
 )
 
 func gcpHTTPClientFunc(config *jwt.Config) *http.Client {
@@ -32,29 +39,53 @@ func NewClientProvider(gcpBasePath string) *ClientProvider {
 }
 
 func (p *ClientProvider) SetConfig(serviceAccountKey, projectID, zone string) error {
-	authURL := GoogleComputeAuth
+	authURLs := []string{GoogleComputeAuth, GoogleDNSAuth, GoogleIAMAuth, GoogleCloudPlatformReadOnlyAuth}
 	if p.basePath != "" {
-		authURL = p.basePath
+		authURLs = []string{p.basePath}
+	}
+
+	config, err := google.JWTConfigFromJSON([]byte(serviceAccountKey), authURLs...)
+	if err != nil {
+		return err
 	}
 
-	config, err := google.JWTConfigFromJSON([]byte(serviceAccountKey), authURL)
+	httpClient := gcpHTTPClient(config)
+
+	service, err := compute.New(httpClient)
 	if err != nil {
 		return err
 	}
 
-	service, err := compute.New(gcpHTTPClient(config))
+	dnsService, err := dns.New(httpClient)
+	if err != nil {
+		return err
+	}
+
+	iamService, err := iam.New(httpClient)
+	if err != nil {
+		return err
+	}
+
+	resourceManagerService, err := cloudresourcemanager.New(httpClient)
 	if err != nil {
 		return err
 	}
 
 	if p.basePath != "" {
 		service.BasePath = p.basePath
+		dnsService.BasePath = p.basePath
+		iamService.BasePath = p.basePath
+		resourceManagerService.BasePath = p.basePath
 	}
 
 	p.client = GCPClient{
-		service:   service,
-		projectID: projectID,
-		zone:      zone,
+		service:    service,
+		dnsService: dnsService,
+		iamService: iamService,
+		projectID:  projectID,
+		zone:       zone,
+
+		resourceManagerService: resourceManagerService,
 	}
 
 	return nil
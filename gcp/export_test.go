@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"net/http"
+	"time"
 
 	"golang.org/x/oauth2/jwt"
 )
@@ -13,3 +14,11 @@ func SetGCPHTTPClient(f func(*jwt.Config) *http.Client) {
 func ResetGCPHTTPClient() {
 	gcpHTTPClient = gcpHTTPClientFunc
 }
+
+func SetSleep(f func(time.Duration)) {
+	sleep = f
+}
+
+func ResetSleep() {
+	sleep = time.Sleep
+}
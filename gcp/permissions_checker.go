@@ -0,0 +1,69 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+// directorServiceAccountPermissions let terraform grant the director service
+// account its roles. roles/editor does not include them.
+var directorServiceAccountPermissions = []string{
+	"resourcemanager.projects.setIamPolicy",
+	"iam.serviceAccounts.setIamPolicy",
+}
+
+type PermissionsChecker struct {
+	clientProvider configurableClientProvider
+}
+
+type configurableClientProvider interface {
+	SetConfig(serviceAccountKey, projectID, zone string) error
+	Client() Client
+}
+
+func NewPermissionsChecker(clientProvider configurableClientProvider) PermissionsChecker {
+	return PermissionsChecker{
+		clientProvider: clientProvider,
+	}
+}
+
+// Check returns an error when the service account of bbl cannot grant the
+// director service account its roles. When the permissions cannot be tested,
+// terraform reports the problem itself.
+func (p PermissionsChecker) Check(state storage.State) error {
+	err := p.clientProvider.SetConfig(state.GCP.ServiceAccountKey, state.GCP.ProjectID, state.GCP.Zone)
+	if err != nil {
+		return nil
+	}
+
+	granted, err := p.clientProvider.Client().TestProjectPermissions(directorServiceAccountPermissions)
+	if err != nil {
+		return nil
+	}
+
+	var missing []string
+	for _, permission := range directorServiceAccountPermissions {
+		if !contains(granted, permission) {
+			missing = append(missing, permission)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the service account key is missing the permissions %s, which bbl needs to create the director service account: grant it the roles 'roles/resourcemanager.projectIamAdmin' and 'roles/iam.serviceAccountAdmin'",
+		strings.Join(missing, ", "))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
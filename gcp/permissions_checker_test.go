@@ -0,0 +1,77 @@
+package gcp_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PermissionsChecker", func() {
+	var (
+		client             *fakes.GCPClient
+		gcpClientProvider  *fakes.GCPClientProvider
+		permissionsChecker gcp.PermissionsChecker
+		state              storage.State
+	)
+
+	BeforeEach(func() {
+		client = &fakes.GCPClient{}
+		client.TestProjectPermissionsCall.Returns.Permissions = []string{
+			"resourcemanager.projects.setIamPolicy",
+			"iam.serviceAccounts.setIamPolicy",
+		}
+		gcpClientProvider = &fakes.GCPClientProvider{}
+		gcpClientProvider.ClientCall.Returns.Client = client
+
+		state = storage.State{
+			GCP: storage.GCP{
+				ServiceAccountKey: "some-service-account-key",
+				ProjectID:         "some-project-id",
+				Zone:              "some-zone",
+			},
+		}
+
+		permissionsChecker = gcp.NewPermissionsChecker(gcpClientProvider)
+	})
+
+	It("tests the permissions with the credentials in the state", func() {
+		err := permissionsChecker.Check(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(gcpClientProvider.SetConfigCall.Receives.ServiceAccountKey).To(Equal("some-service-account-key"))
+		Expect(gcpClientProvider.SetConfigCall.Receives.ProjectID).To(Equal("some-project-id"))
+		Expect(gcpClientProvider.SetConfigCall.Receives.Zone).To(Equal("some-zone"))
+		Expect(client.TestProjectPermissionsCall.Receives.Permissions).To(ConsistOf(
+			"resourcemanager.projects.setIamPolicy",
+			"iam.serviceAccounts.setIamPolicy",
+		))
+	})
+
+	It("returns an error naming the permissions that an editor does not have", func() {
+		client.TestProjectPermissionsCall.Returns.Permissions = []string{}
+
+		err := permissionsChecker.Check(state)
+		Expect(err).To(MatchError(ContainSubstring("missing the permissions resourcemanager.projects.setIamPolicy, iam.serviceAccounts.setIamPolicy")))
+		Expect(err).To(MatchError(ContainSubstring("roles/iam.serviceAccountAdmin")))
+	})
+
+	It("leaves invalid credentials for up to report", func() {
+		gcpClientProvider.SetConfigCall.Returns.Error = errors.New("invalid key")
+
+		err := permissionsChecker.Check(state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.TestProjectPermissionsCall.CallCount).To(Equal(0))
+	})
+
+	It("leaves permissions that cannot be tested for terraform to report", func() {
+		client.TestProjectPermissionsCall.Returns.Error = errors.New("api not enabled")
+
+		err := permissionsChecker.Check(state)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,48 @@
+package gcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+type ServiceAccountKeyManager struct {
+	clientProvider clientProvider
+}
+
+type serviceAccountKey struct {
+	PrivateKeyID string `json:"private_key_id"`
+	ClientEmail  string `json:"client_email"`
+}
+
+func NewServiceAccountKeyManager(clientProvider clientProvider) ServiceAccountKeyManager {
+	return ServiceAccountKeyManager{
+		clientProvider: clientProvider,
+	}
+}
+
+// Create returns the json key file of a new key for the service account.
+func (m ServiceAccountKeyManager) Create(email string) (string, error) {
+	key, err := m.clientProvider.Client().CreateServiceAccountKey(email)
+	if err != nil {
+		return "", err
+	}
+
+	keyFile, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode the service account key: %s", err)
+	}
+
+	return string(keyFile), nil
+}
+
+// Delete deletes the key that the json key file belongs to.
+func (m ServiceAccountKeyManager) Delete(keyFile string) error {
+	var key serviceAccountKey
+	err := json.Unmarshal([]byte(keyFile), &key)
+	if err != nil {
+		return fmt.Errorf("failed to parse the service account key: %s", err)
+	}
+
+	return m.clientProvider.Client().DeleteServiceAccountKey(key.ClientEmail, key.PrivateKeyID)
+}
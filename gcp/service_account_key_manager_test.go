@@ -0,0 +1,85 @@
+package gcp_test
+
+import (
+	"encoding/base64"
+	"errors"
+
+	iam "google.golang.org/api/iam/v1"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ServiceAccountKeyManager", func() {
+	var (
+		manager           gcp.ServiceAccountKeyManager
+		gcpClientProvider *fakes.GCPClientProvider
+		gcpClient         *fakes.GCPClient
+	)
+
+	BeforeEach(func() {
+		gcpClient = &fakes.GCPClient{}
+		gcpClientProvider = &fakes.GCPClientProvider{}
+		gcpClientProvider.ClientCall.Returns.Client = gcpClient
+
+		manager = gcp.NewServiceAccountKeyManager(gcpClientProvider)
+	})
+
+	Describe("Create", func() {
+		It("returns the key file of a new key for the service account", func() {
+			gcpClient.CreateServiceAccountKeyCall.Returns.ServiceAccountKey = &iam.ServiceAccountKey{
+				PrivateKeyData: base64.StdEncoding.EncodeToString([]byte(`{"private_key_id": "some-key-id"}`)),
+			}
+
+			keyFile, err := manager.Create("some-account@some-project.iam.gserviceaccount.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.CreateServiceAccountKeyCall.Receives.Email).To(Equal("some-account@some-project.iam.gserviceaccount.com"))
+			Expect(keyFile).To(Equal(`{"private_key_id": "some-key-id"}`))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the key cannot be created", func() {
+				gcpClient.CreateServiceAccountKeyCall.Returns.Error = errors.New("failed to create key")
+
+				_, err := manager.Create("some-account@some-project.iam.gserviceaccount.com")
+				Expect(err).To(MatchError("failed to create key"))
+			})
+
+			It("returns an error when the key data cannot be decoded", func() {
+				gcpClient.CreateServiceAccountKeyCall.Returns.ServiceAccountKey = &iam.ServiceAccountKey{
+					PrivateKeyData: "%%%",
+				}
+
+				_, err := manager.Create("some-account@some-project.iam.gserviceaccount.com")
+				Expect(err).To(MatchError(ContainSubstring("failed to decode the service account key")))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the key the key file belongs to", func() {
+			err := manager.Delete(`{"private_key_id": "some-key-id", "client_email": "some-account@some-project.iam.gserviceaccount.com"}`)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.DeleteServiceAccountKeyCall.Receives.Email).To(Equal("some-account@some-project.iam.gserviceaccount.com"))
+			Expect(gcpClient.DeleteServiceAccountKeyCall.Receives.KeyID).To(Equal("some-key-id"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the key file cannot be parsed", func() {
+				err := manager.Delete("not-json")
+				Expect(err).To(MatchError(ContainSubstring("failed to parse the service account key")))
+			})
+
+			It("returns an error when the key cannot be deleted", func() {
+				gcpClient.DeleteServiceAccountKeyCall.Returns.Error = errors.New("failed to delete key")
+
+				err := manager.Delete(`{"private_key_id": "some-key-id"}`)
+				Expect(err).To(MatchError("failed to delete key"))
+			})
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dns "google.golang.org/api/dns/v1"
+)
+
+const txtRecordTTL = 60
+
+var (
+	sleep              = time.Sleep
+	dnsChangeRetries   = 60
+	dnsChangeRetryWait = 2 * time.Second
+)
+
+type TXTRecordManager struct {
+	clientProvider clientProvider
+}
+
+func NewTXTRecordManager(clientProvider clientProvider) TXTRecordManager {
+	return TXTRecordManager{
+		clientProvider: clientProvider,
+	}
+}
+
+// SetTXTRecord replaces the TXT record in the managed zone and waits until
+// Cloud DNS reports the change as done.
+func (m TXTRecordManager) SetTXTRecord(managedZone, fqdn string, values []string) error {
+	client := m.clientProvider.Client()
+	name := canonicalName(fqdn)
+
+	existing, err := client.ListResourceRecordSets(managedZone, name, "TXT")
+	if err != nil {
+		return err
+	}
+
+	var rrdatas []string
+	for _, value := range values {
+		rrdatas = append(rrdatas, fmt.Sprintf("%q", value))
+	}
+
+	change, err := client.CreateDNSChange(managedZone, &dns.Change{
+		Deletions: existing.Rrsets,
+		Additions: []*dns.ResourceRecordSet{{
+			Name:    name,
+			Type:    "TXT",
+			Ttl:     txtRecordTTL,
+			Rrdatas: rrdatas,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change the TXT record %s: %s", fqdn, err)
+	}
+
+	for i := 0; change.Status != "done"; i++ {
+		if i == dnsChangeRetries {
+			return fmt.Errorf("timed out waiting for the TXT record %s", fqdn)
+		}
+
+		sleep(dnsChangeRetryWait)
+
+		change, err = client.GetDNSChange(managedZone, change.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m TXTRecordManager) DeleteTXTRecord(managedZone, fqdn string, values []string) error {
+	client := m.clientProvider.Client()
+
+	existing, err := client.ListResourceRecordSets(managedZone, canonicalName(fqdn), "TXT")
+	if err != nil {
+		return err
+	}
+
+	if len(existing.Rrsets) == 0 {
+		return nil
+	}
+
+	_, err = client.CreateDNSChange(managedZone, &dns.Change{
+		Deletions: existing.Rrsets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change the TXT record %s: %s", fqdn, err)
+	}
+
+	return nil
+}
+
+func canonicalName(fqdn string) string {
+	if strings.HasSuffix(fqdn, ".") {
+		return fqdn
+	}
+
+	return fqdn + "."
+}
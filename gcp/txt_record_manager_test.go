@@ -0,0 +1,129 @@
+package gcp_test
+
+import (
+	"errors"
+	"time"
+
+	dns "google.golang.org/api/dns/v1"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TXTRecordManager", func() {
+	var (
+		manager           gcp.TXTRecordManager
+		gcpClientProvider *fakes.GCPClientProvider
+		gcpClient         *fakes.GCPClient
+		existingRecord    *dns.ResourceRecordSet
+		sleeps            []time.Duration
+	)
+
+	BeforeEach(func() {
+		gcpClient = &fakes.GCPClient{}
+		gcpClientProvider = &fakes.GCPClientProvider{}
+		gcpClientProvider.ClientCall.Returns.Client = gcpClient
+
+		existingRecord = &dns.ResourceRecordSet{
+			Name:    "_acme-challenge.example.com.",
+			Type:    "TXT",
+			Ttl:     60,
+			Rrdatas: []string{`"old-value"`},
+		}
+		gcpClient.ListResourceRecordSetsCall.Returns.Response = &dns.ResourceRecordSetsListResponse{
+			Rrsets: []*dns.ResourceRecordSet{existingRecord},
+		}
+		gcpClient.CreateDNSChangeCall.Returns.Change = &dns.Change{Id: "some-change-id", Status: "pending"}
+		gcpClient.GetDNSChangeCall.Returns.Change = &dns.Change{Id: "some-change-id", Status: "done"}
+
+		sleeps = []time.Duration{}
+		gcp.SetSleep(func(d time.Duration) {
+			sleeps = append(sleeps, d)
+		})
+
+		manager = gcp.NewTXTRecordManager(gcpClientProvider)
+	})
+
+	AfterEach(func() {
+		gcp.ResetSleep()
+	})
+
+	Describe("SetTXTRecord", func() {
+		It("replaces the existing record and waits for the change to be done", func() {
+			err := manager.SetTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1", "value-2"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.ListResourceRecordSetsCall.Receives.ManagedZone).To(Equal("some-zone"))
+			Expect(gcpClient.ListResourceRecordSetsCall.Receives.Name).To(Equal("_acme-challenge.example.com."))
+			Expect(gcpClient.ListResourceRecordSetsCall.Receives.RecordType).To(Equal("TXT"))
+
+			Expect(gcpClient.CreateDNSChangeCall.Receives.ManagedZone).To(Equal("some-zone"))
+			Expect(gcpClient.CreateDNSChangeCall.Receives.Change).To(Equal(&dns.Change{
+				Deletions: []*dns.ResourceRecordSet{existingRecord},
+				Additions: []*dns.ResourceRecordSet{{
+					Name:    "_acme-challenge.example.com.",
+					Type:    "TXT",
+					Ttl:     60,
+					Rrdatas: []string{`"value-1"`, `"value-2"`},
+				}},
+			}))
+
+			Expect(gcpClient.GetDNSChangeCall.Receives.ChangeID).To(Equal("some-change-id"))
+			Expect(sleeps).To(Equal([]time.Duration{2 * time.Second}))
+		})
+
+		It("does not wait when the change is already done", func() {
+			gcpClient.CreateDNSChangeCall.Returns.Change = &dns.Change{Id: "some-change-id", Status: "done"}
+
+			err := manager.SetTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.GetDNSChangeCall.CallCount).To(Equal(0))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the existing records cannot be listed", func() {
+				gcpClient.ListResourceRecordSetsCall.Returns.Error = errors.New("failed to list")
+
+				err := manager.SetTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+				Expect(err).To(MatchError("failed to list"))
+			})
+
+			It("returns an error when the change fails", func() {
+				gcpClient.CreateDNSChangeCall.Returns.Error = errors.New("forbidden")
+
+				err := manager.SetTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+				Expect(err).To(MatchError("failed to change the TXT record _acme-challenge.example.com: forbidden"))
+			})
+
+			It("returns an error when the change never completes", func() {
+				gcpClient.GetDNSChangeCall.Returns.Change = &dns.Change{Id: "some-change-id", Status: "pending"}
+
+				err := manager.SetTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+				Expect(err).To(MatchError("timed out waiting for the TXT record _acme-challenge.example.com"))
+			})
+		})
+	})
+
+	Describe("DeleteTXTRecord", func() {
+		It("deletes the existing record", func() {
+			err := manager.DeleteTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.CreateDNSChangeCall.Receives.Change).To(Equal(&dns.Change{
+				Deletions: []*dns.ResourceRecordSet{existingRecord},
+			}))
+		})
+
+		It("does nothing when the record does not exist", func() {
+			gcpClient.ListResourceRecordSetsCall.Returns.Response = &dns.ResourceRecordSetsListResponse{}
+
+			err := manager.DeleteTXTRecord("some-zone", "_acme-challenge.example.com", []string{"value-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(gcpClient.CreateDNSChangeCall.CallCount).To(Equal(0))
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package helpers
 
-import "regexp"
+import (
+	"regexp"
+	"time"
+)
 
 func SetMatchString(f func(string, string) (bool, error)) {
 	matchString = f
@@ -9,3 +12,11 @@ func SetMatchString(f func(string, string) (bool, error)) {
 func ResetMatchString() {
 	matchString = regexp.MatchString
 }
+
+func SetInterruptGracePeriod(gracePeriod time.Duration) {
+	interruptGracePeriod = gracePeriod
+}
+
+func ResetInterruptGracePeriod() {
+	interruptGracePeriod = 2 * time.Minute
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+var interruptGracePeriod = 2 * time.Minute
+
+// RunCommand runs the command in its own process group so that an interrupt
+// from the terminal only reaches bbl. When the context is done the command is
+// sent an interrupt and RunCommand waits for it to exit before returning the
+// context's error, giving terraform and bosh the chance to write their state.
+// A command that is still running after the grace period, for example one
+// stuck on an IaaS API that does not respond, is killed with its process group.
+func RunCommand(ctx context.Context, command *exec.Cmd) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	err := command.Start()
+	if err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- command.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-ctx.Done():
+		command.Process.Signal(os.Interrupt)
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(interruptGracePeriod):
+		syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+		<-exited
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,87 @@
+package helpers_test
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunCommand", func() {
+	It("runs the command to completion", func() {
+		stdout := bytes.NewBuffer([]byte{})
+		command := exec.Command("sh", "-c", "echo some-output")
+		command.Stdout = stdout
+
+		err := helpers.RunCommand(context.Background(), command)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stdout.String()).To(Equal("some-output\n"))
+	})
+
+	It("returns the error of a failed command", func() {
+		err := helpers.RunCommand(context.Background(), exec.Command("sh", "-c", "exit 3"))
+		Expect(err).To(MatchError("exit status 3"))
+	})
+
+	It("interrupts the command and waits for it to exit when the context is cancelled", func() {
+		stdout := bytes.NewBuffer([]byte{})
+		command := exec.Command("sh", "-c", "trap 'echo cleaned up; exit 1' INT; echo started; while true; do sleep 0.1; done")
+		command.Stdout = stdout
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			cancel()
+		}()
+
+		err := helpers.RunCommand(ctx, command)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(stdout.String()).To(Equal("started\ncleaned up\n"))
+	})
+
+	Context("when the command does not exit after the interrupt", func() {
+		BeforeEach(func() {
+			helpers.SetInterruptGracePeriod(200 * time.Millisecond)
+		})
+
+		AfterEach(func() {
+			helpers.ResetInterruptGracePeriod()
+		})
+
+		It("kills the process group of the command after the grace period", func() {
+			stdout := bytes.NewBuffer([]byte{})
+			command := exec.Command("sh", "-c", "trap '' INT; echo started; while true; do sleep 0.1; done")
+			command.Stdout = stdout
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(500 * time.Millisecond)
+				cancel()
+			}()
+
+			started := time.Now()
+			err := helpers.RunCommand(ctx, command)
+			Expect(err).To(Equal(context.Canceled))
+			Expect(stdout.String()).To(Equal("started\n"))
+			Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+
+	It("does not start the command when the context is already done", func() {
+		stdout := bytes.NewBuffer([]byte{})
+		command := exec.Command("sh", "-c", "echo some-output")
+		command.Stdout = stdout
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := helpers.RunCommand(ctx, command)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+})
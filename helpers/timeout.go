@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type TimeoutError struct {
+	phase   string
+	timeout time.Duration
+}
+
+func NewTimeoutError(phase string, timeout time.Duration) TimeoutError {
+	return TimeoutError{
+		phase:   phase,
+		timeout: timeout,
+	}
+}
+
+func (t TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", t.phase, t.timeout)
+}
+
+func (t TimeoutError) Phase() string {
+	return t.phase
+}
+
+// WithTimeout behaves like context.WithTimeout, except that a timeout of zero
+// means the returned context never expires.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
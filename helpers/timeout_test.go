@@ -0,0 +1,51 @@
+package helpers_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeout", func() {
+	Describe("TimeoutError", func() {
+		It("names the phase that timed out", func() {
+			err := helpers.NewTimeoutError("create-env", 90*time.Minute)
+
+			Expect(err).To(MatchError("create-env timed out after 1h30m0s"))
+			Expect(err.Phase()).To(Equal("create-env"))
+		})
+	})
+
+	Describe("WithTimeout", func() {
+		It("returns a context that expires after the timeout", func() {
+			ctx, cancel := helpers.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			Eventually(ctx.Done()).Should(BeClosed())
+			Expect(ctx.Err()).To(Equal(context.DeadlineExceeded))
+		})
+
+		It("returns a context without a deadline when the timeout is zero", func() {
+			ctx, cancel := helpers.WithTimeout(context.Background(), 0)
+
+			_, hasDeadline := ctx.Deadline()
+			Expect(hasDeadline).To(BeFalse())
+
+			cancel()
+			Expect(ctx.Err()).To(Equal(context.Canceled))
+		})
+
+		It("is cancelled with its parent", func() {
+			parent, cancelParent := context.WithCancel(context.Background())
+			ctx, cancel := helpers.WithTimeout(parent, time.Hour)
+			defer cancel()
+
+			cancelParent()
+			Expect(ctx.Err()).To(Equal(context.Canceled))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package iampolicy
+
+var awsResourceActions = map[string][]string{
+	"aws_vpc": {
+		"ec2:CreateVpc", "ec2:DeleteVpc", "ec2:DescribeVpcs", "ec2:DescribeVpcAttribute", "ec2:ModifyVpcAttribute",
+		"ec2:DescribeVpcClassicLink", "ec2:DescribeVpcClassicLinkDnsSupport", "ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_subnet": {
+		"ec2:CreateSubnet", "ec2:DeleteSubnet", "ec2:DescribeSubnets", "ec2:ModifySubnetAttribute",
+		"ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_internet_gateway": {
+		"ec2:CreateInternetGateway", "ec2:DeleteInternetGateway", "ec2:DescribeInternetGateways",
+		"ec2:AttachInternetGateway", "ec2:DetachInternetGateway", "ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_route_table": {
+		"ec2:CreateRouteTable", "ec2:DeleteRouteTable", "ec2:DescribeRouteTables",
+		"ec2:CreateRoute", "ec2:DeleteRoute", "ec2:ReplaceRoute", "ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_route_table_association": {
+		"ec2:AssociateRouteTable", "ec2:DisassociateRouteTable", "ec2:ReplaceRouteTableAssociation", "ec2:DescribeRouteTables",
+	},
+	"aws_security_group": {
+		"ec2:CreateSecurityGroup", "ec2:DeleteSecurityGroup", "ec2:DescribeSecurityGroups",
+		"ec2:AuthorizeSecurityGroupIngress", "ec2:AuthorizeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupIngress", "ec2:RevokeSecurityGroupEgress", "ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_security_group_rule": {
+		"ec2:AuthorizeSecurityGroupIngress", "ec2:AuthorizeSecurityGroupEgress",
+		"ec2:RevokeSecurityGroupIngress", "ec2:RevokeSecurityGroupEgress", "ec2:DescribeSecurityGroups",
+	},
+	"aws_instance": {
+		"ec2:RunInstances", "ec2:TerminateInstances", "ec2:DescribeInstances", "ec2:DescribeInstanceAttribute",
+		"ec2:ModifyInstanceAttribute", "ec2:DescribeVolumes", "ec2:DescribeImages", "ec2:CreateTags", "ec2:DeleteTags",
+	},
+	"aws_eip": {
+		"ec2:AllocateAddress", "ec2:ReleaseAddress", "ec2:AssociateAddress", "ec2:DisassociateAddress", "ec2:DescribeAddresses",
+	},
+	"aws_elb": {
+		"elasticloadbalancing:CreateLoadBalancer", "elasticloadbalancing:DeleteLoadBalancer",
+		"elasticloadbalancing:DescribeLoadBalancers", "elasticloadbalancing:DescribeLoadBalancerAttributes",
+		"elasticloadbalancing:ModifyLoadBalancerAttributes", "elasticloadbalancing:ConfigureHealthCheck",
+		"elasticloadbalancing:CreateLoadBalancerListeners", "elasticloadbalancing:DeleteLoadBalancerListeners",
+		"elasticloadbalancing:SetLoadBalancerListenerSSLCertificate", "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+		"elasticloadbalancing:AttachLoadBalancerToSubnets", "elasticloadbalancing:DetachLoadBalancerFromSubnets",
+		"elasticloadbalancing:AddTags", "elasticloadbalancing:RemoveTags", "elasticloadbalancing:DescribeTags",
+	},
+	"aws_iam_server_certificate": {
+		"iam:UploadServerCertificate", "iam:GetServerCertificate", "iam:DeleteServerCertificate",
+	},
+	"aws_iam_user": {
+		"iam:CreateUser", "iam:GetUser", "iam:DeleteUser", "iam:ListGroupsForUser",
+	},
+	"aws_iam_access_key": {
+		"iam:CreateAccessKey", "iam:DeleteAccessKey", "iam:ListAccessKeys",
+	},
+	"aws_iam_user_policy": {
+		"iam:PutUserPolicy", "iam:GetUserPolicy", "iam:DeleteUserPolicy",
+	},
+	"aws_iam_role": {
+		"iam:CreateRole", "iam:GetRole", "iam:DeleteRole", "iam:ListInstanceProfilesForRole",
+	},
+	"aws_iam_role_policy": {
+		"iam:PutRolePolicy", "iam:GetRolePolicy", "iam:DeleteRolePolicy",
+	},
+	"aws_iam_instance_profile": {
+		"iam:CreateInstanceProfile", "iam:GetInstanceProfile", "iam:DeleteInstanceProfile",
+		"iam:AddRoleToInstanceProfile", "iam:RemoveRoleFromInstanceProfile", "iam:PassRole",
+	},
+	"aws_route53_zone": {
+		"route53:CreateHostedZone", "route53:GetHostedZone", "route53:DeleteHostedZone", "route53:GetChange",
+		"route53:ListResourceRecordSets", "route53:ChangeResourceRecordSets",
+		"route53:ChangeTagsForResource", "route53:ListTagsForResource",
+	},
+	"aws_route53_record": {
+		"route53:ChangeResourceRecordSets", "route53:ListResourceRecordSets", "route53:GetChange", "route53:GetHostedZone",
+	},
+}
+
+// awsSDKActions are the calls bbl makes itself, outside of terraform. Legacy
+// CloudFormation stacks create the same kinds of resources as the terraform
+// templates, so they only add the cloudformation actions.
+var awsSDKActions = []string{
+	"ec2:CreateKeyPair", "ec2:ImportKeyPair", "ec2:DescribeKeyPairs", "ec2:DeleteKeyPair",
+	"ec2:DescribeAvailabilityZones", "ec2:DescribeInstances", "ec2:DescribeAccountAttributes", "ec2:DescribeAddresses",
+	"ec2:DescribeVpcs", "ec2:DescribeSecurityGroups",
+	"elasticloadbalancing:DescribeAccountLimits", "elasticloadbalancing:DescribeLoadBalancers",
+	"iam:UploadServerCertificate", "iam:GetServerCertificate", "iam:DeleteServerCertificate",
+	"iam:CreateAccessKey", "iam:DeleteAccessKey", "iam:GetAccessKeyLastUsed",
+	"cloudformation:CreateStack", "cloudformation:UpdateStack", "cloudformation:DeleteStack",
+	"cloudformation:DescribeStacks", "cloudformation:DescribeStackResource",
+}
@@ -0,0 +1,84 @@
+package iampolicy
+
+var gcpResourcePermissions = map[string][]string{
+	"google_compute_network": {
+		"compute.networks.create", "compute.networks.delete", "compute.networks.get",
+	},
+	"google_compute_subnetwork": {
+		"compute.subnetworks.create", "compute.subnetworks.delete", "compute.subnetworks.get", "compute.subnetworks.use",
+		"compute.networks.updatePolicy",
+	},
+	"google_compute_firewall": {
+		"compute.firewalls.create", "compute.firewalls.delete", "compute.firewalls.get", "compute.firewalls.update",
+		"compute.networks.updatePolicy",
+	},
+	"google_compute_address": {
+		"compute.addresses.create", "compute.addresses.delete", "compute.addresses.get", "compute.addresses.use",
+	},
+	"google_compute_global_address": {
+		"compute.globalAddresses.create", "compute.globalAddresses.delete", "compute.globalAddresses.get", "compute.globalAddresses.use",
+	},
+	"google_compute_forwarding_rule": {
+		"compute.forwardingRules.create", "compute.forwardingRules.delete", "compute.forwardingRules.get",
+		"compute.addresses.use", "compute.targetPools.use",
+	},
+	"google_compute_global_forwarding_rule": {
+		"compute.globalForwardingRules.create", "compute.globalForwardingRules.delete", "compute.globalForwardingRules.get",
+		"compute.globalAddresses.use", "compute.targetHttpProxies.use", "compute.targetHttpsProxies.use",
+	},
+	"google_compute_target_pool": {
+		"compute.targetPools.create", "compute.targetPools.delete", "compute.targetPools.get", "compute.targetPools.update",
+		"compute.httpHealthChecks.useReadOnly",
+	},
+	"google_compute_http_health_check": {
+		"compute.httpHealthChecks.create", "compute.httpHealthChecks.delete", "compute.httpHealthChecks.get", "compute.httpHealthChecks.update",
+	},
+	"google_compute_instance_group": {
+		"compute.instanceGroups.create", "compute.instanceGroups.delete", "compute.instanceGroups.get", "compute.instanceGroups.update",
+	},
+	"google_compute_backend_service": {
+		"compute.backendServices.create", "compute.backendServices.delete", "compute.backendServices.get", "compute.backendServices.update",
+		"compute.instanceGroups.use", "compute.httpHealthChecks.useReadOnly",
+	},
+	"google_compute_url_map": {
+		"compute.urlMaps.create", "compute.urlMaps.delete", "compute.urlMaps.get", "compute.urlMaps.update",
+		"compute.backendServices.use",
+	},
+	"google_compute_target_http_proxy": {
+		"compute.targetHttpProxies.create", "compute.targetHttpProxies.delete", "compute.targetHttpProxies.get",
+		"compute.urlMaps.use",
+	},
+	"google_compute_target_https_proxy": {
+		"compute.targetHttpsProxies.create", "compute.targetHttpsProxies.delete", "compute.targetHttpsProxies.get",
+		"compute.urlMaps.use", "compute.sslCertificates.get",
+	},
+	"google_compute_ssl_certificate": {
+		"compute.sslCertificates.create", "compute.sslCertificates.delete", "compute.sslCertificates.get",
+	},
+	"google_service_account": {
+		"iam.serviceAccounts.create", "iam.serviceAccounts.delete", "iam.serviceAccounts.get", "iam.serviceAccounts.update",
+	},
+	"google_service_account_key": {
+		"iam.serviceAccountKeys.create", "iam.serviceAccountKeys.delete", "iam.serviceAccountKeys.get",
+	},
+	"google_project_iam_member": {
+		"resourcemanager.projects.getIamPolicy", "resourcemanager.projects.setIamPolicy",
+	},
+	"google_service_account_iam_member": {
+		"iam.serviceAccounts.getIamPolicy", "iam.serviceAccounts.setIamPolicy",
+	},
+	"google_dns_managed_zone": {
+		"dns.managedZones.create", "dns.managedZones.delete", "dns.managedZones.get", "dns.managedZones.list",
+	},
+	"google_dns_record_set": {
+		"dns.changes.create", "dns.changes.get", "dns.resourceRecordSets.create", "dns.resourceRecordSets.delete",
+		"dns.resourceRecordSets.list", "dns.resourceRecordSets.update",
+	},
+}
+
+// gcpSDKPermissions are the calls bbl makes itself, outside of terraform.
+var gcpSDKPermissions = []string{
+	"compute.projects.get", "compute.projects.setCommonInstanceMetadata", "compute.regions.get",
+	"compute.instances.list", "compute.networks.list", "compute.zoneOperations.get", "compute.globalOperations.get",
+	"compute.regionOperations.get", "iam.serviceAccountKeys.create", "iam.serviceAccountKeys.delete",
+}
@@ -0,0 +1,132 @@
+package iampolicy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+// The region only changes how many zonal instance groups the gcp template
+// declares, not which kinds of resources it creates.
+const defaultGCPRegion = "us-central1"
+
+type Generator struct {
+	templateGenerator templateGenerator
+}
+
+type templateGenerator interface {
+	Generate(storage.State) string
+}
+
+type AWSPolicy struct {
+	Version   string         `json:"Version" yaml:"Version"`
+	Statement []AWSStatement `json:"Statement" yaml:"Statement"`
+}
+
+type AWSStatement struct {
+	Effect   string   `json:"Effect" yaml:"Effect"`
+	Action   []string `json:"Action" yaml:"Action"`
+	Resource string   `json:"Resource" yaml:"Resource"`
+}
+
+type GCPRole struct {
+	Title               string   `json:"title" yaml:"title"`
+	Description         string   `json:"description" yaml:"description"`
+	Stage               string   `json:"stage" yaml:"stage"`
+	IncludedPermissions []string `json:"includedPermissions" yaml:"includedPermissions"`
+}
+
+func NewGenerator(templateGenerator templateGenerator) Generator {
+	return Generator{
+		templateGenerator: templateGenerator,
+	}
+}
+
+func (g Generator) AWS(lbType, domain string) (AWSPolicy, error) {
+	state := storage.State{
+		IAAS: "aws",
+		LB: storage.LB{
+			Type:   lbType,
+			Domain: domain,
+		},
+	}
+	template := g.templateGenerator.Generate(state)
+
+	// bbl up --aws-iam-instance-profile can switch any environment to an
+	// instance profile, so the policy covers its resources as well.
+	state.AWS.IAMInstanceProfile = true
+	template += g.templateGenerator.Generate(state)
+
+	actions, err := collect(template, awsResourceActions, awsSDKActions)
+	if err != nil {
+		return AWSPolicy{}, err
+	}
+
+	return AWSPolicy{
+		Version: "2012-10-17",
+		Statement: []AWSStatement{{
+			Effect:   "Allow",
+			Action:   actions,
+			Resource: "*",
+		}},
+	}, nil
+}
+
+func (g Generator) GCP(lbType, domain, region string) (GCPRole, error) {
+	if region == "" {
+		region = defaultGCPRegion
+	}
+
+	template := g.templateGenerator.Generate(storage.State{
+		IAAS: "gcp",
+		GCP: storage.GCP{
+			Region: region,
+		},
+		LB: storage.LB{
+			Type:   lbType,
+			Domain: domain,
+		},
+	})
+
+	permissions, err := collect(template, gcpResourcePermissions, gcpSDKPermissions)
+	if err != nil {
+		return GCPRole{}, err
+	}
+
+	return GCPRole{
+		Title:               "bbl",
+		Description:         "Permissions needed by bbl to manage a bosh director and its load balancers",
+		Stage:               "GA",
+		IncludedPermissions: permissions,
+	}, nil
+}
+
+func collect(template string, resourcePermissions map[string][]string, extras ...[]string) ([]string, error) {
+	unique := map[string]bool{}
+	for _, extra := range extras {
+		for _, permission := range extra {
+			unique[permission] = true
+		}
+	}
+
+	for resourceType := range terraform.Resources(template) {
+		permissions, ok := resourcePermissions[resourceType]
+		if !ok {
+			return nil, fmt.Errorf("no permissions are known for terraform resource %q", resourceType)
+		}
+
+		for _, permission := range permissions {
+			unique[permission] = true
+		}
+	}
+
+	var sorted []string
+	for permission := range unique {
+		sorted = append(sorted, permission)
+	}
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
@@ -0,0 +1,119 @@
+package iampolicy_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	"github.com/cloudfoundry/bosh-bootloader/iampolicy"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	awsterraform "github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+	gcpterraform "github.com/cloudfoundry/bosh-bootloader/terraform/gcp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generator", func() {
+	var generator iampolicy.Generator
+
+	BeforeEach(func() {
+		templateGenerator := terraform.NewTemplateGenerator(gcpterraform.NewTemplateGenerator(gcp.NewZones()), awsterraform.NewTemplateGenerator())
+		generator = iampolicy.NewGenerator(templateGenerator)
+	})
+
+	DescribeTable("knows the permissions for every resource in the templates",
+		func(lbType, domain string) {
+			_, err := generator.AWS(lbType, domain)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = generator.GCP(lbType, domain, "")
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("no lb", "", ""),
+		Entry("concourse lb", "concourse", ""),
+		Entry("cf lb", "cf", ""),
+		Entry("cf lb with a domain", "cf", "some-domain"),
+	)
+
+	Describe("AWS", func() {
+		It("returns a policy document with the actions the template and bbl need", func() {
+			policy, err := generator.AWS("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policy.Version).To(Equal("2012-10-17"))
+			Expect(policy.Statement).To(HaveLen(1))
+			Expect(policy.Statement[0].Effect).To(Equal("Allow"))
+			Expect(policy.Statement[0].Resource).To(Equal("*"))
+			Expect(policy.Statement[0].Action).To(ContainElement("ec2:CreateVpc"))
+			Expect(policy.Statement[0].Action).To(ContainElement("ec2:ImportKeyPair"))
+			Expect(policy.Statement[0].Action).To(ContainElement("cloudformation:CreateStack"))
+			Expect(policy.Statement[0].Action).NotTo(ContainElement("elasticloadbalancing:CreateLoadBalancer"))
+		})
+
+		It("includes the actions for an IAM instance profile for the director", func() {
+			policy, err := generator.AWS("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policy.Statement[0].Action).To(ContainElement("iam:CreateAccessKey"))
+			Expect(policy.Statement[0].Action).To(ContainElement("iam:CreateInstanceProfile"))
+			Expect(policy.Statement[0].Action).To(ContainElement("iam:PassRole"))
+		})
+
+		It("adds the load balancer and dns actions for a cf lb with a domain", func() {
+			policy, err := generator.AWS("cf", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(policy.Statement[0].Action).To(ContainElement("elasticloadbalancing:CreateLoadBalancer"))
+			Expect(policy.Statement[0].Action).To(ContainElement("route53:CreateHostedZone"))
+		})
+
+		It("lists every action once, sorted", func() {
+			policy, err := generator.AWS("cf", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+
+			actions := policy.Statement[0].Action
+			for i := 1; i < len(actions); i++ {
+				Expect(actions[i-1] < actions[i]).To(BeTrue(), actions[i])
+			}
+		})
+	})
+
+	Describe("GCP", func() {
+		It("returns a custom role with the permissions the template and bbl need", func() {
+			role, err := generator.GCP("", "", "us-east1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(role.Title).To(Equal("bbl"))
+			Expect(role.Stage).To(Equal("GA"))
+			Expect(role.IncludedPermissions).To(ContainElement("compute.networks.create"))
+			Expect(role.IncludedPermissions).To(ContainElement("compute.projects.setCommonInstanceMetadata"))
+			Expect(role.IncludedPermissions).To(ContainElement("iam.serviceAccountKeys.create"))
+			Expect(role.IncludedPermissions).To(ContainElement("resourcemanager.projects.setIamPolicy"))
+			Expect(role.IncludedPermissions).To(ContainElement("iam.serviceAccounts.setIamPolicy"))
+			Expect(role.IncludedPermissions).NotTo(ContainElement("compute.disks.create"))
+			Expect(role.IncludedPermissions).NotTo(ContainElement("compute.backendServices.create"))
+		})
+
+		It("adds the load balancer and dns permissions for a cf lb with a domain", func() {
+			role, err := generator.GCP("cf", "some-domain", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(role.IncludedPermissions).To(ContainElement("compute.backendServices.create"))
+			Expect(role.IncludedPermissions).To(ContainElement("compute.instanceGroups.create"))
+			Expect(role.IncludedPermissions).To(ContainElement("dns.managedZones.create"))
+		})
+	})
+
+	It("returns an error when the template has a resource without known permissions", func() {
+		templateGenerator := &fakes.TemplateGenerator{}
+		templateGenerator.GenerateCall.Returns.Template = `resource "aws_nat_gateway" "nat" {}`
+		generator = iampolicy.NewGenerator(templateGenerator)
+
+		_, err := generator.AWS("", "")
+		Expect(err).To(MatchError(`no permissions are known for terraform resource "aws_nat_gateway"`))
+
+		Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(storage.State{IAAS: "aws"}))
+	})
+})
@@ -0,0 +1,13 @@
+package iampolicy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestIAMPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "iampolicy")
+}
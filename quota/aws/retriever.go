@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+
+	goaws "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/aws/ec2"
+	"github.com/cloudfoundry/bosh-bootloader/aws/elb"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+const (
+	maxInstancesAttribute     = "max-instances"
+	vpcMaxElasticIPsAttribute = "vpc-max-elastic-ips"
+	classicLoadBalancersLimit = "classic-load-balancers"
+
+	// EC2 does not report these limits, so the defaults are used.
+	vpcsQuota                 = "vpcs-per-region"
+	defaultVPCsLimit          = 5
+	securityGroupsQuota       = "vpc-security-groups-per-region"
+	defaultSecurityGroupLimit = 500
+)
+
+type Retriever struct {
+	clientProvider clientProvider
+}
+
+type clientProvider interface {
+	SetConfig(config aws.Config)
+	GetEC2Client() ec2.Client
+	GetELBClient() elb.Client
+}
+
+func NewRetriever(clientProvider clientProvider) Retriever {
+	return Retriever{
+		clientProvider: clientProvider,
+	}
+}
+
+func (r Retriever) Retrieve(state storage.State) ([]quota.Quota, error) {
+	r.clientProvider.SetConfig(aws.Config{
+		AccessKeyID:     state.AWS.AccessKeyID,
+		SecretAccessKey: state.AWS.SecretAccessKey,
+		Region:          state.AWS.Region,
+	})
+	client := r.clientProvider.GetEC2Client()
+
+	attributes, err := client.DescribeAccountAttributes(&awsec2.DescribeAccountAttributesInput{
+		AttributeNames: []*string{
+			goaws.String(maxInstancesAttribute),
+			goaws.String(vpcMaxElasticIPsAttribute),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limits := map[string]int{}
+	for _, attribute := range attributes.AccountAttributes {
+		for _, value := range attribute.AttributeValues {
+			limit, err := strconv.Atoi(goaws.StringValue(value.AttributeValue))
+			if err != nil {
+				return nil, fmt.Errorf("account attribute %s has a non-numeric value: %s", goaws.StringValue(attribute.AttributeName), err)
+			}
+			limits[goaws.StringValue(attribute.AttributeName)] = limit
+		}
+	}
+
+	addresses, err := client.DescribeAddresses(&awsec2.DescribeAddressesInput{
+		Filters: []*awsec2.Filter{{
+			Name:   goaws.String("domain"),
+			Values: []*string{goaws.String("vpc")},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.DescribeInstances(&awsec2.DescribeInstancesInput{
+		Filters: []*awsec2.Filter{{
+			Name:   goaws.String("instance-state-name"),
+			Values: []*string{goaws.String("pending"), goaws.String("running")},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var instanceCount int
+	for _, reservation := range instances.Reservations {
+		instanceCount += len(reservation.Instances)
+	}
+
+	vpcs, err := client.DescribeVpcs(&awsec2.DescribeVpcsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroups, err := client.DescribeSecurityGroups(&awsec2.DescribeSecurityGroupsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var securityGroupCount int
+	for _, securityGroup := range securityGroups.SecurityGroups {
+		if goaws.StringValue(securityGroup.VpcId) != "" {
+			securityGroupCount++
+		}
+	}
+
+	loadBalancerLimit, loadBalancerCount, err := r.loadBalancers()
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas []quota.Quota
+	if limit, ok := limits[vpcMaxElasticIPsAttribute]; ok {
+		quotas = append(quotas, quota.Quota{
+			Name:      vpcMaxElasticIPsAttribute,
+			Resources: []string{"aws_eip"},
+			Limit:     limit,
+			Usage:     len(addresses.Addresses),
+		})
+	}
+	if limit, ok := limits[maxInstancesAttribute]; ok {
+		quotas = append(quotas, quota.Quota{
+			Name:      maxInstancesAttribute,
+			Resources: []string{"aws_instance", quota.CreateEnvVMResource},
+			Limit:     limit,
+			Usage:     instanceCount,
+		})
+	}
+	if loadBalancerLimit > 0 {
+		quotas = append(quotas, quota.Quota{
+			Name:      classicLoadBalancersLimit,
+			Resources: []string{"aws_elb"},
+			Limit:     loadBalancerLimit,
+			Usage:     loadBalancerCount,
+		})
+	}
+	quotas = append(quotas, quota.Quota{
+		Name:      vpcsQuota,
+		Resources: []string{"aws_vpc"},
+		Limit:     defaultVPCsLimit,
+		Usage:     len(vpcs.Vpcs),
+		Default:   true,
+	}, quota.Quota{
+		Name:      securityGroupsQuota,
+		Resources: []string{"aws_security_group"},
+		Limit:     defaultSecurityGroupLimit,
+		Usage:     securityGroupCount,
+		Default:   true,
+	})
+
+	return quotas, nil
+}
+
+func (r Retriever) loadBalancers() (int, int, error) {
+	client := r.clientProvider.GetELBClient()
+
+	accountLimits, err := client.DescribeAccountLimits(&awselb.DescribeAccountLimitsInput{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var limit int
+	for _, accountLimit := range accountLimits.Limits {
+		if goaws.StringValue(accountLimit.Name) != classicLoadBalancersLimit {
+			continue
+		}
+
+		limit, err = strconv.Atoi(goaws.StringValue(accountLimit.Max))
+		if err != nil {
+			return 0, 0, fmt.Errorf("load balancer limit %s has a non-numeric value: %s", classicLoadBalancersLimit, err)
+		}
+	}
+
+	var count int
+	input := &awselb.DescribeLoadBalancersInput{}
+	for {
+		loadBalancers, err := client.DescribeLoadBalancers(input)
+		if err != nil {
+			return 0, 0, err
+		}
+		count += len(loadBalancers.LoadBalancerDescriptions)
+
+		if goaws.StringValue(loadBalancers.NextMarker) == "" {
+			break
+		}
+		input = &awselb.DescribeLoadBalancersInput{Marker: loadBalancers.NextMarker}
+	}
+
+	return limit, count, nil
+}
@@ -0,0 +1,202 @@
+package aws_test
+
+import (
+	"errors"
+
+	goaws "github.com/aws/aws-sdk-go/aws"
+	awsec2 "github.com/aws/aws-sdk-go/service/ec2"
+	awselb "github.com/aws/aws-sdk-go/service/elb"
+
+	"github.com/cloudfoundry/bosh-bootloader/aws"
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	awsquota "github.com/cloudfoundry/bosh-bootloader/quota/aws"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retriever", func() {
+	var (
+		clientProvider *fakes.AWSClientProvider
+		ec2Client      *fakes.EC2Client
+		elbClient      *fakes.ELBClient
+		state          storage.State
+
+		retriever awsquota.Retriever
+	)
+
+	BeforeEach(func() {
+		ec2Client = &fakes.EC2Client{}
+		ec2Client.DescribeAccountAttributesCall.Returns.Output = &awsec2.DescribeAccountAttributesOutput{
+			AccountAttributes: []*awsec2.AccountAttribute{
+				{
+					AttributeName:   goaws.String("max-instances"),
+					AttributeValues: []*awsec2.AccountAttributeValue{{AttributeValue: goaws.String("20")}},
+				},
+				{
+					AttributeName:   goaws.String("vpc-max-elastic-ips"),
+					AttributeValues: []*awsec2.AccountAttributeValue{{AttributeValue: goaws.String("5")}},
+				},
+			},
+		}
+		ec2Client.DescribeAddressesCall.Returns.Output = &awsec2.DescribeAddressesOutput{
+			Addresses: []*awsec2.Address{{}, {}, {}},
+		}
+		ec2Client.DescribeInstancesCall.Returns.Output = &awsec2.DescribeInstancesOutput{
+			Reservations: []*awsec2.Reservation{
+				{Instances: []*awsec2.Instance{{}, {}}},
+				{Instances: []*awsec2.Instance{{}}},
+			},
+		}
+		ec2Client.DescribeVpcsCall.Returns.Output = &awsec2.DescribeVpcsOutput{
+			Vpcs: []*awsec2.Vpc{{}, {}},
+		}
+		ec2Client.DescribeSecurityGroupsCall.Returns.Output = &awsec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*awsec2.SecurityGroup{
+				{VpcId: goaws.String("some-vpc-id")},
+				{VpcId: goaws.String("some-vpc-id")},
+				{},
+			},
+		}
+
+		elbClient = &fakes.ELBClient{}
+		elbClient.DescribeAccountLimitsCall.Returns.Output = &awselb.DescribeAccountLimitsOutput{
+			Limits: []*awselb.Limit{
+				{Name: goaws.String("classic-listeners"), Max: goaws.String("100")},
+				{Name: goaws.String("classic-load-balancers"), Max: goaws.String("20")},
+			},
+		}
+		elbClient.DescribeLoadBalancersCall.Returns.Output = &awselb.DescribeLoadBalancersOutput{
+			LoadBalancerDescriptions: []*awselb.LoadBalancerDescription{{}},
+		}
+
+		clientProvider = &fakes.AWSClientProvider{}
+		clientProvider.GetEC2ClientCall.Returns.EC2Client = ec2Client
+		clientProvider.GetELBClientCall.Returns.ELBClient = elbClient
+
+		state = storage.State{
+			AWS: storage.AWS{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "some-region",
+			},
+		}
+
+		retriever = awsquota.NewRetriever(clientProvider)
+	})
+
+	Describe("Retrieve", func() {
+		It("compares the account limits with the resources in use", func() {
+			quotas, err := retriever.Retrieve(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(quotas).To(Equal([]quota.Quota{
+				{Name: "vpc-max-elastic-ips", Resources: []string{"aws_eip"}, Limit: 5, Usage: 3},
+				{Name: "max-instances", Resources: []string{"aws_instance", quota.CreateEnvVMResource}, Limit: 20, Usage: 3},
+				{Name: "classic-load-balancers", Resources: []string{"aws_elb"}, Limit: 20, Usage: 1},
+				{Name: "vpcs-per-region", Resources: []string{"aws_vpc"}, Limit: 5, Usage: 2, Default: true},
+				{Name: "vpc-security-groups-per-region", Resources: []string{"aws_security_group"}, Limit: 500, Usage: 2, Default: true},
+			}))
+
+			Expect(clientProvider.SetConfigCall.Receives.Config).To(Equal(aws.Config{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				Region:          "some-region",
+			}))
+			Expect(ec2Client.DescribeAccountAttributesCall.Receives.Input).To(Equal(&awsec2.DescribeAccountAttributesInput{
+				AttributeNames: []*string{goaws.String("max-instances"), goaws.String("vpc-max-elastic-ips")},
+			}))
+			Expect(ec2Client.DescribeAddressesCall.Receives.Input).To(Equal(&awsec2.DescribeAddressesInput{
+				Filters: []*awsec2.Filter{{
+					Name:   goaws.String("domain"),
+					Values: []*string{goaws.String("vpc")},
+				}},
+			}))
+			Expect(ec2Client.DescribeInstancesCall.Receives.Input).To(Equal(&awsec2.DescribeInstancesInput{
+				Filters: []*awsec2.Filter{{
+					Name:   goaws.String("instance-state-name"),
+					Values: []*string{goaws.String("pending"), goaws.String("running")},
+				}},
+			}))
+		})
+
+		It("falls back to the default limits that the account does not report", func() {
+			ec2Client.DescribeAccountAttributesCall.Returns.Output = &awsec2.DescribeAccountAttributesOutput{}
+			elbClient.DescribeAccountLimitsCall.Returns.Output = &awselb.DescribeAccountLimitsOutput{}
+
+			quotas, err := retriever.Retrieve(state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quotas).To(Equal([]quota.Quota{
+				{Name: "vpcs-per-region", Resources: []string{"aws_vpc"}, Limit: 5, Usage: 2, Default: true},
+				{Name: "vpc-security-groups-per-region", Resources: []string{"aws_security_group"}, Limit: 500, Usage: 2, Default: true},
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the account attributes cannot be described", func() {
+				ec2Client.DescribeAccountAttributesCall.Returns.Error = errors.New("UnauthorizedOperation")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("UnauthorizedOperation"))
+			})
+
+			It("returns an error when an account attribute is not a number", func() {
+				ec2Client.DescribeAccountAttributesCall.Returns.Output.AccountAttributes[0].AttributeValues[0].AttributeValue = goaws.String("many")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError(ContainSubstring("account attribute max-instances has a non-numeric value")))
+			})
+
+			It("returns an error when the addresses cannot be described", func() {
+				ec2Client.DescribeAddressesCall.Returns.Error = errors.New("failed to describe addresses")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe addresses"))
+			})
+
+			It("returns an error when the instances cannot be described", func() {
+				ec2Client.DescribeInstancesCall.Returns.Error = errors.New("failed to describe instances")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe instances"))
+			})
+
+			It("returns an error when the vpcs cannot be described", func() {
+				ec2Client.DescribeVpcsCall.Returns.Error = errors.New("failed to describe vpcs")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe vpcs"))
+			})
+
+			It("returns an error when the security groups cannot be described", func() {
+				ec2Client.DescribeSecurityGroupsCall.Returns.Error = errors.New("failed to describe security groups")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe security groups"))
+			})
+
+			It("returns an error when the load balancer limits cannot be described", func() {
+				elbClient.DescribeAccountLimitsCall.Returns.Error = errors.New("failed to describe account limits")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe account limits"))
+			})
+
+			It("returns an error when the load balancer limit is not a number", func() {
+				elbClient.DescribeAccountLimitsCall.Returns.Output.Limits[1].Max = goaws.String("many")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError(ContainSubstring("load balancer limit classic-load-balancers has a non-numeric value")))
+			})
+
+			It("returns an error when the load balancers cannot be described", func() {
+				elbClient.DescribeLoadBalancersCall.Returns.Error = errors.New("failed to describe load balancers")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to describe load balancers"))
+			})
+		})
+	})
+})
@@ -0,0 +1,217 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+)
+
+// CreateEnvVMResource counts the director and jumpbox VMs that bosh
+// create-env creates outside of the terraform template. Each of them has one
+// CPU.
+const CreateEnvVMResource = "create_env_vm"
+
+type Quota struct {
+	Name      string
+	Resources []string
+	Limit     int
+	Usage     int
+
+	// Default is set when the IaaS does not report the limit and Limit is the
+	// IaaS's default. A shortfall is only a warning then, since the limit may
+	// have been raised for the account.
+	Default bool
+}
+
+type Checker struct {
+	logger            logger
+	templateGenerator templateGenerator
+	awsRetriever      retriever
+	gcpRetriever      retriever
+}
+
+type logger interface {
+	Warn(string, ...interface{})
+}
+
+type templateGenerator interface {
+	Generate(storage.State) string
+}
+
+type retriever interface {
+	Retrieve(storage.State) ([]Quota, error)
+}
+
+func NewChecker(logger logger, templateGenerator templateGenerator, awsRetriever retriever, gcpRetriever retriever) Checker {
+	return Checker{
+		logger:            logger,
+		templateGenerator: templateGenerator,
+		awsRetriever:      awsRetriever,
+		gcpRetriever:      gcpRetriever,
+	}
+}
+
+func (c Checker) Check(state storage.State) error {
+	var quotaRetriever retriever
+	switch state.IAAS {
+	case "aws":
+		quotaRetriever = c.awsRetriever
+	case "gcp":
+		quotaRetriever = c.gcpRetriever
+	default:
+		return nil
+	}
+
+	needed, err := countResources(c.templateGenerator.Generate(state), state.TFState)
+	if err != nil {
+		return err
+	}
+
+	if vms := countCreateEnvVMs(state); vms > 0 {
+		needed[CreateEnvVMResource] = vms
+	}
+
+	if len(needed) == 0 {
+		return nil
+	}
+
+	quotas, err := quotaRetriever.Retrieve(state)
+	if err != nil {
+		c.logger.Warn("skipping quota check, %s quotas could not be retrieved: %s", state.IAAS, err)
+		return nil
+	}
+
+	var shortfalls []Shortfall
+	for _, quota := range quotas {
+		count := 0
+		var resources []string
+		for _, resource := range quota.Resources {
+			if needed[resource] > 0 {
+				count += needed[resource]
+				resources = append(resources, resource)
+			}
+		}
+
+		available := quota.Limit - quota.Usage
+		if available < 0 {
+			available = 0
+		}
+
+		if count > available && quota.Default {
+			c.logger.Warn("%s: %d needed for %s, but only %d of the default limit of %d available, bbl will fail unless the limit was raised for this account",
+				quota.Name, count, strings.Join(resources, ", "), available, quota.Limit)
+			continue
+		}
+
+		if count > available {
+			shortfalls = append(shortfalls, Shortfall{
+				Quota:     quota.Name,
+				Resources: resources,
+				Needed:    count,
+				Available: available,
+				Limit:     quota.Limit,
+			})
+		}
+	}
+
+	if len(shortfalls) > 0 {
+		return NewShortfallError(shortfalls)
+	}
+
+	return nil
+}
+
+// countResources returns the number of resources of each type in the
+// template that are not already recorded in the terraform state.
+func countResources(template, tfState string) (map[string]int, error) {
+	needed := terraform.Resources(template)
+
+	if tfState == "" {
+		return needed, nil
+	}
+
+	var state struct {
+		Modules []struct {
+			Resources map[string]json.RawMessage `json:"resources"`
+		} `json:"modules"`
+	}
+	err := json.Unmarshal([]byte(tfState), &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform state: %s", err)
+	}
+
+	for _, module := range state.Modules {
+		for name := range module.Resources {
+			if strings.HasPrefix(name, "data.") {
+				continue
+			}
+
+			resourceType := strings.Split(name, ".")[0]
+			if needed[resourceType] > 0 {
+				needed[resourceType]--
+			}
+		}
+	}
+
+	for resourceType, count := range needed {
+		if count == 0 {
+			delete(needed, resourceType)
+		}
+	}
+
+	return needed, nil
+}
+
+// countCreateEnvVMs returns the number of VMs that create-env has not created
+// yet.
+func countCreateEnvVMs(state storage.State) int {
+	vms := 0
+	if !state.NoDirector && len(state.BOSH.State) == 0 {
+		vms++
+	}
+	if state.Jumpbox.Enabled && len(state.Jumpbox.State) == 0 {
+		vms++
+	}
+
+	return vms
+}
+
+type Shortfall struct {
+	Quota     string
+	Resources []string
+	Needed    int
+	Available int
+	Limit     int
+}
+
+type ShortfallError struct {
+	shortfalls []Shortfall
+}
+
+func NewShortfallError(shortfalls []Shortfall) ShortfallError {
+	return ShortfallError{
+		shortfalls: shortfalls,
+	}
+}
+
+func (s ShortfallError) Error() string {
+	lines := []string{"not enough quota to create the resources of the environment:"}
+	for _, shortfall := range s.shortfalls {
+		resources := append([]string{}, shortfall.Resources...)
+		sort.Strings(resources)
+
+		lines = append(lines, fmt.Sprintf("  %s: %d needed for %s, but only %d of %d available",
+			shortfall.Quota, shortfall.Needed, strings.Join(resources, ", "), shortfall.Available, shortfall.Limit))
+	}
+	lines = append(lines, "Request a quota increase or free up the resources listed above and try again.")
+
+	return strings.Join(lines, "\n")
+}
+
+func (s ShortfallError) Shortfalls() []Shortfall {
+	return s.shortfalls
+}
@@ -0,0 +1,154 @@
+package quota_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		logger            *fakes.Logger
+		templateGenerator *fakes.TemplateGenerator
+		awsRetriever      *fakes.QuotaRetriever
+		gcpRetriever      *fakes.QuotaRetriever
+
+		checker quota.Checker
+	)
+
+	BeforeEach(func() {
+		logger = &fakes.Logger{}
+		templateGenerator = &fakes.TemplateGenerator{}
+		templateGenerator.GenerateCall.Returns.Template = `
+resource "google_compute_address" "bosh-external-ip" {
+  name = "${var.env_id}-bosh-external-ip"
+}
+
+resource "google_compute_address" "cf-ws" {
+  name = "${var.env_id}-cf-ws"
+}
+
+resource "google_compute_forwarding_rule" "cf-ws-https" {
+  name = "${var.env_id}-cf-ws-https"
+}
+
+resource "google_compute_firewall" "internal" {
+  name = "${var.env_id}-internal"
+}
+`
+		awsRetriever = &fakes.QuotaRetriever{}
+		gcpRetriever = &fakes.QuotaRetriever{}
+		gcpRetriever.RetrieveCall.Returns.Quotas = []quota.Quota{
+			{Name: "some-region/STATIC_ADDRESSES", Resources: []string{"google_compute_address"}, Limit: 8, Usage: 2},
+			{Name: "project/FORWARDING_RULES", Resources: []string{"google_compute_forwarding_rule", "google_compute_global_forwarding_rule"}, Limit: 15, Usage: 15},
+			{Name: "project/NETWORKS", Resources: []string{"google_compute_network"}, Limit: 5, Usage: 5},
+		}
+
+		checker = quota.NewChecker(logger, templateGenerator, awsRetriever, gcpRetriever)
+	})
+
+	Describe("Check", func() {
+		It("returns a report of every quota that is too small for the template", func() {
+			state := storage.State{IAAS: "gcp"}
+
+			err := checker.Check(state)
+			Expect(err).To(MatchError("not enough quota to create the resources of the environment:\n" +
+				"  project/FORWARDING_RULES: 1 needed for google_compute_forwarding_rule, but only 0 of 15 available\n" +
+				"Request a quota increase or free up the resources listed above and try again."))
+
+			Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(state))
+			Expect(gcpRetriever.RetrieveCall.Receives.State).To(Equal(state))
+			Expect(awsRetriever.RetrieveCall.CallCount).To(Equal(0))
+
+			shortfallError, ok := err.(quota.ShortfallError)
+			Expect(ok).To(BeTrue())
+			Expect(shortfallError.Shortfalls()).To(Equal([]quota.Shortfall{{
+				Quota:     "project/FORWARDING_RULES",
+				Resources: []string{"google_compute_forwarding_rule"},
+				Needed:    1,
+				Available: 0,
+				Limit:     15,
+			}}))
+		})
+
+		It("does not count resources that already exist in the terraform state", func() {
+			err := checker.Check(storage.State{
+				IAAS: "gcp",
+				TFState: `{"modules": [{"resources": {
+					"google_compute_address.bosh-external-ip": {},
+					"google_compute_forwarding_rule.cf-ws-https": {},
+					"data.google_compute_network.existing": {}
+				}}]}`,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("uses the aws retriever for aws environments", func() {
+			templateGenerator.GenerateCall.Returns.Template = `resource "aws_eip" "bosh_eip" {}`
+			awsRetriever.RetrieveCall.Returns.Quotas = []quota.Quota{
+				{Name: "vpc-max-elastic-ips", Resources: []string{"aws_eip"}, Limit: 5, Usage: 6},
+			}
+
+			err := checker.Check(storage.State{IAAS: "aws"})
+			Expect(err).To(MatchError(ContainSubstring("vpc-max-elastic-ips: 1 needed for aws_eip, but only 0 of 5 available")))
+			Expect(awsRetriever.RetrieveCall.CallCount).To(Equal(1))
+		})
+
+		It("does not retrieve quotas when the template and create-env create nothing new", func() {
+			templateGenerator.GenerateCall.Returns.Template = ""
+
+			err := checker.Check(storage.State{
+				IAAS: "gcp",
+				BOSH: storage.BOSH{State: map[string]interface{}{"some-key": "some-value"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gcpRetriever.RetrieveCall.CallCount).To(Equal(0))
+		})
+
+		It("skips the check when the quotas cannot be retrieved", func() {
+			gcpRetriever.RetrieveCall.Returns.Error = errors.New("permission denied")
+
+			err := checker.Check(storage.State{IAAS: "gcp"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.WarnCall.Messages).To(Equal([]string{
+				"skipping quota check, gcp quotas could not be retrieved: permission denied",
+			}))
+		})
+
+		It("counts the director and jumpbox that create-env has not created yet", func() {
+			templateGenerator.GenerateCall.Returns.Template = ""
+			gcpRetriever.RetrieveCall.Returns.Quotas = []quota.Quota{
+				{Name: "some-region/CPUS", Resources: []string{"google_compute_instance", quota.CreateEnvVMResource}, Limit: 24, Usage: 23},
+			}
+
+			err := checker.Check(storage.State{
+				IAAS:    "gcp",
+				Jumpbox: storage.Jumpbox{Enabled: true},
+			})
+			Expect(err).To(MatchError(ContainSubstring("some-region/CPUS: 2 needed for create_env_vm, but only 1 of 24 available")))
+		})
+
+		It("only warns about a shortfall of a default limit", func() {
+			templateGenerator.GenerateCall.Returns.Template = `resource "aws_vpc" "vpc" {}`
+			awsRetriever.RetrieveCall.Returns.Quotas = []quota.Quota{
+				{Name: "vpcs", Resources: []string{"aws_vpc"}, Limit: 5, Usage: 5, Default: true},
+			}
+
+			err := checker.Check(storage.State{IAAS: "aws"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logger.WarnCall.Messages).To(Equal([]string{
+				"vpcs: 1 needed for aws_vpc, but only 0 of the default limit of 5 available, bbl will fail unless the limit was raised for this account",
+			}))
+		})
+
+		It("returns an error when the terraform state cannot be read", func() {
+			err := checker.Check(storage.State{IAAS: "gcp", TFState: "%%%"})
+			Expect(err).To(MatchError(ContainSubstring("failed to read terraform state: invalid character")))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	compute "google.golang.org/api/compute/v1"
+
+	"github.com/cloudfoundry/bosh-bootloader/gcp"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+)
+
+var (
+	projectQuotaResources = map[string][]string{
+		"STATIC_ADDRESSES":     {"google_compute_global_address"},
+		"FORWARDING_RULES":     {"google_compute_forwarding_rule", "google_compute_global_forwarding_rule"},
+		"FIREWALLS":            {"google_compute_firewall"},
+		"NETWORKS":             {"google_compute_network"},
+		"SUBNETWORKS":          {"google_compute_subnetwork"},
+		"TARGET_POOLS":         {"google_compute_target_pool"},
+		"HEALTH_CHECKS":        {"google_compute_http_health_check"},
+		"BACKEND_SERVICES":     {"google_compute_backend_service"},
+		"URL_MAPS":             {"google_compute_url_map"},
+		"TARGET_HTTP_PROXIES":  {"google_compute_target_http_proxy"},
+		"TARGET_HTTPS_PROXIES": {"google_compute_target_https_proxy"},
+		"SSL_CERTIFICATES":     {"google_compute_ssl_certificate"},
+	}
+
+	regionQuotaResources = map[string][]string{
+		"STATIC_ADDRESSES": {"google_compute_address"},
+		"INSTANCE_GROUPS":  {"google_compute_instance_group"},
+		"CPUS":             {"google_compute_instance", quota.CreateEnvVMResource},
+	}
+)
+
+type Retriever struct {
+	clientProvider clientProvider
+}
+
+type clientProvider interface {
+	SetConfig(serviceAccountKey, projectID, zone string) error
+	Client() gcp.Client
+}
+
+func NewRetriever(clientProvider clientProvider) Retriever {
+	return Retriever{
+		clientProvider: clientProvider,
+	}
+}
+
+func (r Retriever) Retrieve(state storage.State) ([]quota.Quota, error) {
+	err := r.clientProvider.SetConfig(state.GCP.ServiceAccountKey, state.GCP.ProjectID, state.GCP.Zone)
+	if err != nil {
+		return nil, err
+	}
+	client := r.clientProvider.Client()
+
+	project, err := client.GetProject()
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := client.GetRegion(state.GCP.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := r.convert("project", project.Quotas, projectQuotaResources)
+	quotas = append(quotas, r.convert(state.GCP.Region, region.Quotas, regionQuotaResources)...)
+
+	return quotas, nil
+}
+
+func (Retriever) convert(scope string, computeQuotas []*compute.Quota, resources map[string][]string) []quota.Quota {
+	var quotas []quota.Quota
+	for _, computeQuota := range computeQuotas {
+		if _, ok := resources[computeQuota.Metric]; !ok {
+			continue
+		}
+
+		quotas = append(quotas, quota.Quota{
+			Name:      scope + "/" + computeQuota.Metric,
+			Resources: resources[computeQuota.Metric],
+			Limit:     int(computeQuota.Limit),
+			Usage:     int(computeQuota.Usage),
+		})
+	}
+
+	return quotas
+}
@@ -0,0 +1,96 @@
+package gcp_test
+
+import (
+	"errors"
+
+	compute "google.golang.org/api/compute/v1"
+
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
+	"github.com/cloudfoundry/bosh-bootloader/quota"
+	gcpquota "github.com/cloudfoundry/bosh-bootloader/quota/gcp"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retriever", func() {
+	var (
+		clientProvider *fakes.GCPClientProvider
+		client         *fakes.GCPClient
+		state          storage.State
+
+		retriever gcpquota.Retriever
+	)
+
+	BeforeEach(func() {
+		client = &fakes.GCPClient{}
+		client.GetProjectCall.Returns.Project = &compute.Project{
+			Quotas: []*compute.Quota{
+				{Metric: "SNAPSHOTS", Limit: 1000, Usage: 10},
+				{Metric: "FORWARDING_RULES", Limit: 15, Usage: 4},
+			},
+		}
+		client.GetRegionCall.Returns.Region = &compute.Region{
+			Quotas: []*compute.Quota{
+				{Metric: "CPUS", Limit: 24, Usage: 8},
+				{Metric: "STATIC_ADDRESSES", Limit: 8, Usage: 1},
+			},
+		}
+
+		clientProvider = &fakes.GCPClientProvider{}
+		clientProvider.ClientCall.Returns.Client = client
+
+		state = storage.State{
+			GCP: storage.GCP{
+				ServiceAccountKey: "some-service-account-key",
+				ProjectID:         "some-project-id",
+				Zone:              "some-zone",
+				Region:            "some-region",
+			},
+		}
+
+		retriever = gcpquota.NewRetriever(clientProvider)
+	})
+
+	Describe("Retrieve", func() {
+		It("returns the project and region quotas that bbl creates resources against", func() {
+			quotas, err := retriever.Retrieve(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(quotas).To(Equal([]quota.Quota{
+				{Name: "project/FORWARDING_RULES", Resources: []string{"google_compute_forwarding_rule", "google_compute_global_forwarding_rule"}, Limit: 15, Usage: 4},
+				{Name: "some-region/CPUS", Resources: []string{"google_compute_instance", quota.CreateEnvVMResource}, Limit: 24, Usage: 8},
+				{Name: "some-region/STATIC_ADDRESSES", Resources: []string{"google_compute_address"}, Limit: 8, Usage: 1},
+			}))
+
+			Expect(clientProvider.SetConfigCall.Receives.ServiceAccountKey).To(Equal("some-service-account-key"))
+			Expect(clientProvider.SetConfigCall.Receives.ProjectID).To(Equal("some-project-id"))
+			Expect(clientProvider.SetConfigCall.Receives.Zone).To(Equal("some-zone"))
+			Expect(client.GetRegionCall.Receives.Region).To(Equal("some-region"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the service account key is invalid", func() {
+				clientProvider.SetConfigCall.Returns.Error = errors.New("invalid character")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("invalid character"))
+			})
+
+			It("returns an error when the project cannot be retrieved", func() {
+				client.GetProjectCall.Returns.Error = errors.New("failed to get project")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to get project"))
+			})
+
+			It("returns an error when the region cannot be retrieved", func() {
+				client.GetRegionCall.Returns.Error = errors.New("failed to get region")
+
+				_, err := retriever.Retrieve(state)
+				Expect(err).To(MatchError("failed to get region"))
+			})
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package ssl
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+)
+
+// Fingerprint returns the SHA-256 digest of the DER encoded certificates in
+// the given PEM data. Data that is not PEM encoded is digested as is.
+func Fingerprint(pemData ...string) string {
+	hash := sha256.New()
+
+	for _, data := range pemData {
+		rest := []byte(data)
+		found := false
+
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+
+			hash.Write(block.Bytes)
+			found = true
+		}
+
+		if !found {
+			hash.Write([]byte(data))
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
@@ -0,0 +1,33 @@
+package ssl_test
+
+import (
+	"crypto/sha256"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fingerprint", func() {
+	It("returns the sha256 digest of the DER encoded certificate", func() {
+		block, _ := pem.Decode([]byte(certificatePEM))
+
+		Expect(ssl.Fingerprint(certificatePEM)).To(Equal(fmt.Sprintf("%x", sha256.Sum256(block.Bytes))))
+	})
+
+	It("ignores whitespace around the PEM blocks", func() {
+		Expect(ssl.Fingerprint("\n" + certificatePEM + "\n\n")).To(Equal(ssl.Fingerprint(certificatePEM)))
+	})
+
+	It("changes when the chain changes", func() {
+		Expect(ssl.Fingerprint(certificatePEM, caPEM)).NotTo(Equal(ssl.Fingerprint(certificatePEM)))
+		Expect(ssl.Fingerprint(certificatePEM, caPEM)).NotTo(Equal(ssl.Fingerprint(certificatePEM, certificatePEM)))
+	})
+
+	It("digests data that is not PEM encoded as is", func() {
+		Expect(ssl.Fingerprint("some-cert")).To(Equal(fmt.Sprintf("%x", sha256.Sum256([]byte("some-cert")))))
+	})
+})
@@ -1,10 +1,13 @@
 package ssl
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
+	"fmt"
 	"io"
 	"net"
 
@@ -43,29 +46,86 @@ func NewKeyPairGenerator(
 }
 
 func (g KeyPairGenerator) Generate(caCommonName, commonName string) (KeyPair, error) {
-	caPrivateKey, err := g.generateKey(rand.Reader, 2048)
+	caCertificate, caKey, err := g.generateCA(caCommonName)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	return g.issue(caCertificate, caKey, commonName)
+}
+
+// GenerateCA creates a certificate authority that certificates can later be
+// issued from with GenerateFromCA.
+func (g KeyPairGenerator) GenerateCA(commonName string) (CAData, error) {
+	caCertificate, caKey, err := g.generateCA(commonName)
+	if err != nil {
+		return CAData{}, err
+	}
+
+	pemCA, err := caCertificate.Export()
+	if err != nil {
+		return CAData{}, err
+	}
+
+	return CAData{
+		CA: pemCA,
+		PrivateKey: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(caKey.Private.(*rsa.PrivateKey)),
+		}),
+	}, nil
+}
+
+// GenerateFromCA issues a certificate for commonName, which may be an IP
+// address or a host name, from an existing certificate authority.
+func (g KeyPairGenerator) GenerateFromCA(ca CAData, commonName string) (KeyPair, error) {
+	caCertificate, err := certstrappkix.NewCertificateFromPEM(ca.CA)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to parse ca certificate: %s", err)
+	}
+
+	caKey, err := parseCAKey(ca.PrivateKey)
 	if err != nil {
 		return KeyPair{}, err
 	}
+
+	return g.issue(caCertificate, caKey, commonName)
+}
+
+func (g KeyPairGenerator) generateCA(commonName string) (*certstrappkix.Certificate, *certstrappkix.Key, error) {
+	caPrivateKey, err := g.generateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
 	caKey := certstrappkix.NewKey(&caPrivateKey.PublicKey, caPrivateKey)
 
 	caCertificate, err := g.createCertificateAuthority(caKey, "Cloud Foundry", 2, "Cloud Foundry", "USA", "CA",
-		"San Francisco", caCommonName)
+		"San Francisco", commonName)
 	if err != nil {
-		return KeyPair{}, err
+		return nil, nil, err
 	}
 
+	return caCertificate, caKey, nil
+}
+
+func (g KeyPairGenerator) issue(caCertificate *certstrappkix.Certificate, caKey *certstrappkix.Key, commonName string) (KeyPair, error) {
 	certPrivateKey, err := g.generateKey(rand.Reader, 2048)
 	if err != nil {
 		return KeyPair{}, err
 	}
 	certKey := certstrappkix.NewKey(&certPrivateKey.PublicKey, certPrivateKey)
 
-	ipList := []net.IP{
-		net.ParseIP(commonName),
+	var (
+		ipList     []net.IP
+		domainList []string
+	)
+	if ip := net.ParseIP(commonName); ip != nil {
+		ipList = []net.IP{ip}
+	} else {
+		domainList = []string{commonName}
 	}
 
-	csr, err := g.createCertificateSigningRequest(certKey, "Cloud Foundry", ipList, nil, "Cloud Foundry",
+	csr, err := g.createCertificateSigningRequest(certKey, "Cloud Foundry", ipList, domainList, "Cloud Foundry",
 		"USA", "CA", "San Francisco", commonName)
 	if err != nil {
 		return KeyPair{}, err
@@ -95,3 +155,33 @@ func (g KeyPairGenerator) Generate(caCommonName, commonName string) (KeyPair, er
 		}),
 	}, nil
 }
+
+func parseCAKey(data []byte) (*certstrappkix.Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to parse ca private key: no PEM data found")
+	}
+
+	var (
+		privateKey interface{}
+		err        error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ca private key: %s", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported ca private key type %T", privateKey)
+	}
+
+	return certstrappkix.NewKey(signer.Public(), signer), nil
+}
@@ -122,6 +122,15 @@ var _ = Describe("KeyPairGenerator", func() {
 			Expect(strings.TrimSpace(string(generatedKeyPair.PrivateKey))).To(Equal(privateKeyPEM))
 		})
 
+		It("adds host names to the certificate's domain list", func() {
+			_, err := generator.Generate("BOSH Bootloader", "*.example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.IpList).To(BeNil())
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.DomainList).To(Equal([]string{"*.example.com"}))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.CommonName).To(Equal("*.example.com"))
+		})
+
 		Context("failure cases", func() {
 			Context("when private key generation fails for CA", func() {
 				It("returns error", func() {
@@ -170,6 +179,104 @@ var _ = Describe("KeyPairGenerator", func() {
 			})
 		})
 	})
+
+	Describe("GenerateCA", func() {
+		It("generates a CA and returns its certificate and private key", func() {
+			caData, err := generator.GenerateCA("some-ca")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePrivateKeyGenerator.GenerateKeyCall.CallCount).To(Equal(1))
+			Expect(fakeCertstrapPKIX.CreateCertificateAuthorityCall.Receives.Key.Private).To(Equal(caPrivateKey))
+			Expect(fakeCertstrapPKIX.CreateCertificateAuthorityCall.Receives.CommonName).To(Equal("some-ca"))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.CallCount).To(Equal(0))
+
+			Expect(strings.TrimSpace(string(caData.CA))).To(Equal(caPEM))
+			Expect(strings.TrimSpace(string(caData.PrivateKey))).To(Equal(caPrivateKeyPEM))
+		})
+
+		Context("failure cases", func() {
+			It("errors when private key generation fails", func() {
+				fakePrivateKeyGenerator.GenerateKeyCall.Stub = func() (*rsa.PrivateKey, error) {
+					return nil, errors.New("private key generation failed for ca")
+				}
+
+				_, err := generator.GenerateCA("some-ca")
+				Expect(err).To(MatchError("private key generation failed for ca"))
+			})
+
+			It("errors when create certificate authority fails", func() {
+				fakeCertstrapPKIX.CreateCertificateAuthorityCall.Returns.Error = errors.New("create certificate authority failed")
+
+				_, err := generator.GenerateCA("some-ca")
+				Expect(err).To(MatchError("create certificate authority failed"))
+			})
+		})
+	})
+
+	Describe("GenerateFromCA", func() {
+		var caData ssl.CAData
+
+		BeforeEach(func() {
+			caData = ssl.CAData{
+				CA:         []byte(caPEM),
+				PrivateKey: []byte(caPrivateKeyPEM),
+			}
+
+			fakePrivateKeyGenerator.GenerateKeyCall.Stub = func() (*rsa.PrivateKey, error) {
+				return privateKey, nil
+			}
+		})
+
+		It("issues a certificate from the provided CA", func() {
+			keyPair, err := generator.GenerateFromCA(caData, "10.0.0.1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePrivateKeyGenerator.GenerateKeyCall.CallCount).To(Equal(1))
+			Expect(fakeCertstrapPKIX.CreateCertificateAuthorityCall.CallCount).To(Equal(0))
+
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.Key.Private).To(Equal(privateKey))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.IpList).To(Equal([]net.IP{net.ParseIP("10.0.0.1")}))
+			Expect(fakeCertstrapPKIX.CreateCertificateSigningRequestCall.Receives.CommonName).To(Equal("10.0.0.1"))
+
+			Expect(fakeCertstrapPKIX.CreateCertificateHostCall.Receives.CrtAuth).To(Equal(ca))
+			Expect(fakeCertstrapPKIX.CreateCertificateHostCall.Receives.KeyAuth.Private).To(Equal(caPrivateKey))
+			Expect(fakeCertstrapPKIX.CreateCertificateHostCall.Receives.Csr).To(Equal(csr))
+
+			Expect(strings.TrimSpace(string(keyPair.CA))).To(Equal(caPEM))
+			Expect(strings.TrimSpace(string(keyPair.Certificate))).To(Equal(certificatePEM))
+			Expect(strings.TrimSpace(string(keyPair.PrivateKey))).To(Equal(privateKeyPEM))
+		})
+
+		Context("failure cases", func() {
+			It("errors when the CA certificate cannot be parsed", func() {
+				caData.CA = []byte("not a certificate")
+
+				_, err := generator.GenerateFromCA(caData, "10.0.0.1")
+				Expect(err).To(MatchError(ContainSubstring("failed to parse ca certificate")))
+			})
+
+			It("errors when the CA private key is not PEM encoded", func() {
+				caData.PrivateKey = []byte("not a key")
+
+				_, err := generator.GenerateFromCA(caData, "10.0.0.1")
+				Expect(err).To(MatchError("failed to parse ca private key: no PEM data found"))
+			})
+
+			It("errors when the CA private key cannot be parsed", func() {
+				caData.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("garbage")})
+
+				_, err := generator.GenerateFromCA(caData, "10.0.0.1")
+				Expect(err).To(MatchError(ContainSubstring("failed to parse ca private key")))
+			})
+
+			It("errors when create certificate host fails", func() {
+				fakeCertstrapPKIX.CreateCertificateHostCall.Returns.Error = errors.New("could not generate certificate host")
+
+				_, err := generator.GenerateFromCA(caData, "10.0.0.1")
+				Expect(err).To(MatchError("could not generate certificate host"))
+			})
+		})
+	})
 })
 
 func decodeAndParsePrivateKey(privateKeyPEM string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
@@ -15,6 +15,14 @@ type BOSH struct {
 	State                  map[string]interface{} `json:"state"`
 	Manifest               string                 `json:"manifest"`
 	UserOpsFile            string                 `json:"userOpsFile"`
+	PendingCAs             map[string]PendingCA   `json:"pendingCAs,omitempty"`
+}
+
+// PendingCA is a certificate authority that the director already trusts but
+// does not issue certificates from yet.
+type PendingCA struct {
+	Certificate string `json:"certificate"`
+	PrivateKey  string `json:"privateKey"`
 }
 
 func (b BOSH) IsEmpty() bool {
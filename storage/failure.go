@@ -0,0 +1,45 @@
+package storage
+
+import "time"
+
+const (
+	TerraformPhase   = "terraform"
+	CreateEnvPhase   = "create-env"
+	DeleteEnvPhase   = "delete-env"
+	JumpboxPhase     = "jumpbox"
+	CloudConfigPhase = "cloud-config"
+
+	MaxFailures          = 10
+	MaxFailureOutputSize = 64 * 1024
+)
+
+var Phases = []string{TerraformPhase, CreateEnvPhase, DeleteEnvPhase, JumpboxPhase, CloudConfigPhase}
+
+type Failure struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Phase   string    `json:"phase"`
+	Error   string    `json:"error"`
+	Output  string    `json:"output,omitempty"`
+}
+
+func (s *State) AddFailure(failure Failure) {
+	if len(failure.Output) > MaxFailureOutputSize {
+		failure.Output = failure.Output[len(failure.Output)-MaxFailureOutputSize:]
+	}
+
+	s.Failures = append(s.Failures, failure)
+	if len(s.Failures) > MaxFailures {
+		s.Failures = s.Failures[len(s.Failures)-MaxFailures:]
+	}
+}
+
+func (s State) LatestFailure(phase string) (Failure, bool) {
+	for i := len(s.Failures) - 1; i >= 0; i-- {
+		if phase == "" || s.Failures[i].Phase == phase {
+			return s.Failures[i], true
+		}
+	}
+
+	return Failure{}, false
+}
@@ -0,0 +1,94 @@
+package storage_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Failure", func() {
+	var failureTime time.Time
+
+	BeforeEach(func() {
+		failureTime = time.Date(2017, time.June, 1, 12, 30, 0, 0, time.UTC)
+	})
+
+	Describe("AddFailure", func() {
+		It("appends the failure to the state", func() {
+			state := storage.State{}
+			state.AddFailure(storage.Failure{
+				Time:    failureTime,
+				Command: "up",
+				Phase:   storage.TerraformPhase,
+				Error:   "failed to apply",
+				Output:  "some-output",
+			})
+
+			Expect(state.Failures).To(Equal([]storage.Failure{{
+				Time:    failureTime,
+				Command: "up",
+				Phase:   storage.TerraformPhase,
+				Error:   "failed to apply",
+				Output:  "some-output",
+			}}))
+		})
+
+		It("keeps only the most recent failures", func() {
+			state := storage.State{}
+			for i := 0; i < storage.MaxFailures+2; i++ {
+				state.AddFailure(storage.Failure{Error: fmt.Sprintf("failure %d", i)})
+			}
+
+			Expect(state.Failures).To(HaveLen(storage.MaxFailures))
+			Expect(state.Failures[0].Error).To(Equal("failure 2"))
+			Expect(state.Failures[storage.MaxFailures-1].Error).To(Equal(fmt.Sprintf("failure %d", storage.MaxFailures+1)))
+		})
+
+		It("keeps only the end of long output", func() {
+			state := storage.State{}
+			state.AddFailure(storage.Failure{
+				Output: strings.Repeat("a", storage.MaxFailureOutputSize) + "the end",
+			})
+
+			Expect(state.Failures[0].Output).To(HaveLen(storage.MaxFailureOutputSize))
+			Expect(state.Failures[0].Output).To(HaveSuffix("the end"))
+		})
+	})
+
+	Describe("LatestFailure", func() {
+		var state storage.State
+
+		BeforeEach(func() {
+			state = storage.State{}
+			state.AddFailure(storage.Failure{Phase: storage.TerraformPhase, Error: "first terraform failure"})
+			state.AddFailure(storage.Failure{Phase: storage.CreateEnvPhase, Error: "create-env failure"})
+			state.AddFailure(storage.Failure{Phase: storage.TerraformPhase, Error: "second terraform failure"})
+		})
+
+		It("returns the most recent failure for the phase", func() {
+			failure, ok := state.LatestFailure(storage.CreateEnvPhase)
+			Expect(ok).To(BeTrue())
+			Expect(failure.Error).To(Equal("create-env failure"))
+
+			failure, ok = state.LatestFailure(storage.TerraformPhase)
+			Expect(ok).To(BeTrue())
+			Expect(failure.Error).To(Equal("second terraform failure"))
+		})
+
+		It("returns the most recent failure of any phase when no phase is given", func() {
+			failure, ok := state.LatestFailure("")
+			Expect(ok).To(BeTrue())
+			Expect(failure.Error).To(Equal("second terraform failure"))
+		})
+
+		It("returns false when there is no failure for the phase", func() {
+			_, ok := state.LatestFailure(storage.CloudConfigPhase)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
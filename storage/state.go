@@ -23,16 +23,35 @@ type logger interface {
 }
 
 type AWS struct {
-	AccessKeyID     string `json:"accessKeyId"`
-	SecretAccessKey string `json:"secretAccessKey"`
-	Region          string `json:"region"`
+	AccessKeyID       string   `json:"accessKeyId"`
+	SecretAccessKey   string   `json:"secretAccessKey"`
+	Region            string   `json:"region"`
+	ExistingVPCID     string   `json:"existingVPCID,omitempty"`
+	ExistingSubnetIDs []string `json:"existingSubnetIDs,omitempty"`
+
+	DirectorAccessKeyID     string `json:"directorAccessKeyId,omitempty"`
+	DirectorSecretAccessKey string `json:"directorSecretAccessKey,omitempty"`
+	IAMInstanceProfile      bool   `json:"iamInstanceProfile,omitempty"`
+
+	// PreviousDirectorAccessKeyID is deleted once the director uses its new
+	// access key, see bbl rotate --iaas-credentials.
+	PreviousDirectorAccessKeyID string `json:"previousDirectorAccessKeyId,omitempty"`
 }
 
 type GCP struct {
-	ServiceAccountKey string `json:"serviceAccountKey"`
-	ProjectID         string `json:"projectID"`
-	Zone              string `json:"zone"`
-	Region            string `json:"region"`
+	ServiceAccountKey  string `json:"serviceAccountKey"`
+	ProjectID          string `json:"projectID"`
+	Zone               string `json:"zone"`
+	Region             string `json:"region"`
+	ExistingNetwork    string `json:"existingNetwork,omitempty"`
+	ExistingSubnetwork string `json:"existingSubnetwork,omitempty"`
+	NetworkProjectID   string `json:"networkProjectID,omitempty"`
+
+	DirectorServiceAccountKey string `json:"directorServiceAccountKey,omitempty"`
+
+	// PreviousDirectorServiceAccountKey is deleted once the director uses its
+	// new key, see bbl rotate --iaas-credentials.
+	PreviousDirectorServiceAccountKey string `json:"previousDirectorServiceAccountKey,omitempty"`
 }
 
 type Stack struct {
@@ -40,6 +59,14 @@ type Stack struct {
 	LBType          string `json:"lbType"`
 	CertificateName string `json:"certificateName"`
 	BOSHAZ          string `json:"boshAZ"`
+
+	// Migrated is set once bbl migrate moved the stack to terraform. The
+	// terraform templates keep the names the stack gave its resources.
+	Migrated bool `json:"migrated,omitempty"`
+
+	// MigrationInProgress is set while bbl migrate detaches the stack from
+	// resources it already imported into the tf state.
+	MigrationInProgress bool `json:"migrationInProgress,omitempty"`
 }
 
 type LB struct {
@@ -48,6 +75,14 @@ type LB struct {
 	Key    string `json:"key"`
 	Chain  string `json:"chain"`
 	Domain string `json:"domain,omitempty"`
+	CA     string `json:"ca,omitempty"`
+	ACME   *ACME  `json:"acme,omitempty"`
+}
+
+type ACME struct {
+	DirectoryURL string `json:"directoryURL"`
+	Email        string `json:"email,omitempty"`
+	AccountKey   string `json:"accountKey,omitempty"`
 }
 
 type Jumpbox struct {
@@ -58,19 +93,20 @@ type Jumpbox struct {
 }
 
 type State struct {
-	Version        int     `json:"version"`
-	IAAS           string  `json:"iaas"`
-	NoDirector     bool    `json:"noDirector"`
-	AWS            AWS     `json:"aws,omitempty"`
-	GCP            GCP     `json:"gcp,omitempty"`
-	KeyPair        KeyPair `json:"keyPair,omitempty"`
-	Jumpbox        Jumpbox `json:"jumpbox,omitempty"`
-	BOSH           BOSH    `json:"bosh,omitempty"`
-	Stack          Stack   `json:"stack"`
-	EnvID          string  `json:"envID"`
-	TFState        string  `json:"tfState"`
-	LB             LB      `json:"lb"`
-	LatestTFOutput string  `json:"latestTFOutput"`
+	Version        int       `json:"version"`
+	IAAS           string    `json:"iaas"`
+	NoDirector     bool      `json:"noDirector"`
+	AWS            AWS       `json:"aws,omitempty"`
+	GCP            GCP       `json:"gcp,omitempty"`
+	KeyPair        KeyPair   `json:"keyPair,omitempty"`
+	Jumpbox        Jumpbox   `json:"jumpbox,omitempty"`
+	BOSH           BOSH      `json:"bosh,omitempty"`
+	Stack          Stack     `json:"stack"`
+	EnvID          string    `json:"envID"`
+	TFState        string    `json:"tfState"`
+	LB             LB        `json:"lb"`
+	LatestTFOutput string    `json:"latestTFOutput"`
+	Failures       []Failure `json:"failures,omitempty"`
 }
 
 type Store struct {
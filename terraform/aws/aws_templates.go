@@ -1,6 +1,25 @@
 package aws
 
-const BaseTemplate = `resource "aws_eip" "bosh_eip" {
+// BaseTemplate creates the vpc, its subnets and a NAT instance along with
+// everything the director needs.
+const BaseTemplate = boshEIPTemplate +
+	iamTemplate +
+	natTemplate +
+	providerTemplate +
+	securityGroupsTemplate +
+	subnetsTemplate +
+	envIDTemplate +
+	vpcTemplate
+
+// ExistingVPCTemplate only reads the vpc and subnets it is given, so terraform
+// never changes or destroys them. The director is placed in the first subnet.
+const ExistingVPCTemplate = existingVPCTemplate +
+	iamTemplate +
+	providerTemplate +
+	securityGroupsTemplate +
+	envIDTemplate
+
+const boshEIPTemplate = `resource "aws_eip" "bosh_eip" {
   depends_on = ["aws_internet_gateway.ig"]
   vpc      = true
 }
@@ -13,7 +32,76 @@ output "bosh_url" {
   value = "https://${aws_eip.bosh_eip.public_ip}:25555"
 }
 
-resource "aws_iam_user" "bosh" {
+`
+
+const existingVPCTemplate = `variable "existing_vpc_id" {
+  type = "string"
+}
+
+variable "existing_subnet_ids" {
+  type = "list"
+}
+
+data "aws_vpc" "vpc" {
+  id = "${var.existing_vpc_id}"
+}
+
+data "aws_subnet" "existing_subnets" {
+  count = "${length(var.existing_subnet_ids)}"
+  id    = "${element(var.existing_subnet_ids, count.index)}"
+}
+
+resource "aws_eip" "bosh_eip" {
+  vpc      = true
+}
+
+output "bosh_eip" {
+  value = "${aws_eip.bosh_eip.public_ip}"
+}
+
+output "bosh_url" {
+  value = "https://${aws_eip.bosh_eip.public_ip}:25555"
+}
+
+output "bosh_subnet_id" {
+  value = "${data.aws_subnet.existing_subnets.0.id}"
+}
+
+output "bosh_subnet_availability_zone" {
+  value = "${data.aws_subnet.existing_subnets.0.availability_zone}"
+}
+
+output "bosh_subnet_cidr" {
+  value = "${data.aws_subnet.existing_subnets.0.cidr_block}"
+}
+
+output "bosh_subnet_gateway" {
+  value = "${cidrhost(data.aws_subnet.existing_subnets.0.cidr_block, 1)}"
+}
+
+output "bosh_director_internal_ip" {
+  value = "${cidrhost(data.aws_subnet.existing_subnets.0.cidr_block, 6)}"
+}
+
+output "internal_subnet_ids" {
+  value = ["${data.aws_subnet.existing_subnets.*.id}"]
+}
+
+output "internal_subnet_availability_zones" {
+  value = ["${data.aws_subnet.existing_subnets.*.availability_zone}"]
+}
+
+output "internal_subnet_cidrs" {
+  value = ["${data.aws_subnet.existing_subnets.*.cidr_block}"]
+}
+
+output "vpc_id" {
+  value = "${data.aws_vpc.vpc.id}"
+}
+
+`
+
+const iamTemplate = `resource "aws_iam_user" "bosh" {
   name = "${var.env_id}_bosh_user"
 }
 
@@ -22,7 +110,69 @@ resource "aws_iam_user_policy" "bosh" {
   user = "${aws_iam_user.bosh.name}"
 
   policy = <<EOF
+` + boshPolicy + `EOF
+}
+
+` + BOSHAccessKeyTemplate
+
+// BOSHAccessKeyTemplate gives the bosh user the access key of the director. It
+// is cut out of the iam template when the state has an access key of its own.
+const BOSHAccessKeyTemplate = `resource "aws_iam_access_key" "bosh" {
+  user = "${aws_iam_user.bosh.name}"
+}
+
+output "bosh_user_access_key" {
+  value = "${aws_iam_access_key.bosh.id}"
+}
+
+output "bosh_user_secret_access_key" {
+  value = "${aws_iam_access_key.bosh.secret}"
+}
+
+`
+
+// IAMInstanceProfileTemplate replaces the IAM user of the director, and its
+// access key, with a role that the director vm assumes.
+const IAMInstanceProfileTemplate = `resource "aws_iam_role" "bosh" {
+  name = "${var.env_id}_bosh_role"
+  path = "/"
+
+  assume_role_policy = <<EOF
 {
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "ec2.amazonaws.com"
+      },
+      "Effect": "Allow"
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy" "bosh" {
+  name = "${var.env_id}_bosh_role_policy"
+  role = "${aws_iam_role.bosh.id}"
+
+  policy = <<EOF
+` + boshPolicy + `EOF
+}
+
+resource "aws_iam_instance_profile" "bosh" {
+  name = "${var.env_id}_bosh_instance_profile"
+  role = "${aws_iam_role.bosh.name}"
+}
+
+output "bosh_iam_instance_profile" {
+  value = "${aws_iam_instance_profile.bosh.name}"
+}
+
+`
+
+const boshPolicy = `{
   "Version": "2012-10-17",
   "Statement": [
     {
@@ -60,22 +210,9 @@ resource "aws_iam_user_policy" "bosh" {
     }
   ]
 }
-EOF
-}
-
-resource "aws_iam_access_key" "bosh" {
-  user = "${aws_iam_user.bosh.name}"
-}
-
-output "bosh_user_access_key" {
-  value = "${aws_iam_access_key.bosh.id}"
-}
-
-output "bosh_user_secret_access_key" {
-  value = "${aws_iam_access_key.bosh.secret}"
-}
+`
 
-variable "nat_ami_map" {
+const natTemplate = `variable "nat_ami_map" {
   type = "map"
 
   default = {
@@ -156,7 +293,9 @@ output "nat_eip" {
   value = "${aws_eip.nat_eip.public_ip}"
 }
 
-variable "access_key" {
+`
+
+const providerTemplate = `variable "access_key" {
   type = "string"
 }
 
@@ -174,7 +313,9 @@ provider "aws" {
   region     = "${var.region}"
 }
 
-resource "aws_security_group" "internal_security_group" {
+`
+
+const securityGroupsTemplate = `resource "aws_security_group" "internal_security_group" {
   name        = "internal_security_group"
   description = "Internal"
   vpc_id      = "${aws_vpc.vpc.id}"
@@ -314,7 +455,9 @@ resource "aws_security_group_rule" "bosh_internal_security_rule_udp" {
   source_security_group_id = "${aws_security_group.bosh_security_group.id}"
 }
 
-variable "bosh_subnet_cidr" {
+`
+
+const subnetsTemplate = `variable "bosh_subnet_cidr" {
   type    = "string"
   default = "10.0.0.0/24"
 }
@@ -397,7 +540,9 @@ output "internal_subnet_cidrs" {
   value = ["${aws_subnet.internal_subnets.*.cidr_block}"]
 }
 
-variable "env_id" {
+`
+
+const envIDTemplate = `variable "env_id" {
   type = "string"
 }
 
@@ -405,7 +550,9 @@ variable "short_env_id" {
   type = "string"
 }
 
-variable "vpc_cidr" {
+`
+
+const vpcTemplate = `variable "vpc_cidr" {
   type = "string"
   default = "10.0.0.0/16"
 }
@@ -480,8 +627,12 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 resource "aws_iam_server_certificate" "lb_cert" {
-  name_prefix       = "${var.short_env_id}-"
+  name              = "${var.ssl_certificate_name}"
 
   certificate_body  = "${var.ssl_certificate}"
   certificate_chain = "${var.ssl_certificate_chain}"
@@ -1621,6 +1772,10 @@ output "env_dns_zone_name_servers" {
   value = "${aws_route53_zone.env_dns_zone.name_servers}"
 }
 
+output "env_dns_zone_id" {
+  value = "${aws_route53_zone.env_dns_zone.id}"
+}
+
 resource "aws_route53_record" "wildcard_dns" {
   zone_id = "${aws_route53_zone.env_dns_zone.id}"
   name    = "*.${var.system_domain}"
@@ -476,8 +476,12 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 resource "aws_iam_server_certificate" "lb_cert" {
-  name_prefix       = "${var.short_env_id}-"
+  name              = "${var.ssl_certificate_name}"
 
   certificate_body  = "${var.ssl_certificate}"
   certificate_chain = "${var.ssl_certificate_chain}"
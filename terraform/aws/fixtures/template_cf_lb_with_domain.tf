@@ -476,8 +476,12 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 resource "aws_iam_server_certificate" "lb_cert" {
-  name_prefix       = "${var.short_env_id}-"
+  name              = "${var.ssl_certificate_name}"
 
   certificate_body  = "${var.ssl_certificate}"
   certificate_chain = "${var.ssl_certificate_chain}"
@@ -1495,6 +1499,10 @@ output "env_dns_zone_name_servers" {
   value = "${aws_route53_zone.env_dns_zone.name_servers}"
 }
 
+output "env_dns_zone_id" {
+  value = "${aws_route53_zone.env_dns_zone.id}"
+}
+
 resource "aws_route53_record" "wildcard_dns" {
   zone_id = "${aws_route53_zone.env_dns_zone.id}"
   name    = "*.${var.system_domain}"
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
@@ -18,6 +19,10 @@ type availabilityZoneRetriever interface {
 
 const terraformNameCharLimit = 18
 
+// The certificate is named after its fingerprint so that a new certificate
+// is created alongside the old one, and swapped in, when it is rotated.
+const certificateFingerprintLength = 16
+
 var jsonMarshal = json.Marshal
 
 func NewInputGenerator(availabilityZoneRetriever availabilityZoneRetriever) InputGenerator {
@@ -27,16 +32,6 @@ func NewInputGenerator(availabilityZoneRetriever availabilityZoneRetriever) Inpu
 }
 
 func (i InputGenerator) Generate(state storage.State) (map[string]string, error) {
-	azs, err := i.availabilityZoneRetriever.Retrieve(state.AWS.Region)
-	if err != nil {
-		return map[string]string{}, err
-	}
-
-	azsString, err := jsonMarshal(azs)
-	if err != nil {
-		return map[string]string{}, err
-	}
-
 	shortEnvID := state.EnvID
 	if len(shortEnvID) > terraformNameCharLimit {
 		sha1 := fmt.Sprintf("%x", sha1.Sum([]byte(state.EnvID)))
@@ -44,20 +39,46 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 	}
 
 	inputs := map[string]string{
-		"env_id":                 state.EnvID,
-		"short_env_id":           shortEnvID,
-		"nat_ssh_key_pair_name":  state.KeyPair.Name,
-		"access_key":             state.AWS.AccessKeyID,
-		"secret_key":             state.AWS.SecretAccessKey,
-		"region":                 state.AWS.Region,
-		"bosh_availability_zone": state.Stack.BOSHAZ,
-		"availability_zones":     string(azsString),
+		"env_id":       state.EnvID,
+		"short_env_id": shortEnvID,
+		"access_key":   state.AWS.AccessKeyID,
+		"secret_key":   state.AWS.SecretAccessKey,
+		"region":       state.AWS.Region,
+	}
+
+	if state.AWS.ExistingVPCID != "" {
+		subnetIDsString, err := jsonMarshal(state.AWS.ExistingSubnetIDs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+
+		inputs["existing_vpc_id"] = state.AWS.ExistingVPCID
+		inputs["existing_subnet_ids"] = string(subnetIDsString)
+	} else {
+		azs, err := i.availabilityZoneRetriever.Retrieve(state.AWS.Region)
+		if err != nil {
+			return map[string]string{}, err
+		}
+
+		azsString, err := jsonMarshal(azs)
+		if err != nil {
+			return map[string]string{}, err
+		}
+
+		inputs["nat_ssh_key_pair_name"] = state.KeyPair.Name
+		inputs["bosh_availability_zone"] = state.Stack.BOSHAZ
+		inputs["availability_zones"] = string(azsString)
 	}
 
 	if state.LB.Type == "cf" || state.LB.Type == "concourse" {
 		inputs["ssl_certificate"] = state.LB.Cert
 		inputs["ssl_certificate_private_key"] = state.LB.Key
 		inputs["ssl_certificate_chain"] = state.LB.Chain
+		inputs["ssl_certificate_name"] = fmt.Sprintf("%s-%s", shortEnvID, ssl.Fingerprint(state.LB.Cert, state.LB.Chain)[:certificateFingerprintLength])
+		if state.Stack.CertificateName != "" {
+			// The certificate that bbl migrate imported from the stack.
+			inputs["ssl_certificate_name"] = state.Stack.CertificateName
+		}
 
 		if state.LB.Domain != "" {
 			inputs["system_domain"] = state.LB.Domain
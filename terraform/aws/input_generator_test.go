@@ -143,6 +143,7 @@ var _ = Describe("InputGenerator", func() {
 				"ssl_certificate":             "some-cert",
 				"ssl_certificate_chain":       "some-chain",
 				"ssl_certificate_private_key": "some-key",
+				"ssl_certificate_name":        "some-env-id-320d12c658065c18",
 			}))
 		})
 
@@ -169,6 +170,7 @@ var _ = Describe("InputGenerator", func() {
 					"ssl_certificate":             "some-cert",
 					"ssl_certificate_chain":       "some-chain",
 					"ssl_certificate_private_key": "some-key",
+					"ssl_certificate_name":        "some-env-id-320d12c658065c18",
 					"system_domain":               "some-domain",
 				}))
 			})
@@ -223,10 +225,96 @@ var _ = Describe("InputGenerator", func() {
 				"ssl_certificate":             "some-cert",
 				"ssl_certificate_chain":       "some-chain",
 				"ssl_certificate_private_key": "some-key",
+				"ssl_certificate_name":        "some-env-id-320d12c658065c18",
 			}))
 		})
 	})
 
+	Context("when the load balancer certificate is rotated", func() {
+		It("names the new certificate after its fingerprint", func() {
+			state := storage.State{
+				EnvID: "some-env-id",
+				LB: storage.LB{
+					Type:  "cf",
+					Cert:  "some-cert",
+					Chain: "some-chain",
+					Key:   "some-key",
+				},
+			}
+
+			inputs, err := inputGenerator.Generate(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			state.LB.Chain = "some-other-chain"
+			rotatedInputs, err := inputGenerator.Generate(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rotatedInputs["ssl_certificate_name"]).To(HavePrefix("some-env-id-"))
+			Expect(rotatedInputs["ssl_certificate_name"]).NotTo(Equal(inputs["ssl_certificate_name"]))
+		})
+	})
+
+	Context("when the certificate was imported from a cloudformation stack", func() {
+		It("keeps the name of the certificate", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				EnvID: "some-env-id",
+				Stack: storage.Stack{
+					CertificateName: "some-stack-certificate-name",
+					Migrated:        true,
+				},
+				LB: storage.LB{
+					Type: "cf",
+					Cert: "some-cert",
+					Key:  "some-key",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["ssl_certificate_name"]).To(Equal("some-stack-certificate-name"))
+		})
+	})
+
+	Context("when the environment uses an existing vpc", func() {
+		It("returns the vpc and subnets instead of the inputs for the network bbl creates", func() {
+			inputs, err := inputGenerator.Generate(storage.State{
+				IAAS:  "aws",
+				EnvID: "some-env-id",
+				AWS: storage.AWS{
+					AccessKeyID:       "some-access-key-id",
+					SecretAccessKey:   "some-secret-access-key",
+					Region:            "some-region",
+					ExistingVPCID:     "some-vpc-id",
+					ExistingSubnetIDs: []string{"some-subnet-1", "some-subnet-2"},
+				},
+				KeyPair: storage.KeyPair{
+					Name: "some-key-pair-name",
+				},
+				LB: storage.LB{
+					Type:  "concourse",
+					Cert:  "some-cert",
+					Chain: "some-chain",
+					Key:   "some-key",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs).To(Equal(map[string]string{
+				"env_id":                      "some-env-id",
+				"short_env_id":                "some-env-id",
+				"access_key":                  "some-access-key-id",
+				"secret_key":                  "some-secret-access-key",
+				"region":                      "some-region",
+				"existing_vpc_id":             "some-vpc-id",
+				"existing_subnet_ids":         `["some-subnet-1","some-subnet-2"]`,
+				"ssl_certificate":             "some-cert",
+				"ssl_certificate_chain":       "some-chain",
+				"ssl_certificate_private_key": "some-key",
+				"ssl_certificate_name":        "some-env-id-320d12c658065c18",
+			}))
+			Expect(availabilityZoneRetriever.RetrieveCall.Receives.Region).To(Equal(""))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the availability zone retriever fails", func() {
 			It("returns an error", func() {
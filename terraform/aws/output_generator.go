@@ -31,6 +31,7 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 		"bosh_url":                      "director_address",
 		"bosh_user_access_key":          "access_key_id",
 		"bosh_user_secret_access_key":   "secret_access_key",
+		"bosh_iam_instance_profile":     "iam_instance_profile",
 		"bosh_subnet_id":                "subnet_id",
 		"bosh_subnet_availability_zone": "az",
 		"bosh_security_group":           "default_security_groups",
@@ -40,6 +41,13 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 		"vpc_id":                        "vpc_id",
 	}
 
+	if state.AWS.ExistingVPCID != "" {
+		outputMapping["bosh_subnet_cidr"] = "internal_cidr"
+		outputMapping["bosh_subnet_gateway"] = "internal_gw"
+		outputMapping["bosh_director_internal_ip"] = "internal_ip"
+		outputMapping["internal_subnet_availability_zones"] = "internal_subnet_availability_zones"
+	}
+
 	switch state.LB.Type {
 	case "cf":
 		outputMapping["cf_router_lb_name"] = "cf_router_load_balancer"
@@ -59,6 +67,7 @@ func (o OutputGenerator) Generate(state storage.State) (map[string]interface{},
 				servers = append(servers, server.(string))
 			}
 			outputs["cf_system_domain_dns_servers"] = servers
+			outputs["cf_system_domain_dns_zone_id"] = tfOutputs["env_dns_zone_id"]
 		}
 	case "concourse":
 		outputMapping["concourse_lb_name"] = "concourse_load_balancer"
@@ -49,6 +49,7 @@ var _ = Describe("OutputGenerator", func() {
 			"cf_tcp_lb_name":                       "some-cf-tcp-lb",
 			"cf_tcp_lb_url":                        "some-cf-tcp-lb-url",
 			"env_dns_zone_name_servers":            []interface{}{"some-name-server-1", "some-name-server-2"},
+			"env_dns_zone_id":                      "some-zone-id",
 			"nat_eip":                              "some-nat-eip",
 			"vpc_id":                               "some-vpc-id",
 		}
@@ -87,6 +88,50 @@ var _ = Describe("OutputGenerator", func() {
 		})
 	})
 
+	Context("when the environment uses an existing vpc", func() {
+		It("also returns the director network and the availability zones of the subnets", func() {
+			executor.OutputsCall.Returns.Outputs["bosh_subnet_cidr"] = "10.10.0.0/24"
+			executor.OutputsCall.Returns.Outputs["bosh_subnet_gateway"] = "10.10.0.1"
+			executor.OutputsCall.Returns.Outputs["bosh_director_internal_ip"] = "10.10.0.6"
+			executor.OutputsCall.Returns.Outputs["internal_subnet_availability_zones"] = []interface{}{"some-az-1", "some-az-2"}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				EnvID:   "some-env-id",
+				TFState: "some-tf-state",
+				AWS: storage.AWS{
+					ExistingVPCID:     "some-vpc-id",
+					ExistingSubnetIDs: []string{"some-subnet-1", "some-subnet-2"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("internal_cidr", "10.10.0.0/24"))
+			Expect(outputs).To(HaveKeyWithValue("internal_gw", "10.10.0.1"))
+			Expect(outputs).To(HaveKeyWithValue("internal_ip", "10.10.0.6"))
+			Expect(outputs).To(HaveKeyWithValue("internal_subnet_availability_zones", []interface{}{"some-az-1", "some-az-2"}))
+			Expect(outputs).To(HaveKeyWithValue("vpc_id", "some-vpc-id"))
+		})
+	})
+
+	Context("when the director uses an IAM instance profile", func() {
+		It("returns the instance profile", func() {
+			executor.OutputsCall.Returns.Outputs["bosh_iam_instance_profile"] = "some-instance-profile"
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "aws",
+				EnvID:   "some-env-id",
+				TFState: "some-tf-state",
+				AWS: storage.AWS{
+					IAMInstanceProfile: true,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("iam_instance_profile", "some-instance-profile"))
+		})
+	})
+
 	Context("when cf lbs exist", func() {
 		It("returns all terraform outputs including cf lb related outputs", func() {
 			outputs, err := outputGenerator.Generate(storage.State{
@@ -123,6 +168,7 @@ var _ = Describe("OutputGenerator", func() {
 				"cf_tcp_router_load_balancer_url":       "some-cf-tcp-lb-url",
 				"cf_tcp_router_internal_security_group": "some-cf-tcp-lb-internal-security-group",
 				"cf_system_domain_dns_servers":          []string{"some-name-server-1", "some-name-server-2"},
+				"cf_system_domain_dns_zone_id":          "some-zone-id",
 				"vpc_id":                                "some-vpc-id",
 			}))
 		})
@@ -157,7 +203,7 @@ var _ = Describe("OutputGenerator", func() {
 				"concourse_load_balancer":           "some-concourse-lb-name",
 				"concourse_load_balancer_url":       "some-concourse-lb-url",
 				"concourse_internal_security_group": "some-concourse-internal-security-group",
-				"vpc_id": "some-vpc-id",
+				"vpc_id":                            "some-vpc-id",
 			}))
 		})
 	})
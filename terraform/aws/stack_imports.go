@@ -0,0 +1,202 @@
+package aws
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+)
+
+// StackImport pairs a resource in the terraform templates with the logical id
+// of the same resource in the cloudformation stacks bbl used to create.
+type StackImport struct {
+	Address   string
+	LogicalID string
+}
+
+func StackImports(availabilityZoneCount int, lbType string) []StackImport {
+	imports := []StackImport{
+		{Address: "aws_vpc.vpc", LogicalID: "VPC"},
+		{Address: "aws_internet_gateway.ig", LogicalID: "VPCGatewayInternetGateway"},
+		{Address: "aws_eip.bosh_eip", LogicalID: "BOSHEIP"},
+		{Address: "aws_iam_user.bosh", LogicalID: "BOSHUser"},
+		{Address: "aws_security_group.nat_security_group", LogicalID: "NATSecurityGroup"},
+		{Address: "aws_instance.nat", LogicalID: "NATInstance"},
+		{Address: "aws_eip.nat_eip", LogicalID: "NATEIP"},
+		{Address: "aws_security_group.internal_security_group", LogicalID: "InternalSecurityGroup"},
+		{Address: "aws_security_group.bosh_security_group", LogicalID: "BOSHSecurityGroup"},
+		{Address: "aws_subnet.bosh_subnet", LogicalID: "BOSHSubnet"},
+		{Address: "aws_route_table.bosh_route_table", LogicalID: "BOSHRouteTable"},
+		{Address: "aws_route_table.internal_route_table", LogicalID: "InternalRouteTable"},
+	}
+
+	for i := 0; i < availabilityZoneCount; i++ {
+		imports = append(imports, StackImport{
+			Address:   fmt.Sprintf("aws_subnet.internal_subnets[%d]", i),
+			LogicalID: fmt.Sprintf("InternalSubnet%d", i+1),
+		})
+	}
+
+	if lbType != "cf" && lbType != "concourse" {
+		return imports
+	}
+
+	imports = append(imports, StackImport{Address: "aws_route_table.lb_route_table", LogicalID: "LoadBalancerRouteTable"})
+	for i := 0; i < availabilityZoneCount; i++ {
+		imports = append(imports, StackImport{
+			Address:   fmt.Sprintf("aws_subnet.lb_subnets[%d]", i),
+			LogicalID: fmt.Sprintf("LoadBalancerSubnet%d", i+1),
+		})
+	}
+
+	switch lbType {
+	case "concourse":
+		imports = append(imports,
+			StackImport{Address: "aws_security_group.concourse_lb_security_group", LogicalID: "ConcourseSecurityGroup"},
+			StackImport{Address: "aws_security_group.concourse_lb_internal_security_group", LogicalID: "ConcourseInternalSecurityGroup"},
+			StackImport{Address: "aws_elb.concourse_lb", LogicalID: "ConcourseLoadBalancer"},
+		)
+	case "cf":
+		imports = append(imports,
+			StackImport{Address: "aws_security_group.cf_router_lb_security_group", LogicalID: "CFRouterSecurityGroup"},
+			StackImport{Address: "aws_security_group.cf_router_lb_internal_security_group", LogicalID: "CFRouterInternalSecurityGroup"},
+			StackImport{Address: "aws_elb.cf_router_lb", LogicalID: "CFRouterLoadBalancer"},
+			StackImport{Address: "aws_security_group.cf_ssh_lb_security_group", LogicalID: "CFSSHProxySecurityGroup"},
+			StackImport{Address: "aws_security_group.cf_ssh_lb_internal_security_group", LogicalID: "CFSSHProxyInternalSecurityGroup"},
+			StackImport{Address: "aws_elb.cf_ssh_lb", LogicalID: "CFSSHProxyLoadBalancer"},
+		)
+	}
+
+	return imports
+}
+
+// StackUserPolicyName is the name of the inline policy of the iam user of a
+// stack.
+const StackUserPolicyName = "aws-cpi"
+
+// StackSecurityGroupRule is a rule in the terraform templates. Stacks declare
+// these rules inside their security groups and terraform cannot import them,
+// so bbl migrate writes them to the tf state itself.
+type StackSecurityGroupRule struct {
+	Address                      string
+	SecurityGroupLogicalID       string
+	Type                         string
+	Protocol                     string
+	FromPort                     int
+	ToPort                       int
+	CIDRBlock                    string
+	Self                         bool
+	SourceSecurityGroupLogicalID string
+}
+
+func StackSecurityGroupRules() []StackSecurityGroupRule {
+	return []StackSecurityGroupRule{
+		{Address: "aws_security_group_rule.internal_security_group_rule_tcp", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 0, ToPort: 65535, Self: true},
+		{Address: "aws_security_group_rule.internal_security_group_rule_udp", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "ingress", Protocol: "udp", FromPort: 0, ToPort: 65535, Self: true},
+		{Address: "aws_security_group_rule.internal_security_group_rule_icmp", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "ingress", Protocol: "icmp", FromPort: -1, ToPort: -1, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.internal_security_group_rule_allow_internet", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "egress", Protocol: "-1", FromPort: 0, ToPort: 0, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_tcp_ssh", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 22, ToPort: 22, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_tcp_bosh_agent", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 6868, ToPort: 6868, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_tcp_director_api", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 25555, ToPort: 25555, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_tcp", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 0, ToPort: 65535, SourceSecurityGroupLogicalID: "InternalSecurityGroup"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_udp", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "ingress", Protocol: "udp", FromPort: 0, ToPort: 65535, SourceSecurityGroupLogicalID: "InternalSecurityGroup"},
+		{Address: "aws_security_group_rule.bosh_security_group_rule_allow_internet", SecurityGroupLogicalID: "BOSHSecurityGroup", Type: "egress", Protocol: "-1", FromPort: 0, ToPort: 0, CIDRBlock: "0.0.0.0/0"},
+		{Address: "aws_security_group_rule.bosh_internal_security_rule_tcp", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "ingress", Protocol: "tcp", FromPort: 0, ToPort: 65535, SourceSecurityGroupLogicalID: "BOSHSecurityGroup"},
+		{Address: "aws_security_group_rule.bosh_internal_security_rule_udp", SecurityGroupLogicalID: "InternalSecurityGroup", Type: "ingress", Protocol: "udp", FromPort: 0, ToPort: 65535, SourceSecurityGroupLogicalID: "BOSHSecurityGroup"},
+	}
+}
+
+// Attributes returns the tf state attributes of the rule. Terraform finds the
+// rule by these attributes and only tracks it by its id.
+func (r StackSecurityGroupRule) Attributes(securityGroupID, sourceSecurityGroupID string) map[string]string {
+	id := fmt.Sprintf("%s-%s-%s-%d-%d-%s-%s-%t", securityGroupID, r.Type, r.Protocol, r.FromPort, r.ToPort,
+		r.CIDRBlock, sourceSecurityGroupID, r.Self)
+
+	attributes := map[string]string{
+		"id":                "sgrule-" + strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(id))), 10),
+		"security_group_id": securityGroupID,
+		"type":              r.Type,
+		"protocol":          r.Protocol,
+		"from_port":         strconv.Itoa(r.FromPort),
+		"to_port":           strconv.Itoa(r.ToPort),
+		"self":              strconv.FormatBool(r.Self),
+		"cidr_blocks.#":     "0",
+		"prefix_list_ids.#": "0",
+	}
+	if r.CIDRBlock != "" {
+		attributes["cidr_blocks.#"] = "1"
+		attributes["cidr_blocks.0"] = r.CIDRBlock
+	}
+	if sourceSecurityGroupID != "" {
+		attributes["source_security_group_id"] = sourceSecurityGroupID
+	}
+
+	return attributes
+}
+
+// StackRouteTableAssociation is a route table association in the terraform
+// templates. Terraform cannot import these either.
+type StackRouteTableAssociation struct {
+	Address             string
+	SubnetLogicalID     string
+	RouteTableLogicalID string
+}
+
+func StackRouteTableAssociations(availabilityZoneCount int, lbType string) []StackRouteTableAssociation {
+	associations := []StackRouteTableAssociation{
+		{Address: "aws_route_table_association.route_bosh_subnets", SubnetLogicalID: "BOSHSubnet", RouteTableLogicalID: "BOSHRouteTable"},
+	}
+
+	for i := 0; i < availabilityZoneCount; i++ {
+		associations = append(associations, StackRouteTableAssociation{
+			Address:             fmt.Sprintf("aws_route_table_association.route_internal_subnets[%d]", i),
+			SubnetLogicalID:     fmt.Sprintf("InternalSubnet%d", i+1),
+			RouteTableLogicalID: "InternalRouteTable",
+		})
+	}
+
+	if lbType != "cf" && lbType != "concourse" {
+		return associations
+	}
+
+	for i := 0; i < availabilityZoneCount; i++ {
+		associations = append(associations, StackRouteTableAssociation{
+			Address:             fmt.Sprintf("aws_route_table_association.route_lb_subnets[%d]", i),
+			SubnetLogicalID:     fmt.Sprintf("LoadBalancerSubnet%d", i+1),
+			RouteTableLogicalID: "LoadBalancerRouteTable",
+		})
+	}
+
+	return associations
+}
+
+func (StackRouteTableAssociation) Attributes(associationID, subnetID, routeTableID string) map[string]string {
+	return map[string]string{
+		"id":             associationID,
+		"subnet_id":      subnetID,
+		"route_table_id": routeTableID,
+	}
+}
+
+// StackUserPolicyAttributes returns the tf state attributes of the inline
+// policy of the iam user, which terraform cannot import.
+func StackUserPolicyAttributes(userName string) map[string]string {
+	return map[string]string{
+		"id":   userName + ":" + StackUserPolicyName,
+		"name": StackUserPolicyName,
+		"user": userName,
+	}
+}
+
+// StackAdditions are the resources of the terraform templates that stacks
+// never had. Terraform creates them on the next bbl up.
+func StackAdditions(lbType string) []string {
+	if lbType != "cf" {
+		return nil
+	}
+
+	return []string{
+		"aws_security_group.cf_tcp_lb_security_group",
+		"aws_security_group.cf_tcp_lb_internal_security_group",
+		"aws_elb.cf_tcp_lb",
+	}
+}
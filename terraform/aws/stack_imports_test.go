@@ -0,0 +1,180 @@
+package aws_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/terraform/aws"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StackImports", func() {
+	It("maps the stack resources to the resources in the base template", func() {
+		imports := aws.StackImports(2, "")
+
+		Expect(imports).To(Equal([]aws.StackImport{
+			{Address: "aws_vpc.vpc", LogicalID: "VPC"},
+			{Address: "aws_internet_gateway.ig", LogicalID: "VPCGatewayInternetGateway"},
+			{Address: "aws_eip.bosh_eip", LogicalID: "BOSHEIP"},
+			{Address: "aws_iam_user.bosh", LogicalID: "BOSHUser"},
+			{Address: "aws_security_group.nat_security_group", LogicalID: "NATSecurityGroup"},
+			{Address: "aws_instance.nat", LogicalID: "NATInstance"},
+			{Address: "aws_eip.nat_eip", LogicalID: "NATEIP"},
+			{Address: "aws_security_group.internal_security_group", LogicalID: "InternalSecurityGroup"},
+			{Address: "aws_security_group.bosh_security_group", LogicalID: "BOSHSecurityGroup"},
+			{Address: "aws_subnet.bosh_subnet", LogicalID: "BOSHSubnet"},
+			{Address: "aws_route_table.bosh_route_table", LogicalID: "BOSHRouteTable"},
+			{Address: "aws_route_table.internal_route_table", LogicalID: "InternalRouteTable"},
+			{Address: "aws_subnet.internal_subnets[0]", LogicalID: "InternalSubnet1"},
+			{Address: "aws_subnet.internal_subnets[1]", LogicalID: "InternalSubnet2"},
+		}))
+	})
+
+	It("maps the concourse load balancer", func() {
+		imports := aws.StackImports(1, "concourse")
+
+		Expect(imports[len(imports)-5:]).To(Equal([]aws.StackImport{
+			{Address: "aws_route_table.lb_route_table", LogicalID: "LoadBalancerRouteTable"},
+			{Address: "aws_subnet.lb_subnets[0]", LogicalID: "LoadBalancerSubnet1"},
+			{Address: "aws_security_group.concourse_lb_security_group", LogicalID: "ConcourseSecurityGroup"},
+			{Address: "aws_security_group.concourse_lb_internal_security_group", LogicalID: "ConcourseInternalSecurityGroup"},
+			{Address: "aws_elb.concourse_lb", LogicalID: "ConcourseLoadBalancer"},
+		}))
+	})
+
+	It("maps the cf load balancers", func() {
+		imports := aws.StackImports(1, "cf")
+
+		Expect(imports[len(imports)-8:]).To(Equal([]aws.StackImport{
+			{Address: "aws_route_table.lb_route_table", LogicalID: "LoadBalancerRouteTable"},
+			{Address: "aws_subnet.lb_subnets[0]", LogicalID: "LoadBalancerSubnet1"},
+			{Address: "aws_security_group.cf_router_lb_security_group", LogicalID: "CFRouterSecurityGroup"},
+			{Address: "aws_security_group.cf_router_lb_internal_security_group", LogicalID: "CFRouterInternalSecurityGroup"},
+			{Address: "aws_elb.cf_router_lb", LogicalID: "CFRouterLoadBalancer"},
+			{Address: "aws_security_group.cf_ssh_lb_security_group", LogicalID: "CFSSHProxySecurityGroup"},
+			{Address: "aws_security_group.cf_ssh_lb_internal_security_group", LogicalID: "CFSSHProxyInternalSecurityGroup"},
+			{Address: "aws_elb.cf_ssh_lb", LogicalID: "CFSSHProxyLoadBalancer"},
+		}))
+	})
+})
+
+var _ = Describe("StackSecurityGroupRules", func() {
+	It("maps every security group rule in the base template", func() {
+		var addresses []string
+		for _, rule := range aws.StackSecurityGroupRules() {
+			addresses = append(addresses, rule.Address)
+		}
+
+		Expect(addresses).To(ConsistOf(
+			"aws_security_group_rule.internal_security_group_rule_tcp",
+			"aws_security_group_rule.internal_security_group_rule_udp",
+			"aws_security_group_rule.internal_security_group_rule_icmp",
+			"aws_security_group_rule.internal_security_group_rule_allow_internet",
+			"aws_security_group_rule.bosh_security_group_rule_tcp_ssh",
+			"aws_security_group_rule.bosh_security_group_rule_tcp_bosh_agent",
+			"aws_security_group_rule.bosh_security_group_rule_tcp_director_api",
+			"aws_security_group_rule.bosh_security_group_rule_tcp",
+			"aws_security_group_rule.bosh_security_group_rule_udp",
+			"aws_security_group_rule.bosh_security_group_rule_allow_internet",
+			"aws_security_group_rule.bosh_internal_security_rule_tcp",
+			"aws_security_group_rule.bosh_internal_security_rule_udp",
+		))
+	})
+
+	Describe("Attributes", func() {
+		It("returns the attributes of a rule with a cidr block", func() {
+			rule := aws.StackSecurityGroupRule{
+				Address:                "aws_security_group_rule.bosh_security_group_rule_tcp_ssh",
+				SecurityGroupLogicalID: "BOSHSecurityGroup",
+				Type:                   "ingress",
+				Protocol:               "tcp",
+				FromPort:               22,
+				ToPort:                 22,
+				CIDRBlock:              "0.0.0.0/0",
+			}
+
+			attributes := rule.Attributes("sg-12345", "")
+			Expect(attributes["id"]).To(HavePrefix("sgrule-"))
+			delete(attributes, "id")
+
+			Expect(attributes).To(Equal(map[string]string{
+				"security_group_id": "sg-12345",
+				"type":              "ingress",
+				"protocol":          "tcp",
+				"from_port":         "22",
+				"to_port":           "22",
+				"self":              "false",
+				"cidr_blocks.#":     "1",
+				"cidr_blocks.0":     "0.0.0.0/0",
+				"prefix_list_ids.#": "0",
+			}))
+		})
+
+		It("returns the attributes of a rule with a source security group", func() {
+			rule := aws.StackSecurityGroupRule{
+				Address:                      "aws_security_group_rule.bosh_security_group_rule_udp",
+				SecurityGroupLogicalID:       "BOSHSecurityGroup",
+				Type:                         "ingress",
+				Protocol:                     "udp",
+				FromPort:                     0,
+				ToPort:                       65535,
+				SourceSecurityGroupLogicalID: "InternalSecurityGroup",
+			}
+
+			attributes := rule.Attributes("sg-12345", "sg-67890")
+			Expect(attributes).To(HaveKeyWithValue("source_security_group_id", "sg-67890"))
+			Expect(attributes).To(HaveKeyWithValue("cidr_blocks.#", "0"))
+			Expect(attributes).NotTo(HaveKey("cidr_blocks.0"))
+		})
+
+		It("gives different rules different ids", func() {
+			rules := aws.StackSecurityGroupRules()
+
+			ids := map[string]bool{}
+			for _, rule := range rules {
+				ids[rule.Attributes("sg-12345", "sg-67890")["id"]] = true
+			}
+			Expect(ids).To(HaveLen(len(rules)))
+		})
+	})
+})
+
+var _ = Describe("StackRouteTableAssociations", func() {
+	It("routes the bosh and internal subnets", func() {
+		Expect(aws.StackRouteTableAssociations(2, "")).To(Equal([]aws.StackRouteTableAssociation{
+			{Address: "aws_route_table_association.route_bosh_subnets", SubnetLogicalID: "BOSHSubnet", RouteTableLogicalID: "BOSHRouteTable"},
+			{Address: "aws_route_table_association.route_internal_subnets[0]", SubnetLogicalID: "InternalSubnet1", RouteTableLogicalID: "InternalRouteTable"},
+			{Address: "aws_route_table_association.route_internal_subnets[1]", SubnetLogicalID: "InternalSubnet2", RouteTableLogicalID: "InternalRouteTable"},
+		}))
+	})
+
+	It("routes the load balancer subnets", func() {
+		associations := aws.StackRouteTableAssociations(1, "concourse")
+
+		Expect(associations[len(associations)-1]).To(Equal(aws.StackRouteTableAssociation{
+			Address:             "aws_route_table_association.route_lb_subnets[0]",
+			SubnetLogicalID:     "LoadBalancerSubnet1",
+			RouteTableLogicalID: "LoadBalancerRouteTable",
+		}))
+	})
+})
+
+var _ = Describe("StackUserPolicyAttributes", func() {
+	It("returns the attributes of the inline policy of the stack", func() {
+		Expect(aws.StackUserPolicyAttributes("some-user")).To(Equal(map[string]string{
+			"id":   "some-user:aws-cpi",
+			"name": "aws-cpi",
+			"user": "some-user",
+		}))
+	})
+})
+
+var _ = Describe("StackAdditions", func() {
+	It("returns the cf tcp load balancer, which stacks never had", func() {
+		Expect(aws.StackAdditions("cf")).To(ConsistOf(
+			"aws_security_group.cf_tcp_lb_security_group",
+			"aws_security_group.cf_tcp_lb_internal_security_group",
+			"aws_elb.cf_tcp_lb",
+		))
+		Expect(aws.StackAdditions("concourse")).To(BeEmpty())
+	})
+})
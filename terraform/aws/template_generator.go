@@ -1,11 +1,29 @@
 package aws
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
+// CloudFormation named the security groups and load balancers of a stack
+// itself and described some of the security groups differently. Terraform
+// can only change either by replacing the resource.
+var (
+	stackNameRegex = regexp.MustCompile(`(?m)^  name\s+= "(\w+_security_group|\$\{var\.short_env_id\}-[\w-]+-lb)"\n`)
+
+	stackDescriptions = strings.NewReplacer(
+		`description = "Bosh"`, `description = "BOSH"`,
+		`description = "Concourse Internal"`, `description = "ConcourseInternal"`,
+		`description = "CF Router"`, `description = "Router"`,
+		`description = "CF Router Internal"`, `description = "CFRouterInternal"`,
+		`description = "CF SSH"`, `description = "CFSSHProxy"`,
+		`description = "CF SSH Internal"`, `description = "CFSSHProxyInternal"`,
+		`name  = "${var.env_id}_bosh_user_policy"`, `name  = "`+StackUserPolicyName+`"`,
+	)
+)
+
 type TemplateGenerator struct {
 }
 
@@ -14,18 +32,47 @@ func NewTemplateGenerator() TemplateGenerator {
 }
 
 func (t TemplateGenerator) Generate(state storage.State) string {
-	template := BaseTemplate
+	existingVPC := state.AWS.ExistingVPCID != ""
+
+	templates := []string{BaseTemplate}
+	lbSubnetTemplates := []string{LBSubnetTemplate}
+	if existingVPC {
+		templates = []string{ExistingVPCTemplate}
+		lbSubnetTemplates = nil
+	}
 
 	switch state.LB.Type {
 	case "concourse":
-		template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, ConcourseLBTemplate}, "\n")
+		templates = append(templates, lbSubnetTemplates...)
+		templates = append(templates, SSLCertificateTemplate, ConcourseLBTemplate)
 	case "cf":
+		templates = append(templates, lbSubnetTemplates...)
+		templates = append(templates, SSLCertificateTemplate, CFLBTemplate)
 		if state.LB.Domain != "" {
-			template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, CFLBTemplate, CFDNSTemplate}, "\n")
-		} else {
-			template = strings.Join([]string{template, LBSubnetTemplate, SSLCertificateTemplate, CFLBTemplate}, "\n")
+			templates = append(templates, CFDNSTemplate)
 		}
 	}
 
+	template := strings.Join(templates, "\n")
+
+	if state.AWS.IAMInstanceProfile {
+		template = strings.Replace(template, iamTemplate, IAMInstanceProfileTemplate, 1)
+	} else if state.AWS.DirectorAccessKeyID != "" {
+		template = strings.Replace(template, BOSHAccessKeyTemplate, "", 1)
+	}
+
+	if existingVPC {
+		// The security group and load balancer templates are shared with
+		// environments where bbl creates the vpc and its lb subnets.
+		template = strings.NewReplacer(
+			"${aws_vpc.vpc.id}", "${data.aws_vpc.vpc.id}",
+			"${aws_subnet.lb_subnets.*.id}", "${var.existing_subnet_ids}",
+		).Replace(template)
+	}
+
+	if state.Stack.Migrated {
+		template = stackDescriptions.Replace(stackNameRegex.ReplaceAllString(template, ""))
+	}
+
 	return template
 }
@@ -39,5 +39,100 @@ var _ = Describe("TemplateGenerator", func() {
 			Entry("when a cf lb type is provided", "fixtures/template_cf_lb.tf", "cf", ""),
 			Entry("when a cf lb type is provided with a system domain", "fixtures/template_cf_lb_with_domain.tf", "cf", "some-domain"),
 		)
+
+		Context("when the environment uses an existing vpc", func() {
+			It("reads the vpc and subnets instead of creating a network", func() {
+				template := templateGenerator.Generate(storage.State{
+					AWS: storage.AWS{
+						ExistingVPCID:     "some-vpc-id",
+						ExistingSubnetIDs: []string{"some-subnet-1", "some-subnet-2"},
+					},
+					LB: storage.LB{
+						Type: "cf",
+					},
+				})
+
+				Expect(template).To(ContainSubstring(`data "aws_vpc" "vpc" {`))
+				Expect(template).To(ContainSubstring(`data "aws_subnet" "existing_subnets" {`))
+				Expect(template).To(ContainSubstring(`vpc_id      = "${data.aws_vpc.vpc.id}"`))
+				Expect(template).To(ContainSubstring(`subnets         = ["${var.existing_subnet_ids}"]`))
+				Expect(template).To(ContainSubstring(`resource "aws_elb" "cf_router_lb" {`))
+
+				Expect(template).NotTo(ContainSubstring(`resource "aws_vpc"`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_internet_gateway"`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_instance" "nat"`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_subnet"`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_route_table"`))
+				Expect(template).NotTo(ContainSubstring(`${aws_vpc.vpc.id}`))
+				Expect(template).NotTo(ContainSubstring(`aws_subnet.lb_subnets`))
+			})
+		})
+
+		Context("when bbl manages the access key of the director", func() {
+			It("leaves the terraform access key out", func() {
+				template := templateGenerator.Generate(storage.State{
+					AWS: storage.AWS{
+						DirectorAccessKeyID: "some-access-key-id",
+					},
+				})
+
+				Expect(template).To(ContainSubstring(`resource "aws_iam_user" "bosh" {`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_iam_access_key" "bosh" {`))
+				Expect(template).NotTo(ContainSubstring(`output "bosh_user_access_key" {`))
+			})
+		})
+
+		Context("when the director uses an IAM instance profile", func() {
+			It("replaces the IAM user of the director with an instance profile", func() {
+				template := templateGenerator.Generate(storage.State{
+					AWS: storage.AWS{
+						IAMInstanceProfile: true,
+					},
+				})
+
+				Expect(template).To(ContainSubstring(aws.IAMInstanceProfileTemplate))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_iam_user" "bosh" {`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_iam_access_key" "bosh" {`))
+			})
+
+			It("replaces the IAM user in an existing vpc as well", func() {
+				template := templateGenerator.Generate(storage.State{
+					AWS: storage.AWS{
+						ExistingVPCID:      "some-vpc-id",
+						ExistingSubnetIDs:  []string{"some-subnet-1"},
+						IAMInstanceProfile: true,
+					},
+				})
+
+				Expect(template).To(ContainSubstring(`resource "aws_iam_instance_profile" "bosh" {`))
+				Expect(template).NotTo(ContainSubstring(`resource "aws_iam_user" "bosh" {`))
+			})
+		})
+
+		Context("when the environment was migrated from a cloudformation stack", func() {
+			It("keeps the names and descriptions the stack gave its resources", func() {
+				template := templateGenerator.Generate(storage.State{
+					Stack: storage.Stack{
+						Migrated: true,
+					},
+					LB: storage.LB{
+						Type: "cf",
+					},
+				})
+
+				Expect(template).NotTo(ContainSubstring(`name        = "internal_security_group"`))
+				Expect(template).NotTo(ContainSubstring(`name = "cf_router_lb_security_group"`))
+				Expect(template).NotTo(ContainSubstring(`name                      = "${var.short_env_id}-cf-router-lb"`))
+				Expect(template).To(ContainSubstring(`description = "BOSH"`))
+				Expect(template).To(ContainSubstring(`description = "Router"`))
+				Expect(template).To(ContainSubstring(`description = "CFRouterInternal"`))
+				Expect(template).To(ContainSubstring(`description = "CFSSHProxy"`))
+				Expect(template).To(ContainSubstring(`description = "CFSSHProxyInternal"`))
+				Expect(template).To(ContainSubstring(`name  = "aws-cpi"`))
+
+				Expect(template).To(ContainSubstring(`name              = "${var.ssl_certificate_name}"`))
+				Expect(template).To(ContainSubstring(`name = "${var.env_id}_bosh_user"`))
+			})
+		})
 	})
 })
@@ -1,33 +1,77 @@
 package terraform
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os/exec"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
 )
 
 type Cmd struct {
 	stderr       io.Writer
 	outputBuffer io.Writer
+	logWriter    io.Writer
+	events       subprocessEvents
+}
+
+type subprocessEvents interface {
+	SubprocessStarted(name, subcommand string)
+	SubprocessExited(name, subcommand string, duration time.Duration, err error)
 }
 
-func NewCmd(stderr, outputBuffer io.Writer) Cmd {
+func NewCmd(stderr, outputBuffer, logWriter io.Writer, events subprocessEvents) Cmd {
 	return Cmd{
 		stderr:       stderr,
 		outputBuffer: outputBuffer,
+		logWriter:    logWriter,
+		events:       events,
 	}
 }
 
-func (c Cmd) Run(stdout io.Writer, workingDirectory string, args []string, debug bool) error {
+func (c Cmd) Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string, debug bool) error {
 	command := exec.Command("terraform", args...)
 	command.Dir = workingDirectory
 
+	subcommand := subcommandName(args)
+
+	// output and plan print output values and planned attributes, which
+	// include keys, so only their stderr is written to the log file.
+	stdoutLogWriter := c.logWriter
+	if subcommand == "output" || subcommand == "plan" {
+		stdoutLogWriter = ioutil.Discard
+	}
+
 	if debug {
-		command.Stdout = io.MultiWriter(stdout, c.outputBuffer)
-		command.Stderr = io.MultiWriter(c.stderr, c.outputBuffer)
+		command.Stdout = io.MultiWriter(stdout, c.outputBuffer, stdoutLogWriter)
+		command.Stderr = io.MultiWriter(c.stderr, c.outputBuffer, c.logWriter)
 	} else {
-		command.Stdout = c.outputBuffer
-		command.Stderr = c.outputBuffer
+		command.Stdout = io.MultiWriter(c.outputBuffer, stdoutLogWriter)
+		command.Stderr = io.MultiWriter(c.outputBuffer, c.logWriter)
+	}
+
+	fmt.Fprintf(c.logWriter, "running terraform %s\n", subcommand)
+	c.events.SubprocessStarted("terraform", subcommand)
+	started := time.Now()
+
+	err := helpers.RunCommand(ctx, command)
+	c.events.SubprocessExited("terraform", subcommand, time.Since(started), err)
+	if err != nil {
+		fmt.Fprintf(c.logWriter, "terraform %s failed: %s\n", subcommand, err)
+		return err
+	}
+
+	fmt.Fprintf(c.logWriter, "terraform %s succeeded\n", subcommand)
+	return nil
+}
+
+func subcommandName(args []string) string {
+	if len(args) == 0 {
+		return ""
 	}
 
-	return command.Run()
+	return args[0]
 }
@@ -2,6 +2,7 @@ package terraform_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
 
 	. "github.com/onsi/ginkgo"
@@ -24,6 +26,8 @@ var _ = Describe("Run", func() {
 		stdout       *bytes.Buffer
 		stderr       *bytes.Buffer
 		outputBuffer *bytes.Buffer
+		logWriter    *bytes.Buffer
+		events       *fakes.SubprocessEvents
 
 		cmd terraform.Cmd
 
@@ -53,14 +57,21 @@ var _ = Describe("Run", func() {
 		stdout = bytes.NewBuffer([]byte{})
 		stderr = bytes.NewBuffer([]byte{})
 		outputBuffer = bytes.NewBuffer([]byte{})
+		logWriter = bytes.NewBuffer([]byte{})
 
-		cmd = terraform.NewCmd(stderr, outputBuffer)
+		events = &fakes.SubprocessEvents{}
+
+		cmd = terraform.NewCmd(stderr, outputBuffer, logWriter, events)
 
 		fakeTerraformBackendServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 			if getFastFailTerraform() {
 				responseWriter.WriteHeader(http.StatusInternalServerError)
 			}
 
+			if request.URL.Path == "/output/some-output" {
+				responseWriter.Write([]byte("some-secret-value"))
+			}
+
 			if request.Method == "POST" {
 				terraformArgsMutex.Lock()
 				defer terraformArgsMutex.Unlock()
@@ -93,7 +104,7 @@ var _ = Describe("Run", func() {
 	})
 
 	It("runs terraform with args", func() {
-		err := cmd.Run(stdout, "/tmp", []string{"apply", "some-arg"}, false)
+		err := cmd.Run(context.Background(), stdout, "/tmp", []string{"apply", "some-arg"}, false)
 		Expect(err).NotTo(HaveOccurred())
 
 		terraformArgsMutex.Lock()
@@ -105,7 +116,7 @@ var _ = Describe("Run", func() {
 	})
 
 	It("redirects command stdout to the provided buffer", func() {
-		err := cmd.Run(nil, "/tmp", []string{"apply", "some-arg"}, false)
+		err := cmd.Run(context.Background(), nil, "/tmp", []string{"apply", "some-arg"}, false)
 		Expect(err).NotTo(HaveOccurred())
 
 		terraformArgsMutex.Lock()
@@ -117,14 +128,70 @@ var _ = Describe("Run", func() {
 		Expect(outputBufferContents).To(ContainSubstring("apply some-arg"))
 	})
 
+	It("writes the command output to the log writer", func() {
+		err := cmd.Run(context.Background(), stdout, "/tmp", []string{"apply", "some-arg"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		logContents := logWriter.String()
+		Expect(logContents).To(HavePrefix("running terraform apply\n"))
+		Expect(logContents).To(MatchRegexp("working directory: (.*)/tmp"))
+		Expect(logContents).To(HaveSuffix("terraform apply succeeded\n"))
+	})
+
+	It("does not write the stdout of terraform output to the log writer", func() {
+		err := cmd.Run(context.Background(), stdout, "/tmp", []string{"output", "some-output"}, true)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stdout.String()).To(Equal("some-secret-value"))
+		Expect(logWriter.String()).To(Equal("running terraform output\nterraform output succeeded\n"))
+	})
+
+	It("reports the start and exit of the subprocess", func() {
+		err := cmd.Run(context.Background(), stdout, "/tmp", []string{"apply", "some-arg"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(events.SubprocessStartedCall.CallCount).To(Equal(1))
+		Expect(events.SubprocessStartedCall.Receives.Name).To(Equal("terraform"))
+		Expect(events.SubprocessStartedCall.Receives.Subcommand).To(Equal("apply"))
+
+		Expect(events.SubprocessExitedCall.CallCount).To(Equal(1))
+		Expect(events.SubprocessExitedCall.Receives.Name).To(Equal("terraform"))
+		Expect(events.SubprocessExitedCall.Receives.Subcommand).To(Equal("apply"))
+		Expect(events.SubprocessExitedCall.Receives.Duration).To(BeNumerically(">", 0))
+		Expect(events.SubprocessExitedCall.Receives.Error).NotTo(HaveOccurred())
+	})
+
 	It("redirects command stdout to provided stdout when debug is true", func() {
-		err := cmd.Run(stdout, "/tmp", []string{"apply", "some-arg"}, true)
+		err := cmd.Run(context.Background(), stdout, "/tmp", []string{"apply", "some-arg"}, true)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(stdout).To(MatchRegexp("working directory: (.*)/tmp"))
 		Expect(stdout).To(ContainSubstring("apply some-arg"))
 	})
 
+	Context("when the context is cancelled", func() {
+		It("interrupts terraform and waits for it to write its state", func() {
+			workingDirectory, err := ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				defer GinkgoRecover()
+				Eventually(filepath.Join(workingDirectory, "waiting-for-interrupt")).Should(BeAnExistingFile())
+				cancel()
+			}()
+
+			err = cmd.Run(ctx, stdout, workingDirectory, []string{"apply", "-var", "region=wait-for-interrupt"}, false)
+			Expect(err).To(Equal(context.Canceled))
+
+			tfState, err := ioutil.ReadFile(filepath.Join(workingDirectory, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(tfState)).To(Equal(`{"key":"interrupted-apply"}`))
+
+			Expect(logWriter.String()).To(HaveSuffix("terraform apply failed: context canceled\n"))
+		})
+	})
+
 	Context("failure case", func() {
 		BeforeEach(func() {
 			setFastFailTerraform(true)
@@ -135,15 +202,29 @@ var _ = Describe("Run", func() {
 		})
 
 		It("returns an error and redirects command stderr to the provided buffer when terraform fails", func() {
-			err := cmd.Run(stdout, "", []string{"fast-fail"}, false)
+			err := cmd.Run(context.Background(), stdout, "", []string{"fast-fail"}, false)
 			Expect(err).To(MatchError("exit status 1"))
 
 			outputBufferContents := string(outputBuffer.Bytes())
 			Expect(outputBufferContents).To(ContainSubstring("failed to terraform"))
 		})
 
+		It("writes the command stderr and the failure to the log writer", func() {
+			_ = cmd.Run(context.Background(), stdout, "", []string{"fast-fail"}, false)
+
+			Expect(logWriter.String()).To(ContainSubstring("failed to terraform"))
+			Expect(logWriter.String()).To(HaveSuffix("terraform fast-fail failed: exit status 1\n"))
+		})
+
+		It("reports the failed exit of the subprocess", func() {
+			_ = cmd.Run(context.Background(), stdout, "", []string{"fast-fail"}, false)
+
+			Expect(events.SubprocessExitedCall.CallCount).To(Equal(1))
+			Expect(events.SubprocessExitedCall.Receives.Error).To(MatchError("exit status 1"))
+		})
+
 		It("redirects command stderr to provided stderr and buffer when debug is true", func() {
-			_ = cmd.Run(stdout, "", []string{"fast-fail"}, true)
+			_ = cmd.Run(context.Background(), stdout, "", []string{"fast-fail"}, true)
 			Expect(stderr).To(ContainSubstring("failed to terraform"))
 
 			outputBufferContents := string(outputBuffer.Bytes())
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/cloudfoundry/bosh-bootloader/helpers"
+	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 var tempDir func(dir, prefix string) (string, error) = ioutil.TempDir
@@ -18,8 +23,16 @@ var writeFile func(file string, data []byte, perm os.FileMode) error = ioutil.Wr
 var readFile func(filename string) ([]byte, error) = ioutil.ReadFile
 
 type Executor struct {
-	cmd   terraformCmd
-	debug bool
+	cmd     terraformCmd
+	debug   bool
+	timeout time.Duration
+}
+
+// Output is the value of a terraform output and whether the template marks it
+// as sensitive.
+type Output struct {
+	Value     interface{}
+	Sensitive bool
 }
 
 type tfOutput struct {
@@ -29,14 +42,14 @@ type tfOutput struct {
 }
 
 type terraformCmd interface {
-	Run(stdout io.Writer, workingDirectory string, args []string, debug bool) error
+	Run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string, debug bool) error
 }
 
-func NewExecutor(cmd terraformCmd, debug bool) Executor {
-	return Executor{cmd: cmd, debug: debug}
+func NewExecutor(cmd terraformCmd, debug bool, timeout time.Duration) Executor {
+	return Executor{cmd: cmd, debug: debug, timeout: timeout}
 }
 
-func (e Executor) Apply(input map[string]string, template, prevTFState string) (string, error) {
+func (e Executor) Apply(ctx context.Context, input map[string]string, template, prevTFState string) (string, error) {
 	tempDir, err := tempDir("", "")
 	if err != nil {
 		return "", err
@@ -58,7 +71,7 @@ func (e Executor) Apply(input map[string]string, template, prevTFState string) (
 	for k, v := range input {
 		args = append(args, makeVar(k, v)...)
 	}
-	err = e.cmd.Run(os.Stdout, tempDir, args, e.debug)
+	err = e.run(ctx, os.Stdout, tempDir, args, e.debug)
 	if err != nil {
 		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
 	}
@@ -71,7 +84,7 @@ func (e Executor) Apply(input map[string]string, template, prevTFState string) (
 	return string(tfState), nil
 }
 
-func (e Executor) Destroy(input map[string]string, template, prevTFState string) (string, error) {
+func (e Executor) Destroy(ctx context.Context, input map[string]string, template, prevTFState string) (string, error) {
 	tempDir, err := tempDir("", "")
 	if err != nil {
 		return "", err
@@ -93,7 +106,7 @@ func (e Executor) Destroy(input map[string]string, template, prevTFState string)
 	for k, v := range input {
 		args = append(args, makeVar(k, v)...)
 	}
-	err = e.cmd.Run(os.Stdout, tempDir, args, e.debug)
+	err = e.run(ctx, os.Stdout, tempDir, args, e.debug)
 	if err != nil {
 		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
 	}
@@ -106,9 +119,92 @@ func (e Executor) Destroy(input map[string]string, template, prevTFState string)
 	return string(tfState), nil
 }
 
+// Plan refreshes the given tf state and returns the changes terraform would
+// make to bring the infrastructure back in line with the template. The tf
+// state itself is left untouched.
+func (e Executor) Plan(ctx context.Context, input map[string]string, template, tfState string) (string, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "template.tf"), []byte(template), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"plan", "-refresh=true", "-input=false", "-no-color"}
+	for k, v := range input {
+		args = append(args, makeVar(k, v)...)
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err = e.run(ctx, buffer, tempDir, args, true)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+// Import adds an existing resource to the tf state under the given address.
+func (e Executor) Import(ctx context.Context, input map[string]string, template, tfState, address, id string) (string, error) {
+	tempDir, err := tempDir("", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFile(filepath.Join(tempDir, "template.tf"), []byte(template), os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	if tfState != "" {
+		err = writeFile(filepath.Join(tempDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	args := []string{"import", "-input=false"}
+	for k, v := range input {
+		args = append(args, makeVar(k, v)...)
+	}
+	args = append(args, address, id)
+
+	err = e.run(ctx, os.Stdout, tempDir, args, e.debug)
+	if err != nil {
+		return "", NewExecutorError(filepath.Join(tempDir, "terraform.tfstate"), err, e.debug)
+	}
+
+	newTFState, err := readFile(filepath.Join(tempDir, "terraform.tfstate"))
+	if err != nil {
+		return "", err
+	}
+
+	return string(newTFState), nil
+}
+
+func (e Executor) run(ctx context.Context, stdout io.Writer, workingDirectory string, args []string, debug bool) error {
+	runCtx, cancel := helpers.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	err := e.cmd.Run(runCtx, stdout, workingDirectory, args, debug)
+	if err != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return helpers.NewTimeoutError(storage.TerraformPhase, e.timeout)
+	}
+
+	return err
+}
+
 func (e Executor) Version() (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
-	err := e.cmd.Run(buffer, "/tmp", []string{"version"}, true)
+	err := e.cmd.Run(context.Background(), buffer, "/tmp", []string{"version"}, true)
 	if err != nil {
 		return "", err
 	}
@@ -136,7 +232,7 @@ func (e Executor) Output(tfState, outputName string) (string, error) {
 
 	args := []string{"output", outputName}
 	buffer := bytes.NewBuffer([]byte{})
-	err = e.cmd.Run(buffer, templateDir, args, true)
+	err = e.cmd.Run(context.Background(), buffer, templateDir, args, true)
 	if err != nil {
 		return "", err
 	}
@@ -145,33 +241,49 @@ func (e Executor) Output(tfState, outputName string) (string, error) {
 }
 
 func (e Executor) Outputs(tfState string) (map[string]interface{}, error) {
-	templateDir, err := tempDir("", "")
+	detailedOutputs, err := e.DetailedOutputs(tfState)
 	if err != nil {
 		return map[string]interface{}{}, err
 	}
 
+	outputs := map[string]interface{}{}
+	for name, output := range detailedOutputs {
+		outputs[name] = output.Value
+	}
+
+	return outputs, nil
+}
+
+func (e Executor) DetailedOutputs(tfState string) (map[string]Output, error) {
+	templateDir, err := tempDir("", "")
+	if err != nil {
+		return map[string]Output{}, err
+	}
+
 	err = writeFile(filepath.Join(templateDir, "terraform.tfstate"), []byte(tfState), os.ModePerm)
 	if err != nil {
-		return map[string]interface{}{}, err
+		return map[string]Output{}, err
 	}
 
 	args := []string{"output", "--json"}
 	buffer := bytes.NewBuffer([]byte{})
-	err = e.cmd.Run(buffer, templateDir, args, true)
+	err = e.cmd.Run(context.Background(), buffer, templateDir, args, true)
 	if err != nil {
-		return map[string]interface{}{}, err
+		return map[string]Output{}, err
 	}
 
 	var tfOutputs map[string]tfOutput
 	err = json.Unmarshal(buffer.Bytes(), &tfOutputs)
 	if err != nil {
-		return map[string]interface{}{}, err
+		return map[string]Output{}, err
 	}
 
-	outputs := map[string]interface{}{}
-
+	outputs := map[string]Output{}
 	for tfKey, tfValue := range tfOutputs {
-		outputs[tfKey] = tfValue.Value
+		outputs[tfKey] = Output{
+			Value:     tfValue.Value,
+			Sensitive: tfValue.Sensitive,
+		}
 	}
 
 	return outputs, nil
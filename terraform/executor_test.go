@@ -1,6 +1,7 @@
 package terraform_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -28,7 +30,7 @@ var _ = Describe("Executor", func() {
 	BeforeEach(func() {
 		cmd = &fakes.TerraformCmd{}
 
-		executor = terraform.NewExecutor(cmd, true)
+		executor = terraform.NewExecutor(cmd, true, 0)
 
 		var err error
 		tempDir, err = ioutil.TempDir("", "")
@@ -62,7 +64,7 @@ var _ = Describe("Executor", func() {
 
 	Describe("Apply", func() {
 		It("writes the terraform template to a file", func() {
-			_, err := executor.Apply(input, "some-template", "")
+			_, err := executor.Apply(context.Background(), input, "some-template", "")
 			Expect(err).NotTo(HaveOccurred())
 
 			fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
@@ -72,7 +74,7 @@ var _ = Describe("Executor", func() {
 		})
 
 		It("passes the correct args and dir to run command", func() {
-			_, err := executor.Apply(input, "some-template", "")
+			_, err := executor.Apply(context.Background(), input, "some-template", "")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
@@ -90,6 +92,52 @@ var _ = Describe("Executor", func() {
 			Expect(cmd.RunCall.Receives.Debug).To(BeTrue())
 		})
 
+		It("runs the command with a context that is cancelled along with the provided context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var runContextErr error
+			cmd.RunCall.Stub = func(io.Writer) {
+				cancel()
+				runContextErr = cmd.RunCall.Receives.Context.Err()
+			}
+
+			_, err := executor.Apply(ctx, input, "some-template", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runContextErr).To(Equal(context.Canceled))
+		})
+
+		Context("when a timeout is provided", func() {
+			BeforeEach(func() {
+				executor = terraform.NewExecutor(cmd, true, time.Millisecond)
+				cmd.RunCall.Stub = func(io.Writer) {
+					<-cmd.RunCall.Receives.Context.Done()
+				}
+				cmd.RunCall.Returns.Error = context.DeadlineExceeded
+			})
+
+			It("returns an error naming the terraform phase and the current tf state when the command times out", func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "terraform.tfstate"), []byte("some-partial-tf-state"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = executor.Apply(context.Background(), input, "some-template", "")
+				executorErr := err.(terraform.ExecutorError)
+				Expect(executorErr).To(MatchError("terraform timed out after 1ms"))
+
+				tfState, err := executorErr.TFState()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tfState).To(Equal("some-partial-tf-state"))
+			})
+
+			It("does not time out when the command finishes in time", func() {
+				cmd.RunCall.Stub = nil
+				cmd.RunCall.Returns.Error = nil
+
+				_, err := executor.Apply(context.Background(), input, "some-template", "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		It("reads and returns the terraform state written by the command", func() {
 			var actualFilename string
 
@@ -98,7 +146,7 @@ var _ = Describe("Executor", func() {
 				return []byte("some-terraform-state"), nil
 			})
 
-			terraformState, err := executor.Apply(input, "some-template", "")
+			terraformState, err := executor.Apply(context.Background(), input, "some-template", "")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(actualFilename).To(ContainSubstring("terraform.tfstate"))
@@ -107,7 +155,7 @@ var _ = Describe("Executor", func() {
 
 		Context("when previous tf state is blank", func() {
 			It("does not write the previous tf state file", func() {
-				_, err := executor.Apply(input, "some-template", "")
+				_, err := executor.Apply(context.Background(), input, "some-template", "")
 				Expect(err).NotTo(HaveOccurred())
 
 				_, err = os.Stat(filepath.Join(tempDir, "terraform.tfstate"))
@@ -117,7 +165,7 @@ var _ = Describe("Executor", func() {
 
 		Context("when previous tf state is not blank", func() {
 			It("writes the tf state to a file", func() {
-				_, err := executor.Apply(input, "some-template", "some-tf-state")
+				_, err := executor.Apply(context.Background(), input, "some-template", "some-tf-state")
 				Expect(err).NotTo(HaveOccurred())
 
 				fileContents, err := ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
@@ -132,7 +180,7 @@ var _ = Describe("Executor", func() {
 				terraform.SetTempDir(func(dir, prefix string) (string, error) {
 					return "", errors.New("failed to make temp dir")
 				})
-				_, err := executor.Apply(input, "some-template", "")
+				_, err := executor.Apply(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to make temp dir"))
 			})
 
@@ -145,7 +193,7 @@ var _ = Describe("Executor", func() {
 					return nil
 				})
 
-				_, err := executor.Apply(input, "some-template", "")
+				_, err := executor.Apply(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to write template file"))
 			})
 
@@ -158,7 +206,7 @@ var _ = Describe("Executor", func() {
 					return nil
 				})
 
-				_, err := executor.Apply(input, "some-template", "some-tf-state")
+				_, err := executor.Apply(context.Background(), input, "some-template", "some-tf-state")
 				Expect(err).To(MatchError("failed to write tf state file"))
 			})
 
@@ -168,7 +216,7 @@ var _ = Describe("Executor", func() {
 
 				cmd.RunCall.Returns.Error = errors.New("failed to run terraform command")
 
-				_, err = executor.Apply(input, "some-template", "")
+				_, err = executor.Apply(context.Background(), input, "some-template", "")
 				taErr := err.(terraform.ExecutorError)
 				Expect(taErr).To(MatchError("failed to run terraform command"))
 
@@ -182,13 +230,13 @@ var _ = Describe("Executor", func() {
 					return []byte{}, errors.New("failed to read tf state file")
 				})
 
-				_, err := executor.Apply(input, "some-template", "")
+				_, err := executor.Apply(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to read tf state file"))
 			})
 
 			Context("when --debug is false", func() {
 				BeforeEach(func() {
-					executor = terraform.NewExecutor(cmd, false)
+					executor = terraform.NewExecutor(cmd, false, 0)
 				})
 
 				It("returns an error and the current tf state when it fails to call terraform command run", func() {
@@ -197,7 +245,7 @@ var _ = Describe("Executor", func() {
 
 					cmd.RunCall.Returns.Error = errors.New("failed to run terraform command")
 
-					_, err = executor.Apply(input, "some-template", "")
+					_, err = executor.Apply(context.Background(), input, "some-template", "")
 					taErr := err.(terraform.ExecutorError)
 
 					tfState, err := taErr.TFState()
@@ -208,9 +256,134 @@ var _ = Describe("Executor", func() {
 		})
 	})
 
+	Describe("Plan", func() {
+		It("writes the template and tf state and runs a refreshing plan", func() {
+			cmd.RunCall.Stub = func(stdout io.Writer) {
+				fmt.Fprintf(stdout, "~ aws_instance.bosh")
+			}
+
+			plan, err := executor.Plan(context.Background(), map[string]string{"env_id": "some-env-id"}, "some-template", "some-tf-state")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).To(Equal("~ aws_instance.bosh"))
+
+			template, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(template)).To(Equal("some-template"))
+
+			tfState, err := ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(tfState)).To(Equal("some-tf-state"))
+
+			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"plan", "-refresh=true", "-input=false", "-no-color",
+				"-var", "env_id=some-env-id",
+			}))
+		})
+
+		It("captures the plan when debug is off", func() {
+			executor = terraform.NewExecutor(cmd, false, 0)
+			cmd.RunCall.Stub = func(stdout io.Writer) {
+				if cmd.RunCall.Receives.Debug {
+					fmt.Fprintf(stdout, "~ aws_instance.bosh")
+				}
+			}
+
+			plan, err := executor.Plan(context.Background(), map[string]string{"env_id": "some-env-id"}, "some-template", "some-tf-state")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).To(Equal("~ aws_instance.bosh"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when it fails to create a temp dir", func() {
+				terraform.SetTempDir(func(dir, prefix string) (string, error) {
+					return "", errors.New("failed to make temp dir")
+				})
+				_, err := executor.Plan(context.Background(), input, "some-template", "some-tf-state")
+				Expect(err).To(MatchError("failed to make temp dir"))
+			})
+
+			It("returns an error when the plan fails", func() {
+				cmd.RunCall.Returns.Error = errors.New("failed to plan")
+				_, err := executor.Plan(context.Background(), input, "some-template", "some-tf-state")
+				Expect(err).To(MatchError("failed to plan"))
+			})
+		})
+	})
+
+	Describe("Import", func() {
+		It("writes the template and tf state and imports the resource", func() {
+			terraform.SetReadFile(func(string) ([]byte, error) {
+				return []byte("some-new-tf-state"), nil
+			})
+
+			tfState, err := executor.Import(context.Background(), map[string]string{"env_id": "some-env-id"},
+				"some-template", "some-tf-state", "aws_vpc.vpc", "vpc-12345")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tfState).To(Equal("some-new-tf-state"))
+
+			template, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(template)).To(Equal("some-template"))
+
+			previousTFState, err := ioutil.ReadFile(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(previousTFState)).To(Equal("some-tf-state"))
+
+			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
+			Expect(cmd.RunCall.Receives.Args).To(Equal([]string{
+				"import", "-input=false",
+				"-var", "env_id=some-env-id",
+				"aws_vpc.vpc", "vpc-12345",
+			}))
+		})
+
+		It("does not write a tf state when there is none yet", func() {
+			_, err := executor.Import(context.Background(), input, "some-template", "", "aws_vpc.vpc", "vpc-12345")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = os.Stat(filepath.Join(tempDir, "terraform.tfstate"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when it fails to create a temp dir", func() {
+				terraform.SetTempDir(func(dir, prefix string) (string, error) {
+					return "", errors.New("failed to make temp dir")
+				})
+				_, err := executor.Import(context.Background(), input, "some-template", "", "aws_vpc.vpc", "vpc-12345")
+				Expect(err).To(MatchError("failed to make temp dir"))
+			})
+
+			It("returns an error and the current tf state when the import fails", func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "terraform.tfstate"), []byte("some-tf-state"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				cmd.RunCall.Returns.Error = errors.New("failed to import")
+
+				_, err = executor.Import(context.Background(), input, "some-template", "", "aws_vpc.vpc", "vpc-12345")
+				importErr := err.(terraform.ExecutorError)
+				Expect(importErr).To(MatchError("failed to import"))
+
+				tfState, err := importErr.TFState()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tfState).To(Equal("some-tf-state"))
+			})
+
+			It("returns an error when it fails to read the tf state file", func() {
+				terraform.SetReadFile(func(filename string) ([]byte, error) {
+					return []byte{}, errors.New("failed to read tf state file")
+				})
+
+				_, err := executor.Import(context.Background(), input, "some-template", "", "aws_vpc.vpc", "vpc-12345")
+				Expect(err).To(MatchError("failed to read tf state file"))
+			})
+		})
+	})
+
 	Describe("Destroy", func() {
 		It("writes the template and tf state to a temp dir", func() {
-			_, err := executor.Destroy(input, "some-template", "some-tf-state")
+			_, err := executor.Destroy(context.Background(), input, "some-template", "some-tf-state")
 			Expect(err).NotTo(HaveOccurred())
 
 			templateContents, err := ioutil.ReadFile(filepath.Join(tempDir, "template.tf"))
@@ -223,7 +396,7 @@ var _ = Describe("Executor", func() {
 		})
 
 		It("passes the correct args and dir to run command", func() {
-			_, err := executor.Destroy(input, "some-template", "some-tf-state")
+			_, err := executor.Destroy(context.Background(), input, "some-template", "some-tf-state")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(cmd.RunCall.Receives.WorkingDirectory).To(Equal(tempDir))
@@ -242,12 +415,58 @@ var _ = Describe("Executor", func() {
 			Expect(cmd.RunCall.Receives.Debug).To(BeTrue())
 		})
 
+		It("runs the command with a context that is cancelled along with the provided context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var runContextErr error
+			cmd.RunCall.Stub = func(io.Writer) {
+				cancel()
+				runContextErr = cmd.RunCall.Receives.Context.Err()
+			}
+
+			_, err := executor.Destroy(ctx, input, "some-template", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runContextErr).To(Equal(context.Canceled))
+		})
+
+		Context("when a timeout is provided", func() {
+			BeforeEach(func() {
+				executor = terraform.NewExecutor(cmd, true, time.Millisecond)
+				cmd.RunCall.Stub = func(io.Writer) {
+					<-cmd.RunCall.Receives.Context.Done()
+				}
+				cmd.RunCall.Returns.Error = context.DeadlineExceeded
+			})
+
+			It("returns an error naming the terraform phase and the current tf state when the command times out", func() {
+				err := ioutil.WriteFile(filepath.Join(tempDir, "terraform.tfstate"), []byte("some-partial-tf-state"), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = executor.Destroy(context.Background(), input, "some-template", "")
+				executorErr := err.(terraform.ExecutorError)
+				Expect(executorErr).To(MatchError("terraform timed out after 1ms"))
+
+				tfState, err := executorErr.TFState()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tfState).To(Equal("some-partial-tf-state"))
+			})
+
+			It("does not time out when the command finishes in time", func() {
+				cmd.RunCall.Stub = nil
+				cmd.RunCall.Returns.Error = nil
+
+				_, err := executor.Destroy(context.Background(), input, "some-template", "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		It("reads and returns the tf state", func() {
 			terraform.SetReadFile(func(filename string) ([]byte, error) {
 				return []byte{}, nil
 			})
 
-			tfState, err := executor.Destroy(input, "some-template", "some-tf-state")
+			tfState, err := executor.Destroy(context.Background(), input, "some-template", "some-tf-state")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(tfState).To(Equal(""))
@@ -259,7 +478,7 @@ var _ = Describe("Executor", func() {
 					return "", errors.New("failed to make temp dir")
 				})
 
-				_, err := executor.Destroy(input, "some-template", "")
+				_, err := executor.Destroy(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to make temp dir"))
 			})
 
@@ -272,7 +491,7 @@ var _ = Describe("Executor", func() {
 					return nil
 				})
 
-				_, err := executor.Destroy(input, "some-template", "")
+				_, err := executor.Destroy(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to write template file"))
 			})
 
@@ -285,7 +504,7 @@ var _ = Describe("Executor", func() {
 					return nil
 				})
 
-				_, err := executor.Destroy(input, "some-template", "some-tf-state")
+				_, err := executor.Destroy(context.Background(), input, "some-template", "some-tf-state")
 				Expect(err).To(MatchError("failed to write tf state file"))
 			})
 
@@ -294,7 +513,7 @@ var _ = Describe("Executor", func() {
 				Expect(err).NotTo(HaveOccurred())
 				cmd.RunCall.Returns.Error = errors.New("failed to run terraform command")
 
-				_, err = executor.Destroy(input, "some-template", "")
+				_, err = executor.Destroy(context.Background(), input, "some-template", "")
 				tdErr := err.(terraform.ExecutorError)
 				Expect(tdErr).To(MatchError("failed to run terraform command"))
 
@@ -308,13 +527,13 @@ var _ = Describe("Executor", func() {
 					return []byte{}, errors.New("failed to read tf state file")
 				})
 
-				_, err := executor.Destroy(input, "some-template", "")
+				_, err := executor.Destroy(context.Background(), input, "some-template", "")
 				Expect(err).To(MatchError("failed to read tf state file"))
 			})
 
 			Context("when --debug is false", func() {
 				BeforeEach(func() {
-					executor = terraform.NewExecutor(cmd, false)
+					executor = terraform.NewExecutor(cmd, false, 0)
 				})
 
 				It("returns an error and the current tf state when it fails to call terraform command run", func() {
@@ -323,7 +542,7 @@ var _ = Describe("Executor", func() {
 
 					cmd.RunCall.Returns.Error = errors.New("failed to run terraform command")
 
-					_, err = executor.Destroy(input, "some-template", "")
+					_, err = executor.Destroy(context.Background(), input, "some-template", "")
 					tdErr := err.(terraform.ExecutorError)
 
 					tfState, err := tdErr.TFState()
@@ -0,0 +1,537 @@
+variable "project_id" {
+	type = "string"
+}
+
+variable "region" {
+	type = "string"
+}
+
+variable "zone" {
+	type = "string"
+}
+
+variable "env_id" {
+	type = "string"
+}
+
+variable "credentials" {
+	type = "string"
+}
+
+provider "google" {
+	credentials = "${file("${var.credentials}")}"
+	project = "${var.project_id}"
+	region = "${var.region}"
+}
+
+output "external_ip" {
+    value = "${google_compute_address.bosh-external-ip.address}"
+}
+
+output "network_name" {
+    value = "${data.google_compute_network.bbl-network.name}"
+}
+
+output "subnetwork_name" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.name}"
+}
+
+output "bosh_open_tag_name" {
+    value = "${google_compute_firewall.bosh-open.name}"
+}
+
+output "internal_tag_name" {
+    value = "${google_compute_firewall.internal.name}"
+}
+
+output "director_address" {
+	value = "https://${google_compute_address.bosh-external-ip.address}:25555"
+}
+
+variable "existing_network" {
+	type = "string"
+}
+
+variable "existing_subnetwork" {
+	type = "string"
+}
+
+variable "network_project_id" {
+	type = "string"
+}
+
+data "google_compute_network" "bbl-network" {
+  name    = "${var.existing_network}"
+  project = "${var.network_project_id}"
+}
+
+data "google_compute_subnetwork" "bbl-subnet" {
+  name    = "${var.existing_subnetwork}"
+  project = "${var.network_project_id}"
+  region  = "${var.region}"
+}
+
+output "internal_cidr" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.ip_cidr_range}"
+}
+
+output "internal_gw" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.gateway_address}"
+}
+
+output "director_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 6)}"
+}
+
+output "jumpbox_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 5)}"
+}
+
+resource "google_compute_address" "bosh-external-ip" {
+  name = "${var.env_id}-bosh-external-ip"
+}
+
+resource "google_compute_firewall" "bosh-open" {
+  name    = "${var.env_id}-bosh-open"
+  project = "${var.network_project_id}"
+  network = "${data.google_compute_network.bbl-network.name}"
+
+  source_ranges = ["0.0.0.0/0"]
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    ports = ["22", "6868", "25555"]
+    protocol = "tcp"
+  }
+
+  target_tags = ["${var.env_id}-bosh-open"]
+}
+
+resource "google_compute_firewall" "internal" {
+  name    = "${var.env_id}-internal"
+  project = "${var.network_project_id}"
+  network = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    protocol = "tcp"
+  }
+
+  allow {
+    protocol = "udp"
+  }
+
+  source_tags = ["${var.env_id}-bosh-open","${var.env_id}-internal"]
+}
+
+output "jumpbox_url" {
+    value = "${google_compute_address.bosh-external-ip.address}:22"
+}
+
+variable "director_service_account_id" {
+  type = "string"
+}
+
+variable "director_roles" {
+  type = "list"
+  default = [
+    "roles/compute.instanceAdmin.v1",
+    "roles/compute.networkUser",
+    "roles/compute.storageAdmin",
+    "roles/storage.objectViewer",
+  ]
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh" {
+  count   = "${length(var.director_roles)}"
+  project = "${var.project_id}"
+  role    = "${element(var.director_roles, count.index)}"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_iam_member" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+  role               = "roles/iam.serviceAccountUser"
+  member             = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_key" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+}
+
+output "director_service_account_key" {
+  value     = "${base64decode(google_service_account_key.bosh.private_key)}"
+  sensitive = true
+}
+
+variable "ssl_certificate" {
+  type = "string"
+}
+
+variable "ssl_certificate_private_key" {
+  type = "string"
+}
+
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
+output "router_backend_service" {
+  value = "${google_compute_backend_service.router-lb-backend-service.name}"
+}
+
+output "router_lb_ip" {
+    value = "${google_compute_global_address.cf-address.address}"
+}
+
+output "ssh_proxy_lb_ip" {
+    value = "${google_compute_address.cf-ssh-proxy.address}"
+}
+
+output "tcp_router_lb_ip" {
+    value = "${google_compute_address.cf-tcp-router.address}"
+}
+
+output "ws_lb_ip" {
+    value = "${google_compute_address.cf-ws.address}"
+}
+
+resource "google_compute_firewall" "firewall-cf" {
+  name       = "${var.env_id}-cf-open"
+  project    = "${var.network_project_id}"
+  network    = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["80", "443"]
+  }
+
+  source_ranges = ["0.0.0.0/0"]
+
+  target_tags = ["${google_compute_backend_service.router-lb-backend-service.name}"]
+}
+
+resource "google_compute_global_address" "cf-address" {
+  name = "${var.env_id}-cf"
+}
+
+resource "google_compute_global_forwarding_rule" "cf-http-forwarding-rule" {
+  name       = "${var.env_id}-cf-http"
+  ip_address = "${google_compute_global_address.cf-address.address}"
+  target     = "${google_compute_target_http_proxy.cf-http-lb-proxy.self_link}"
+  port_range = "80"
+}
+
+resource "google_compute_global_forwarding_rule" "cf-https-forwarding-rule" {
+  name       = "${var.env_id}-cf-https"
+  ip_address = "${google_compute_global_address.cf-address.address}"
+  target     = "${google_compute_target_https_proxy.cf-https-lb-proxy.self_link}"
+  port_range = "443"
+}
+
+resource "google_compute_target_http_proxy" "cf-http-lb-proxy" {
+  name        = "${var.env_id}-http-proxy"
+  description = "really a load balancer but listed as an http proxy"
+  url_map     = "${google_compute_url_map.cf-https-lb-url-map.self_link}"
+}
+
+resource "google_compute_target_https_proxy" "cf-https-lb-proxy" {
+  name             = "${var.env_id}-https-proxy"
+  description      = "really a load balancer but listed as an https proxy"
+  url_map          = "${google_compute_url_map.cf-https-lb-url-map.self_link}"
+  ssl_certificates = ["${google_compute_ssl_certificate.cf-cert.self_link}"]
+}
+
+resource "google_compute_ssl_certificate" "cf-cert" {
+  name        = "${var.ssl_certificate_name}"
+  description = "user provided ssl private key / ssl certificate pair"
+  private_key = "${file(var.ssl_certificate_private_key)}"
+  certificate = "${file(var.ssl_certificate)}"
+  lifecycle {
+	create_before_destroy = true
+  }
+}
+
+resource "google_compute_url_map" "cf-https-lb-url-map" {
+  name = "${var.env_id}-cf-http"
+
+  default_service = "${google_compute_backend_service.router-lb-backend-service.self_link}"
+}
+
+resource "google_compute_http_health_check" "cf-public-health-check" {
+  name                = "${var.env_id}-cf"
+  port                = 8080
+  request_path        = "/health"
+}
+
+resource "google_compute_firewall" "cf-health-check" {
+  name       = "${var.env_id}-cf-health-check"
+  project    = "${var.network_project_id}"
+  network    = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["8080", "80"]
+  }
+
+  source_ranges = ["130.211.0.0/22"]
+  target_tags   = ["${google_compute_backend_service.router-lb-backend-service.name}"]
+}
+
+output "ssh_proxy_target_pool" {
+  value = "${google_compute_target_pool.cf-ssh-proxy.name}"
+}
+
+resource "google_compute_address" "cf-ssh-proxy" {
+  name = "${var.env_id}-cf-ssh-proxy"
+}
+
+resource "google_compute_firewall" "cf-ssh-proxy" {
+  name       = "${var.env_id}-cf-ssh-proxy-open"
+  project    = "${var.network_project_id}"
+  network    = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["2222"]
+  }
+
+  target_tags = ["${google_compute_target_pool.cf-ssh-proxy.name}"]
+}
+
+resource "google_compute_target_pool" "cf-ssh-proxy" {
+  name = "${var.env_id}-cf-ssh-proxy"
+
+  session_affinity = "NONE"
+}
+
+resource "google_compute_forwarding_rule" "cf-ssh-proxy" {
+  name        = "${var.env_id}-cf-ssh-proxy"
+  target      = "${google_compute_target_pool.cf-ssh-proxy.self_link}"
+  port_range  = "2222"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.cf-ssh-proxy.address}"
+}
+
+output "tcp_router_target_pool" {
+  value = "${google_compute_target_pool.cf-tcp-router.name}"
+}
+
+resource "google_compute_firewall" "cf-tcp-router" {
+  name       = "${var.env_id}-cf-tcp-router"
+  project    = "${var.network_project_id}"
+  network    = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "tcp"
+    ports    = ["1024-32768"]
+  }
+
+  target_tags = ["${google_compute_target_pool.cf-tcp-router.name}"]
+}
+
+resource "google_compute_address" "cf-tcp-router" {
+  name = "${var.env_id}-cf-tcp-router"
+}
+
+resource "google_compute_http_health_check" "cf-tcp-router" {
+  name                = "${var.env_id}-cf-tcp-router"
+  port                = 80
+  request_path        = "/health"
+}
+
+resource "google_compute_target_pool" "cf-tcp-router" {
+  name = "${var.env_id}-cf-tcp-router"
+
+  session_affinity = "NONE"
+
+  health_checks = [
+    "${google_compute_http_health_check.cf-tcp-router.name}",
+  ]
+}
+
+resource "google_compute_forwarding_rule" "cf-tcp-router" {
+  name        = "${var.env_id}-cf-tcp-router"
+  target      = "${google_compute_target_pool.cf-tcp-router.self_link}"
+  port_range  = "1024-32768"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.cf-tcp-router.address}"
+}
+
+output "ws_target_pool" {
+  value = "${google_compute_target_pool.cf-ws.name}"
+}
+
+resource "google_compute_address" "cf-ws" {
+  name = "${var.env_id}-cf-ws"
+}
+
+resource "google_compute_target_pool" "cf-ws" {
+  name = "${var.env_id}-cf-ws"
+
+  session_affinity = "NONE"
+
+  health_checks = ["${google_compute_http_health_check.cf-public-health-check.name}"]
+}
+
+resource "google_compute_forwarding_rule" "cf-ws-https" {
+  name        = "${var.env_id}-cf-ws-https"
+  target      = "${google_compute_target_pool.cf-ws.self_link}"
+  port_range  = "443"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.cf-ws.address}"
+}
+
+resource "google_compute_forwarding_rule" "cf-ws-http" {
+  name        = "${var.env_id}-cf-ws-http"
+  target      = "${google_compute_target_pool.cf-ws.self_link}"
+  port_range  = "80"
+  ip_protocol = "TCP"
+  ip_address  = "${google_compute_address.cf-ws.address}"
+}
+
+resource "google_compute_instance_group" "router-lb-0" {
+  name        = "${var.env_id}-router-lb-0-z1"
+  description = "terraform generated instance group that is multi-zone for https loadbalancing"
+  zone        = "z1"
+}
+
+resource "google_compute_instance_group" "router-lb-1" {
+  name        = "${var.env_id}-router-lb-1-z2"
+  description = "terraform generated instance group that is multi-zone for https loadbalancing"
+  zone        = "z2"
+}
+
+resource "google_compute_instance_group" "router-lb-2" {
+  name        = "${var.env_id}-router-lb-2-z3"
+  description = "terraform generated instance group that is multi-zone for https loadbalancing"
+  zone        = "z3"
+}
+
+resource "google_compute_backend_service" "router-lb-backend-service" {
+  name        = "${var.env_id}-router-lb"
+  port_name   = "http"
+  protocol    = "HTTP"
+  timeout_sec = 900
+  enable_cdn  = false
+
+  backend {
+    group = "${google_compute_instance_group.router-lb-0.self_link}"
+  }
+
+  backend {
+    group = "${google_compute_instance_group.router-lb-1.self_link}"
+  }
+
+  backend {
+    group = "${google_compute_instance_group.router-lb-2.self_link}"
+  }
+
+  health_checks = ["${google_compute_http_health_check.cf-public-health-check.self_link}"]
+}
+
+variable "system_domain" {
+  type = "string"
+}
+
+resource "google_dns_managed_zone" "env_dns_zone" {
+  name        = "${var.env_id}-zone"
+  dns_name    = "${var.system_domain}."
+  description = "DNS zone for the ${var.env_id} environment"
+}
+
+output "system_domain_dns_servers" {
+  value = "${google_dns_managed_zone.env_dns_zone.name_servers}"
+}
+
+resource "google_dns_record_set" "wildcard-dns" {
+  name       = "*.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_global_address.cf-address"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_global_address.cf-address.address}"]
+}
+
+resource "google_dns_record_set" "bosh-dns" {
+  name       = "bosh.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.bosh-external-ip"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.bosh-external-ip.address}"]
+}
+
+resource "google_dns_record_set" "cf-ssh-proxy" {
+  name       = "ssh.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.cf-ssh-proxy"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.cf-ssh-proxy.address}"]
+}
+
+resource "google_dns_record_set" "tcp-dns" {
+  name       = "tcp.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.cf-tcp-router"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.cf-tcp-router.address}"]
+}
+
+resource "google_dns_record_set" "doppler-dns" {
+  name       = "doppler.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.cf-ws"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.cf-ws.address}"]
+}
+
+resource "google_dns_record_set" "loggregator-dns" {
+  name       = "loggregator.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.cf-ws"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.cf-ws.address}"]
+}
+
+resource "google_dns_record_set" "wildcard-ws-dns" {
+  name       = "*.ws.${google_dns_managed_zone.env_dns_zone.dns_name}"
+  depends_on = ["google_compute_address.cf-ws"]
+  type       = "A"
+  ttl        = 300
+
+  managed_zone = "${google_dns_managed_zone.env_dns_zone.name}"
+
+  rrdatas = ["${google_compute_address.cf-ws.address}"]
+}
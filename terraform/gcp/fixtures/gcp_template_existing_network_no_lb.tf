@@ -0,0 +1,180 @@
+variable "project_id" {
+	type = "string"
+}
+
+variable "region" {
+	type = "string"
+}
+
+variable "zone" {
+	type = "string"
+}
+
+variable "env_id" {
+	type = "string"
+}
+
+variable "credentials" {
+	type = "string"
+}
+
+provider "google" {
+	credentials = "${file("${var.credentials}")}"
+	project = "${var.project_id}"
+	region = "${var.region}"
+}
+
+output "external_ip" {
+    value = "${google_compute_address.bosh-external-ip.address}"
+}
+
+output "network_name" {
+    value = "${data.google_compute_network.bbl-network.name}"
+}
+
+output "subnetwork_name" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.name}"
+}
+
+output "bosh_open_tag_name" {
+    value = "${google_compute_firewall.bosh-open.name}"
+}
+
+output "internal_tag_name" {
+    value = "${google_compute_firewall.internal.name}"
+}
+
+output "director_address" {
+	value = "https://${google_compute_address.bosh-external-ip.address}:25555"
+}
+
+variable "existing_network" {
+	type = "string"
+}
+
+variable "existing_subnetwork" {
+	type = "string"
+}
+
+variable "network_project_id" {
+	type = "string"
+}
+
+data "google_compute_network" "bbl-network" {
+  name    = "${var.existing_network}"
+  project = "${var.network_project_id}"
+}
+
+data "google_compute_subnetwork" "bbl-subnet" {
+  name    = "${var.existing_subnetwork}"
+  project = "${var.network_project_id}"
+  region  = "${var.region}"
+}
+
+output "internal_cidr" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.ip_cidr_range}"
+}
+
+output "internal_gw" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.gateway_address}"
+}
+
+output "director_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 6)}"
+}
+
+output "jumpbox_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 5)}"
+}
+
+resource "google_compute_address" "bosh-external-ip" {
+  name = "${var.env_id}-bosh-external-ip"
+}
+
+resource "google_compute_firewall" "bosh-open" {
+  name    = "${var.env_id}-bosh-open"
+  project = "${var.network_project_id}"
+  network = "${data.google_compute_network.bbl-network.name}"
+
+  source_ranges = ["0.0.0.0/0"]
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    ports = ["22", "6868", "25555"]
+    protocol = "tcp"
+  }
+
+  target_tags = ["${var.env_id}-bosh-open"]
+}
+
+resource "google_compute_firewall" "internal" {
+  name    = "${var.env_id}-internal"
+  project = "${var.network_project_id}"
+  network = "${data.google_compute_network.bbl-network.name}"
+
+  allow {
+    protocol = "icmp"
+  }
+
+  allow {
+    protocol = "tcp"
+  }
+
+  allow {
+    protocol = "udp"
+  }
+
+  source_tags = ["${var.env_id}-bosh-open","${var.env_id}-internal"]
+}
+
+output "jumpbox_url" {
+    value = "${google_compute_address.bosh-external-ip.address}:22"
+}
+
+variable "director_service_account_id" {
+  type = "string"
+}
+
+variable "director_roles" {
+  type = "list"
+  default = [
+    "roles/compute.instanceAdmin.v1",
+    "roles/compute.networkUser",
+    "roles/compute.storageAdmin",
+    "roles/storage.objectViewer",
+  ]
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh" {
+  count   = "${length(var.director_roles)}"
+  project = "${var.project_id}"
+  role    = "${element(var.director_roles, count.index)}"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_iam_member" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+  role               = "roles/iam.serviceAccountUser"
+  member             = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_key" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+}
+
+output "director_service_account_key" {
+  value     = "${base64decode(google_service_account_key.bosh.private_key)}"
+  sensitive = true
+}
@@ -102,3 +102,48 @@ resource "google_compute_firewall" "internal" {
 output "jumpbox_url" {
     value = "${google_compute_address.bosh-external-ip.address}:22"
 }
+
+variable "director_service_account_id" {
+  type = "string"
+}
+
+variable "director_roles" {
+  type = "list"
+  default = [
+    "roles/compute.instanceAdmin.v1",
+    "roles/compute.networkUser",
+    "roles/compute.storageAdmin",
+    "roles/storage.objectViewer",
+  ]
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh" {
+  count   = "${length(var.director_roles)}"
+  project = "${var.project_id}"
+  role    = "${element(var.director_roles, count.index)}"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_iam_member" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+  role               = "roles/iam.serviceAccountUser"
+  member             = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_key" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+}
+
+output "director_service_account_key" {
+  value     = "${base64decode(google_service_account_key.bosh.private_key)}"
+  sensitive = true
+}
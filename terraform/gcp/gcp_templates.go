@@ -27,7 +27,9 @@ provider "google" {
 }
 `
 
-const BOSHDirectorTemplate = `output "external_ip" {
+const BOSHDirectorTemplate = boshDirectorOutputsTemplate + bblNetworkTemplate + boshDirectorResourcesTemplate
+
+const boshDirectorOutputsTemplate = `output "external_ip" {
     value = "${google_compute_address.bosh-external-ip.address}"
 }
 
@@ -51,7 +53,9 @@ output "director_address" {
 	value = "https://${google_compute_address.bosh-external-ip.address}:25555"
 }
 
-resource "google_compute_network" "bbl-network" {
+`
+
+const bblNetworkTemplate = `resource "google_compute_network" "bbl-network" {
   name		 = "${var.env_id}-network"
 }
 
@@ -61,7 +65,52 @@ resource "google_compute_subnetwork" "bbl-subnet" {
   network		= "${google_compute_network.bbl-network.self_link}"
 }
 
-resource "google_compute_address" "bosh-external-ip" {
+`
+
+const ExistingNetworkTemplate = boshDirectorOutputsTemplate + existingNetworkTemplate + boshDirectorResourcesTemplate
+
+const existingNetworkTemplate = `variable "existing_network" {
+	type = "string"
+}
+
+variable "existing_subnetwork" {
+	type = "string"
+}
+
+variable "network_project_id" {
+	type = "string"
+}
+
+data "google_compute_network" "bbl-network" {
+  name    = "${var.existing_network}"
+  project = "${var.network_project_id}"
+}
+
+data "google_compute_subnetwork" "bbl-subnet" {
+  name    = "${var.existing_subnetwork}"
+  project = "${var.network_project_id}"
+  region  = "${var.region}"
+}
+
+output "internal_cidr" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.ip_cidr_range}"
+}
+
+output "internal_gw" {
+    value = "${data.google_compute_subnetwork.bbl-subnet.gateway_address}"
+}
+
+output "director_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 6)}"
+}
+
+output "jumpbox_internal_ip" {
+    value = "${cidrhost(data.google_compute_subnetwork.bbl-subnet.ip_cidr_range, 5)}"
+}
+
+`
+
+const boshDirectorResourcesTemplate = `resource "google_compute_address" "bosh-external-ip" {
   name = "${var.env_id}-bosh-external-ip"
 }
 
@@ -107,6 +156,67 @@ output "jumpbox_url" {
 }
 `
 
+// DirectorServiceAccountTemplate creates the service account that the director
+// uses, with only the roles the CPI needs. The director may act as its own
+// service account, but not as the other service accounts of the project.
+const DirectorServiceAccountTemplate = `variable "director_service_account_id" {
+  type = "string"
+}
+
+variable "director_roles" {
+  type = "list"
+  default = [
+    "roles/compute.instanceAdmin.v1",
+    "roles/compute.networkUser",
+    "roles/compute.storageAdmin",
+    "roles/storage.objectViewer",
+  ]
+}
+
+resource "google_service_account" "bosh" {
+  account_id   = "${var.director_service_account_id}"
+  display_name = "${var.env_id} bosh director"
+}
+
+resource "google_project_iam_member" "bosh" {
+  count   = "${length(var.director_roles)}"
+  project = "${var.project_id}"
+  role    = "${element(var.director_roles, count.index)}"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+resource "google_service_account_iam_member" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+  role               = "roles/iam.serviceAccountUser"
+  member             = "serviceAccount:${google_service_account.bosh.email}"
+}
+
+output "director_service_account_email" {
+  value = "${google_service_account.bosh.email}"
+}
+`
+
+// SharedVPCNetworkUserTemplate lets the director service account use the
+// subnetworks of a shared vpc, which belong to the host project.
+const SharedVPCNetworkUserTemplate = `resource "google_project_iam_member" "bosh_network_user" {
+  project = "${var.network_project_id}"
+  role    = "roles/compute.networkUser"
+  member  = "serviceAccount:${google_service_account.bosh.email}"
+}
+`
+
+// DirectorServiceAccountKeyTemplate creates the first key of the director
+// service account and outputs it for the director manifest.
+const DirectorServiceAccountKeyTemplate = `resource "google_service_account_key" "bosh" {
+  service_account_id = "${google_service_account.bosh.name}"
+}
+
+output "director_service_account_key" {
+  value     = "${base64decode(google_service_account_key.bosh.private_key)}"
+  sensitive = true
+}
+`
+
 const ConcourseLBTemplate = `output "concourse_target_pool" {
 	value = "${google_compute_target_pool.target-pool.name}"
 }
@@ -162,6 +272,10 @@ variable "ssl_certificate_private_key" {
   type = "string"
 }
 
+variable "ssl_certificate_name" {
+  type = "string"
+}
+
 output "router_backend_service" {
   value = "${google_compute_backend_service.router-lb-backend-service.name}"
 }
@@ -229,7 +343,7 @@ resource "google_compute_target_https_proxy" "cf-https-lb-proxy" {
 }
 
 resource "google_compute_ssl_certificate" "cf-cert" {
-  name_prefix = "${var.env_id}"
+  name        = "${var.ssl_certificate_name}"
   description = "user provided ssl private key / ssl certificate pair"
   private_key = "${file(var.ssl_certificate_private_key)}"
   certificate = "${file(var.ssl_certificate)}"
@@ -1,16 +1,26 @@
 package gcp
 
 import (
+	"crypto/sha1"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/ssl"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 )
 
 var tempDir func(dir, prefix string) (string, error) = ioutil.TempDir
 var writeFile func(file string, data []byte, perm os.FileMode) error = ioutil.WriteFile
 
+const (
+	resourceNameCharLimit        = 63
+	certificateFingerprintLength = 16
+	serviceAccountIDCharLimit    = 30
+)
+
 type InputGenerator struct {
 }
 
@@ -31,12 +41,22 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 	}
 
 	input := map[string]string{
-		"env_id":        state.EnvID,
-		"project_id":    state.GCP.ProjectID,
-		"region":        state.GCP.Region,
-		"zone":          state.GCP.Zone,
-		"credentials":   credentialsPath,
-		"system_domain": state.LB.Domain,
+		"env_id":                      state.EnvID,
+		"project_id":                  state.GCP.ProjectID,
+		"region":                      state.GCP.Region,
+		"zone":                        state.GCP.Zone,
+		"credentials":                 credentialsPath,
+		"system_domain":               state.LB.Domain,
+		"director_service_account_id": directorServiceAccountID(state.EnvID),
+	}
+
+	if state.GCP.ExistingNetwork != "" {
+		input["existing_network"] = state.GCP.ExistingNetwork
+		input["existing_subnetwork"] = state.GCP.ExistingSubnetwork
+		input["network_project_id"] = state.GCP.ProjectID
+		if state.GCP.NetworkProjectID != "" {
+			input["network_project_id"] = state.GCP.NetworkProjectID
+		}
 	}
 
 	if state.LB.Cert != "" && state.LB.Key != "" {
@@ -53,7 +73,35 @@ func (i InputGenerator) Generate(state storage.State) (map[string]string, error)
 			return map[string]string{}, err
 		}
 		input["ssl_certificate_private_key"] = keyPath
+		input["ssl_certificate_name"] = certificateName(state.EnvID, state.LB.Cert)
 	}
 
 	return input, nil
 }
+
+// certificateName names the certificate after its fingerprint so that a new
+// certificate is created alongside the old one, and swapped in, when it is
+// rotated.
+func certificateName(envID, certificate string) string {
+	prefixLimit := resourceNameCharLimit - certificateFingerprintLength - 1
+	if len(envID) > prefixLimit {
+		envID = envID[:prefixLimit]
+	}
+
+	return fmt.Sprintf("%s-%s", envID, ssl.Fingerprint(certificate)[:certificateFingerprintLength])
+}
+
+// directorServiceAccountID keeps the id within the gcp limit, adding a hash of
+// the env id when it is shortened so that similar env ids do not collide.
+func directorServiceAccountID(envID string) string {
+	const suffix = "-bosh"
+
+	prefix := strings.ToLower(envID)
+	if len(prefix)+len(suffix) > serviceAccountIDCharLimit {
+		hash := fmt.Sprintf("%x", sha1.Sum([]byte(envID)))
+		prefixLimit := serviceAccountIDCharLimit - len(suffix) - 9
+		prefix = fmt.Sprintf("%s-%s", strings.TrimRight(prefix[:prefixLimit], "-"), hash[:8])
+	}
+
+	return prefix + suffix
+}
@@ -59,12 +59,13 @@ var _ = Describe("InputGenerator", func() {
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(inputs).To(Equal(map[string]string{
-			"env_id":        state.EnvID,
-			"project_id":    state.GCP.ProjectID,
-			"region":        state.GCP.Region,
-			"zone":          state.GCP.Zone,
-			"credentials":   filepath.Join(tempDir, "credentials.json"),
-			"system_domain": state.LB.Domain,
+			"env_id":                      state.EnvID,
+			"project_id":                  state.GCP.ProjectID,
+			"region":                      state.GCP.Region,
+			"zone":                        state.GCP.Zone,
+			"credentials":                 filepath.Join(tempDir, "credentials.json"),
+			"system_domain":               state.LB.Domain,
+			"director_service_account_id": "some-env-id-bosh",
 		}))
 
 		credentials, err := ioutil.ReadFile(inputs["credentials"])
@@ -72,6 +73,33 @@ var _ = Describe("InputGenerator", func() {
 		Expect(string(credentials)).To(Equal("some-service-account-key"))
 	})
 
+	Context("when the environment uses an existing network", func() {
+		BeforeEach(func() {
+			state.GCP.ExistingNetwork = "some-network"
+			state.GCP.ExistingSubnetwork = "some-subnetwork"
+		})
+
+		It("returns the network, subnetwork and the project they belong to", func() {
+			inputs, err := inputGenerator.Generate(state)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(inputs["existing_network"]).To(Equal("some-network"))
+			Expect(inputs["existing_subnetwork"]).To(Equal("some-subnetwork"))
+			Expect(inputs["network_project_id"]).To(Equal("some-project-id"))
+		})
+
+		Context("when the network belongs to a shared vpc host project", func() {
+			It("returns the host project", func() {
+				state.GCP.NetworkProjectID = "some-host-project-id"
+
+				inputs, err := inputGenerator.Generate(state)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(inputs["network_project_id"]).To(Equal("some-host-project-id"))
+			})
+		})
+	})
+
 	It("returns a map containing cert and key variables when cert/key are provided", func() {
 		state.LB.Cert = "some-cert"
 		state.LB.Key = "some-key"
@@ -87,7 +115,9 @@ var _ = Describe("InputGenerator", func() {
 			"credentials":                 filepath.Join(tempDir, "credentials.json"),
 			"ssl_certificate":             filepath.Join(tempDir, "cert"),
 			"ssl_certificate_private_key": filepath.Join(tempDir, "key"),
+			"ssl_certificate_name":        "some-env-id-a5b185c5b1121d80",
 			"system_domain":               state.LB.Domain,
+			"director_service_account_id": "some-env-id-bosh",
 		}))
 
 		sslCertificate, err := ioutil.ReadFile(inputs["ssl_certificate"])
@@ -99,6 +129,26 @@ var _ = Describe("InputGenerator", func() {
 		Expect(string(sslCertificatePrivateKey)).To(Equal("some-key"))
 	})
 
+	It("keeps the certificate name within the gcp limit for long env ids", func() {
+		state.EnvID = strings.Repeat("a", 70)
+		state.LB.Cert = "some-cert"
+		state.LB.Key = "some-key"
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs["ssl_certificate_name"]).To(Equal(strings.Repeat("a", 46) + "-a5b185c5b1121d80"))
+	})
+
+	It("keeps the id of the director service account within the gcp limit for long env ids", func() {
+		state.EnvID = "bbl-env-superior-2017-09-27t16-02z"
+
+		inputs, err := inputGenerator.Generate(state)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputs["director_service_account_id"]).To(Equal("bbl-env-superior-50e8ed9a-bosh"))
+	})
+
 	Context("failure cases", func() {
 		It("returns an error if temp dir cannot be created", func() {
 			gcp.SetTempDir(func(dir, prefix string) (string, error) {
@@ -8,6 +8,7 @@ import (
 
 type executor interface {
 	Output(string, string) (string, error)
+	Outputs(string) (map[string]interface{}, error)
 }
 
 type OutputGenerator struct {
@@ -68,6 +69,47 @@ func (g OutputGenerator) Generate(bblState storage.State) (map[string]interface{
 	}
 	outputs["jumpbox_url"] = jumpboxURL
 
+	if bblState.GCP.ExistingNetwork != "" {
+		internalCIDR, err := g.executor.Output(bblState.TFState, "internal_cidr")
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		outputs["internal_cidr"] = internalCIDR
+
+		internalGateway, err := g.executor.Output(bblState.TFState, "internal_gw")
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		outputs["internal_gw"] = internalGateway
+
+		directorInternalIP, err := g.executor.Output(bblState.TFState, "director_internal_ip")
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		outputs["internal_ip"] = directorInternalIP
+
+		jumpboxInternalIP, err := g.executor.Output(bblState.TFState, "jumpbox_internal_ip")
+		if err != nil {
+			return map[string]interface{}{}, err
+		}
+		outputs["jumpbox_internal_ip"] = jumpboxInternalIP
+	}
+
+	// The tf state of an environment that has not been applied since bbl gave
+	// the director a service account of its own has no outputs for it.
+	tfOutputs, err := g.executor.Outputs(bblState.TFState)
+	if err != nil {
+		return map[string]interface{}{}, err
+	}
+
+	if serviceAccountEmail, ok := tfOutputs["director_service_account_email"]; ok {
+		outputs["director_service_account_email"] = serviceAccountEmail
+	}
+
+	if serviceAccountKey, ok := tfOutputs["director_service_account_key"]; ok && bblState.GCP.DirectorServiceAccountKey == "" {
+		outputs["director_service_account_key"] = serviceAccountKey
+	}
+
 	var (
 		routerBackendService      string
 		sshProxyTargetPool        string
@@ -21,9 +21,112 @@ var _ = Describe("OutputGenerator", func() {
 
 	BeforeEach(func() {
 		executor = &fakes.TerraformExecutor{}
+		executor.OutputsCall.Returns.Outputs = map[string]interface{}{
+			"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+			"director_service_account_key":   "some-director-service-account-key",
+		}
 		outputGenerator = gcp.NewOutputGenerator(executor)
 	})
 
+	Context("when the environment uses an existing network", func() {
+		BeforeEach(func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				switch output {
+				case "internal_cidr":
+					return "172.16.0.0/20", nil
+				case "internal_gw":
+					return "172.16.0.1", nil
+				case "director_internal_ip":
+					return "172.16.0.6", nil
+				case "jumpbox_internal_ip":
+					return "172.16.0.5", nil
+				default:
+					return fmt.Sprintf("some-%s", output), nil
+				}
+			}
+		})
+
+		It("returns the network of the existing subnetwork", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS: "gcp",
+				GCP: storage.GCP{
+					ExistingNetwork:    "some-network",
+					ExistingSubnetwork: "some-subnetwork",
+				},
+				TFState: "some-tf-state",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).To(HaveKeyWithValue("internal_cidr", "172.16.0.0/20"))
+			Expect(outputs).To(HaveKeyWithValue("internal_gw", "172.16.0.1"))
+			Expect(outputs).To(HaveKeyWithValue("internal_ip", "172.16.0.6"))
+			Expect(outputs).To(HaveKeyWithValue("jumpbox_internal_ip", "172.16.0.5"))
+		})
+
+		It("returns an error when an output cannot be read", func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				if output == "director_internal_ip" {
+					return "", errors.New("failed to get output")
+				}
+				return "", nil
+			}
+
+			_, err := outputGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					ExistingNetwork: "some-network",
+				},
+				TFState: "some-tf-state",
+			})
+			Expect(err).To(MatchError("failed to get output"))
+		})
+	})
+
+	Context("director service account", func() {
+		BeforeEach(func() {
+			executor.OutputCall.Stub = func(output string) (string, error) {
+				return fmt.Sprintf("some-%s", output), nil
+			}
+		})
+
+		It("does not read the key that terraform created once bbl manages the keys", func() {
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS: "gcp",
+				GCP: storage.GCP{
+					DirectorServiceAccountKey: "some-key-file",
+				},
+				TFState: "some-tf-state",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.OutputsCall.Receives.TFState).To(Equal("some-tf-state"))
+			Expect(outputs).To(HaveKeyWithValue("director_service_account_email", "some-director@some-project.iam.gserviceaccount.com"))
+			Expect(outputs).NotTo(HaveKey("director_service_account_key"))
+		})
+
+		It("skips the outputs that the tf state does not have yet", func() {
+			executor.OutputsCall.Returns.Outputs = map[string]interface{}{}
+
+			outputs, err := outputGenerator.Generate(storage.State{
+				IAAS:    "gcp",
+				TFState: "some-tf-state",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputs).NotTo(HaveKey("director_service_account_email"))
+			Expect(outputs).NotTo(HaveKey("director_service_account_key"))
+			Expect(outputs).To(HaveKeyWithValue("external_ip", "some-external_ip"))
+		})
+
+		It("returns an error when the outputs cannot be read", func() {
+			executor.OutputsCall.Returns.Error = errors.New("failed to get outputs")
+
+			_, err := outputGenerator.Generate(storage.State{
+				TFState: "some-tf-state",
+			})
+			Expect(err).To(MatchError("failed to get outputs"))
+		})
+	})
+
 	Context("when no lb exists", func() {
 		BeforeEach(func() {
 			executor.OutputCall.Stub = func(output string) (string, error) {
@@ -67,13 +170,15 @@ var _ = Describe("OutputGenerator", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(outputs).To(Equal(map[string]interface{}{
-				"external_ip":        "some-external-ip",
-				"network_name":       "some-network-name",
-				"subnetwork_name":    "some-subnetwork-name",
-				"bosh_open_tag_name": "some-bosh-open-tag-name",
-				"internal_tag_name":  "some-internal-tag-name",
-				"director_address":   "some-director-address",
-				"jumpbox_url":        "some-jumpbox-url",
+				"external_ip":                    "some-external-ip",
+				"network_name":                   "some-network-name",
+				"subnetwork_name":                "some-subnetwork-name",
+				"bosh_open_tag_name":             "some-bosh-open-tag-name",
+				"internal_tag_name":              "some-internal-tag-name",
+				"director_address":               "some-director-address",
+				"jumpbox_url":                    "some-jumpbox-url",
+				"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+				"director_service_account_key":   "some-director-service-account-key",
 			}))
 		})
 	})
@@ -138,21 +243,23 @@ var _ = Describe("OutputGenerator", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(outputs).To(Equal(map[string]interface{}{
-					"external_ip":            "some-external-ip",
-					"network_name":           "some-network-name",
-					"subnetwork_name":        "some-subnetwork-name",
-					"bosh_open_tag_name":     "some-bosh-open-tag-name",
-					"internal_tag_name":      "some-internal-tag-name",
-					"director_address":       "some-director-address",
-					"jumpbox_url":            "some-jumpbox-url",
-					"router_backend_service": "some-router-backend-service",
-					"ssh_proxy_target_pool":  "some-ssh-proxy-target-pool",
-					"tcp_router_target_pool": "some-tcp-router-target-pool",
-					"ws_target_pool":         "some-ws-target-pool",
-					"router_lb_ip":           "some-router-lb-ip",
-					"ssh_proxy_lb_ip":        "some-ssh-proxy-lb-ip",
-					"tcp_router_lb_ip":       "some-tcp-router-lb-ip",
-					"ws_lb_ip":               "some-ws-lb-ip",
+					"external_ip":                    "some-external-ip",
+					"network_name":                   "some-network-name",
+					"subnetwork_name":                "some-subnetwork-name",
+					"bosh_open_tag_name":             "some-bosh-open-tag-name",
+					"internal_tag_name":              "some-internal-tag-name",
+					"director_address":               "some-director-address",
+					"jumpbox_url":                    "some-jumpbox-url",
+					"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+					"director_service_account_key":   "some-director-service-account-key",
+					"router_backend_service":         "some-router-backend-service",
+					"ssh_proxy_target_pool":          "some-ssh-proxy-target-pool",
+					"tcp_router_target_pool":         "some-tcp-router-target-pool",
+					"ws_target_pool":                 "some-ws-target-pool",
+					"router_lb_ip":                   "some-router-lb-ip",
+					"ssh_proxy_lb_ip":                "some-ssh-proxy-lb-ip",
+					"tcp_router_lb_ip":               "some-tcp-router-lb-ip",
+					"ws_lb_ip":                       "some-ws-lb-ip",
 				}))
 			})
 		})
@@ -218,22 +325,24 @@ var _ = Describe("OutputGenerator", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(outputs).To(Equal(map[string]interface{}{
-					"external_ip":               "some-external-ip",
-					"network_name":              "some-network-name",
-					"subnetwork_name":           "some-subnetwork-name",
-					"bosh_open_tag_name":        "some-bosh-open-tag-name",
-					"internal_tag_name":         "some-internal-tag-name",
-					"director_address":          "some-director-address",
-					"jumpbox_url":               "some-jumpbox-url",
-					"router_backend_service":    "some-router-backend-service",
-					"ssh_proxy_target_pool":     "some-ssh-proxy-target-pool",
-					"tcp_router_target_pool":    "some-tcp-router-target-pool",
-					"ws_target_pool":            "some-ws-target-pool",
-					"router_lb_ip":              "some-router-lb-ip",
-					"ssh_proxy_lb_ip":           "some-ssh-proxy-lb-ip",
-					"tcp_router_lb_ip":          "some-tcp-router-lb-ip",
-					"ws_lb_ip":                  "some-ws-lb-ip",
-					"system_domain_dns_servers": []string{"some-name-server-1", "some-name-server-2"},
+					"external_ip":                    "some-external-ip",
+					"network_name":                   "some-network-name",
+					"subnetwork_name":                "some-subnetwork-name",
+					"bosh_open_tag_name":             "some-bosh-open-tag-name",
+					"internal_tag_name":              "some-internal-tag-name",
+					"director_address":               "some-director-address",
+					"jumpbox_url":                    "some-jumpbox-url",
+					"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+					"director_service_account_key":   "some-director-service-account-key",
+					"router_backend_service":         "some-router-backend-service",
+					"ssh_proxy_target_pool":          "some-ssh-proxy-target-pool",
+					"tcp_router_target_pool":         "some-tcp-router-target-pool",
+					"ws_target_pool":                 "some-ws-target-pool",
+					"router_lb_ip":                   "some-router-lb-ip",
+					"ssh_proxy_lb_ip":                "some-ssh-proxy-lb-ip",
+					"tcp_router_lb_ip":               "some-tcp-router-lb-ip",
+					"ws_lb_ip":                       "some-ws-lb-ip",
+					"system_domain_dns_servers":      []string{"some-name-server-1", "some-name-server-2"},
 				}))
 			})
 		})
@@ -286,15 +395,17 @@ var _ = Describe("OutputGenerator", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(outputs).To(Equal(map[string]interface{}{
-				"external_ip":           "some-external-ip",
-				"network_name":          "some-network-name",
-				"subnetwork_name":       "some-subnetwork-name",
-				"bosh_open_tag_name":    "some-bosh-open-tag-name",
-				"internal_tag_name":     "some-internal-tag-name",
-				"director_address":      "some-director-address",
-				"jumpbox_url":           "some-jumpbox-url",
-				"concourse_target_pool": "some-concourse-target-pool",
-				"concourse_lb_ip":       "some-concourse-lb-ip",
+				"external_ip":                    "some-external-ip",
+				"network_name":                   "some-network-name",
+				"subnetwork_name":                "some-subnetwork-name",
+				"bosh_open_tag_name":             "some-bosh-open-tag-name",
+				"internal_tag_name":              "some-internal-tag-name",
+				"director_address":               "some-director-address",
+				"jumpbox_url":                    "some-jumpbox-url",
+				"director_service_account_email": "some-director@some-project.iam.gserviceaccount.com",
+				"director_service_account_key":   "some-director-service-account-key",
+				"concourse_target_pool":          "some-concourse-target-pool",
+				"concourse_lb_ip":                "some-concourse-lb-ip",
 			}))
 		})
 	})
@@ -2,6 +2,7 @@ package gcp
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/cloudfoundry/bosh-bootloader/storage"
@@ -15,6 +16,16 @@ type zones interface {
 	Get(region string) []string
 }
 
+var (
+	networkDependsOnRegex = regexp.MustCompile(`(?m)^\s*depends_on = \["google_compute_network\.bbl-network"\]\n`)
+	firewallNetworkRegex  = regexp.MustCompile(`(?m)^(\s*)network(\s*)= "\$\{google_compute_network\.bbl-network\.name\}"`)
+
+	existingNetworkReplacer = strings.NewReplacer(
+		"${google_compute_network.bbl-network.", "${data.google_compute_network.bbl-network.",
+		"${google_compute_subnetwork.bbl-subnet.", "${data.google_compute_subnetwork.bbl-subnet.",
+	)
+)
+
 const backendBase = `resource "google_compute_backend_service" "router-lb-backend-service" {
   name        = "${var.env_id}-router-lb"
   port_name   = "http"
@@ -33,7 +44,20 @@ func NewTemplateGenerator(zones zones) TemplateGenerator {
 }
 
 func (t TemplateGenerator) Generate(state storage.State) string {
-	template := strings.Join([]string{VarsTemplate, BOSHDirectorTemplate}, "\n")
+	directorTemplate := BOSHDirectorTemplate
+	if state.GCP.ExistingNetwork != "" {
+		directorTemplate = ExistingNetworkTemplate
+	}
+
+	template := strings.Join([]string{VarsTemplate, directorTemplate, DirectorServiceAccountTemplate}, "\n")
+
+	if state.GCP.ExistingNetwork != "" && state.GCP.NetworkProjectID != "" && state.GCP.NetworkProjectID != state.GCP.ProjectID {
+		template = strings.Join([]string{template, SharedVPCNetworkUserTemplate}, "\n")
+	}
+
+	if state.GCP.DirectorServiceAccountKey == "" {
+		template = strings.Join([]string{template, DirectorServiceAccountKeyTemplate}, "\n")
+	}
 
 	switch state.LB.Type {
 	case "concourse":
@@ -48,9 +72,24 @@ func (t TemplateGenerator) Generate(state storage.State) string {
 			template = strings.Join([]string{template, CFDNSTemplate}, "\n")
 		}
 	}
+
+	if state.GCP.ExistingNetwork != "" {
+		template = useExistingNetwork(template)
+	}
+
 	return template
 }
 
+// useExistingNetwork points the firewall rules at the existing network, which
+// may live in a shared vpc host project, instead of the network bbl creates.
+func useExistingNetwork(template string) string {
+	template = networkDependsOnRegex.ReplaceAllString(template, "")
+	template = firewallNetworkRegex.ReplaceAllString(template,
+		"${1}project${2}= \"$${var.network_project_id}\"\n${1}network${2}= \"$${data.google_compute_network.bbl-network.name}\"")
+
+	return existingNetworkReplacer.Replace(template)
+}
+
 func (t TemplateGenerator) GenerateBackendService(region string) string {
 	zones := t.zones.Get(region)
 	var backends string
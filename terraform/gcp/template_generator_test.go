@@ -49,6 +49,80 @@ var _ = Describe("TemplateGenerator", func() {
 			Entry("when a cf lb type is provided", "fixtures/gcp_template_cf_lb.tf", "some-region", "cf", ""),
 			Entry("when a cf lb type is provided with a domain", "fixtures/gcp_template_cf_lb_dns.tf", "some-region", "cf", "some-domain"),
 		)
+
+		DescribeTable("generates a terraform template for an existing network", func(fixtureFilename, lbType, domain string) {
+			expectedTemplate, err := ioutil.ReadFile(fixtureFilename)
+			Expect(err).NotTo(HaveOccurred())
+
+			template := templateGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					Region:             "some-region",
+					ExistingNetwork:    "some-network",
+					ExistingSubnetwork: "some-subnetwork",
+				},
+				LB: storage.LB{
+					Type:   lbType,
+					Domain: domain,
+				},
+			})
+			Expect(template).To(Equal(string(expectedTemplate)))
+			Expect(template).NotTo(ContainSubstring(`resource "google_compute_network"`))
+			Expect(template).NotTo(ContainSubstring(`resource "google_compute_subnetwork"`))
+		},
+			Entry("when no lb type is provided", "fixtures/gcp_template_existing_network_no_lb.tf", "", ""),
+			Entry("when a cf lb type is provided with a domain", "fixtures/gcp_template_existing_network_cf_lb_dns.tf", "cf", "some-domain"),
+		)
+
+		Context("when the existing network belongs to a shared vpc host project", func() {
+			var state storage.State
+
+			BeforeEach(func() {
+				state = storage.State{
+					GCP: storage.GCP{
+						Region:             "some-region",
+						ProjectID:          "some-project-id",
+						ExistingNetwork:    "some-network",
+						ExistingSubnetwork: "some-subnetwork",
+						NetworkProjectID:   "some-host-project-id",
+					},
+				}
+			})
+
+			It("makes the director service account a network user of the host project", func() {
+				template := templateGenerator.Generate(state)
+				Expect(template).To(ContainSubstring(gcp.SharedVPCNetworkUserTemplate))
+			})
+
+			It("does not bind the role twice when the network belongs to the same project", func() {
+				state.GCP.NetworkProjectID = "some-project-id"
+
+				template := templateGenerator.Generate(state)
+				Expect(template).NotTo(ContainSubstring(gcp.SharedVPCNetworkUserTemplate))
+			})
+		})
+
+		It("lets the director act as its own service account only", func() {
+			template := templateGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					Region: "some-region",
+				},
+			})
+
+			Expect(template).To(ContainSubstring(`resource "google_service_account_iam_member" "bosh"`))
+			Expect(template).NotTo(ContainSubstring(`"roles/iam.serviceAccountUser",`))
+		})
+
+		It("leaves out the key of the director service account once bbl manages its keys", func() {
+			template := templateGenerator.Generate(storage.State{
+				GCP: storage.GCP{
+					Region:                    "some-region",
+					DirectorServiceAccountKey: "some-key-file",
+				},
+			})
+
+			Expect(template).To(ContainSubstring(gcp.DirectorServiceAccountTemplate))
+			Expect(template).NotTo(ContainSubstring(gcp.DirectorServiceAccountKeyTemplate))
+		})
 	})
 
 	Describe("GenerateBackendService", func() {
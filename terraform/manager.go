@@ -2,8 +2,10 @@ package terraform
 
 import (
 	"bytes"
+	"context"
 	"errors"
 
+	"github.com/cloudfoundry/bosh-bootloader/drift"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/coreos/go-semver/semver"
 )
@@ -19,8 +21,21 @@ type Manager struct {
 
 type executor interface {
 	Version() (string, error)
-	Destroy(inputs map[string]string, terraformTemplate, tfState string) (string, error)
-	Apply(inputs map[string]string, terraformTemplate, tfState string) (string, error)
+	Destroy(ctx context.Context, inputs map[string]string, terraformTemplate, tfState string) (string, error)
+	Apply(ctx context.Context, inputs map[string]string, terraformTemplate, tfState string) (string, error)
+	Plan(ctx context.Context, inputs map[string]string, terraformTemplate, tfState string) (string, error)
+	Import(ctx context.Context, inputs map[string]string, terraformTemplate, tfState, address, id string) (string, error)
+	Outputs(tfState string) (map[string]interface{}, error)
+	DetailedOutputs(tfState string) (map[string]Output, error)
+}
+
+type Import struct {
+	Address string
+	ID      string
+
+	// Attributes are written to the tf state as they are, instead of running
+	// terraform import, for resources that terraform cannot import.
+	Attributes map[string]string
 }
 
 type templateGenerator interface {
@@ -97,7 +112,7 @@ func (m Manager) ValidateVersion() error {
 	return nil
 }
 
-func (m Manager) Apply(bblState storage.State) (storage.State, error) {
+func (m Manager) Apply(ctx context.Context, bblState storage.State) (storage.State, error) {
 	m.logger.Step("generating terraform template")
 	template := m.templateGenerator.Generate(bblState)
 
@@ -107,6 +122,7 @@ func (m Manager) Apply(bblState storage.State) (storage.State, error) {
 	}
 
 	tfState, err := m.executor.Apply(
+		ctx,
 		input,
 		template,
 		bblState.TFState)
@@ -125,7 +141,7 @@ func (m Manager) Apply(bblState storage.State) (storage.State, error) {
 	return bblState, nil
 }
 
-func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
+func (m Manager) Destroy(ctx context.Context, bblState storage.State) (storage.State, error) {
 	m.logger.Step("destroying infrastructure")
 	if bblState.TFState == "" {
 		return bblState, nil
@@ -139,6 +155,7 @@ func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
 	}
 
 	tfState, err := m.executor.Destroy(
+		ctx,
 		input,
 		template,
 		bblState.TFState)
@@ -157,6 +174,72 @@ func (m Manager) Destroy(bblState storage.State) (storage.State, error) {
 	return bblState, nil
 }
 
+func (m Manager) Drift(ctx context.Context, bblState storage.State) ([]drift.Resource, error) {
+	m.logger.Step("checking infrastructure for drift")
+	template := m.templateGenerator.Generate(bblState)
+
+	input, err := m.inputGenerator.Generate(bblState)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := m.executor.Plan(ctx, input, template, bblState.TFState)
+	readAndReset(m.terraformOutputBuffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePlan(plan), nil
+}
+
+// Import adds existing resources to the tf state of the environment without
+// changing them. Terraform adds the rules of an imported security group as
+// resources of their own, so only the given resources are kept, otherwise the
+// next plan would delete those rules.
+func (m Manager) Import(ctx context.Context, bblState storage.State, imports []Import) (storage.State, error) {
+	m.logger.Step("importing infrastructure into terraform")
+	template := m.templateGenerator.Generate(bblState)
+
+	input, err := m.inputGenerator.Generate(bblState)
+	if err != nil {
+		return storage.State{}, err
+	}
+
+	var addresses []string
+	for _, resource := range imports {
+		addresses = append(addresses, resource.Address)
+
+		if resource.Attributes != nil {
+			bblState.TFState, err = addResource(bblState.TFState, resource)
+			if err != nil {
+				return storage.State{}, err
+			}
+			continue
+		}
+
+		tfState, err := m.executor.Import(ctx, input, template, bblState.TFState, resource.Address, resource.ID)
+
+		bblState.LatestTFOutput = readAndReset(m.terraformOutputBuffer)
+
+		switch err.(type) {
+		case executorError:
+			return storage.State{}, NewManagerError(bblState, err.(executorError))
+		case error:
+			return storage.State{}, err
+		}
+
+		bblState.TFState = tfState
+	}
+
+	bblState.TFState, err = keepResources(bblState.TFState, addresses)
+	if err != nil {
+		return storage.State{}, err
+	}
+	m.logger.Step("imported infrastructure into terraform")
+
+	return bblState, nil
+}
+
 func (m Manager) GetOutputs(bblState storage.State) (map[string]interface{}, error) {
 	outputs, err := m.outputGenerator.Generate(bblState)
 	if err != nil {
@@ -166,6 +249,19 @@ func (m Manager) GetOutputs(bblState storage.State) (map[string]interface{}, err
 	return outputs, nil
 }
 
+func (m Manager) GetAllOutputs(bblState storage.State) (map[string]Output, error) {
+	if bblState.TFState == "" {
+		return map[string]Output{}, nil
+	}
+
+	outputs, err := m.executor.DetailedOutputs(bblState.TFState)
+	if err != nil {
+		return map[string]Output{}, err
+	}
+
+	return outputs, nil
+}
+
 func readAndReset(buf *bytes.Buffer) string {
 	contents := buf.Bytes()
 	buf.Reset()
@@ -31,3 +31,11 @@ func (m ManagerError) BBLState() (storage.State, error) {
 func (m ManagerError) Error() string {
 	return m.executorError.Error()
 }
+
+func (m ManagerError) Phase() string {
+	return storage.TerraformPhase
+}
+
+func (m ManagerError) Output() string {
+	return m.bblState.LatestTFOutput
+}
@@ -29,6 +29,22 @@ var _ = Describe("ManagerError", func() {
 		})
 	})
 
+	Describe("Phase", func() {
+		It("returns the terraform phase", func() {
+			managerError := terraform.NewManagerError(storage.State{}, executorError)
+			Expect(managerError.Phase()).To(Equal("terraform"))
+		})
+	})
+
+	Describe("Output", func() {
+		It("returns the latest terraform output", func() {
+			managerError := terraform.NewManagerError(storage.State{
+				LatestTFOutput: "some-terraform-output",
+			}, executorError)
+			Expect(managerError.Output()).To(Equal("some-terraform-output"))
+		})
+	})
+
 	Describe("BBLState", func() {
 		It("returns the bbl state with additional tf state", func() {
 			executorError.TFStateCall.Returns.TFState = "some-tf-state"
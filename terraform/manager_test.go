@@ -2,11 +2,15 @@ package terraform_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/cloudfoundry/bosh-bootloader/drift"
 	"github.com/cloudfoundry/bosh-bootloader/fakes"
 	"github.com/cloudfoundry/bosh-bootloader/storage"
 	"github.com/cloudfoundry/bosh-bootloader/terraform"
@@ -90,7 +94,7 @@ var _ = Describe("Manager", func() {
 		})
 
 		It("logs steps", func() {
-			_, err := manager.Apply(storage.State{})
+			_, err := manager.Apply(context.Background(), storage.State{})
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(logger.StepCall.Messages).To(ContainSequence([]string{
@@ -101,7 +105,7 @@ var _ = Describe("Manager", func() {
 		It("returns a state with new tfState and output from executor apply", func() {
 			terraformOutputBuffer.Write([]byte(expectedTFOutput))
 
-			state, err := manager.Apply(incomingState)
+			state, err := manager.Apply(context.Background(), incomingState)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
@@ -121,6 +125,16 @@ var _ = Describe("Manager", func() {
 			Expect(state).To(Equal(expectedState))
 		})
 
+		It("passes the context to the executor so that terraform can be interrupted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			_, err := manager.Apply(ctx, incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.ApplyCall.Receives.Context).To(BeIdenticalTo(ctx))
+		})
+
 		Context("failure cases", func() {
 			Context("when InputGenerator.Generate returns an error", func() {
 				BeforeEach(func() {
@@ -128,7 +142,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("bubbles up the error", func() {
-					_, err := manager.Apply(incomingState)
+					_, err := manager.Apply(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to generate inputs"))
 				})
 			})
@@ -158,7 +172,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns the bblState with latest terraform output and a ManagerError", func() {
-					_, err := manager.Apply(incomingState)
+					_, err := manager.Apply(context.Background(), incomingState)
 
 					expectedState := incomingState
 					expectedState.LatestTFOutput = expectedTFOutput
@@ -179,7 +193,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("bubbles up the error", func() {
-					_, err := manager.Apply(incomingState)
+					_, err := manager.Apply(context.Background(), incomingState)
 					Expect(err).To(Equal(executorError))
 				})
 			})
@@ -226,7 +240,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("logs steps", func() {
-				_, err := manager.Destroy(incomingState)
+				_, err := manager.Destroy(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(logger.StepCall.Messages).To(ContainSequence([]string{
@@ -235,7 +249,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("calls Executor.Destroy with the right arguments", func() {
-				_, err := manager.Destroy(incomingState)
+				_, err := manager.Destroy(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
@@ -254,10 +268,20 @@ var _ = Describe("Manager", func() {
 				Expect(executor.DestroyCall.Receives.TFState).To(Equal(incomingState.TFState))
 			})
 
+			It("passes the context to the executor so that terraform can be interrupted", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				_, err := manager.Destroy(ctx, incomingState)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(executor.DestroyCall.Receives.Context).To(BeIdenticalTo(ctx))
+			})
+
 			It("returns the bbl state updated with the TFState and output from executor destroy", func() {
 				terraformOutputBuffer.Write([]byte(expectedTFOutput))
 
-				newBBLState, err := manager.Destroy(incomingState)
+				newBBLState, err := manager.Destroy(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(newBBLState).To(Equal(expectedState))
@@ -269,7 +293,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("bubbles up the error", func() {
-					_, err := manager.Apply(incomingState)
+					_, err := manager.Apply(context.Background(), incomingState)
 					Expect(err).To(MatchError("failed to generate inputs"))
 				})
 			})
@@ -299,7 +323,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("returns a ManagerError", func() {
-					_, err := manager.Destroy(incomingState)
+					_, err := manager.Destroy(context.Background(), incomingState)
 
 					expectedState := incomingState
 					expectedState.LatestTFOutput = expectedTFOutput
@@ -320,7 +344,7 @@ var _ = Describe("Manager", func() {
 				})
 
 				It("bubbles up the error", func() {
-					_, err := manager.Destroy(incomingState)
+					_, err := manager.Destroy(context.Background(), incomingState)
 					Expect(err).To(Equal(executorError))
 				})
 			})
@@ -334,7 +358,7 @@ var _ = Describe("Manager", func() {
 			)
 
 			It("returns the bbl state and skips calling executor destroy", func() {
-				bblState, err := manager.Destroy(incomingState)
+				bblState, err := manager.Destroy(context.Background(), incomingState)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(bblState).To(Equal(incomingState))
@@ -343,6 +367,255 @@ var _ = Describe("Manager", func() {
 		})
 	})
 
+	Describe("Drift", func() {
+		var incomingState storage.State
+
+		BeforeEach(func() {
+			incomingState = storage.State{
+				IAAS:    "aws",
+				EnvID:   "some-env-id",
+				TFState: "some-tf-state",
+			}
+
+			templateGenerator.GenerateCall.Returns.Template = "some-aws-terraform-template"
+			inputGenerator.GenerateCall.Returns.Inputs = map[string]string{"env_id": "some-env-id"}
+			executor.PlanCall.Returns.Plan = `
+~ aws_instance.nat
+    source_dest_check: "true" => "false"
+`
+		})
+
+		It("plans the generated template against the tf state and returns the drift", func() {
+			resources, err := manager.Drift(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
+			Expect(inputGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
+			Expect(executor.PlanCall.Receives.Inputs).To(Equal(map[string]string{"env_id": "some-env-id"}))
+			Expect(executor.PlanCall.Receives.Template).To(Equal("some-aws-terraform-template"))
+			Expect(executor.PlanCall.Receives.TFState).To(Equal("some-tf-state"))
+
+			Expect(resources).To(Equal([]drift.Resource{{
+				Name:   "aws_instance.nat",
+				Status: drift.Changed,
+				Attributes: []drift.Attribute{{
+					Name:     "source_dest_check",
+					Status:   drift.Changed,
+					Expected: "false",
+					Actual:   "true",
+				}},
+			}}))
+		})
+
+		It("does not leave the plan in the terraform output buffer", func() {
+			terraformOutputBuffer.Write([]byte("some plan output"))
+
+			_, err := manager.Drift(context.Background(), incomingState)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(terraformOutputBuffer.Len()).To(Equal(0))
+		})
+
+		It("returns an error when the inputs cannot be generated", func() {
+			inputGenerator.GenerateCall.Returns.Error = errors.New("failed to generate inputs")
+
+			_, err := manager.Drift(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to generate inputs"))
+		})
+
+		It("returns an error when the plan fails", func() {
+			executor.PlanCall.Returns.Error = errors.New("failed to plan")
+
+			_, err := manager.Drift(context.Background(), incomingState)
+			Expect(err).To(MatchError("failed to plan"))
+		})
+	})
+
+	Describe("Import", func() {
+		var (
+			incomingState storage.State
+			imports       []terraform.Import
+			imported      map[string]string
+		)
+
+		tfStateWith := func(resources map[string]string) string {
+			stateResources := map[string]interface{}{}
+			for address, id := range resources {
+				stateResources[address] = map[string]interface{}{
+					"type":    strings.Split(address, ".")[0],
+					"primary": map[string]interface{}{"id": id},
+				}
+			}
+
+			contents, err := json.Marshal(map[string]interface{}{
+				"version": 3,
+				"serial":  len(resources),
+				"modules": []interface{}{
+					map[string]interface{}{
+						"path":      []string{"root"},
+						"resources": stateResources,
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			return string(contents)
+		}
+
+		tfStateResources := func(tfState string) map[string]map[string]interface{} {
+			var state struct {
+				Modules []struct {
+					Resources map[string]map[string]interface{} `json:"resources"`
+				} `json:"modules"`
+			}
+			Expect(json.Unmarshal([]byte(tfState), &state)).To(Succeed())
+			Expect(state.Modules).To(HaveLen(1))
+
+			return state.Modules[0].Resources
+		}
+
+		BeforeEach(func() {
+			incomingState = storage.State{
+				IAAS:  "aws",
+				EnvID: "some-env-id",
+			}
+			imports = []terraform.Import{
+				{Address: "aws_vpc.vpc", ID: "vpc-12345"},
+				{Address: "aws_subnet.bosh_subnet", ID: "subnet-12345"},
+			}
+
+			templateGenerator.GenerateCall.Returns.Template = "some-aws-terraform-template"
+			inputGenerator.GenerateCall.Returns.Inputs = map[string]string{"env_id": "some-env-id"}
+
+			imported = map[string]string{}
+			executor.ImportCall.Stub = func(tfState, address, id string) (string, error) {
+				imported[address] = id
+				return tfStateWith(imported), nil
+			}
+		})
+
+		It("imports each resource into the tf state in order", func() {
+			terraformOutputBuffer.Write([]byte(expectedTFOutput))
+
+			state, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templateGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
+			Expect(inputGenerator.GenerateCall.Receives.State).To(Equal(incomingState))
+			Expect(executor.ImportCall.CallCount).To(Equal(2))
+			Expect(executor.ImportCall.Receives.TFState).To(Equal(tfStateWith(map[string]string{"aws_vpc.vpc": "vpc-12345"})))
+			Expect(executor.ImportCall.Receives.Inputs).To(Equal(map[string]string{"env_id": "some-env-id"}))
+			Expect(executor.ImportCall.Receives.Template).To(Equal("some-aws-terraform-template"))
+
+			resources := tfStateResources(state.TFState)
+			Expect(resources).To(HaveLen(2))
+			Expect(resources["aws_vpc.vpc"]["primary"]).To(HaveKeyWithValue("id", "vpc-12345"))
+			Expect(resources["aws_subnet.bosh_subnet"]["primary"]).To(HaveKeyWithValue("id", "subnet-12345"))
+			Expect(state.LatestTFOutput).To(Equal(""))
+			Expect(logger.StepCall.Receives.Message).To(Equal("imported infrastructure into terraform"))
+		})
+
+		It("writes the resources that terraform cannot import to the tf state", func() {
+			imports = append(imports, terraform.Import{
+				Address: "aws_route_table_association.route_internal_subnets[1]",
+				ID:      "rtbassoc-12345",
+				Attributes: map[string]string{
+					"subnet_id":      "subnet-67890",
+					"route_table_id": "rtb-12345",
+				},
+			})
+
+			state, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.ImportCall.CallCount).To(Equal(2))
+
+			resources := tfStateResources(state.TFState)
+			Expect(resources).To(HaveLen(3))
+			Expect(resources["aws_route_table_association.route_internal_subnets.1"]).To(Equal(map[string]interface{}{
+				"type":       "aws_route_table_association",
+				"depends_on": []interface{}{},
+				"primary": map[string]interface{}{
+					"id": "rtbassoc-12345",
+					"attributes": map[string]interface{}{
+						"id":             "rtbassoc-12345",
+						"subnet_id":      "subnet-67890",
+						"route_table_id": "rtb-12345",
+					},
+					"meta":    map[string]interface{}{},
+					"tainted": false,
+				},
+				"deposed":  []interface{}{},
+				"provider": "",
+			}))
+		})
+
+		It("writes a tf state when terraform has not imported anything", func() {
+			imports = []terraform.Import{{
+				Address:    "aws_iam_user_policy.bosh",
+				ID:         "some-user:aws-cpi",
+				Attributes: map[string]string{"name": "aws-cpi"},
+			}}
+
+			state, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).NotTo(HaveOccurred())
+
+			resources := tfStateResources(state.TFState)
+			Expect(resources).To(HaveKey("aws_iam_user_policy.bosh"))
+		})
+
+		It("drops the resources terraform imported on its own", func() {
+			imports = []terraform.Import{{Address: "aws_security_group.bosh_security_group", ID: "sg-12345"}}
+			executor.ImportCall.Stub = func(tfState, address, id string) (string, error) {
+				return tfStateWith(map[string]string{
+					"aws_security_group.bosh_security_group":        "sg-12345",
+					"aws_security_group_rule.bosh_security_group":   "sgrule-1",
+					"aws_security_group_rule.bosh_security_group-1": "sgrule-2",
+				}), nil
+			}
+
+			state, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).NotTo(HaveOccurred())
+
+			resources := tfStateResources(state.TFState)
+			Expect(resources).To(HaveLen(1))
+			Expect(resources).To(HaveKey("aws_security_group.bosh_security_group"))
+		})
+
+		It("returns an error when the inputs cannot be generated", func() {
+			inputGenerator.GenerateCall.Returns.Error = errors.New("failed to generate inputs")
+
+			_, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).To(MatchError("failed to generate inputs"))
+		})
+
+		It("returns a ManagerError with the resources imported so far when an import fails", func() {
+			executorError := &fakes.TerraformExecutorError{}
+			executor.ImportCall.Stub = func(tfState, address, id string) (string, error) {
+				if address == "aws_subnet.bosh_subnet" {
+					terraformOutputBuffer.Write([]byte(expectedTFOutput))
+					return "", executorError
+				}
+				return "some-partial-tf-state", nil
+			}
+
+			_, err := manager.Import(context.Background(), incomingState, imports)
+
+			expectedState := incomingState
+			expectedState.TFState = "some-partial-tf-state"
+			expectedState.LatestTFOutput = expectedTFOutput
+			Expect(err).To(MatchError(terraform.NewManagerError(expectedState, executorError)))
+		})
+
+		It("returns other import errors as they are", func() {
+			executor.ImportCall.Stub = nil
+			executor.ImportCall.Returns.Error = errors.New("failed to import")
+
+			_, err := manager.Import(context.Background(), incomingState, imports)
+			Expect(err).To(MatchError("failed to import"))
+		})
+	})
+
 	Describe("GetOutputs", func() {
 		BeforeEach(func() {
 			outputGenerator.GenerateCall.Returns.Outputs = map[string]interface{}{
@@ -397,6 +670,41 @@ var _ = Describe("Manager", func() {
 		})
 	})
 
+	Describe("GetAllOutputs", func() {
+		It("returns every output in the terraform state", func() {
+			executor.DetailedOutputsCall.Returns.Outputs = map[string]terraform.Output{
+				"external_ip":         {Value: "some-external-ip"},
+				"some-custom-output":  {Value: "some-custom-value", Sensitive: true},
+				"some-list-of-output": {Value: []interface{}{"some-value"}},
+			}
+
+			terraformOutputs, err := manager.GetAllOutputs(storage.State{TFState: "some-tf-state"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.DetailedOutputsCall.Receives.TFState).To(Equal("some-tf-state"))
+			Expect(terraformOutputs).To(Equal(map[string]terraform.Output{
+				"external_ip":         {Value: "some-external-ip"},
+				"some-custom-output":  {Value: "some-custom-value", Sensitive: true},
+				"some-list-of-output": {Value: []interface{}{"some-value"}},
+			}))
+		})
+
+		It("returns no outputs when there is no terraform state", func() {
+			terraformOutputs, err := manager.GetAllOutputs(storage.State{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executor.DetailedOutputsCall.CallCount).To(Equal(0))
+			Expect(terraformOutputs).To(BeEmpty())
+		})
+
+		It("returns an error when the executor fails to read the outputs", func() {
+			executor.DetailedOutputsCall.Returns.Error = errors.New("failed to read outputs")
+
+			_, err := manager.GetAllOutputs(storage.State{TFState: "some-tf-state"})
+			Expect(err).To(MatchError("failed to read outputs"))
+		})
+	})
+
 	Describe("Version", func() {
 		BeforeEach(func() {
 			executor.VersionCall.Returns.Version = "some-version"
@@ -0,0 +1,113 @@
+package terraform
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+)
+
+var (
+	planResourceRegex  = regexp.MustCompile(`^\s*(-/\+|~|\+|-|<=) (\S+\.\S+)`)
+	planChangeRegex    = regexp.MustCompile(`^\s+(.+?):\s+("(?:[^"\\]|\\.)*"|<computed>) => ("(?:[^"\\]|\\.)*"|<computed>)`)
+	planAttributeRegex = regexp.MustCompile(`^\s+(.+?):\s+("(?:[^"\\]|\\.)*"|<computed>)\s*$`)
+)
+
+// ParsePlan reads the output of terraform plan. Values on the left of "=>"
+// are what terraform refreshed from the IaaS, values on the right are what
+// the template expects.
+func ParsePlan(output string) []drift.Resource {
+	var (
+		resources []drift.Resource
+		current   = -1
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if matches := planResourceRegex.FindStringSubmatch(line); matches != nil {
+			// data sources are read on every plan, so reading one is not drift.
+			if matches[1] == "<=" {
+				current = -1
+				continue
+			}
+
+			resources = append(resources, drift.Resource{
+				Name:   matches[2],
+				Status: resourceStatus(matches[1]),
+			})
+			current = len(resources) - 1
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			current = -1
+			continue
+		}
+
+		if current < 0 {
+			continue
+		}
+
+		if matches := planChangeRegex.FindStringSubmatch(line); matches != nil {
+			actual := unquote(matches[2])
+			expected := unquote(matches[3])
+			resources[current].Attributes = append(resources[current].Attributes, drift.Attribute{
+				Name:     matches[1],
+				Status:   attributeStatus(actual, expected),
+				Expected: expected,
+				Actual:   actual,
+			})
+			continue
+		}
+
+		if matches := planAttributeRegex.FindStringSubmatch(line); matches != nil {
+			attribute := drift.Attribute{
+				Name:   matches[1],
+				Status: resources[current].Status,
+			}
+			if resources[current].Status == drift.Extra {
+				attribute.Actual = unquote(matches[2])
+			} else {
+				attribute.Expected = unquote(matches[2])
+			}
+			resources[current].Attributes = append(resources[current].Attributes, attribute)
+		}
+	}
+
+	return resources
+}
+
+func resourceStatus(action string) string {
+	switch action {
+	case "+":
+		return drift.Missing
+	case "-":
+		return drift.Extra
+	case "-/+":
+		return drift.Replaced
+	default:
+		return drift.Changed
+	}
+}
+
+func attributeStatus(actual, expected string) string {
+	switch {
+	case actual == "":
+		return drift.Missing
+	case expected == "":
+		return drift.Extra
+	default:
+		return drift.Changed
+	}
+}
+
+func unquote(value string) string {
+	if strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+
+	return strings.Replace(value, `\"`, `"`, -1)
+}
@@ -0,0 +1,86 @@
+package terraform_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/drift"
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParsePlan", func() {
+	It("returns changed, replaced, missing and extra resources with their attributes", func() {
+		resources := terraform.ParsePlan(`Refreshing Terraform state in-memory prior to plan...
+
+aws_vpc.vpc: Refreshing state... (ID: vpc-12345)
+aws_instance.nat: Refreshing state... (ID: i-12345)
+
+The Terraform execution plan has been generated and is shown below.
+
+Note: You didn't specify an "-out" parameter to save this plan, so when
+"apply" is called, Terraform can't guarantee this is what will execute.
+
+~ aws_instance.nat
+    source_dest_check: "true" => "false"
+    tags.%:            "2" => "1"
+    tags.Owner:        "someone" => ""
+    tags.Name:         "" => "some-env-id-nat"
+
++ aws_subnet.bosh_subnet
+    cidr_block: "10.0.0.0/24"
+    id:         <computed>
+
+- aws_eip.old
+
+-/+ aws_security_group.bosh_security_group (new resource required)
+    description: "old description" => "Bosh" (forces new resource)
+
+<= data.aws_availability_zones.zones
+
+Plan: 2 to add, 1 to change, 2 to destroy.
+`)
+
+		Expect(resources).To(Equal([]drift.Resource{
+			{
+				Name:   "aws_instance.nat",
+				Status: drift.Changed,
+				Attributes: []drift.Attribute{
+					{Name: "source_dest_check", Status: drift.Changed, Expected: "false", Actual: "true"},
+					{Name: "tags.%", Status: drift.Changed, Expected: "1", Actual: "2"},
+					{Name: "tags.Owner", Status: drift.Extra, Actual: "someone"},
+					{Name: "tags.Name", Status: drift.Missing, Expected: "some-env-id-nat"},
+				},
+			},
+			{
+				Name:   "aws_subnet.bosh_subnet",
+				Status: drift.Missing,
+				Attributes: []drift.Attribute{
+					{Name: "cidr_block", Status: drift.Missing, Expected: "10.0.0.0/24"},
+					{Name: "id", Status: drift.Missing, Expected: "<computed>"},
+				},
+			},
+			{
+				Name:   "aws_eip.old",
+				Status: drift.Extra,
+			},
+			{
+				Name:   "aws_security_group.bosh_security_group",
+				Status: drift.Replaced,
+				Attributes: []drift.Attribute{
+					{Name: "description", Status: drift.Changed, Expected: "Bosh", Actual: "old description"},
+				},
+			},
+		}))
+	})
+
+	It("returns no resources when there are no changes", func() {
+		resources := terraform.ParsePlan(`Refreshing Terraform state in-memory prior to plan...
+
+aws_vpc.vpc: Refreshing state... (ID: vpc-12345)
+
+No changes. Infrastructure is up-to-date.
+`)
+
+		Expect(resources).To(BeEmpty())
+	})
+})
@@ -0,0 +1,15 @@
+package terraform
+
+import "regexp"
+
+var resourcePattern = regexp.MustCompile(`(?m)^resource "([^"]+)" "[^"]+"`)
+
+// Resources returns how many resources of each type a template declares.
+func Resources(template string) map[string]int {
+	resources := map[string]int{}
+	for _, match := range resourcePattern.FindAllStringSubmatch(template, -1) {
+		resources[match[1]]++
+	}
+
+	return resources
+}
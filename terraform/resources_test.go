@@ -0,0 +1,37 @@
+package terraform_test
+
+import (
+	"github.com/cloudfoundry/bosh-bootloader/terraform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resources", func() {
+	It("counts the resources of each type in the template", func() {
+		resources := terraform.Resources(`
+variable "env_id" {}
+
+resource "aws_eip" "bosh_eip" {
+  vpc = true
+}
+
+resource "aws_eip" "nat_eip" {
+  vpc = true
+}
+
+data "aws_vpc" "existing" {}
+
+output "external_ip" {
+  value = "${aws_eip.bosh_eip.public_ip}"
+}
+
+resource "aws_instance" "nat" {}
+`)
+
+		Expect(resources).To(Equal(map[string]int{
+			"aws_eip":      2,
+			"aws_instance": 1,
+		}))
+	})
+})
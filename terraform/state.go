@@ -0,0 +1,129 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var countIndexPattern = regexp.MustCompile(`\[(\d+)\]$`)
+
+// stateKey turns a resource address into its key in the tf state, where
+// aws_subnet.internal_subnets[0] is stored as aws_subnet.internal_subnets.0.
+func stateKey(address string) string {
+	return countIndexPattern.ReplaceAllString(address, ".$1")
+}
+
+// addResource writes a resource to the root module of the tf state, for
+// resources that terraform cannot import.
+func addResource(tfState string, resource Import) (string, error) {
+	state, err := readState(tfState)
+	if err != nil {
+		return "", err
+	}
+
+	attributes := map[string]string{"id": resource.ID}
+	for name, value := range resource.Attributes {
+		attributes[name] = value
+	}
+
+	rootModule := state.rootModule()
+	resources, _ := rootModule["resources"].(map[string]interface{})
+	if resources == nil {
+		resources = map[string]interface{}{}
+		rootModule["resources"] = resources
+	}
+
+	resources[stateKey(resource.Address)] = map[string]interface{}{
+		"type":       strings.Split(resource.Address, ".")[0],
+		"depends_on": []string{},
+		"primary": map[string]interface{}{
+			"id":         resource.ID,
+			"attributes": attributes,
+			"meta":       map[string]interface{}{},
+			"tainted":    false,
+		},
+		"deposed":  []interface{}{},
+		"provider": "",
+	}
+
+	return state.write()
+}
+
+// keepResources removes every resource but the given ones from the root
+// module of the tf state.
+func keepResources(tfState string, addresses []string) (string, error) {
+	state, err := readState(tfState)
+	if err != nil {
+		return "", err
+	}
+
+	keep := map[string]bool{}
+	for _, address := range addresses {
+		keep[stateKey(address)] = true
+	}
+
+	resources, _ := state.rootModule()["resources"].(map[string]interface{})
+	for key := range resources {
+		if !strings.HasPrefix(key, "data.") && !keep[key] {
+			delete(resources, key)
+		}
+	}
+
+	return state.write()
+}
+
+type stateFile map[string]interface{}
+
+func readState(contents string) (stateFile, error) {
+	if contents == "" {
+		return stateFile{"version": 3}, nil
+	}
+
+	state := stateFile{}
+	err := json.Unmarshal([]byte(contents), &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform state: %s", err)
+	}
+
+	return state, nil
+}
+
+func (s stateFile) rootModule() map[string]interface{} {
+	modules, _ := s["modules"].([]interface{})
+	for _, module := range modules {
+		module, ok := module.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path, _ := module["path"].([]interface{})
+		if len(path) == 1 && path[0] == "root" {
+			return module
+		}
+	}
+
+	rootModule := map[string]interface{}{
+		"path":       []interface{}{"root"},
+		"outputs":    map[string]interface{}{},
+		"resources":  map[string]interface{}{},
+		"depends_on": []interface{}{},
+	}
+	s["modules"] = append(modules, rootModule)
+
+	return rootModule
+}
+
+// write bumps the serial, as terraform does whenever it changes the state.
+func (s stateFile) write() (string, error) {
+	serial, _ := s["serial"].(float64)
+	s["serial"] = serial + 1
+
+	contents, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}